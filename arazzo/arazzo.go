@@ -0,0 +1,348 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package arazzo walks Arazzo workflow documents into doctor models. Workflows, steps and their parameters are
+// Foundation backed (so they generate JSONPaths and can carry findings), and steps are resolved to the real
+// operations of the OpenAPI documents they describe.
+package arazzo
+
+import (
+	"errors"
+	"fmt"
+	"github.com/pb33f/doctor/model"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+// Finding ids used when steps cannot be resolved.
+const (
+	FindingStepOperation = "arazzo-step-operation"
+	FindingStepWorkflow  = "arazzo-step-workflow"
+	FindingStepTarget    = "arazzo-step-target"
+)
+
+// Document is an Arazzo document.
+type Document struct {
+	Arazzo             string
+	Info               *Info
+	SourceDescriptions []*SourceDescription
+	Workflows          []*Workflow
+	RootNode           *yaml.Node
+	drBase.Foundation
+}
+
+// Info is the metadata of an Arazzo document.
+type Info struct {
+	Title       string `yaml:"title"`
+	Summary     string `yaml:"summary"`
+	Description string `yaml:"description"`
+	Version     string `yaml:"version"`
+	drBase.Foundation
+}
+
+// SourceDescription is a named OpenAPI (or Arazzo) document the workflows use.
+type SourceDescription struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+	Type string `yaml:"type"`
+	drBase.Foundation
+}
+
+// Workflow is a sequence of steps.
+type Workflow struct {
+	WorkflowId  string            `yaml:"workflowId"`
+	Summary     string            `yaml:"summary"`
+	Description string            `yaml:"description"`
+	DependsOn   []string          `yaml:"dependsOn"`
+	Outputs     map[string]string `yaml:"outputs"`
+	Inputs      *yaml.Node        `yaml:"-"`
+	Steps       []*Step           `yaml:"-"`
+	Parameters  []*Parameter      `yaml:"-"`
+	drBase.Foundation
+}
+
+// Step is a single step of a workflow, it calls an operation (by operationId or operationPath) or another workflow.
+type Step struct {
+	StepId          string            `yaml:"stepId"`
+	Description     string            `yaml:"description"`
+	OperationId     string            `yaml:"operationId"`
+	OperationPath   string            `yaml:"operationPath"`
+	WorkflowId      string            `yaml:"workflowId"`
+	Outputs         map[string]string `yaml:"outputs"`
+	SuccessCriteria []*Criterion      `yaml:"successCriteria"`
+	Parameters      []*Parameter      `yaml:"-"`
+
+	// Operation is the operation the step calls, and Source is the name of the source description it was found in.
+	// Workflow is the workflow the step calls. They are set by Document.Resolve.
+	Operation *model.PathOperation `yaml:"-"`
+	Source    string               `yaml:"-"`
+	Workflow  *Workflow            `yaml:"-"`
+	drBase.Foundation
+}
+
+// Parameter is a parameter passed to an operation or workflow by a step.
+type Parameter struct {
+	Name  string     `yaml:"name"`
+	In    string     `yaml:"in"`
+	Value *yaml.Node `yaml:"-"`
+	drBase.Foundation
+}
+
+// Criterion is a success criterion of a step.
+type Criterion struct {
+	Context   string `yaml:"context"`
+	Condition string `yaml:"condition"`
+	Type      string `yaml:"type"`
+}
+
+// NewDocument parses and walks an Arazzo document (YAML or JSON).
+func NewDocument(spec []byte) (*Document, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(spec, &root); err != nil {
+		return nil, fmt.Errorf("unable to parse arazzo document: %w", err)
+	}
+	node := &root
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, errors.New("arazzo document must be an object")
+	}
+	d := &Document{RootNode: &root}
+	d.PathSegment = "document"
+	d.ValueNode = node
+	d.Arazzo = mapValue(node, "arazzo").Value
+	if d.Arazzo == "" {
+		return nil, errors.New("arazzo document has no 'arazzo' version")
+	}
+
+	if k, v := mapPair(node, "info"); v != nil {
+		d.Info = &Info{}
+		if err := v.Decode(d.Info); err != nil {
+			return nil, fmt.Errorf("unable to decode info: %w", err)
+		}
+		setFoundation(&d.Info.Foundation, d, "info", k, v, -1)
+	}
+
+	_, sources := mapPair(node, "sourceDescriptions")
+	for i, v := range sequence(sources) {
+		sd := &SourceDescription{}
+		if err := v.Decode(sd); err != nil {
+			return nil, fmt.Errorf("unable to decode source description %d: %w", i, err)
+		}
+		setFoundation(&sd.Foundation, d, "sourceDescriptions", nil, v, i)
+		d.SourceDescriptions = append(d.SourceDescriptions, sd)
+	}
+
+	_, workflows := mapPair(node, "workflows")
+	for i, v := range sequence(workflows) {
+		wf := &Workflow{}
+		if err := v.Decode(wf); err != nil {
+			return nil, fmt.Errorf("unable to decode workflow %d: %w", i, err)
+		}
+		_, wf.Inputs = mapPair(v, "inputs")
+		setFoundation(&wf.Foundation, d, "workflows", nil, v, i)
+		wf.Parameters = decodeParameters(wf, v)
+
+		_, steps := mapPair(v, "steps")
+		for j, sv := range sequence(steps) {
+			step := &Step{}
+			if err := sv.Decode(step); err != nil {
+				return nil, fmt.Errorf("unable to decode step %d of workflow '%s': %w", j, wf.WorkflowId, err)
+			}
+			setFoundation(&step.Foundation, wf, "steps", nil, sv, j)
+			step.Parameters = decodeParameters(step, sv)
+			wf.Steps = append(wf.Steps, step)
+		}
+		d.Workflows = append(d.Workflows, wf)
+	}
+	return d, nil
+}
+
+// Workflow returns the workflow with the supplied id, or nil if there isn't one.
+func (d *Document) Workflow(workflowId string) *Workflow {
+	for _, wf := range d.Workflows {
+		if wf.WorkflowId == workflowId {
+			return wf
+		}
+	}
+	return nil
+}
+
+// Resolve cross-references every step into the OpenAPI documents it describes. Sources are DrDocuments keyed
+// by the name of their source description. Steps that use an operationId without a source prefix are looked up in
+// every source, in the order the source descriptions are declared.
+//
+// A finding is returned for every step that cannot be resolved.
+func (d *Document) Resolve(sources map[string]*model.DrDocument) []*drBase.Finding {
+	var findings []*drBase.Finding
+	fail := func(id string, step *Step, msg string) {
+		findings = append(findings, drBase.NewFinding(id, drBase.SeverityError,
+			fmt.Sprintf("step '%s' %s", step.StepId, msg), step))
+	}
+	var order []string
+	for _, sd := range d.SourceDescriptions {
+		if sources[sd.Name] != nil {
+			order = append(order, sd.Name)
+		}
+	}
+
+	for _, wf := range d.Workflows {
+		for _, step := range wf.Steps {
+			targets := 0
+			for _, t := range []string{step.OperationId, step.OperationPath, step.WorkflowId} {
+				if t != "" {
+					targets++
+				}
+			}
+			if targets != 1 {
+				fail(FindingStepTarget, step, "must define exactly one of operationId, operationPath or workflowId")
+				continue
+			}
+			switch {
+			case step.OperationId != "":
+				source, opId := splitSourceExpression(step.OperationId)
+				names := order
+				if source != "" {
+					names = []string{source}
+				}
+				for _, name := range names {
+					if po := findOperationById(sources[name], opId); po != nil {
+						step.Operation, step.Source = po, name
+						break
+					}
+				}
+				if step.Operation == nil {
+					fail(FindingStepOperation, step, fmt.Sprintf("calls operationId '%s', which does not exist", step.OperationId))
+				}
+			case step.OperationPath != "":
+				source, pointer := splitOperationPath(step.OperationPath)
+				if po := findOperationByPointer(sources[source], pointer); po != nil {
+					step.Operation, step.Source = po, source
+				} else {
+					fail(FindingStepOperation, step, fmt.Sprintf("calls operationPath '%s', which does not exist", step.OperationPath))
+				}
+			default:
+				source, workflowId := splitSourceExpression(step.WorkflowId)
+				if source != "" {
+					// workflows in other arazzo documents are not resolved.
+					continue
+				}
+				if step.Workflow = d.Workflow(workflowId); step.Workflow == nil {
+					fail(FindingStepWorkflow, step, fmt.Sprintf("calls workflow '%s', which does not exist", step.WorkflowId))
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// splitSourceExpression splits `$sourceDescriptions.petstore.getPet` into the source name and the id.
+func splitSourceExpression(expression string) (source, id string) {
+	rest, ok := strings.CutPrefix(expression, "$sourceDescriptions.")
+	if !ok {
+		return "", expression
+	}
+	source, id, _ = strings.Cut(rest, ".")
+	return source, id
+}
+
+// splitOperationPath splits `{$sourceDescriptions.petstore.url}#/paths/~1pets/get` into the source name and
+// the JSON pointer.
+func splitOperationPath(operationPath string) (source, pointer string) {
+	expression, pointer, _ := strings.Cut(operationPath, "#")
+	expression = strings.Trim(expression, "{}")
+	source, _ = splitSourceExpression(strings.TrimSuffix(expression, ".url"))
+	return source, pointer
+}
+
+func findOperationById(drDoc *model.DrDocument, operationId string) *model.PathOperation {
+	if drDoc == nil {
+		return nil
+	}
+	for _, po := range drDoc.Operations() {
+		if po.Operation.Value.OperationId == operationId {
+			return po
+		}
+	}
+	return nil
+}
+
+// findOperationByPointer finds the operation for a JSON pointer, like `/paths/~1pets/get`.
+func findOperationByPointer(drDoc *model.DrDocument, pointer string) *model.PathOperation {
+	if drDoc == nil {
+		return nil
+	}
+	segments := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	if len(segments) != 3 || (segments[0] != "paths" && segments[0] != "webhooks") {
+		return nil
+	}
+	unescape := func(s string) string {
+		return strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+	}
+	path, method := unescape(segments[1]), strings.ToLower(unescape(segments[2]))
+	for _, po := range drDoc.Operations() {
+		if po.Path == path && po.Method == method && po.Webhook == (segments[0] == "webhooks") {
+			return po
+		}
+	}
+	return nil
+}
+
+func decodeParameters(parent drBase.Foundational, node *yaml.Node) []*Parameter {
+	var params []*Parameter
+	_, v := mapPair(node, "parameters")
+	for i, pv := range sequence(v) {
+		p := &Parameter{}
+		if pv.Decode(p) != nil {
+			continue
+		}
+		_, p.Value = mapPair(pv, "value")
+		setFoundation(&p.Foundation, parent, "parameters", nil, pv, i)
+		params = append(params, p)
+	}
+	return params
+}
+
+// setFoundation links a model into the tree, a negative index is not part of the path.
+func setFoundation(f *drBase.Foundation, parent any, segment string, key, value *yaml.Node, index int) {
+	f.Parent = parent
+	f.PathSegment = segment
+	f.KeyNode = key
+	f.ValueNode = value
+	if key == nil {
+		f.KeyNode = value
+	}
+	if index >= 0 {
+		i := index
+		f.IsIndexed = true
+		f.Index = &i
+	}
+}
+
+func mapPair(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+func mapValue(node *yaml.Node, key string) *yaml.Node {
+	if _, v := mapPair(node, key); v != nil {
+		return v
+	}
+	return &yaml.Node{}
+}
+
+func sequence(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return node.Content
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package arazzo
+
+import (
+	"github.com/pb33f/doctor/model"
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+const workflows = `arazzo: 1.0.0
+info:
+  title: burger workflows
+  version: 1.0.0
+sourceDescriptions:
+  - name: burgers
+    url: ./burgershop.openapi.yaml
+    type: openapi
+workflows:
+  - workflowId: orderBurger
+    steps:
+      - stepId: create
+        operationPath: '{$sourceDescriptions.burgers.url}#/paths/~1burgers/post'
+        successCriteria:
+          - condition: $statusCode == 200
+      - stepId: locate
+        operationId: $sourceDescriptions.burgers.locateBurger
+        parameters:
+          - name: burgerId
+            in: path
+            value: $steps.create.outputs.id
+      - stepId: dress
+        operationId: listBurgerDressings
+      - stepId: again
+        workflowId: eatBurger
+  - workflowId: eatBurger
+    steps:
+      - stepId: pizza
+        operationId: orderPizza
+      - stepId: confused
+        operationId: createBurger
+        workflowId: orderBurger`
+
+func TestNewDocument(t *testing.T) {
+	doc, err := NewDocument([]byte(workflows))
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", doc.Arazzo)
+	assert.Equal(t, "burger workflows", doc.Info.Title)
+	require.Len(t, doc.SourceDescriptions, 1)
+	assert.Equal(t, "$.sourceDescriptions[0]", doc.SourceDescriptions[0].GenerateJSONPath())
+	require.Len(t, doc.Workflows, 2)
+
+	order := doc.Workflow("orderBurger")
+	require.Len(t, order.Steps, 4)
+	assert.Equal(t, "$.workflows[0].steps[1]", order.Steps[1].GenerateJSONPath())
+	require.Len(t, order.Steps[1].Parameters, 1)
+	assert.Equal(t, "$.workflows[0].steps[1].parameters[0]", order.Steps[1].Parameters[0].GenerateJSONPath())
+	assert.Equal(t, "$steps.create.outputs.id", order.Steps[1].Parameters[0].Value.Value)
+	assert.Equal(t, "$statusCode == 200", order.Steps[0].SuccessCriteria[0].Condition)
+	assert.Equal(t, 12, order.Steps[0].GetKeyNode().Line)
+
+	_, err = NewDocument([]byte(`info: {}`))
+	assert.Error(t, err)
+}
+
+func TestDocument_Resolve(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ := libopenapi.NewDocument(bytes)
+	v3Doc, _ := newDoc.BuildV3Model()
+	burgers := model.NewDrDocument(v3Doc)
+
+	doc, err := NewDocument([]byte(workflows))
+	require.NoError(t, err)
+	findings := doc.Resolve(map[string]*model.DrDocument{"burgers": burgers})
+
+	order := doc.Workflow("orderBurger")
+	assert.Equal(t, "createBurger", order.Steps[0].Operation.Operation.Value.OperationId)
+	assert.Equal(t, "burgers", order.Steps[0].Source)
+	assert.Equal(t, "/burgers/{burgerId}", order.Steps[1].Operation.Path)
+	assert.Equal(t, "listBurgerDressings", order.Steps[2].Operation.Operation.Value.OperationId)
+	assert.Same(t, doc.Workflow("eatBurger"), order.Steps[3].Workflow)
+
+	require.Len(t, findings, 2)
+	assert.Equal(t, FindingStepOperation, findings[0].Id)
+	assert.Equal(t, "step 'pizza' calls operationId 'orderPizza', which does not exist", findings[0].Message)
+	assert.Equal(t, "$.workflows[1].steps[0]", findings[0].Path)
+	assert.Equal(t, FindingStepTarget, findings[1].Id)
+}
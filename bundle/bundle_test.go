@@ -0,0 +1,69 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package bundle
+
+import (
+	"github.com/pb33f/doctor/model"
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func loadDocument(t *testing.T, bytes []byte, basePath string) *model.DrDocument {
+	config := &datamodel.DocumentConfiguration{
+		BasePath:            basePath,
+		AllowFileReferences: true,
+	}
+	doc, err := libopenapi.NewDocumentWithConfiguration(bytes, config)
+	require.NoError(t, err)
+	v3Doc, errs := doc.BuildV3Model()
+	require.Empty(t, errs)
+	return model.NewDrDocument(v3Doc)
+}
+
+func TestSplit_ByTag(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	drDoc := loadDocument(t, bytes, "../test_specs")
+
+	layout, err := Split(drDoc, ByTag())
+	require.NoError(t, err)
+	assert.Equal(t, "root.yaml", layout.RootFile)
+	require.Contains(t, layout.Files, "burgers.yaml")
+	require.Contains(t, layout.Files, "dressing.yaml")
+
+	root := string(layout.Files[layout.RootFile])
+	burgers := string(layout.Files["burgers.yaml"])
+	assert.Contains(t, root, "burgers.yaml#/components/requestBodies/BurgerRequest")
+	assert.Contains(t, burgers, "    BurgerRequest:")
+	assert.NotContains(t, root, "\n    BurgerRequest:")
+
+	// Burger is also used by the untagged webhook, so it stays in the root document.
+	assert.Contains(t, root, "\n    Burger:")
+	assert.Contains(t, burgers, "root.yaml#/components/schemas/Burger")
+
+	// round trip the layout through merge, there should be no references to the split files left.
+	dir := t.TempDir()
+	for name, b := range layout.Files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), b, 0o644))
+	}
+	split := loadDocument(t, layout.Files[layout.RootFile], dir)
+	merged, err := Merge(split)
+	require.NoError(t, err)
+	assert.False(t, strings.Contains(string(merged), ".yaml#/"))
+	assert.Contains(t, string(merged), "The tastiest food on the planet")
+}
+
+func TestMerge_Circular(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/root.yaml")
+	drDoc := loadDocument(t, bytes, "../test_specs")
+
+	merged, err := Merge(drDoc)
+	assert.Error(t, err)
+	assert.NotEmpty(t, merged)
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"github.com/pb33f/doctor/model"
+	"github.com/pb33f/libopenapi/index"
+	"gopkg.in/yaml.v3"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// Merge is the inverse of Split, it inlines every reference to an external file into a single document.
+// References local to the root document are left alone. Local references inside external files are resolved
+// against the file they were found in and inlined as well.
+//
+// Circular references through external files cannot be inlined, they are left in place and reported in the
+// returned error, along with references that could not be resolved. The merged document is always returned.
+func Merge(drDoc *model.DrDocument) ([]byte, error) {
+	if drDoc == nil || drDoc.GetIndex() == nil {
+		return nil, fmt.Errorf("DrDocument is nil, cannot merge")
+	}
+	m := &merger{root: drDoc.GetIndex(), files: make(map[string]*index.SpecIndex)}
+	if r := m.root.GetRolodex(); r != nil {
+		for _, idx := range r.GetIndexes() {
+			m.files[idx.GetSpecAbsolutePath()] = idx
+		}
+	}
+	m.files[m.root.GetSpecAbsolutePath()] = m.root
+
+	doc := copyNode(m.root.GetRootNode())
+	m.inline(doc, m.root.GetSpecAbsolutePath(), nil)
+	b, err := render(doc)
+	if err != nil {
+		return nil, err
+	}
+	return b, errors.Join(m.errors...)
+}
+
+type merger struct {
+	root   *index.SpecIndex
+	files  map[string]*index.SpecIndex
+	errors []error
+}
+
+// inline walks a node that belongs to file, replacing external references with a copy of what they point to.
+// stack holds the references being inlined, to stop circular references.
+func (m *merger) inline(node *yaml.Node, file string, stack []string) {
+	if node == nil {
+		return
+	}
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value != "$ref" || node.Content[i+1].Kind != yaml.ScalarNode {
+				continue
+			}
+			ref := node.Content[i+1].Value
+			targetFile, pointer := m.resolve(file, ref)
+			if targetFile == m.root.GetSpecAbsolutePath() {
+				if file != targetFile {
+					// a reference from an external file back into the root, make it local.
+					node.Content[i+1].Value = "#" + pointer
+				}
+				break
+			}
+			full := targetFile + "#" + pointer
+			for _, s := range stack {
+				if s == full {
+					m.errors = append(m.errors, fmt.Errorf("circular reference '%s' cannot be merged", ref))
+					return
+				}
+			}
+			idx := m.files[targetFile]
+			if idx == nil {
+				m.errors = append(m.errors, fmt.Errorf("unable to locate file for reference '%s'", ref))
+				return
+			}
+			target := locatePointer(idx.GetRootNode(), pointer)
+			if target == nil {
+				m.errors = append(m.errors, fmt.Errorf("unable to resolve reference '%s'", ref))
+				return
+			}
+			c := copyNode(target)
+			*node = *c
+			m.inline(node, targetFile, append(stack, full))
+			return
+		}
+	}
+	for _, c := range node.Content {
+		m.inline(c, file, stack)
+	}
+}
+
+// resolve converts a reference found in file into the absolute location of the file it points to, and
+// the JSON pointer inside that file.
+func (m *merger) resolve(file, ref string) (string, string) {
+	location, pointer, _ := strings.Cut(ref, "#")
+	if location == "" {
+		return file, pointer
+	}
+	if u, err := url.Parse(location); err == nil && u.IsAbs() {
+		return location, pointer
+	}
+	if base, err := url.Parse(file); err == nil && base.IsAbs() && base.Scheme != "" && len(base.Scheme) > 1 {
+		if rel, e := url.Parse(location); e == nil {
+			return base.ResolveReference(rel).String(), pointer
+		}
+	}
+	if filepath.IsAbs(location) {
+		return filepath.Clean(location), pointer
+	}
+	return filepath.Join(filepath.Dir(file), location), pointer
+}
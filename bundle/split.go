@@ -0,0 +1,206 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package bundle
+
+import (
+	"fmt"
+	"github.com/pb33f/doctor/model"
+	"gopkg.in/yaml.v3"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ComponentUsage describes a component, and every operation that references it (directly, or through other
+// components).
+type ComponentUsage struct {
+	Kind       string
+	Name       string
+	Definition string
+	Operations []*model.PathOperation
+}
+
+// Strategy decides which file a component is moved into when splitting a document. Returning an empty
+// string leaves the component in the root document.
+type Strategy func(usage *ComponentUsage) string
+
+// Layout is a multi-file version of a document. Files are keyed by their name, relative to the root document.
+type Layout struct {
+	RootFile string
+	Files    map[string][]byte
+}
+
+// ByTag moves components into a file named after the tag of the operations that use them. Components
+// used by operations with different tags, untagged operations, or not used by any operation stay in the
+// root document.
+func ByTag() Strategy {
+	return func(usage *ComponentUsage) string {
+		return single(usage, func(po *model.PathOperation) string {
+			if len(po.Operation.Value.Tags) == 0 {
+				return ""
+			}
+			return po.Operation.Value.Tags[0]
+		})
+	}
+}
+
+// ByPathPrefix moves components into a file named after the first segment of the paths that use them,
+// for example components only used by `/burgers` and `/burgers/{burgerId}` are moved to `burgers.yaml`.
+func ByPathPrefix() Strategy {
+	return func(usage *ComponentUsage) string {
+		return single(usage, func(po *model.PathOperation) string {
+			return strings.SplitN(strings.TrimPrefix(po.Path, "/"), "/", 2)[0]
+		})
+	}
+}
+
+// single returns a file name for the domain, when every operation resolves to the same domain.
+func single(usage *ComponentUsage, domain func(po *model.PathOperation) string) string {
+	found := ""
+	for _, po := range usage.Operations {
+		d := domain(po)
+		if d == "" || (found != "" && d != found) {
+			return ""
+		}
+		found = d
+	}
+	if found == "" {
+		return ""
+	}
+	return slug(found) + ".yaml"
+}
+
+func slug(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+// Split breaks a document into multiple files. Each component is assigned a file by the strategy, moved into
+// a `components` section of that file, and every $ref pointing to it (from any file in the layout) is rewritten.
+// References between components in the same file remain local.
+//
+// The DrDocument is not modified, the layout is built from a copy of the root document.
+func Split(drDoc *model.DrDocument, strategy Strategy) (*Layout, error) {
+	if drDoc == nil || drDoc.GetIndex() == nil {
+		return nil, fmt.Errorf("DrDocument is nil, cannot split")
+	}
+	if strategy == nil {
+		return nil, fmt.Errorf("no split strategy supplied")
+	}
+	idx := drDoc.GetIndex()
+	rootFile := filepath.Base(idx.GetSpecAbsolutePath())
+	if rootFile == "" || rootFile == "." || rootFile == "/" {
+		rootFile = "openapi.yaml"
+	}
+
+	// work out which operations use each component.
+	usages := make(map[string]*ComponentUsage)
+	for _, po := range drDoc.Operations() {
+		l := po.Operation.Value.GoLow()
+		if l == nil {
+			continue
+		}
+		for _, def := range drDoc.ReferencedComponents(l.RootNode) {
+			u, ok := usages[def]
+			if !ok {
+				kind, name := model.SplitComponentDefinition(def)
+				u = &ComponentUsage{Kind: kind, Name: name, Definition: def}
+				usages[def] = u
+			}
+			u.Operations = append(u.Operations, po)
+		}
+	}
+
+	root := copyNode(idx.GetRootNode())
+	components := mapValue(documentRoot(root), "components")
+	assigned := make(map[string]string)
+	docs := map[string]*yaml.Node{rootFile: root}
+
+	if components != nil && components.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(components.Content); i += 2 {
+			kind := components.Content[i].Value
+			section := components.Content[i+1]
+			if section.Kind != yaml.MappingNode {
+				continue
+			}
+			var names []string
+			for j := 0; j+1 < len(section.Content); j += 2 {
+				names = append(names, section.Content[j].Value)
+			}
+			for _, name := range names {
+				def := "#/components/" + kind + "/" + escape(name)
+				u := usages[def]
+				if u == nil {
+					u = &ComponentUsage{Kind: kind, Name: name, Definition: def}
+				}
+				file := strategy(u)
+				if file == "" || file == rootFile {
+					continue
+				}
+				assigned[def] = file
+				doc, ok := docs[file]
+				if !ok {
+					doc = &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+					docs[file] = doc
+				}
+				k, v := removeKey(section, name)
+				target := ensureMap(ensureMap(documentRoot(doc), "components"), kind)
+				target.Content = append(target.Content, k, v)
+			}
+		}
+
+		// clean up anything left empty in the root.
+		for i := 0; i+1 < len(components.Content); {
+			if components.Content[i+1].Kind == yaml.MappingNode && len(components.Content[i+1].Content) == 0 {
+				components.Content = append(components.Content[:i], components.Content[i+2:]...)
+				continue
+			}
+			i += 2
+		}
+		if len(components.Content) == 0 {
+			removeKey(documentRoot(root), "components")
+		}
+	}
+
+	layout := &Layout{RootFile: rootFile, Files: make(map[string][]byte)}
+	files := make([]string, 0, len(docs))
+	for f := range docs {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		doc := docs[file]
+		visitRefs(doc, func(_, ref *yaml.Node) {
+			def := model.ComponentDefinition(ref.Value)
+			if def == "" {
+				return
+			}
+			target, ok := assigned[def]
+			if !ok {
+				target = rootFile
+			}
+			if target != file {
+				ref.Value = target + ref.Value
+			}
+		})
+		b, err := render(doc)
+		if err != nil {
+			return nil, fmt.Errorf("unable to render '%s': %w", file, err)
+		}
+		layout.Files[file] = b
+	}
+	return layout, nil
+}
+
+func escape(segment string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(segment, "~", "~0"), "/", "~1")
+}
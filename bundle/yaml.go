@@ -0,0 +1,128 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package bundle
+
+import (
+	"bytes"
+	"gopkg.in/yaml.v3"
+	"strconv"
+	"strings"
+)
+
+// copyNode creates a deep copy of a yaml node, so documents can be rearranged without touching the
+// nodes used by the model.
+func copyNode(node *yaml.Node) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	c := *node
+	if node.Alias != nil {
+		c.Alias = copyNode(node.Alias)
+	}
+	if len(node.Content) > 0 {
+		c.Content = make([]*yaml.Node, len(node.Content))
+		for i, n := range node.Content {
+			c.Content[i] = copyNode(n)
+		}
+	}
+	return &c
+}
+
+func documentRoot(node *yaml.Node) *yaml.Node {
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		return node.Content[0]
+	}
+	return node
+}
+
+func mapValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// ensureMap returns the mapping value for key, creating it if it does not exist.
+func ensureMap(node *yaml.Node, key string) *yaml.Node {
+	if v := mapValue(node, key); v != nil {
+		return v
+	}
+	v := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, v)
+	return v
+}
+
+// removeKey removes a key and its value from a mapping node, returning the key and value nodes.
+func removeKey(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			k, v := node.Content[i], node.Content[i+1]
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return k, v
+		}
+	}
+	return nil, nil
+}
+
+// locatePointer navigates a JSON pointer (like `/components/schemas/Burger`) from a root node.
+func locatePointer(root *yaml.Node, pointer string) *yaml.Node {
+	node := documentRoot(root)
+	if pointer == "" || pointer == "/" {
+		return node
+	}
+	for _, segment := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		segment = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+		switch {
+		case node == nil:
+			return nil
+		case node.Kind == yaml.MappingNode:
+			node = mapValue(node, segment)
+		case node.Kind == yaml.SequenceNode:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(node.Content) {
+				return nil
+			}
+			node = node.Content[i]
+		default:
+			return nil
+		}
+	}
+	return node
+}
+
+// visitRefs calls fn for every mapping node that contains a `$ref` key, with the ref value node.
+func visitRefs(node *yaml.Node, fn func(parent, ref *yaml.Node)) {
+	if node == nil {
+		return
+	}
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "$ref" && node.Content[i+1].Kind == yaml.ScalarNode {
+				fn(node, node.Content[i+1])
+			}
+		}
+	}
+	for _, c := range node.Content {
+		visitRefs(c, fn)
+	}
+}
+
+func render(node *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	_ = enc.Close()
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	drModel "github.com/pb33f/doctor/model"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/what-changed/model"
+)
+
+// Attachments associates changes with the doctor object they were made to, in the updated version of the
+// specification. Changes are resolved once and the object is cached, so every renderer reports the same object
+// for a change, and each change is appended to its object (see drBase.HasChanges). Attachments are not safe for
+// concurrent use, the objects they append changes to are.
+type Attachments struct {
+	drDoc   *drModel.DrDocument
+	objects map[*model.Change]drBase.Foundational
+	byPath  map[string]drBase.Foundational
+}
+
+// AttachChanges resolves every change in the document changes to its doctor object (see Attachments.Object).
+// The document should be the updated version of the specification.
+func AttachChanges(docChanges *model.DocumentChanges, drDoc *drModel.DrDocument) *Attachments {
+	a := &Attachments{drDoc: drDoc, objects: make(map[*model.Change]drBase.Foundational)}
+	for _, c := range LocateChanges(docChanges) {
+		a.Object(c)
+	}
+	return a
+}
+
+// Object returns the doctor object a change was made to, or nil when no object can be found. The object is
+// found at the line of the change in the updated specification, preferring the object at the path of the change
+// when there is more than one and falling back to the closest. Changes without a line in the updated
+// specification (removals) are attached to the object at their path, which is the object that lost the property.
+// Changes that were not attached by AttachChanges are resolved, and cached, the first time they are asked for.
+func (a *Attachments) Object(c *LocatedChange) drBase.Foundational {
+	if a == nil || c == nil || c.Change == nil || a.drDoc == nil {
+		return nil
+	}
+	if f, ok := a.objects[c.Change]; ok {
+		return f
+	}
+	var found drBase.Foundational
+	if ctx := c.Change.Context; ctx != nil && ctx.NewLine != nil {
+		if models, err := a.drDoc.LocateModelByLine(*ctx.NewLine); err == nil && len(models) > 0 {
+			// models are ordered by line, the last is the closest to the change.
+			found = models[len(models)-1]
+			for _, m := range models {
+				if p := m.GenerateJSONPath(); p == c.Path || p == c.PropertyPath() {
+					found = m
+				}
+			}
+		}
+	}
+	if found == nil {
+		found = a.objectAtPath(c.Path)
+	}
+	if hc, ok := found.(drBase.HasChanges); ok {
+		hc.AppendChange(c.Change)
+	}
+	a.objects[c.Change] = found
+	return found
+}
+
+// objectAtPath returns the walked object with a JSONPath, the paths of every object are indexed the first time.
+func (a *Attachments) objectAtPath(path string) drBase.Foundational {
+	if a.byPath == nil {
+		a.byPath = make(map[string]drBase.Foundational)
+		if a.drDoc.V3Document != nil {
+			var index func(f drBase.Foundational)
+			index = func(f drBase.Foundational) {
+				p := f.GenerateJSONPath()
+				if _, ok := a.byPath[p]; !ok {
+					a.byPath[p] = f
+				}
+				for _, child := range drBase.ChildrenOf(f) {
+					index(child)
+				}
+			}
+			index(a.drDoc.V3Document)
+		}
+	}
+	return a.byPath[path]
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestAttachChanges(t *testing.T) {
+	left := `openapi: 3.1.0
+info:
+  title: burgers
+  version: 1.0.0
+paths:
+  /burgers:
+    get:
+      description: list burgers
+      responses:
+        "200":
+          description: ok
+components:
+  schemas:
+    Burger:
+      type: object
+      properties:
+        name:
+          type: string
+        fries:
+          type: boolean`
+	right := strings.Replace(left, "list burgers", "list every burger", 1)
+	right = strings.Replace(right, "        fries:\n          type: boolean", "", 1)
+	right = strings.Replace(right, "name:\n          type: string", "name:\n          type: integer", 1)
+
+	drDoc := drDocument(t, right)
+	docChanges := compare(t, left, right)
+	attached := AttachChanges(docChanges, drDoc)
+
+	objects := make(map[string]string)
+	for _, c := range LocateChanges(docChanges) {
+		f := attached.Object(c)
+		require.NotNil(t, f, c.PropertyPath())
+		objects[c.PropertyPath()] = f.GenerateJSONPath()
+	}
+	assert.Equal(t, "$.paths['/burgers'].get", objects["$.paths['/burgers'].get.description"])
+	assert.Equal(t, "$.components.schemas['Burger'].properties['name']",
+		objects["$.components.schemas['Burger'].properties['name'].type"])
+
+	// changes are appended to their objects.
+	op := drDoc.PathOperations()[0].Operation
+	require.Len(t, op.GetChanges(), 1)
+	assert.Equal(t, "description", op.GetChanges()[0].Property)
+	assert.Len(t, drDoc.V3Document.GetAllNestedChanges(), 3)
+
+	// the removed property has no line in the updated specification, it is attached to the schema that lost it.
+	assert.Equal(t, "$.components.schemas['Burger']", objects["$.components.schemas['Burger'].properties['fries']"])
+
+	assert.Nil(t, (*Attachments)(nil).Object(nil))
+	assert.Nil(t, AttachChanges(nil, nil).Object(&LocatedChange{}))
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	v3 "github.com/pb33f/libopenapi/datamodel/low/v3"
+	"slices"
+)
+
+const (
+	// CategoryEnum is a value added to, or removed from the enum of a schema, or a changed const.
+	CategoryEnum = "enum"
+
+	// CategoryGeneral is every other change.
+	CategoryGeneral = "general"
+)
+
+// Category returns the category of the change, CategoryEnum for the values of enums and consts (which what-changed
+// reports as changes to schema properties), CategoryGeneral for everything else.
+func (l *LocatedChange) Category() string {
+	if l.Change != nil && (l.Change.Property == v3.EnumLabel || l.Change.Property == v3.ConstLabel) {
+		return CategoryEnum
+	}
+	return CategoryGeneral
+}
+
+// changeTypeLabel is the change type of a change as rendered in a table, with the category of changes that are not
+// general.
+func changeTypeLabel(c *LocatedChange) string {
+	label := ChangeTypeName(c.Change.ChangeType)
+	if category := c.Category(); category != CategoryGeneral {
+		label += " (" + category + ")"
+	}
+	return label
+}
+
+// inCategories checks a change is in one of the categories, every change is when there are none.
+func inCategories(categories []string, c *LocatedChange) bool {
+	return len(categories) == 0 || slices.Contains(categories, c.Category())
+}
+
+// filterCategories removes the changes that are not in one of the categories from the report, with the operations,
+// groups and renames left without changes, and counts what is left. The most impactful changes are filtered
+// before they are ranked (see analyzeChanges). Nothing is removed when there are no categories.
+func (r *changeReport) filterCategories(categories []string) {
+	if len(categories) == 0 {
+		return
+	}
+	// changes are shared by more than one list of the report, so lists are copied rather than filtered in place.
+	keep := func(changes []*LocatedChange) []*LocatedChange {
+		var kept []*LocatedChange
+		for _, c := range changes {
+			if inCategories(categories, c) {
+				kept = append(kept, c)
+			}
+		}
+		return kept
+	}
+	r.located, r.other, r.acknowledged = keep(r.located), keep(r.other), keep(r.acknowledged)
+	// operations can be in more than one group, filtering them twice leaves the same changes.
+	var groups []*ChangeGroup
+	for _, g := range r.groups.Groups {
+		var operations []*OperationChangeReport
+		for _, op := range g.Operations {
+			op.Changes = keep(op.Changes)
+			var referenced []*ReferencedChange
+			for _, rc := range op.ReferencedChanges {
+				if rc.Changes = keep(rc.Changes); len(rc.Changes) > 0 {
+					referenced = append(referenced, rc)
+				}
+			}
+			if op.ReferencedChanges = referenced; op.TotalChanges() > 0 {
+				operations = append(operations, op)
+			}
+		}
+		if g.Operations = operations; len(operations) > 0 {
+			groups = append(groups, g)
+		}
+	}
+	r.groups.Groups = groups
+	var renamed []*RenamedComponent
+	for _, rn := range r.renamed {
+		if rn.Changes = keep(rn.Changes); len(rn.Changes) > 0 {
+			renamed = append(renamed, rn)
+		}
+	}
+	r.renamed = renamed
+	r.total, r.breaking = len(r.located), 0
+	for _, c := range r.located {
+		if c.Change.Breaking {
+			r.breaking++
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const enumSpec = `openapi: 3.1.0
+info:
+  title: burgers
+  version: 1.0.0
+paths:
+  /burgers:
+    get:
+      responses:
+        "200":
+          description: burgers
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Burger'
+components:
+  schemas:
+    Burger:
+      type: object
+      properties:
+        bun:
+          type: string
+          enum: [sesame, brioche]
+        kind:
+          const: beef`
+
+func TestLocatedChange_Category(t *testing.T) {
+	updated := strings.Replace(enumSpec, "[sesame, brioche]", "[sesame, pretzel]", 1)
+	updated = strings.Replace(updated, "const: beef", "const: chicken", 1)
+	updated = strings.Replace(updated, "version: 1.0.0", "version: 1.1.0", 1)
+
+	categories := make(map[string]int)
+	for _, c := range LocateChanges(compare(t, enumSpec, updated)) {
+		categories[c.Category()]++
+	}
+	assert.Equal(t, map[string]int{CategoryEnum: 3, CategoryGeneral: 1}, categories)
+}
+
+func TestReport_Categories(t *testing.T) {
+	updated := strings.Replace(enumSpec, "[sesame, brioche]", "[sesame, pretzel]", 1)
+	updated = strings.Replace(updated, "version: 1.0.0", "version: 1.1.0", 1)
+
+	md, err := Report([]byte(enumSpec), []byte(updated), FormatMarkdown, nil)
+	require.NoError(t, err)
+	assert.Contains(t, string(md), "3 changes, 1 breaking.")
+	assert.Contains(t, string(md), "| property_removed (enum) <!--")
+	assert.Contains(t, string(md), "## Other changes")
+
+	cfg := &RenderConfig{Categories: []string{CategoryEnum}}
+	md, err = Report([]byte(enumSpec), []byte(updated), FormatMarkdown, cfg)
+	require.NoError(t, err)
+	assert.Contains(t, string(md), "2 changes, 1 breaking.")
+	assert.Contains(t, string(md), "| property_added (enum) <!--")
+	assert.NotContains(t, string(md), "## Other changes")
+
+	j, err := Report([]byte(enumSpec), []byte(updated), FormatJSON, &RenderConfig{Categories: []string{CategoryGeneral}})
+	require.NoError(t, err)
+	var report struct {
+		TotalChanges int                      `json:"totalChanges"`
+		Groups       map[string]*GroupSummary `json:"groups"`
+		Changes      []*ReportChange          `json:"changes"`
+	}
+	require.NoError(t, json.Unmarshal(j, &report))
+	assert.Equal(t, 1, report.TotalChanges)
+	assert.Empty(t, report.Groups)
+	require.Len(t, report.Changes, 1)
+	assert.Equal(t, "$.info.version", report.Changes[0].Path)
+	assert.Equal(t, CategoryGeneral, report.Changes[0].Category)
+}
@@ -0,0 +1,227 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"fmt"
+	drModel "github.com/pb33f/doctor/model"
+	"github.com/pb33f/libopenapi/what-changed/model"
+	"gopkg.in/yaml.v3"
+	"slices"
+	"strings"
+)
+
+// ConsumerContract is the part of an API a consumer (a client) uses, so changes can be checked against what the
+// consumer relies on rather than the whole specification.
+//
+//	name: burger-app
+//	paths:
+//	  - $.paths['/burgers'].get
+//	  - '#/components/schemas/Burger/properties/name'
+type ConsumerContract struct {
+	// Name names the consumer.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Paths are JSONPaths, or component references, of the objects the consumer uses, in the original
+	// specification. Everything at or below a path is used.
+	Paths []string `json:"paths" yaml:"paths"`
+}
+
+// ContractResult is the outcome of evaluating changes against a consumer contract.
+type ContractResult struct {
+	Contract *ConsumerContract `json:"contract" yaml:"contract"`
+
+	// Changes are the changes that intersect the contract, BreakingChanges counts the breaking ones.
+	Changes         []*LocatedChange `json:"changes,omitempty" yaml:"changes,omitempty"`
+	BreakingChanges int              `json:"breakingChanges" yaml:"breakingChanges"`
+
+	// Excluded is the number of changes that do not intersect the contract.
+	Excluded int `json:"excluded" yaml:"excluded"`
+}
+
+// Breaks returns true when a change breaks the consumer.
+func (r *ContractResult) Breaks() bool {
+	return r.BreakingChanges > 0
+}
+
+// ParseConsumerContract parses a consumer contract from YAML or JSON.
+func ParseConsumerContract(data []byte) (*ConsumerContract, error) {
+	var contract ConsumerContract
+	if err := yaml.Unmarshal(data, &contract); err != nil {
+		return nil, fmt.Errorf("unable to parse consumer contract: %w", err)
+	}
+	if len(contract.Paths) == 0 {
+		return nil, fmt.Errorf("consumer contract '%s' has no paths", contract.Name)
+	}
+	for i, p := range contract.Paths {
+		if !strings.HasPrefix(p, "$") && !strings.HasPrefix(p, "#/") {
+			return nil, fmt.Errorf("path %d of consumer contract '%s' is not a JSONPath or a component reference: '%s'",
+				i, contract.Name, p)
+		}
+	}
+	return &contract, nil
+}
+
+// ConsumerContractFromSpec builds a consumer contract from a pruned specification: a copy of the specification
+// with only the operations, components and schema properties the consumer uses. Every operation and component
+// in it is part of the contract, component schemas with properties contribute only the properties they keep
+// (nested inline objects are pruned the same way).
+func ConsumerContractFromSpec(name string, spec []byte) (*ConsumerContract, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(spec, &root); err != nil {
+		return nil, fmt.Errorf("unable to parse the specification of consumer contract '%s': %w", name, err)
+	}
+	contract := &ConsumerContract{Name: name}
+	doc := &root
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		doc = doc.Content[0]
+	}
+	for _, section := range []string{"paths", "webhooks"} {
+		for path, item := range mapEntries(mapValue(doc, section)) {
+			for method := range mapEntries(item) {
+				if slices.Contains(contractMethods, method) {
+					contract.Paths = append(contract.Paths, appendProperty(appendKey("$."+section, path), method))
+				}
+			}
+		}
+	}
+	for kind, components := range mapEntries(mapValue(doc, "components")) {
+		for component, value := range mapEntries(components) {
+			path := appendKey("$.components."+kind, component)
+			if kind == "schemas" {
+				contract.Paths = append(contract.Paths, schemaContractPaths(path, value)...)
+				continue
+			}
+			contract.Paths = append(contract.Paths, path)
+		}
+	}
+	if len(contract.Paths) == 0 {
+		return nil, fmt.Errorf("the specification of consumer contract '%s' has no operations or components", name)
+	}
+	return contract, nil
+}
+
+// contractMethods are the keys of a path item that are operations.
+var contractMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// schemaContractPaths returns the paths of the properties a pruned schema keeps, or the schema when it has none.
+func schemaContractPaths(path string, schema *yaml.Node) []string {
+	properties := mapValue(schema, "properties")
+	if properties == nil || len(properties.Content) == 0 {
+		return []string{path}
+	}
+	var paths []string
+	for property, value := range mapEntries(properties) {
+		paths = append(paths, schemaContractPaths(appendKey(path+".properties", property), value)...)
+	}
+	return paths
+}
+
+// mapValue returns the value of a key of a mapping node, or nil.
+func mapValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// mapEntries iterates the keys and values of a mapping node, in order.
+func mapEntries(node *yaml.Node) func(yield func(string, *yaml.Node) bool) {
+	return func(yield func(string, *yaml.Node) bool) {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if !yield(node.Content[i].Value, node.Content[i+1]) {
+				return
+			}
+		}
+	}
+}
+
+// Expand returns a copy of the contract with the components its operations use, directly or through other
+// components, looked up in the original specification. Components the contract already names a part of (such
+// as some properties of a schema) are not added, so the contract keeps only that part.
+func (c *ConsumerContract) Expand(original *drModel.DrDocument) *ConsumerContract {
+	expanded := &ConsumerContract{Name: c.Name, Paths: slices.Clone(c.Paths)}
+	if original == nil {
+		return expanded
+	}
+	named := make(map[string]bool)
+	var entries [][]string
+	for _, p := range c.Paths {
+		segments := pathSegments(p)
+		entries = append(entries, segments)
+		if len(segments) >= 3 && segments[0] == "components" {
+			named[strings.Join(segments[:3], "/")] = true
+		}
+	}
+	for _, po := range original.Operations() {
+		if po.Operation == nil || po.Operation.Value == nil || po.Operation.Value.GoLow() == nil {
+			continue
+		}
+		operation := pathSegments(po.Operation.GenerateJSONPath())
+		if !slices.ContainsFunc(entries, func(e []string) bool { return pathContains(e, operation) }) {
+			continue
+		}
+		for _, def := range original.ReferencedComponents(po.Operation.Value.GoLow().RootNode) {
+			if key := strings.TrimPrefix(def, "#/"); !named[key] {
+				named[key] = true
+				expanded.Paths = append(expanded.Paths, def)
+			}
+		}
+	}
+	return expanded
+}
+
+// Covers returns true when a change intersects the contract: the changed property is at or below a path of the
+// contract, or above it (removing a path the contract uses below), or the change modifies an object the contract
+// uses part of (such as the type of a schema, when the contract uses some of its properties). Adding and removing
+// the unused children of such an object does not intersect the contract.
+func (c *ConsumerContract) Covers(change *LocatedChange) bool {
+	if c == nil || change == nil || change.Change == nil {
+		return false
+	}
+	property := pathSegments(change.PropertyPath())
+	owner := pathSegments(change.Path)
+	objectChange := change.Change.ChangeType == model.ObjectAdded || change.Change.ChangeType == model.ObjectRemoved
+	for _, p := range c.Paths {
+		entry := pathSegments(p)
+		if pathContains(entry, property) || pathContains(property, entry) {
+			return true
+		}
+		if !objectChange && pathContains(owner, entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// EvaluateContract checks document changes against a consumer contract, reporting only the changes that
+// intersect it: what would break the consumer, rather than what would break any client. The contract is
+// expanded (see ConsumerContract.Expand) with the original document, so changes to the components its
+// operations use are reported.
+func EvaluateContract(docChanges *model.DocumentChanges, contract *ConsumerContract, original *drModel.DrDocument) *ContractResult {
+	result := &ContractResult{Contract: contract}
+	if contract == nil {
+		return result
+	}
+	expanded := contract.Expand(original)
+	for _, c := range LocateChanges(docChanges) {
+		if !expanded.Covers(c) {
+			result.Excluded++
+			continue
+		}
+		result.Changes = append(result.Changes, c)
+		if c.Change.Breaking {
+			result.BreakingChanges++
+		}
+	}
+	return result
+}
@@ -0,0 +1,166 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	drModel "github.com/pb33f/doctor/model"
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const contractSpec = `openapi: 3.1.0
+info:
+  title: burgers
+  version: 1.0.0
+paths:
+  /burgers:
+    get:
+      responses:
+        "200":
+          description: burgers
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Burger'
+  /drinks:
+    get:
+      responses:
+        "200":
+          description: drinks
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Drink'
+components:
+  schemas:
+    Burger:
+      type: object
+      properties:
+        name:
+          type: string
+        price:
+          type: number
+        bun:
+          $ref: '#/components/schemas/Bun'
+    Bun:
+      type: string
+    Drink:
+      type: object
+      properties:
+        name:
+          type: string`
+
+func contractDocument(t *testing.T, spec string) *drModel.DrDocument {
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	v3Doc, _ := doc.BuildV3Model()
+	return drModel.NewDrDocument(v3Doc)
+}
+
+func TestParseConsumerContract(t *testing.T) {
+	contract, err := ParseConsumerContract([]byte(`name: burger-app
+paths:
+  - $.paths['/burgers'].get
+  - '#/components/schemas/Burger/properties/name'`))
+	require.NoError(t, err)
+	assert.Equal(t, "burger-app", contract.Name)
+	assert.Len(t, contract.Paths, 2)
+
+	_, err = ParseConsumerContract([]byte(`name: burger-app`))
+	assert.ErrorContains(t, err, "has no paths")
+	_, err = ParseConsumerContract([]byte(`paths: [burgers]`))
+	assert.ErrorContains(t, err, "is not a JSONPath or a component reference")
+}
+
+func TestConsumerContractFromSpec(t *testing.T) {
+	contract, err := ConsumerContractFromSpec("burger-app", []byte(`openapi: 3.1.0
+paths:
+  /burgers:
+    parameters:
+      - name: limit
+        in: query
+    get:
+      responses:
+        "200":
+          description: burgers
+components:
+  schemas:
+    Burger:
+      type: object
+      properties:
+        name:
+          type: string
+        bun:
+          type: object
+          properties:
+            seeds:
+              type: boolean
+    Bun:
+      type: string
+  responses:
+    NotFound:
+      description: not found`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"$.paths['/burgers'].get",
+		"$.components.schemas['Burger'].properties['name']",
+		"$.components.schemas['Burger'].properties['bun'].properties['seeds']",
+		"$.components.schemas['Bun']",
+		"$.components.responses['NotFound']",
+	}, contract.Paths)
+
+	_, err = ConsumerContractFromSpec("burger-app", []byte(`openapi: 3.1.0`))
+	assert.ErrorContains(t, err, "has no operations or components")
+}
+
+func TestEvaluateContract(t *testing.T) {
+	updated := strings.Replace(contractSpec, "        price:\n          type: number\n", "", 1)
+	updated = strings.Replace(updated, "    Bun:\n      type: string", "    Bun:\n      type: integer", 1)
+	updated = strings.Replace(updated, "    Drink:\n      type: object\n      properties:\n        name:\n          type: string",
+		"    Drink:\n      type: object", 1)
+	updated = strings.Replace(updated, "        name:\n          type: string\n        bun:",
+		"        name:\n          type: integer\n        bun:", 1)
+	docChanges := compare(t, contractSpec, updated)
+	require.NotNil(t, docChanges)
+	original := contractDocument(t, contractSpec)
+
+	// the consumer lists burgers, and reads only the name of a burger.
+	contract := &ConsumerContract{Name: "burger-app", Paths: []string{
+		"$.paths['/burgers'].get",
+		"#/components/schemas/Burger/properties/name",
+	}}
+	result := EvaluateContract(docChanges, contract, original)
+	var paths []string
+	for _, c := range result.Changes {
+		paths = append(paths, c.PropertyPath())
+	}
+	// the removed price (unused) and the drink changes are excluded, Bun is used through Burger.
+	assert.ElementsMatch(t, []string{
+		"$.components.schemas['Burger'].properties['name'].type",
+		"$.components.schemas['Bun'].type",
+	}, paths)
+	assert.Equal(t, 2, result.Excluded)
+	assert.True(t, result.Breaks())
+
+	expanded := contract.Expand(original)
+	assert.Equal(t, append(contract.Paths, "#/components/schemas/Bun"), expanded.Paths)
+	assert.Len(t, contract.Paths, 2)
+
+	// a consumer of drinks is broken by the removed drink name alone.
+	drinks := EvaluateContract(docChanges, &ConsumerContract{Paths: []string{"$.paths['/drinks']"}}, original)
+	require.Len(t, drinks.Changes, 1)
+	assert.Equal(t, "$.components.schemas['Drink'].properties['name']", drinks.Changes[0].PropertyPath())
+
+	// the gate only counts what the consumer uses.
+	gate := EvaluateGate(docChanges, GateConfig{MaxBreaking: 0, MaxTotal: Unlimited,
+		Contract: (&ConsumerContract{Paths: []string{"$.paths['/drinks'].get"}}).Expand(original)})
+	assert.False(t, gate.Passed)
+	assert.Equal(t, 1, gate.TotalChanges)
+	assert.Equal(t, 3, gate.OutsideContract)
+
+	assert.Empty(t, EvaluateContract(docChanges, nil, original).Changes)
+}
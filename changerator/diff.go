@@ -0,0 +1,305 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"fmt"
+	drModel "github.com/pb33f/doctor/model"
+	"github.com/pb33f/libopenapi/what-changed/model"
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+// maxDiffRegion is the most lines of an object diffed for a change, larger objects (like the document itself) are
+// not diffed, only the block at the line of the change is.
+const maxDiffRegion = 400
+
+// DiffHunk is a unified diff hunk of the raw specifications around a change. Starts are one-based lines, Lines are
+// prefixed with ' ' (unchanged), '-' (only in the original) or '+' (only in the updated specification).
+type DiffHunk struct {
+	Change        *LocatedChange
+	OriginalStart int
+	OriginalCount int
+	NewStart      int
+	NewCount      int
+	Lines         []string
+}
+
+// String renders the hunk, the header is followed by the location of the change.
+func (h *DiffHunk) String() string {
+	buf := strings.Builder{}
+	buf.WriteString(fmt.Sprintf("@@ -%s +%s @@ %s\n", hunkRange(h.OriginalStart, h.OriginalCount),
+		hunkRange(h.NewStart, h.NewCount), h.Change.PropertyPath()))
+	for _, l := range h.Lines {
+		buf.WriteString(l + "\n")
+	}
+	return buf.String()
+}
+
+// Differ renders unified diffs of the raw specifications around each change. Context is the number of unchanged
+// lines kept around the changed lines of a hunk, and Redactor (when set) is applied to every line of a hunk, with
+// the path of its change (see RenderConfig.Redactor).
+type Differ struct {
+	Context  int
+	Redactor func(path, value string) string
+	left     *diffSource
+	right    *diffSource
+}
+
+type diffSource struct {
+	lines   []string
+	objects *Attachments
+}
+
+// NewDiffer creates a differ for the raw original and updated specifications and their doctor documents, with
+// three lines of context.
+func NewDiffer(leftSpec, rightSpec []byte, left, right *drModel.DrDocument) *Differ {
+	source := func(spec []byte, drDoc *drModel.DrDocument) *diffSource {
+		lines := strings.Split(strings.TrimSuffix(string(spec), "\n"), "\n")
+		for i := range lines {
+			lines[i] = strings.TrimSuffix(lines[i], "\r")
+		}
+		return &diffSource{lines: lines, objects: &Attachments{drDoc: drDoc}}
+	}
+	return &Differ{Context: 3, left: source(leftSpec, left), right: source(rightSpec, right)}
+}
+
+// Hunks returns a hunk for every change in the document changes, in the order they are located. Changes that
+// cannot be seen in the raw specifications (the lines around them are identical) have no hunk.
+func (d *Differ) Hunks(docChanges *model.DocumentChanges) []*DiffHunk {
+	var hunks []*DiffHunk
+	for _, c := range LocateChanges(docChanges) {
+		if h := d.Hunk(c); h != nil {
+			hunks = append(hunks, h)
+		}
+	}
+	return hunks
+}
+
+// Render renders every hunk of the document changes as a unified diff.
+func (d *Differ) Render(docChanges *model.DocumentChanges) string {
+	buf := strings.Builder{}
+	buf.WriteString("--- original\n+++ updated\n")
+	for _, h := range d.Hunks(docChanges) {
+		buf.WriteString(h.String())
+	}
+	return buf.String()
+}
+
+// Hunk diffs the raw specifications around a change. The region diffed on each side is the block of the object
+// the change was made to (from its key node to the end of its value), found by the path of the change, so
+// additions and removals are shown where they are made. When the object cannot be found, does not contain the
+// line of the change, or is too large, the region is the block at the line of the change, the line and the lines
+// indented beneath it. Hunk returns nil when the regions are identical.
+func (d *Differ) Hunk(c *LocatedChange) *DiffHunk {
+	if c == nil || c.Change == nil || c.Change.Context == nil {
+		return nil
+	}
+	originalLine, newLine := 0, 0
+	if c.Change.Context.OriginalLine != nil {
+		originalLine = *c.Change.Context.OriginalLine
+	}
+	if c.Change.Context.NewLine != nil {
+		newLine = *c.Change.Context.NewLine
+	}
+	if originalLine == 0 && newLine == 0 {
+		return nil
+	}
+
+	lStart, lEnd := d.left.objectBlock(c.Path, originalLine)
+	rStart, rEnd := d.right.objectBlock(c.Path, newLine)
+	if lStart == 0 || rStart == 0 {
+		lStart, lEnd = d.left.lineBlock(originalLine)
+		rStart, rEnd = d.right.lineBlock(newLine)
+		// an empty region is positioned at the line of the other side.
+		if lStart == 0 {
+			lStart, lEnd = d.left.emptyBlock(newLine)
+		}
+		if rStart == 0 {
+			rStart, rEnd = d.right.emptyBlock(originalLine)
+		}
+	}
+
+	ops := diffLines(d.left.lines[lStart-1:lEnd], d.right.lines[rStart-1:rEnd])
+	first, last := -1, -1
+	for i, op := range ops {
+		if op[0] != ' ' {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return nil
+	}
+	from, to := max(0, first-d.Context), min(len(ops), last+d.Context+1)
+
+	h := &DiffHunk{Change: c, OriginalStart: lStart, NewStart: rStart}
+	for i, op := range ops[:to] {
+		if i < from {
+			if op[0] != '+' {
+				h.OriginalStart++
+			}
+			if op[0] != '-' {
+				h.NewStart++
+			}
+			continue
+		}
+		if op[0] != '+' {
+			h.OriginalCount++
+		}
+		if op[0] != '-' {
+			h.NewCount++
+		}
+		if d.Redactor != nil {
+			op = op[:1] + d.Redactor(c.PropertyPath(), op[1:])
+		}
+		h.Lines = append(h.Lines, op)
+	}
+	return h
+}
+
+// annotateDiffs sets the diff of every modified change, changes are located more than once while building a
+// report, every located copy of a change is annotated. Additions and removals have a single value, they are not
+// diffed.
+func annotateDiffs(d *Differ, changes ...[]*LocatedChange) {
+	hunks := make(map[*model.Change]*DiffHunk)
+	for _, list := range changes {
+		for _, c := range list {
+			if c.Change.ChangeType != model.Modified {
+				continue
+			}
+			h, ok := hunks[c.Change]
+			if !ok {
+				h = d.Hunk(c)
+				hunks[c.Change] = h
+			}
+			c.Diff = h
+		}
+	}
+}
+
+// objectBlock returns the lines of the object at a path, zero when there is no object, it is too large, or it
+// does not contain the line (when there is one).
+func (s *diffSource) objectBlock(path string, line int) (int, int) {
+	if s.objects.drDoc == nil {
+		return 0, 0
+	}
+	f := s.objects.objectAtPath(path)
+	if f == nil {
+		return 0, 0
+	}
+	value := f.GetValueNode()
+	if value == nil {
+		return 0, 0
+	}
+	start := value.Line
+	if key := f.GetKeyNode(); key != nil {
+		start = key.Line
+	}
+	if start < 1 || start > len(s.lines) {
+		return 0, 0
+	}
+	end := s.extend(start, max(start, lastLine(value)))
+	if end-start+1 > maxDiffRegion || (line != 0 && (line < start || line > end)) {
+		return 0, 0
+	}
+	return start, end
+}
+
+// lineBlock returns the line and the lines indented beneath it, zero when there is no line.
+func (s *diffSource) lineBlock(line int) (int, int) {
+	if line < 1 || line > len(s.lines) {
+		return 0, 0
+	}
+	return line, s.extend(line, line)
+}
+
+// emptyBlock returns an empty block before a line.
+func (s *diffSource) emptyBlock(line int) (int, int) {
+	line = min(max(line, 1), len(s.lines)+1)
+	return line, line - 1
+}
+
+// extend moves the end of a block past the lines indented deeper than its first line (like the lines of a block
+// scalar), trailing blank lines are not part of the block.
+func (s *diffSource) extend(start, end int) int {
+	end = min(end, len(s.lines))
+	indent := indentation(s.lines[start-1])
+	for end < len(s.lines) {
+		next := s.lines[end]
+		if strings.TrimSpace(next) != "" && indentation(next) <= indent {
+			break
+		}
+		end++
+	}
+	for end > start && strings.TrimSpace(s.lines[end-1]) == "" {
+		end--
+	}
+	return end
+}
+
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// lastLine returns the last line of a node and its descendants.
+func lastLine(n *yaml.Node) int {
+	last := n.Line
+	for _, c := range n.Content {
+		last = max(last, lastLine(c))
+	}
+	return last
+}
+
+// diffLines diffs two regions line by line (longest common subsequence), every line is prefixed with its
+// operation.
+func diffLines(left, right []string) []string {
+	// lcs[i][j] is the length of the longest common subsequence of left[i:] and right[j:].
+	lcs := make([][]int, len(left)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(right)+1)
+	}
+	for i := len(left) - 1; i >= 0; i-- {
+		for j := len(right) - 1; j >= 0; j-- {
+			if left[i] == right[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var ops []string
+	i, j := 0, 0
+	for i < len(left) && j < len(right) {
+		switch {
+		case left[i] == right[j]:
+			ops = append(ops, " "+left[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, "-"+left[i])
+			i++
+		default:
+			ops = append(ops, "+"+right[j])
+			j++
+		}
+	}
+	for ; i < len(left); i++ {
+		ops = append(ops, "-"+left[i])
+	}
+	for ; j < len(right); j++ {
+		ops = append(ops, "+"+right[j])
+	}
+	return ops
+}
+
+// hunkRange renders the range of a side of a hunk, an empty range starts at the line before it.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
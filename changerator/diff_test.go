@@ -0,0 +1,79 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestDiffer_Hunks(t *testing.T) {
+	updated := strings.Replace(impactSpec, "name:\n          type: string",
+		"name:\n          type: integer\n        size:\n          type: string", 1)
+	updated = strings.Replace(updated, "      deprecated: true\n", "", 1)
+
+	differ := NewDiffer([]byte(impactSpec), []byte(updated), drDocument(t, impactSpec), drDocument(t, updated))
+	differ.Context = 1
+	hunks := differ.Hunks(compare(t, impactSpec, updated))
+	rendered := map[string]string{}
+	for _, h := range hunks {
+		rendered[h.Change.PropertyPath()] = h.String()
+	}
+	require.Len(t, rendered, 3)
+
+	assert.Equal(t, "@@ -37,2 +36,2 @@ $.components.schemas['Burger'].properties['name'].type\n"+
+		"         name:\n-          type: string\n+          type: integer\n",
+		rendered["$.components.schemas['Burger'].properties['name'].type"])
+
+	// the addition is diffed in the properties it was added to.
+	assert.Equal(t, "@@ -37,2 +36,4 @@ $.components.schemas['Burger'].properties['size']\n"+
+		"         name:\n+          type: integer\n+        size:\n           type: string\n",
+		rendered["$.components.schemas['Burger'].properties['size']"])
+
+	// the removal is shown in the operation it was removed from.
+	assert.Equal(t, "@@ -26,3 +26,2 @@ $.paths['/fries'].get.deprecated\n"+
+		"     get:\n-      deprecated: true\n       description: list fries\n",
+		rendered["$.paths['/fries'].get.deprecated"])
+}
+
+func TestDiffer_Hunk_NoObject(t *testing.T) {
+	updated := strings.Replace(impactSpec, "version: 1.0.0", "version: 1.1.0", 1)
+
+	// without doctor documents, the block at the line of the change is diffed.
+	differ := NewDiffer([]byte(impactSpec), []byte(updated), nil, nil)
+	hunks := differ.Hunks(compare(t, impactSpec, updated))
+	require.Len(t, hunks, 1)
+	assert.Equal(t, "@@ -4,1 +4,1 @@ $.info.version\n-  version: 1.0.0\n+  version: 1.1.0\n", hunks[0].String())
+	assert.Empty(t, differ.Hunks(nil))
+}
+
+func TestReport_Diff(t *testing.T) {
+	updated := strings.Replace(impactSpec, "list burgers", "list every burger", 1)
+
+	out, err := Report([]byte(impactSpec), []byte(updated), FormatDiff, &RenderConfig{DiffContext: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "--- original\n+++ updated\n"+
+		"@@ -7,3 +7,3 @@ $.paths['/burgers'].get.description\n"+
+		"     get:\n-      description: list burgers\n+      description: list every burger\n       responses:\n",
+		string(out))
+}
+
+func TestReport_ValueDiffs(t *testing.T) {
+	h, err := Report([]byte(leftSpec), []byte(rightSpec), FormatHTML, &RenderConfig{ValueDiffs: true, DiffContext: 1})
+	require.NoError(t, err)
+	assert.Contains(t, string(h), "<style>\ntr.diff pre")
+	assert.Contains(t, string(h), "<td><code>1.0.0</code></td><td><code>1.1.0</code></td><td></td></tr>\n"+
+		"<tr class=\"diff\"><td colspan=\"5\"><pre><span class=\"diff-context\">   title: burgers</span>\n"+
+		"<span class=\"diff-remove\">-  version: 1.0.0</span>\n"+
+		"<span class=\"diff-add\">+  version: 1.1.0</span>\n</pre></td></tr>\n")
+	// removals have a single value, they are not diffed.
+	assert.Equal(t, 1, strings.Count(string(h), "<tr class=\"diff\">"))
+
+	h, err = Report([]byte(leftSpec), []byte(rightSpec), FormatHTML, nil)
+	require.NoError(t, err)
+	assert.NotContains(t, string(h), "<style>")
+	assert.NotContains(t, string(h), "<tr class=\"diff\">")
+}
@@ -0,0 +1,164 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"context"
+	"fmt"
+	drModel "github.com/pb33f/doctor/model"
+	whatChanged "github.com/pb33f/libopenapi/what-changed"
+	"github.com/pb33f/libopenapi/what-changed/model"
+	"html"
+	"strings"
+)
+
+// Digest is an email summarizing the changes between two versions of a specification. HTML is email-safe:
+// the layout is made of tables, every style is inline and nothing is loaded from elsewhere. Text is the plain text
+// alternative for the same content.
+type Digest struct {
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// inline styles of the digest, email clients ignore (or strip) style sheets.
+const (
+	digestBody   = "margin:0;padding:0;background-color:#f4f4f4;"
+	digestTable  = "border-collapse:collapse;background-color:#ffffff;font-family:Arial,Helvetica,sans-serif;font-size:14px;color:#222222;"
+	digestTitle  = "padding:16px;font-size:20px;font-weight:bold;border-bottom:2px solid #222222;"
+	digestHeader = "padding:12px 16px 4px 16px;font-size:16px;font-weight:bold;"
+	digestCell   = "padding:4px 16px;border-bottom:1px solid #e0e0e0;vertical-align:top;"
+	digestCode   = "font-family:Menlo,Consolas,monospace;font-size:12px;"
+	digestRed    = "color:#b00020;font-weight:bold;"
+)
+
+// EmailDigest compares two versions of a specification and renders an email digest of the changes, for example
+// for a nightly API change notification. Only cfg.Group, cfg.Methods, the document configurations, cfg.Redactor and
+// the events, tracing and memory budget of the walk are used, a nil cfg groups by tag.
+func EmailDigest(leftSpec, rightSpec []byte, cfg *RenderConfig) (*Digest, error) {
+	if cfg == nil {
+		cfg = &RenderConfig{}
+	}
+	left, err := buildModel("original", leftSpec, cfg.originalConfiguration())
+	if err != nil {
+		return nil, err
+	}
+	right, err := buildModel("updated", rightSpec, cfg.DocumentConfiguration)
+	if err != nil {
+		return nil, err
+	}
+	docChanges := whatChanged.CompareOpenAPIDocuments(left.Model.GoLow(), right.Model.GoLow())
+	if cfg.Redactor != nil {
+		redactChanges(LocateChanges(docChanges), cfg.Redactor)
+	}
+	drDoc, err := cfg.walk(context.Background(), "updated", right)
+	if err != nil {
+		return nil, err
+	}
+	return NewDigest(docChanges, drDoc, cfg.groupConfig()), nil
+}
+
+// NewDigest renders an email digest of document changes: the totals, every breaking change, and the number of
+// changes made to the operations of each group (see GroupChanges). The document is the updated version of the
+// specification, its title is used in the subject.
+func NewDigest(docChanges *model.DocumentChanges, drDoc *drModel.DrDocument, config GroupConfig) *Digest {
+	title := "API"
+	if drDoc != nil && drDoc.V3Document != nil && drDoc.V3Document.Document != nil &&
+		drDoc.V3Document.Document.Info != nil && drDoc.V3Document.Document.Info.Title != "" {
+		title = drDoc.V3Document.Document.Info.Title
+	}
+	total, breaking := 0, 0
+	var breakingChanges []*LocatedChange
+	if docChanges != nil {
+		total, breaking = docChanges.TotalChanges(), docChanges.TotalBreakingChanges()
+		for _, c := range LocateChanges(docChanges) {
+			if c.Change.Breaking {
+				breakingChanges = append(breakingChanges, c)
+			}
+		}
+	}
+	groups := GroupChanges(docChanges, drDoc, config)
+	totals := fmt.Sprintf("%d changes, %d breaking.", total, breaking)
+
+	d := &Digest{Subject: fmt.Sprintf("%s: %d changes, %d breaking", title, total, breaking)}
+
+	text := strings.Builder{}
+	text.WriteString(fmt.Sprintf("%s changes\n\n%s\n", title, totals))
+	if len(breakingChanges) > 0 {
+		text.WriteString("\nBreaking changes\n\n")
+		for _, c := range breakingChanges {
+			text.WriteString(fmt.Sprintf("- %s %s%s\n", ChangeTypeName(c.Change.ChangeType), c.PropertyPath(),
+				textValues(c.Change.Original, c.Change.New)))
+		}
+	}
+	if len(groups.Groups) > 0 {
+		text.WriteString("\nOperation changes by group\n\n")
+		for _, g := range groups.Groups {
+			t, b := g.counts()
+			text.WriteString(fmt.Sprintf("- %s: %d changes, %d breaking\n", g.Name, t, b))
+		}
+	}
+	d.Text = text.String()
+
+	buf := strings.Builder{}
+	buf.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	buf.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	buf.WriteString(fmt.Sprintf("<title>%s</title>\n</head>\n", html.EscapeString(d.Subject)))
+	buf.WriteString(fmt.Sprintf("<body style=\"%s\">\n", digestBody))
+	buf.WriteString("<table role=\"presentation\" width=\"100%\" cellpadding=\"0\" cellspacing=\"0\" border=\"0\">\n" +
+		"<tr><td align=\"center\" style=\"padding:16px;\">\n")
+	buf.WriteString(fmt.Sprintf("<table role=\"presentation\" width=\"640\" cellpadding=\"0\" cellspacing=\"0\" "+
+		"border=\"0\" style=\"%s\">\n", digestTable))
+	buf.WriteString(fmt.Sprintf("<tr><td colspan=\"4\" style=\"%s\">%s changes</td></tr>\n", digestTitle,
+		html.EscapeString(title)))
+	totalsStyle := ""
+	if breaking > 0 {
+		totalsStyle = digestRed
+	}
+	buf.WriteString(fmt.Sprintf("<tr><td colspan=\"4\" style=\"%s%s\">%s</td></tr>\n", digestCell, totalsStyle,
+		totals))
+	if len(breakingChanges) > 0 {
+		buf.WriteString(fmt.Sprintf("<tr><td colspan=\"4\" style=\"%s\">Breaking changes</td></tr>\n", digestHeader))
+		for _, c := range breakingChanges {
+			buf.WriteString(fmt.Sprintf("<tr><td style=\"%s\">%s</td><td style=\"%s%s\">%s</td>"+
+				"<td style=\"%s%s\">%s</td><td style=\"%s%s\">%s</td></tr>\n",
+				digestCell, ChangeTypeName(c.Change.ChangeType),
+				digestCell, digestCode, html.EscapeString(c.PropertyPath()),
+				digestCell, digestCode, html.EscapeString(c.Change.Original),
+				digestCell, digestCode, html.EscapeString(c.Change.New)))
+		}
+	}
+	if len(groups.Groups) > 0 {
+		buf.WriteString(fmt.Sprintf("<tr><td colspan=\"4\" style=\"%s\">Operation changes by group</td></tr>\n", digestHeader))
+		for _, g := range groups.Groups {
+			t, b := g.counts()
+			style := ""
+			if b > 0 {
+				style = digestRed
+			}
+			buf.WriteString(fmt.Sprintf("<tr><td colspan=\"2\" style=\"%s\">%s</td><td style=\"%s\">%d changes</td>"+
+				"<td style=\"%s%s\">%d breaking</td></tr>\n", digestCell, html.EscapeString(g.Name), digestCell, t,
+				digestCell, style, b))
+		}
+	}
+	buf.WriteString("</table>\n</td></tr>\n</table>\n</body>\n</html>\n")
+	d.HTML = buf.String()
+	return d
+}
+
+// textValues renders the original and new values of a change for the plain text digest.
+func textValues(original, updated string) string {
+	flatten := func(v string) string {
+		return strings.ReplaceAll(v, "\n", " ")
+	}
+	switch {
+	case original != "" && updated != "":
+		return fmt.Sprintf(": %s -> %s", flatten(original), flatten(updated))
+	case original != "":
+		return fmt.Sprintf(": %s", flatten(original))
+	case updated != "":
+		return fmt.Sprintf(": %s", flatten(updated))
+	}
+	return ""
+}
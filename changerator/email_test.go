@@ -0,0 +1,45 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestEmailDigest(t *testing.T) {
+	updated := strings.Replace(impactSpec, "name:\n          type: string", "name:\n          type: integer", 1)
+	updated = strings.Replace(updated, "list burgers", "list <every> burger", 1)
+
+	d, err := EmailDigest([]byte(impactSpec), []byte(updated), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "burgers: 2 changes, 1 breaking", d.Subject)
+
+	// email clients drop style sheets and classes, everything is inline.
+	assert.NotContains(t, d.HTML, "<style")
+	assert.NotContains(t, d.HTML, "class=")
+	assert.NotContains(t, d.HTML, "<link")
+	assert.Contains(t, d.HTML, "<table role=\"presentation\"")
+	assert.Contains(t, d.HTML, "2 changes, 1 breaking.")
+	assert.Contains(t, d.HTML, "$.components.schemas[&#39;Burger&#39;].properties[&#39;name&#39;].type")
+	assert.NotContains(t, d.HTML, "<every>")
+
+	assert.Contains(t, d.Text, "burgers changes\n\n2 changes, 1 breaking.\n")
+	assert.Contains(t, d.Text, "Breaking changes\n\n- modified $.components.schemas['Burger'].properties['name'].type: string -> integer\n")
+	// a change to a shared component is a change to every operation that uses it.
+	assert.Contains(t, d.Text, "Operation changes by group\n\n")
+	assert.Contains(t, d.Text, "- unowned: 3 changes, 2 breaking\n")
+
+	_, err = EmailDigest([]byte("not a spec"), []byte(updated), nil)
+	assert.Error(t, err)
+}
+
+func TestNewDigest_NoChanges(t *testing.T) {
+	d := NewDigest(nil, drDocument(t, impactSpec), GroupConfig{})
+	assert.Equal(t, "burgers: 0 changes, 0 breaking", d.Subject)
+	assert.NotContains(t, d.Text, "Breaking changes")
+	assert.NotContains(t, d.HTML, "Breaking changes")
+}
@@ -0,0 +1,208 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"fmt"
+	drModel "github.com/pb33f/doctor/model"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/doctor/validation"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/pb33f/libopenapi/what-changed/model"
+	"gopkg.in/yaml.v3"
+	"html"
+	"slices"
+	"strings"
+)
+
+const FindingExampleDrift = "example-drift"
+
+// ExampleDrift is an example that was valid in the original specification, and is not valid for its schema in
+// the updated specification, because the schema changed (for example an enum value the example uses was removed).
+// The finding is attached to the object the example belongs to, Example is the JSONPath of the example itself.
+// Violations are the reasons it is no longer valid, and Changes are the changes made to its schema, or to the
+// components its schema uses.
+type ExampleDrift struct {
+	*drBase.Finding
+	Example    string
+	Violations []*validation.Violation
+	Changes    []*LocatedChange
+}
+
+// documentExample is an example, and the schema it is an example of.
+type documentExample struct {
+	path       string
+	value      *yaml.Node
+	schema     *base.Schema
+	schemaPath string
+	schemaNode *yaml.Node
+	owner      *yaml.Node
+	object     drBase.Foundational
+}
+
+// DetectExampleDrift validates the examples of media types, parameters, headers and schemas in both versions of a
+// specification, and returns the examples that became invalid because of a change to their schema. Examples that
+// are new, were already invalid, or became invalid without a change to their schema (the example itself was
+// edited) are not drift. Drift is returned in the order examples are found: media types, parameters, headers, then
+// schemas.
+func DetectExampleDrift(docChanges *model.DocumentChanges, left, right *drModel.DrDocument) []*ExampleDrift {
+	if docChanges == nil || left == nil || right == nil {
+		return nil
+	}
+	located := LocateChanges(docChanges)
+	original := make(map[string]*documentExample)
+	for _, e := range documentExamples(left) {
+		original[e.path] = e
+	}
+
+	var drift []*ExampleDrift
+	for _, e := range documentExamples(right) {
+		before := original[e.path]
+		if before == nil || len(before.violations()) > 0 {
+			continue
+		}
+		violations := e.violations()
+		if len(violations) == 0 {
+			continue
+		}
+		schemaPath := pathSegments(e.schemaPath)
+		var components []string
+		resolved := false
+		var causes []*LocatedChange
+		for _, c := range located {
+			if pathContains(schemaPath, pathSegments(c.PropertyPath())) || changedWithin(c, before, e) {
+				causes = append(causes, c)
+				continue
+			}
+			def := componentForPath(c.Path)
+			if def == "" {
+				continue
+			}
+			if !resolved {
+				components, resolved = right.ReferencedComponents(e.owner), true
+			}
+			if slices.Contains(components, def) {
+				causes = append(causes, c)
+			}
+		}
+		if len(causes) == 0 {
+			continue
+		}
+		drift = append(drift, &ExampleDrift{
+			Finding: drBase.NewFinding(FindingExampleDrift, drBase.SeverityWarn,
+				fmt.Sprintf("example `%s` is no longer valid for its schema: %s", e.path, violations[0].Message),
+				e.object),
+			Example:    e.path,
+			Violations: violations,
+			Changes:    causes,
+		})
+	}
+	return drift
+}
+
+// changedWithin returns true when a change was made on a line of the schema of the example, in either version.
+func changedWithin(c *LocatedChange, before, after *documentExample) bool {
+	within := func(n *yaml.Node, line *int) bool {
+		return n != nil && line != nil && *line >= n.Line && *line <= lastLine(n)
+	}
+	ctx := c.Change.Context
+	return ctx != nil && (within(after.schemaNode, ctx.NewLine) || within(before.schemaNode, ctx.OriginalLine))
+}
+
+// violations validates the example against its schema.
+func (e *documentExample) violations() []*validation.Violation {
+	var value any
+	if err := e.value.Decode(&value); err != nil {
+		return nil
+	}
+	return validation.ValidateSchema(e.schema, value, e.schemaPath, e.path)
+}
+
+// documentExamples returns every example of the document that has a schema to validate it with.
+func documentExamples(drDoc *drModel.DrDocument) []*documentExample {
+	var found []*documentExample
+	seen := make(map[string]bool)
+	add := func(path string, value *yaml.Node, schema *base.Schema, schemaPath string, schemaNode, owner *yaml.Node,
+		object drBase.Foundational) {
+		if value == nil || schema == nil || seen[path] {
+			return
+		}
+		seen[path] = true
+		found = append(found, &documentExample{path: path, value: value, schema: schema, schemaPath: schemaPath,
+			schemaNode: schemaNode, owner: owner, object: object})
+	}
+	// objects with a schema, and an example and named examples.
+	addAll := func(f drBase.Foundational, proxy *base.SchemaProxy, example *yaml.Node,
+		examples *orderedmap.Map[string, *base.Example], owner *yaml.Node) {
+		if proxy == nil || proxy.GoLow() == nil {
+			return
+		}
+		schema, schemaNode := proxy.Schema(), proxy.GoLow().GetValueNode()
+		path := f.GenerateJSONPath()
+		add(path+".example", example, schema, path+".schema", schemaNode, owner, f)
+		for name, ex := range examples.FromOldest() {
+			if ex != nil {
+				add(appendKey(path+".examples", name), ex.Value, schema, path+".schema", schemaNode, owner, f)
+			}
+		}
+	}
+
+	for _, mt := range drDoc.MediaTypes {
+		if mt.Value != nil && mt.Value.GoLow() != nil {
+			addAll(mt, mt.Value.Schema, mt.Value.Example, mt.Value.Examples, mt.Value.GoLow().RootNode)
+		}
+	}
+	for _, p := range drDoc.Parameters {
+		if p.Value != nil && p.Value.GoLow() != nil {
+			addAll(p, p.Value.Schema, p.Value.Example, p.Value.Examples, p.Value.GoLow().RootNode)
+		}
+	}
+	for _, h := range drDoc.Headers {
+		if h.Value != nil && h.Value.GoLow() != nil {
+			addAll(h, h.Value.Schema, h.Value.Example, h.Value.Examples, h.Value.GoLow().RootNode)
+		}
+	}
+	for _, s := range drDoc.Schemas {
+		if s.Value == nil || s.Value.GoLow() == nil {
+			continue
+		}
+		path := s.GenerateJSONPath()
+		owner := s.Value.GoLow().RootNode
+		add(path+".example", s.Value.Example, s.Value, path, owner, owner, s)
+		for i, ex := range s.Value.Examples {
+			add(fmt.Sprintf("%s.examples[%d]", path, i), ex, s.Value, path, owner, owner, s)
+		}
+	}
+	return found
+}
+
+// markdown renders the drift as a list item, with the changes that caused it.
+func (d *ExampleDrift) markdown(redact func(path, value string) string) string {
+	buf := strings.Builder{}
+	buf.WriteString(fmt.Sprintf("- `%s`\n", d.Example))
+	for _, v := range d.Violations {
+		buf.WriteString(fmt.Sprintf("  - %s (`%s`)\n", redact(v.Location, v.Message), v.Path))
+	}
+	for _, c := range d.Changes {
+		buf.WriteString(fmt.Sprintf("  - caused by %s `%s`\n", ChangeTypeName(c.Change.ChangeType), c.PropertyPath()))
+	}
+	return buf.String()
+}
+
+// html renders the drift as a list item, with links to the changes that caused it.
+func (d *ExampleDrift) html(redact func(path, value string) string) string {
+	buf := strings.Builder{}
+	buf.WriteString(fmt.Sprintf("<li><code>%s</code>\n<ul>\n", html.EscapeString(d.Example)))
+	for _, v := range d.Violations {
+		buf.WriteString(fmt.Sprintf("<li>%s (<code>%s</code>)</li>\n", html.EscapeString(redact(v.Location, v.Message)),
+			html.EscapeString(v.Path)))
+	}
+	for _, c := range d.Changes {
+		buf.WriteString(fmt.Sprintf("<li>caused by <a href=\"#%s\">%s <code>%s</code></a></li>\n", c.Anchor(),
+			ChangeTypeName(c.Change.ChangeType), html.EscapeString(c.PropertyPath())))
+	}
+	buf.WriteString("</ul>\n</li>\n")
+	return buf.String()
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+var driftSpec = `openapi: 3.1.0
+info:
+  title: burgers
+  version: 1.0.0
+paths:
+  /burgers:
+    get:
+      parameters:
+        - name: size
+          in: query
+          example: large
+          schema:
+            type: string
+            enum: [small, large]
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Burger'
+              examples:
+                cheese:
+                  value:
+                    name: cheese
+                    bun: brioche
+components:
+  schemas:
+    Burger:
+      type: object
+      properties:
+        name:
+          type: string
+        bun:
+          $ref: '#/components/schemas/Bun'
+    Bun:
+      type: string
+      enum: [brioche, sesame]
+      example: sesame`
+
+func TestDetectExampleDrift(t *testing.T) {
+	// brioche buns and large burgers are gone.
+	updated := strings.Replace(driftSpec, "enum: [brioche, sesame]", "enum: [sesame, potato]", 1)
+	updated = strings.Replace(updated, "enum: [small, large]", "enum: [small, medium]", 1)
+	docChanges := compare(t, driftSpec, updated)
+
+	drift := DetectExampleDrift(docChanges, drDocument(t, driftSpec), drDocument(t, updated))
+	require.Len(t, drift, 2)
+
+	body := drift[0]
+	assert.Equal(t, FindingExampleDrift, body.Id)
+	assert.Equal(t, "$.paths['/burgers'].get.responses['200'].content['application/json'].examples['cheese']",
+		body.Example)
+	assert.Equal(t, "$.paths['/burgers'].get.responses['200'].content['application/json']", body.Path)
+	require.Len(t, body.Violations, 1)
+	assert.Equal(t, "value 'brioche' is not one of the allowed enum values", body.Violations[0].Message)
+	require.NotEmpty(t, body.Changes)
+	assert.Equal(t, "$.components.schemas['Bun']", body.Changes[0].Path)
+
+	param := drift[1]
+	assert.Equal(t, "$.paths['/burgers'].get.parameters[0].example", param.Example)
+	assert.Contains(t, param.Message, "value 'large' is not one of the allowed enum values")
+	require.NotEmpty(t, param.Changes)
+	for _, c := range param.Changes {
+		assert.NotEqual(t, "$.components.schemas['Bun']", c.Path)
+	}
+
+	// the schema example of Bun is still valid.
+	for _, d := range drift {
+		assert.NotContains(t, d.Example, "components")
+	}
+
+	// an example that is edited to be invalid is not drift.
+	edited := strings.Replace(driftSpec, "bun: brioche", "bun: potato", 1)
+	assert.Empty(t, DetectExampleDrift(compare(t, driftSpec, edited), drDocument(t, driftSpec), drDocument(t, edited)))
+	assert.Nil(t, DetectExampleDrift(nil, nil, nil))
+}
+
+func TestReport_ExampleDrift(t *testing.T) {
+	updated := strings.Replace(driftSpec, "enum: [brioche, sesame]", "enum: [sesame, potato]", 1)
+
+	md, err := Report([]byte(driftSpec), []byte(updated), FormatMarkdown, nil)
+	require.NoError(t, err)
+	assert.Contains(t, string(md), "\n## Example drift\n\n- `$.paths['/burgers'].get.responses['200']"+
+		".content['application/json'].examples['cheese']`\n  - value 'brioche' is not one of the allowed enum values")
+	assert.Contains(t, string(md), "  - caused by ")
+
+	page, err := Report([]byte(driftSpec), []byte(updated), FormatHTML, nil)
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "<section id=\"example-drift\">")
+
+	raw, err := Report([]byte(driftSpec), []byte(updated), FormatJSON, nil)
+	require.NoError(t, err)
+	var report jsonReport
+	require.NoError(t, json.Unmarshal(raw, &report))
+	require.Len(t, report.ExampleDrift, 1)
+	assert.NotEmpty(t, report.ExampleDrift[0].Changes)
+
+	// nothing drifted.
+	md, err = Report([]byte(driftSpec), []byte(driftSpec), FormatMarkdown, nil)
+	require.NoError(t, err)
+	assert.NotContains(t, string(md), "Example drift")
+}
@@ -0,0 +1,143 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"fmt"
+	drModel "github.com/pb33f/doctor/model"
+	drV3 "github.com/pb33f/doctor/model/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/pb33f/libopenapi/renderer"
+	"html"
+	"slices"
+	"strings"
+)
+
+// BodyExample is a generated example of a request or response body that changed.
+type BodyExample struct {
+	// Path is the JSONPath of the media type, for example `$.paths['/burgers'].post.requestBody.content['application/json']`.
+	Path        string
+	ContentType string
+
+	// Code is the response code of a response body, it is empty for a request body.
+	Code    string
+	Payload string
+}
+
+// GenerateExamples generates an example of every request and response body of the operation that changed, from
+// the schema of the body in the updated document. A body changed when a change was made inside its media type,
+// or to a component its schema uses. Examples are generated as YAML for YAML content types, and as JSON for every
+// other content type.
+func (r *OperationChangeReport) GenerateExamples(drDoc *drModel.DrDocument) error {
+	r.Examples = nil
+	if drDoc == nil {
+		return nil
+	}
+	var op *drModel.PathOperation
+	for _, po := range drDoc.Operations() {
+		if !po.Webhook && po.Path == r.Path && po.Method == r.Method {
+			op = po
+			break
+		}
+	}
+	if op == nil || op.Operation == nil {
+		return nil
+	}
+
+	var changed []string
+	for _, rc := range r.ReferencedChanges {
+		changed = append(changed, rc.Component)
+	}
+	jsonGenerator, yamlGenerator := renderer.NewMockGenerator(renderer.JSON), renderer.NewMockGenerator(renderer.YAML)
+	jsonGenerator.SetPretty()
+	generate := func(path, code string, content *orderedmap.Map[string, *drV3.MediaType]) error {
+		if content == nil {
+			return nil
+		}
+		for contentType, mt := range content.FromOldest() {
+			if mt == nil || mt.Value == nil || mt.Value.Schema == nil {
+				continue
+			}
+			mtPath := appendKey(path+".content", contentType)
+			if !r.bodyChanged(mtPath, mt, drDoc, changed) {
+				continue
+			}
+			generator := jsonGenerator
+			if strings.Contains(contentType, "yaml") {
+				generator = yamlGenerator
+			}
+			payload, err := generator.GenerateMock(mt.Value.Schema.Schema(), "")
+			if err != nil {
+				return fmt.Errorf("unable to generate example for '%s': %w", mtPath, err)
+			}
+			if len(payload) == 0 {
+				continue
+			}
+			r.Examples = append(r.Examples, &BodyExample{Path: mtPath, ContentType: contentType, Code: code,
+				Payload: strings.TrimSpace(string(payload))})
+		}
+		return nil
+	}
+
+	opPath := appendKey("$.paths", r.Path) + "." + r.Method
+	if rb := op.Operation.RequestBody; rb != nil {
+		if err := generate(opPath+".requestBody", "", rb.Content); err != nil {
+			return err
+		}
+	}
+	if responses := op.Operation.Responses; responses != nil {
+		if responses.Codes != nil {
+			for code, response := range responses.Codes.FromOldest() {
+				if err := generate(appendKey(opPath+".responses", code), code, response.Content); err != nil {
+					return err
+				}
+			}
+		}
+		if responses.Default != nil {
+			if err := generate(opPath+".responses.default", "default", responses.Default.Content); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bodyChanged returns true if a change was made inside a media type, or to a changed component it uses.
+func (r *OperationChangeReport) bodyChanged(path string, mt *drV3.MediaType, drDoc *drModel.DrDocument, changed []string) bool {
+	prefix := pathSegments(path)
+	for _, c := range r.Changes {
+		if pathContains(prefix, pathSegments(c.PropertyPath())) {
+			return true
+		}
+	}
+	if len(changed) == 0 || mt.Value.GoLow() == nil {
+		return false
+	}
+	return slices.ContainsFunc(drDoc.ReferencedComponents(mt.Value.GoLow().RootNode), func(def string) bool {
+		return slices.Contains(changed, def)
+	})
+}
+
+// title describes the body an example is for, code formats the response code, and text the content type.
+func (e *BodyExample) title(code, text func(string) string) string {
+	if e.Code == "" {
+		return fmt.Sprintf("Example request body (%s)", text(e.ContentType))
+	}
+	return fmt.Sprintf("Example %s response body (%s)", code(e.Code), text(e.ContentType))
+}
+
+func (e *BodyExample) markdown() string {
+	lang := "json"
+	if strings.Contains(e.ContentType, "yaml") {
+		lang = "yaml"
+	}
+	title := e.title(func(code string) string { return "`" + code + "`" }, func(text string) string { return text })
+	return fmt.Sprintf("\n**%s**\n\n```%s\n%s\n```\n", title, lang, e.Payload)
+}
+
+func (e *BodyExample) html() string {
+	title := e.title(func(code string) string { return "<code>" + html.EscapeString(code) + "</code>" },
+		html.EscapeString)
+	return fmt.Sprintf("<p><strong>%s</strong></p>\n<pre><code>%s</code></pre>\n", title, html.EscapeString(e.Payload))
+}
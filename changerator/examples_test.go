@@ -0,0 +1,97 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const exampleSpec = `openapi: 3.1.0
+info:
+  title: burgers
+  version: 1.0.0
+paths:
+  /burgers:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Burger'
+      responses:
+        "201":
+          description: created
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: string
+                    example: abc
+        "400":
+          description: bad request
+          content:
+            application/yaml:
+              schema:
+                type: object
+                properties:
+                  message:
+                    type: string
+components:
+  schemas:
+    Burger:
+      type: object
+      properties:
+        name:
+          type: string
+          example: big mac`
+
+func TestOperationChangeReport_GenerateExamples(t *testing.T) {
+	updated := strings.Replace(exampleSpec, "example: big mac", "example: big mac\n        patties:\n          type: integer\n          example: 2", 1)
+	updated = strings.Replace(updated, "example: abc", "example: abc\n                  cooked:\n                    type: boolean\n                    example: true", 1)
+	changes := compare(t, exampleSpec, updated)
+	drDoc := drDocument(t, updated)
+
+	report := ExtractOperationChanges(changes, drDoc, "/burgers", "post")
+	require.NoError(t, report.GenerateExamples(drDoc))
+	require.Len(t, report.Examples, 2)
+
+	request := report.Examples[0]
+	assert.Equal(t, "$.paths['/burgers'].post.requestBody.content['application/json']", request.Path)
+	assert.Empty(t, request.Code)
+	assert.Contains(t, request.Payload, `"patties": 2`)
+
+	response := report.Examples[1]
+	assert.Equal(t, "201", response.Code)
+	assert.Contains(t, response.Payload, `"cooked": true`)
+
+	md := report.Markdown()
+	assert.Contains(t, md, "**Example request body (application/json)**\n\n```json\n{")
+	assert.Contains(t, md, "**Example `201` response body (application/json)**")
+	assert.NotContains(t, md, "`400`")
+
+	assert.NoError(t, report.GenerateExamples(nil))
+	assert.Empty(t, report.Examples)
+}
+
+func TestReport_Examples(t *testing.T) {
+	updated := strings.Replace(exampleSpec, "message:\n                    type: string", "message:\n                    type: string\n                    example: nope", 1)
+
+	md, err := Report([]byte(exampleSpec), []byte(updated), FormatMarkdown, &RenderConfig{Examples: true})
+	require.NoError(t, err)
+	assert.Contains(t, string(md), "**Example `400` response body (application/yaml)**\n\n```yaml\nmessage: nope\n```\n")
+
+	h, err := Report([]byte(exampleSpec), []byte(updated), FormatHTML, &RenderConfig{Examples: true})
+	require.NoError(t, err)
+	assert.Contains(t, string(h), "<p><strong>Example <code>400</code> response body (application/yaml)</strong></p>\n"+
+		"<pre><code>message: nope</code></pre>")
+
+	md, err = Report([]byte(exampleSpec), []byte(updated), FormatMarkdown, nil)
+	require.NoError(t, err)
+	assert.NotContains(t, string(md), "Example")
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/datamodel/high"
+	"github.com/pb33f/libopenapi/index"
+	"github.com/pb33f/libopenapi/what-changed/model"
+	"path/filepath"
+	"reflect"
+)
+
+// File returns the file of a multi-file specification a change was made in, relative to the directory of the
+// root document (the root document is its own file name). The file is found through the low level object the
+// change was made to (the original object for removals), which knows the index (rolodex file) it was built from,
+// and falls back to the object at the path of the change, then to the root document.
+func (a *Attachments) File(c *LocatedChange) string {
+	if a == nil || c == nil || c.Change == nil {
+		return ""
+	}
+	objects := []any{c.Change.NewObject, c.Change.OriginalObject}
+	if c.Change.ChangeType == model.ObjectRemoved || c.Change.ChangeType == model.PropertyRemoved {
+		objects = []any{c.Change.OriginalObject, c.Change.NewObject}
+	}
+	for _, o := range objects {
+		if file := indexFile(o); file != "" {
+			return file
+		}
+	}
+	if a.drDoc == nil {
+		return ""
+	}
+	if f := a.objectAtPath(c.Path); f != nil {
+		if hv, ok := f.(drBase.HasValue); ok {
+			if gl, ok := hv.GetValue().(high.GoesLowUntyped); ok {
+				if file := indexFile(gl.GoLowUntyped()); file != "" {
+					return file
+				}
+			}
+		}
+	}
+	return relativeFile(a.drDoc.GetIndex())
+}
+
+// indexFile returns the file of the index a low level object was built from. Changes to objects that were added
+// or removed hold a nil object for the version the object is missing from.
+func indexFile(o any) string {
+	hi, ok := o.(drBase.HasIndex)
+	if !ok || reflect.ValueOf(o).Kind() == reflect.Pointer && reflect.ValueOf(o).IsNil() {
+		return ""
+	}
+	return relativeFile(hi.GetIndex())
+}
+
+// relativeFile returns the file of an index, relative to the directory of the root document of its rolodex.
+func relativeFile(idx *index.SpecIndex) string {
+	if idx == nil || idx.GetSpecAbsolutePath() == "" {
+		return ""
+	}
+	file := idx.GetSpecAbsolutePath()
+	root := file
+	if rolodex := idx.GetRolodex(); rolodex != nil && rolodex.GetRootIndex() != nil &&
+		rolodex.GetRootIndex().GetSpecAbsolutePath() != "" {
+		root = rolodex.GetRootIndex().GetSpecAbsolutePath()
+	}
+	if rel, err := filepath.Rel(filepath.Dir(root), file); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return file
+}
+
+// annotateFiles sets the file of every change, when the changes were made in more than one file, so single file
+// specifications are rendered as they always were. Changes are located more than once while building a report,
+// every located copy of a change is annotated.
+func annotateFiles(a *Attachments, changes ...[]*LocatedChange) {
+	files := make(map[*model.Change]string)
+	distinct := make(map[string]bool)
+	for _, list := range changes {
+		for _, c := range list {
+			if _, ok := files[c.Change]; !ok {
+				files[c.Change] = a.File(c)
+				distinct[files[c.Change]] = true
+			}
+		}
+	}
+	if len(distinct) < 2 {
+		return
+	}
+	for _, list := range changes {
+		for _, c := range list {
+			c.File = files[c.Change]
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"encoding/json"
+	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var filesRoot = `openapi: 3.1.0
+info:
+  title: burgers
+  version: 1.0.0
+paths:
+  /burgers:
+    get:
+      tags: [burgers]
+      description: list burgers
+      responses:
+        "200":
+          $ref: 'responses/burgers.yaml'`
+
+var filesBurger = `description: ok
+content:
+  application/json:
+    schema:
+      type: object
+      properties:
+        name:
+          type: string`
+
+// writeVersion writes a version of the multi-file specification to its own directory.
+func writeVersion(t *testing.T, root, burger string) (*datamodel.DocumentConfiguration, []byte) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "responses"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "openapi.yaml"), []byte(root), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "responses", "burgers.yaml"), []byte(burger), 0o644))
+	return &datamodel.DocumentConfiguration{BasePath: dir, SpecFilePath: "openapi.yaml",
+		AllowFileReferences: true}, []byte(root)
+}
+
+func TestReport_Files(t *testing.T) {
+	leftConfig, left := writeVersion(t, filesRoot, filesBurger)
+	rightConfig, right := writeVersion(t, strings.Replace(filesRoot, "list burgers", "list all burgers", 1),
+		strings.Replace(filesBurger, "type: string", "type: integer", 1))
+	cfg := &RenderConfig{DocumentConfiguration: rightConfig, OriginalDocumentConfiguration: leftConfig}
+
+	md, err := Report(left, right, FormatMarkdown, cfg)
+	require.NoError(t, err)
+	report := string(md)
+	assert.Contains(t, report, "`$.paths['/burgers'].get.description` in `openapi.yaml` | `list burgers` |")
+	assert.Contains(t, report, "in `responses/burgers.yaml` | `string` | `integer` | yes |")
+
+	raw, err := Report(left, right, FormatJSON, cfg)
+	require.NoError(t, err)
+	var decoded jsonReport
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+	var files []string
+	for _, c := range decoded.Changes {
+		files = append(files, c.File)
+	}
+	assert.ElementsMatch(t, []string{"openapi.yaml", "responses/burgers.yaml"}, files)
+
+	cfg.Group = GroupConfig{By: GroupByFile}
+	md, err = Report(left, right, FormatMarkdown, cfg)
+	require.NoError(t, err)
+	report = string(md)
+	assert.Contains(t, report, "## Contents\n\n- [openapi.yaml](#group-openapi-yaml): 1 changes, 0 breaking\n"+
+		"- [responses/burgers.yaml](#group-responses-burgers-yaml): 1 changes, 1 breaking\n")
+
+	page, err := Report(left, right, FormatHTML, cfg)
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "<section id=\"group-responses-burgers-yaml\">")
+	assert.Contains(t, string(page), " in <code>responses/burgers.yaml</code>")
+}
+
+func TestReport_SingleFile(t *testing.T) {
+	// changes to a single file are not annotated.
+	updated := strings.Replace(teamSpec, "description: orders", "description: all the orders", 1)
+	md, err := Report([]byte(teamSpec), []byte(updated), FormatMarkdown, &RenderConfig{Group: GroupConfig{By: GroupByFile}})
+	require.NoError(t, err)
+	assert.Contains(t, string(md), ".description` | `orders` | `all the orders` |")
+	assert.NotContains(t, string(md), " in `")
+}
@@ -0,0 +1,154 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"fmt"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/what-changed/model"
+	"html"
+	"strings"
+)
+
+// changedFinding is a finding attached to an object that changed, and the changes made to the object.
+type changedFinding struct {
+	finding *drBase.Finding
+	changes []*LocatedChange
+}
+
+// changedFindings returns the findings attached to the objects changes were made to (see Attachments.Object and
+// DrDocument.AttachFindings), in the order the changes are located. Each finding is returned once.
+func changedFindings(located []*LocatedChange, attached *Attachments) []*changedFinding {
+	var found []*changedFinding
+	byFinding := make(map[*drBase.Finding]*changedFinding)
+	for _, c := range located {
+		f := attached.Object(c)
+		if f == nil {
+			continue
+		}
+		for _, finding := range drBase.FindingsOf(f) {
+			cf, ok := byFinding[finding]
+			if !ok {
+				cf = &changedFinding{finding: finding}
+				byFinding[finding] = cf
+				found = append(found, cf)
+			}
+			cf.changes = append(cf.changes, c)
+		}
+	}
+	return found
+}
+
+// markdown renders the finding as a list item, with the changes made to its object.
+func (cf *changedFinding) markdown() string {
+	buf := strings.Builder{}
+	f := cf.finding
+	buf.WriteString(fmt.Sprintf("- **%s** `%s` on `%s`: %s\n", f.Severity, f.Id, f.Path, f.Message))
+	for _, c := range cf.changes {
+		buf.WriteString(fmt.Sprintf("  - changed by %s `%s`\n", ChangeTypeName(c.Change.ChangeType), c.PropertyPath()))
+	}
+	return buf.String()
+}
+
+// html renders the finding as a list item, with links to the changes made to its object.
+func (cf *changedFinding) html() string {
+	buf := strings.Builder{}
+	f := cf.finding
+	buf.WriteString(fmt.Sprintf("<li><strong>%s</strong> <code>%s</code> on <code>%s</code>: %s\n<ul>\n",
+		html.EscapeString(f.Severity), html.EscapeString(f.Id), html.EscapeString(f.Path), html.EscapeString(f.Message)))
+	for _, c := range cf.changes {
+		buf.WriteString(fmt.Sprintf("<li>changed by <a href=\"#%s\">%s <code>%s</code></a></li>\n", c.Anchor(),
+			ChangeTypeName(c.Change.ChangeType), html.EscapeString(c.PropertyPath())))
+	}
+	buf.WriteString("</ul>\n</li>\n")
+	return buf.String()
+}
+
+// changeFindings returns the findings on the object a change was made to. An object that was added is new to
+// the specification, so the findings on every object beneath it are included too.
+func changeFindings(c *LocatedChange, attached *Attachments) []*drBase.Finding {
+	f := attached.Object(c)
+	if f == nil {
+		return nil
+	}
+	findings := drBase.FindingsOf(f)
+	added := c.Change.ChangeType == model.ObjectAdded || c.Change.ChangeType == model.PropertyAdded
+	if !added || f.GenerateJSONPath() != c.PropertyPath() {
+		return findings
+	}
+	seen := map[drBase.Foundational]bool{f: true}
+	var collect func(children []drBase.Foundational)
+	collect = func(children []drBase.Foundational) {
+		for _, child := range children {
+			if seen[child] {
+				continue
+			}
+			seen[child] = true
+			findings = append(findings, drBase.FindingsOf(child)...)
+			collect(drBase.ChildrenOf(child))
+		}
+	}
+	collect(drBase.ChildrenOf(f))
+	return findings
+}
+
+// interleaveFindings sets the findings of every change (every located copy of a change is set), and returns the
+// findings that were set on a change.
+func interleaveFindings(attached *Attachments, changes ...[]*LocatedChange) map[*drBase.Finding]bool {
+	interleaved := make(map[*drBase.Finding]bool)
+	byChange := make(map[*model.Change][]*drBase.Finding)
+	for _, list := range changes {
+		for _, c := range list {
+			findings, ok := byChange[c.Change]
+			if !ok {
+				findings = changeFindings(c, attached)
+				byChange[c.Change] = findings
+				for _, f := range findings {
+					interleaved[f] = true
+				}
+			}
+			c.Findings = findings
+		}
+	}
+	return interleaved
+}
+
+// markdownFindingRows renders the findings of a change as table rows beneath it.
+func markdownFindingRows(c *LocatedChange) string {
+	buf := strings.Builder{}
+	for _, f := range c.Findings {
+		buf.WriteString(fmt.Sprintf("| ↳ %s | `%s` | `%s` | %s |  |\n", f.Severity, f.Path, f.Id,
+			markdownCell(f.Message)))
+	}
+	return buf.String()
+}
+
+// htmlFindingRows renders the findings of a change as table rows beneath it.
+func htmlFindingRows(c *LocatedChange) string {
+	buf := strings.Builder{}
+	for _, f := range c.Findings {
+		buf.WriteString(fmt.Sprintf("<tr class=\"finding\"><td>&#8627; %s</td><td><code>%s</code></td>"+
+			"<td><code>%s</code></td><td>%s</td><td></td></tr>\n", html.EscapeString(f.Severity),
+			html.EscapeString(f.Path), html.EscapeString(f.Id), html.EscapeString(f.Message)))
+	}
+	return buf.String()
+}
+
+// markdownFinding renders a finding that is not interleaved with a change as a list item.
+func markdownFinding(f *drBase.Finding) string {
+	if f.Path == "" {
+		return fmt.Sprintf("- **%s** `%s`: %s\n", f.Severity, f.Id, f.Message)
+	}
+	return fmt.Sprintf("- **%s** `%s` on `%s`: %s\n", f.Severity, f.Id, f.Path, f.Message)
+}
+
+// htmlFinding renders a finding that is not interleaved with a change as a list item.
+func htmlFinding(f *drBase.Finding) string {
+	on := ""
+	if f.Path != "" {
+		on = " on <code>" + html.EscapeString(f.Path) + "</code>"
+	}
+	return fmt.Sprintf("<li><strong>%s</strong> <code>%s</code>%s: %s</li>\n", html.EscapeString(f.Severity),
+		html.EscapeString(f.Id), on, html.EscapeString(f.Message))
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"encoding/json"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestReport_Findings(t *testing.T) {
+	updated := strings.Replace(teamSpec, "description: orders", "description: all the orders", 1)
+	findings := func() []*drBase.Finding {
+		return []*drBase.Finding{
+			{Id: "response-examples", Severity: drBase.SeverityWarn, Message: "add an example",
+				Path: "$.paths['/orders'].get.responses['200']"},
+			{Id: "status-summary", Severity: drBase.SeverityInfo, Message: "add a summary",
+				Path: "$.paths['/status'].get"},
+		}
+	}
+
+	md, err := Report([]byte(teamSpec), []byte(updated), FormatMarkdown, &RenderConfig{Findings: findings()})
+	require.NoError(t, err)
+	assert.Contains(t, string(md), "\n## Findings\n\n- **warn** `response-examples` on "+
+		"`$.paths['/orders'].get.responses['200']`: add an example\n"+
+		"  - changed by modified `$.paths['/orders'].get.responses['200'].description`\n")
+	// findings on objects that did not change are not reported.
+	assert.NotContains(t, string(md), "status-summary")
+
+	page, err := Report([]byte(teamSpec), []byte(updated), FormatHTML, &RenderConfig{Findings: findings()})
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "<section id=\"findings\">")
+
+	raw, err := Report([]byte(teamSpec), []byte(updated), FormatJSON, &RenderConfig{Findings: findings()})
+	require.NoError(t, err)
+	var report jsonReport
+	require.NoError(t, json.Unmarshal(raw, &report))
+	require.Len(t, report.Changes, 1)
+	require.Len(t, report.Changes[0].Findings, 1)
+	assert.Equal(t, "response-examples", report.Changes[0].Findings[0].Id)
+
+	md, err = Report([]byte(teamSpec), []byte(updated), FormatMarkdown, nil)
+	require.NoError(t, err)
+	assert.NotContains(t, string(md), "## Findings")
+}
+
+func TestReport_InterleaveFindings(t *testing.T) {
+	updated := strings.Replace(teamSpec, "description: orders", "description: all the orders", 1)
+	updated = strings.Replace(updated, "  /status:\n", `  /status:
+    post:
+      responses:
+        "201":
+          description: created
+`, 1)
+	findings := func() []*drBase.Finding {
+		return []*drBase.Finding{
+			{Id: "response-examples", Severity: drBase.SeverityWarn, Message: "add an example",
+				Path: "$.paths['/orders'].get.responses['200']"},
+			{Id: "operation-id", Severity: drBase.SeverityError, Message: "missing operationId",
+				Path: "$.paths['/status'].post.responses['201']"},
+			{Id: "status-summary", Severity: drBase.SeverityInfo, Message: "add a summary",
+				Path: "$.paths['/status'].get"},
+			{Id: "nowhere", Severity: drBase.SeverityInfo, Message: "not in the spec", Path: "$.paths['/pizza']"},
+		}
+	}
+	cfg := &RenderConfig{InterleaveFindings: true}
+
+	cfg.Findings = findings()
+	md, err := Report([]byte(teamSpec), []byte(updated), FormatMarkdown, cfg)
+	require.NoError(t, err)
+	report := string(md)
+	assert.Contains(t, report, "| `$.paths['/orders'].get.responses['200'].description` | `orders` | `all the orders` |  |\n"+
+		"| ↳ warn | `$.paths['/orders'].get.responses['200']` | `response-examples` | add an example |  |\n")
+	// the added operation includes the findings beneath it.
+	assert.Contains(t, report, "| ↳ error | `$.paths['/status'].post.responses['201']` | `operation-id` | "+
+		"missing operationId |  |\n")
+	assert.Contains(t, report, "\n## Other findings\n\n- **info** `status-summary` on `$.paths['/status'].get`: "+
+		"add a summary\n- **info** `nowhere` on `$.paths['/pizza']`: not in the spec\n")
+	assert.NotContains(t, report, "\n## Findings\n")
+
+	cfg.Findings = findings()
+	page, err := Report([]byte(teamSpec), []byte(updated), FormatHTML, cfg)
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "<tr class=\"finding\"><td>&#8627; warn</td>"+
+		"<td><code>$.paths[&#39;/orders&#39;].get.responses[&#39;200&#39;]</code></td>")
+	assert.Contains(t, string(page), "<section id=\"other-findings\">")
+}
@@ -0,0 +1,137 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package changerator evaluates and reports on the changes between two versions of an OpenAPI document,
+// as computed by the libopenapi what-changed module.
+package changerator
+
+import (
+	"fmt"
+	"github.com/pb33f/libopenapi/what-changed/model"
+	"slices"
+	"time"
+)
+
+// Unlimited can be used for GateConfig.MaxBreaking or GateConfig.MaxTotal to disable the threshold.
+// A zero MaxTotal is unlimited as well.
+const Unlimited = -1
+
+// GateConfig is the set of thresholds a set of changes must meet to pass the gate.
+type GateConfig struct {
+	// MaxBreaking is the number of breaking changes allowed, zero fails on any breaking change.
+	MaxBreaking int
+
+	// MaxTotal is the number of changes allowed, zero (or Unlimited) allows any number of changes. Use
+	// ForbiddenChangeTypes, or a MaxBreaking of zero, to fail on specific kinds of change instead.
+	MaxTotal int
+
+	// ForbiddenChangeTypes are what-changed change types (model.Modified, model.PropertyAdded, model.ObjectAdded,
+	// model.ObjectRemoved or model.PropertyRemoved) that fail the gate regardless of the thresholds.
+	ForbiddenChangeTypes []int
+
+	// Suppressions acknowledge known changes, acknowledged changes are not counted and never violate the gate.
+	Suppressions *SuppressionList
+
+	// Contract limits the gate to the changes that intersect a consumer contract, so the gate fails only on
+	// what would break that consumer. Expand the contract with the original document first (see
+	// ConsumerContract.Expand), for changes to the components its operations use to be counted.
+	Contract *ConsumerContract
+}
+
+// GateViolation is a change that caused the gate to fail, and the reason it failed.
+type GateViolation struct {
+	Change *model.Change `json:"change" yaml:"change"`
+	Reason string        `json:"reason" yaml:"reason"`
+}
+
+// GateResult is the outcome of evaluating a gate.
+type GateResult struct {
+	Passed          bool             `json:"passed" yaml:"passed"`
+	TotalChanges    int              `json:"totalChanges" yaml:"totalChanges"`
+	BreakingChanges int              `json:"breakingChanges" yaml:"breakingChanges"`
+	Reasons         []string         `json:"reasons,omitempty" yaml:"reasons,omitempty"`
+	Violations      []*GateViolation `json:"violations,omitempty" yaml:"violations,omitempty"`
+
+	// Acknowledged are the changes that matched a suppression.
+	Acknowledged []*AcknowledgedChange `json:"acknowledged,omitempty" yaml:"acknowledged,omitempty"`
+
+	// OutsideContract is the number of changes that did not intersect the consumer contract of the gate.
+	OutsideContract int `json:"outsideContract,omitempty" yaml:"outsideContract,omitempty"`
+}
+
+// ExitCode returns 0 when the gate passed, and 1 when it failed, for use as a process exit code.
+func (r *GateResult) ExitCode() int {
+	if r.Passed {
+		return 0
+	}
+	return 1
+}
+
+// EvaluateGate checks document changes against a gate configuration, returning pass or fail and the changes that
+// violate it. Nil document changes (no changes found) always pass. Changes acknowledged by the configured
+// suppressions are returned separately, and do not count towards the thresholds. Changes outside the contract of
+// the configuration, when it has one, are not counted either.
+func EvaluateGate(docChanges *model.DocumentChanges, config GateConfig) *GateResult {
+	result := &GateResult{Passed: true}
+	if docChanges == nil {
+		return result
+	}
+	var changes []*model.Change
+	now := time.Now()
+	for _, c := range LocateChanges(docChanges) {
+		if config.Contract != nil && !config.Contract.Covers(c) {
+			result.OutsideContract++
+			continue
+		}
+		if s := config.Suppressions.Match(c, now); s != nil {
+			result.Acknowledged = append(result.Acknowledged, &AcknowledgedChange{LocatedChange: c, Suppression: s})
+			continue
+		}
+		changes = append(changes, c.Change)
+	}
+	result.TotalChanges = len(changes)
+	result.BreakingChanges = model.CountBreakingChanges(changes)
+
+	if config.MaxBreaking >= 0 && result.BreakingChanges > config.MaxBreaking {
+		result.Passed = false
+		reason := fmt.Sprintf("%d breaking changes found, the maximum allowed is %d", result.BreakingChanges, config.MaxBreaking)
+		result.Reasons = append(result.Reasons, reason)
+		for _, c := range changes {
+			if c.Breaking {
+				result.Violations = append(result.Violations, &GateViolation{Change: c, Reason: "breaking change"})
+			}
+		}
+	}
+	if config.MaxTotal > 0 && result.TotalChanges > config.MaxTotal {
+		result.Passed = false
+		result.Reasons = append(result.Reasons,
+			fmt.Sprintf("%d changes found, the maximum allowed is %d", result.TotalChanges, config.MaxTotal))
+	}
+	if len(config.ForbiddenChangeTypes) > 0 {
+		for _, c := range changes {
+			if slices.Contains(config.ForbiddenChangeTypes, c.ChangeType) {
+				result.Passed = false
+				result.Violations = append(result.Violations,
+					&GateViolation{Change: c, Reason: fmt.Sprintf("change type '%s' is forbidden", ChangeTypeName(c.ChangeType))})
+			}
+		}
+	}
+	return result
+}
+
+// ChangeTypeName returns the name of a what-changed change type, as used when changes are rendered as JSON.
+func ChangeTypeName(changeType int) string {
+	switch changeType {
+	case model.Modified:
+		return "modified"
+	case model.PropertyAdded:
+		return "property_added"
+	case model.ObjectAdded:
+		return "object_added"
+	case model.ObjectRemoved:
+		return "object_removed"
+	case model.PropertyRemoved:
+		return "property_removed"
+	}
+	return "unknown"
+}
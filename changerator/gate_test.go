@@ -0,0 +1,125 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"github.com/pb33f/libopenapi"
+	whatChanged "github.com/pb33f/libopenapi/what-changed"
+	"github.com/pb33f/libopenapi/what-changed/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const leftSpec = `openapi: 3.1.0
+info:
+  title: burgers
+  version: 1.0.0
+paths:
+  /burgers:
+    get:
+      operationId: listBurgers
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: burgers
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Burger'
+components:
+  schemas:
+    Burger:
+      type: object
+      properties:
+        name:
+          type: string
+        patties:
+          type: integer`
+
+const rightSpec = `openapi: 3.1.0
+info:
+  title: burgers
+  version: 1.1.0
+paths:
+  /burgers:
+    get:
+      operationId: listBurgers
+      responses:
+        "200":
+          description: burgers
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Burger'
+components:
+  schemas:
+    Burger:
+      type: object
+      properties:
+        name:
+          type: string`
+
+func compare(t *testing.T, left, right string) *model.DocumentChanges {
+	l, err := libopenapi.NewDocument([]byte(left))
+	require.NoError(t, err)
+	r, err := libopenapi.NewDocument([]byte(right))
+	require.NoError(t, err)
+	lm, _ := l.BuildV3Model()
+	rm, _ := r.BuildV3Model()
+	return whatChanged.CompareOpenAPIDocuments(lm.Model.GoLow(), rm.Model.GoLow())
+}
+
+func TestEvaluateGate(t *testing.T) {
+	changes := compare(t, leftSpec, rightSpec)
+	require.NotNil(t, changes)
+
+	result := EvaluateGate(changes, GateConfig{MaxBreaking: Unlimited, MaxTotal: Unlimited})
+	assert.True(t, result.Passed)
+	assert.Equal(t, 0, result.ExitCode())
+	assert.Equal(t, 3, result.TotalChanges)
+	assert.Equal(t, 2, result.BreakingChanges)
+
+	result = EvaluateGate(changes, GateConfig{MaxBreaking: 1, MaxTotal: Unlimited})
+	assert.False(t, result.Passed)
+	assert.Equal(t, 1, result.ExitCode())
+	assert.Len(t, result.Violations, 2)
+	assert.Equal(t, []string{"2 breaking changes found, the maximum allowed is 1"}, result.Reasons)
+
+	result = EvaluateGate(changes, GateConfig{MaxBreaking: Unlimited, MaxTotal: 2})
+	assert.False(t, result.Passed)
+	assert.Empty(t, result.Violations)
+
+	result = EvaluateGate(changes, GateConfig{MaxBreaking: Unlimited, MaxTotal: Unlimited,
+		ForbiddenChangeTypes: []int{model.Modified}})
+	assert.False(t, result.Passed)
+	require.Len(t, result.Violations, 1)
+	assert.Equal(t, "version", result.Violations[0].Change.Property)
+	assert.Equal(t, "change type 'modified' is forbidden", result.Violations[0].Reason)
+}
+
+func TestEvaluateGate_ZeroValue(t *testing.T) {
+	versionOnly := strings.Replace(leftSpec, "version: 1.0.0", "version: 1.0.1", 1)
+
+	// a zero MaxTotal is unlimited, so non-breaking changes pass a gate that only forbids breaking ones.
+	result := EvaluateGate(compare(t, leftSpec, versionOnly), GateConfig{MaxBreaking: 0})
+	assert.True(t, result.Passed)
+	assert.Equal(t, 1, result.TotalChanges)
+	assert.Empty(t, result.Reasons)
+
+	result = EvaluateGate(compare(t, leftSpec, rightSpec), GateConfig{})
+	assert.False(t, result.Passed)
+	assert.Equal(t, []string{"2 breaking changes found, the maximum allowed is 0"}, result.Reasons)
+}
+
+func TestEvaluateGate_NoChanges(t *testing.T) {
+	result := EvaluateGate(compare(t, leftSpec, leftSpec), GateConfig{})
+	assert.True(t, result.Passed)
+	assert.Equal(t, 0, result.TotalChanges)
+}
@@ -0,0 +1,370 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"fmt"
+	drModel "github.com/pb33f/doctor/model"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/pb33f/libopenapi/what-changed/model"
+	"gopkg.in/yaml.v3"
+	"html"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	// GroupByTag groups operation changes by the tags of the operation.
+	GroupByTag = "tag"
+
+	// GroupByOwner groups operation changes by an owner extension (`x-owner` by default).
+	GroupByOwner = "owner"
+
+	// GroupByFile groups operation changes by the file of a multi-file specification they were made in (see
+	// Attachments.File), an operation is in the group of every file it has changes in, with only those changes.
+	GroupByFile = "file"
+)
+
+// DefaultOwnerExtension is the extension used to find the owner of an operation.
+const DefaultOwnerExtension = "x-owner"
+
+// Unowned is the group for operations that have no tag or owner.
+const Unowned = "unowned"
+
+// GroupConfig controls how operation changes are grouped.
+type GroupConfig struct {
+	// By is GroupByTag (the default), GroupByOwner or GroupByFile.
+	By string
+
+	// OwnerExtension is the extension that names the owner of an operation, when grouping by owner. The extension
+	// can be a string or a list of strings, and is read from the operation, then the path item, then the info
+	// object of the document. Defaults to DefaultOwnerExtension.
+	OwnerExtension string
+
+	// Methods controls which operations are grouped, and the order the operations of a path are listed in each
+	// group. Every operation is grouped in the order it is declared when nil.
+	Methods *drBase.MethodOrder
+}
+
+// ChangeGroup is a team (a tag or an owner), and the changes to the operations it is responsible for. When
+// grouping by file, the group is a file and the changes made in it.
+type ChangeGroup struct {
+	Name       string
+	Operations []*OperationChangeReport
+}
+
+// ChangeGroups is every group with changed operations, sorted by name, Unowned is last.
+type ChangeGroups struct {
+	Groups []*ChangeGroup
+}
+
+// GroupSummary is the structured summary of a group, for routing notifications to a team.
+type GroupSummary struct {
+	Operations      []string `json:"operations" yaml:"operations"`
+	TotalChanges    int      `json:"totalChanges" yaml:"totalChanges"`
+	BreakingChanges int      `json:"breakingChanges" yaml:"breakingChanges"`
+}
+
+// GroupChanges buckets the changes of every operation in the updated document by the team responsible for it,
+// so each team can see (or be told about) the changes to their endpoints. An operation with more than one tag
+// or owner is included in every group. Changes to referenced components are included with each operation that
+// uses them.
+//
+// Operations that were removed no longer exist in the updated document, they are not grouped.
+func GroupChanges(docChanges *model.DocumentChanges, drDoc *drModel.DrDocument, config GroupConfig) *ChangeGroups {
+	groups := &ChangeGroups{}
+	if docChanges == nil || drDoc == nil {
+		return groups
+	}
+	ext := config.OwnerExtension
+	if ext == "" {
+		ext = DefaultOwnerExtension
+	}
+	byName := make(map[string]*ChangeGroup)
+	add := func(name string, report *OperationChangeReport) {
+		g, ok := byName[name]
+		if !ok {
+			g = &ChangeGroup{Name: name}
+			byName[name] = g
+			groups.Groups = append(groups.Groups, g)
+		}
+		if !slices.Contains(g.Operations, report) {
+			g.Operations = append(g.Operations, report)
+		}
+	}
+	files := &Attachments{drDoc: drDoc}
+	for _, po := range drBase.OrderMethods(config.Methods, drDoc.Operations(), operationKey) {
+		if po.Webhook {
+			continue
+		}
+		report := ExtractOperationChanges(docChanges, drDoc, po.Path, po.Method)
+		if report.TotalChanges() == 0 {
+			continue
+		}
+		if config.By == GroupByFile {
+			for _, fr := range report.splitByFile(files) {
+				add(fr.File, fr.Report)
+			}
+			continue
+		}
+		var names []string
+		if config.By == GroupByOwner {
+			names = extensionValues(po.Operation.Value.Extensions, ext)
+			if len(names) == 0 {
+				names = extensionValues(po.PathItem.Value.Extensions, ext)
+			}
+			if len(names) == 0 && drDoc.V3Document.Info != nil {
+				names = extensionValues(drDoc.V3Document.Info.Value.Extensions, ext)
+			}
+		} else {
+			names = po.Operation.Value.Tags
+		}
+		if len(names) == 0 {
+			names = []string{Unowned}
+		}
+		for _, name := range names {
+			add(name, report)
+		}
+	}
+	sort.SliceStable(groups.Groups, func(i, j int) bool {
+		a, b := groups.Groups[i].Name, groups.Groups[j].Name
+		if a == Unowned || b == Unowned {
+			return b == Unowned && a != Unowned
+		}
+		return a < b
+	})
+	return groups
+}
+
+// Group returns a group by name, or nil if the group has no changes.
+func (g *ChangeGroups) Group(name string) *ChangeGroup {
+	for _, group := range g.Groups {
+		if group.Name == name {
+			return group
+		}
+	}
+	return nil
+}
+
+// Summary returns a map of group names to the operations that changed, and how many changes they have.
+func (g *ChangeGroups) Summary() map[string]*GroupSummary {
+	summary := make(map[string]*GroupSummary)
+	for _, group := range g.Groups {
+		s := &GroupSummary{}
+		for _, op := range group.Operations {
+			s.Operations = append(s.Operations, fmt.Sprintf("%s %s", strings.ToUpper(op.Method), op.Path))
+		}
+		s.TotalChanges, s.BreakingChanges = group.counts()
+		summary[group.Name] = s
+	}
+	return summary
+}
+
+// Markdown renders a table of contents, and a section for every group containing the changes to each of its
+// operations. Sections are anchored (see groupAnchor), and changes are tagged with their anchors.
+func (g *ChangeGroups) Markdown() string {
+	buf := strings.Builder{}
+	ids := anchors{}
+	buf.WriteString("## Contents\n\n")
+	for _, group := range g.Groups {
+		total, breaking := group.counts()
+		buf.WriteString(fmt.Sprintf("- [%s](#%s): %d changes, %d breaking\n", group.Name, groupAnchor(group.Name),
+			total, breaking))
+	}
+	for _, group := range g.Groups {
+		buf.WriteString(fmt.Sprintf("\n<a id=\"%s\"></a>\n\n## %s\n", groupAnchor(group.Name), group.Name))
+		for _, op := range group.Operations {
+			buf.WriteString("\n")
+			// operation reports are page sections, nest them beneath the group.
+			for _, line := range strings.SplitAfter(op.markdown(ids), "\n") {
+				if strings.HasPrefix(line, "#") {
+					buf.WriteString("#")
+				}
+				buf.WriteString(line)
+			}
+		}
+	}
+	return buf.String()
+}
+
+// HTML renders a table of contents, and a section for every group containing a table of the changes to each of
+// its operations. Every change row has its anchor as an id.
+func (g *ChangeGroups) HTML() string {
+	buf := strings.Builder{}
+	ids := anchors{}
+	buf.WriteString("<nav>\n<h2>Contents</h2>\n<ul>\n")
+	for _, group := range g.Groups {
+		total, breaking := group.counts()
+		buf.WriteString(fmt.Sprintf("<li><a href=\"#%s\">%s</a> (%d changes, %d breaking)</li>\n",
+			html.EscapeString(groupAnchor(group.Name)), html.EscapeString(group.Name), total, breaking))
+	}
+	buf.WriteString("</ul>\n</nav>\n")
+	for _, group := range g.Groups {
+		buf.WriteString(fmt.Sprintf("<section id=\"%s\">\n<h2>%s</h2>\n", html.EscapeString(groupAnchor(group.Name)),
+			html.EscapeString(group.Name)))
+		for _, op := range group.Operations {
+			buf.WriteString(fmt.Sprintf("<h3><code>%s %s</code></h3>\n<p>%d changes, %d breaking.</p>\n",
+				strings.ToUpper(op.Method), html.EscapeString(op.Path), op.TotalChanges(), op.TotalBreakingChanges()))
+			changes := slices.Clone(op.Changes)
+			for _, rc := range op.ReferencedChanges {
+				changes = append(changes, rc.Changes...)
+			}
+			buf.WriteString("<table>\n" + htmlTableHeader)
+			for _, c := range changes {
+				buf.WriteString(htmlRow(c, ids.next(c)))
+			}
+			buf.WriteString("</table>\n")
+			for _, e := range op.Examples {
+				buf.WriteString(e.html())
+			}
+		}
+		buf.WriteString("</section>\n")
+	}
+	return buf.String()
+}
+
+// fileReport is the part of an operation report made in a single file.
+type fileReport struct {
+	File   string
+	Report *OperationChangeReport
+}
+
+// splitByFile splits the report into a report for each file its changes were made in, in the order the files are
+// first found.
+func (r *OperationChangeReport) splitByFile(files *Attachments) []*fileReport {
+	var split []*fileReport
+	byFile := make(map[string]*OperationChangeReport)
+	report := func(c *LocatedChange) *OperationChangeReport {
+		file := files.File(c)
+		fr, ok := byFile[file]
+		if !ok {
+			fr = &OperationChangeReport{Path: r.Path, Method: r.Method, Operation: r.Operation}
+			byFile[file] = fr
+			split = append(split, &fileReport{File: file, Report: fr})
+		}
+		return fr
+	}
+	for _, c := range r.Changes {
+		fr := report(c)
+		fr.Changes = append(fr.Changes, c)
+	}
+	for _, rc := range r.ReferencedChanges {
+		for _, c := range rc.Changes {
+			fr := report(c)
+			if n := len(fr.ReferencedChanges); n == 0 || fr.ReferencedChanges[n-1].Component != rc.Component {
+				fr.ReferencedChanges = append(fr.ReferencedChanges, &ReferencedChange{Component: rc.Component,
+					Operations: rc.Operations, IndirectOperations: rc.IndirectOperations})
+			}
+			last := fr.ReferencedChanges[len(fr.ReferencedChanges)-1]
+			last.Changes = append(last.Changes, c)
+		}
+	}
+	return split
+}
+
+// counts returns the number of changes, and breaking changes, to the operations of the group.
+func (g *ChangeGroup) counts() (int, int) {
+	total, breaking := 0, 0
+	for _, op := range g.Operations {
+		total += op.TotalChanges()
+		breaking += op.TotalBreakingChanges()
+	}
+	return total, breaking
+}
+
+// extensionValues reads an extension that is a string, or a list of strings.
+func extensionValues(extensions *orderedmap.Map[string, *yaml.Node], name string) []string {
+	if extensions == nil {
+		return nil
+	}
+	node := extensions.GetOrZero(name)
+	if node == nil {
+		return nil
+	}
+	var values []string
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Value != "" {
+			values = append(values, node.Value)
+		}
+	case yaml.SequenceNode:
+		for _, n := range node.Content {
+			if n.Kind == yaml.ScalarNode && n.Value != "" {
+				values = append(values, n.Value)
+			}
+		}
+	}
+	return values
+}
+
+// operationKey is the path and method of an operation, for ordering operations by method.
+func operationKey(po *drModel.PathOperation) (string, string) {
+	if po.Webhook {
+		return "webhook " + po.Path, po.Method
+	}
+	return po.Path, po.Method
+}
+
+// groupAnchor turns a group name into an id for an HTML element.
+func groupAnchor(name string) string {
+	return "group-" + strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}), "-")
+}
+
+const htmlTableHeader = "<tr><th>Change</th><th>Location</th><th>Original</th><th>New</th><th>Breaking</th></tr>\n"
+
+// htmlRow renders a change as a table row, with its anchor as the id. The location includes the file of the
+// change, when it is known, acknowledged changes are marked, and the diff and findings of the change are rendered
+// as rows beneath it.
+func htmlRow(c *LocatedChange, anchor string) string {
+	breaking := ""
+	if c.Change.Breaking {
+		breaking = "yes"
+	}
+	location := "<code>" + html.EscapeString(c.PropertyPath()) + "</code>"
+	if c.File != "" {
+		location += " in <code>" + html.EscapeString(c.File) + "</code>"
+	}
+	changeType, class := changeTypeLabel(c), ""
+	if c.Acknowledged != nil {
+		changeType += " <em>(acknowledged)</em>"
+		class = " class=\"acknowledged\""
+	}
+	return fmt.Sprintf("<tr id=\"%s\"%s><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+		anchor, class, changeType, location, htmlValue(c.Change.Original), htmlValue(c.Change.New),
+		breaking) + htmlDiffRow(c) + htmlFindingRows(c)
+}
+
+// htmlDiffRow renders the diff of a modified value (see RenderConfig.ValueDiffs) as a row beneath its change, every
+// line has the diff-remove, diff-add or diff-context class.
+func htmlDiffRow(c *LocatedChange) string {
+	if c.Diff == nil {
+		return ""
+	}
+	buf := strings.Builder{}
+	buf.WriteString("<tr class=\"diff\"><td colspan=\"5\"><pre>")
+	for _, line := range c.Diff.Lines {
+		class := "diff-context"
+		switch line[0] {
+		case '-':
+			class = "diff-remove"
+		case '+':
+			class = "diff-add"
+		}
+		buf.WriteString(fmt.Sprintf("<span class=\"%s\">%s</span>\n", class, html.EscapeString(line)))
+	}
+	buf.WriteString("</pre></td></tr>\n")
+	return buf.String()
+}
+
+// htmlValue renders a changed value inside a table cell.
+func htmlValue(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "<code>" + html.EscapeString(value) + "</code>"
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	drModel "github.com/pb33f/doctor/model"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const teamSpec = `openapi: 3.1.0
+info:
+  title: teams
+  version: 1.0.0
+  x-owner: platform
+paths:
+  /orders:
+    get:
+      tags: [orders, billing]
+      x-owner: [checkout, finance]
+      responses:
+        "200":
+          description: orders
+  /refunds:
+    x-owner: finance
+    get:
+      tags: [billing]
+      responses:
+        "200":
+          description: refunds
+  /status:
+    get:
+      responses:
+        "200":
+          description: ok`
+
+func TestGroupChanges(t *testing.T) {
+	updated := strings.Replace(teamSpec, "description: orders", "description: all the orders", 1)
+	updated = strings.Replace(updated, "description: refunds", "description: all the refunds", 1)
+	updated = strings.Replace(updated, "description: ok", "description: all ok", 1)
+	changes := compare(t, teamSpec, updated)
+
+	doc, _ := libopenapi.NewDocument([]byte(updated))
+	v3Doc, _ := doc.BuildV3Model()
+	drDoc := drModel.NewDrDocument(v3Doc)
+
+	groups := GroupChanges(changes, drDoc, GroupConfig{})
+	var names []string
+	for _, g := range groups.Groups {
+		names = append(names, g.Name)
+	}
+	assert.Equal(t, []string{"billing", "orders", Unowned}, names)
+	require.Len(t, groups.Group("billing").Operations, 2)
+	assert.Nil(t, groups.Group("checkout"))
+
+	groups = GroupChanges(changes, drDoc, GroupConfig{By: GroupByOwner})
+	names = nil
+	for _, g := range groups.Groups {
+		names = append(names, g.Name)
+	}
+	assert.Equal(t, []string{"checkout", "finance", "platform"}, names)
+
+	summary := groups.Summary()
+	assert.Equal(t, []string{"GET /orders", "GET /refunds"}, summary["finance"].Operations)
+	assert.Equal(t, 2, summary["finance"].TotalChanges)
+	assert.Equal(t, []string{"GET /status"}, summary["platform"].Operations)
+
+	md := groups.Markdown()
+	assert.Contains(t, md, "## Contents\n\n- [checkout](#group-checkout): 1 changes, 0 breaking\n")
+	assert.Contains(t, md, "<a id=\"group-finance\"></a>\n\n## finance\n\n### `GET /orders`\n")
+	assert.Contains(t, md, "### `GET /refunds`")
+
+	h := groups.HTML()
+	assert.Contains(t, h, "<li><a href=\"#group-finance\">finance</a> (2 changes, 0 breaking)</li>")
+	assert.Contains(t, h, "<section id=\"group-finance\">\n<h2>finance</h2>")
+	refund := groups.Group("finance").Operations[1].Changes[0]
+	assert.Contains(t, h, "<tr id=\""+refund.Anchor()+"\"><td>modified</td><td><code>$.paths[&#39;/refunds&#39;].get.responses[&#39;200&#39;].description</code></td>"+
+		"<td><code>refunds</code></td><td><code>all the refunds</code></td>")
+
+	assert.Empty(t, GroupChanges(nil, drDoc, GroupConfig{}).Groups)
+}
+
+func TestGroupChanges_Methods(t *testing.T) {
+	spec := strings.Replace(teamSpec, "  /refunds:\n", `  /refunds:
+    trace:
+      tags: [billing]
+      responses:
+        "200":
+          description: trace
+    delete:
+      tags: [billing]
+      responses:
+        "200":
+          description: deleted
+`, 1)
+	updated := strings.ReplaceAll(spec, "description: refunds", "description: all the refunds")
+	updated = strings.Replace(updated, "description: trace", "description: all traced", 1)
+	updated = strings.Replace(updated, "description: deleted", "description: all deleted", 1)
+	changes := compare(t, spec, updated)
+	drDoc := drDocument(t, updated)
+
+	methods := func(g *ChangeGroup) []string {
+		var m []string
+		for _, op := range g.Operations {
+			m = append(m, op.Method)
+		}
+		return m
+	}
+	assert.Equal(t, []string{"trace", "delete", "get"}, methods(GroupChanges(changes, drDoc, GroupConfig{}).Group("billing")))
+	group := GroupChanges(changes, drDoc, GroupConfig{Methods: &drBase.MethodOrder{Methods: drBase.HTTPMethods,
+		Exclude: []string{"trace"}}}).Group("billing")
+	assert.Equal(t, []string{"get", "delete"}, methods(group))
+
+	md, err := Report([]byte(spec), []byte(updated), FormatMarkdown,
+		&RenderConfig{Methods: &drBase.MethodOrder{Exclude: []string{"TRACE"}}})
+	require.NoError(t, err)
+	assert.NotContains(t, string(md), "`TRACE /refunds`")
+	assert.Contains(t, string(md), "`DELETE /refunds`")
+}
@@ -0,0 +1,204 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"fmt"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"slices"
+	"strings"
+)
+
+const (
+	HistoryAdded         = "added"
+	HistoryRemoved       = "removed"
+	HistoryTypeChanged   = "type"
+	HistoryFormatChanged = "format"
+	HistoryRequired      = "required"
+	HistoryOptional      = "optional"
+	HistoryDeprecated    = "deprecated"
+)
+
+// maxHistoryDepth is how deep nested properties are followed, so circular schemas end.
+const maxHistoryDepth = 10
+
+// ComponentHistory is the evolution of a component schema across revisions of a specification. Revisions are
+// named by the version of their info object (or `revision N` when it has none), in the order they were given.
+type ComponentHistory struct {
+	Component  string             `json:"component"`
+	Revisions  []string           `json:"revisions"`
+	Schema     []*HistoryEvent    `json:"schema"`
+	Properties []*PropertyHistory `json:"properties"`
+}
+
+// PropertyHistory is the timeline of a property of the schema. Nested properties are named by their path
+// (`address.city`), and the items of arrays are named with `[]` (`toppings[].name`).
+type PropertyHistory struct {
+	Name   string          `json:"name"`
+	Events []*HistoryEvent `json:"events"`
+}
+
+// HistoryEvent is a change made in a revision, Change is one of the History constants. From and To are the
+// previous and new values of type and format changes.
+type HistoryEvent struct {
+	Revision string `json:"revision"`
+	Index    int    `json:"index"`
+	Change   string `json:"change"`
+	From     string `json:"from,omitempty"`
+	To       string `json:"to,omitempty"`
+}
+
+// propertyState is what is compared between revisions of a property.
+type propertyState struct {
+	types      string
+	format     string
+	required   bool
+	deprecated bool
+}
+
+// SchemaHistory builds the timeline of a component schema (by its name under `components.schemas`) across
+// revisions of a specification, oldest first. The timeline of every property the schema has had says in which
+// revision it was added or removed, its type or format changed, it became required or optional, or it was
+// deprecated. Revisions without the schema are recorded as the removal of the schema and all its properties.
+func SchemaHistory(component string, revisions [][]byte) (*ComponentHistory, error) {
+	h := &ComponentHistory{Component: component, Revisions: []string{}, Schema: []*HistoryEvent{},
+		Properties: []*PropertyHistory{}}
+	properties := make(map[string]*PropertyHistory)
+	var previous map[string]*propertyState
+	existed := false
+	for i, spec := range revisions {
+		doc, err := buildModel(fmt.Sprintf("revision %d", i+1), spec, nil)
+		if err != nil {
+			return nil, err
+		}
+		label := fmt.Sprintf("revision %d", i+1)
+		if doc.Model.Info != nil && doc.Model.Info.Version != "" {
+			label = doc.Model.Info.Version
+		}
+		h.Revisions = append(h.Revisions, label)
+		event := func(change, from, to string) *HistoryEvent {
+			return &HistoryEvent{Revision: label, Index: i, Change: change, From: from, To: to}
+		}
+
+		var schema *base.Schema
+		if c := doc.Model.Components; c != nil {
+			if proxy, ok := c.Schemas.Get(component); ok && proxy != nil {
+				schema = proxy.Schema()
+			}
+		}
+		if schema != nil && !existed {
+			h.Schema = append(h.Schema, event(HistoryAdded, "", ""))
+		}
+		if schema == nil && existed {
+			h.Schema = append(h.Schema, event(HistoryRemoved, "", ""))
+		}
+		existed = schema != nil
+
+		current := make(map[string]*propertyState)
+		if schema != nil {
+			collectProperties(schema, "", 0, current)
+		}
+		names := make([]string, 0, len(current)+len(previous))
+		for name := range current {
+			names = append(names, name)
+		}
+		for name := range previous {
+			if current[name] == nil {
+				names = append(names, name)
+			}
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			var events []*HistoryEvent
+			before, after := previous[name], current[name]
+			switch {
+			case before == nil:
+				events = append(events, event(HistoryAdded, "", after.types))
+			case after == nil:
+				events = append(events, event(HistoryRemoved, before.types, ""))
+			default:
+				if before.types != after.types {
+					events = append(events, event(HistoryTypeChanged, before.types, after.types))
+				}
+				if before.format != after.format {
+					events = append(events, event(HistoryFormatChanged, before.format, after.format))
+				}
+				if !before.required && after.required {
+					events = append(events, event(HistoryRequired, "", ""))
+				}
+				if before.required && !after.required {
+					events = append(events, event(HistoryOptional, "", ""))
+				}
+				if !before.deprecated && after.deprecated {
+					events = append(events, event(HistoryDeprecated, "", ""))
+				}
+			}
+			if len(events) == 0 {
+				continue
+			}
+			ph := properties[name]
+			if ph == nil {
+				ph = &PropertyHistory{Name: name}
+				properties[name] = ph
+				h.Properties = append(h.Properties, ph)
+			}
+			ph.Events = append(ph.Events, events...)
+		}
+		previous = current
+	}
+	slices.SortFunc(h.Properties, func(a, b *PropertyHistory) int { return strings.Compare(a.Name, b.Name) })
+	return h, nil
+}
+
+// collectProperties records the state of every property of a schema, and of the properties nested beneath them.
+func collectProperties(schema *base.Schema, prefix string, depth int, states map[string]*propertyState) {
+	if schema == nil || depth > maxHistoryDepth {
+		return
+	}
+	for name, proxy := range schema.Properties.FromOldest() {
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		s := proxy.Schema()
+		state := &propertyState{required: slices.Contains(schema.Required, name)}
+		if s != nil {
+			state.types = strings.Join(s.Type, "|")
+			state.format = s.Format
+			state.deprecated = s.Deprecated != nil && *s.Deprecated
+		}
+		states[path] = state
+		if s == nil {
+			continue
+		}
+		collectProperties(s, path, depth+1, states)
+		if s.Items != nil && s.Items.IsA() && s.Items.A != nil {
+			collectProperties(s.Items.A.Schema(), path+"[]", depth+1, states)
+		}
+	}
+}
+
+// Markdown renders the history as a table, with a row for every event, the schema first and then each property.
+func (h *ComponentHistory) Markdown() string {
+	buf := strings.Builder{}
+	buf.WriteString(fmt.Sprintf("## `%s` history\n\n", h.Component))
+	if len(h.Revisions) > 0 {
+		buf.WriteString(fmt.Sprintf("Revisions: %s.\n\n", strings.Join(h.Revisions, ", ")))
+	}
+	buf.WriteString("| Property | Revision | Change | From | To |\n|----------|----------|--------|------|----|\n")
+	for _, e := range h.Schema {
+		buf.WriteString(e.markdown("*schema*"))
+	}
+	for _, p := range h.Properties {
+		for _, e := range p.Events {
+			buf.WriteString(e.markdown("`" + p.Name + "`"))
+		}
+	}
+	return buf.String()
+}
+
+func (e *HistoryEvent) markdown(property string) string {
+	return fmt.Sprintf("| %s | %s | %s | %s | %s |\n", property, markdownCell(e.Revision), e.Change,
+		markdownValue(e.From), markdownValue(e.To))
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func historyRevision(version, burger string) []byte {
+	spec := fmt.Sprintf("openapi: 3.1.0\ninfo:\n  title: burgers\n  version: %s\npaths: {}\n", version)
+	if burger != "" {
+		spec += "components:\n  schemas:\n    Burger:\n" + burger
+	}
+	return []byte(spec)
+}
+
+func TestSchemaHistory(t *testing.T) {
+	revisions := [][]byte{
+		historyRevision("1.0.0", `      type: object
+      properties:
+        name:
+          type: string
+`),
+		historyRevision("2.0.0", `      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        price:
+          type: integer
+        toppings:
+          type: array
+          items:
+            type: object
+            properties:
+              name:
+                type: string
+`),
+		historyRevision("3.0.0", `      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+          deprecated: true
+        price:
+          type: number
+          format: double
+        toppings:
+          type: array
+          items:
+            type: object
+            properties:
+              name:
+                type: string
+`),
+		historyRevision("", ""),
+	}
+
+	h, err := SchemaHistory("Burger", revisions)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.0.0", "2.0.0", "3.0.0", "revision 4"}, h.Revisions)
+	assert.Equal(t, []*HistoryEvent{
+		{Revision: "1.0.0", Index: 0, Change: HistoryAdded},
+		{Revision: "revision 4", Index: 3, Change: HistoryRemoved},
+	}, h.Schema)
+
+	require.Len(t, h.Properties, 4)
+	assert.Equal(t, "name", h.Properties[0].Name)
+	assert.Equal(t, []*HistoryEvent{
+		{Revision: "1.0.0", Index: 0, Change: HistoryAdded, To: "string"},
+		{Revision: "2.0.0", Index: 1, Change: HistoryRequired},
+		{Revision: "3.0.0", Index: 2, Change: HistoryDeprecated},
+		{Revision: "revision 4", Index: 3, Change: HistoryRemoved, From: "string"},
+	}, h.Properties[0].Events)
+	assert.Equal(t, "price", h.Properties[1].Name)
+	assert.Equal(t, []*HistoryEvent{
+		{Revision: "2.0.0", Index: 1, Change: HistoryAdded, To: "integer"},
+		{Revision: "3.0.0", Index: 2, Change: HistoryTypeChanged, From: "integer", To: "number"},
+		{Revision: "3.0.0", Index: 2, Change: HistoryFormatChanged, To: "double"},
+		{Revision: "revision 4", Index: 3, Change: HistoryRemoved, From: "number"},
+	}, h.Properties[1].Events)
+	assert.Equal(t, "toppings", h.Properties[2].Name)
+	assert.Equal(t, "toppings[].name", h.Properties[3].Name)
+
+	md := h.Markdown()
+	assert.Contains(t, md, "## `Burger` history\n\nRevisions: 1.0.0, 2.0.0, 3.0.0, revision 4.\n\n")
+	assert.Contains(t, md, "| *schema* | 1.0.0 | added |  |  |\n")
+	assert.Contains(t, md, "| `price` | 3.0.0 | type | `integer` | `number` |\n")
+	assert.Contains(t, md, "| `toppings[].name` | 2.0.0 | added |  | `string` |\n")
+
+	raw, err := json.Marshal(h)
+	require.NoError(t, err)
+	var decoded ComponentHistory
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+	assert.Equal(t, h, &decoded)
+}
+
+func TestSchemaHistory_Invalid(t *testing.T) {
+	_, err := SchemaHistory("Burger", [][]byte{historyRevision("1.0.0", ""), []byte("")})
+	assert.ErrorContains(t, err, "revision 2")
+
+	h, err := SchemaHistory("Burger", nil)
+	require.NoError(t, err)
+	assert.Empty(t, h.Properties)
+}
@@ -0,0 +1,241 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"fmt"
+	drModel "github.com/pb33f/doctor/model"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/what-changed/model"
+	"html"
+	"slices"
+	"strings"
+)
+
+// ImpactWeights is the scoring model for the impact of a change. The score of a change is
+//
+//	Base × ObjectTypes[type] × Breaking (when breaking) × (1 + Usage × (operations - 1)) × Deprecated (when every
+//	affected operation is deprecated)
+//
+// Object types missing from ObjectTypes have a weight of 1.
+type ImpactWeights struct {
+	Base float64
+
+	// Breaking multiplies the score of breaking changes.
+	Breaking float64
+
+	// ObjectTypes weighs changes by the type of object they were made to (see ScoredChange.ObjectType).
+	ObjectTypes map[string]float64
+
+	// Usage is added to the multiplier for every affected operation after the first, so a change to a component
+	// used by many operations outranks the same change made to a single operation.
+	Usage float64
+
+	// Deprecated multiplies the score of changes that only affect deprecated operations.
+	Deprecated float64
+}
+
+// DefaultImpactWeights ranks breaking changes far above other changes, changes to operations and their inputs above
+// changes to documentation, and discounts changes to deprecated operations.
+var DefaultImpactWeights = &ImpactWeights{
+	Base:     1,
+	Breaking: 10,
+	ObjectTypes: map[string]float64{
+		"operation":      2,
+		"parameter":      2,
+		"requestBody":    2,
+		"response":       1.5,
+		"schema":         1.5,
+		"securityScheme": 2,
+		"security":       2,
+		"server":         1.5,
+		"info":           0.25,
+		"tag":            0.25,
+		"externalDocs":   0.25,
+	},
+	Usage:      0.5,
+	Deprecated: 0.25,
+}
+
+// ScoredChange is a change, and its impact. Operations are the operations the change affects, the operations of a
+// changed path, the operations that use a changed component directly or indirectly, or every operation for a
+// change to servers or security requirements of the document.
+type ScoredChange struct {
+	*LocatedChange
+	Score      float64
+	ObjectType string
+	Operations []*drModel.PathOperation
+
+	// Deprecated is true when every affected operation is deprecated.
+	Deprecated bool
+}
+
+// impactMarkers map the segments of a change path to the type of object they hold. Keyed markers are followed by
+// a map key (a property, path or component name) that is not itself a marker.
+var impactMarkers = map[string]struct {
+	objectType string
+	keyed      bool
+}{
+	"info":                 {"info", false},
+	"servers":              {"server", false},
+	"security":             {"security", false},
+	"tags":                 {"tag", false},
+	"externalDocs":         {"externalDocs", false},
+	"paths":                {"pathItem", true},
+	"webhooks":             {"pathItem", true},
+	"parameters":           {"parameter", true},
+	"requestBody":          {"requestBody", false},
+	"requestBodies":        {"requestBody", true},
+	"responses":            {"response", true},
+	"headers":              {"header", true},
+	"schemas":              {"schema", true},
+	"schema":               {"schema", false},
+	"properties":           {"schema", true},
+	"items":                {"schema", false},
+	"allOf":                {"schema", false},
+	"oneOf":                {"schema", false},
+	"anyOf":                {"schema", false},
+	"additionalProperties": {"schema", false},
+	"securitySchemes":      {"securityScheme", true},
+	"examples":             {"example", true},
+	"links":                {"link", true},
+	"callbacks":            {"callback", true},
+}
+
+// ScoreChanges scores every change in the document changes, and returns them ranked by impact, highest first.
+// Changes with the same score keep the order they are located in. The document is the updated version of the
+// specification, it is used to find the operations affected by each change. A nil weights uses
+// DefaultImpactWeights.
+func ScoreChanges(docChanges *model.DocumentChanges, drDoc *drModel.DrDocument, weights *ImpactWeights) []*ScoredChange {
+	if weights == nil {
+		weights = DefaultImpactWeights
+	}
+	ops := drDoc.Operations()
+	byComponent := make(map[string][]*drModel.PathOperation)
+	for _, rc := range ReferencedChanges(docChanges, drDoc) {
+		byComponent[rc.Component] = append(slices.Clone(rc.Operations), rc.IndirectOperations...)
+	}
+
+	var scored []*ScoredChange
+	for _, c := range LocateChanges(docChanges) {
+		sc := &ScoredChange{LocatedChange: c, ObjectType: changeObjectType(pathSegments(c.PropertyPath()))}
+		segments := pathSegments(c.Path)
+		switch {
+		case len(segments) >= 2 && (segments[0] == "paths" || segments[0] == "webhooks"):
+			for _, po := range ops {
+				if po.Path == segments[1] && po.Webhook == (segments[0] == "webhooks") &&
+					(len(segments) == 2 || !slices.Contains(drBase.HTTPMethods, segments[2]) || po.Method == segments[2]) {
+					sc.Operations = append(sc.Operations, po)
+				}
+			}
+		case componentForPath(c.Path) != "":
+			sc.Operations = byComponent[componentForPath(c.Path)]
+		case sc.ObjectType == "server" || sc.ObjectType == "security":
+			sc.Operations = ops
+		}
+		sc.Deprecated = len(sc.Operations) > 0
+		for _, po := range sc.Operations {
+			if po.Operation == nil || po.Operation.Value == nil || po.Operation.Value.Deprecated == nil ||
+				!*po.Operation.Value.Deprecated {
+				sc.Deprecated = false
+				break
+			}
+		}
+		sc.Score = weights.score(sc)
+		scored = append(scored, sc)
+	}
+	slices.SortStableFunc(scored, func(a, b *ScoredChange) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		}
+		return 0
+	})
+	return scored
+}
+
+func (w *ImpactWeights) score(sc *ScoredChange) float64 {
+	score := w.Base
+	if t, ok := w.ObjectTypes[sc.ObjectType]; ok {
+		score *= t
+	}
+	if sc.Change.Breaking {
+		score *= w.Breaking
+	}
+	if len(sc.Operations) > 1 {
+		score *= 1 + w.Usage*float64(len(sc.Operations)-1)
+	}
+	if sc.Deprecated {
+		score *= w.Deprecated
+	}
+	return score
+}
+
+// changeObjectType returns the type of object the segments of a change path lead to, the last marker in the path
+// wins. The document itself is `document`.
+func changeObjectType(segments []string) string {
+	objectType := "document"
+	for i := 0; i < len(segments); i++ {
+		m, ok := impactMarkers[segments[i]]
+		if !ok {
+			if slices.Contains(drBase.HTTPMethods, segments[i]) && objectType == "pathItem" {
+				objectType = "operation"
+			}
+			continue
+		}
+		objectType = m.objectType
+		if m.keyed {
+			i++
+		}
+	}
+	return objectType
+}
+
+// TopChanges returns the first n scored changes, or all of them when there are fewer than n.
+func TopChanges(scored []*ScoredChange, n int) []*ScoredChange {
+	if n < len(scored) {
+		return scored[:n]
+	}
+	return scored
+}
+
+const impactMarkdownHeader = "| Score | Change | Location | Operations | Breaking |\n|-------|--------|----------|------------|----------|\n"
+
+// impactMarkdown renders ranked changes as a table.
+func impactMarkdown(scored []*ScoredChange) string {
+	buf := strings.Builder{}
+	buf.WriteString(impactMarkdownHeader)
+	for _, sc := range scored {
+		breaking := ""
+		if sc.Change.Breaking {
+			breaking = "yes"
+		}
+		buf.WriteString(fmt.Sprintf("| %s | %s | `%s` | %d | %s |\n", formatScore(sc.Score),
+			ChangeTypeName(sc.Change.ChangeType), sc.PropertyPath(), len(sc.Operations), breaking))
+	}
+	return buf.String()
+}
+
+// impactHTML renders ranked changes as a table, each change links to its row in the report.
+func impactHTML(scored []*ScoredChange) string {
+	buf := strings.Builder{}
+	buf.WriteString("<table>\n<tr><th>Score</th><th>Change</th><th>Location</th><th>Operations</th><th>Breaking</th></tr>\n")
+	for _, sc := range scored {
+		breaking := ""
+		if sc.Change.Breaking {
+			breaking = "yes"
+		}
+		buf.WriteString(fmt.Sprintf("<tr><td>%s</td><td>%s</td><td><a href=\"#%s\"><code>%s</code></a></td>"+
+			"<td>%d</td><td>%s</td></tr>\n", formatScore(sc.Score), ChangeTypeName(sc.Change.ChangeType), sc.Anchor(),
+			html.EscapeString(sc.PropertyPath()), len(sc.Operations), breaking))
+	}
+	buf.WriteString("</table>\n")
+	return buf.String()
+}
+
+func formatScore(score float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", score), "0"), ".")
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+var impactSpec = `openapi: 3.1.0
+info:
+  title: burgers
+  version: 1.0.0
+paths:
+  /burgers:
+    get:
+      description: list burgers
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Burger'
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Burger'
+      responses:
+        "201":
+          description: created
+  /fries:
+    get:
+      deprecated: true
+      description: list fries
+      responses:
+        "200":
+          description: ok
+components:
+  schemas:
+    Burger:
+      type: object
+      properties:
+        name:
+          type: string`
+
+func TestScoreChanges(t *testing.T) {
+	updated := strings.Replace(impactSpec, "name:\n          type: string", "name:\n          type: integer", 1)
+	updated = strings.Replace(updated, "list burgers", "list every burger", 1)
+	updated = strings.Replace(updated, "list fries", "list all fries", 1)
+	updated = strings.Replace(updated, "version: 1.0.0", "version: 1.1.0", 1)
+
+	scored := ScoreChanges(compare(t, impactSpec, updated), drDocument(t, updated), nil)
+	require.Len(t, scored, 4)
+
+	// the breaking schema change is used by two operations.
+	assert.Equal(t, "$.components.schemas['Burger'].properties['name'].type", scored[0].PropertyPath())
+	assert.Equal(t, "schema", scored[0].ObjectType)
+	assert.Len(t, scored[0].Operations, 2)
+	assert.Equal(t, 1*1.5*10*1.5, scored[0].Score)
+
+	assert.Equal(t, "$.paths['/burgers'].get.description", scored[1].PropertyPath())
+	assert.Equal(t, "operation", scored[1].ObjectType)
+	assert.Equal(t, float64(2), scored[1].Score)
+
+	assert.Equal(t, "$.paths['/fries'].get.description", scored[2].PropertyPath())
+	assert.True(t, scored[2].Deprecated)
+	assert.Equal(t, 0.5, scored[2].Score)
+
+	assert.Equal(t, "info", scored[3].ObjectType)
+	assert.Equal(t, 0.25, scored[3].Score)
+
+	assert.Len(t, TopChanges(scored, 2), 2)
+	assert.Len(t, TopChanges(scored, 10), 4)
+	assert.Empty(t, ScoreChanges(nil, nil, nil))
+}
+
+func TestChangeObjectType(t *testing.T) {
+	for path, expected := range map[string]string{
+		"$":                                      "document",
+		"$.paths['/burgers']":                    "pathItem",
+		"$.paths['/burgers'].get":                "operation",
+		"$.paths['/burgers'].get.parameters":     "parameter",
+		"$.paths['/responses'].post.requestBody": "requestBody",
+		"$.components.schemas['Burger'].properties['schema'].type": "schema",
+		"$.components.parameters['Id']":                            "parameter",
+		"$.servers":                                                "server",
+	} {
+		assert.Equal(t, expected, changeObjectType(pathSegments(path)), path)
+	}
+}
+
+func TestReport_TopImpact(t *testing.T) {
+	updated := strings.Replace(impactSpec, "name:\n          type: string", "name:\n          type: integer", 1)
+	updated = strings.Replace(updated, "version: 1.0.0", "version: 1.1.0", 1)
+
+	md, err := Report([]byte(impactSpec), []byte(updated), FormatMarkdown, &RenderConfig{TopImpact: 1})
+	require.NoError(t, err)
+	assert.Contains(t, string(md), "## Most impactful changes\n\n"+impactMarkdownHeader+
+		"| 22.5 | modified | `$.components.schemas['Burger'].properties['name'].type` | 2 | yes |\n\n")
+
+	out, err := Report([]byte(impactSpec), []byte(updated), FormatJSON, &RenderConfig{TopImpact: 5})
+	require.NoError(t, err)
+	var report jsonReport
+	require.NoError(t, json.Unmarshal(out, &report))
+	require.Len(t, report.Impact, 2)
+	assert.Equal(t, 22.5, report.Impact[0].Score)
+
+	out, err = Report([]byte(impactSpec), []byte(updated), FormatHTML, &RenderConfig{TopImpact: 1})
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "<section id=\"most-impactful-changes\">")
+
+	md, _ = Report([]byte(impactSpec), []byte(updated), FormatMarkdown, nil)
+	assert.NotContains(t, string(md), "Most impactful changes")
+}
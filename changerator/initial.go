@@ -0,0 +1,238 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	drModel "github.com/pb33f/doctor/model"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"html"
+	"strings"
+)
+
+// ReleaseSummary summarizes a specification that has no previous version, for an initial release report. Every
+// operation, component schema and security scheme is listed once, rather than as a change.
+type ReleaseSummary struct {
+	Title           string                   `json:"title"`
+	Version         string                   `json:"version"`
+	Operations      []*ReleaseOperation      `json:"operations"`
+	Schemas         []string                 `json:"schemas"`
+	SecuritySchemes []*ReleaseSecurityScheme `json:"securitySchemes"`
+}
+
+// ReleaseOperation is an operation of an initial release, Path is the name of the webhook for webhook operations.
+type ReleaseOperation struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	OperationId string `json:"operationId,omitempty"`
+	Summary     string `json:"summary,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+	Webhook     bool   `json:"webhook,omitempty"`
+}
+
+// ReleaseSecurityScheme is a security scheme of an initial release.
+type ReleaseSecurityScheme struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// SummarizeRelease summarizes a document for an initial release: its title and version, its operations (paths
+// first, then webhooks, in the order they are declared), its component schemas and its security schemes.
+func SummarizeRelease(drDoc *drModel.DrDocument) *ReleaseSummary {
+	s := &ReleaseSummary{Operations: []*ReleaseOperation{}, Schemas: []string{},
+		SecuritySchemes: []*ReleaseSecurityScheme{}}
+	if drDoc == nil || drDoc.V3Document == nil || drDoc.V3Document.Document == nil {
+		return s
+	}
+	doc := drDoc.V3Document.Document
+	if doc.Info != nil {
+		s.Title, s.Version = doc.Info.Title, doc.Info.Version
+	}
+	operations := func(name string, pi *v3.PathItem, webhook bool) {
+		for method, op := range pi.GetOperations().FromOldest() {
+			ro := &ReleaseOperation{Method: strings.ToUpper(method), Path: name, OperationId: op.OperationId,
+				Summary: op.Summary, Webhook: webhook}
+			ro.Deprecated = op.Deprecated != nil && *op.Deprecated
+			s.Operations = append(s.Operations, ro)
+		}
+	}
+	if doc.Paths != nil && doc.Paths.PathItems != nil {
+		for path, pi := range doc.Paths.PathItems.FromOldest() {
+			operations(path, pi, false)
+		}
+	}
+	for name, pi := range doc.Webhooks.FromOldest() {
+		operations(name, pi, true)
+	}
+	if c := doc.Components; c != nil {
+		for name := range c.Schemas.KeysFromOldest() {
+			s.Schemas = append(s.Schemas, name)
+		}
+		for name, ss := range c.SecuritySchemes.FromOldest() {
+			s.SecuritySchemes = append(s.SecuritySchemes, &ReleaseSecurityScheme{Name: name, Type: ss.Type})
+		}
+	}
+	return s
+}
+
+// Markdown renders the summary as an initial release report.
+func (s *ReleaseSummary) Markdown() string {
+	buf := strings.Builder{}
+	buf.WriteString("# Initial release\n\n")
+	buf.WriteString(s.headline() + "\n")
+	if len(s.Operations) > 0 {
+		buf.WriteString("\n## Operations\n\n| Method | Path | Operation ID | Summary |\n| --- | --- | --- | --- |\n")
+		for _, op := range s.Operations {
+			buf.WriteString(fmt.Sprintf("| %s | `%s` | %s | %s |\n", op.Method, op.Path, op.OperationId,
+				markdownCell(op.notes())))
+		}
+	}
+	if len(s.Schemas) > 0 {
+		buf.WriteString("\n## Schemas\n\n")
+		for _, name := range s.Schemas {
+			buf.WriteString(fmt.Sprintf("- `%s`\n", name))
+		}
+	}
+	if len(s.SecuritySchemes) > 0 {
+		buf.WriteString("\n## Security\n\n| Scheme | Type |\n| --- | --- |\n")
+		for _, ss := range s.SecuritySchemes {
+			buf.WriteString(fmt.Sprintf("| `%s` | %s |\n", ss.Name, ss.Type))
+		}
+	}
+	return buf.String()
+}
+
+// HTML renders the summary as an initial release report page.
+func (s *ReleaseSummary) HTML() string {
+	buf := strings.Builder{}
+	buf.WriteString("<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"><title>Initial release</title></head>\n<body>\n")
+	buf.WriteString(fmt.Sprintf("<h1>Initial release</h1>\n<p>%s</p>\n", html.EscapeString(s.headline())))
+	if len(s.Operations) > 0 {
+		buf.WriteString("<section id=\"operations\">\n<h2>Operations</h2>\n<table>\n")
+		buf.WriteString("<tr><th>Method</th><th>Path</th><th>Operation ID</th><th>Summary</th></tr>\n")
+		for _, op := range s.Operations {
+			buf.WriteString(fmt.Sprintf("<tr><td>%s</td><td><code>%s</code></td><td>%s</td><td>%s</td></tr>\n",
+				op.Method, html.EscapeString(op.Path), html.EscapeString(op.OperationId),
+				html.EscapeString(op.notes())))
+		}
+		buf.WriteString("</table>\n</section>\n")
+	}
+	if len(s.Schemas) > 0 {
+		buf.WriteString("<section id=\"schemas\">\n<h2>Schemas</h2>\n<ul>\n")
+		for _, name := range s.Schemas {
+			buf.WriteString(fmt.Sprintf("<li><code>%s</code></li>\n", html.EscapeString(name)))
+		}
+		buf.WriteString("</ul>\n</section>\n")
+	}
+	if len(s.SecuritySchemes) > 0 {
+		buf.WriteString("<section id=\"security\">\n<h2>Security</h2>\n<table>\n<tr><th>Scheme</th><th>Type</th></tr>\n")
+		for _, ss := range s.SecuritySchemes {
+			buf.WriteString(fmt.Sprintf("<tr><td><code>%s</code></td><td>%s</td></tr>\n",
+				html.EscapeString(ss.Name), html.EscapeString(ss.Type)))
+		}
+		buf.WriteString("</table>\n</section>\n")
+	}
+	buf.WriteString("</body>\n</html>\n")
+	return buf.String()
+}
+
+// headline names the release, and counts what it contains.
+func (s *ReleaseSummary) headline() string {
+	name := "The API"
+	if s.Title != "" {
+		name = s.Title
+	}
+	if s.Version != "" {
+		name += " " + s.Version
+	}
+	return fmt.Sprintf("%s is released with %d operations, %d schemas and %d security schemes.", name,
+		len(s.Operations), len(s.Schemas), len(s.SecuritySchemes))
+}
+
+// notes returns the summary of the operation, and marks deprecated operations and webhooks.
+func (op *ReleaseOperation) notes() string {
+	var notes []string
+	if op.Webhook {
+		notes = append(notes, "(webhook)")
+	}
+	if op.Deprecated {
+		notes = append(notes, "(deprecated)")
+	}
+	if op.Summary != "" {
+		notes = append(notes, op.Summary)
+	}
+	return strings.Join(notes, " ")
+}
+
+// markdownCell escapes text for a markdown table cell.
+func markdownCell(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "|", "\\|"), "\n", " ")
+}
+
+// emptyBaseline reports whether the original specification has nothing to compare to: no content at all, or a
+// document with no paths, webhooks or components (a placeholder).
+func emptyBaseline(spec []byte, doc *libopenapi.DocumentModel[v3.Document]) bool {
+	if len(bytes.TrimSpace(spec)) == 0 {
+		return true
+	}
+	if doc == nil {
+		return false
+	}
+	m := &doc.Model
+	if m.Paths != nil && orderedmap.Len(m.Paths.PathItems) > 0 {
+		return false
+	}
+	if orderedmap.Len(m.Webhooks) > 0 {
+		return false
+	}
+	if c := m.Components; c != nil {
+		if orderedmap.Len(c.Schemas) > 0 || orderedmap.Len(c.Responses) > 0 || orderedmap.Len(c.Parameters) > 0 ||
+			orderedmap.Len(c.Examples) > 0 || orderedmap.Len(c.RequestBodies) > 0 || orderedmap.Len(c.Headers) > 0 ||
+			orderedmap.Len(c.SecuritySchemes) > 0 || orderedmap.Len(c.Links) > 0 || orderedmap.Len(c.Callbacks) > 0 ||
+			orderedmap.Len(c.PathItems) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// initialReport renders an initial release report of the updated specification. A diff report adds every line of
+// the specification in a single hunk, lines are redacted with the path of the document.
+func initialReport(rightSpec []byte, drDoc *drModel.DrDocument, format Format, cfg *RenderConfig) ([]byte, error) {
+	summary := SummarizeRelease(drDoc)
+	summary.Operations = drBase.OrderMethods(cfg.groupConfig().Methods, summary.Operations,
+		func(op *ReleaseOperation) (string, string) {
+			if op.Webhook {
+				return "webhook " + op.Path, op.Method
+			}
+			return op.Path, op.Method
+		})
+	redactor := cfg.Redactor
+	switch format {
+	case FormatDiff:
+		lines := strings.Split(strings.TrimSuffix(string(rightSpec), "\n"), "\n")
+		buf := strings.Builder{}
+		buf.WriteString(fmt.Sprintf("--- original\n+++ updated\n@@ -0,0 +%s @@\n", hunkRange(1, len(lines))))
+		for _, l := range lines {
+			l = strings.TrimSuffix(l, "\r")
+			if redactor != nil {
+				l = redactor("$", l)
+			}
+			buf.WriteString("+" + l + "\n")
+		}
+		return []byte(buf.String()), nil
+	case FormatJSON:
+		return json.MarshalIndent(&jsonReport{Groups: map[string]*GroupSummary{}, Changes: []*ReportChange{},
+			InitialRelease: summary}, "", "  ")
+	case FormatHTML:
+		return []byte(summary.HTML()), nil
+	default:
+		return []byte(summary.Markdown()), nil
+	}
+}
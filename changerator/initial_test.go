@@ -0,0 +1,111 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+var initialSpec = `openapi: 3.1.0
+info:
+  title: burgers
+  version: 1.0.0
+paths:
+  /burgers:
+    get:
+      operationId: listBurgers
+      summary: List | all burgers
+      responses:
+        "200":
+          description: ok
+    delete:
+      deprecated: true
+      responses:
+        "204":
+          description: deleted
+webhooks:
+  burgerCooked:
+    post:
+      responses:
+        "200":
+          description: ok
+components:
+  schemas:
+    Burger:
+      type: object
+    Fries:
+      type: object
+  securitySchemes:
+    key:
+      type: apiKey
+      in: header
+      name: X-Key`
+
+func TestSummarizeRelease(t *testing.T) {
+	s := SummarizeRelease(drDocument(t, initialSpec))
+	assert.Equal(t, "burgers", s.Title)
+	assert.Equal(t, "1.0.0", s.Version)
+	require.Len(t, s.Operations, 3)
+	assert.Equal(t, &ReleaseOperation{Method: "GET", Path: "/burgers", OperationId: "listBurgers",
+		Summary: "List | all burgers"}, s.Operations[0])
+	assert.Equal(t, &ReleaseOperation{Method: "DELETE", Path: "/burgers", Deprecated: true}, s.Operations[1])
+	assert.Equal(t, &ReleaseOperation{Method: "POST", Path: "burgerCooked", Webhook: true}, s.Operations[2])
+	assert.Equal(t, []string{"Burger", "Fries"}, s.Schemas)
+	assert.Equal(t, []*ReleaseSecurityScheme{{Name: "key", Type: "apiKey"}}, s.SecuritySchemes)
+
+	assert.Empty(t, SummarizeRelease(nil).Operations)
+}
+
+func TestReport_InitialRelease(t *testing.T) {
+	md, err := Report(nil, []byte(initialSpec), FormatMarkdown, nil)
+	require.NoError(t, err)
+	report := string(md)
+	assert.True(t, strings.HasPrefix(report, "# Initial release\n\n"+
+		"burgers 1.0.0 is released with 3 operations, 2 schemas and 1 security schemes.\n"))
+	assert.Contains(t, report, "| GET | `/burgers` | listBurgers | List \\| all burgers |\n")
+	assert.Contains(t, report, "| DELETE | `/burgers` |  | (deprecated) |\n")
+	assert.Contains(t, report, "| POST | `burgerCooked` |  | (webhook) |\n")
+	assert.Contains(t, report, "## Schemas\n\n- `Burger`\n- `Fries`\n")
+	assert.Contains(t, report, "| `key` | apiKey |\n")
+	assert.NotContains(t, report, "# Changes")
+
+	// a placeholder document is an empty baseline too.
+	placeholder := []byte("openapi: 3.1.0\ninfo:\n  title: burgers\n  version: 0.0.0\n")
+	placeholderMd, err := Report(placeholder, []byte(initialSpec), FormatMarkdown, nil)
+	require.NoError(t, err)
+	assert.Equal(t, report, string(placeholderMd))
+
+	page, err := Report([]byte("  \n"), []byte(initialSpec), FormatHTML, nil)
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "<h1>Initial release</h1>")
+	assert.Contains(t, string(page), "<tr><td>GET</td><td><code>/burgers</code></td><td>listBurgers</td>"+
+		"<td>List | all burgers</td></tr>")
+	assert.Contains(t, string(page), "<li><code>Fries</code></li>")
+
+	raw, err := Report(nil, []byte(initialSpec), FormatJSON, nil)
+	require.NoError(t, err)
+	var decoded jsonReport
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+	assert.Zero(t, decoded.TotalChanges)
+	assert.Empty(t, decoded.Changes)
+	require.NotNil(t, decoded.InitialRelease)
+	assert.Len(t, decoded.InitialRelease.Operations, 3)
+
+	diff, err := Report(nil, []byte(initialSpec), FormatDiff, nil)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(diff), "\n"), "\n")
+	assert.Equal(t, []string{"--- original", "+++ updated", "@@ -0,0 +1,34 @@", "+openapi: 3.1.0"}, lines[:4])
+	assert.Equal(t, "+      name: X-Key", lines[len(lines)-1])
+	assert.Len(t, lines, 37)
+}
+
+func TestReport_NotInitialRelease(t *testing.T) {
+	md, err := Report([]byte(initialSpec), []byte(initialSpec), FormatMarkdown, nil)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(md), "# Changes\n\n0 changes, 0 breaking.\n"))
+}
@@ -0,0 +1,172 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/what-changed/model"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// LocatedChange is a change, and the JSONPath of the object that owns it. The property that changed is
+// Change.Property. File is the file of a multi-file specification the change was made in (see Attachments.File),
+// it is only set by reports that span more than one file. Findings are the findings on the object the change was
+// made to, they are only set by reports that interleave findings with changes (see RenderConfig.InterleaveFindings).
+// Acknowledged is the suppression that matched the change, it is only set by reports with suppressions (see
+// RenderConfig.Suppressions). Diff is the diff of the raw specifications around a modified value, it is only set by
+// HTML reports that render value diffs (see RenderConfig.ValueDiffs).
+type LocatedChange struct {
+	Path         string
+	Change       *model.Change
+	File         string
+	Findings     []*drBase.Finding
+	Acknowledged *Suppression
+	Diff         *DiffHunk
+}
+
+// PropertyPath returns the JSONPath of the property that changed. For objects that were added or removed from
+// a map (for example a schema property) the path includes the key of the object.
+func (l *LocatedChange) PropertyPath() string {
+	if l.Change == nil || l.Change.Property == "" {
+		return l.Path
+	}
+	path := appendProperty(l.Path, l.Change.Property)
+	if l.Change.ChangeType == model.ObjectAdded || l.Change.ChangeType == model.ObjectRemoved {
+		key := l.Change.New
+		if key == "" {
+			key = l.Change.Original
+		}
+		if key != "" && !strings.ContainsAny(key, "\n'") {
+			path = appendKey(path, key)
+		}
+	}
+	return path
+}
+
+// Anchor returns a stable id for the change, for deep-linking to it from a report. The id is a hash of the path,
+// the property and the type of the change, so it is the same every time the same change is reported.
+func (l *LocatedChange) Anchor() string {
+	property, changeType := "", 0
+	if l.Change != nil {
+		property, changeType = l.Change.Property, l.Change.ChangeType
+	}
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d", l.Path, property, changeType)))
+	return "change-" + hex.EncodeToString(hash[:6])
+}
+
+// anchors hands out the anchors of changes, changes that would share an anchor (the same property of an object in
+// an array changed in the same way) are given a numbered suffix, so ids are unique within a report.
+type anchors map[string]int
+
+func (a anchors) next(c *LocatedChange) string {
+	anchor := c.Anchor()
+	a[anchor]++
+	if n := a[anchor]; n > 1 {
+		return fmt.Sprintf("%s-%d", anchor, n)
+	}
+	return anchor
+}
+
+// segments that are named differently in the what-changed model, an empty segment is not part of the path.
+var changeSegments = map[string]string{
+	"changes":              "",
+	"pathItems":            "",
+	"expressions":          "",
+	"response":             "",
+	"extensions":           "",
+	"externalDoc":          "externalDocs",
+	"securityRequirements": "security",
+	"requestBodies":        "requestBody",
+	"serverVariables":      "variables",
+	"oAuthFlow":            "flows",
+	"authCode":             "authorizationCode",
+	"mappings":             "mapping",
+}
+
+var changeType = reflect.TypeOf(&model.Change{})
+
+// LocateChanges returns every change in the document changes, with the JSONPath of the object that owns it,
+// for example `$.paths['/burgers'].get.responses['200']`. Changes inside arrays (parameters, servers, tags and
+// security requirements) are located at the array, as the what-changed model does not record the index.
+//
+// Changes are returned in a stable order, map keys are visited in sorted order.
+func LocateChanges(docChanges *model.DocumentChanges) []*LocatedChange {
+	if docChanges == nil {
+		return nil
+	}
+	var located []*LocatedChange
+	locateChanges(reflect.ValueOf(docChanges), "$", &located)
+	return located
+}
+
+func locateChanges(v reflect.Value, path string, located *[]*LocatedChange) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		if v.Type() == changeType {
+			*located = append(*located, &LocatedChange{Path: path, Change: v.Interface().(*model.Change)})
+			return
+		}
+		locateChanges(v.Elem(), path, located)
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			locateChanges(v.Index(i), path, located)
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+		})
+		for _, k := range keys {
+			locateChanges(v.MapIndex(k), appendKey(path, fmt.Sprint(k.Interface())), located)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			if f.Anonymous {
+				// embedded property changes belong to this object.
+				locateChanges(v.Field(i), path, located)
+				continue
+			}
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if s, ok := changeSegments[name]; ok {
+				name = s
+			}
+			p := path
+			if name != "" {
+				p = path + "." + name
+			}
+			locateChanges(v.Field(i), p, located)
+		}
+	}
+}
+
+// appendProperty appends a property name to a JSONPath, names that are not simple identifiers are quoted.
+func appendProperty(path, property string) string {
+	for _, r := range property {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '$') {
+			return appendKey(path, property)
+		}
+	}
+	return path + "." + property
+}
+
+// appendKey appends a map key to a JSONPath.
+func appendKey(path, key string) string {
+	return fmt.Sprintf("%s['%s']", path, key)
+}
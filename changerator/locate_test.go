@@ -0,0 +1,54 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"github.com/pb33f/libopenapi"
+	whatChanged "github.com/pb33f/libopenapi/what-changed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+func TestLocateChanges(t *testing.T) {
+	located := LocateChanges(compare(t, leftSpec, rightSpec))
+	require.Len(t, located, 3)
+
+	assert.Equal(t, "$.info", located[0].Path)
+	assert.Equal(t, "$.info.version", located[0].PropertyPath())
+	assert.Equal(t, "$.paths['/burgers'].get", located[1].Path)
+	assert.Equal(t, "$.paths['/burgers'].get.parameters", located[1].PropertyPath())
+	assert.Equal(t, "$.components.schemas['Burger']", located[2].Path)
+	assert.Equal(t, "$.components.schemas['Burger'].properties['patties']", located[2].PropertyPath())
+}
+
+func TestLocatedChange_Anchor(t *testing.T) {
+	located := LocateChanges(compare(t, leftSpec, rightSpec))
+	again := LocateChanges(compare(t, leftSpec, rightSpec))
+	require.Len(t, located, 3)
+
+	// anchors are stable between runs, and unique between changes.
+	assert.Regexp(t, "^change-[0-9a-f]{12}$", located[0].Anchor())
+	assert.Equal(t, again[0].Anchor(), located[0].Anchor())
+	assert.NotEqual(t, located[1].Anchor(), located[0].Anchor())
+
+	ids := anchors{}
+	assert.Equal(t, located[0].Anchor(), ids.next(located[0]))
+	assert.Equal(t, located[0].Anchor()+"-2", ids.next(located[0]))
+}
+
+func TestLocateChanges_Stripe(t *testing.T) {
+	original, _ := os.ReadFile("../test_specs/stripe-old.yaml")
+	updated, _ := os.ReadFile("../test_specs/stripe.yaml")
+	l, _ := libopenapi.NewDocument(original)
+	r, _ := libopenapi.NewDocument(updated)
+	lm, _ := l.BuildV3Model()
+	rm, _ := r.BuildV3Model()
+	changes := whatChanged.CompareOpenAPIDocuments(lm.Model.GoLow(), rm.Model.GoLow())
+
+	// every change is located.
+	assert.Len(t, LocateChanges(changes), changes.TotalChanges())
+	assert.Nil(t, LocateChanges(nil))
+}
@@ -0,0 +1,182 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"fmt"
+	drModel "github.com/pb33f/doctor/model"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/what-changed/model"
+	"slices"
+	"strings"
+)
+
+// OperationChangeReport is the subset of document changes that affect a single operation.
+type OperationChangeReport struct {
+	Path   string
+	Method string
+
+	// Operation is the what-changed subtree for the operation, it is nil when the operation itself did not change.
+	Operation *model.OperationChanges
+
+	// Changes are the located changes made to the operation, and to the path item it belongs to (for example
+	// shared parameters), excluding changes to the other operations of the path item.
+	Changes []*LocatedChange
+
+	// ReferencedChanges are the changed components the operation uses, directly or indirectly.
+	ReferencedChanges []*ReferencedChange
+
+	// Examples are generated examples of the changed request and response bodies, they are only set by
+	// GenerateExamples and are rendered after the changes.
+	Examples []*BodyExample
+}
+
+// ExtractOperationChanges returns the changes that affect a single operation, including changes to the components
+// it references. The document is the updated version of the specification, and is used to find the components the
+// operation references; when it is nil, referenced component changes are not included.
+func ExtractOperationChanges(docChanges *model.DocumentChanges, drDoc *drModel.DrDocument, path, method string) *OperationChangeReport {
+	method = strings.ToLower(method)
+	report := &OperationChangeReport{Path: path, Method: method}
+	if docChanges == nil {
+		return report
+	}
+	if docChanges.PathsChanges != nil {
+		if pic := docChanges.PathsChanges.PathItemsChanges[path]; pic != nil {
+			report.Operation = operationChanges(pic, method)
+		}
+	}
+
+	prefix := pathSegments(appendKey("$.paths", path))
+	for _, c := range LocateChanges(docChanges) {
+		segments := pathSegments(c.PropertyPath())
+		if !pathContains(prefix, segments) {
+			continue
+		}
+		// skip changes made to the other operations of the path item.
+		if len(segments) > len(prefix) && slices.Contains(drBase.HTTPMethods, segments[len(prefix)]) &&
+			segments[len(prefix)] != method {
+			continue
+		}
+		report.Changes = append(report.Changes, c)
+	}
+
+	for _, rc := range ReferencedChanges(docChanges, drDoc) {
+		matches := func(po *drModel.PathOperation) bool {
+			return !po.Webhook && po.Path == path && po.Method == method
+		}
+		if slices.ContainsFunc(rc.Operations, matches) || slices.ContainsFunc(rc.IndirectOperations, matches) {
+			report.ReferencedChanges = append(report.ReferencedChanges, rc)
+		}
+	}
+	return report
+}
+
+// TotalChanges returns the number of changes that affect the operation, including referenced component changes.
+func (r *OperationChangeReport) TotalChanges() int {
+	total := len(r.Changes)
+	for _, rc := range r.ReferencedChanges {
+		total += len(rc.Changes)
+	}
+	return total
+}
+
+// TotalBreakingChanges returns the number of breaking changes that affect the operation, including referenced
+// component changes.
+func (r *OperationChangeReport) TotalBreakingChanges() int {
+	total := 0
+	count := func(changes []*LocatedChange) {
+		for _, c := range changes {
+			if c.Change.Breaking {
+				total++
+			}
+		}
+	}
+	count(r.Changes)
+	for _, rc := range r.ReferencedChanges {
+		count(rc.Changes)
+	}
+	return total
+}
+
+// Markdown renders the changes that affect the operation, as a page section for the operation. Each change is
+// tagged with its anchor (see LocatedChange.Anchor) in an HTML comment.
+func (r *OperationChangeReport) Markdown() string {
+	return r.markdown(anchors{})
+}
+
+func (r *OperationChangeReport) markdown(ids anchors) string {
+	buf := strings.Builder{}
+	buf.WriteString(fmt.Sprintf("## `%s %s`\n\n", strings.ToUpper(r.Method), r.Path))
+	buf.WriteString(fmt.Sprintf("%d changes, %d breaking.\n", r.TotalChanges(), r.TotalBreakingChanges()))
+	table := func(changes []*LocatedChange) {
+		buf.WriteString("\n" + markdownTableHeader)
+		for _, c := range changes {
+			buf.WriteString(markdownRow(c, ids.next(c)))
+		}
+	}
+	if len(r.Changes) > 0 {
+		table(r.Changes)
+	}
+	for _, rc := range r.ReferencedChanges {
+		buf.WriteString(fmt.Sprintf("\n### `%s`\n", rc.Component))
+		table(rc.Changes)
+	}
+	for _, e := range r.Examples {
+		buf.WriteString(e.markdown())
+	}
+	return buf.String()
+}
+
+func operationChanges(pic *model.PathItemChanges, method string) *model.OperationChanges {
+	switch method {
+	case "get":
+		return pic.GetChanges
+	case "put":
+		return pic.PutChanges
+	case "post":
+		return pic.PostChanges
+	case "delete":
+		return pic.DeleteChanges
+	case "options":
+		return pic.OptionsChanges
+	case "head":
+		return pic.HeadChanges
+	case "patch":
+		return pic.PatchChanges
+	case "trace":
+		return pic.TraceChanges
+	}
+	return nil
+}
+
+const markdownTableHeader = "| Change | Location | Original | New | Breaking |\n|--------|----------|----------|-----|----------|\n"
+
+// markdownRow renders a change as a table row, tagged with its anchor. The location includes the file of the
+// change, when it is known, acknowledged changes are marked, and the findings of the change are rendered as rows
+// beneath it.
+func markdownRow(c *LocatedChange, anchor string) string {
+	breaking := ""
+	if c.Change.Breaking {
+		breaking = "yes"
+	}
+	location := "`" + c.PropertyPath() + "`"
+	if c.File != "" {
+		location += " in `" + c.File + "`"
+	}
+	changeType := changeTypeLabel(c)
+	if c.Acknowledged != nil {
+		changeType += " _(acknowledged)_"
+	}
+	return fmt.Sprintf("| %s <!-- %s --> | %s | %s | %s | %s |\n", changeType, anchor,
+		location, markdownValue(c.Change.Original), markdownValue(c.Change.New), breaking) + markdownFindingRows(c)
+}
+
+// markdownValue renders a changed value inside a table cell.
+func markdownValue(value string) string {
+	if value == "" {
+		return ""
+	}
+	value = strings.ReplaceAll(strings.ReplaceAll(value, "|", "\\|"), "\n", " ")
+	return "`" + value + "`"
+}
@@ -0,0 +1,45 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	drModel "github.com/pb33f/doctor/model"
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestExtractOperationChanges(t *testing.T) {
+	updated := strings.Replace(customerSpec, "street:\n          type: string", "street:\n          type: integer", 1)
+	updated = strings.Replace(updated, "description: customers", "description: all the customers", 1)
+	updated = strings.Replace(updated, "description: addresses", "description: all the addresses", 1)
+	changes := compare(t, customerSpec, updated)
+
+	doc, _ := libopenapi.NewDocument([]byte(updated))
+	v3Doc, _ := doc.BuildV3Model()
+	drDoc := drModel.NewDrDocument(v3Doc)
+
+	report := ExtractOperationChanges(changes, drDoc, "/customers", "GET")
+	assert.Equal(t, "get", report.Method)
+	require.NotNil(t, report.Operation)
+	require.Len(t, report.Changes, 1)
+	assert.Equal(t, "$.paths['/customers'].get.responses['200'].description", report.Changes[0].PropertyPath())
+	require.Len(t, report.ReferencedChanges, 1)
+	assert.Equal(t, "#/components/schemas/Address", report.ReferencedChanges[0].Component)
+	assert.Equal(t, 2, report.TotalChanges())
+	assert.Equal(t, 1, report.TotalBreakingChanges())
+
+	md := report.Markdown()
+	assert.Contains(t, md, "## `GET /customers`")
+	assert.Contains(t, md, "| modified <!-- "+report.Changes[0].Anchor()+" --> | "+
+		"`$.paths['/customers'].get.responses['200'].description` | `customers` | `all the customers` |  |")
+	assert.Contains(t, md, "### `#/components/schemas/Address`")
+
+	// an operation that did not change, and does not use a changed component.
+	report = ExtractOperationChanges(changes, drDoc, "/status", "get")
+	assert.Nil(t, report.Operation)
+	assert.Zero(t, report.TotalChanges())
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"github.com/pb33f/libopenapi/what-changed/model"
+	"regexp"
+)
+
+// Redacted replaces the secrets found by the built-in secret patterns.
+const Redacted = "[REDACTED]"
+
+// SecretPattern finds a secret in a value, matches are replaced with Replacement, which can refer to the groups of
+// the pattern (see regexp.Regexp.ReplaceAllString).
+type SecretPattern struct {
+	Name        string
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// SecretPatterns are the built-in patterns used by RedactSecrets, they find common secret formats: authorization
+// header credentials, JWTs, private keys, credentials in URLs, and the tokens of well known providers.
+var SecretPatterns = []*SecretPattern{
+	{Name: "private-key", Pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+		Replacement: Redacted},
+	{Name: "authorization", Pattern: regexp.MustCompile(`(?i)\b(bearer|basic|token)\s+[A-Za-z0-9\-._~+/]{8,}=*`),
+		Replacement: "$1 " + Redacted},
+	{Name: "jwt", Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+		Replacement: Redacted},
+	{Name: "url-credentials", Pattern: regexp.MustCompile(`(://[^/\s:@]+:)[^/\s@]+@`),
+		Replacement: "${1}" + Redacted + "@"},
+	{Name: "aws-access-key", Pattern: regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`), Replacement: Redacted},
+	{Name: "github-token", Pattern: regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`),
+		Replacement: Redacted},
+	{Name: "slack-token", Pattern: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`), Replacement: Redacted},
+	{Name: "stripe-key", Pattern: regexp.MustCompile(`\b[rs]k_(live|test)_[A-Za-z0-9]{16,}\b`), Replacement: Redacted},
+	{Name: "google-api-key", Pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`), Replacement: Redacted},
+}
+
+// RedactSecrets is a redactor (see RenderConfig.Redactor) that replaces every secret found by SecretPatterns.
+func RedactSecrets(path, value string) string {
+	return redactPatterns(value, SecretPatterns)
+}
+
+// NewPatternRedactor creates a redactor (see RenderConfig.Redactor) that replaces every match of the patterns, for
+// secret formats the built-in patterns do not know about.
+func NewPatternRedactor(patterns ...*SecretPattern) func(path, value string) string {
+	return func(path, value string) string {
+		return redactPatterns(value, patterns)
+	}
+}
+
+func redactPatterns(value string, patterns []*SecretPattern) string {
+	for _, p := range patterns {
+		value = p.Pattern.ReplaceAllString(value, p.Replacement)
+	}
+	return value
+}
+
+// redactChanges redacts the original and new values of every change, the path of a value is the path of the
+// property that changed. Paths are read before any value is redacted, as they can contain values.
+func redactChanges(located []*LocatedChange, redactor func(path, value string) string) {
+	paths := make([]string, len(located))
+	for i, c := range located {
+		paths[i] = c.PropertyPath()
+	}
+	seen := make(map[*model.Change]bool)
+	for i, c := range located {
+		if seen[c.Change] {
+			continue
+		}
+		seen[c.Change] = true
+		if c.Change.Original != "" {
+			c.Change.Original = redactor(paths[i], c.Change.Original)
+		}
+		if c.Change.New != "" {
+			c.Change.New = redactor(paths[i], c.Change.New)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"fmt"
+	drModel "github.com/pb33f/doctor/model"
+	"github.com/pb33f/libopenapi/what-changed/model"
+	"gopkg.in/yaml.v3"
+	"slices"
+	"strings"
+)
+
+// ReferencedChange is a changed component, and the operations it affects. Operations reference the component
+// directly, IndirectOperations reference it through other components (for example a changed `Address` schema
+// used by a `Customer` schema affects every operation that uses `Customer`).
+type ReferencedChange struct {
+	Component          string
+	Changes            []*LocatedChange
+	Operations         []*drModel.PathOperation
+	IndirectOperations []*drModel.PathOperation
+}
+
+// ReferencedChanges groups the changes made to components by the component they were made to, and finds every
+// operation in the document that uses each changed component, directly or transitively through the references
+// between components. The document should be the updated version of the specification.
+//
+// Components are returned in the order their first change is located.
+func ReferencedChanges(docChanges *model.DocumentChanges, drDoc *drModel.DrDocument) []*ReferencedChange {
+	var referenced []*ReferencedChange
+	byComponent := make(map[string]*ReferencedChange)
+	for _, c := range LocateChanges(docChanges) {
+		def := componentForPath(c.Path)
+		if def == "" {
+			continue
+		}
+		rc, ok := byComponent[def]
+		if !ok {
+			rc = &ReferencedChange{Component: def}
+			byComponent[def] = rc
+			referenced = append(referenced, rc)
+		}
+		rc.Changes = append(rc.Changes, c)
+	}
+	if len(referenced) == 0 || drDoc == nil {
+		return referenced
+	}
+
+	for _, po := range drDoc.Operations() {
+		if po.Operation == nil || po.Operation.Value == nil || po.Operation.Value.GoLow() == nil {
+			continue
+		}
+		node := po.Operation.Value.GoLow().RootNode
+		direct := directReferences(node)
+		var transitive []string
+		for _, rc := range referenced {
+			if slices.Contains(direct, rc.Component) {
+				rc.Operations = append(rc.Operations, po)
+				continue
+			}
+			if transitive == nil {
+				transitive = drDoc.ReferencedComponents(node)
+			}
+			if slices.Contains(transitive, rc.Component) {
+				rc.IndirectOperations = append(rc.IndirectOperations, po)
+			}
+		}
+	}
+	return referenced
+}
+
+// Markdown renders the changed component, and the operations it affects directly and indirectly.
+func (rc *ReferencedChange) Markdown() string {
+	buf := strings.Builder{}
+	buf.WriteString(fmt.Sprintf("### `%s`\n\n", rc.Component))
+	buf.WriteString(fmt.Sprintf("%d changes.\n", len(rc.Changes)))
+	list := func(title string, ops []*drModel.PathOperation) {
+		if len(ops) == 0 {
+			return
+		}
+		buf.WriteString(fmt.Sprintf("\n**%s**\n\n", title))
+		for _, po := range ops {
+			op := fmt.Sprintf("`%s %s`", strings.ToUpper(po.Method), po.Path)
+			if po.Webhook {
+				op += " (webhook)"
+			}
+			buf.WriteString(fmt.Sprintf("- %s\n", op))
+		}
+	}
+	list("Affected operations", rc.Operations)
+	list("Indirectly affected operations", rc.IndirectOperations)
+	return buf.String()
+}
+
+// componentForPath returns the component definition (`#/components/schemas/Burger`) that a change located
+// at a JSONPath was made to, or an empty string if the change was not made to a component.
+func componentForPath(path string) string {
+	segments := pathSegments(path)
+	if len(segments) < 3 || segments[0] != "components" {
+		return ""
+	}
+	name := strings.ReplaceAll(strings.ReplaceAll(segments[2], "~", "~0"), "/", "~1")
+	return "#/components/" + segments[1] + "/" + name
+}
+
+// directReferences returns the components referenced by a node, without following the references.
+func directReferences(node *yaml.Node) []string {
+	var found []string
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		if n == nil {
+			return
+		}
+		if n.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if n.Content[i].Value == "$ref" && n.Content[i+1].Kind == yaml.ScalarNode {
+					if def := drModel.ComponentDefinition(n.Content[i+1].Value); def != "" && !slices.Contains(found, def) {
+						found = append(found, def)
+					}
+				}
+			}
+		}
+		for _, c := range n.Content {
+			walk(c)
+		}
+	}
+	walk(node)
+	return found
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	drModel "github.com/pb33f/doctor/model"
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const customerSpec = `openapi: 3.1.0
+info:
+  title: customers
+  version: 1.0.0
+paths:
+  /customers:
+    get:
+      responses:
+        "200":
+          description: customers
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Customer'
+  /addresses:
+    get:
+      responses:
+        "200":
+          description: addresses
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Address'
+  /status:
+    get:
+      responses:
+        "200":
+          description: ok
+components:
+  schemas:
+    Customer:
+      type: object
+      properties:
+        address:
+          $ref: '#/components/schemas/Address'
+    Address:
+      type: object
+      properties:
+        street:
+          type: string`
+
+func TestReferencedChanges(t *testing.T) {
+	updated := strings.Replace(customerSpec, "street:\n          type: string", "street:\n          type: integer", 1)
+	changes := compare(t, customerSpec, updated)
+
+	doc, _ := libopenapi.NewDocument([]byte(updated))
+	v3Doc, _ := doc.BuildV3Model()
+	drDoc := drModel.NewDrDocument(v3Doc)
+
+	referenced := ReferencedChanges(changes, drDoc)
+	require.Len(t, referenced, 1)
+	address := referenced[0]
+	assert.Equal(t, "#/components/schemas/Address", address.Component)
+	assert.Len(t, address.Changes, 1)
+	require.Len(t, address.Operations, 1)
+	assert.Equal(t, "/addresses", address.Operations[0].Path)
+	require.Len(t, address.IndirectOperations, 1)
+	assert.Equal(t, "/customers", address.IndirectOperations[0].Path)
+
+	// without a document, changes are still grouped.
+	assert.Len(t, ReferencedChanges(changes, nil), 1)
+}
+
+func TestReferencedChange_Markdown(t *testing.T) {
+	updated := strings.Replace(customerSpec, "street:\n          type: string", "street:\n          type: integer", 1)
+	doc, _ := libopenapi.NewDocument([]byte(updated))
+	v3Doc, _ := doc.BuildV3Model()
+
+	referenced := ReferencedChanges(compare(t, customerSpec, updated), drModel.NewDrDocument(v3Doc))
+	require.Len(t, referenced, 1)
+	assert.Equal(t, "### `#/components/schemas/Address`\n\n1 changes.\n\n**Affected operations**\n\n- `GET /addresses`\n"+
+		"\n**Indirectly affected operations**\n\n- `GET /customers`\n", referenced[0].Markdown())
+}
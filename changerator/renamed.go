@@ -0,0 +1,138 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"fmt"
+	drModel "github.com/pb33f/doctor/model"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/what-changed/model"
+	"html"
+	"reflect"
+	"strings"
+)
+
+// RenamedComponent is a component schema that was removed from the original specification, and added to the
+// updated specification under a new name with the same structure. Removed and Added are the changes that
+// what-changed reports for the rename, Changes are the changes between the original and the renamed schema
+// (documentation, such as descriptions and examples, is not part of the structure, so it can still change).
+type RenamedComponent struct {
+	From    string
+	To      string
+	Removed *LocatedChange
+	Added   *LocatedChange
+	Changes []*LocatedChange
+}
+
+// DetectRenames finds component schemas that were renamed between two versions of a specification. A removed
+// schema and an added schema are matched when their structure hashes (see drModel.NewSchemaShape) are the same,
+// and no other removed or added schema has that structure, so an ambiguous match is left as a removal and an
+// addition. The documents are the original and the updated versions of the specification.
+//
+// Renames are returned in the order the removals are located.
+func DetectRenames(docChanges *model.DocumentChanges, left, right *drModel.DrDocument) []*RenamedComponent {
+	if docChanges == nil || docChanges.ComponentsChanges == nil {
+		return nil
+	}
+	var removed, added []*LocatedChange
+	for _, c := range LocateChanges(docChanges) {
+		if c.Path != "$.components" || c.Change.Property != "schemas" {
+			continue
+		}
+		switch c.Change.ChangeType {
+		case model.ObjectRemoved:
+			removed = append(removed, c)
+		case model.ObjectAdded:
+			added = append(added, c)
+		}
+	}
+	if len(removed) == 0 || len(added) == 0 {
+		return nil
+	}
+
+	// the structure hash of each removed and added schema, and the changes with each hash.
+	leftSchemas, rightSchemas := componentSchemas(left), componentSchemas(right)
+	hashes := make(map[*LocatedChange]string)
+	removedHashes, addedHashes := make(map[string][]*LocatedChange), make(map[string][]*LocatedChange)
+	for _, c := range removed {
+		if s := leftSchemas[c.Change.Original]; s != nil && s.Schema != nil {
+			hashes[c] = drModel.NewSchemaShape(s.Schema).Hash
+			removedHashes[hashes[c]] = append(removedHashes[hashes[c]], c)
+		}
+	}
+	for _, c := range added {
+		if s := rightSchemas[c.Change.New]; s != nil && s.Schema != nil {
+			hashes[c] = drModel.NewSchemaShape(s.Schema).Hash
+			addedHashes[hashes[c]] = append(addedHashes[hashes[c]], c)
+		}
+	}
+
+	var renamed []*RenamedComponent
+	for _, r := range removed {
+		hash, ok := hashes[r]
+		if !ok || len(removedHashes[hash]) != 1 || len(addedHashes[hash]) != 1 {
+			continue
+		}
+		a := addedHashes[hash][0]
+		rc := &RenamedComponent{From: r.Change.Original, To: a.Change.New, Removed: r, Added: a}
+		from, to := leftSchemas[rc.From], rightSchemas[rc.To]
+		if from.Value != nil && to.Value != nil {
+			if sc := model.CompareSchemas(from.Value.GoLow(), to.Value.GoLow()); sc != nil {
+				locateChanges(reflect.ValueOf(sc), appendKey("$.components.schemas", rc.To), &rc.Changes)
+			}
+		}
+		renamed = append(renamed, rc)
+	}
+	return renamed
+}
+
+// Markdown renders the rename as a list item, with the changes made to the schema nested beneath it.
+func (r *RenamedComponent) Markdown() string {
+	return r.markdown(anchors{})
+}
+
+func (r *RenamedComponent) markdown(ids anchors) string {
+	buf := strings.Builder{}
+	buf.WriteString(fmt.Sprintf("- renamed `%s` → `%s`\n", r.From, r.To))
+	for _, c := range r.Changes {
+		line := fmt.Sprintf("%s `%s`", ChangeTypeName(c.Change.ChangeType), c.PropertyPath())
+		if c.Change.Original != "" || c.Change.New != "" {
+			line += fmt.Sprintf(": %s → %s", markdownValue(c.Change.Original), markdownValue(c.Change.New))
+		}
+		if c.Change.Breaking {
+			line += " (breaking)"
+		}
+		buf.WriteString(fmt.Sprintf("  - %s <!-- %s -->\n", line, ids.next(c)))
+	}
+	return buf.String()
+}
+
+// html renders the rename as a list item, with a table of the changes made to the schema nested beneath it.
+func (r *RenamedComponent) html(ids anchors) string {
+	buf := strings.Builder{}
+	buf.WriteString(fmt.Sprintf("<li>renamed <code>%s</code> → <code>%s</code>", html.EscapeString(r.From),
+		html.EscapeString(r.To)))
+	if len(r.Changes) > 0 {
+		buf.WriteString("\n<table>\n" + htmlTableHeader)
+		for _, c := range r.Changes {
+			buf.WriteString(htmlRow(c, ids.next(c)))
+		}
+		buf.WriteString("</table>\n")
+	}
+	buf.WriteString("</li>\n")
+	return buf.String()
+}
+
+// componentSchemas returns the component schemas of a document, by name.
+func componentSchemas(drDoc *drModel.DrDocument) map[string]*drBase.SchemaProxy {
+	schemas := make(map[string]*drBase.SchemaProxy)
+	if drDoc == nil || drDoc.V3Document == nil || drDoc.V3Document.Components == nil ||
+		drDoc.V3Document.Components.Schemas == nil {
+		return schemas
+	}
+	for name, s := range drDoc.V3Document.Components.Schemas.FromOldest() {
+		schemas[name] = s
+	}
+	return schemas
+}
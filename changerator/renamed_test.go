@@ -0,0 +1,92 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	drModel "github.com/pb33f/doctor/model"
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const renameSpec = `openapi: 3.1.0
+info:
+  title: burgers
+  version: 1.0.0
+paths:
+  /burgers:
+    get:
+      responses:
+        "200":
+          description: burgers
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Burger'
+components:
+  schemas:
+    Burger:
+      type: object
+      description: a burger
+      properties:
+        name:
+          type: string
+        price:
+          type: number
+    Fries:
+      type: object
+      properties:
+        salted:
+          type: boolean`
+
+func drDocument(t *testing.T, spec string) *drModel.DrDocument {
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	v3Doc, _ := doc.BuildV3Model()
+	require.NotNil(t, v3Doc)
+	return drModel.NewDrDocument(v3Doc)
+}
+
+func TestDetectRenames(t *testing.T) {
+	updated := strings.ReplaceAll(renameSpec, "Burger", "Sandwich")
+	updated = strings.Replace(updated, "description: a burger", "description: a sandwich", 1)
+	updated = strings.Replace(updated, "Fries:\n      type: object\n      properties:\n        salted:\n          type: boolean",
+		"Chips:\n      type: object\n      properties:\n        salted:\n          type: integer", 1)
+	changes := compare(t, renameSpec, updated)
+
+	renamed := DetectRenames(changes, drDocument(t, renameSpec), drDocument(t, updated))
+	require.Len(t, renamed, 1)
+	r := renamed[0]
+	assert.Equal(t, "Burger", r.From)
+	assert.Equal(t, "Sandwich", r.To)
+	assert.Equal(t, "$.components.schemas['Burger']", r.Removed.PropertyPath())
+	assert.Equal(t, "$.components.schemas['Sandwich']", r.Added.PropertyPath())
+	require.Len(t, r.Changes, 1)
+	assert.Equal(t, "$.components.schemas['Sandwich'].description", r.Changes[0].PropertyPath())
+
+	md := r.Markdown()
+	assert.True(t, strings.HasPrefix(md, "- renamed `Burger` → `Sandwich`\n"))
+	assert.Contains(t, md, "  - modified `$.components.schemas['Sandwich'].description`: `a burger` → `a sandwich`")
+
+	// ambiguous matches are not renames.
+	updated = strings.Replace(renameSpec, "Fries:", "Sides:", 1)
+	updated = strings.Replace(updated, "    Burger:", "    Chips:\n      type: object\n      properties:\n        salted:\n          type: boolean\n    Burger:", 1)
+	assert.Empty(t, DetectRenames(compare(t, renameSpec, updated), drDocument(t, renameSpec), drDocument(t, updated)))
+	assert.Nil(t, DetectRenames(nil, nil, nil))
+}
+
+func TestReport_Renamed(t *testing.T) {
+	updated := strings.ReplaceAll(renameSpec, "Fries", "Sides")
+
+	md, err := Report([]byte(renameSpec), []byte(updated), FormatMarkdown, nil)
+	require.NoError(t, err)
+	assert.Contains(t, string(md), "## Renamed components\n\n- renamed `Fries` → `Sides`\n")
+	assert.NotContains(t, string(md), "## Other changes")
+
+	h, err := Report([]byte(renameSpec), []byte(updated), FormatHTML, nil)
+	require.NoError(t, err)
+	assert.Contains(t, string(h), "<li>renamed <code>Fries</code> → <code>Sides</code></li>")
+}
@@ -0,0 +1,400 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pb33f/doctor/events"
+	drModel "github.com/pb33f/doctor/model"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/doctor/telemetry"
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	whatChanged "github.com/pb33f/libopenapi/what-changed"
+	"github.com/pb33f/libopenapi/what-changed/model"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Format is the output format of a report.
+type Format string
+
+const (
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+	FormatJSON     Format = "json"
+
+	// FormatDiff is a unified diff of the raw specifications, with a hunk for every change (see Differ).
+	FormatDiff Format = "diff"
+)
+
+// RenderConfig controls how a report is built and rendered.
+type RenderConfig struct {
+	// Group controls how operation changes are grouped into sections, by tag by default.
+	Group GroupConfig
+
+	// Methods controls which operations are rendered, and the order the operations of a path are rendered in, for
+	// example to leave out trace operations. It is used when Group.Methods is nil.
+	Methods *drBase.MethodOrder
+
+	// DocumentConfiguration is used to parse both specifications, for example to resolve file references.
+	// OriginalDocumentConfiguration is used to parse the original specification instead when it is set, for
+	// specifications split across files where each version has its own directory.
+	DocumentConfiguration         *datamodel.DocumentConfiguration
+	OriginalDocumentConfiguration *datamodel.DocumentConfiguration
+
+	// Examples embeds a generated example of every changed request and response body in markdown and HTML
+	// reports, beneath the changes to its operation (see OperationChangeReport.GenerateExamples).
+	Examples bool
+
+	// TopImpact adds a section ranking the most impactful changes (see ScoreChanges) to the report, with up to
+	// TopImpact changes, scored with ImpactWeights (DefaultImpactWeights when nil). No section is added when zero.
+	TopImpact     int
+	ImpactWeights *ImpactWeights
+
+	// DiffContext is the number of unchanged lines around the changed lines of each hunk of a diff report, three
+	// when zero.
+	DiffContext int
+
+	// Redactor is applied to every value a report renders (the original and new values of changes, generated
+	// examples and the lines of a diff), with the JSONPath of the value, and returns the value to render, for
+	// example to hide tokens in example headers (see RedactSecrets). Values are rendered as they are when nil.
+	Redactor func(path, value string) string
+
+	// Findings are attached to the updated specification (see DrDocument.AttachFindings), for example the results
+	// of linting it. Findings on objects that changed are reported with the changes.
+	Findings []*drBase.Finding
+
+	// InterleaveFindings renders the findings of markdown and HTML reports beneath the changes to their objects,
+	// rather than in a section of their own, so reviewers read changes and lint results in one place. The
+	// findings on an added object include the findings on everything beneath it. Findings that are not on a
+	// changed object are listed last, under other findings.
+	InterleaveFindings bool
+
+	// Events receives the progress of the report, and of the walks of both specifications, on the
+	// events.Progress and events.Warning topics. Nothing is published when nil.
+	Events *events.Bus
+
+	// TracerProvider traces the report, and the walks of both specifications (see drModel.DrConfig), and records
+	// how long it took to render when it is also a telemetry.MeterProvider. Nothing is traced when nil.
+	TracerProvider telemetry.TracerProvider
+
+	// MaxMemoryBytes is the memory budget of the walk of each specification (see drModel.DrConfig), a report
+	// fails when either walk exceeds it. There is no budget when zero.
+	MaxMemoryBytes int64
+
+	// Suppressions acknowledge known changes (see GateConfig.Suppressions). Acknowledged changes are still
+	// reported, marked as acknowledged where they are rendered and listed with the reasons they were accepted.
+	Suppressions *SuppressionList
+
+	// Deprecations adds a section listing the operations, parameters and schema properties the updated
+	// specification deprecates that the original did not (see drModel.DeprecationReport.NewlyDeprecated).
+	Deprecations bool
+
+	// Categories limits a report to the changes in these categories (see LocatedChange.Category), for example
+	// CategoryEnum to review only the values added to and removed from enums. Totals count the reported changes.
+	// Every change is reported when empty.
+	Categories []string
+
+	// ValueDiffs renders a diff of the original and new values of every modified change beneath its row in HTML
+	// reports, as they are written in the specifications (see Differ.Hunk), with DiffContext lines of context.
+	// Removed and added lines have the diff-remove and diff-add classes, which the report styles.
+	ValueDiffs bool
+}
+
+// Report compares two versions of a specification and renders a report of the changes in a single call. The
+// report contains a section for each group of operations (see GroupChanges), followed by the changes that do
+// not belong to any operation (such as changes to the info object, or components no operation uses). Component
+// schemas that were renamed (see DetectRenames) are reported as renames, rather than a removal and an addition.
+// Examples that are no longer valid because their schema changed are reported next (see DetectExampleDrift),
+// followed by the findings of cfg.Findings on objects that changed (or with the changes, see
+// RenderConfig.InterleaveFindings).
+// When the changes were made in more than one file of a specification split across files, every change is
+// annotated with its file (see Attachments.File), and cfg.Group can group them by file. cfg.Methods leaves out
+// or reorders operations by method, in every format.
+// When cfg.TopImpact is set, the most impactful changes are ranked at the top of the report. A nil cfg groups by
+// tag. When the original specification is empty, or has no paths, webhooks or components, every change would be an
+// addition, so an initial release report summarizing the updated specification is rendered instead (see
+// SummarizeRelease). A report fails when either specification can't be parsed or completely walked.
+func Report(leftSpec, rightSpec []byte, format Format, cfg *RenderConfig) (rendered []byte, err error) {
+	if cfg == nil {
+		cfg = &RenderConfig{}
+	}
+	if format != FormatMarkdown && format != FormatHTML && format != FormatJSON && format != FormatDiff {
+		return nil, fmt.Errorf("unknown report format '%s'", format)
+	}
+	cfg.progress(0, "parsing specifications")
+	start := time.Now()
+	instruments := telemetry.Instrument(cfg.TracerProvider, telemetryScope)
+	ctx, span := instruments.Start(context.Background(), telemetry.SpanRender, telemetry.String("format", string(format)))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+		} else {
+			cfg.progress(reportSteps, fmt.Sprintf("rendered %s report", format))
+		}
+		span.End()
+		instruments.Duration(ctx, telemetry.MetricRenderDuration, start, telemetry.String("format", string(format)))
+	}()
+	var left *libopenapi.DocumentModel[v3.Document]
+	if !emptyBaseline(leftSpec, nil) {
+		if left, err = buildModel("original", leftSpec, cfg.originalConfiguration()); err != nil {
+			return nil, err
+		}
+	}
+	right, err := buildModel("updated", rightSpec, cfg.DocumentConfiguration)
+	if err != nil {
+		return nil, err
+	}
+	drDoc, err := cfg.walk(ctx, "updated", right)
+	if err != nil {
+		return nil, err
+	}
+	if left == nil || emptyBaseline(leftSpec, left) {
+		cfg.progress(2, fmt.Sprintf("rendering %s report", format))
+		return initialReport(rightSpec, drDoc, format, cfg)
+	}
+	cfg.progress(1, "comparing specifications")
+	docChanges := whatChanged.CompareOpenAPIDocuments(left.Model.GoLow(), right.Model.GoLow())
+	leftDoc, err := cfg.walk(ctx, "original", left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := analyzeChanges(docChanges, leftSpec, rightSpec, leftDoc, drDoc, format, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.progress(2, fmt.Sprintf("rendering %s report", format))
+	switch format {
+	case FormatDiff:
+		return r.diff(), nil
+	case FormatJSON:
+		return r.json()
+	case FormatHTML:
+		return r.html(), nil
+	default:
+		return r.markdown(), nil
+	}
+}
+
+// changeReport is everything a report renders, analyzed once for every format (see Report).
+type changeReport struct {
+	cfg                 *RenderConfig
+	leftSpec, rightSpec []byte
+	docChanges          *model.DocumentChanges
+	leftDoc, drDoc      *drModel.DrDocument
+	groups              *ChangeGroups
+	renamed             []*RenamedComponent
+
+	// located are all the changes, other are the changes that are not reported with an operation or a rename.
+	located, other []*LocatedChange
+	acknowledged   []*LocatedChange
+	attached       *Attachments
+	drift          []*ExampleDrift
+	deprecated     []*drModel.Deprecation
+	findings       []*changedFinding
+	otherFindings  []*drBase.Finding
+	impact         []*ScoredChange
+	redact         func(path, value string) string
+
+	total, breaking int
+}
+
+// analyzeChanges groups, locates and annotates document changes for a report in a format.
+func analyzeChanges(docChanges *model.DocumentChanges, leftSpec, rightSpec []byte, leftDoc, drDoc *drModel.DrDocument,
+	format Format, cfg *RenderConfig) (*changeReport, error) {
+	r := &changeReport{cfg: cfg, leftSpec: leftSpec, rightSpec: rightSpec, docChanges: docChanges, leftDoc: leftDoc,
+		drDoc: drDoc}
+	r.groups = GroupChanges(docChanges, drDoc, cfg.groupConfig())
+	r.renamed = DetectRenames(docChanges, leftDoc, drDoc)
+	if cfg.Examples && (format == FormatMarkdown || format == FormatHTML) {
+		for _, g := range r.groups.Groups {
+			for _, op := range g.Operations {
+				if err := op.GenerateExamples(drDoc); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	// changes that are not reported with an operation.
+	grouped := make(map[*model.Change]bool)
+	for _, g := range r.groups.Groups {
+		for _, op := range g.Operations {
+			for _, c := range op.Changes {
+				grouped[c.Change] = true
+			}
+			for _, rc := range op.ReferencedChanges {
+				for _, c := range rc.Changes {
+					grouped[c.Change] = true
+				}
+			}
+		}
+	}
+	for _, rn := range r.renamed {
+		grouped[rn.Removed.Change] = true
+		grouped[rn.Added.Change] = true
+	}
+	r.located = LocateChanges(docChanges)
+	annotated := [][]*LocatedChange{r.located}
+	for _, g := range r.groups.Groups {
+		for _, op := range g.Operations {
+			annotated = append(annotated, op.Changes)
+			for _, rc := range op.ReferencedChanges {
+				annotated = append(annotated, rc.Changes)
+			}
+		}
+	}
+	for _, rn := range r.renamed {
+		annotated = append(annotated, rn.Changes, []*LocatedChange{rn.Removed, rn.Added})
+	}
+	annotateFiles(&Attachments{drDoc: drDoc}, annotated...)
+	r.acknowledged = annotateSuppressions(cfg.Suppressions, time.Now(), annotated...)
+	if cfg.ValueDiffs && format == FormatHTML {
+		annotateDiffs(r.differ(), annotated...)
+	}
+	r.drift = DetectExampleDrift(docChanges, leftDoc, drDoc)
+	if cfg.Deprecations {
+		r.deprecated = drDoc.DeprecationReport().NewlyDeprecated(leftDoc.DeprecationReport())
+	}
+	drDoc.AttachFindings(cfg.Findings)
+	r.attached = AttachChanges(docChanges, drDoc)
+	if cfg.InterleaveFindings {
+		interleaved := interleaveFindings(r.attached, annotated...)
+		for _, f := range cfg.Findings {
+			if f != nil && !interleaved[f] {
+				r.otherFindings = append(r.otherFindings, f)
+			}
+		}
+	} else {
+		r.findings = changedFindings(r.located, r.attached)
+	}
+	r.redact = cfg.Redactor
+	if r.redact == nil {
+		r.redact = func(path, value string) string { return value }
+	}
+	if cfg.Redactor != nil {
+		redactChanges(r.located, cfg.Redactor)
+		for _, g := range r.groups.Groups {
+			for _, op := range g.Operations {
+				for _, e := range op.Examples {
+					e.Payload = cfg.Redactor(e.Path, e.Payload)
+				}
+			}
+		}
+	}
+	for _, c := range r.located {
+		if !grouped[c.Change] {
+			r.other = append(r.other, c)
+		}
+	}
+	if cfg.TopImpact > 0 {
+		scored := slices.DeleteFunc(ScoreChanges(docChanges, drDoc, cfg.ImpactWeights), func(sc *ScoredChange) bool {
+			return !inCategories(cfg.Categories, sc.LocatedChange)
+		})
+		r.impact = TopChanges(scored, cfg.TopImpact)
+	}
+	if docChanges != nil {
+		r.total, r.breaking = docChanges.TotalChanges(), docChanges.TotalBreakingChanges()
+	}
+	r.filterCategories(cfg.Categories)
+	return r, nil
+}
+
+// summary counts the changes of the report, and the changes that were acknowledged when there are any.
+func (r *changeReport) summary() string {
+	summary := fmt.Sprintf("%d changes, %d breaking", r.total, r.breaking)
+	if len(r.acknowledged) > 0 {
+		summary += fmt.Sprintf(", %d acknowledged", len(r.acknowledged))
+	}
+	return summary
+}
+
+// deprecationOperation renders the operation a deprecated element belongs to, quoted with quote, or nothing for
+// component schemas.
+func deprecationOperation(d *drModel.Deprecation, quote string) string {
+	if d.Operation == nil {
+		return ""
+	}
+	return quote + strings.ToUpper(d.Operation.Method) + " " + d.Operation.Path + quote
+}
+
+// differ returns a differ of the specifications, with the context and redactor of the configuration.
+func (r *changeReport) differ() *Differ {
+	differ := NewDiffer(r.leftSpec, r.rightSpec, r.leftDoc, r.drDoc)
+	if r.cfg.DiffContext > 0 {
+		differ.Context = r.cfg.DiffContext
+	}
+	differ.Redactor = r.cfg.Redactor
+	return differ
+}
+
+// diff renders the report as a unified diff of the specifications (see Differ).
+func (r *changeReport) diff() []byte {
+	return []byte(r.differ().Render(r.docChanges))
+}
+
+// groupConfig returns the group configuration, with the methods of the render configuration when it has none.
+func (cfg *RenderConfig) groupConfig() GroupConfig {
+	group := cfg.Group
+	if group.Methods == nil {
+		group.Methods = cfg.Methods
+	}
+	return group
+}
+
+// originalConfiguration returns the configuration used to parse the original specification.
+func (cfg *RenderConfig) originalConfiguration() *datamodel.DocumentConfiguration {
+	if cfg.OriginalDocumentConfiguration != nil {
+		return cfg.OriginalDocumentConfiguration
+	}
+	return cfg.DocumentConfiguration
+}
+
+// telemetryScope is the instrumentation scope of reports.
+const telemetryScope = "github.com/pb33f/doctor/changerator"
+
+// reportSteps is the number of steps of a report: parsing, comparing and rendering.
+const reportSteps = 3
+
+// progress publishes the progress of a report to the events of the configuration.
+func (cfg *RenderConfig) progress(done int, message string) {
+	events.Publish(cfg.Events, events.Progress, events.SourceChangerator,
+		&events.ProgressEvent{Stage: "report", Message: message, Done: done, Total: reportSteps})
+}
+
+// walk walks a specification with the defaults of drModel.NewDrDocument, publishing to the events and tracing
+// with the provider of the configuration. The walks of a report are traced beneath its span. A walk that does not
+// complete (such as one that exceeds cfg.MaxMemoryBytes) fails the report, rather than reporting a partial model.
+func (cfg *RenderConfig) walk(ctx context.Context, name string, doc *libopenapi.DocumentModel[v3.Document]) (*drModel.DrDocument, error) {
+	drDoc, err := drModel.NewDrDocumentWithContext(ctx, doc, &drModel.DrConfig{UseSchemaCache: true, BuildLineIndex: true,
+		Events: cfg.Events, TracerProvider: cfg.TracerProvider, MaxMemoryBytes: cfg.MaxMemoryBytes})
+	if err != nil {
+		return nil, fmt.Errorf("unable to walk %s specification: %w", name, err)
+	}
+	return drDoc, nil
+}
+
+func buildModel(name string, spec []byte, docConfig *datamodel.DocumentConfiguration) (*libopenapi.DocumentModel[v3.Document], error) {
+	var doc libopenapi.Document
+	var err error
+	if docConfig != nil {
+		doc, err = libopenapi.NewDocumentWithConfiguration(spec, docConfig)
+	} else {
+		doc, err = libopenapi.NewDocument(spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s specification: %w", name, err)
+	}
+	v3Doc, errs := doc.BuildV3Model()
+	if v3Doc == nil {
+		return nil, fmt.Errorf("unable to build %s specification: %w", name, errors.Join(errs...))
+	}
+	return v3Doc, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// diffStyle styles the lines of value diffs (see RenderConfig.ValueDiffs).
+const diffStyle = `<style>
+tr.diff pre { margin: 0; }
+.diff-remove { background: #ffebe9; color: #82071e; }
+.diff-add { background: #dafbe1; color: #116329; }
+.diff-context { color: #57606a; }
+</style>
+`
+
+// html renders the report as a standalone HTML page.
+func (r *changeReport) html() []byte {
+	buf := strings.Builder{}
+	buf.WriteString("<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"><title>Changes</title>")
+	if r.cfg.ValueDiffs {
+		buf.WriteString("\n" + diffStyle)
+	}
+	buf.WriteString("</head>\n<body>\n")
+	buf.WriteString(fmt.Sprintf("<h1>Changes</h1>\n<p>%s.</p>\n", r.summary()))
+	if len(r.impact) > 0 {
+		buf.WriteString("<section id=\"most-impactful-changes\">\n<h2>Most impactful changes</h2>\n")
+		buf.WriteString(impactHTML(r.impact) + "</section>\n")
+	}
+	buf.WriteString(r.groups.HTML())
+	ids := anchors{}
+	if len(r.renamed) > 0 {
+		buf.WriteString("<section id=\"renamed-components\">\n<h2>Renamed components</h2>\n<ul>\n")
+		for _, rn := range r.renamed {
+			buf.WriteString(rn.html(ids))
+		}
+		buf.WriteString("</ul>\n</section>\n")
+	}
+	if len(r.other) > 0 {
+		buf.WriteString("<section id=\"other-changes\">\n<h2>Other changes</h2>\n<table>\n" + htmlTableHeader)
+		for _, c := range r.other {
+			buf.WriteString(htmlRow(c, ids.next(c)))
+		}
+		buf.WriteString("</table>\n</section>\n")
+	}
+	if len(r.acknowledged) > 0 {
+		buf.WriteString("<section id=\"acknowledged-changes\">\n<h2>Acknowledged changes</h2>\n<table>\n")
+		buf.WriteString("<tr><th>Change</th><th>Location</th><th>Suppression</th><th>Reason</th><th>Expires</th></tr>\n")
+		for _, c := range r.acknowledged {
+			s := c.Acknowledged
+			buf.WriteString(fmt.Sprintf("<tr><td>%s</td><td><code>%s</code></td><td><code>%s</code></td><td>%s</td><td>%s</td></tr>\n",
+				ChangeTypeName(c.Change.ChangeType), html.EscapeString(c.PropertyPath()), html.EscapeString(s.Path),
+				html.EscapeString(s.Reason), suppressionExpiry(s)))
+		}
+		buf.WriteString("</table>\n</section>\n")
+	}
+	if len(r.drift) > 0 {
+		buf.WriteString("<section id=\"example-drift\">\n<h2>Example drift</h2>\n<ul>\n")
+		for _, d := range r.drift {
+			buf.WriteString(d.html(r.redact))
+		}
+		buf.WriteString("</ul>\n</section>\n")
+	}
+	if len(r.deprecated) > 0 {
+		buf.WriteString("<section id=\"newly-deprecated\">\n<h2>Newly deprecated</h2>\n<table>\n")
+		buf.WriteString("<tr><th>Kind</th><th>Name</th><th>Operation</th><th>Location</th></tr>\n")
+		for _, d := range r.deprecated {
+			operation := deprecationOperation(d, "")
+			if operation != "" {
+				operation = "<code>" + html.EscapeString(operation) + "</code>"
+			}
+			buf.WriteString(fmt.Sprintf("<tr><td>%s</td><td><code>%s</code></td><td>%s</td><td><code>%s</code> (%d:%d)</td></tr>\n",
+				d.Kind, html.EscapeString(d.Name), operation, html.EscapeString(d.JSONPath), d.Line, d.Column))
+		}
+		buf.WriteString("</table>\n</section>\n")
+	}
+	if len(r.findings) > 0 {
+		buf.WriteString("<section id=\"findings\">\n<h2>Findings</h2>\n<ul>\n")
+		for _, f := range r.findings {
+			buf.WriteString(f.html())
+		}
+		buf.WriteString("</ul>\n</section>\n")
+	}
+	if len(r.otherFindings) > 0 {
+		buf.WriteString("<section id=\"other-findings\">\n<h2>Other findings</h2>\n<ul>\n")
+		for _, f := range r.otherFindings {
+			buf.WriteString(htmlFinding(f))
+		}
+		buf.WriteString("</ul>\n</section>\n")
+	}
+	buf.WriteString("</body>\n</html>\n")
+	return []byte(buf.String())
+}
@@ -0,0 +1,150 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"encoding/json"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/doctor/validation"
+)
+
+// ReportChange is a single change, as rendered in a JSON report.
+type ReportChange struct {
+	Anchor   string `json:"anchor"`
+	Path     string `json:"path"`
+	Type     string `json:"type"`
+	Category string `json:"category"`
+	Original string `json:"original,omitempty"`
+	New      string `json:"new,omitempty"`
+	Breaking bool   `json:"breaking"`
+
+	// Line is the line of the change in the updated specification (or the original, for removals), Object is
+	// the JSONPath of the doctor object the change is attached to (see AttachChanges). File is the file the change
+	// was made in, for specifications split across files.
+	Line   int    `json:"line,omitempty"`
+	Object string `json:"object,omitempty"`
+	File   string `json:"file,omitempty"`
+
+	// Findings are the findings attached to the object the change is attached to (see RenderConfig.Findings).
+	Findings []*drBase.Finding `json:"findings,omitempty"`
+
+	// Acknowledged is the suppression that matched the change (see RenderConfig.Suppressions).
+	Acknowledged *Suppression `json:"acknowledged,omitempty"`
+}
+
+// ReportImpact is a change ranked by its impact (see ScoreChanges), as rendered in a JSON report.
+type ReportImpact struct {
+	Anchor     string  `json:"anchor"`
+	Path       string  `json:"path"`
+	Score      float64 `json:"score"`
+	ObjectType string  `json:"objectType"`
+	Operations int     `json:"operations"`
+	Breaking   bool    `json:"breaking"`
+}
+
+// ReportExampleDrift is an example that is no longer valid for its changed schema (see DetectExampleDrift), as
+// rendered in a JSON report. Changes are the anchors of the changes that caused it.
+type ReportExampleDrift struct {
+	Example    string                  `json:"example"`
+	Violations []*validation.Violation `json:"violations"`
+	Changes    []string                `json:"changes"`
+}
+
+// ReportRename is a renamed component schema (see DetectRenames), as rendered in a JSON report.
+type ReportRename struct {
+	From    string          `json:"from"`
+	To      string          `json:"to"`
+	Changes []*ReportChange `json:"changes,omitempty"`
+}
+
+// ReportDeprecation is an element the updated specification deprecates (see RenderConfig.Deprecations), as
+// rendered in a JSON report. Operation is the method and path of the operation the element belongs to, empty for
+// component schemas.
+type ReportDeprecation struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	Line      int    `json:"line,omitempty"`
+	Operation string `json:"operation,omitempty"`
+	Reference string `json:"reference,omitempty"`
+}
+
+type jsonReport struct {
+	TotalChanges    int                      `json:"totalChanges"`
+	BreakingChanges int                      `json:"breakingChanges"`
+	Acknowledged    int                      `json:"acknowledgedChanges,omitempty"`
+	Groups          map[string]*GroupSummary `json:"groups"`
+	Changes         []*ReportChange          `json:"changes"`
+	Renamed         []*ReportRename          `json:"renamed,omitempty"`
+	Impact          []*ReportImpact          `json:"impact,omitempty"`
+	ExampleDrift    []*ReportExampleDrift    `json:"exampleDrift,omitempty"`
+	NewlyDeprecated []*ReportDeprecation     `json:"newlyDeprecated,omitempty"`
+
+	// InitialRelease summarizes the specification when there is no original to compare it to.
+	InitialRelease *ReleaseSummary `json:"initialRelease,omitempty"`
+}
+
+// json renders the report as JSON.
+func (r *changeReport) json() ([]byte, error) {
+	report := &jsonReport{TotalChanges: r.total, BreakingChanges: r.breaking, Acknowledged: len(r.acknowledged),
+		Groups: r.groups.Summary(), Changes: make([]*ReportChange, 0, len(r.located))}
+	for _, c := range r.located {
+		report.Changes = append(report.Changes, reportChange(c, r.attached))
+	}
+	for _, rn := range r.renamed {
+		rr := &ReportRename{From: rn.From, To: rn.To}
+		for _, c := range rn.Changes {
+			rr.Changes = append(rr.Changes, reportChange(c, r.attached))
+		}
+		report.Renamed = append(report.Renamed, rr)
+	}
+	for _, sc := range r.impact {
+		report.Impact = append(report.Impact, &ReportImpact{Anchor: sc.Anchor(), Path: sc.PropertyPath(),
+			Score: sc.Score, ObjectType: sc.ObjectType, Operations: len(sc.Operations), Breaking: sc.Change.Breaking})
+	}
+	for _, d := range r.drift {
+		rd := &ReportExampleDrift{Example: d.Example}
+		for _, v := range d.Violations {
+			rd.Violations = append(rd.Violations, &validation.Violation{Message: r.redact(v.Location, v.Message),
+				Path: v.Path, Location: v.Location})
+		}
+		for _, c := range d.Changes {
+			rd.Changes = append(rd.Changes, c.Anchor())
+		}
+		report.ExampleDrift = append(report.ExampleDrift, rd)
+	}
+	for _, d := range r.deprecated {
+		report.NewlyDeprecated = append(report.NewlyDeprecated, &ReportDeprecation{Kind: d.Kind, Name: d.Name,
+			Path: d.JSONPath, Line: d.Line, Operation: deprecationOperation(d, ""), Reference: d.Reference})
+	}
+	return json.MarshalIndent(report, "", "  ")
+}
+
+// reportChange renders a change for a JSON report, with the JSONPath of the doctor object it is attached to.
+func reportChange(c *LocatedChange, attached *Attachments) *ReportChange {
+	rc := &ReportChange{
+		Anchor:   c.Anchor(),
+		Path:     c.PropertyPath(),
+		Type:     ChangeTypeName(c.Change.ChangeType),
+		Category: c.Category(),
+		Original: c.Change.Original,
+		New:      c.Change.New,
+		Breaking: c.Change.Breaking,
+		File:     c.File,
+
+		Acknowledged: c.Acknowledged,
+	}
+	if ctx := c.Change.Context; ctx != nil {
+		if ctx.NewLine != nil {
+			rc.Line = *ctx.NewLine
+		} else if ctx.OriginalLine != nil {
+			rc.Line = *ctx.OriginalLine
+		}
+	}
+	if f := attached.Object(c); f != nil {
+		rc.Object = f.GenerateJSONPath()
+		rc.Findings = drBase.FindingsOf(f)
+	}
+	return rc
+}
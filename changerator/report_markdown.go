@@ -0,0 +1,68 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// markdown renders the report as markdown.
+func (r *changeReport) markdown() []byte {
+	buf := strings.Builder{}
+	buf.WriteString(fmt.Sprintf("# Changes\n\n%s.\n\n", r.summary()))
+	if len(r.impact) > 0 {
+		buf.WriteString("## Most impactful changes\n\n" + impactMarkdown(r.impact) + "\n")
+	}
+	buf.WriteString(r.groups.Markdown())
+	ids := anchors{}
+	if len(r.renamed) > 0 {
+		buf.WriteString("\n## Renamed components\n\n")
+		for _, rn := range r.renamed {
+			buf.WriteString(rn.markdown(ids))
+		}
+	}
+	if len(r.other) > 0 {
+		buf.WriteString("\n## Other changes\n\n" + markdownTableHeader)
+		for _, c := range r.other {
+			buf.WriteString(markdownRow(c, ids.next(c)))
+		}
+	}
+	if len(r.acknowledged) > 0 {
+		buf.WriteString("\n## Acknowledged changes\n\n| Change | Location | Suppression | Reason | Expires |\n")
+		buf.WriteString("|--------|----------|-------------|--------|---------|\n")
+		for _, c := range r.acknowledged {
+			s := c.Acknowledged
+			buf.WriteString(fmt.Sprintf("| %s | `%s` | `%s` | %s | %s |\n", ChangeTypeName(c.Change.ChangeType),
+				c.PropertyPath(), s.Path, strings.ReplaceAll(s.Reason, "|", "\\|"), suppressionExpiry(s)))
+		}
+	}
+	if len(r.drift) > 0 {
+		buf.WriteString("\n## Example drift\n\n")
+		for _, d := range r.drift {
+			buf.WriteString(d.markdown(r.redact))
+		}
+	}
+	if len(r.deprecated) > 0 {
+		buf.WriteString("\n## Newly deprecated\n\n| Kind | Name | Operation | Location |\n")
+		buf.WriteString("|------|------|-----------|----------|\n")
+		for _, d := range r.deprecated {
+			buf.WriteString(fmt.Sprintf("| %s | `%s` | %s | `%s` (%d:%d) |\n", d.Kind, d.Name,
+				deprecationOperation(d, "`"), d.JSONPath, d.Line, d.Column))
+		}
+	}
+	if len(r.findings) > 0 {
+		buf.WriteString("\n## Findings\n\n")
+		for _, f := range r.findings {
+			buf.WriteString(f.markdown())
+		}
+	}
+	if len(r.otherFindings) > 0 {
+		buf.WriteString("\n## Other findings\n\n")
+		for _, f := range r.otherFindings {
+			buf.WriteString(markdownFinding(f))
+		}
+	}
+	return []byte(buf.String())
+}
@@ -0,0 +1,155 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pb33f/doctor/events"
+	drModel "github.com/pb33f/doctor/model"
+	"github.com/pb33f/doctor/telemetry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	updated := strings.Replace(teamSpec, "description: orders", "description: all the orders", 1)
+	updated = strings.Replace(updated, "version: 1.0.0", "version: 1.1.0", 1)
+
+	md, err := Report([]byte(teamSpec), []byte(updated), FormatMarkdown, nil)
+	require.NoError(t, err)
+	assert.Contains(t, string(md), "# Changes\n\n2 changes, 0 breaking.\n\n## Contents\n\n- [billing](#group-billing)")
+	assert.Contains(t, string(md), "### `GET /orders`")
+	assert.Contains(t, string(md), "## Other changes\n")
+	assert.Contains(t, string(md), "`$.info.version` | `1.0.0` | `1.1.0` |")
+
+	h, err := Report([]byte(teamSpec), []byte(updated), FormatHTML, &RenderConfig{Group: GroupConfig{By: GroupByOwner}})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(h), "<!DOCTYPE html>"))
+	assert.Contains(t, string(h), "<section id=\"group-checkout\">")
+	assert.Contains(t, string(h), "<section id=\"other-changes\">")
+
+	j, err := Report([]byte(teamSpec), []byte(updated), FormatJSON, nil)
+	require.NoError(t, err)
+	var report struct {
+		TotalChanges int                      `json:"totalChanges"`
+		Groups       map[string]*GroupSummary `json:"groups"`
+		Changes      []*ReportChange          `json:"changes"`
+	}
+	require.NoError(t, json.Unmarshal(j, &report))
+	assert.Equal(t, 2, report.TotalChanges)
+	assert.Equal(t, []string{"GET /orders"}, report.Groups["orders"].Operations)
+	require.Len(t, report.Changes, 2)
+	assert.Equal(t, "$.info.version", report.Changes[0].Path)
+	assert.Equal(t, 4, report.Changes[0].Line)
+	assert.Equal(t, "$.info", report.Changes[0].Object)
+	assert.NotEmpty(t, report.Changes[0].Anchor)
+
+	_, err = Report([]byte(teamSpec), []byte(updated), "pdf", nil)
+	assert.EqualError(t, err, "unknown report format 'pdf'")
+	_, err = Report([]byte("not: [a spec"), []byte(updated), FormatJSON, nil)
+	assert.Error(t, err)
+}
+
+func TestReport_WalkError(t *testing.T) {
+	updated := strings.Replace(teamSpec, "description: orders", "description: all the orders", 1)
+
+	_, err := Report([]byte(teamSpec), []byte(updated), FormatMarkdown, &RenderConfig{MaxMemoryBytes: 1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to walk updated specification")
+	var budget *drModel.MemoryBudgetError
+	assert.ErrorAs(t, err, &budget)
+
+	_, err = EmailDigest([]byte(teamSpec), []byte(updated), &RenderConfig{MaxMemoryBytes: 1})
+	assert.ErrorAs(t, err, &budget)
+}
+
+func TestReport_Events(t *testing.T) {
+	updated := strings.Replace(teamSpec, "description: orders", "description: all the orders", 1)
+
+	bus := events.NewBus()
+	sub := bus.Subscribe(0, events.DropNewest, events.Progress)
+	_, err := Report([]byte(teamSpec), []byte(updated), FormatJSON, &RenderConfig{Events: bus})
+	require.NoError(t, err)
+	bus.Close()
+
+	var report, walks []string
+	for e := range sub.Events() {
+		p, ok := events.Payload(e, events.Progress)
+		require.True(t, ok)
+		switch e.Source {
+		case events.SourceChangerator:
+			report = append(report, fmt.Sprintf("%d/%d %s", p.Done, p.Total, p.Message))
+		case events.SourceWalker:
+			walks = append(walks, p.Message)
+		}
+	}
+	assert.Equal(t, []string{"0/3 parsing specifications", "1/3 comparing specifications",
+		"2/3 rendering json report", "3/3 rendered json report"}, report)
+	assert.Len(t, walks, 4) // both specifications are walked.
+}
+
+func TestReport_Telemetry(t *testing.T) {
+	updated := strings.Replace(teamSpec, "description: orders", "description: all the orders", 1)
+
+	recorder := telemetry.NewRecorder()
+	_, err := Report([]byte(teamSpec), []byte(updated), FormatMarkdown, &RenderConfig{TracerProvider: recorder})
+	require.NoError(t, err)
+
+	renders := recorder.Spans(telemetry.SpanRender)
+	require.Len(t, renders, 1)
+	assert.Equal(t, "markdown", renders[0].Attributes["format"])
+	assert.Empty(t, renders[0].Errors)
+
+	// both specifications are walked beneath the render.
+	walks := recorder.Spans(telemetry.SpanWalk)
+	require.Len(t, walks, 2)
+	for _, w := range walks {
+		assert.Same(t, renders[0], w.Parent)
+	}
+	require.NotNil(t, recorder.Metric(telemetry.MetricRenderDuration))
+	assert.Len(t, recorder.Metric(telemetry.MetricRenderDuration).Values, 1)
+
+	_, err = Report([]byte(teamSpec), []byte("burgers: [\n"), FormatMarkdown, &RenderConfig{TracerProvider: recorder})
+	require.Error(t, err)
+	renders = recorder.Spans(telemetry.SpanRender)
+	require.Len(t, renders, 2)
+	assert.Len(t, renders[1].Errors, 1)
+}
+
+func TestReport_Deprecations(t *testing.T) {
+	updated := strings.Replace(leftSpec, "      operationId: listBurgers\n",
+		"      operationId: listBurgers\n      deprecated: true\n", 1)
+	updated = strings.Replace(updated, "        patties:\n          type: integer",
+		"        patties:\n          type: integer\n          deprecated: true", 1)
+	cfg := &RenderConfig{Deprecations: true}
+
+	md, err := Report([]byte(leftSpec), []byte(updated), FormatMarkdown, cfg)
+	require.NoError(t, err)
+	assert.Contains(t, string(md), "## Newly deprecated\n\n| Kind | Name | Operation | Location |\n"+
+		"|------|------|-----------|----------|\n"+
+		"| operation | `get /burgers` | `GET /burgers` | `$.paths['/burgers'].get` (7:5) |\n")
+	assert.Contains(t, string(md), "| property | `patties` |  | `$.components.schemas['Burger'].properties['patties']`")
+
+	h, err := Report([]byte(leftSpec), []byte(updated), FormatHTML, cfg)
+	require.NoError(t, err)
+	assert.Contains(t, string(h), "<section id=\"newly-deprecated\">")
+	assert.Contains(t, string(h), "<tr><td>operation</td><td><code>get /burgers</code></td><td><code>GET /burgers</code></td>")
+
+	j, err := Report([]byte(leftSpec), []byte(updated), FormatJSON, cfg)
+	require.NoError(t, err)
+	var report struct {
+		NewlyDeprecated []*ReportDeprecation `json:"newlyDeprecated"`
+	}
+	require.NoError(t, json.Unmarshal(j, &report))
+	require.NotEmpty(t, report.NewlyDeprecated)
+	assert.Equal(t, &ReportDeprecation{Kind: "operation", Name: "get /burgers", Path: "$.paths['/burgers'].get",
+		Line: 7, Operation: "GET /burgers"}, report.NewlyDeprecated[0])
+
+	md, err = Report([]byte(leftSpec), []byte(updated), FormatMarkdown, nil)
+	require.NoError(t, err)
+	assert.NotContains(t, string(md), "## Newly deprecated")
+}
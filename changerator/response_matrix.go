@@ -0,0 +1,77 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"fmt"
+	drModel "github.com/pb33f/doctor/model"
+	"html"
+	"strings"
+)
+
+const responseMatrixMarkdownHeader = "| Operation | Responses | Content Types | Missing |\n|-----------|-----------|---------------|---------|\n"
+
+const responseMatrixHTMLHeader = "<tr><th>Operation</th><th>Responses</th><th>Content Types</th><th>Missing</th></tr>\n"
+
+// ResponseMatrixMarkdown renders the responses of every operation (see drModel.DrDocument.ResponseMatrix) as a
+// markdown table, in the style of the change tables of a report: the codes each operation declares, the content
+// types of each code and the common response classes it is missing.
+func ResponseMatrixMarkdown(m *drModel.ResponseMatrix) string {
+	buf := strings.Builder{}
+	buf.WriteString(responseMatrixMarkdownHeader)
+	if m == nil {
+		return buf.String()
+	}
+	for _, row := range m.Rows {
+		var types []string
+		for _, code := range row.Codes {
+			if cts := row.ContentTypes[code]; len(cts) > 0 {
+				types = append(types, code+": "+markdownValue(strings.Join(cts, ", ")))
+			}
+		}
+		op := markdownValue(responseMatrixOperation(row))
+		if row.Operation.Webhook {
+			op += " (webhook)"
+		}
+		buf.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+			op, strings.Join(row.Codes, ", "), strings.Join(types, "<br/>"), strings.Join(row.Missing(), ", ")))
+	}
+	return buf.String()
+}
+
+// ResponseMatrixHTML renders the responses of every operation (see drModel.DrDocument.ResponseMatrix) as an HTML
+// table, in the style of the change tables of a report. Operations that are missing a common response class have
+// the missing class.
+func ResponseMatrixHTML(m *drModel.ResponseMatrix) string {
+	buf := strings.Builder{}
+	buf.WriteString("<table>\n" + responseMatrixHTMLHeader)
+	if m != nil {
+		for _, row := range m.Rows {
+			var types []string
+			for _, code := range row.Codes {
+				if cts := row.ContentTypes[code]; len(cts) > 0 {
+					types = append(types, html.EscapeString(code)+": "+htmlValue(strings.Join(cts, ", ")))
+				}
+			}
+			op := htmlValue(responseMatrixOperation(row))
+			if row.Operation.Webhook {
+				op += " (webhook)"
+			}
+			missing, class := row.Missing(), ""
+			if len(missing) > 0 {
+				class = " class=\"missing\""
+			}
+			buf.WriteString(fmt.Sprintf("<tr%s><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n", class, op,
+				html.EscapeString(strings.Join(row.Codes, ", ")), strings.Join(types, "<br/>"),
+				strings.Join(missing, ", ")))
+		}
+	}
+	buf.WriteString("</table>\n")
+	return buf.String()
+}
+
+// responseMatrixOperation is the method and path of the operation of a row.
+func responseMatrixOperation(row *drModel.ResponseMatrixRow) string {
+	return strings.ToUpper(row.Operation.Method) + " " + row.Operation.Path
+}
@@ -0,0 +1,30 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestResponseMatrixMarkdown(t *testing.T) {
+	spec, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	matrix := contractDocument(t, string(spec)).ResponseMatrix()
+
+	md := ResponseMatrixMarkdown(matrix)
+	assert.Contains(t, md, responseMatrixMarkdownHeader+"| `POST /burgers` | 200, 500, 422 | 200: `application/json`")
+	assert.Contains(t, md, "| default |\n")
+	assert.Equal(t, responseMatrixMarkdownHeader, ResponseMatrixMarkdown(nil))
+}
+
+func TestResponseMatrixHTML(t *testing.T) {
+	spec, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	matrix := contractDocument(t, string(spec)).ResponseMatrix()
+
+	h := ResponseMatrixHTML(matrix)
+	assert.Contains(t, h, "<table>\n"+responseMatrixHTMLHeader+
+		"<tr class=\"missing\"><td><code>POST /burgers</code></td><td>200, 500, 422</td><td>200: <code>application/json</code>")
+	assert.Contains(t, h, "<td>default</td></tr>\n")
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"fmt"
+	drModel "github.com/pb33f/doctor/model"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"slices"
+	"strings"
+)
+
+const (
+	FindingSecurityMissing          = "security-missing"
+	FindingSecuritySchemeNotAllowed = "security-scheme-not-allowed"
+	FindingSecurityScopes           = "security-scopes"
+)
+
+// SecurityPolicy is the security every operation of a document must meet to pass the gate.
+type SecurityPolicy struct {
+	// AllowUnsecured are the operations that may be called without credentials (like a health check), as
+	// operationIds or as `METHOD /path`.
+	AllowUnsecured []string
+
+	// AllowedSchemes are the names of the security schemes operations may use, any scheme is allowed when empty.
+	AllowedSchemes []string
+
+	// MaxScopes is the number of scopes an operation may require from a single scheme, zero fails on any scope
+	// and Unlimited disables the check.
+	MaxScopes int
+}
+
+// SecurityGateResult is the outcome of evaluating a security policy. Findings are attached to the operations that
+// violate the policy.
+type SecurityGateResult struct {
+	Passed   bool              `json:"passed" yaml:"passed"`
+	Reasons  []string          `json:"reasons,omitempty" yaml:"reasons,omitempty"`
+	Findings []*drBase.Finding `json:"findings,omitempty" yaml:"findings,omitempty"`
+}
+
+// ExitCode returns 0 when the gate passed, and 1 when it failed, for use as a process exit code.
+func (r *SecurityGateResult) ExitCode() int {
+	if r.Passed {
+		return 0
+	}
+	return 1
+}
+
+// RequireSecurity checks every operation of a document against a security policy (see model.SecurityAudit), the
+// gate fails when an operation has no security requirement (or an optional one) and is not allowed to be
+// unsecured, uses a scheme that is not allowed, or requires more scopes from a scheme than the maximum.
+func RequireSecurity(drDoc *drModel.DrDocument, policy SecurityPolicy) *SecurityGateResult {
+	result := &SecurityGateResult{Passed: true}
+	audit := drDoc.SecurityAudit()
+
+	unsecured := 0
+	for _, po := range audit.Unsecured {
+		if slices.Contains(policy.AllowUnsecured, operationName(po)) ||
+			(po.Operation.Value.OperationId != "" && slices.Contains(policy.AllowUnsecured, po.Operation.Value.OperationId)) {
+			continue
+		}
+		unsecured++
+		result.Findings = append(result.Findings, drBase.NewFinding(FindingSecurityMissing, drBase.SeverityError,
+			fmt.Sprintf("`%s` can be called without credentials", operationName(po)), po.Operation))
+	}
+	if unsecured > 0 {
+		result.Reasons = append(result.Reasons, fmt.Sprintf("%d operations are not secured", unsecured))
+	}
+
+	disallowed, scoped := make(map[*drModel.PathOperation]bool), make(map[*drModel.PathOperation]bool)
+	for _, sa := range audit.Schemes {
+		if len(policy.AllowedSchemes) > 0 && !slices.Contains(policy.AllowedSchemes, sa.Name) {
+			for _, po := range sa.Operations {
+				disallowed[po] = true
+				result.Findings = append(result.Findings, drBase.NewFinding(FindingSecuritySchemeNotAllowed,
+					drBase.SeverityError, fmt.Sprintf("`%s` uses the security scheme '%s', which is not allowed",
+						operationName(po), sa.Name), po.Operation))
+			}
+		}
+		if policy.MaxScopes < 0 {
+			continue
+		}
+		for _, po := range sa.Operations {
+			var scopes []string
+			for scope, ops := range sa.ScopeOperations {
+				if slices.Contains(ops, po) {
+					scopes = append(scopes, scope)
+				}
+			}
+			if len(scopes) > policy.MaxScopes {
+				scoped[po] = true
+				slices.Sort(scopes)
+				result.Findings = append(result.Findings, drBase.NewFinding(FindingSecurityScopes, drBase.SeverityError,
+					fmt.Sprintf("`%s` requires %d scopes from '%s' (%s), the maximum allowed is %d", operationName(po),
+						len(scopes), sa.Name, strings.Join(scopes, ", "), policy.MaxScopes), po.Operation))
+			}
+		}
+	}
+	if len(disallowed) > 0 {
+		result.Reasons = append(result.Reasons, fmt.Sprintf("%d operations use security schemes that are not allowed",
+			len(disallowed)))
+	}
+	if len(scoped) > 0 {
+		result.Reasons = append(result.Reasons, fmt.Sprintf("%d operations require more than %d scopes from a scheme",
+			len(scoped), policy.MaxScopes))
+	}
+	result.Passed = len(result.Findings) == 0
+	return result
+}
+
+// operationName names an operation as `METHOD /path`.
+func operationName(po *drModel.PathOperation) string {
+	return strings.ToUpper(po.Method) + " " + po.Path
+}
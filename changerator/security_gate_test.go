@@ -0,0 +1,97 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var securitySpec = `openapi: 3.1.0
+info:
+  title: burgers
+  version: 1.0.0
+security:
+  - oauth: [burgers:read]
+paths:
+  /burgers:
+    get:
+      responses:
+        "200":
+          description: ok
+    post:
+      security:
+        - oauth: [burgers:read, burgers:write, fries:write]
+        - key: []
+      responses:
+        "201":
+          description: created
+  /health:
+    get:
+      operationId: health
+      security: []
+      responses:
+        "200":
+          description: ok
+  /menu:
+    get:
+      security:
+        - {}
+      responses:
+        "200":
+          description: ok
+components:
+  securitySchemes:
+    oauth:
+      type: oauth2
+      flows:
+        clientCredentials:
+          tokenUrl: https://example.com/token
+          scopes:
+            burgers:read: read burgers
+            burgers:write: write burgers
+            fries:write: write fries
+    key:
+      type: apiKey
+      in: header
+      name: X-Key`
+
+func TestRequireSecurity(t *testing.T) {
+	drDoc := drDocument(t, securitySpec)
+
+	result := RequireSecurity(drDoc, SecurityPolicy{
+		AllowUnsecured: []string{"health"},
+		AllowedSchemes: []string{"oauth"},
+		MaxScopes:      2,
+	})
+	assert.False(t, result.Passed)
+	assert.Equal(t, 1, result.ExitCode())
+	assert.Equal(t, []string{
+		"1 operations are not secured",
+		"1 operations use security schemes that are not allowed",
+		"1 operations require more than 2 scopes from a scheme",
+	}, result.Reasons)
+	require.Len(t, result.Findings, 3)
+
+	// security is optional for the menu.
+	assert.Equal(t, FindingSecurityMissing, result.Findings[0].Id)
+	assert.Equal(t, "`GET /menu` can be called without credentials", result.Findings[0].Message)
+	assert.Equal(t, "$.paths['/menu'].get", result.Findings[0].Path)
+
+	assert.Equal(t, FindingSecurityScopes, result.Findings[1].Id)
+	assert.Equal(t, "`POST /burgers` requires 3 scopes from 'oauth' (burgers:read, burgers:write, fries:write), "+
+		"the maximum allowed is 2", result.Findings[1].Message)
+
+	assert.Equal(t, FindingSecuritySchemeNotAllowed, result.Findings[2].Id)
+	assert.Equal(t, "$.paths['/burgers'].post", result.Findings[2].Path)
+
+	result = RequireSecurity(drDoc, SecurityPolicy{
+		AllowUnsecured: []string{"GET /health", "GET /menu"},
+		MaxScopes:      Unlimited,
+	})
+	assert.True(t, result.Passed)
+	assert.Equal(t, 0, result.ExitCode())
+	assert.Empty(t, result.Findings)
+}
@@ -0,0 +1,206 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"fmt"
+	"github.com/pb33f/libopenapi/what-changed/model"
+	"gopkg.in/yaml.v3"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Suppression acknowledges a known change, so it no longer fails a gate. Changes are matched by location and
+// (optionally) change type.
+type Suppression struct {
+	// Path is a JSONPath (for example `$.paths['/burgers'].get`) or a component reference (for example
+	// `#/components/schemas/Burger`). Every change at, or below the location is matched.
+	Path string `json:"path" yaml:"path"`
+
+	// ChangeType is the name of the change type to match (modified, property_added, object_added, object_removed
+	// or property_removed), all change types are matched when empty.
+	ChangeType string `json:"changeType,omitempty" yaml:"changeType,omitempty"`
+
+	// Expires is the time the suppression stops applying, it never expires when nil.
+	Expires *time.Time `json:"expires,omitempty" yaml:"expires,omitempty"`
+
+	// Reason explains why the change was accepted.
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// SuppressionList is a list of suppressions, usually loaded from an ignore file.
+//
+//	suppressions:
+//	  - path: '#/components/schemas/Burger'
+//	    changeType: object_removed
+//	    expires: 2025-01-01T00:00:00Z
+//	    reason: patties are no longer counted
+type SuppressionList struct {
+	Suppressions []*Suppression `json:"suppressions" yaml:"suppressions"`
+}
+
+// AcknowledgedChange is a change that matched a suppression.
+type AcknowledgedChange struct {
+	*LocatedChange
+	Suppression *Suppression `json:"suppression" yaml:"suppression"`
+}
+
+// ParseSuppressions parses a suppression list from YAML or JSON.
+func ParseSuppressions(data []byte) (*SuppressionList, error) {
+	var list SuppressionList
+	if err := yaml.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("unable to parse suppressions: %w", err)
+	}
+	for i, s := range list.Suppressions {
+		if s == nil || s.Path == "" {
+			return nil, fmt.Errorf("suppression %d has no path", i)
+		}
+		if s.ChangeType != "" && changeTypeFromName(s.ChangeType) == 0 {
+			return nil, fmt.Errorf("suppression %d has an unknown change type '%s'", i, s.ChangeType)
+		}
+	}
+	return &list, nil
+}
+
+// LoadSuppressions reads and parses a suppression list (ignore file) from disk.
+func LoadSuppressions(path string) (*SuppressionList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSuppressions(data)
+}
+
+// Match returns the first suppression (that has not expired at the supplied time) that matches the change,
+// or nil if there isn't one.
+func (l *SuppressionList) Match(change *LocatedChange, now time.Time) *Suppression {
+	if l == nil || change == nil || change.Change == nil {
+		return nil
+	}
+	for _, s := range l.Suppressions {
+		if s.Expires != nil && !now.Before(*s.Expires) {
+			continue
+		}
+		if s.ChangeType != "" && changeTypeFromName(s.ChangeType) != change.Change.ChangeType {
+			continue
+		}
+		path := pathSegments(s.Path)
+		if pathContains(path, pathSegments(change.Path)) || pathContains(path, pathSegments(change.PropertyPath())) {
+			return s
+		}
+	}
+	return nil
+}
+
+// Expired returns the suppressions that have expired at the supplied time, so they can be reported
+// and removed from the list.
+func (l *SuppressionList) Expired(now time.Time) []*Suppression {
+	if l == nil {
+		return nil
+	}
+	var expired []*Suppression
+	for _, s := range l.Suppressions {
+		if s.Expires != nil && !now.Before(*s.Expires) {
+			expired = append(expired, s)
+		}
+	}
+	return expired
+}
+
+// Acknowledged returns every change in the document changes that matches a suppression at the supplied time.
+func (l *SuppressionList) Acknowledged(docChanges *model.DocumentChanges, now time.Time) []*AcknowledgedChange {
+	if l == nil {
+		return nil
+	}
+	var acknowledged []*AcknowledgedChange
+	for _, c := range LocateChanges(docChanges) {
+		if s := l.Match(c, now); s != nil {
+			acknowledged = append(acknowledged, &AcknowledgedChange{LocatedChange: c, Suppression: s})
+		}
+	}
+	return acknowledged
+}
+
+// annotateSuppressions sets the suppression that matches every change at the supplied time, changes are located
+// more than once while building a report, every located copy of a change is annotated. It returns the changes that
+// matched, once each, in the order of the first list.
+func annotateSuppressions(l *SuppressionList, now time.Time, changes ...[]*LocatedChange) []*LocatedChange {
+	if l == nil {
+		return nil
+	}
+	matched := make(map[*model.Change]*Suppression)
+	var acknowledged []*LocatedChange
+	for _, list := range changes {
+		for _, c := range list {
+			s, ok := matched[c.Change]
+			if !ok {
+				s = l.Match(c, now)
+				matched[c.Change] = s
+				if s != nil {
+					acknowledged = append(acknowledged, c)
+				}
+			}
+			c.Acknowledged = s
+		}
+	}
+	return acknowledged
+}
+
+// suppressionExpiry renders when a suppression expires, for the acknowledged changes of a report.
+func suppressionExpiry(s *Suppression) string {
+	if s.Expires == nil {
+		return "never"
+	}
+	return s.Expires.Format(time.DateOnly)
+}
+
+// pathSegments splits a JSONPath (`$.components.schemas['Burger']`) or a component reference
+// (`#/components/schemas/Burger`) into its segments, so the two forms can be compared.
+func pathSegments(path string) []string {
+	var segments []string
+	if strings.HasPrefix(path, "#/") {
+		for _, segment := range strings.Split(strings.TrimPrefix(path, "#/"), "/") {
+			segments = append(segments, strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~"))
+		}
+		return segments
+	}
+	rest := strings.TrimPrefix(path, "$")
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "['"):
+			end := strings.Index(rest, "']")
+			if end < 0 {
+				return append(segments, rest[2:])
+			}
+			segments = append(segments, rest[2:end])
+			rest = rest[end+2:]
+		case rest[0] == '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				return append(segments, rest[1:])
+			}
+			segments = append(segments, rest[1:end+1])
+			rest = rest[end+1:]
+		default:
+			return append(segments, rest)
+		}
+	}
+	return segments
+}
+
+// pathContains checks the path is the same as, or is below the parent path.
+func pathContains(parent, path []string) bool {
+	return len(path) >= len(parent) && slices.Equal(parent, path[:len(parent)])
+}
+
+func changeTypeFromName(name string) int {
+	for _, t := range []int{model.Modified, model.PropertyAdded, model.ObjectAdded, model.ObjectRemoved, model.PropertyRemoved} {
+		if ChangeTypeName(t) == name {
+			return t
+		}
+	}
+	return 0
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package changerator
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSuppressions(t *testing.T) {
+	list, err := ParseSuppressions([]byte(`suppressions:
+  - path: '#/components/schemas/Burger'
+    changeType: object_removed
+    reason: patties are no longer counted
+  - path: $.paths['/burgers'].get
+    expires: 2020-01-01T00:00:00Z`))
+	require.NoError(t, err)
+	require.Len(t, list.Suppressions, 2)
+	assert.Equal(t, "patties are no longer counted", list.Suppressions[0].Reason)
+	assert.Len(t, list.Expired(time.Now()), 1)
+
+	_, err = ParseSuppressions([]byte(`suppressions:
+  - changeType: modified`))
+	assert.Error(t, err)
+
+	_, err = ParseSuppressions([]byte(`suppressions:
+  - path: $.info
+    changeType: renamed`))
+	assert.Error(t, err)
+}
+
+func TestSuppressionList_Acknowledged(t *testing.T) {
+	list, err := ParseSuppressions([]byte(`suppressions:
+  - path: '#/components/schemas/Burger/properties/patties'
+    changeType: object_removed
+  - path: '#/paths/~1burgers/get'
+    expires: 2020-01-01T00:00:00Z
+  - path: $.info
+    changeType: property_added`))
+	require.NoError(t, err)
+
+	changes := compare(t, leftSpec, rightSpec)
+	acknowledged := list.Acknowledged(changes, time.Now())
+	require.Len(t, acknowledged, 1)
+	assert.Equal(t, "$.components.schemas['Burger']", acknowledged[0].Path)
+	assert.Same(t, list.Suppressions[0], acknowledged[0].Suppression)
+
+	// before the suppression expired, the operation change is acknowledged too.
+	assert.Len(t, list.Acknowledged(changes, time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)), 2)
+}
+
+func TestEvaluateGate_Suppressions(t *testing.T) {
+	list, _ := ParseSuppressions([]byte(`suppressions:
+  - path: '#/paths/~1burgers'
+  - path: '#/components/schemas/Burger'`))
+
+	result := EvaluateGate(compare(t, leftSpec, rightSpec), GateConfig{MaxBreaking: 0, MaxTotal: Unlimited, Suppressions: list})
+	assert.True(t, result.Passed)
+	assert.Equal(t, 1, result.TotalChanges)
+	assert.Equal(t, 0, result.BreakingChanges)
+	assert.Len(t, result.Acknowledged, 2)
+}
+
+func TestReport_Suppressions(t *testing.T) {
+	list, err := ParseSuppressions([]byte(`suppressions:
+  - path: '#/components/schemas/Burger'
+    reason: patties are no longer counted`))
+	require.NoError(t, err)
+	cfg := &RenderConfig{Suppressions: list}
+
+	md, err := Report([]byte(leftSpec), []byte(rightSpec), FormatMarkdown, cfg)
+	require.NoError(t, err)
+	assert.Contains(t, string(md), "3 changes, 2 breaking, 1 acknowledged.")
+	assert.Contains(t, string(md), "| object_removed _(acknowledged)_ <!--")
+	assert.Contains(t, string(md), "## Acknowledged changes\n\n| Change | Location | Suppression | Reason | Expires |\n"+
+		"|--------|----------|-------------|--------|---------|\n"+
+		"| object_removed | `$.components.schemas['Burger'].properties['patties']` | `#/components/schemas/Burger` | "+
+		"patties are no longer counted | never |\n")
+
+	h, err := Report([]byte(leftSpec), []byte(rightSpec), FormatHTML, cfg)
+	require.NoError(t, err)
+	assert.Contains(t, string(h), "class=\"acknowledged\"><td>object_removed <em>(acknowledged)</em></td>")
+	assert.Contains(t, string(h), "<section id=\"acknowledged-changes\">")
+
+	j, err := Report([]byte(leftSpec), []byte(rightSpec), FormatJSON, cfg)
+	require.NoError(t, err)
+	var report struct {
+		Acknowledged int             `json:"acknowledgedChanges"`
+		Changes      []*ReportChange `json:"changes"`
+	}
+	require.NoError(t, json.Unmarshal(j, &report))
+	assert.Equal(t, 1, report.Acknowledged)
+	for _, c := range report.Changes {
+		if strings.HasPrefix(c.Path, "$.components") {
+			require.NotNil(t, c.Acknowledged)
+			assert.Equal(t, "patties are no longer counted", c.Acknowledged.Reason)
+		} else {
+			assert.Nil(t, c.Acknowledged)
+		}
+	}
+}
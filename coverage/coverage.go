@@ -0,0 +1,264 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package coverage maps recorded traffic (HAR files or simple access log records) to the operations of a
+// DrDocument, and reports which operations were exercised, which response codes were seen compared to those
+// declared, and which requests hit endpoints that are not in the specification.
+package coverage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pb33f/doctor/internal/routing"
+	"github.com/pb33f/doctor/model"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Record is a single recorded request, and the status code of the response.
+type Record struct {
+	Method string
+	URL    string
+	Status int
+}
+
+// OperationCoverage is the traffic seen for a single operation.
+type OperationCoverage struct {
+	*model.PathOperation
+
+	// Requests is the number of records that matched the operation.
+	Requests int
+
+	// DeclaredCodes are the response codes defined by the operation, SeenCodes are the status codes seen
+	// in the traffic (in ascending order).
+	DeclaredCodes []string
+	SeenCodes     []int
+
+	// UndeclaredCodes are status codes that were seen but not declared (and not covered by a range or default),
+	// UnseenCodes are declared codes that were never seen.
+	UndeclaredCodes []int
+	UnseenCodes     []string
+}
+
+// Exercised returns true when at least one request matched the operation.
+func (o *OperationCoverage) Exercised() bool {
+	return o.Requests > 0
+}
+
+// UndocumentedEndpoint is a method and path seen in the traffic that does not match an operation.
+type UndocumentedEndpoint struct {
+	Method   string
+	Path     string
+	Requests int
+	Statuses []int
+
+	// MethodNotAllowed is true when the path exists in the specification, but does not define the method.
+	MethodNotAllowed bool
+}
+
+// Report is the coverage of a specification by recorded traffic.
+type Report struct {
+	Requests     int
+	Operations   []*OperationCoverage
+	Undocumented []*UndocumentedEndpoint
+}
+
+// ExercisedOperations returns the operations that were exercised by at least one request.
+func (r *Report) ExercisedOperations() []*OperationCoverage {
+	var ops []*OperationCoverage
+	for _, o := range r.Operations {
+		if o.Exercised() {
+			ops = append(ops, o)
+		}
+	}
+	return ops
+}
+
+// Coverage returns the fraction of operations that were exercised, between 0 and 1.
+func (r *Report) Coverage() float64 {
+	if len(r.Operations) == 0 {
+		return 0
+	}
+	return float64(len(r.ExercisedOperations())) / float64(len(r.Operations))
+}
+
+// Analyze matches every record to an operation in the document (webhooks are not matched), and builds a
+// coverage report. BasePath is stripped from the start of every record path before it is matched,
+// for example `/v1`, but only on a segment boundary, so `/v1beta/pets` keeps its prefix.
+func Analyze(drDoc *model.DrDocument, records []*Record, basePath string) *Report {
+	report := &Report{Requests: len(records)}
+	byOperation := make(map[string]*OperationCoverage)
+	for _, po := range drDoc.PathOperations() {
+		oc := &OperationCoverage{PathOperation: po, DeclaredCodes: declaredCodes(po)}
+		byOperation[po.Method+" "+po.Path] = oc
+		report.Operations = append(report.Operations, oc)
+	}
+
+	undocumented := make(map[string]*UndocumentedEndpoint)
+	for _, rec := range records {
+		path := routing.TrimBasePath(requestPath(rec.URL), basePath)
+		match, err := drDoc.MatchOperation(rec.Method, path)
+		if err != nil {
+			key := strings.ToUpper(rec.Method) + " " + path
+			ue, ok := undocumented[key]
+			if !ok {
+				ue = &UndocumentedEndpoint{
+					Method:           strings.ToUpper(rec.Method),
+					Path:             path,
+					MethodNotAllowed: errors.Is(err, model.ErrMethodNotAllowed),
+				}
+				undocumented[key] = ue
+				report.Undocumented = append(report.Undocumented, ue)
+			}
+			ue.Requests++
+			if rec.Status > 0 && !slices.Contains(ue.Statuses, rec.Status) {
+				ue.Statuses = append(ue.Statuses, rec.Status)
+			}
+			continue
+		}
+		oc := byOperation[match.Method+" "+match.Path]
+		if oc == nil {
+			continue
+		}
+		oc.Requests++
+		if rec.Status > 0 && !slices.Contains(oc.SeenCodes, rec.Status) {
+			oc.SeenCodes = append(oc.SeenCodes, rec.Status)
+		}
+	}
+
+	for _, oc := range report.Operations {
+		sort.Ints(oc.SeenCodes)
+		for _, code := range oc.SeenCodes {
+			if !isDeclared(oc.DeclaredCodes, code) {
+				oc.UndeclaredCodes = append(oc.UndeclaredCodes, code)
+			}
+		}
+		for _, declared := range oc.DeclaredCodes {
+			if !slices.ContainsFunc(oc.SeenCodes, func(code int) bool { return codeMatches(declared, code) }) {
+				oc.UnseenCodes = append(oc.UnseenCodes, declared)
+			}
+		}
+	}
+	for _, ue := range report.Undocumented {
+		sort.Ints(ue.Statuses)
+	}
+	return report
+}
+
+// declaredCodes returns the response codes declared by an operation, including `default`.
+func declaredCodes(po *model.PathOperation) []string {
+	var codes []string
+	if po.Operation == nil || po.Operation.Responses == nil {
+		return nil
+	}
+	if po.Operation.Responses.Codes != nil {
+		for code := range po.Operation.Responses.Codes.KeysFromOldest() {
+			codes = append(codes, code)
+		}
+	}
+	if po.Operation.Responses.Default != nil {
+		codes = append(codes, "default")
+	}
+	return codes
+}
+
+// isDeclared checks a status code is covered by a declared code, a range (like `4XX`) or `default`.
+func isDeclared(declared []string, code int) bool {
+	for _, d := range declared {
+		if d == "default" || codeMatches(d, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// codeMatches checks a status code matches a declared code, or a declared range (like `4XX`).
+func codeMatches(declared string, code int) bool {
+	d := strings.ToUpper(declared)
+	if len(d) == 3 && strings.HasSuffix(d, "XX") {
+		return strconv.Itoa(code/100) == d[:1]
+	}
+	return d == strconv.Itoa(code)
+}
+
+// requestPath extracts the path from a full URL or a path with a query.
+func requestPath(u string) string {
+	if _, rest, ok := strings.Cut(u, "://"); ok {
+		if i := strings.Index(rest, "/"); i >= 0 {
+			u = rest[i:]
+		} else {
+			u = "/"
+		}
+	}
+	path, _, _ := strings.Cut(u, "?")
+	path, _, _ = strings.Cut(path, "#")
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// har is the subset of the HAR 1.2 format needed to extract records.
+type har struct {
+	Log struct {
+		Entries []struct {
+			Request struct {
+				Method string `json:"method"`
+				URL    string `json:"url"`
+			} `json:"request"`
+			Response struct {
+				Status int `json:"status"`
+			} `json:"response"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+// ParseHAR extracts a record for every entry of a HAR (HTTP archive) file.
+func ParseHAR(reader io.Reader) ([]*Record, error) {
+	var h har
+	if err := json.NewDecoder(reader).Decode(&h); err != nil {
+		return nil, fmt.Errorf("unable to parse HAR: %w", err)
+	}
+	records := make([]*Record, 0, len(h.Log.Entries))
+	for _, e := range h.Log.Entries {
+		records = append(records, &Record{Method: e.Request.Method, URL: e.Request.URL, Status: e.Response.Status})
+	}
+	return records, nil
+}
+
+// ParseRecords reads simple records, one per line, as a method, URL and (optional) status code separated
+// by whitespace, for example `GET /burgers/1234 200`. Empty lines and lines starting with `#` are skipped.
+func ParseRecords(reader io.Reader) ([]*Record, error) {
+	var records []*Record
+	scanner := bufio.NewScanner(reader)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: expected a method and a URL", line)
+		}
+		rec := &Record{Method: fields[0], URL: fields[1]}
+		if len(fields) > 2 {
+			status, err := strconv.Atoi(fields[2])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid status code '%s'", line, fields[2])
+			}
+			rec.Status = status
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
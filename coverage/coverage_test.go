@@ -0,0 +1,92 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package coverage
+
+import (
+	"github.com/pb33f/doctor/model"
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"strings"
+	"testing"
+)
+
+func loadBurgerShop(t *testing.T) *model.DrDocument {
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	doc, err := libopenapi.NewDocument(bytes)
+	require.NoError(t, err)
+	v3Doc, errs := doc.BuildV3Model()
+	require.Empty(t, errs)
+	return model.NewDrDocument(v3Doc)
+}
+
+func TestAnalyze(t *testing.T) {
+	records, err := ParseRecords(strings.NewReader(`# traffic from the load balancer
+GET https://api.pb33f.io/v1/burgers/1234 200
+GET /v1/burgers/5678?fries=true 418
+GET /v1/burgers/9999 200
+PATCH /v1/burgers/1234 405
+GET /v1/pizza 404
+`))
+	require.NoError(t, err)
+	require.Len(t, records, 5)
+
+	report := Analyze(loadBurgerShop(t), records, "/v1/")
+	assert.Equal(t, 5, report.Requests)
+	require.Len(t, report.ExercisedOperations(), 1)
+
+	locate := report.ExercisedOperations()[0]
+	assert.Equal(t, "locateBurger", locate.Operation.Value.OperationId)
+	assert.Equal(t, 3, locate.Requests)
+	assert.Equal(t, []string{"200", "404", "500"}, locate.DeclaredCodes)
+	assert.Equal(t, []int{200, 418}, locate.SeenCodes)
+	assert.Equal(t, []int{418}, locate.UndeclaredCodes)
+	assert.Equal(t, []string{"404", "500"}, locate.UnseenCodes)
+	assert.InDelta(t, 1/float64(len(report.Operations)), report.Coverage(), 0.0001)
+
+	require.Len(t, report.Undocumented, 2)
+	assert.Equal(t, "PATCH", report.Undocumented[0].Method)
+	assert.True(t, report.Undocumented[0].MethodNotAllowed)
+	assert.Equal(t, "/pizza", report.Undocumented[1].Path)
+	assert.Equal(t, []int{404}, report.Undocumented[1].Statuses)
+	assert.False(t, report.Undocumented[1].MethodNotAllowed)
+}
+
+func TestAnalyze_BasePathBoundary(t *testing.T) {
+	records, err := ParseRecords(strings.NewReader(`GET /v1beta/burgers/1234 200
+GET /v1 200
+`))
+	require.NoError(t, err)
+
+	report := Analyze(loadBurgerShop(t), records, "/v1")
+	assert.Empty(t, report.ExercisedOperations())
+	require.Len(t, report.Undocumented, 2)
+	assert.Equal(t, "/v1beta/burgers/1234", report.Undocumented[0].Path)
+	assert.Equal(t, "/", report.Undocumented[1].Path)
+}
+
+func TestParseHAR(t *testing.T) {
+	records, err := ParseHAR(strings.NewReader(`{"log": {"version": "1.2", "entries": [
+		{"request": {"method": "POST", "url": "https://api.pb33f.io/burgers"}, "response": {"status": 200}},
+		{"request": {"method": "GET", "url": "https://api.pb33f.io/dressings"}, "response": {"status": 500}}
+	]}}`))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, &Record{Method: "POST", URL: "https://api.pb33f.io/burgers", Status: 200}, records[0])
+
+	report := Analyze(loadBurgerShop(t), records, "")
+	assert.Len(t, report.ExercisedOperations(), 2)
+	assert.Empty(t, report.Undocumented)
+
+	_, err = ParseHAR(strings.NewReader(`{"log":`))
+	assert.Error(t, err)
+}
+
+func TestParseRecords_Invalid(t *testing.T) {
+	_, err := ParseRecords(strings.NewReader("GET"))
+	assert.EqualError(t, err, "line 1: expected a method and a URL")
+	_, err = ParseRecords(strings.NewReader("GET /burgers ok"))
+	assert.EqualError(t, err, "line 1: invalid status code 'ok'")
+}
@@ -0,0 +1,233 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package doctor is the high level entry point to the doctor. Run loads a specification (from bytes, a file or a
+// URL), walks it into a DrDocument, runs registered rules against it and compares it to a base version, and returns
+// everything in a single result, so applications that embed the doctor do not have to orchestrate each step.
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pb33f/doctor/loader"
+	"github.com/pb33f/doctor/model"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	whatChanged "github.com/pb33f/libopenapi/what-changed"
+	wcModel "github.com/pb33f/libopenapi/what-changed/model"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Rule checks a walked document, and returns the problems it finds.
+type Rule func(drDoc *model.DrDocument) []*drBase.Finding
+
+var (
+	rulesLock sync.RWMutex
+	rules     = map[string]Rule{}
+)
+
+// the doctor's built-in analyzers are registered as rules.
+func init() {
+	RegisterRule("paths", func(drDoc *model.DrDocument) []*drBase.Finding {
+		return embedded(drDoc.AnalyzePaths(), func(f *model.PathFinding) *drBase.Finding { return f.Finding })
+	})
+	RegisterRule("refs", func(drDoc *model.DrDocument) []*drBase.Finding {
+		return embedded(drDoc.CheckRefs(), func(f *model.RefFinding) *drBase.Finding { return f.Finding })
+	})
+	RegisterRule("links", func(drDoc *model.DrDocument) []*drBase.Finding {
+		return embedded(drDoc.ValidateLinks(), func(f *model.LinkFinding) *drBase.Finding { return f.Finding })
+	})
+	RegisterRule("parameters", func(drDoc *model.DrDocument) []*drBase.Finding {
+		return drDoc.ParameterConsistency().Findings
+	})
+	RegisterRule("headers", func(drDoc *model.DrDocument) []*drBase.Finding {
+		return drDoc.HeaderInventory(nil).Findings
+	})
+	RegisterRule("response-headers", func(drDoc *model.DrDocument) []*drBase.Finding {
+		return drDoc.ResponseHeaderPropagation().Findings
+	})
+}
+
+// RegisterRule registers a rule under a name, replacing any rule already registered with the name. Rules are run
+// by Run when they are named in Options.Rules, or when Options.AllRules is set.
+func RegisterRule(name string, rule Rule) {
+	rulesLock.Lock()
+	defer rulesLock.Unlock()
+	rules[name] = rule
+}
+
+// RegisteredRules returns the names of every registered rule, sorted.
+func RegisteredRules() []string {
+	rulesLock.RLock()
+	defer rulesLock.RUnlock()
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Source is where a specification is loaded from, Spec when it is set, otherwise Location (a file path, or an
+// http(s) URL). When DocumentConfiguration is nil, references are resolved relative to the location.
+type Source struct {
+	Spec                  []byte
+	Location              string
+	DocumentConfiguration *datamodel.DocumentConfiguration
+}
+
+// Options controls what Run does.
+type Options struct {
+	// Spec is the specification to check.
+	Spec Source
+
+	// Base is compared to the specification when it is set, the changes are the changes made since the base.
+	Base *Source
+
+	// Config is used to walk the specification, nil uses the same defaults as model.NewDrDocument.
+	Config *model.DrConfig
+
+	// Rules are the names of the registered rules to run (see RegisterRule), every registered rule is run when
+	// AllRules is set.
+	Rules    []string
+	AllRules bool
+
+	// Loader fetches specifications from URLs, and the remote files they reference (see loader.Config for
+	// authentication, caching and checksums). A loader with the default config is used when nil.
+	Loader *loader.Loader
+}
+
+// Result is everything Run produced. BuildErrors are the errors building the model that did not stop it from
+// being built, Findings are the findings of every rule that was run, in the order the rules were run, and Changes
+// are the changes since the base (nil without a base, or when nothing changed).
+type Result struct {
+	Model       *libopenapi.DocumentModel[v3.Document]
+	Document    *model.DrDocument
+	BuildErrors []error
+	Findings    []*drBase.Finding
+	Changes     *wcModel.DocumentChanges
+}
+
+// Run loads, builds and walks a specification, then runs the chosen rules against it and compares it to the base.
+// Rules are resolved before anything is loaded, an unknown rule is an error.
+func Run(opts *Options) (*Result, error) {
+	if opts == nil {
+		return nil, errors.New("no options supplied")
+	}
+	names := opts.Rules
+	if opts.AllRules {
+		names = RegisteredRules()
+	}
+	selected := make([]Rule, 0, len(names))
+	rulesLock.RLock()
+	for _, name := range names {
+		rule, ok := rules[name]
+		if !ok {
+			rulesLock.RUnlock()
+			return nil, fmt.Errorf("unknown rule '%s'", name)
+		}
+		selected = append(selected, rule)
+	}
+	rulesLock.RUnlock()
+
+	v3Doc, errs, err := opts.build("specification", &opts.Spec)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Model: v3Doc, BuildErrors: errs}
+	if opts.Config != nil {
+		result.Document = model.NewDrDocumentWithConfig(v3Doc, opts.Config)
+	} else {
+		result.Document = model.NewDrDocument(v3Doc)
+	}
+	for _, rule := range selected {
+		result.Findings = append(result.Findings, rule(result.Document)...)
+	}
+
+	if opts.Base != nil {
+		baseDoc, _, err := opts.build("base specification", opts.Base)
+		if err != nil {
+			return nil, err
+		}
+		result.Changes = whatChanged.CompareOpenAPIDocuments(baseDoc.Model.GoLow(), v3Doc.Model.GoLow())
+	}
+	return result, nil
+}
+
+// build loads a specification from its source and builds its model.
+func (o *Options) build(name string, source *Source) (*libopenapi.DocumentModel[v3.Document], []error, error) {
+	spec, docConfig := source.Spec, source.DocumentConfiguration
+	if spec == nil {
+		if source.Location == "" {
+			return nil, nil, fmt.Errorf("no %s supplied", name)
+		}
+		var err error
+		if spec, err = o.read(source.Location); err != nil {
+			return nil, nil, fmt.Errorf("unable to read %s '%s': %w", name, source.Location, err)
+		}
+		if docConfig == nil {
+			if docConfig, err = o.locationConfiguration(source.Location); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	var doc libopenapi.Document
+	var err error
+	if docConfig != nil {
+		doc, err = libopenapi.NewDocumentWithConfiguration(spec, docConfig)
+	} else {
+		doc, err = libopenapi.NewDocument(spec)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to parse %s: %w", name, err)
+	}
+	v3Doc, errs := doc.BuildV3Model()
+	if v3Doc == nil {
+		return nil, nil, fmt.Errorf("unable to build %s: %w", name, errors.Join(errs...))
+	}
+	return v3Doc, errs, nil
+}
+
+// read reads a specification from a file, or fetches it from a URL.
+func (o *Options) read(location string) ([]byte, error) {
+	if !isURL(location) {
+		return os.ReadFile(location)
+	}
+	return o.loader().Fetch(context.Background(), location)
+}
+
+// locationConfiguration resolves references relative to the file or URL a specification was loaded from.
+func (o *Options) locationConfiguration(location string) (*datamodel.DocumentConfiguration, error) {
+	if !isURL(location) {
+		return &datamodel.DocumentConfiguration{BasePath: filepath.Dir(location), AllowFileReferences: true}, nil
+	}
+	return o.loader().DocumentConfiguration(location)
+}
+
+func (o *Options) loader() *loader.Loader {
+	if o.Loader == nil {
+		return loader.NewLoader(nil)
+	}
+	return o.Loader
+}
+
+func isURL(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// embedded returns the findings embedded in the findings of an analyzer.
+func embedded[T any](findings []T, finding func(T) *drBase.Finding) []*drBase.Finding {
+	out := make([]*drBase.Finding, 0, len(findings))
+	for _, f := range findings {
+		out = append(out, finding(f))
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package doctor
+
+import (
+	"github.com/pb33f/doctor/loader"
+	"github.com/pb33f/doctor/model"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	RegisterRule("test-operation-count", func(drDoc *model.DrDocument) []*drBase.Finding {
+		return []*drBase.Finding{drBase.NewFinding("operation-count", drBase.SeverityInfo,
+			"operations counted", drDoc.V3Document)}
+	})
+	assert.Contains(t, RegisteredRules(), "paths")
+	assert.Contains(t, RegisteredRules(), "test-operation-count")
+
+	spec, err := os.ReadFile("test_specs/burgershop.openapi.yaml")
+	require.NoError(t, err)
+	base := strings.Replace(string(spec), "version: \"1.2\"", "version: \"1.1\"", 1)
+
+	result, err := Run(&Options{
+		Spec:  Source{Location: "test_specs/burgershop.openapi.yaml"},
+		Base:  &Source{Spec: []byte(base)},
+		Rules: []string{"test-operation-count"},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Document)
+	assert.NotEmpty(t, result.Document.Operations())
+	require.Len(t, result.Findings, 1)
+	assert.Equal(t, "$", result.Findings[0].Path)
+	require.NotNil(t, result.Changes)
+	assert.Equal(t, 1, result.Changes.TotalChanges())
+
+	// every rule, without a base.
+	result, err = Run(&Options{Spec: Source{Spec: spec}, AllRules: true})
+	require.NoError(t, err)
+	assert.Nil(t, result.Changes)
+	assert.NotEmpty(t, result.Findings)
+}
+
+func TestRun_URL(t *testing.T) {
+	spec, err := os.ReadFile("test_specs/burgershop.openapi.yaml")
+	require.NoError(t, err)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/specs/burgershop.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(spec)
+	}))
+	defer server.Close()
+
+	l := loader.NewLoader(&loader.Config{Client: server.Client()})
+	result, err := Run(&Options{Spec: Source{Location: server.URL + "/specs/burgershop.yaml"}, Loader: l})
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.Document.Operations())
+
+	_, err = Run(&Options{Spec: Source{Location: server.URL + "/missing.yaml"}, Loader: l})
+	assert.ErrorContains(t, err, "unexpected status '404 Not Found'")
+}
+
+func TestRun_Errors(t *testing.T) {
+	_, err := Run(nil)
+	assert.EqualError(t, err, "no options supplied")
+
+	_, err = Run(&Options{})
+	assert.EqualError(t, err, "no specification supplied")
+
+	_, err = Run(&Options{Spec: Source{Spec: []byte("openapi: 3.1.0")}, Rules: []string{"nope"}})
+	assert.EqualError(t, err, "unknown rule 'nope'")
+
+	_, err = Run(&Options{Spec: Source{Location: "test_specs/missing.yaml"}})
+	assert.ErrorContains(t, err, "unable to read specification 'test_specs/missing.yaml'")
+
+	_, err = Run(&Options{Spec: Source{Spec: []byte("openapi: 3.1.0")}, Base: &Source{}})
+	assert.EqualError(t, err, "no base specification supplied")
+}
@@ -0,0 +1,220 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package events is a small event bus shared by the subsystems of the doctor (walking, reporting and GitHub), so
+// progress, warnings and rate limits from all of them reach a consumer through one subscription.
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultBuffer is the number of events a subscription holds when it is not given a buffer.
+const DefaultBuffer = 64
+
+// Sources of the events published by the doctor.
+const (
+	SourceWalker      = "walker"
+	SourceChangerator = "changerator"
+	SourceGitHub      = "github"
+)
+
+// DropPolicy decides what happens to an event published to a subscription with a full buffer.
+type DropPolicy int
+
+const (
+	// DropNewest drops the event being published, the events already in the buffer are kept.
+	DropNewest DropPolicy = iota
+
+	// DropOldest drops the oldest event in the buffer to make room for the event being published.
+	DropOldest
+
+	// Block blocks the publisher until the consumer makes room, or the subscription is closed. Slow consumers
+	// slow down the subsystems publishing to them.
+	Block
+)
+
+// Topic names a kind of event, and the type of its payload.
+type Topic[T any] struct {
+	name string
+}
+
+// NewTopic returns a topic with a name, topics with the same name are the same topic.
+func NewTopic[T any](name string) Topic[T] {
+	return Topic[T]{name: name}
+}
+
+// Name returns the name of the topic.
+func (t Topic[T]) Name() string {
+	return t.name
+}
+
+// Named is a topic of any payload type, it is what subscriptions are made with.
+type Named interface {
+	Name() string
+}
+
+// Event is an event published to a bus.
+type Event struct {
+	// Topic is the name of the topic the event was published to, use Payload to read its payload.
+	Topic string
+
+	// Source is the subsystem that published the event (for example SourceWalker).
+	Source string
+
+	// Time is when the event was published.
+	Time time.Time
+
+	// Payload is the payload of the event, of the type of its topic.
+	Payload any
+}
+
+// Bus delivers published events to its subscriptions. A nil bus drops every event, so subsystems can publish
+// without checking whether anyone is listening. A bus is safe for concurrent use.
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions []*Subscription
+	closed        bool
+}
+
+// NewBus creates an empty bus.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Publish publishes a payload to a topic of a bus, from a source.
+func Publish[T any](b *Bus, topic Topic[T], source string, payload T) {
+	if b == nil {
+		return
+	}
+	b.publish(&Event{Topic: topic.name, Source: source, Time: time.Now(), Payload: payload})
+}
+
+func (b *Bus) publish(e *Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, s := range b.subscriptions {
+		if len(s.topics) == 0 || s.topics[e.Topic] {
+			s.deliver(e)
+		}
+	}
+}
+
+// Subscribe subscribes to topics of a bus, or to every topic when none are given. The subscription buffers up to
+// buffer events (DefaultBuffer when zero or less), and applies a drop policy when its buffer is full. Subscribing
+// to a closed bus returns a closed subscription.
+func (b *Bus) Subscribe(buffer int, policy DropPolicy, topics ...Named) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+	s := &Subscription{
+		bus:    b,
+		policy: policy,
+		events: make(chan *Event, buffer),
+		done:   make(chan struct{}),
+	}
+	if len(topics) > 0 {
+		s.topics = make(map[string]bool, len(topics))
+		for _, t := range topics {
+			s.topics[t.Name()] = true
+		}
+	}
+	b.mu.Lock()
+	closed := b.closed
+	if !closed {
+		b.subscriptions = append(b.subscriptions, s)
+	}
+	b.mu.Unlock()
+	if closed {
+		s.Close()
+	}
+	return s
+}
+
+// Close closes every subscription of a bus, events published after it is closed are dropped.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	b.closed = true
+	subscriptions := b.subscriptions
+	b.mu.Unlock()
+	for _, s := range subscriptions {
+		s.Close()
+	}
+}
+
+// Subscription receives the events published to the topics it subscribed to.
+type Subscription struct {
+	bus     *Bus
+	topics  map[string]bool
+	policy  DropPolicy
+	events  chan *Event
+	done    chan struct{}
+	mu      sync.Mutex
+	once    sync.Once
+	dropped atomic.Uint64
+}
+
+// Events returns the channel events are received from, it is closed when the subscription is closed.
+func (s *Subscription) Events() <-chan *Event {
+	return s.events
+}
+
+// Dropped returns the number of events the drop policy of the subscription has dropped.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close unsubscribes from the bus and closes the channel of events, events still in the buffer can be received.
+// Publishers blocked on the subscription are released.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		close(s.done)
+		// publishers hold the read lock while delivering, nothing delivers once the subscription is removed.
+		s.bus.mu.Lock()
+		for i, sub := range s.bus.subscriptions {
+			if sub == s {
+				s.bus.subscriptions = append(s.bus.subscriptions[:i:i], s.bus.subscriptions[i+1:]...)
+				break
+			}
+		}
+		s.bus.mu.Unlock()
+		close(s.events)
+	})
+}
+
+func (s *Subscription) deliver(e *Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.done:
+		return
+	case s.events <- e:
+		return
+	default:
+	}
+	switch s.policy {
+	case Block:
+		select {
+		case s.events <- e:
+		case <-s.done:
+			s.dropped.Add(1)
+		}
+	case DropOldest:
+		for {
+			select {
+			case s.events <- e:
+				return
+			default:
+			}
+			select {
+			case <-s.events:
+				s.dropped.Add(1)
+			default:
+			}
+		}
+	default:
+		s.dropped.Add(1)
+	}
+}
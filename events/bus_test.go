@@ -0,0 +1,112 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package events
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+func messages(s *Subscription) []string {
+	var m []string
+	for {
+		select {
+		case e, ok := <-s.Events():
+			if !ok {
+				return m
+			}
+			if p, is := Payload(e, Warning); is {
+				m = append(m, p.Message)
+			}
+			if p, is := Payload(e, Progress); is {
+				m = append(m, p.Message)
+			}
+		default:
+			return m
+		}
+	}
+}
+
+func TestBus_Subscribe(t *testing.T) {
+	bus := NewBus()
+	all := bus.Subscribe(0, DropNewest)
+	warnings := bus.Subscribe(0, DropNewest, Warning)
+
+	Publish(bus, Progress, SourceWalker, &ProgressEvent{Message: "walking"})
+	Publish(bus, Warning, SourceGitHub, &WarningEvent{Message: "burgers"})
+
+	assert.Equal(t, []string{"walking", "burgers"}, messages(all))
+	assert.Equal(t, []string{"burgers"}, messages(warnings))
+
+	warnings.Close()
+	warnings.Close()
+	Publish(bus, Warning, SourceGitHub, &WarningEvent{Message: "fries"})
+	assert.Equal(t, []string{"fries"}, messages(all))
+	_, open := <-warnings.Events()
+	assert.False(t, open)
+
+	bus.Close()
+	_, open = <-all.Events()
+	assert.False(t, open)
+	late := bus.Subscribe(1, DropNewest)
+	_, open = <-late.Events()
+	assert.False(t, open)
+
+	// a nil bus drops everything.
+	Publish(nil, Warning, SourceWalker, &WarningEvent{Message: "nobody"})
+}
+
+func TestBus_DropPolicies(t *testing.T) {
+	bus := NewBus()
+	newest := bus.Subscribe(2, DropNewest)
+	oldest := bus.Subscribe(2, DropOldest)
+	for _, m := range []string{"one", "two", "three"} {
+		Publish(bus, Warning, SourceWalker, &WarningEvent{Message: m})
+	}
+	assert.Equal(t, []string{"one", "two"}, messages(newest))
+	assert.Equal(t, uint64(1), newest.Dropped())
+	assert.Equal(t, []string{"two", "three"}, messages(oldest))
+	assert.Equal(t, uint64(1), oldest.Dropped())
+}
+
+func TestBus_Block(t *testing.T) {
+	bus := NewBus()
+	blocking := bus.Subscribe(1, Block)
+	Publish(bus, Progress, SourceWalker, &ProgressEvent{Message: "one"})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		Publish(bus, Progress, SourceWalker, &ProgressEvent{Message: "two"})
+	}()
+
+	e := <-blocking.Events()
+	p, ok := Payload(e, Progress)
+	require.True(t, ok)
+	assert.Equal(t, "one", p.Message)
+	select {
+	case e = <-blocking.Events():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the blocked publisher was not delivered")
+	}
+	p, _ = Payload(e, Progress)
+	assert.Equal(t, "two", p.Message)
+	wg.Wait()
+	assert.Zero(t, blocking.Dropped())
+
+	// closing releases blocked publishers.
+	Publish(bus, Progress, SourceWalker, &ProgressEvent{Message: "three"})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		Publish(bus, Progress, SourceWalker, &ProgressEvent{Message: "four"})
+	}()
+	time.Sleep(10 * time.Millisecond)
+	blocking.Close()
+	wg.Wait()
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package events
+
+import "time"
+
+// The topics published by the doctor.
+var (
+	Progress  = NewTopic[*ProgressEvent]("progress")
+	Warning   = NewTopic[*WarningEvent]("warning")
+	RateLimit = NewTopic[*RateLimitEvent]("rate-limit")
+)
+
+// ProgressEvent reports how far a stage of work (such as a walk, or a report) has come.
+type ProgressEvent struct {
+	// Stage is the work in progress (for example "walk"), Message describes the step it has reached.
+	Stage   string
+	Message string
+
+	// Done is the number of steps of the stage that are complete, out of Total. Total is zero when the number of
+	// steps is not known.
+	Done  int
+	Total int
+}
+
+// WarningEvent is a problem that did not stop the work publishing it, such as a schema that could not be built.
+type WarningEvent struct {
+	Message string
+
+	// Path is the JSONPath of the object the warning is about, when there is one.
+	Path string
+
+	// Err is the error behind the warning, if any.
+	Err error
+}
+
+// RateLimitEvent is the rate limit of an API, as reported by its last response.
+type RateLimitEvent struct {
+	// Resource is the rate limited resource (for example "core", for the GitHub REST API).
+	Resource string
+
+	Limit     int
+	Remaining int
+	Used      int
+
+	// Reset is when the rate limit resets, zero when it was not reported.
+	Reset time.Time
+}
+
+// Payload returns the payload of an event when it was published to a topic.
+func Payload[T any](e *Event, topic Topic[T]) (T, bool) {
+	var zero T
+	if e == nil || e.Topic != topic.name {
+		return zero, false
+	}
+	p, ok := e.Payload.(T)
+	return p, ok
+}
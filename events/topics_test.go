@@ -0,0 +1,25 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package events
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPayload(t *testing.T) {
+	e := &Event{Topic: RateLimit.Name(), Source: SourceGitHub, Payload: &RateLimitEvent{Remaining: 12}}
+	limit, ok := Payload(e, RateLimit)
+	assert.True(t, ok)
+	assert.Equal(t, 12, limit.Remaining)
+
+	_, ok = Payload(e, Warning)
+	assert.False(t, ok)
+	_, ok = Payload(nil, RateLimit)
+	assert.False(t, ok)
+
+	// topics with the same name are the same topic, payloads of another type are not returned.
+	_, ok = Payload(e, NewTopic[string]("rate-limit"))
+	assert.False(t, ok)
+}
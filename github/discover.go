@@ -0,0 +1,208 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/pb33f/doctor/events"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// MaxSniffedFiles is the number of files DiscoverSpecs reads the first bytes of, the files with the highest
+// confidence by name are read first.
+const MaxSniffedFiles = 50
+
+// sniffLength is the number of bytes read from the start of a file to find its version.
+const sniffLength = 1024
+
+// maxSpecSize is the size of the largest file that is considered a candidate.
+const maxSpecSize = 20 << 20
+
+// SpecCandidate is a file in a repository that could be an OpenAPI (or Swagger) document.
+type SpecCandidate struct {
+	// Path is the path of the file in the repository.
+	Path string `json:"path"`
+
+	// Confidence is how likely the file is a specification, from 0 to 1. Files that declare an OpenAPI or
+	// Swagger version in their first bytes have a confidence of at least 0.9, other files are scored by name.
+	Confidence float64 `json:"confidence"`
+
+	// Version is the OpenAPI (or Swagger) version the file declares, empty when the file was not read or the
+	// version is not in its first bytes.
+	Version string `json:"version,omitempty"`
+
+	// Sniffed is true when the first bytes of the file were read.
+	Sniffed bool `json:"sniffed"`
+}
+
+type treeEntry struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+	Size int64  `json:"size"`
+}
+
+type tree struct {
+	Tree      []*treeEntry `json:"tree"`
+	Truncated bool         `json:"truncated"`
+}
+
+// specExtensions are the extensions of files that can be specifications.
+var specExtensions = []string{".yaml", ".yml", ".json"}
+
+// ignoredDirectories hold files that are not the specifications of a repository.
+var ignoredDirectories = []string{"node_modules", "vendor", ".git", "testdata", "fixtures", "dist"}
+
+// versionPattern finds the openapi or swagger version at the start of a YAML or JSON document.
+var versionPattern = regexp.MustCompile(`(?m)^[\s{]*["']?(openapi|swagger)["']?\s*:\s*["']?([0-9]+(\.[0-9]+)*)`)
+
+// DiscoverSpecs scans the tree of a repository at a ref (a branch, tag or commit, HEAD when empty) for OpenAPI
+// documents. YAML and JSON files are scored by their name and directory, then the first bytes of the most likely
+// files (up to MaxSniffedFiles) are read: files that declare an OpenAPI or Swagger version are kept with a high
+// confidence, files that don't are dropped. Candidates are ordered by confidence, then path.
+//
+// Trees that are too large for GitHub to return in full are scanned as far as they are returned.
+func DiscoverSpecs(ctx context.Context, session *Session, owner, repo, ref string) ([]*SpecCandidate, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	repoPath := "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo)
+	resp, err := session.get(ctx, repoPath+"/git/trees/"+url.PathEscape(ref)+"?recursive=1", nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to discover specifications in '%s/%s@%s': %w", owner, repo, ref, err)
+	}
+	var t tree
+	err = json.NewDecoder(resp.Body).Decode(&t)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the tree of '%s/%s@%s': %w", owner, repo, ref, err)
+	}
+
+	var candidates []*SpecCandidate
+	for _, entry := range t.Tree {
+		if entry.Type != "blob" || entry.Size > maxSpecSize {
+			continue
+		}
+		if c := nameConfidence(entry.Path); c > 0 {
+			candidates = append(candidates, &SpecCandidate{Path: entry.Path, Confidence: c})
+		}
+	}
+	sortCandidates(candidates)
+
+	var discovered []*SpecCandidate
+	sniffed := min(len(candidates), MaxSniffedFiles)
+	for i, c := range candidates {
+		if i >= MaxSniffedFiles {
+			discovered = append(discovered, c)
+			continue
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		session.progress(i, sniffed, "reading "+c.Path)
+		version, sniffErr := sniff(ctx, session, repoPath, c.Path, ref)
+		if sniffErr != nil {
+			// files that can't be read keep the confidence of their name.
+			discovered = append(discovered, c)
+			continue
+		}
+		c.Sniffed = true
+		if version == "" {
+			continue
+		}
+		c.Version = version
+		c.Confidence = 0.9 + c.Confidence/10
+		discovered = append(discovered, c)
+	}
+	session.progress(sniffed, sniffed, fmt.Sprintf("discovered %d specifications", len(discovered)))
+	sortCandidates(discovered)
+	return discovered, nil
+}
+
+// nameConfidence scores a path by its name and directories, paths that can't be specifications score 0.
+func nameConfidence(p string) float64 {
+	ext := strings.ToLower(path.Ext(p))
+	if !slices.Contains(specExtensions, ext) {
+		return 0
+	}
+	dirs := strings.Split(strings.ToLower(path.Dir(p)), "/")
+	if slices.ContainsFunc(dirs, func(d string) bool { return slices.Contains(ignoredDirectories, d) }) {
+		return 0
+	}
+	name := strings.TrimSuffix(strings.ToLower(path.Base(p)), ext)
+	var confidence float64
+	switch {
+	case name == "openapi" || name == "swagger":
+		confidence = 0.6
+	case strings.Contains(name, "openapi") || strings.Contains(name, "swagger"):
+		confidence = 0.5
+	case name == "api" || strings.HasPrefix(name, "api.") || strings.HasPrefix(name, "api-") ||
+		strings.HasSuffix(name, ".api") || strings.HasSuffix(name, "-api") || strings.Contains(name, "spec"):
+		confidence = 0.3
+	default:
+		confidence = 0.1
+	}
+	for _, d := range dirs {
+		switch d {
+		case "openapi", "swagger", "spec", "specs", "api", "apis", "docs", "schema", "schemas":
+			return confidence + 0.1
+		}
+	}
+	return confidence
+}
+
+// sniff reads the first bytes of a file and returns the OpenAPI or Swagger version it declares.
+func sniff(ctx context.Context, session *Session, repoPath, p, ref string) (string, error) {
+	header := http.Header{
+		"Accept": {"application/vnd.github.raw+json"},
+		"Range":  {fmt.Sprintf("bytes=0-%d", sniffLength-1)},
+	}
+	resp, err := session.get(ctx, contentsPath(repoPath, p, ref), header)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, sniffLength))
+	if err != nil {
+		return "", err
+	}
+	if m := versionPattern.FindSubmatch(b); m != nil {
+		return string(m[2]), nil
+	}
+	return "", nil
+}
+
+// contentsPath returns the API path of the contents of a file in a repository at a ref.
+func contentsPath(repoPath, p, ref string) string {
+	var escaped []string
+	for _, segment := range strings.Split(p, "/") {
+		escaped = append(escaped, url.PathEscape(segment))
+	}
+	return repoPath + "/contents/" + strings.Join(escaped, "/") + "?ref=" + url.QueryEscape(ref)
+}
+
+// progress publishes the progress of discovering specifications to the events of the session.
+func (s *Session) progress(done, total int, message string) {
+	if s != nil {
+		events.Publish(s.Events, events.Progress, events.SourceGitHub,
+			&events.ProgressEvent{Stage: "discover", Message: message, Done: done, Total: total})
+	}
+}
+
+func sortCandidates(candidates []*SpecCandidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Confidence != candidates[j].Confidence {
+			return candidates[i].Confidence > candidates[j].Confidence
+		}
+		return candidates[i].Path < candidates[j].Path
+	})
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pb33f/doctor/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func githubServer(t *testing.T, files map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer burgers", r.Header.Get("Authorization"))
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		w.Header().Set("X-RateLimit-Used", "1")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		w.Header().Set("X-RateLimit-Resource", "core")
+		switch {
+		case r.URL.Path == "/repos/pb33f/shop/git/trees/main":
+			assert.Equal(t, "1", r.URL.Query().Get("recursive"))
+			var entries []*treeEntry
+			for p, content := range files {
+				entries = append(entries, &treeEntry{Path: p, Type: "blob", Size: int64(len(content))})
+			}
+			entries = append(entries, &treeEntry{Path: "specs", Type: "tree"})
+			_ = json.NewEncoder(w).Encode(&tree{Tree: entries})
+		case strings.HasPrefix(r.URL.Path, "/repos/pb33f/shop/contents/"):
+			assert.Equal(t, "main", r.URL.Query().Get("ref"))
+			content, ok := files[strings.TrimPrefix(r.URL.Path, "/repos/pb33f/shop/contents/")]
+			if !ok || strings.Contains(r.URL.Path, "private") {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(content))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestDiscoverSpecs(t *testing.T) {
+	server := githubServer(t, map[string]string{
+		"openapi.yaml":                    "openapi: 3.1.0\ninfo:\n  title: burgers\n",
+		"specs/legacy.json":               `{"swagger": "2.0", "info": {"title": "burgers"}}`,
+		"specs/burgers-api.yml":           "# burgers\n\"openapi\": '3.0.3'\n",
+		"docker-compose.yml":              "services:\n  shop:\n    image: burgers\n",
+		"docs/private-openapi.yaml":       "openapi: 3.1.0\n",
+		"node_modules/thing/openapi.yaml": "openapi: 3.1.0\n",
+		"README.md":                       "# shop",
+	})
+	defer server.Close()
+
+	session := &Session{Token: "burgers", BaseURL: server.URL}
+	candidates, err := DiscoverSpecs(context.Background(), session, "pb33f", "shop", "main")
+	require.NoError(t, err)
+
+	var paths, versions []string
+	for _, c := range candidates {
+		paths = append(paths, c.Path)
+		versions = append(versions, c.Version)
+	}
+	assert.Equal(t, []string{"openapi.yaml", "specs/burgers-api.yml", "specs/legacy.json", "docs/private-openapi.yaml"}, paths)
+	assert.Equal(t, []string{"3.1.0", "3.0.3", "2.0", ""}, versions)
+	assert.InDelta(t, 0.96, candidates[0].Confidence, 0.001)
+	assert.True(t, candidates[0].Sniffed)
+
+	// files that could not be read keep the confidence of their name.
+	assert.False(t, candidates[3].Sniffed)
+	assert.InDelta(t, 0.6, candidates[3].Confidence, 0.001)
+
+	_, err = DiscoverSpecs(context.Background(), session, "pb33f", "missing", "main")
+	assert.ErrorContains(t, err, "unexpected status '404 Not Found'")
+}
+
+func TestDiscoverSpecs_Events(t *testing.T) {
+	server := githubServer(t, map[string]string{
+		"openapi.yaml":      "openapi: 3.1.0\n",
+		"specs/legacy.json": `{"swagger": "2.0"}`,
+	})
+	defer server.Close()
+
+	bus := events.NewBus()
+	sub := bus.Subscribe(0, events.DropNewest)
+	session := &Session{Token: "burgers", BaseURL: server.URL, Events: bus}
+	_, err := DiscoverSpecs(context.Background(), session, "pb33f", "shop", "main")
+	require.NoError(t, err)
+	bus.Close()
+
+	var progress []string
+	var limits []*events.RateLimitEvent
+	for e := range sub.Events() {
+		assert.Equal(t, events.SourceGitHub, e.Source)
+		if p, ok := events.Payload(e, events.Progress); ok {
+			progress = append(progress, p.Message)
+			assert.Equal(t, 2, p.Total)
+		}
+		if l, ok := events.Payload(e, events.RateLimit); ok {
+			limits = append(limits, l)
+		}
+	}
+	assert.Equal(t, []string{"reading openapi.yaml", "reading specs/legacy.json", "discovered 2 specifications"}, progress)
+
+	// the tree, and both files.
+	require.Len(t, limits, 3)
+	assert.Equal(t, &events.RateLimitEvent{Resource: "core", Limit: 5000, Remaining: 4999, Used: 1,
+		Reset: time.Unix(1700000000, 0)}, limits[0])
+}
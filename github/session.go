@@ -0,0 +1,125 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package github reads specifications from GitHub repositories, and publishes the results of gates to their
+// commits, through the GitHub REST API.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/pb33f/doctor/events"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the URL of the GitHub REST API, GitHub Enterprise servers have their own.
+const DefaultBaseURL = "https://api.github.com"
+
+// DefaultTimeout is the timeout of a request when the session does not have a client.
+const DefaultTimeout = 30 * time.Second
+
+// Session is a connection to the GitHub REST API, a session without a token can only read public repositories
+// (and is rate limited by GitHub).
+type Session struct {
+	// Token is sent as a bearer token with every request, when set.
+	Token string
+
+	// BaseURL is the URL of the API, DefaultBaseURL when empty.
+	BaseURL string
+
+	// Client sends the requests, a client with DefaultTimeout is used when nil.
+	Client *http.Client
+
+	// Events receives the rate limit GitHub reports with every response on the events.RateLimit topic, and the
+	// progress of discovering specifications. Nothing is published when nil.
+	Events *events.Bus
+}
+
+// get sends a GET request for a path of the API, and returns the response when it is successful. The caller closes
+// the body of the response.
+func (s *Session) get(ctx context.Context, path string, header http.Header) (*http.Response, error) {
+	return s.do(ctx, http.MethodGet, path, header, nil)
+}
+
+// do sends a request for a path of the API, with a JSON body when it is not nil, and returns the response when it
+// is successful. The caller closes the body of the response.
+func (s *Session) do(ctx context.Context, method, path string, header http.Header, body any) (*http.Response, error) {
+	base := DefaultBaseURL
+	client := &http.Client{Timeout: DefaultTimeout}
+	token := ""
+	if s != nil {
+		if s.BaseURL != "" {
+			base = s.BaseURL
+		}
+		if s.Client != nil {
+			client = s.Client
+		}
+		token = s.Token
+	}
+	location := strings.TrimSuffix(base, "/") + path
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode request to '%s': %w", location, err)
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, location, reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to request '%s': %w", location, err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to request '%s': %w", location, err)
+	}
+	if s != nil {
+		if limit := rateLimit(resp.Header); limit != nil {
+			events.Publish(s.Events, events.RateLimit, events.SourceGitHub, limit)
+		}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		return nil, fmt.Errorf("unable to request '%s': unexpected status '%s'", location, resp.Status)
+	}
+	return resp, nil
+}
+
+// rateLimit reads the rate limit from the headers of a response, it returns nil when the response has none.
+func rateLimit(header http.Header) *events.RateLimitEvent {
+	if header.Get("X-RateLimit-Limit") == "" {
+		return nil
+	}
+	number := func(name string) int {
+		n, _ := strconv.Atoi(header.Get(name))
+		return n
+	}
+	limit := &events.RateLimitEvent{
+		Resource:  header.Get("X-RateLimit-Resource"),
+		Limit:     number("X-RateLimit-Limit"),
+		Remaining: number("X-RateLimit-Remaining"),
+		Used:      number("X-RateLimit-Used"),
+	}
+	if reset := number("X-RateLimit-Reset"); reset > 0 {
+		limit.Reset = time.Unix(int64(reset), 0)
+	}
+	return limit
+}
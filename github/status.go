@@ -0,0 +1,104 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package github
+
+import (
+	"context"
+	"fmt"
+	"github.com/pb33f/doctor/changerator"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// States of a commit status.
+const (
+	StatusPending = "pending"
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+	StatusError   = "error"
+)
+
+// DefaultStatusContext is the context of a commit status when it does not have one. Branch protection rules
+// require statuses by their context.
+const DefaultStatusContext = "pb33f/doctor"
+
+// maxDescriptionLength is the length of the longest description GitHub accepts.
+const maxDescriptionLength = 140
+
+// CommitStatus is a classic commit status, for repositories that require statuses (rather than check runs) in
+// their branch protection rules.
+type CommitStatus struct {
+	State       string `json:"state"`
+	TargetURL   string `json:"target_url,omitempty"`
+	Description string `json:"description,omitempty"`
+	Context     string `json:"context,omitempty"`
+}
+
+// GateStatus returns the commit status of the result of a change gate (see changerator.EvaluateGate): success when
+// it passed, failure with its reasons when it failed, and error when there is no result. The target URL links
+// to the details of the result, such as a report, and can be empty.
+func GateStatus(result *changerator.GateResult, context, targetURL string) *CommitStatus {
+	if result == nil {
+		return gateStatus(false, false, nil, "", context, targetURL)
+	}
+	summary := fmt.Sprintf("%d changes, %d breaking", result.TotalChanges, result.BreakingChanges)
+	return gateStatus(true, result.Passed, result.Reasons, summary, context, targetURL)
+}
+
+// SecurityGateStatus returns the commit status of the result of a security gate (see
+// changerator.RequireSecurity), the same way as GateStatus.
+func SecurityGateStatus(result *changerator.SecurityGateResult, context, targetURL string) *CommitStatus {
+	if result == nil {
+		return gateStatus(false, false, nil, "", context, targetURL)
+	}
+	return gateStatus(true, result.Passed, result.Reasons, "every operation meets the security policy", context, targetURL)
+}
+
+func gateStatus(evaluated, passed bool, reasons []string, summary, context, targetURL string) *CommitStatus {
+	if context == "" {
+		context = DefaultStatusContext
+	}
+	status := &CommitStatus{Context: context, TargetURL: targetURL}
+	switch {
+	case !evaluated:
+		status.State, status.Description = StatusError, "the gate was not evaluated"
+	case passed:
+		status.State, status.Description = StatusSuccess, "passed: "+summary
+	default:
+		status.State, status.Description = StatusFailure, "failed: "+strings.Join(reasons, "; ")
+	}
+	status.Description = truncateDescription(status.Description)
+	return status
+}
+
+// truncateDescription shortens a description to the length GitHub accepts, ending it with an ellipsis.
+func truncateDescription(description string) string {
+	if utf8.RuneCountInString(description) <= maxDescriptionLength {
+		return description
+	}
+	runes := []rune(description)
+	return string(runes[:maxDescriptionLength-1]) + "…"
+}
+
+// PublishCommitStatus creates a status for a commit (by SHA, or a branch or tag name). Statuses with the same
+// context replace each other, the latest is the one branch protection rules see.
+func PublishCommitStatus(ctx context.Context, session *Session, owner, repo, sha string, status *CommitStatus) error {
+	if status == nil {
+		return fmt.Errorf("unable to publish the status of '%s/%s@%s': no status", owner, repo, sha)
+	}
+	if status.Context == "" {
+		published := *status
+		published.Context = DefaultStatusContext
+		status = &published
+	}
+	path := "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/statuses/" + url.PathEscape(sha)
+	resp, err := session.do(ctx, http.MethodPost, path, nil, status)
+	if err != nil {
+		return fmt.Errorf("unable to publish the status of '%s/%s@%s': %w", owner, repo, sha, err)
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pb33f/doctor/changerator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestGateStatus(t *testing.T) {
+	status := GateStatus(&changerator.GateResult{Passed: true, TotalChanges: 3, BreakingChanges: 0}, "", "https://pb33f.io/report")
+	assert.Equal(t, &CommitStatus{State: StatusSuccess, Description: "passed: 3 changes, 0 breaking",
+		Context: DefaultStatusContext, TargetURL: "https://pb33f.io/report"}, status)
+
+	status = GateStatus(&changerator.GateResult{Reasons: []string{"2 breaking changes, 0 allowed", "forbidden change"}},
+		"api/gate", "")
+	assert.Equal(t, StatusFailure, status.State)
+	assert.Equal(t, "failed: 2 breaking changes, 0 allowed; forbidden change", status.Description)
+	assert.Equal(t, "api/gate", status.Context)
+
+	status = SecurityGateStatus(&changerator.SecurityGateResult{Reasons: []string{strings.Repeat("unsecured ", 20)}}, "", "")
+	assert.Equal(t, StatusFailure, status.State)
+	assert.Equal(t, 140, utf8.RuneCountInString(status.Description))
+	assert.True(t, strings.HasSuffix(status.Description, "…"))
+
+	assert.Equal(t, StatusError, GateStatus(nil, "", "").State)
+}
+
+func TestPublishCommitStatus(t *testing.T) {
+	var published CommitStatus
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		if r.URL.Path != "/repos/pb33f/shop/statuses/abc123" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, "Bearer burgers", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&published))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	session := &Session{Token: "burgers", BaseURL: server.URL}
+	err := PublishCommitStatus(context.Background(), session, "pb33f", "shop", "abc123",
+		&CommitStatus{State: StatusPending, Description: "evaluating"})
+	require.NoError(t, err)
+	assert.Equal(t, CommitStatus{State: StatusPending, Description: "evaluating", Context: DefaultStatusContext}, published)
+
+	err = PublishCommitStatus(context.Background(), session, "pb33f", "missing", "abc123", GateStatus(nil, "", ""))
+	assert.ErrorContains(t, err, "unexpected status")
+	assert.Error(t, PublishCommitStatus(context.Background(), session, "pb33f", "shop", "abc123", nil))
+}
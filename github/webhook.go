@@ -0,0 +1,346 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/pb33f/doctor/changerator"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// MaxWebhookSize is the size of the largest delivery a WebhookHandler reads, GitHub caps payloads at 25MB.
+const MaxWebhookSize = 25 << 20
+
+// specNameConfidence is the lowest name confidence (see DiscoverSpecs) of a pushed file that is a specification,
+// when the handler has no paths: files named like a specification, or any YAML or JSON file in an API directory.
+const specNameConfidence = 0.2
+
+// DefaultMaxPushes is the most pushes a WebhookHandler dispatches at once, when its MaxPushes is zero.
+const DefaultMaxPushes = 8
+
+// nullSHA is the SHA GitHub sends as the before (or after) of a push that creates (or deletes) a branch.
+const nullSHA = "0000000000000000000000000000000000000000"
+
+// PushEvent is a push to a repository that touched at least one specification.
+type PushEvent struct {
+	// Owner and Repo name the repository that was pushed to.
+	Owner string `json:"owner"`
+	Repo  string `json:"repo"`
+
+	// Ref is the full name of the ref that was pushed, for example refs/heads/main.
+	Ref string `json:"ref"`
+
+	// Before and After are the SHAs of the ref before and after the push. Before is empty when the push created
+	// the ref.
+	Before string `json:"before,omitempty"`
+	After  string `json:"after"`
+
+	// Pusher is the name of the user that pushed.
+	Pusher string `json:"pusher,omitempty"`
+
+	// Compare is the URL of the comparison of Before and After on GitHub.
+	Compare string `json:"compare,omitempty"`
+
+	// Added, Modified and Removed are the paths of the specifications the push added, modified and removed, across
+	// all of its commits: a specification that was added then modified was added, and one that was added then
+	// removed is not listed.
+	Added    []string `json:"added,omitempty"`
+	Modified []string `json:"modified,omitempty"`
+	Removed  []string `json:"removed,omitempty"`
+}
+
+// WebhookHandler receives push deliveries from a GitHub webhook, and calls OnPush for every push that touched a
+// specification. Ping deliveries are acknowledged, other events are ignored.
+type WebhookHandler struct {
+	// Secret is the secret of the webhook, every delivery must be signed with it. Every delivery is refused when
+	// empty, a webhook without a secret can be called by anyone.
+	Secret []byte
+
+	// OnPush is called with every push that touched a specification, in its own goroutine so deliveries are
+	// acknowledged before GitHub times them out.
+	OnPush func(event PushEvent)
+
+	// MaxPushes is the most calls to OnPush that run at once, DefaultMaxPushes when zero. Pushes delivered while
+	// every call is running are refused with a 503, so they can be redelivered from the settings of the webhook.
+	MaxPushes int
+
+	// Paths are patterns (see path.Match) of the paths of specifications in the repository. When empty, pushed
+	// files are scored by name as DiscoverSpecs scores them.
+	Paths []string
+
+	running     chan struct{}
+	runningOnce sync.Once
+}
+
+type pushPayload struct {
+	Ref        string `json:"ref"`
+	Before     string `json:"before"`
+	After      string `json:"after"`
+	Deleted    bool   `json:"deleted"`
+	Compare    string `json:"compare"`
+	Repository struct {
+		Name  string `json:"name"`
+		Owner struct {
+			Login string `json:"login"`
+			Name  string `json:"name"`
+		} `json:"owner"`
+	} `json:"repository"`
+	Pusher struct {
+		Name string `json:"name"`
+	} `json:"pusher"`
+	Commits []struct {
+		Added    []string `json:"added"`
+		Removed  []string `json:"removed"`
+		Modified []string `json:"modified"`
+	} `json:"commits"`
+}
+
+// NewWebhookHandler returns a handler for the deliveries of a GitHub webhook signed with secret, that calls
+// onPush for every push that touched a specification. Serve it on the payload URL of the webhook, and pass the
+// event to ReportPush (or the rest of a pipeline) from onPush.
+func NewWebhookHandler(secret string, onPush func(event PushEvent)) *WebhookHandler {
+	return &WebhookHandler{Secret: []byte(secret), OnPush: onPush}
+}
+
+// ServeHTTP verifies the signature of a delivery, and dispatches pushes that touched a specification: it responds
+// 202 when a push was dispatched, 204 when the delivery was ignored, 401 when the signature is not valid (or the
+// handler has no secret) and 503 when too many pushes are running.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "webhook deliveries are posted", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxWebhookSize+1))
+	if err != nil {
+		http.Error(w, "unable to read the delivery", http.StatusBadRequest)
+		return
+	}
+	if len(body) > MaxWebhookSize {
+		http.Error(w, "the delivery is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if !h.verify(body, r.Header.Get("X-Hub-Signature-256")) {
+		http.Error(w, "the signature of the delivery is not valid", http.StatusUnauthorized)
+		return
+	}
+	switch r.Header.Get("X-GitHub-Event") {
+	case "ping":
+		w.WriteHeader(http.StatusOK)
+		return
+	case "push":
+	default:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	var payload pushPayload
+	if err = json.Unmarshal(body, &payload); err != nil {
+		http.Error(w, fmt.Sprintf("unable to decode the push: %s", err), http.StatusBadRequest)
+		return
+	}
+	event := h.pushEvent(&payload)
+	if event == nil || h.OnPush == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	h.runningOnce.Do(func() {
+		limit := h.MaxPushes
+		if limit <= 0 {
+			limit = DefaultMaxPushes
+		}
+		h.running = make(chan struct{}, limit)
+	})
+	select {
+	case h.running <- struct{}{}:
+	default:
+		http.Error(w, "too many pushes are running, redeliver the push later", http.StatusServiceUnavailable)
+		return
+	}
+	go func() {
+		defer func() { <-h.running }()
+		h.OnPush(*event)
+	}()
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// verify checks the signature of a delivery, a 'sha256=' prefixed HMAC of the body with the secret. Nothing is
+// verified without a secret.
+func (h *WebhookHandler) verify(body []byte, signature string) bool {
+	if len(h.Secret) == 0 {
+		return false
+	}
+	sum, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	expected, err := hex.DecodeString(sum)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, h.Secret)
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// pushEvent returns the event of a push, nil when the push deleted its ref or did not touch a specification.
+func (h *WebhookHandler) pushEvent(payload *pushPayload) *PushEvent {
+	if payload.Deleted || payload.After == nullSHA {
+		return nil
+	}
+	const (
+		added = iota + 1
+		modified
+		removed
+	)
+	// paths that were added then removed are kept in states (without a state), so they are listed once when they
+	// are added again.
+	states := make(map[string]int)
+	var order []string
+	set := func(paths []string, state int) {
+		for _, p := range paths {
+			if !h.isSpec(p) {
+				continue
+			}
+			previous, seen := states[p]
+			if !seen {
+				order = append(order, p)
+			}
+			switch {
+			case previous == added && state == modified:
+			case previous == added && state == removed:
+				states[p] = 0
+			case previous == removed && state == added:
+				states[p] = modified
+			default:
+				states[p] = state
+			}
+		}
+	}
+	for _, c := range payload.Commits {
+		set(c.Removed, removed)
+		set(c.Added, added)
+		set(c.Modified, modified)
+	}
+	owner := payload.Repository.Owner.Login
+	if owner == "" {
+		owner = payload.Repository.Owner.Name
+	}
+	event := &PushEvent{
+		Owner:   owner,
+		Repo:    payload.Repository.Name,
+		Ref:     payload.Ref,
+		After:   payload.After,
+		Pusher:  payload.Pusher.Name,
+		Compare: payload.Compare,
+	}
+	if payload.Before != nullSHA {
+		event.Before = payload.Before
+	}
+	for _, p := range order {
+		switch states[p] {
+		case added:
+			event.Added = append(event.Added, p)
+		case modified:
+			event.Modified = append(event.Modified, p)
+		case removed:
+			event.Removed = append(event.Removed, p)
+		}
+	}
+	if len(event.Added)+len(event.Modified)+len(event.Removed) == 0 {
+		return nil
+	}
+	return event
+}
+
+// isSpec reports whether a pushed file is a specification.
+func (h *WebhookHandler) isSpec(p string) bool {
+	if len(h.Paths) == 0 {
+		return nameConfidence(p) >= specNameConfidence
+	}
+	return slices.ContainsFunc(h.Paths, func(pattern string) bool {
+		matched, _ := path.Match(pattern, p)
+		return matched
+	})
+}
+
+// PushReport is the report of the changes a push made to a specification.
+type PushReport struct {
+	// Path is the path of the specification in the repository.
+	Path string `json:"path"`
+
+	// Added is true when the push added the specification, its report is an initial release report.
+	Added bool `json:"added,omitempty"`
+
+	// Report is the rendered report (see changerator.Report).
+	Report []byte `json:"report"`
+}
+
+// ReportPush reads every specification a push added or modified before and after the push, and renders a report of
+// its changes (see changerator.Report). Added specifications are compared to nothing, so their report is an
+// initial release report. Removed specifications have nothing to report. Reports are returned in the order of the
+// event, added specifications first.
+//
+// Specifications split across files are read one file at a time, so their references to other files are not
+// resolved unless cfg resolves them.
+func ReportPush(ctx context.Context, session *Session, event PushEvent, format changerator.Format,
+	cfg *changerator.RenderConfig) ([]*PushReport, error) {
+	repoPath := "/repos/" + url.PathEscape(event.Owner) + "/" + url.PathEscape(event.Repo)
+	var reports []*PushReport
+	report := func(p string, added bool) error {
+		var left []byte
+		if !added && event.Before != "" {
+			var err error
+			if left, err = readFile(ctx, session, repoPath, p, event.Before); err != nil {
+				return err
+			}
+		}
+		right, err := readFile(ctx, session, repoPath, p, event.After)
+		if err != nil {
+			return err
+		}
+		rendered, err := changerator.Report(left, right, format, cfg)
+		if err != nil {
+			return fmt.Errorf("unable to report the changes to '%s': %w", p, err)
+		}
+		reports = append(reports, &PushReport{Path: p, Added: len(left) == 0, Report: rendered})
+		return nil
+	}
+	for _, p := range event.Added {
+		if err := report(p, true); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range event.Modified {
+		if err := report(p, false); err != nil {
+			return nil, err
+		}
+	}
+	return reports, nil
+}
+
+// readFile reads a file of a repository at a ref.
+func readFile(ctx context.Context, session *Session, repoPath, p, ref string) ([]byte, error) {
+	header := http.Header{"Accept": {"application/vnd.github.raw+json"}}
+	resp, err := session.get(ctx, contentsPath(repoPath, p, ref), header)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read '%s@%s': %w", p, ref, err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxSpecSize))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read '%s@%s': %w", p, ref, err)
+	}
+	return b, nil
+}
@@ -0,0 +1,177 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/pb33f/doctor/changerator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const pushDelivery = `{
+  "ref": "refs/heads/main",
+  "before": "aaa",
+  "after": "bbb",
+  "compare": "https://github.com/pb33f/shop/compare/aaa...bbb",
+  "repository": {"name": "shop", "owner": {"login": "pb33f"}},
+  "pusher": {"name": "quobix"},
+  "commits": [
+    {"added": ["specs/fries.yaml", "specs/scratch.yaml"], "modified": ["openapi.yaml", "main.go"], "removed": []},
+    {"added": [], "modified": ["specs/fries.yaml", "package.json"], "removed": ["specs/scratch.yaml", "specs/legacy.json"]}
+  ]
+}`
+
+func deliver(handler http.Handler, event, body, secret string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/hooks/github", strings.NewReader(body))
+	req.Header.Set("X-GitHub-Event", event)
+	if secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWebhookHandler(t *testing.T) {
+	pushes := make(chan PushEvent, 1)
+	handler := NewWebhookHandler("ketchup", func(event PushEvent) { pushes <- event })
+
+	rec := deliver(handler, "push", pushDelivery, "ketchup")
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	select {
+	case event := <-pushes:
+		assert.Equal(t, PushEvent{
+			Owner:    "pb33f",
+			Repo:     "shop",
+			Ref:      "refs/heads/main",
+			Before:   "aaa",
+			After:    "bbb",
+			Pusher:   "quobix",
+			Compare:  "https://github.com/pb33f/shop/compare/aaa...bbb",
+			Added:    []string{"specs/fries.yaml"},
+			Modified: []string{"openapi.yaml"},
+			Removed:  []string{"specs/legacy.json"},
+		}, event)
+	case <-time.After(time.Second):
+		t.Fatal("the push was not dispatched")
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, deliver(handler, "push", pushDelivery, "mustard").Code)
+	assert.Equal(t, http.StatusUnauthorized, deliver(handler, "push", pushDelivery, "").Code)
+	assert.Equal(t, http.StatusOK, deliver(handler, "ping", `{"zen": "burgers"}`, "ketchup").Code)
+	assert.Equal(t, http.StatusNoContent, deliver(handler, "issues", `{}`, "ketchup").Code)
+	assert.Equal(t, http.StatusNoContent, deliver(handler, "push",
+		`{"after": "bbb", "commits": [{"modified": ["main.go"]}]}`, "ketchup").Code)
+	assert.Equal(t, http.StatusNoContent, deliver(handler, "push",
+		`{"deleted": true, "commits": [{"removed": ["openapi.yaml"]}]}`, "ketchup").Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/hooks/github", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Empty(t, pushes)
+}
+
+func TestWebhookHandler_Paths(t *testing.T) {
+	pushes := make(chan PushEvent, 1)
+	handler := NewWebhookHandler("ketchup", func(event PushEvent) { pushes <- event })
+	handler.Paths = []string{"specs/*.yaml"}
+
+	assert.Equal(t, http.StatusAccepted, deliver(handler, "push", pushDelivery, "ketchup").Code)
+	event := <-pushes
+	assert.Equal(t, []string{"specs/fries.yaml"}, event.Added)
+	assert.Empty(t, event.Modified)
+	assert.Empty(t, event.Removed)
+}
+
+func TestWebhookHandler_NoSecret(t *testing.T) {
+	handler := NewWebhookHandler("", func(event PushEvent) { t.Fatal("an unsigned push was dispatched") })
+
+	assert.Equal(t, http.StatusUnauthorized, deliver(handler, "push", pushDelivery, "").Code)
+	assert.Equal(t, http.StatusUnauthorized, deliver(handler, "push", pushDelivery, "ketchup").Code)
+}
+
+func TestWebhookHandler_Readded(t *testing.T) {
+	pushes := make(chan PushEvent, 1)
+	handler := NewWebhookHandler("ketchup", func(event PushEvent) { pushes <- event })
+
+	delivery := `{
+  "ref": "refs/heads/main",
+  "after": "bbb",
+  "commits": [
+    {"added": ["specs/fries.yaml"]},
+    {"removed": ["specs/fries.yaml"]},
+    {"added": ["specs/fries.yaml"]}
+  ]
+}`
+	assert.Equal(t, http.StatusAccepted, deliver(handler, "push", delivery, "ketchup").Code)
+	event := <-pushes
+	assert.Equal(t, []string{"specs/fries.yaml"}, event.Added)
+	assert.Empty(t, event.Modified)
+	assert.Empty(t, event.Removed)
+}
+
+func TestWebhookHandler_MaxPushes(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan PushEvent, 2)
+	handler := NewWebhookHandler("ketchup", func(event PushEvent) {
+		started <- event
+		<-release
+	})
+	handler.MaxPushes = 1
+
+	assert.Equal(t, http.StatusAccepted, deliver(handler, "push", pushDelivery, "ketchup").Code)
+	<-started
+	assert.Equal(t, http.StatusServiceUnavailable, deliver(handler, "push", pushDelivery, "ketchup").Code)
+
+	// once the running push is done, the next is dispatched.
+	close(release)
+	assert.Eventually(t, func() bool {
+		return deliver(handler, "push", pushDelivery, "ketchup").Code == http.StatusAccepted
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestReportPush(t *testing.T) {
+	spec := "openapi: 3.1.0\ninfo:\n  title: burgers\n  version: %s\npaths:\n  /burgers:\n    get:\n      responses:\n        '200':\n          description: ok\n"
+	revisions := map[string]string{
+		"aaa": strings.Replace(spec, "%s", "1.0.0", 1),
+		"bbb": strings.Replace(spec, "%s", "1.1.0", 1),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/vnd.github.raw+json", r.Header.Get("Accept"))
+		if !strings.HasPrefix(r.URL.Path, "/repos/pb33f/shop/contents/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(revisions[r.URL.Query().Get("ref")]))
+	}))
+	defer server.Close()
+
+	session := &Session{BaseURL: server.URL}
+	event := PushEvent{Owner: "pb33f", Repo: "shop", Before: "aaa", After: "bbb",
+		Added: []string{"specs/fries.yaml"}, Modified: []string{"openapi.yaml"}, Removed: []string{"legacy.yaml"}}
+	reports, err := ReportPush(context.Background(), session, event, changerator.FormatMarkdown, nil)
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	assert.Equal(t, "specs/fries.yaml", reports[0].Path)
+	assert.True(t, reports[0].Added)
+	assert.Equal(t, "openapi.yaml", reports[1].Path)
+	assert.False(t, reports[1].Added)
+	assert.Contains(t, string(reports[1].Report), "`$.info.version` | `1.0.0` | `1.1.0` |")
+
+	event.Owner = "nobody"
+	_, err = ReportPush(context.Background(), session, event, changerator.FormatMarkdown, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package routing
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Template is a compiled OpenAPI path template, like `/burgers/{burgerId}`.
+type Template struct {
+	Raw          string
+	Params       []string
+	matcher      *regexp.Regexp
+	literalChars int
+	segments     int
+}
+
+// Route pairs a compiled template with whatever the caller wants to get back when it matches.
+type Route[T any] struct {
+	Template *Template
+	Value    T
+}
+
+// Router matches concrete request paths against a set of path templates. Routes are ordered so that
+// concrete paths always win over templated ones (`/burgers/pizza` before `/burgers/{burgerId}`), as required
+// by the OpenAPI specification.
+type Router[T any] struct {
+	routes []*Route[T]
+}
+
+// Compile converts a path template into a Template that can be matched against concrete paths.
+func Compile(template string) *Template {
+	t := &Template{Raw: template}
+	buf := strings.Builder{}
+	buf.WriteString("^")
+	rest := template
+	for len(rest) > 0 {
+		open := strings.Index(rest, "{")
+		if open < 0 {
+			buf.WriteString(regexp.QuoteMeta(rest))
+			t.literalChars += len(rest)
+			break
+		}
+		closing := strings.Index(rest[open:], "}")
+		if closing < 0 {
+			// unterminated template variable, treat the rest as a literal.
+			buf.WriteString(regexp.QuoteMeta(rest))
+			t.literalChars += len(rest)
+			break
+		}
+		buf.WriteString(regexp.QuoteMeta(rest[:open]))
+		t.literalChars += open
+		t.Params = append(t.Params, rest[open+1:open+closing])
+		buf.WriteString("([^/]+)")
+		rest = rest[open+closing+1:]
+	}
+	buf.WriteString("/?$")
+	t.segments = strings.Count(strings.Trim(template, "/"), "/") + 1
+	t.matcher = regexp.MustCompile(buf.String())
+	return t
+}
+
+// Match checks a concrete path against the template, returning the extracted path parameter values.
+func (t *Template) Match(path string) (map[string]string, bool) {
+	found := t.matcher.FindStringSubmatch(path)
+	if found == nil {
+		return nil, false
+	}
+	params := make(map[string]string, len(t.Params))
+	for i, name := range t.Params {
+		params[name] = found[i+1]
+	}
+	return params, true
+}
+
+// NewRouter creates an empty Router.
+func NewRouter[T any]() *Router[T] {
+	return &Router[T]{}
+}
+
+// Add compiles and registers a template with the router.
+func (r *Router[T]) Add(template string, value T) {
+	r.routes = append(r.routes, &Route[T]{Template: Compile(template), Value: value})
+	sort.SliceStable(r.routes, func(i, j int) bool {
+		a, b := r.routes[i].Template, r.routes[j].Template
+		if len(a.Params) != len(b.Params) {
+			return len(a.Params) < len(b.Params)
+		}
+		return a.literalChars > b.literalChars
+	})
+}
+
+// Routes returns every registered route, in match order.
+func (r *Router[T]) Routes() []*Route[T] {
+	return r.routes
+}
+
+// Match returns every route matching the supplied path, in precedence order, along with the extracted
+// path parameters for each.
+func (r *Router[T]) Match(path string) ([]*Route[T], []map[string]string) {
+	var routes []*Route[T]
+	var params []map[string]string
+	for _, route := range r.routes {
+		if route.Template.segments != strings.Count(strings.Trim(path, "/"), "/")+1 {
+			continue
+		}
+		if p, ok := route.Template.Match(path); ok {
+			routes = append(routes, route)
+			params = append(params, p)
+		}
+	}
+	return routes, params
+}
+
+// TrimBasePath strips a base path like `/v1` from the start of a request path. The base path is only stripped
+// on a segment boundary, so `/v1foo` is left alone, and a request for the base path itself becomes `/`.
+func TrimBasePath(path, basePath string) string {
+	basePath = strings.TrimSuffix(basePath, "/")
+	if basePath == "" {
+		return path
+	}
+	if path == basePath {
+		return "/"
+	}
+	if strings.HasPrefix(path, basePath+"/") {
+		return path[len(basePath):]
+	}
+	return path
+}
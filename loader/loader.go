@@ -0,0 +1,296 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package loader fetches specifications, and the files they reference, over HTTPS. Requests can carry
+// authentication headers and have a timeout, fetched files can be cached on disk, and the content of a file can be
+// pinned to a checksum. The loader is plugged into the rolodex as its remote handler, so a DrDocument can be built
+// from a URL directly and every remote reference is fetched the same way.
+package loader
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/pb33f/doctor/model"
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout is the timeout of a request when the config does not set one.
+const DefaultTimeout = 30 * time.Second
+
+// DefaultMaxSize is the largest file (32MB) that will be fetched when the config does not set a limit.
+const DefaultMaxSize int64 = 32 << 20
+
+// maxRedirects is the number of redirects followed before a fetch fails, the same as the default http client.
+const maxRedirects = 10
+
+// ErrChecksumMismatch is returned when the content of a file does not match its pinned checksum.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// Config controls how files are fetched.
+type Config struct {
+	// Headers are sent with requests to their host (like `api.example.com`), for example an Authorization
+	// header, credentials are never sent to other hosts, including when a request is redirected.
+	Headers map[string]http.Header
+
+	// Timeout is the timeout of each request, DefaultTimeout when zero. It is not used with a Client.
+	Timeout time.Duration
+
+	// CacheDir caches fetched files on disk when set, cached files are used instead of fetching them again until
+	// they are older than CacheTTL (they never expire when it is zero).
+	CacheDir string
+	CacheTTL time.Duration
+
+	// Checksums pins the content of files, by URL, to a hex encoded SHA-256 checksum. Files that do not match
+	// are not used, and fail with ErrChecksumMismatch.
+	Checksums map[string]string
+
+	// MaxSize is the largest file, in bytes, that will be fetched, DefaultMaxSize when zero.
+	MaxSize int64
+
+	// AllowHTTP allows fetching files over plain HTTP, only HTTPS is allowed by default. Redirects are held to
+	// the same rule.
+	AllowHTTP bool
+
+	// Client sends the requests, a client with the timeout is used when nil. The loader uses a copy of the client
+	// that checks redirects before its own CheckRedirect is called.
+	Client *http.Client
+}
+
+// Loader fetches files as configured, it is safe for concurrent use.
+type Loader struct {
+	config *Config
+	client *http.Client
+}
+
+// NewLoader creates a loader, a nil config fetches over HTTPS without authentication or caching.
+func NewLoader(config *Config) *Loader {
+	if config == nil {
+		config = &Config{}
+	}
+	client := &http.Client{}
+	if config.Client != nil {
+		*client = *config.Client
+	} else {
+		client.Timeout = config.Timeout
+		if client.Timeout <= 0 {
+			client.Timeout = DefaultTimeout
+		}
+	}
+	l := &Loader{config: config, client: client}
+	next := client.CheckRedirect
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return l.checkRedirect(req, via, next)
+	}
+	return l
+}
+
+// Fetch returns the content of the file at a URL, from the cache when it holds a fresh copy.
+func (l *Loader) Fetch(ctx context.Context, location string) ([]byte, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse location '%s': %w", location, err)
+	}
+	if !l.allowed(u) {
+		return nil, fmt.Errorf("unable to fetch '%s': the '%s' scheme is not allowed", location, u.Scheme)
+	}
+
+	cached := l.cachePath(location)
+	if cached != "" {
+		if info, statErr := os.Stat(cached); statErr == nil &&
+			(l.config.CacheTTL <= 0 || time.Since(info.ModTime()) < l.config.CacheTTL) {
+			if b, readErr := os.ReadFile(cached); readErr == nil && l.verify(location, b) == nil {
+				return b, nil
+			}
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch '%s': %w", location, err)
+	}
+	for name, values := range l.config.Headers[u.Host] {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch '%s': %w", location, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unable to fetch '%s': unexpected status '%s'", location, resp.Status)
+	}
+	limit := l.config.MaxSize
+	if limit <= 0 {
+		limit = DefaultMaxSize
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch '%s': %w", location, err)
+	}
+	if int64(len(b)) > limit {
+		return nil, fmt.Errorf("unable to fetch '%s': the file is larger than the limit of %d bytes", location, limit)
+	}
+	if err = l.verify(location, b); err != nil {
+		return nil, err
+	}
+	if cached != "" {
+		if err = writeCache(cached, b); err != nil {
+			return nil, fmt.Errorf("unable to cache '%s': %w", location, err)
+		}
+	}
+	return b, nil
+}
+
+// Handler fetches files for the rolodex (see datamodel.DocumentConfiguration.RemoteURLHandler), the rolodex has
+// no context to pass along, so these requests are only bounded by the timeout of the client.
+func (l *Loader) Handler(location string) (*http.Response, error) {
+	b, err := l.Fetch(context.Background(), location)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{},
+		Body:          io.NopCloser(bytes.NewReader(b)),
+		ContentLength: int64(len(b)),
+	}, nil
+}
+
+// DocumentConfiguration returns a configuration for the specification at a URL, that resolves references relative
+// to it and fetches remote references with the loader.
+func (l *Loader) DocumentConfiguration(location string) (*datamodel.DocumentConfiguration, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse location '%s': %w", location, err)
+	}
+	u.Path = path.Dir(u.Path)
+	u.RawQuery, u.Fragment = "", ""
+	return &datamodel.DocumentConfiguration{
+		BaseURL:               u,
+		AllowRemoteReferences: true,
+		RemoteURLHandler:      l.Handler,
+	}, nil
+}
+
+// LoadDocument fetches, parses and builds the specification at a URL.
+func (l *Loader) LoadDocument(ctx context.Context, location string) (*libopenapi.DocumentModel[v3.Document], error) {
+	spec, err := l.Fetch(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	docConfig, err := l.DocumentConfiguration(location)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := libopenapi.NewDocumentWithConfiguration(spec, docConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse '%s': %w", location, err)
+	}
+	v3Doc, errs := doc.BuildV3Model()
+	if v3Doc == nil {
+		return nil, fmt.Errorf("unable to build '%s': %w", location, errors.Join(errs...))
+	}
+	return v3Doc, nil
+}
+
+// NewDrDocument fetches the specification at a URL and walks it, a nil config uses the same defaults as
+// model.NewDrDocument. The context bounds the fetch and the walk, see model.NewDrDocumentWithContext.
+func (l *Loader) NewDrDocument(ctx context.Context, location string, config *model.DrConfig) (*model.DrDocument, error) {
+	v3Doc, err := l.LoadDocument(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	return model.NewDrDocumentWithContext(ctx, v3Doc, config)
+}
+
+// allowed checks the scheme of a URL, HTTPS is always allowed and HTTP only when configured.
+func (l *Loader) allowed(u *url.URL) bool {
+	return u.Scheme == "https" || (u.Scheme == "http" && l.config.AllowHTTP)
+}
+
+// checkRedirect applies the scheme rule to redirects, and moves the configured headers over to the host being
+// redirected to, so credentials for one host are never sent to another. next is the CheckRedirect of the
+// configured client, if it has one.
+func (l *Loader) checkRedirect(req *http.Request, via []*http.Request, next func(*http.Request, []*http.Request) error) error {
+	if !l.allowed(req.URL) {
+		return fmt.Errorf("redirect to '%s' is not allowed: the '%s' scheme is not allowed", req.URL, req.URL.Scheme)
+	}
+	// redirects carry the headers of the original request.
+	if original := via[0].URL.Host; original != req.URL.Host {
+		for name := range l.config.Headers[original] {
+			req.Header.Del(name)
+		}
+		for name, values := range l.config.Headers[req.URL.Host] {
+			for _, v := range values {
+				req.Header.Add(name, v)
+			}
+		}
+	}
+	if next != nil {
+		return next(req, via)
+	}
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	return nil
+}
+
+// verify checks content against the checksum pinned for its URL, if there is one.
+func (l *Loader) verify(location string, b []byte) error {
+	expected, ok := l.config.Checksums[location]
+	if !ok {
+		return nil
+	}
+	sum := sha256.Sum256(b)
+	if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("%w for '%s': expected %s, got %s", ErrChecksumMismatch, location, expected, actual)
+	}
+	return nil
+}
+
+// cachePath returns the file a URL is cached in, files are named after the checksum of their URL.
+func (l *Loader) cachePath(location string) string {
+	if l.config.CacheDir == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(location))
+	return filepath.Join(l.config.CacheDir, hex.EncodeToString(sum[:]))
+}
+
+// writeCache writes a file to a temporary file first, so concurrent readers never see it half written.
+func writeCache(name string, b []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
@@ -0,0 +1,172 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package loader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+)
+
+var rootSpec = `openapi: 3.1.0
+info:
+  title: burgers
+  version: 1.0.0
+paths:
+  /burgers:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: 'schemas.yaml#/Burger'`
+
+var schemasSpec = `Burger:
+  type: object
+  properties:
+    name:
+      type: string`
+
+func specServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Authorization") != "Bearer burger" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/specs/openapi.yaml":
+			_, _ = w.Write([]byte(rootSpec))
+		case "/specs/schemas.yaml":
+			_, _ = w.Write([]byte(schemasSpec))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func authenticated(server *httptest.Server, config *Config) *Config {
+	u, _ := url.Parse(server.URL)
+	config.Client = server.Client()
+	config.Headers = map[string]http.Header{u.Host: {"Authorization": {"Bearer burger"}}}
+	return config
+}
+
+func TestLoader_NewDrDocument(t *testing.T) {
+	var requests atomic.Int32
+	server := specServer(t, &requests)
+	l := NewLoader(authenticated(server, &Config{CacheDir: t.TempDir()}))
+
+	drDoc, err := l.NewDrDocument(context.Background(), server.URL+"/specs/openapi.yaml", nil)
+	require.NoError(t, err)
+	require.Len(t, drDoc.Operations(), 1)
+
+	// the referenced schema was fetched, with the same credentials.
+	assert.Equal(t, int32(2), requests.Load())
+	schema := drDoc.Operations()[0].Operation.Value.Responses.Codes.GetOrZero("200").
+		Content.GetOrZero("application/json").Schema.Schema()
+	require.NotNil(t, schema)
+	assert.Equal(t, []string{"object"}, schema.Type)
+
+	// both files are cached.
+	_, err = l.NewDrDocument(context.Background(), server.URL+"/specs/openapi.yaml", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestLoader_Fetch(t *testing.T) {
+	var requests atomic.Int32
+	server := specServer(t, &requests)
+	location := server.URL + "/specs/schemas.yaml"
+
+	// credentials are only sent to their host.
+	_, err := NewLoader(&Config{Client: server.Client()}).Fetch(context.Background(), location)
+	assert.ErrorContains(t, err, "unexpected status '401 Unauthorized'")
+
+	sum := sha256.Sum256([]byte(schemasSpec))
+	l := NewLoader(authenticated(server, &Config{Checksums: map[string]string{location: hex.EncodeToString(sum[:])}}))
+	b, err := l.Fetch(context.Background(), location)
+	require.NoError(t, err)
+	assert.Equal(t, schemasSpec, string(b))
+
+	l = NewLoader(authenticated(server, &Config{Checksums: map[string]string{location: "00"}}))
+	_, err = l.Fetch(context.Background(), location)
+	assert.True(t, errors.Is(err, ErrChecksumMismatch))
+
+	_, err = NewLoader(nil).Fetch(context.Background(), "http://example.com/openapi.yaml")
+	assert.EqualError(t, err, "unable to fetch 'http://example.com/openapi.yaml': the 'http' scheme is not allowed")
+}
+
+func TestLoader_Fetch_Limits(t *testing.T) {
+	var requests atomic.Int32
+	server := specServer(t, &requests)
+	location := server.URL + "/specs/schemas.yaml"
+
+	_, err := NewLoader(authenticated(server, &Config{MaxSize: 8})).Fetch(context.Background(), location)
+	assert.EqualError(t, err, "unable to fetch '"+location+"': the file is larger than the limit of 8 bytes")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewLoader(authenticated(server, &Config{})).Fetch(ctx, location)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestLoader_Fetch_Redirects(t *testing.T) {
+	var received http.Header
+	target := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		_, _ = w.Write([]byte(schemasSpec))
+	}))
+	t.Cleanup(target.Close)
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(schemasSpec))
+	}))
+	t.Cleanup(plain.Close)
+	origin := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/moved.yaml":
+			http.Redirect(w, r, target.URL+"/schemas.yaml", http.StatusFound)
+		case "/insecure.yaml":
+			http.Redirect(w, r, plain.URL+"/schemas.yaml", http.StatusFound)
+		}
+	}))
+	t.Cleanup(origin.Close)
+
+	originURL, _ := url.Parse(origin.URL)
+	targetURL, _ := url.Parse(target.URL)
+	l := NewLoader(&Config{
+		Client: origin.Client(),
+		Headers: map[string]http.Header{
+			originURL.Host: {"X-Api-Key": {"burger"}},
+			targetURL.Host: {"X-Target-Key": {"fries"}},
+		},
+	})
+
+	// the headers of the origin are replaced by the headers of the host the request is redirected to.
+	b, err := l.Fetch(context.Background(), origin.URL+"/moved.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, schemasSpec, string(b))
+	assert.Empty(t, received.Get("X-Api-Key"))
+	assert.Equal(t, "fries", received.Get("X-Target-Key"))
+
+	// redirects from HTTPS to HTTP are held to the same rule as the original request.
+	_, err = l.Fetch(context.Background(), origin.URL+"/insecure.yaml")
+	assert.ErrorContains(t, err, "the 'http' scheme is not allowed")
+
+	l = NewLoader(&Config{Client: origin.Client(), AllowHTTP: true})
+	_, err = l.Fetch(context.Background(), origin.URL+"/insecure.yaml")
+	assert.NoError(t, err)
+}
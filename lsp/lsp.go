@@ -0,0 +1,342 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package lsp answers the questions a language server asks about an OpenAPI document (hover, go to definition
+// and find references) using a walked DrDocument. Positions, ranges and locations are shaped like their LSP
+// counterparts, so they can be handed to a language server without translation: lines and characters are zero
+// based, where the lines and columns of the yaml nodes are one based.
+package lsp
+
+import (
+	"errors"
+	"fmt"
+	"github.com/pb33f/doctor/model"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"gopkg.in/yaml.v3"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Position is a zero based position in a document.
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is the span between two positions, End is exclusive.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Location is a range inside the document found at URI.
+type Location struct {
+	URI   string `json:"uri"`
+	Range Range  `json:"range"`
+}
+
+// Hover is the result of a hover request, Contents is markdown and Range is the span of the node under the cursor.
+type Hover struct {
+	Contents string `json:"contents"`
+	Range    *Range `json:"range,omitempty"`
+}
+
+// Document wraps a walked document, and the URI the language client knows the root document by.
+type Document struct {
+	drDoc *model.DrDocument
+	uri   string
+}
+
+// NewDocument creates a Document for a walked document, uri is used for locations inside the root document.
+func NewDocument(drDoc *model.DrDocument, uri string) *Document {
+	return &Document{drDoc: drDoc, uri: uri}
+}
+
+// Hover describes the object under a position: its type, JSONPath and description, and a summary when the object
+// is a schema. When the position is on a `$ref` value, the object the reference points to is described. A nil
+// Hover is returned when there is nothing under the position.
+func (d *Document) Hover(position Position) (*Hover, error) {
+	n, err := d.nodeAt(position)
+	if err != nil || n == nil {
+		return nil, err
+	}
+	var f drBase.Foundational
+	if n.isRef {
+		if f = d.referenceObject(n); f != nil {
+			if resolved := d.drDoc.Resolve(f); resolved != nil && resolved.Object != nil {
+				f = resolved.Object
+			}
+		}
+	} else if models, _ := d.drDoc.LocateModelByLine(n.node.Line); len(models) > 0 {
+		f = models[len(models)-1]
+	}
+	if f == nil {
+		return nil, nil
+	}
+
+	var b strings.Builder
+	if t := f.GetInstanceType(); t != "" {
+		b.WriteString(fmt.Sprintf("**%s** ", t))
+	} else if schemaOf(f) != nil {
+		b.WriteString("**schema** ")
+	}
+	b.WriteString(fmt.Sprintf("`%s`", f.GenerateJSONPath()))
+	if n.isRef {
+		b.WriteString(fmt.Sprintf("\n\nreferenced by `%s`", n.node.Value))
+	}
+	if desc := description(f); desc != "" {
+		b.WriteString("\n\n" + desc)
+	}
+	if summary := schemaSummary(f); summary != "" {
+		b.WriteString("\n\n" + summary)
+	}
+	r := nodeRange(n.node)
+	return &Hover{Contents: b.String(), Range: &r}, nil
+}
+
+// Definition returns the location of the target of the `$ref` value under a position, or nil when the position is
+// not on a reference (or the target cannot be found).
+func (d *Document) Definition(position Position) (*Location, error) {
+	n, err := d.nodeAt(position)
+	if err != nil || n == nil || !n.isRef {
+		return nil, err
+	}
+	if f := d.referenceObject(n); f != nil {
+		if hops := d.drDoc.RefChain(f); len(hops) > 0 && hops[0].Line > 0 {
+			return d.location(hops[0].Origin, hops[0].Line, hops[0].Column), nil
+		}
+	}
+
+	// references that are not held by a walked object (like a path item reference) are searched in the index.
+	idx := d.drDoc.GetIndex()
+	if idx == nil {
+		return nil, nil
+	}
+	found, foundIdx := idx.SearchIndexForReference(n.node.Value)
+	if found == nil || found.Node == nil {
+		return nil, nil
+	}
+	origin := ""
+	if foundIdx != nil {
+		origin = foundIdx.GetSpecAbsolutePath()
+	}
+	return d.location(origin, found.Node.Line, found.Node.Column), nil
+}
+
+// References returns the location of every `$ref` in the root document that points into the component under a
+// position, in document order. The position can be on the name of a component (or anywhere inside it), or on a
+// `$ref` value, in which case the references to the same component are returned. References to other files are
+// not followed.
+func (d *Document) References(position Position) ([]*Location, error) {
+	n, err := d.nodeAt(position)
+	if err != nil || n == nil {
+		return nil, err
+	}
+	var def string
+	switch {
+	case n.isRef:
+		def = model.ComponentDefinition(n.node.Value)
+	case len(n.path) >= 3 && n.path[0] == "components":
+		def = "#/components/" + n.path[1] + "/" + pointerEscaper.Replace(n.path[2])
+	}
+	if def == "" {
+		return nil, nil
+	}
+	var locations []*Location
+	for _, ref := range refNodes(d.drDoc.GetIndex().GetRootNode()) {
+		if model.ComponentDefinition(ref.Value) == def {
+			r := nodeRange(ref)
+			locations = append(locations, &Location{URI: d.uri, Range: r})
+		}
+	}
+	return locations, nil
+}
+
+// located is the scalar found under a position, and the keys (and sequence indexes) leading to it. For a `$ref`
+// value, owner is the key of the mapping holding the reference.
+type located struct {
+	node  *yaml.Node
+	owner *yaml.Node
+	path  []string
+	isRef bool
+}
+
+// nodeAt finds the scalar key or value under a position in the root document.
+func (d *Document) nodeAt(position Position) (*located, error) {
+	if d == nil || d.drDoc == nil || d.drDoc.GetIndex() == nil {
+		return nil, errors.New("document has not been walked, cannot locate position")
+	}
+	line, column := position.Line+1, position.Character+1
+	var find func(n, owner *yaml.Node, path []string) *located
+	find = func(n, owner *yaml.Node, path []string) *located {
+		if n == nil {
+			return nil
+		}
+		switch n.Kind {
+		case yaml.DocumentNode:
+			for _, c := range n.Content {
+				if l := find(c, owner, path); l != nil {
+					return l
+				}
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				key, value := n.Content[i], n.Content[i+1]
+				p := append(append([]string{}, path...), key.Value)
+				if covers(key, line, column) {
+					return &located{node: key, path: p}
+				}
+				if covers(value, line, column) {
+					return &located{node: value, owner: owner, path: p, isRef: key.Value == "$ref"}
+				}
+				if l := find(value, key, p); l != nil {
+					return l
+				}
+			}
+		case yaml.SequenceNode:
+			for i, c := range n.Content {
+				p := append(append([]string{}, path...), strconv.Itoa(i))
+				if covers(c, line, column) {
+					return &located{node: c, path: p}
+				}
+				if l := find(c, c, p); l != nil {
+					return l
+				}
+			}
+		}
+		return nil
+	}
+	return find(d.drDoc.GetIndex().GetRootNode(), nil, nil), nil
+}
+
+// referenceObject returns the walked object holding the `$ref` under a position. References are indexed at the
+// line of their target, so the object is found amongst the objects at the line of the key that holds it (and
+// their children).
+func (d *Document) referenceObject(n *located) drBase.Foundational {
+	if n.owner == nil {
+		return nil
+	}
+	holds := func(f drBase.Foundational) bool {
+		hops := d.drDoc.RefChain(f)
+		return len(hops) > 0 && hops[0].Reference == n.node.Value
+	}
+	models, _ := d.drDoc.LocateModelByLine(n.owner.Line)
+	for i := len(models) - 1; i >= 0; i-- {
+		if models[i].GetKeyNode() == n.owner && holds(models[i]) {
+			return models[i]
+		}
+		for _, child := range drBase.ChildrenOf(models[i]) {
+			if child.GetKeyNode() == n.owner && holds(child) {
+				return child
+			}
+		}
+	}
+	return nil
+}
+
+// location creates an empty range at a one based line and column, origin is the absolute location of the file.
+func (d *Document) location(origin string, line, column int) *Location {
+	uri := d.uri
+	if origin != "" && origin != d.drDoc.GetIndex().GetSpecAbsolutePath() {
+		uri = origin
+		if !strings.Contains(origin, "://") {
+			uri = "file://" + origin
+		}
+	}
+	p := Position{Line: line - 1, Character: column - 1}
+	return &Location{URI: uri, Range: Range{Start: p, End: p}}
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// covers checks if a scalar node spans a one based line and column.
+func covers(n *yaml.Node, line, column int) bool {
+	if n.Kind != yaml.ScalarNode || n.Line != line {
+		return false
+	}
+	r := nodeRange(n)
+	return column-1 >= r.Start.Character && column-1 < r.End.Character
+}
+
+// nodeRange returns the range of a scalar node on its first line, including any quotes.
+func nodeRange(n *yaml.Node) Range {
+	width := len(n.Value)
+	if i := strings.IndexByte(n.Value, '\n'); i >= 0 {
+		width = i
+	}
+	if n.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 {
+		width += 2
+	}
+	start := Position{Line: n.Line - 1, Character: n.Column - 1}
+	return Range{Start: start, End: Position{Line: start.Line, Character: start.Character + width}}
+}
+
+// refNodes returns the value node of every `$ref` inside a node, in document order.
+func refNodes(node *yaml.Node) []*yaml.Node {
+	if node == nil {
+		return nil
+	}
+	var refs []*yaml.Node
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "$ref" && node.Content[i+1].Kind == yaml.ScalarNode {
+				refs = append(refs, node.Content[i+1])
+			}
+		}
+	}
+	for _, c := range node.Content {
+		refs = append(refs, refNodes(c)...)
+	}
+	return refs
+}
+
+// description reads the description of a walked object, if the object has one.
+func description(f drBase.Foundational) string {
+	if s := schemaOf(f); s != nil && s.Value != nil {
+		return strings.TrimSpace(s.Value.Description)
+	}
+	hv, ok := f.(model.HasValue)
+	if !ok || hv.GetValue() == nil {
+		return ""
+	}
+	value := reflect.ValueOf(hv.GetValue())
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return ""
+	}
+	if field := value.Elem().FieldByName("Description"); field.IsValid() && field.Kind() == reflect.String {
+		return strings.TrimSpace(field.String())
+	}
+	return ""
+}
+
+// schemaSummary lists the constraints (see drBase.Schema.ConstraintSummary) and properties of a schema.
+func schemaSummary(f drBase.Foundational) string {
+	s := schemaOf(f)
+	if s == nil || s.Value == nil {
+		return ""
+	}
+	var lines []string
+	if c := s.ConstraintSummary().String(); c != "" {
+		lines = append(lines, fmt.Sprintf("- constraints: `%s`", c))
+	}
+	if s.Value.Properties != nil && s.Value.Properties.Len() > 0 {
+		var props []string
+		for name := range s.Value.Properties.KeysFromOldest() {
+			props = append(props, name)
+		}
+		lines = append(lines, fmt.Sprintf("- properties: `%s`", strings.Join(props, "`, `")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// schemaOf returns the schema of a walked schema or schema proxy.
+func schemaOf(f drBase.Foundational) *drBase.Schema {
+	switch v := f.(type) {
+	case *drBase.Schema:
+		return v
+	case *drBase.SchemaProxy:
+		return v.Schema
+	}
+	return nil
+}
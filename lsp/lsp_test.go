@@ -0,0 +1,119 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package lsp
+
+import (
+	"github.com/pb33f/doctor/model"
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var spec = `openapi: 3.1.0
+info:
+  title: burgers
+  version: 1.0.0
+paths:
+  /burgers:
+    get:
+      responses:
+        "200":
+          description: a burger
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Burger'
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Burger/properties/name'
+components:
+  schemas:
+    Burger:
+      type: object
+      description: a tasty burger
+      required:
+        - name
+      properties:
+        name:
+          type: string
+        fries:
+          type: boolean`
+
+func lspDocument(t *testing.T) *Document {
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	v3Doc, errs := doc.BuildV3Model()
+	require.Empty(t, errs)
+	return NewDocument(model.NewDrDocument(v3Doc), "file:///burgers.yaml")
+}
+
+func TestDocument_Hover(t *testing.T) {
+	d := lspDocument(t)
+
+	// on the $ref value, the referenced schema is described.
+	hover, err := d.Hover(Position{Line: 13, Character: 24})
+	require.NoError(t, err)
+	require.NotNil(t, hover)
+	assert.Contains(t, hover.Contents, "`$.components.schemas['Burger']`")
+	assert.Contains(t, hover.Contents, "a tasty burger")
+	assert.Contains(t, hover.Contents, "- constraints: `object, requires name`")
+	assert.Contains(t, hover.Contents, "- properties: `name`, `fries`")
+	assert.Equal(t, Range{Start: Position{Line: 13, Character: 22}, End: Position{Line: 13, Character: 51}}, *hover.Range)
+
+	hover, err = d.Hover(Position{Line: 9, Character: 12})
+	require.NoError(t, err)
+	require.NotNil(t, hover)
+	assert.Contains(t, hover.Contents, "a burger")
+
+	hover, err = d.Hover(Position{Line: 40, Character: 0})
+	assert.NoError(t, err)
+	assert.Nil(t, hover)
+
+	_, err = NewDocument(nil, "").Hover(Position{})
+	assert.Error(t, err)
+}
+
+func TestDocument_Definition(t *testing.T) {
+	d := lspDocument(t)
+
+	loc, err := d.Definition(Position{Line: 13, Character: 30})
+	require.NoError(t, err)
+	require.NotNil(t, loc)
+	assert.Equal(t, "file:///burgers.yaml", loc.URI)
+	assert.Equal(t, 23, loc.Range.Start.Line)
+
+	loc, err = d.Definition(Position{Line: 19, Character: 30})
+	require.NoError(t, err)
+	require.NotNil(t, loc)
+	assert.Equal(t, 29, loc.Range.Start.Line)
+
+	// not a reference.
+	loc, err = d.Definition(Position{Line: 9, Character: 12})
+	assert.NoError(t, err)
+	assert.Nil(t, loc)
+}
+
+func TestDocument_References(t *testing.T) {
+	d := lspDocument(t)
+
+	// on the name of the component.
+	locs, err := d.References(Position{Line: 22, Character: 6})
+	require.NoError(t, err)
+	require.Len(t, locs, 2)
+	assert.Equal(t, 13, locs[0].Range.Start.Line)
+	assert.Equal(t, 19, locs[1].Range.Start.Line)
+
+	// on a reference to the component.
+	locs, err = d.References(Position{Line: 19, Character: 30})
+	require.NoError(t, err)
+	assert.Len(t, locs, 2)
+
+	locs, err = d.References(Position{Line: 1, Character: 2})
+	assert.NoError(t, err)
+	assert.Empty(t, locs)
+}
@@ -0,0 +1,220 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package mock
+
+import (
+	"errors"
+	"fmt"
+	"github.com/pb33f/doctor/internal/routing"
+	"github.com/pb33f/doctor/model"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	drV3 "github.com/pb33f/doctor/model/high/v3"
+	"github.com/pb33f/doctor/validation"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/renderer"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ServerOptions controls the behavior of the mock server.
+type ServerOptions struct {
+	// BasePath is stripped from the start of every incoming request path before it is matched against
+	// the paths of the document, for example `/v1`.
+	BasePath string
+
+	// ValidateRequests will reject requests that do not satisfy the operation (parameters and request bodies
+	// are checked against their schemas) with a 400 response listing every violation.
+	ValidateRequests bool
+
+	// MaxBodySize is the largest request body, in bytes, read when validating requests. Larger bodies are
+	// rejected with a 413, zero uses validation.DefaultMaxBodySize.
+	MaxBodySize int64
+
+	// PrettyJSON renders generated JSON payloads with indentation.
+	PrettyJSON bool
+}
+
+// Server is an http.Handler that serves mock responses for every operation in a DrDocument.
+type Server struct {
+	drDocument    *model.DrDocument
+	options       *ServerOptions
+	validator     *validation.RequestValidator
+	jsonGenerator *renderer.MockGenerator
+	yamlGenerator *renderer.MockGenerator
+}
+
+// NewServer creates a new mock server for the supplied DrDocument. Requests are routed to operations using
+// the path templates of the document. Responses are served from examples when they exist, or generated from
+// the response schema when they do not.
+//
+// Clients can pick a specific response using the `Prefer` header, for example `Prefer: code=404, example=notFound`
+func NewServer(drDoc *model.DrDocument, opts *ServerOptions) http.Handler {
+	if opts == nil {
+		opts = &ServerOptions{}
+	}
+	s := &Server{
+		drDocument:    drDoc,
+		options:       opts,
+		jsonGenerator: renderer.NewMockGenerator(renderer.JSON),
+		yamlGenerator: renderer.NewMockGenerator(renderer.YAML),
+	}
+	if opts.PrettyJSON {
+		s.jsonGenerator.SetPretty()
+	}
+	s.validator = validation.NewValidator(drDoc)
+	s.validator.MaxBodySize = opts.MaxBodySize
+	return s
+}
+
+// ServeHTTP routes the request to an operation and serves a mock response.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := routing.TrimBasePath(r.URL.Path, s.options.BasePath)
+
+	match, err := s.drDocument.MatchOperation(r.Method, path)
+	switch {
+	case errors.Is(err, model.ErrPathNotFound):
+		validation.WriteError(w, http.StatusNotFound, fmt.Sprintf("no path in the specification matches '%s'", path), nil)
+		return
+	case errors.Is(err, model.ErrMethodNotAllowed):
+		validation.WriteError(w, http.StatusMethodNotAllowed,
+			fmt.Sprintf("method '%s' is not defined for path '%s'", r.Method, match.Path), nil)
+		return
+	}
+	op, pathItem, pathParams := match.Operation, match.PathItem, match.PathParameters
+
+	if s.options.ValidateRequests {
+		if code, violations := s.validator.ValidateOperation(r, pathItem, op, pathParams); code != http.StatusOK {
+			msg := "request is not valid for this operation"
+			if code != http.StatusBadRequest {
+				msg = violations[0].Message
+			}
+			validation.WriteError(w, code, msg, violations)
+			return
+		}
+	}
+
+	code, exampleName := parsePrefer(r.Header.Get("Prefer"))
+	response, statusCode := selectResponse(op, code)
+	if response == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if response.Headers != nil {
+		for k, h := range response.Headers.FromOldest() {
+			if h.Value.Example != nil {
+				w.Header().Set(k, h.Value.Example.Value)
+			}
+		}
+	}
+
+	if response.Content == nil || response.Content.Len() == 0 {
+		w.WriteHeader(statusCode)
+		return
+	}
+
+	contentType, mediaType := selectMediaType(response, r.Header.Get("Accept"))
+	generator := s.jsonGenerator
+	if strings.Contains(contentType, "yaml") {
+		generator = s.yamlGenerator
+	}
+	payload, err := generator.GenerateMock(responseView(mediaType), exampleName)
+	if err != nil {
+		validation.WriteError(w, http.StatusInternalServerError,
+			fmt.Sprintf("unable to generate mock for '%s': %s", mediaType.GenerateJSONPath(), err.Error()), nil)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(payload)
+}
+
+// responseView returns a copy of a media type, with its schema as responses see it, so writeOnly properties are
+// not generated.
+func responseView(mediaType *drV3.MediaType) *v3.MediaType {
+	mt := *mediaType.Value
+	if mt.Schema != nil && mt.Schema.Schema() != nil {
+		mt.Schema = base.CreateSchemaProxy(drBase.SchemaView(mt.Schema.Schema(), drBase.ViewResponse))
+	}
+	return &mt
+}
+
+// parsePrefer extracts the code and example preferences from a `Prefer` header.
+func parsePrefer(prefer string) (code, example string) {
+	for _, part := range strings.Split(prefer, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch strings.ToLower(kv[0]) {
+		case "code":
+			code = strings.TrimSpace(kv[1])
+		case "example":
+			example = strings.TrimSpace(kv[1])
+		}
+	}
+	return
+}
+
+// selectResponse picks the preferred response code if it exists, otherwise the lowest 2xx response, then
+// the default response, then the lowest declared code.
+func selectResponse(op *drV3.Operation, preferred string) (*drV3.Response, int) {
+	if op.Responses == nil {
+		return nil, http.StatusOK
+	}
+	if op.Responses.Codes != nil && preferred != "" {
+		if resp, ok := op.Responses.Codes.Get(preferred); ok {
+			return resp, statusCode(preferred)
+		}
+	}
+	var codes []string
+	if op.Responses.Codes != nil {
+		for k := range op.Responses.Codes.KeysFromOldest() {
+			codes = append(codes, k)
+		}
+	}
+	sort.Strings(codes)
+	for _, c := range codes {
+		if strings.HasPrefix(c, "2") {
+			return op.Responses.Codes.GetOrZero(c), statusCode(c)
+		}
+	}
+	if op.Responses.Default != nil {
+		return op.Responses.Default, http.StatusOK
+	}
+	for _, c := range codes {
+		if code, err := strconv.Atoi(c); err == nil {
+			return op.Responses.Codes.GetOrZero(c), code
+		}
+	}
+	return nil, http.StatusOK
+}
+
+// statusCode converts a response code into an HTTP status. Ranges like `2XX` become the first code in the
+// range, and anything else that is not a valid status falls back to 200.
+func statusCode(code string) int {
+	if c, err := strconv.Atoi(code); err == nil {
+		if c >= 100 && c <= 599 {
+			return c
+		}
+		return http.StatusOK
+	}
+	if len(code) == 3 && code[0] >= '1' && code[0] <= '5' && strings.EqualFold(code[1:], "XX") {
+		return int(code[0]-'0') * 100
+	}
+	return http.StatusOK
+}
+
+// selectMediaType negotiates the Accept header against the response media types, or picks the first declared
+// media type when nothing is acceptable.
+func selectMediaType(response *drV3.Response, accept string) (string, *drV3.MediaType) {
+	if contentType, mt := response.PickContentType(accept); mt != nil {
+		return contentType, mt
+	}
+	first := response.Content.First()
+	return first.Key(), first.Value()
+}
@@ -0,0 +1,228 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package mock
+
+import (
+	"encoding/json"
+	"github.com/pb33f/doctor/model"
+	"github.com/pb33f/doctor/validation"
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func loadBurgerShop(t *testing.T) *model.DrDocument {
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	doc, err := libopenapi.NewDocument(bytes)
+	require.NoError(t, err)
+	v3Doc, errs := doc.BuildV3Model()
+	require.Empty(t, errs)
+	return model.NewDrDocument(v3Doc)
+}
+
+func TestServer_ServeHTTP(t *testing.T) {
+	server := NewServer(loadBurgerShop(t), &ServerOptions{ValidateRequests: true})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/burgers/123", nil)
+	req.Header.Set("burgerHeader", "big-mac")
+	server.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.True(t, json.Valid(rec.Body.Bytes()))
+
+	// unknown path
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/pizza", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// unknown method
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, "/burgers/123", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	// missing required header
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/burgers/123", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var errResp validation.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errResp))
+	require.Len(t, errResp.Violations, 1)
+	assert.Contains(t, errResp.Violations[0].Message, "burgerHeader")
+
+	// broken body
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/burgers", strings.NewReader(`{"name":`))
+	req.Header.Set("Content-Type", "application/json")
+	server.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	errResp = validation.ErrorResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errResp))
+	require.Len(t, errResp.Violations, 1)
+	assert.Equal(t, "$.paths['/burgers'].post.requestBody.content['application/json']", errResp.Violations[0].Path)
+
+	// body that does not match its schema
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/burgers", strings.NewReader(`{"name":"big mac","numPatties":"two"}`))
+	req.Header.Set("Content-Type", "application/json")
+	server.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	errResp = validation.ErrorResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errResp))
+	require.NotEmpty(t, errResp.Violations)
+	assert.Contains(t, errResp.Violations[0].Location, "numPatties")
+
+	// valid body
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/burgers", strings.NewReader(`{"name":"big mac","numPatties":2}`))
+	req.Header.Set("Content-Type", "application/json")
+	server.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestServer_ServeHTTP_Prefer(t *testing.T) {
+	server := NewServer(loadBurgerShop(t), &ServerOptions{BasePath: "/v1"})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/v1/burgers/123", nil)
+	req.Header.Set("Prefer", "code=404")
+	server.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.True(t, json.Valid(rec.Body.Bytes()))
+}
+
+func TestServer_ServeHTTP_Views(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: users
+  version: 1.0.0
+paths:
+  /users:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/User'
+      responses:
+        "201":
+          description: created
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+components:
+  schemas:
+    User:
+      type: object
+      required: [id, name, password]
+      properties:
+        id:
+          type: string
+          readOnly: true
+        name:
+          type: string
+        password:
+          type: string
+          writeOnly: true`
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	v3Doc, _ := doc.BuildV3Model()
+	server := NewServer(model.NewDrDocument(v3Doc), &ServerOptions{ValidateRequests: true})
+
+	// the readOnly id is not required in requests, the writeOnly password is not generated for responses.
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"pb33f","password":"burgers"}`))
+	req.Header.Set("Content-Type", "application/json")
+	server.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var user map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &user))
+	assert.Contains(t, user, "id")
+	assert.Contains(t, user, "name")
+	assert.NotContains(t, user, "password")
+}
+
+func TestServer_ServeHTTP_RangeCodes(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: ranges
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        "2XX":
+          description: some pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: string
+        "4XX":
+          description: something went wrong
+          content:
+            application/json:
+              schema:
+                type: object`
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	v3Doc, _ := doc.BuildV3Model()
+	server := NewServer(model.NewDrDocument(v3Doc), nil)
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/pets", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+	req.Header.Set("Prefer", "code=2XX")
+	server.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/pets", nil)
+	req.Header.Set("Prefer", "code=4XX")
+	server.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestServer_ServeHTTP_BasePathBoundary(t *testing.T) {
+	server := NewServer(loadBurgerShop(t), &ServerOptions{BasePath: "/v1/"})
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/burgers/123", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1burgers/123", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestStatusCode(t *testing.T) {
+	assert.Equal(t, http.StatusCreated, statusCode("201"))
+	assert.Equal(t, http.StatusOK, statusCode("2XX"))
+	assert.Equal(t, http.StatusNotFound, statusCode("404"))
+	assert.Equal(t, http.StatusBadRequest, statusCode("4xx"))
+	assert.Equal(t, http.StatusInternalServerError, statusCode("5XX"))
+	assert.Equal(t, http.StatusOK, statusCode("default"))
+	assert.Equal(t, http.StatusOK, statusCode("9XX"))
+	assert.Equal(t, http.StatusOK, statusCode("42"))
+}
+
+func TestServer_ServeHTTP_MaxBodySize(t *testing.T) {
+	server := NewServer(loadBurgerShop(t), &ServerOptions{ValidateRequests: true, MaxBodySize: 8})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/burgers", strings.NewReader(`{"name":"big mac","numPatties":2}`))
+	req.Header.Set("Content-Type", "application/json")
+	server.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
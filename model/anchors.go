@@ -0,0 +1,143 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"gopkg.in/yaml.v3"
+	"slices"
+	"sort"
+)
+
+// anchorIndex tracks where YAML anchors are used through aliases, across every file in the rolodex.
+type anchorIndex struct {
+	// values maps a key node to its value, for every pair whose value is an alias, or a sequence containing aliases.
+	values map[*yaml.Node]*yaml.Node
+
+	// aliases maps an anchor to every alias node that uses it.
+	aliases map[*yaml.Node][]*yaml.Node
+
+	// models maps an anchor to every walked object whose value is the anchor, from the definition and alias sites.
+	models map[*yaml.Node][]drBase.Foundational
+}
+
+type aliasable interface {
+	SetAliasNode(node *yaml.Node)
+	GetArrayIndex() *int
+}
+
+// buildAnchorIndex scans the root document, and any other documents in the rolodex, for aliases.
+func (w *DrDocument) buildAnchorIndex() *anchorIndex {
+	ai := &anchorIndex{
+		values:  make(map[*yaml.Node]*yaml.Node),
+		aliases: make(map[*yaml.Node][]*yaml.Node),
+		models:  make(map[*yaml.Node][]drBase.Foundational),
+	}
+	if w.index == nil {
+		return ai
+	}
+	roots := []*yaml.Node{w.index.GetRootNode()}
+	if rolodex := w.index.GetRolodex(); rolodex != nil {
+		for _, idx := range rolodex.GetIndexes() {
+			if idx != nil && idx.GetRootNode() != w.index.GetRootNode() {
+				roots = append(roots, idx.GetRootNode())
+			}
+		}
+	}
+	seen := make(map[*yaml.Node]bool)
+	for _, root := range roots {
+		ai.scan(root, seen)
+	}
+	return ai
+}
+
+func (ai *anchorIndex) scan(node *yaml.Node, seen map[*yaml.Node]bool) {
+	if node == nil || seen[node] {
+		return
+	}
+	seen[node] = true
+	switch node.Kind {
+	case yaml.AliasNode:
+		if node.Alias != nil {
+			ai.aliases[node.Alias] = append(ai.aliases[node.Alias], node)
+		}
+		return
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value := node.Content[i+1]
+			if value.Kind == yaml.AliasNode || (value.Kind == yaml.SequenceNode &&
+				slices.ContainsFunc(value.Content, func(n *yaml.Node) bool { return n.Kind == yaml.AliasNode })) {
+				ai.values[node.Content[i]] = value
+			}
+		}
+	}
+	for _, n := range node.Content {
+		ai.scan(n, seen)
+	}
+}
+
+// track records a walked object against the anchor it represents (if any), and marks it as an alias usage
+// when it was included through an alias.
+func (ai *anchorIndex) track(f drBase.Foundational) {
+	if ai == nil || len(ai.aliases) == 0 {
+		return
+	}
+	value := f.GetValueNode()
+	if value == nil || ai.aliases[value] == nil {
+		return
+	}
+	ai.models[value] = append(ai.models[value], f)
+	a, ok := f.(aliasable)
+	if !ok || f.GetKeyNode() == nil {
+		return
+	}
+	site := ai.values[f.GetKeyNode()]
+	if site == nil {
+		return
+	}
+	if site.Kind == yaml.SequenceNode {
+		idx := a.GetArrayIndex()
+		if idx == nil || *idx < 0 || *idx >= len(site.Content) {
+			return
+		}
+		site = site.Content[*idx]
+	}
+	if site.Kind == yaml.AliasNode && site.Alias == value {
+		a.SetAliasNode(site)
+	}
+}
+
+// anchorModels returns every walked object that represents the same anchor as the supplied node, which can either
+// be the anchor itself, or an alias of it. Objects are sorted by JSONPath, nil is returned if the node has no
+// aliases.
+func (w *DrDocument) anchorModels(node *yaml.Node) []drBase.Foundational {
+	if w.anchors == nil || node == nil {
+		return nil
+	}
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	models := slices.Clone(w.anchors.models[node])
+	sort.Slice(models, func(i, j int) bool {
+		return models[i].GenerateJSONPath() < models[j].GenerateJSONPath()
+	})
+	return models
+}
+
+// LocateAliases returns the anchor definition a node represents, and every alias (`*anchor`) that uses it. The node
+// can be the anchor, or one of its aliases. Nil is returned if the node is not anchored, or has no aliases.
+func (w *DrDocument) LocateAliases(node *yaml.Node) (*yaml.Node, []*yaml.Node) {
+	if w == nil || w.anchors == nil || node == nil {
+		return nil, nil
+	}
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	aliases := w.anchors.aliases[node]
+	if len(aliases) == 0 {
+		return nil, nil
+	}
+	return node, slices.Clone(aliases)
+}
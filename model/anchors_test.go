@@ -0,0 +1,83 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWalker_WalkV3_Anchors(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: anchors
+  version: 1.0.0
+components:
+  parameters:
+    Page: &page
+      name: page
+      in: query
+      schema:
+        type: integer
+paths:
+  /burgers:
+    get:
+      parameters:
+        - *page
+      responses:
+        "200": &ok
+          description: ok
+  /fries:
+    get:
+      parameters:
+        - *page
+      responses:
+        "200": *ok`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocument(v3Doc)
+
+	page := walker.V3Document.Components.Parameters.GetOrZero("Page")
+	assert.False(t, page.IsAliasUsage())
+	fries := walker.V3Document.Paths.PathItems.GetOrZero("/fries").Get
+	assert.True(t, fries.Parameters[0].IsAliasUsage())
+	assert.Equal(t, 23, fries.Parameters[0].GetAliasNode().Line)
+
+	anchor, aliases := walker.LocateAliases(page.ValueNode)
+	assert.Equal(t, "page", anchor.Anchor)
+	assert.Len(t, aliases, 2)
+
+	// locating the anchor definition also returns the alias sites.
+	models, err := walker.LocateModelsByKeyAndValue(page.KeyNode, page.ValueNode)
+	require.NoError(t, err)
+	var paths []string
+	for _, m := range models {
+		paths = append(paths, m.GenerateJSONPath())
+	}
+	assert.Equal(t, []string{
+		"$.components.parameters['Page']",
+		"$.paths['/burgers'].get.parameters[0]",
+		"$.paths['/fries'].get.parameters[0]",
+	}, paths)
+
+	// locating an alias site returns the definition too.
+	friesOk := fries.Responses.Codes.GetOrZero("200")
+	assert.True(t, friesOk.IsAliasUsage())
+	models, err = walker.LocateModelsByKeyAndValue(friesOk.KeyNode, friesOk.GetAliasNode())
+	require.NoError(t, err)
+	paths = nil
+	for _, m := range models {
+		paths = append(paths, m.GenerateJSONPath())
+	}
+	assert.Equal(t, []string{
+		"$.paths['/burgers'].get.responses['200']",
+		"$.paths['/fries'].get.responses", // the line of the alias is also the last line of the responses.
+		"$.paths['/fries'].get.responses['200']",
+	}, paths)
+	assert.False(t, models[0].(base.HasAlias).IsAliasUsage())
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"context"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	drV3 "github.com/pb33f/doctor/model/high/v3"
+	"sync"
+)
+
+// RetryBuildErrors attempts to build every schema that failed to build during the walk again, for use after the
+// configuration of the index or rolodex has changed (for example, after enabling file references or adding a
+// file system). When at least one of the schemas now builds, the document is walked again with the same
+// configuration so the rebuilt schemas (and everything below them) are part of the model.
+//
+// The build errors that remain are returned.
+func (w *DrDocument) RetryBuildErrors() []*drBase.BuildError {
+	if len(w.BuildErrors) == 0 || w.document == nil {
+		return w.BuildErrors
+	}
+	rebuilt := false
+	for _, be := range w.BuildErrors {
+		if be.SchemaProxy != nil && be.SchemaProxy.Schema() != nil {
+			rebuilt = true
+		}
+	}
+	if !rebuilt {
+		return w.BuildErrors
+	}
+	w.lineIndexOnce = sync.Once{}
+	w.routerOnce = sync.Once{}
+	w.definitionsOnce = sync.Once{}
+	w.definitions = nil
+	w.pendingObjects = nil
+	w.Edges = nil
+	w.walkV3(context.Background(), w.document, w.config)
+	return w.BuildErrors
+}
+
+// buildErrorOwner returns the JSONPath of the closest operation or path item that owns the schema that failed
+// to build, or an empty string if there isn't one.
+func buildErrorOwner(be *drBase.BuildError) string {
+	if be.DrSchemaProxy == nil {
+		return ""
+	}
+	var f drBase.Foundational = be.DrSchemaProxy
+	for f != nil {
+		switch f.(type) {
+		case *drV3.Operation, *drV3.PathItem:
+			return f.GenerateJSONPath()
+		}
+		f = f.GetParent()
+	}
+	return ""
+}
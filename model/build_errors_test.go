@@ -0,0 +1,103 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWalker_WalkV3_RetryBuildErrors(t *testing.T) {
+
+	yml := `openapi: "3.1"
+paths:
+  /pizza:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                additionalProperties: indeterminate
+components:
+  schemas:
+    Foo:
+      type: object
+      additionalProperties: indeterminate`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(yml))
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocument(v3Doc)
+
+	require.Len(t, walker.BuildErrors, 2)
+	op := walker.BuildErrors[0]
+	assert.Equal(t, base.BuildErrorSchema, op.Category)
+	assert.Equal(t, "$.paths['/pizza'].get", op.OwnerPath)
+	assert.Equal(t, "$.paths['/pizza'].get.responses['200'].content['application/json'].schema", op.JSONPath)
+	assert.Equal(t, 10, op.Line)
+
+	foo := walker.BuildErrors[1]
+	assert.Empty(t, foo.OwnerPath)
+	assert.Equal(t, "$.components.schemas['Foo']", foo.JSONPath)
+
+	// nothing has changed, so nothing can be rebuilt.
+	assert.Len(t, walker.RetryBuildErrors(), 2)
+
+	// fix the operation schema, and try again.
+	fixed := op.SchemaProxy.GoLow().GetValueNode().Content[3]
+	fixed.Value, fixed.Tag = "true", "!!bool"
+
+	remaining := walker.RetryBuildErrors()
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "$.components.schemas['Foo']", remaining[0].JSONPath)
+	assert.Len(t, walker.Schemas, 1)
+}
+
+func TestWalker_WalkV3_RetryBuildErrors_ResolveReference(t *testing.T) {
+
+	yml := `openapi: "3.1"
+paths:
+  /pizza:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Foo'
+components:
+  schemas:
+    Foo:
+      type: object
+      additionalProperties: indeterminate`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(yml))
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocument(v3Doc)
+
+	require.NotEmpty(t, walker.BuildErrors)
+	foo := walker.BuildErrors[len(walker.BuildErrors)-1]
+	assert.Equal(t, "$.components.schemas['Foo']", foo.JSONPath)
+
+	// resolve before the retry, so the definitions are cached.
+	op := walker.V3Document.Paths.PathItems.GetOrZero("/pizza").Get
+	stale := walker.ResolveReference("#/components/schemas/Foo", op)
+	require.NotNil(t, stale)
+
+	fixed := foo.SchemaProxy.GoLow().GetValueNode().Content[3]
+	fixed.Value, fixed.Tag = "true", "!!bool"
+	require.Empty(t, walker.RetryBuildErrors())
+
+	resolved := walker.ResolveReference("#/components/schemas/Foo", op)
+	require.NotNil(t, resolved)
+	assert.NotSame(t, stale, resolved)
+	assert.Equal(t, "$.components.schemas['Foo']", resolved.GenerateJSONPath())
+	assert.Same(t, walker.V3Document.Components.Schemas.GetOrZero("Foo"), resolved)
+}
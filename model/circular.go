@@ -0,0 +1,144 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/index"
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+const (
+	// CircularArray is a loop through an array, these are safe as an empty array ends the loop.
+	CircularArray = "array"
+	// CircularPolymorphic is a loop through oneOf, anyOf or allOf.
+	CircularPolymorphic = "polymorphic"
+	// CircularInvalid is a loop where every hop is required, it can never be satisfied.
+	CircularInvalid = "invalid"
+	// CircularStandard is any other loop, it can be ended by omitting an optional property.
+	CircularStandard = "standard"
+)
+
+// CircularReference is a reference cycle found in the document.
+type CircularReference struct {
+	Classification  string
+	ArraySafe       bool
+	Polymorphic     bool
+	PolymorphicType string
+	Invalid         bool
+
+	// Hops contains every reference in the journey, in order, ending with the loop point.
+	Hops []*CircularHop
+
+	// Result is the underlying result from the index.
+	Result *index.CircularReferenceResult
+}
+
+// CircularHop is a single reference in a circular reference journey.
+type CircularHop struct {
+	Definition string
+	JSONPath   string
+	Line       int
+	Column     int
+
+	// Schema is the doctor schema the hop points to, when it has been walked.
+	Schema *drBase.Schema
+}
+
+// CircularReferences returns every circular reference found in the document, classified as array-safe,
+// polymorphic, invalid (infinite) or standard loops. Each hop in the journey is returned with its JSONPath,
+// line information and the doctor schema it points to.
+func (w *DrDocument) CircularReferences() []*CircularReference {
+	if w == nil || w.index == nil {
+		return nil
+	}
+	var results []*index.CircularReferenceResult
+	results = append(results, w.index.GetCircularReferences()...)
+	results = append(results, w.index.GetIgnoredPolymorphicCircularReferences()...)
+	results = append(results, w.index.GetIgnoredArrayCircularReferences()...)
+	if r := w.index.GetRolodex(); r != nil {
+		results = append(results, r.GetIgnoredCircularReferences()...)
+	}
+
+	// map the walked schemas by their root node, so hops can be tied back to doctor models.
+	schemaNodes := make(map[*yaml.Node]*drBase.Schema)
+	for _, s := range append(append([]*drBase.Schema{}, w.Schemas...), w.SkippedSchemas...) {
+		if s.Value != nil && s.Value.GoLow() != nil {
+			if _, ok := schemaNodes[s.Value.GoLow().RootNode]; !ok {
+				schemaNodes[s.Value.GoLow().RootNode] = s
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	var circles []*CircularReference
+	for _, res := range results {
+		if res == nil || res.LoopPoint == nil {
+			continue
+		}
+		var journey []string
+		for _, j := range res.Journey {
+			journey = append(journey, j.FullDefinition)
+		}
+		key := strings.Join(journey, "->")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		c := &CircularReference{
+			ArraySafe:       res.IsArrayResult,
+			Polymorphic:     res.IsPolymorphicResult,
+			PolymorphicType: res.PolymorphicType,
+			Invalid:         res.IsInfiniteLoop && !res.IsArrayResult && !res.IsPolymorphicResult,
+			Result:          res,
+		}
+
+		// array and polymorphic loops can always be ended (with an empty array, or another choice), even
+		// when every hop is required.
+		switch {
+		case res.IsArrayResult:
+			c.Classification = CircularArray
+		case res.IsPolymorphicResult:
+			c.Classification = CircularPolymorphic
+		case res.IsInfiniteLoop:
+			c.Classification = CircularInvalid
+		default:
+			c.Classification = CircularStandard
+		}
+		for _, ref := range res.Journey {
+			c.Hops = append(c.Hops, w.buildCircularHop(ref, schemaNodes))
+		}
+		circles = append(circles, c)
+	}
+	return circles
+}
+
+func (w *DrDocument) buildCircularHop(ref *index.Reference, schemaNodes map[*yaml.Node]*drBase.Schema) *CircularHop {
+	hop := &CircularHop{
+		Definition: ref.Definition,
+		JSONPath:   ref.Path,
+	}
+	if ref.Node != nil {
+		hop.Line, hop.Column = ref.Node.Line, ref.Node.Column
+		hop.Schema = schemaNodes[ref.Node]
+	}
+	if w.V3Document != nil && w.V3Document.Components != nil && w.V3Document.Components.Schemas != nil &&
+		(ref.Index == nil || ref.Index == w.index) {
+		if kind, name := SplitComponentDefinition(ComponentDefinition(ref.Definition)); kind == "schemas" {
+			if sp, ok := w.V3Document.Components.Schemas.Get(name); ok {
+				hop.JSONPath = sp.GenerateJSONPath()
+				if sp.KeyNode != nil {
+					hop.Line, hop.Column = sp.KeyNode.Line, sp.KeyNode.Column
+				}
+				if sp.Schema != nil {
+					hop.Schema = sp.Schema
+				}
+			}
+		}
+	}
+	return hop
+}
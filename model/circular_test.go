@@ -0,0 +1,76 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWalker_WalkV3_CircularReferences(t *testing.T) {
+
+	yml := `openapi: 3.1.0
+paths:
+  /test:
+    get:
+      responses:
+        '200':
+          description: OK
+components:
+  schemas:
+    Obj:
+      type: object
+      properties:
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Obj'
+      required:
+        - children
+    Egg:
+      type: object
+      required:
+        - chicken
+      properties:
+        chicken:
+          $ref: '#/components/schemas/Chicken'
+    Chicken:
+      type: object
+      required:
+        - egg
+      properties:
+        egg:
+          $ref: '#/components/schemas/Egg'`
+
+	docConfig := &datamodel.DocumentConfiguration{
+		IgnoreArrayCircularReferences: true,
+	}
+
+	newDoc, _ := libopenapi.NewDocumentWithConfiguration([]byte(yml), docConfig)
+	v3Doc, _ := newDoc.BuildV3Model()
+
+	walker := NewDrDocument(v3Doc)
+	circles := walker.CircularReferences()
+	require.Len(t, circles, 2)
+
+	classes := make(map[string]*CircularReference)
+	for _, c := range circles {
+		classes[c.Classification] = c
+	}
+	require.NotNil(t, classes[CircularArray])
+	assert.True(t, classes[CircularArray].ArraySafe)
+	assert.Equal(t, "$.components.schemas['Obj']", classes[CircularArray].Hops[0].JSONPath)
+	assert.Equal(t, 10, classes[CircularArray].Hops[0].Line)
+
+	require.NotNil(t, classes[CircularInvalid])
+	assert.True(t, classes[CircularInvalid].Invalid)
+	assert.GreaterOrEqual(t, len(classes[CircularInvalid].Hops), 2)
+	for _, hop := range classes[CircularInvalid].Hops {
+		assert.NotEmpty(t, hop.JSONPath)
+		assert.NotZero(t, hop.Line)
+	}
+}
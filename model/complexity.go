@@ -0,0 +1,220 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"gopkg.in/yaml.v3"
+	"math"
+	"strings"
+)
+
+// Dimensions of a complexity score.
+const (
+	ComplexitySchemaDepth          = "schema-depth"
+	ComplexityComposition          = "composition"
+	ComplexityRefFanOut            = "ref-fan-out"
+	ComplexityOperations           = "operations"
+	ComplexityPathParameterDensity = "path-parameter-density"
+)
+
+// maxComplexityDepth limits how deep schemas are followed when measuring their depth.
+const maxComplexityDepth = 64
+
+// complexityMidpoints are the measurements at which each dimension scores 0.5, far beyond what a well factored
+// specification measures.
+var complexityMidpoints = map[string]float64{
+	ComplexitySchemaDepth:          8,
+	ComplexityComposition:          0.5,
+	ComplexityRefFanOut:            12,
+	ComplexityOperations:           150,
+	ComplexityPathParameterDensity: 1.5,
+}
+
+// ComplexityDimension is one measurement of the complexity of a specification.
+type ComplexityDimension struct {
+	Name string `json:"name"`
+
+	// Value is the measurement: the deepest schema nesting, the composition members per schema, the most
+	// components a single schema references, the number of operations, or the parameters per path.
+	Value float64 `json:"value"`
+
+	// Midpoint is the value that scores 0.5, Score is the value normalized from 0 to 1 (value / (value + midpoint)).
+	Midpoint float64 `json:"midpoint"`
+	Score    float64 `json:"score"`
+
+	// Worst is the schema that measured the value, for the schema depth and ref fan-out dimensions.
+	Worst string `json:"worst,omitempty"`
+}
+
+// ComplexityScore is a normalized score of how complex a specification is, with the breakdown of its dimensions.
+type ComplexityScore struct {
+	// Score is the mean of the scores of the dimensions, from 0 (trivial) to 100.
+	Score      float64                `json:"score"`
+	Dimensions []*ComplexityDimension `json:"dimensions"`
+}
+
+// Dimension returns a dimension of the score by name, or nil.
+func (c *ComplexityScore) Dimension(name string) *ComplexityDimension {
+	for _, d := range c.Dimensions {
+		if d.Name == name {
+			return d
+		}
+	}
+	return nil
+}
+
+// ComplexityScore scores how complex the document is, to flag specifications that need refactoring. It combines
+// five dimensions, each normalized from 0 to 1 so that none can dominate the score:
+//
+//   - schema depth: the deepest nesting of properties, items and compositions beneath a component schema,
+//     following references (circular references stop at the schema they loop back to).
+//   - composition: the allOf, oneOf and anyOf members per walked schema.
+//   - ref fan-out: the most distinct components referenced from within a single component schema.
+//   - operations: the number of operations, including webhooks.
+//   - path parameter density: the templated segments per path.
+func (w *DrDocument) ComplexityScore() *ComplexityScore {
+	score := &ComplexityScore{}
+	if w == nil || w.V3Document == nil || w.V3Document.Document == nil {
+		return score
+	}
+	doc := w.V3Document.Document
+
+	depth, fanOut := &ComplexityDimension{Name: ComplexitySchemaDepth}, &ComplexityDimension{Name: ComplexityRefFanOut}
+	depths := make(map[*yaml.Node]int)
+	if doc.Components != nil && doc.Components.Schemas != nil {
+		for name, proxy := range doc.Components.Schemas.FromOldest() {
+			ref := "#/components/schemas/" + name
+			if d := schemaDepth(proxy, depths, make(map[*yaml.Node]bool)); float64(d) > depth.Value {
+				depth.Value, depth.Worst = float64(d), ref
+			}
+			refs := make(map[string]bool)
+			if schema := proxy.Schema(); schema != nil && !proxy.IsReference() {
+				collectReferences(schema, refs, make(map[*yaml.Node]bool))
+			}
+			if n := float64(len(refs)); n > fanOut.Value {
+				fanOut.Value, fanOut.Worst = n, ref
+			}
+		}
+	}
+
+	composition := &ComplexityDimension{Name: ComplexityComposition}
+	var members, schemas int
+	for _, s := range w.Schemas {
+		if s == nil || s.Value == nil {
+			continue
+		}
+		schemas++
+		members += len(s.Value.AllOf) + len(s.Value.OneOf) + len(s.Value.AnyOf)
+	}
+	if schemas > 0 {
+		composition.Value = float64(members) / float64(schemas)
+	}
+
+	operations := &ComplexityDimension{Name: ComplexityOperations, Value: float64(len(w.Operations()))}
+
+	density := &ComplexityDimension{Name: ComplexityPathParameterDensity}
+	if doc.Paths != nil && doc.Paths.PathItems != nil {
+		var paths, templated int
+		for path := range doc.Paths.PathItems.KeysFromOldest() {
+			paths++
+			templated += strings.Count(path, "{")
+		}
+		if paths > 0 {
+			density.Value = float64(templated) / float64(paths)
+		}
+	}
+
+	score.Dimensions = []*ComplexityDimension{depth, composition, fanOut, operations, density}
+	var total float64
+	for _, d := range score.Dimensions {
+		d.Midpoint = complexityMidpoints[d.Name]
+		normalized := d.Value / (d.Value + d.Midpoint)
+		d.Score = math.Round(normalized*1000) / 1000
+		total += normalized
+	}
+	score.Score = math.Round(total/float64(len(score.Dimensions))*100*100) / 100
+	return score
+}
+
+// schemaDepth returns how deep a schema nests, following references. Depths are memoized, schemas on the stack
+// (circular references) are not followed again. Schemas are keyed by their root node, every reference to a
+// schema builds a schema of its own.
+func schemaDepth(proxy *base.SchemaProxy, depths map[*yaml.Node]int, stack map[*yaml.Node]bool) int {
+	if proxy == nil {
+		return 0
+	}
+	schema := proxy.Schema()
+	if schema == nil || schema.GoLow() == nil {
+		return 0
+	}
+	key := schema.GoLow().RootNode
+	if stack[key] || len(stack) >= maxComplexityDepth {
+		return 0
+	}
+	if d, ok := depths[key]; ok {
+		return d
+	}
+	stack[key] = true
+	deepest := 0
+	for _, child := range schemaChildren(schema) {
+		deepest = max(deepest, schemaDepth(child, depths, stack))
+	}
+	delete(stack, key)
+	depths[key] = deepest + 1
+	return deepest + 1
+}
+
+// collectReferences collects the references made from within a schema, without following them.
+func collectReferences(schema *base.Schema, refs map[string]bool, seen map[*yaml.Node]bool) {
+	if schema.GoLow() == nil || seen[schema.GoLow().RootNode] {
+		return
+	}
+	seen[schema.GoLow().RootNode] = true
+	for _, child := range schemaChildren(schema) {
+		if child.IsReference() {
+			refs[child.GetReference()] = true
+			continue
+		}
+		if s := child.Schema(); s != nil {
+			collectReferences(s, refs, seen)
+		}
+	}
+}
+
+// schemaChildren returns the schemas nested directly beneath a schema.
+func schemaChildren(schema *base.Schema) []*base.SchemaProxy {
+	var children []*base.SchemaProxy
+	if schema.Properties != nil {
+		for _, p := range schema.Properties.FromOldest() {
+			children = append(children, p)
+		}
+	}
+	if schema.PatternProperties != nil {
+		for _, p := range schema.PatternProperties.FromOldest() {
+			children = append(children, p)
+		}
+	}
+	if schema.Items != nil && schema.Items.IsA() {
+		children = append(children, schema.Items.A)
+	}
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.IsA() {
+		children = append(children, schema.AdditionalProperties.A)
+	}
+	children = append(children, schema.AllOf...)
+	children = append(children, schema.OneOf...)
+	children = append(children, schema.AnyOf...)
+	children = append(children, schema.PrefixItems...)
+	if schema.Not != nil {
+		children = append(children, schema.Not)
+	}
+	var nonNil []*base.SchemaProxy
+	for _, c := range children {
+		if c != nil {
+			nonNil = append(nonNil, c)
+		}
+	}
+	return nonNil
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWalker_WalkV3_ComplexityScore(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: burgers
+  version: 1
+paths:
+  /burgers/{burgerId}:
+    get:
+      responses:
+        "200":
+          description: a burger
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Burger'
+  /burgers/{burgerId}/toppings/{toppingId}:
+    get:
+      responses:
+        "200":
+          description: a topping
+    delete:
+      responses:
+        "204":
+          description: removed
+  /menu:
+    get:
+      responses:
+        "200":
+          description: the menu
+components:
+  schemas:
+    Burger:
+      type: object
+      properties:
+        name:
+          type: string
+        bun:
+          $ref: '#/components/schemas/Bun'
+        toppings:
+          type: array
+          items:
+            $ref: '#/components/schemas/Topping'
+    Bun:
+      oneOf:
+        - type: string
+        - $ref: '#/components/schemas/Topping'
+    Topping:
+      type: object
+      properties:
+        name:
+          type: string
+        next:
+          $ref: '#/components/schemas/Topping'`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+	score := NewDrDocument(v3Doc).ComplexityScore()
+	require.Len(t, score.Dimensions, 5)
+
+	// Burger > toppings > items (Topping) > next, the circular next is not followed.
+	depth := score.Dimension(ComplexitySchemaDepth)
+	assert.Equal(t, 4.0, depth.Value)
+	assert.Equal(t, "#/components/schemas/Burger", depth.Worst)
+	assert.Equal(t, 0.333, depth.Score)
+
+	fanOut := score.Dimension(ComplexityRefFanOut)
+	assert.Equal(t, 2.0, fanOut.Value)
+	assert.Equal(t, "#/components/schemas/Burger", fanOut.Worst)
+
+	assert.Equal(t, 4.0, score.Dimension(ComplexityOperations).Value)
+	assert.Equal(t, 1.0, score.Dimension(ComplexityPathParameterDensity).Value)
+	assert.Positive(t, score.Dimension(ComplexityComposition).Value)
+	assert.Nil(t, score.Dimension("burgers"))
+
+	var total float64
+	for _, d := range score.Dimensions {
+		assert.Equal(t, complexityMidpoints[d.Name], d.Midpoint)
+		total += d.Value / (d.Value + d.Midpoint)
+	}
+	assert.InDelta(t, total/5*100, score.Score, 0.01)
+
+	// an empty document scores nothing.
+	assert.Zero(t, (*DrDocument)(nil).ComplexityScore().Score)
+}
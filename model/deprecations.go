@@ -0,0 +1,148 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+)
+
+const (
+	DeprecatedOperation = "operation"
+	DeprecatedParameter = "parameter"
+	DeprecatedProperty  = "property"
+)
+
+// Deprecation is a deprecated element found in a document.
+type Deprecation struct {
+	Kind     string
+	Name     string
+	JSONPath string
+
+	// Line and Column locate the deprecated element in the specification.
+	Line   int
+	Column int
+
+	// Operation is the operation the element is reachable from, it is nil for component schemas that
+	// are not reachable from any operation.
+	Operation *PathOperation
+
+	// Reference is the component reference the element was reached through, if it was deprecated
+	// inside a referenced component.
+	Reference string
+
+	// Object is the doctor model for the element, when one exists. Properties are reached by following
+	// references, so they do not always have a walked doctor model.
+	Object drBase.Foundational
+}
+
+// DeprecationReport contains every deprecated operation, parameter and schema property in a document.
+type DeprecationReport struct {
+	Operations []*Deprecation
+	Parameters []*Deprecation
+	Properties []*Deprecation
+}
+
+// DeprecationReport enumerates deprecated operations, parameters and schema properties. Properties are
+// reported once for every operation they are reachable from (including through references), and once for
+// the component schema that defines them.
+func (w *DrDocument) DeprecationReport() *DeprecationReport {
+	report := &DeprecationReport{}
+	if w == nil || w.V3Document == nil {
+		return report
+	}
+	for _, po := range w.Operations() {
+		if po.Operation.Value.Deprecated != nil && *po.Operation.Value.Deprecated {
+			report.Operations = append(report.Operations, &Deprecation{
+				Kind:      DeprecatedOperation,
+				Name:      po.Method + " " + po.Path,
+				JSONPath:  po.Operation.GenerateJSONPath(),
+				Line:      po.Operation.KeyNode.Line,
+				Column:    po.Operation.KeyNode.Column,
+				Operation: po,
+				Object:    po.Operation,
+			})
+		}
+		for _, p := range po.Parameters() {
+			if p.Value.Deprecated {
+				d := &Deprecation{
+					Kind:      DeprecatedParameter,
+					Name:      p.Value.Name,
+					JSONPath:  p.GenerateJSONPath(),
+					Operation: po,
+					Object:    p,
+				}
+				if p.KeyNode != nil {
+					d.Line, d.Column = p.KeyNode.Line, p.KeyNode.Column
+				}
+				if l := p.Value.GoLow(); l != nil && l.IsReference() {
+					d.Reference = l.GetReference()
+				}
+				report.Parameters = append(report.Parameters, d)
+			}
+		}
+		visitOperationSchemas(po, func(v *visitedSchema) {
+			if d := deprecatedProperty(v); d != nil {
+				d.Operation = po
+				report.Properties = append(report.Properties, d)
+			}
+		})
+	}
+	if w.V3Document.Components != nil && w.V3Document.Components.Schemas != nil {
+		seen := make(map[*base.Schema]bool)
+		for _, sp := range w.V3Document.Components.Schemas.FromOldest() {
+			visitSchemaProxy(sp.Value, sp.GenerateJSONPath(), "", "", seen, func(v *visitedSchema) {
+				if d := deprecatedProperty(v); d != nil {
+					report.Properties = append(report.Properties, d)
+				}
+			})
+		}
+	}
+	return report
+}
+
+func deprecatedProperty(v *visitedSchema) *Deprecation {
+	if v.Property == "" || v.Schema.Deprecated == nil || !*v.Schema.Deprecated {
+		return nil
+	}
+	d := &Deprecation{
+		Kind:      DeprecatedProperty,
+		Name:      v.Property,
+		JSONPath:  v.JSONPath,
+		Reference: v.Reference,
+	}
+	if l := v.Schema.GoLow(); l != nil && l.RootNode != nil {
+		d.Line, d.Column = l.RootNode.Line, l.RootNode.Column
+	}
+	return d
+}
+
+// All returns every deprecation in the report.
+func (d *DeprecationReport) All() []*Deprecation {
+	var all []*Deprecation
+	all = append(all, d.Operations...)
+	all = append(all, d.Parameters...)
+	all = append(all, d.Properties...)
+	return all
+}
+
+// NewlyDeprecated returns every deprecation in this report that does not exist in the previous report,
+// elements are matched using their kind and JSONPath. This is used to highlight elements that have been
+// deprecated between two versions of a document.
+func (d *DeprecationReport) NewlyDeprecated(previous *DeprecationReport) []*Deprecation {
+	existing := make(map[string]bool)
+	if previous != nil {
+		for _, p := range previous.All() {
+			existing[p.Kind+":"+p.JSONPath] = true
+		}
+	}
+	var added []*Deprecation
+	for _, c := range d.All() {
+		if !existing[c.Kind+":"+c.JSONPath] {
+			added = append(added, c)
+		}
+	}
+	return added
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWalker_WalkV3_DeprecationReport(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /pizza:
+    get:
+      deprecated: true
+      parameters:
+        - $ref: '#/components/parameters/Topping'
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pizza'
+components:
+  parameters:
+    Topping:
+      in: query
+      name: topping
+      deprecated: true
+  schemas:
+    Pizza:
+      type: object
+      properties:
+        crust:
+          type: string
+          deprecated: true
+        size:
+          type: string`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+
+	walker := NewDrDocument(v3Doc)
+	report := walker.DeprecationReport()
+
+	require.Len(t, report.Operations, 1)
+	assert.Equal(t, "$.paths['/pizza'].get", report.Operations[0].JSONPath)
+	assert.Equal(t, 4, report.Operations[0].Line)
+
+	require.Len(t, report.Parameters, 1)
+	assert.Equal(t, "topping", report.Parameters[0].Name)
+	assert.Equal(t, "#/components/parameters/Topping", report.Parameters[0].Reference)
+
+	require.Len(t, report.Properties, 2)
+	assert.Equal(t, "$.paths['/pizza'].get.responses['200'].content['application/json'].schema.properties['crust']",
+		report.Properties[0].JSONPath)
+	assert.Equal(t, "#/components/schemas/Pizza", report.Properties[0].Reference)
+	assert.NotNil(t, report.Properties[0].Operation)
+	assert.Equal(t, "$.components.schemas['Pizza'].properties['crust']", report.Properties[1].JSONPath)
+	assert.Nil(t, report.Properties[1].Operation)
+
+	// nothing is new when compared against itself, everything is new compared to nothing.
+	assert.Empty(t, report.NewlyDeprecated(report))
+	assert.Len(t, report.NewlyDeprecated(nil), 4)
+}
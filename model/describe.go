@@ -0,0 +1,135 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+// Description is the catalog metadata of a specification, as used by API registries.
+type Description struct {
+	Title       string               `json:"title"`
+	Summary     string               `json:"summary,omitempty"`
+	Version     string               `json:"version"`
+	SpecVersion string               `json:"specVersion"`
+	Contact     *DescriptionContact  `json:"contact,omitempty"`
+	License     *DescriptionLicense  `json:"license,omitempty"`
+	Servers     []*ServerEnvironment `json:"servers,omitempty"`
+	Counts      *DescriptionCounts   `json:"counts"`
+	ContentHash string               `json:"contentHash"`
+}
+
+// DescriptionContact is the contact for the API.
+type DescriptionContact struct {
+	Name  string `json:"name,omitempty"`
+	URL   string `json:"url,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// DescriptionLicense is the license the API is offered under, Identifier is an SPDX expression.
+type DescriptionLicense struct {
+	Name       string `json:"name,omitempty"`
+	Identifier string `json:"identifier,omitempty"`
+	URL        string `json:"url,omitempty"`
+}
+
+// ServerEnvironment is a server declared for the whole document, the environment is the description of the
+// server (like `production` or `staging`). URLs are the concrete URLs the server can produce (see
+// drV3.Server.ExpandURLs).
+type ServerEnvironment struct {
+	Environment string   `json:"environment,omitempty"`
+	URL         string   `json:"url"`
+	URLs        []string `json:"urls,omitempty"`
+}
+
+// DescriptionCounts counts the contents of a specification, components are counted by kind.
+type DescriptionCounts struct {
+	Paths                int `json:"paths"`
+	Operations           int `json:"operations"`
+	DeprecatedOperations int `json:"deprecatedOperations"`
+	Webhooks             int `json:"webhooks"`
+	Tags                 int `json:"tags"`
+	Schemas              int `json:"schemas"`
+	Parameters           int `json:"parameters"`
+	Responses            int `json:"responses"`
+	RequestBodies        int `json:"requestBodies"`
+	SecuritySchemes      int `json:"securitySchemes"`
+}
+
+// Describe returns the catalog metadata of the document: its title, version, contact and license, the servers
+// (environments) it declares, the version of OpenAPI it is written in, counts of its contents and a hash of its
+// content. The content hash is the hex encoded SHA-256 checksum of the specification bytes, it changes whenever
+// the specification does (including formatting changes), so it can be used to tell when a catalog entry is stale.
+// Metadata is read from the document model, so it is complete even when only some sections were walked.
+func (w *DrDocument) Describe() *Description {
+	d := &Description{Counts: &DescriptionCounts{}}
+	if w == nil || w.V3Document == nil || w.V3Document.Document == nil {
+		return d
+	}
+	doc := w.V3Document.Document
+	d.SpecVersion = doc.Version
+	if doc.Info != nil {
+		d.Title, d.Summary, d.Version = doc.Info.Title, doc.Info.Summary, doc.Info.Version
+		if c := doc.Info.Contact; c != nil {
+			d.Contact = &DescriptionContact{Name: c.Name, URL: c.URL, Email: c.Email}
+		}
+		if l := doc.Info.License; l != nil {
+			d.License = &DescriptionLicense{Name: l.Name, Identifier: l.Identifier, URL: l.URL}
+		}
+	}
+
+	walked := make(map[string][]string)
+	for _, s := range w.V3Document.Servers {
+		if s.Value != nil {
+			walked[s.Value.URL] = s.ExpandURLs()
+		}
+	}
+	for _, s := range doc.Servers {
+		d.Servers = append(d.Servers, &ServerEnvironment{Environment: s.Description, URL: s.URL, URLs: walked[s.URL]})
+	}
+
+	counts := d.Counts
+	if doc.Paths != nil && doc.Paths.PathItems != nil {
+		counts.Paths = orderedmap.Len(doc.Paths.PathItems)
+		for _, pi := range doc.Paths.PathItems.FromOldest() {
+			for _, op := range pi.GetOperations().FromOldest() {
+				counts.Operations++
+				if op.Deprecated != nil && *op.Deprecated {
+					counts.DeprecatedOperations++
+				}
+			}
+		}
+	}
+	counts.Webhooks = orderedmap.Len(doc.Webhooks)
+	counts.Tags = len(doc.Tags)
+	if c := doc.Components; c != nil {
+		counts.Schemas = orderedmap.Len(c.Schemas)
+		counts.Parameters = orderedmap.Len(c.Parameters)
+		counts.Responses = orderedmap.Len(c.Responses)
+		counts.RequestBodies = orderedmap.Len(c.RequestBodies)
+		counts.SecuritySchemes = orderedmap.Len(c.SecuritySchemes)
+	}
+	d.ContentHash = w.contentHash()
+	return d
+}
+
+// contentHash returns the checksum of the specification bytes, or of the root node when the bytes are not known.
+func (w *DrDocument) contentHash() string {
+	var content []byte
+	if w.index != nil && w.index.GetConfig() != nil && w.index.GetConfig().SpecInfo != nil &&
+		w.index.GetConfig().SpecInfo.SpecBytes != nil {
+		content = *w.index.GetConfig().SpecInfo.SpecBytes
+	} else if w.index != nil && w.index.GetRootNode() != nil {
+		content, _ = yaml.Marshal(w.index.GetRootNode())
+	}
+	if content == nil {
+		return ""
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
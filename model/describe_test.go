@@ -0,0 +1,91 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestWalker_WalkV3_Describe(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: burgers
+  summary: burgers for everyone
+  version: 1.2.0
+  contact:
+    name: the kitchen
+    email: kitchen@example.com
+  license:
+    name: Apache 2.0
+    identifier: Apache-2.0
+servers:
+  - url: https://{region}.burgers.example.com
+    description: production
+    variables:
+      region:
+        default: eu
+        enum: [eu, us]
+  - url: https://staging.burgers.example.com
+    description: staging
+tags:
+  - name: burgers
+paths:
+  /burgers:
+    get:
+      responses:
+        "200":
+          description: ok
+    post:
+      deprecated: true
+      responses:
+        "201":
+          description: created
+webhooks:
+  cooked:
+    post:
+      responses:
+        "200":
+          description: ok
+components:
+  schemas:
+    Burger:
+      type: object
+  securitySchemes:
+    key:
+      type: apiKey
+      in: header
+      name: X-Key`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	v3Doc, _ := doc.BuildV3Model()
+	d := NewDrDocument(v3Doc).Describe()
+
+	assert.Equal(t, "burgers", d.Title)
+	assert.Equal(t, "burgers for everyone", d.Summary)
+	assert.Equal(t, "1.2.0", d.Version)
+	assert.Equal(t, "3.1.0", d.SpecVersion)
+	assert.Equal(t, &DescriptionContact{Name: "the kitchen", Email: "kitchen@example.com"}, d.Contact)
+	assert.Equal(t, "Apache-2.0", d.License.Identifier)
+
+	require.Len(t, d.Servers, 2)
+	assert.Equal(t, "production", d.Servers[0].Environment)
+	assert.Equal(t, []string{"https://eu.burgers.example.com", "https://us.burgers.example.com"}, d.Servers[0].URLs)
+	assert.Equal(t, "staging", d.Servers[1].Environment)
+
+	assert.Equal(t, &DescriptionCounts{Paths: 1, Operations: 2, DeprecatedOperations: 1, Webhooks: 1, Tags: 1,
+		Schemas: 1, SecuritySchemes: 1}, d.Counts)
+	assert.Len(t, d.ContentHash, 64)
+
+	// the hash changes with the content.
+	doc, _ = libopenapi.NewDocument([]byte(strings.Replace(spec, "1.2.0", "1.3.0", 1)))
+	v3Doc, _ = doc.BuildV3Model()
+	assert.NotEqual(t, d.ContentHash, NewDrDocument(v3Doc).Describe().ContentHash)
+
+	assert.Empty(t, (*DrDocument)(nil).Describe().Title)
+}
@@ -0,0 +1,153 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"fmt"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"gopkg.in/yaml.v3"
+	"sort"
+)
+
+const (
+	FindingDiscriminatorProperty = "discriminator-property"
+	FindingDiscriminatorMapping  = "discriminator-mapping"
+	FindingDiscriminatorCoverage = "discriminator-coverage"
+)
+
+// ValidateDiscriminators checks every discriminator in the document:
+//
+//   - every oneOf and anyOf member, and every mapped schema, defines the discriminator property (directly or
+//     through allOf).
+//   - every mapping points to a schema that exists.
+//   - every oneOf and anyOf member is covered by a mapping, when the discriminator has one. Inline members can't
+//     be named by the value of the property, so they are always flagged.
+//
+// Findings point to the nodes they were found on: the member or mapped schema missing the property, the target of
+// a mapping, or the member that is not covered. They are ordered by line.
+func (w *DrDocument) ValidateDiscriminators() []*drBase.Finding {
+	var findings []*drBase.Finding
+	seen := make(map[*yaml.Node]bool)
+	for _, s := range w.Schemas {
+		d := s.Discriminator
+		if d == nil || d.Value == nil || seen[d.GetValueNode()] {
+			continue
+		}
+		seen[d.GetValueNode()] = true
+		findings = append(findings, validateDiscriminator(s, d)...)
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findingLine(findings[i]) < findingLine(findings[j])
+	})
+	return findings
+}
+
+func validateDiscriminator(s *drBase.Schema, d *drBase.Discriminator) []*drBase.Finding {
+	var findings []*drBase.Finding
+	property := d.Value.PropertyName
+	checked := make(map[*yaml.Node]bool)
+	checkProperty := func(proxy *base.SchemaProxy, object drBase.Foundational, node *yaml.Node, name string) {
+		if proxy == nil || proxy.Schema() == nil || property == "" {
+			return
+		}
+		root := proxy.Schema().GoLow().RootNode
+		if checked[root] {
+			return
+		}
+		checked[root] = true
+		if !hasProperty(proxy.Schema(), property, make(map[*base.Schema]bool)) {
+			f := drBase.NewFinding(FindingDiscriminatorProperty, drBase.SeverityError,
+				fmt.Sprintf("%s does not define the discriminator property '%s'", name, property), object)
+			if node != nil {
+				f.Node = node
+			}
+			findings = append(findings, f)
+		}
+	}
+
+	mappings := d.ResolveMappings()
+	mapped := make(map[*yaml.Node]bool)
+	for _, m := range mappings {
+		if m.Schema == nil || m.Schema.Value == nil || m.Schema.Value.Schema() == nil {
+			f := drBase.NewFinding(FindingDiscriminatorMapping, drBase.SeverityError,
+				fmt.Sprintf("discriminator maps '%s' to '%s', which does not exist", m.Value, m.Target), d)
+			if m.Node != nil {
+				f.Node = m.Node
+			}
+			findings = append(findings, f)
+			continue
+		}
+		mapped[m.Schema.Value.Schema().GoLow().RootNode] = true
+		checkProperty(m.Schema.Value, m.Schema, nil, fmt.Sprintf("schema '%s', mapped from '%s',", m.Target, m.Value))
+	}
+
+	var members []*drBase.SchemaProxy
+	members = append(members, s.OneOf...)
+	members = append(members, s.AnyOf...)
+	for _, member := range members {
+		if member == nil || member.Value == nil {
+			continue
+		}
+		name := fmt.Sprintf("%s member", member.PathSegment)
+		if member.Value.IsReference() {
+			name = fmt.Sprintf("%s member '%s'", member.PathSegment, member.Value.GetReference())
+		}
+		// members share the node of their keyword, the node of the member itself is the node of the finding.
+		node := member.Value.GetValueNode()
+		if member.Value.IsReference() {
+			node = member.Value.GetReferenceNode()
+		}
+		checkProperty(member.Value, member, node, name)
+
+		covered := false
+		switch {
+		case !member.Value.IsReference():
+		case len(mappings) == 0:
+			// references are mapped implicitly by the name of their schema.
+			covered = true
+		case member.Value.Schema() != nil:
+			covered = mapped[member.Value.Schema().GoLow().RootNode]
+		}
+		if !covered {
+			message := fmt.Sprintf("%s is not covered by a discriminator mapping", name)
+			if !member.Value.IsReference() {
+				message = fmt.Sprintf("%s is an inline schema, which can't be mapped by a discriminator", name)
+			}
+			f := drBase.NewFinding(FindingDiscriminatorCoverage, drBase.SeverityWarn, message, member)
+			if node != nil {
+				f.Node = node
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// hasProperty returns true if a schema, or any of its allOf schemas, defines a property.
+func hasProperty(schema *base.Schema, name string, visited map[*base.Schema]bool) bool {
+	if schema == nil || visited[schema] {
+		return false
+	}
+	visited[schema] = true
+	if schema.Properties != nil {
+		if _, ok := schema.Properties.Get(name); ok {
+			return true
+		}
+	}
+	for _, all := range schema.AllOf {
+		if all != nil && hasProperty(all.Schema(), name, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+func findingLine(f *drBase.Finding) int {
+	if f.Node == nil {
+		return 0
+	}
+	return f.Node.Line
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"fmt"
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWalker_WalkV3_ValidateDiscriminators(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: pets
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Pet:
+      oneOf:
+        - $ref: '#/components/schemas/Cat'
+        - $ref: '#/components/schemas/Dog'
+        - $ref: '#/components/schemas/Lizard'
+        - type: object
+          properties:
+            petType:
+              type: string
+      discriminator:
+        propertyName: petType
+        mapping:
+          cat: Cat
+          dog: '#/components/schemas/Dog'
+          fish: Fish
+    Named:
+      type: object
+      properties:
+        petType:
+          type: string
+    Cat:
+      allOf:
+        - $ref: '#/components/schemas/Named'
+    Dog:
+      type: object
+      properties:
+        petType:
+          type: string
+    Lizard:
+      type: object
+      properties:
+        scales:
+          type: integer`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+	drDoc := NewDrDocument(v3Doc)
+	pet := drDoc.V3Document.Components.Schemas.GetOrZero("Pet").Schema
+
+	mappings := pet.Discriminator.ResolveMappings()
+	require.Len(t, mappings, 3)
+	assert.Equal(t, "#/components/schemas/Cat", mappings[0].Reference)
+	require.NotNil(t, mappings[0].Schema)
+	assert.Equal(t, "$.components.schemas['Cat']", mappings[0].Schema.GenerateJSONPath())
+	require.NotNil(t, mappings[1].Schema)
+	assert.Equal(t, "$.components.schemas['Dog']", mappings[1].Schema.GenerateJSONPath())
+	assert.Nil(t, mappings[2].Schema)
+	assert.Equal(t, 22, mappings[2].Node.Line)
+
+	findings := drDoc.ValidateDiscriminators()
+	var reported []string
+	for _, f := range findings {
+		reported = append(reported, fmt.Sprintf("%s:%d", f.Id, f.Node.Line))
+	}
+	assert.Equal(t, []string{
+		"discriminator-property:12", "discriminator-coverage:12", "discriminator-coverage:13",
+		"discriminator-mapping:22",
+	}, reported)
+	assert.Contains(t, findings[0].Message, "'#/components/schemas/Lizard' does not define the discriminator property 'petType'")
+	assert.Contains(t, findings[2].Message, "inline schema")
+}
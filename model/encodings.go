@@ -0,0 +1,38 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"fmt"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"sort"
+)
+
+const (
+	FindingEncodingProperty = "encoding-property"
+)
+
+// ValidateEncodings checks the encodings of every multipart and form media type in the document are for
+// properties defined by the schema of the media type. Media types without a schema are not checked. Findings point
+// to the encodings, and are ordered by their path.
+func (w *DrDocument) ValidateEncodings() []*drBase.Finding {
+	var findings []*drBase.Finding
+	for _, mt := range w.MediaTypes {
+		if mt.Value == nil || mt.Value.Schema == nil {
+			continue
+		}
+		for _, pe := range mt.PropertyEncodings() {
+			if pe.Schema == nil && pe.Encoding != nil {
+				findings = append(findings, drBase.NewFinding(FindingEncodingProperty, drBase.SeverityWarn,
+					fmt.Sprintf("encoding '%s' is for a property that is not defined by the schema of '%s'",
+						pe.Property, mt.Key), pe.Encoding))
+			}
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Path < findings[j].Path
+	})
+	return findings
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWalker_WalkV3_ValidateEncodings(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: burgers
+  version: 1.0.0
+paths:
+  /burgers:
+    post:
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              allOf:
+                - $ref: '#/components/schemas/Burger'
+                - type: object
+                  properties:
+                    photo:
+                      type: string
+                      format: binary
+            encoding:
+              photo:
+                contentType: image/png
+                headers:
+                  X-Rate-Limit:
+                    schema:
+                      type: integer
+              sauce:
+                contentType: text/plain
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Burger'
+      responses:
+        "200":
+          description: ok
+components:
+  schemas:
+    Burger:
+      type: object
+      properties:
+        name:
+          type: string
+        toppings:
+          type: array
+          items:
+            type: object
+        calories:
+          type: integer`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+	drDoc := NewDrDocument(v3Doc)
+	content := drDoc.V3Document.Paths.PathItems.GetOrZero("/burgers").Post.RequestBody.Content
+
+	assert.Nil(t, content.GetOrZero("application/json").PropertyEncodings())
+	encodings := content.GetOrZero("multipart/form-data").PropertyEncodings()
+	require.Len(t, encodings, 5)
+	var properties, contentTypes []string
+	for _, pe := range encodings {
+		properties = append(properties, pe.Property)
+		contentTypes = append(contentTypes, pe.ContentType)
+	}
+	assert.Equal(t, []string{"name", "toppings", "calories", "photo", "sauce"}, properties)
+	assert.Equal(t, []string{"text/plain", "application/json", "text/plain", "image/png", "text/plain"}, contentTypes)
+	assert.Nil(t, encodings[0].Encoding)
+	require.NotNil(t, encodings[3].Headers)
+	assert.Equal(t, "$.paths['/burgers'].post.requestBody.content['multipart/form-data'].encoding['photo'].headers['X-Rate-Limit']",
+		encodings[3].Headers.GetOrZero("X-Rate-Limit").GenerateJSONPath())
+	assert.Nil(t, encodings[4].Schema)
+
+	findings := drDoc.ValidateEncodings()
+	require.Len(t, findings, 1)
+	assert.Equal(t, FindingEncodingProperty, findings[0].Id)
+	assert.Equal(t, "$.paths['/burgers'].post.requestBody.content['multipart/form-data'].encoding['sauce']", findings[0].Path)
+}
@@ -0,0 +1,163 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"fmt"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"gopkg.in/yaml.v3"
+	"slices"
+)
+
+// EnumDefinition is an enum (or const) declared in a schema.
+type EnumDefinition struct {
+	// Const is true when the values come from a `const` keyword rather than `enum`.
+	Const  bool
+	Values []any
+
+	// JSONPath is the path of the schema that owns the enum, component paths are used when the schema
+	// is a component (or inside one).
+	JSONPath string
+	Line     int
+	Column   int
+	Schema   *base.Schema
+
+	// Usages contains every JSONPath the enum is reachable through from an operation, and Operations the
+	// operations that reach it.
+	Usages     []string
+	Operations []*PathOperation
+}
+
+// Enums returns every enum and const declared in a schema that is a component, or is reachable from
+// an operation.
+func (w *DrDocument) Enums() []*EnumDefinition {
+	var enums []*EnumDefinition
+	defs := make(map[*yaml.Node][]*EnumDefinition)
+	record := func(v *visitedSchema, po *PathOperation) {
+		if len(v.Schema.Enum) == 0 && v.Schema.Const == nil {
+			return
+		}
+		l := v.Schema.GoLow()
+		if l == nil || l.RootNode == nil {
+			return
+		}
+		found, ok := defs[l.RootNode]
+		if !ok {
+			if len(v.Schema.Enum) > 0 {
+				found = append(found, newEnumDefinition(v, false, v.Schema.Enum))
+			}
+			if v.Schema.Const != nil {
+				found = append(found, newEnumDefinition(v, true, []*yaml.Node{v.Schema.Const}))
+			}
+			defs[l.RootNode] = found
+			enums = append(enums, found...)
+		}
+		if po != nil {
+			for _, e := range found {
+				e.Usages = append(e.Usages, v.JSONPath)
+				if !slices.Contains(e.Operations, po) {
+					e.Operations = append(e.Operations, po)
+				}
+			}
+		}
+	}
+
+	if w.V3Document != nil && w.V3Document.Components != nil && w.V3Document.Components.Schemas != nil {
+		for _, sp := range w.V3Document.Components.Schemas.FromOldest() {
+			visitSchemaProxy(sp.Value, sp.GenerateJSONPath(), "", "", make(map[*base.Schema]bool),
+				func(v *visitedSchema) { record(v, nil) })
+		}
+	}
+	for _, po := range w.Operations() {
+		visitOperationSchemas(po, func(v *visitedSchema) { record(v, po) })
+	}
+	return enums
+}
+
+func newEnumDefinition(v *visitedSchema, isConst bool, nodes []*yaml.Node) *EnumDefinition {
+	e := &EnumDefinition{
+		Const:    isConst,
+		JSONPath: v.JSONPath,
+		Schema:   v.Schema,
+	}
+	for _, n := range nodes {
+		var val any
+		if n.Decode(&val) == nil {
+			e.Values = append(e.Values, val)
+		}
+	}
+	if l := v.Schema.GoLow(); l != nil && l.RootNode != nil {
+		e.Line, e.Column = l.RootNode.Line, l.RootNode.Column
+	}
+	return e
+}
+
+// EnumChange describes values added to, or removed from an enum between two versions of a document.
+type EnumChange struct {
+	JSONPath string
+	Const    bool
+	Added    []any
+	Removed  []any
+
+	// New is true when the whole enum was added, Deleted is true when the whole enum was removed.
+	New     bool
+	Deleted bool
+}
+
+// CompareEnums compares the enums of two documents, matching enums by the JSONPath of the schema that owns them.
+// Only enums with changed values are returned.
+func CompareEnums(previous, current *DrDocument) []*EnumChange {
+	key := func(e *EnumDefinition) string { return fmt.Sprintf("%t:%s", e.Const, e.JSONPath) }
+	old := make(map[string]*EnumDefinition)
+	var oldEnums []*EnumDefinition
+	if previous != nil {
+		oldEnums = previous.Enums()
+	}
+	for _, e := range oldEnums {
+		old[key(e)] = e
+	}
+	var changes []*EnumChange
+	seen := make(map[string]bool)
+	if current != nil {
+		for _, e := range current.Enums() {
+			seen[key(e)] = true
+			p, ok := old[key(e)]
+			if !ok {
+				changes = append(changes, &EnumChange{JSONPath: e.JSONPath, Const: e.Const, Added: e.Values, New: true})
+				continue
+			}
+			c := &EnumChange{JSONPath: e.JSONPath, Const: e.Const}
+			c.Added = diffValues(e.Values, p.Values)
+			c.Removed = diffValues(p.Values, e.Values)
+			if len(c.Added) > 0 || len(c.Removed) > 0 {
+				changes = append(changes, c)
+			}
+		}
+	}
+	for _, e := range oldEnums {
+		if !seen[key(e)] {
+			changes = append(changes, &EnumChange{JSONPath: e.JSONPath, Const: e.Const, Removed: e.Values, Deleted: true})
+		}
+	}
+	return changes
+}
+
+// diffValues returns the values in a that are not in b.
+func diffValues(a, b []any) []any {
+	var diff []any
+	for _, v := range a {
+		found := false
+		for _, o := range b {
+			if fmt.Sprint(v) == fmt.Sprint(o) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			diff = append(diff, v)
+		}
+	}
+	return diff
+}
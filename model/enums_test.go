@@ -0,0 +1,65 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestWalker_WalkV3_Enums(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /pizza:
+    get:
+      parameters:
+        - in: query
+          name: size
+          schema:
+            type: string
+            enum: [small, large]
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pizza'
+components:
+  schemas:
+    Pizza:
+      type: object
+      properties:
+        crust:
+          type: string
+          enum: [thin, thick]
+        kind:
+          const: pizza`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocument(v3Doc)
+
+	enums := walker.Enums()
+	require.Len(t, enums, 3)
+	assert.Equal(t, "$.components.schemas['Pizza'].properties['crust']", enums[0].JSONPath)
+	assert.Equal(t, []any{"thin", "thick"}, enums[0].Values)
+	assert.Equal(t, []string{"$.paths['/pizza'].get.responses['200'].content['application/json'].schema.properties['crust']"},
+		enums[0].Usages)
+	assert.Len(t, enums[0].Operations, 1)
+	assert.True(t, enums[1].Const)
+	assert.Equal(t, "$.paths['/pizza'].get.parameters[0].schema", enums[2].JSONPath)
+
+	changed := strings.Replace(spec, "enum: [thin, thick]", "enum: [thin, stuffed]", 1)
+	newDoc, _ = libopenapi.NewDocument([]byte(changed))
+	v3Doc, _ = newDoc.BuildV3Model()
+	changes := CompareEnums(walker, NewDrDocument(v3Doc))
+	require.Len(t, changes, 1)
+	assert.Equal(t, []any{"stuffed"}, changes[0].Added)
+	assert.Equal(t, []any{"thick"}, changes[0].Removed)
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/datamodel/high"
+	"github.com/pb33f/libopenapi/datamodel/low"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+	"reflect"
+	"sort"
+)
+
+// Extension is a single `x-` extension found in the document.
+type Extension struct {
+	Name     string
+	Value    *yaml.Node
+	JSONPath string
+	Owner    drBase.Foundational
+	Line     int
+	Column   int
+}
+
+// Extensions collects every `x-` extension from every walked object in the document, along with the object
+// that owns it. Extensions are returned in the order they appear in the document.
+func (w *DrDocument) Extensions() []*Extension {
+	if w == nil {
+		return nil
+	}
+	var extensions []*Extension
+	seen := make(map[any]bool)
+	for _, objects := range w.lineIndex() {
+		for _, obj := range objects {
+			if seen[obj] {
+				continue
+			}
+			seen[obj] = true
+			f, ok := obj.(drBase.Foundational)
+			if !ok {
+				continue
+			}
+			hv, ok := obj.(HasValue)
+			if !ok {
+				continue
+			}
+			ext := extractExtensions(hv.GetValue())
+			if ext == nil {
+				continue
+			}
+			keys := extractExtensionKeys(hv.GetValue())
+			for name, node := range ext.FromOldest() {
+				e := &Extension{
+					Name:     name,
+					Value:    node,
+					JSONPath: f.GenerateJSONPath(),
+					Owner:    f,
+				}
+				if k := keys[name]; k != nil {
+					e.Line, e.Column = k.Line, k.Column
+				} else if node != nil {
+					e.Line, e.Column = node.Line, node.Column
+				}
+				extensions = append(extensions, e)
+			}
+		}
+	}
+	sort.SliceStable(extensions, func(i, j int) bool {
+		if extensions[i].Line != extensions[j].Line {
+			return extensions[i].Line < extensions[j].Line
+		}
+		if extensions[i].JSONPath != extensions[j].JSONPath {
+			return extensions[i].JSONPath < extensions[j].JSONPath
+		}
+		return extensions[i].Name < extensions[j].Name
+	})
+	return extensions
+}
+
+// extractExtensionKeys pulls the extension key nodes out of the low-level model behind a high-level model.
+func extractExtensionKeys(value any) map[string]*yaml.Node {
+	gl, ok := value.(high.GoesLowUntyped)
+	if !ok {
+		return nil
+	}
+	ext, ok := extractField(gl.GoLowUntyped()).(*orderedmap.Map[low.KeyReference[string], low.ValueReference[*yaml.Node]])
+	if !ok || ext == nil {
+		return nil
+	}
+	keys := make(map[string]*yaml.Node)
+	for k := range ext.KeysFromOldest() {
+		keys[k.Value] = k.KeyNode
+	}
+	return keys
+}
+
+// extractExtensions pulls the Extensions map out of a high-level libopenapi model, if it has one.
+func extractExtensions(value any) *orderedmap.Map[string, *yaml.Node] {
+	ext, _ := extractField(value).(*orderedmap.Map[string, *yaml.Node])
+	return ext
+}
+
+func extractField(value any) any {
+	if value == nil {
+		return nil
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	field := v.Elem().FieldByName("Extensions")
+	if !field.IsValid() || field.Kind() != reflect.Ptr || field.IsNil() {
+		return nil
+	}
+	return field.Interface()
+}
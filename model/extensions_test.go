@@ -0,0 +1,38 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestWalker_WalkV3_Extensions(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ := libopenapi.NewDocument(bytes)
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocument(v3Doc)
+
+	extensions := walker.Extensions()
+	assert.Len(t, extensions, 17)
+
+	assert.Equal(t, "x-internal-ting", extensions[0].Name)
+	assert.Equal(t, "$.tags[0]", extensions[0].JSONPath)
+	assert.Equal(t, 25, extensions[0].Line)
+	assert.Equal(t, "somethingSpecial", extensions[0].Value.Value)
+
+	last := extensions[len(extensions)-1]
+	assert.Equal(t, "x-something-something", last.Name)
+	assert.Equal(t, "$", last.JSONPath)
+	assert.Equal(t, "darkside", last.Value.Value)
+
+	var names []string
+	for _, e := range extensions {
+		names = append(names, e.Name)
+	}
+	assert.Contains(t, names, "x-milky-milk")
+	assert.Contains(t, names, "x-nice")
+}
@@ -0,0 +1,104 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ExternalSource is a file, or URL, that was loaded into the rolodex to resolve references.
+type ExternalSource struct {
+	// Location is the absolute path of a local file, or the URL of a remote one.
+	Location string `json:"location" yaml:"location"`
+	Remote   bool   `json:"remote" yaml:"remote"`
+
+	// Size is the size of the source in bytes.
+	Size int64 `json:"size" yaml:"size"`
+
+	// References is the number of references (in any file) that point into the source, ReferencedBy is the
+	// location of every file that makes at least one of them, sorted.
+	References   int      `json:"references" yaml:"references"`
+	ReferencedBy []string `json:"referencedBy,omitempty" yaml:"referencedBy,omitempty"`
+}
+
+// ExternalSourceReport is every external source a specification loads, for reviewing what a specification
+// actually pulls in (and from where) before trusting it.
+type ExternalSourceReport struct {
+	Sources   []*ExternalSource `json:"sources" yaml:"sources"`
+	TotalSize int64             `json:"totalSize" yaml:"totalSize"`
+	Local     int               `json:"local" yaml:"local"`
+	Remote    int               `json:"remote" yaml:"remote"`
+}
+
+// ExternalSources lists every file and URL the rolodex loaded to resolve the references of the document, other
+// than the root document itself. Sources are sorted by location.
+func (w *DrDocument) ExternalSources() *ExternalSourceReport {
+	report := &ExternalSourceReport{}
+	if w == nil || w.index == nil || w.index.GetRolodex() == nil {
+		return report
+	}
+	rolodex := w.index.GetRolodex()
+	rootLocation := w.index.GetSpecAbsolutePath()
+	sources := make(map[string]*ExternalSource)
+	for _, idx := range w.allIndexes() {
+		if idx == nil {
+			continue
+		}
+		location := idx.GetSpecAbsolutePath()
+		if idx == w.index || location == "" || location == rootLocation || sources[location] != nil {
+			continue
+		}
+		source := &ExternalSource{Location: location, Remote: strings.HasPrefix(location, "http")}
+		if rf, err := rolodex.Open(location); err == nil && rf != nil {
+			source.Size = rf.Size()
+			if source.Size <= 0 {
+				source.Size = int64(len(rf.GetContent()))
+			}
+		}
+		sources[location] = source
+		report.Sources = append(report.Sources, source)
+	}
+
+	// count the references into each source, from every file.
+	for _, idx := range w.allIndexes() {
+		if idx == nil {
+			continue
+		}
+		seen := make(map[*ExternalSource]bool)
+		for _, ref := range findRefs(idx.GetRootNode()) {
+			location, _, _ := strings.Cut(ref.Value, "#")
+			if location == "" {
+				continue
+			}
+			source := sources[referenceLocation(idx, location)]
+			if source == nil {
+				continue
+			}
+			source.References++
+			if referrer := idx.GetSpecAbsolutePath(); !seen[source] && referrer != "" {
+				seen[source] = true
+				if !slices.Contains(source.ReferencedBy, referrer) {
+					source.ReferencedBy = append(source.ReferencedBy, referrer)
+				}
+			}
+		}
+	}
+
+	sort.Slice(report.Sources, func(i, j int) bool {
+		return report.Sources[i].Location < report.Sources[j].Location
+	})
+	for _, source := range report.Sources {
+		sort.Strings(source.ReferencedBy)
+		report.TotalSize += source.Size
+		if source.Remote {
+			report.Remote++
+		} else {
+			report.Local++
+		}
+	}
+	return report
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWalker_WalkV3_ExternalSources(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/test-relative/spec.yaml")
+	newDoc, _ := libopenapi.NewDocumentWithConfiguration(bytes, &datamodel.DocumentConfiguration{
+		BasePath:            "../test_specs/test-relative",
+		SpecFilePath:        "test_specs/test-relative/spec.yaml",
+		AllowFileReferences: true,
+	})
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocument(v3Doc)
+
+	report := walker.ExternalSources()
+	root, _ := filepath.Abs("../test_specs/test-relative")
+	summary := make(map[string]int)
+	for _, s := range report.Sources {
+		rel, _ := filepath.Rel(root, s.Location)
+		summary[rel] = s.References
+		if rel == "colors/schemas.yaml" {
+			assert.Len(t, s.ReferencedBy, 3)
+		}
+		assert.False(t, s.Remote)
+		assert.Greater(t, s.Size, int64(0))
+	}
+	assert.Equal(t, map[string]int{
+		"all_shared.yaml":             1,
+		"lemons/schemas.yaml":         2,
+		"colors/schemas.yaml":         3,
+		"oranges/schemas.yaml":        1,
+		"oranges/subtypes/types.yaml": 1,
+	}, summary)
+	assert.Equal(t, len(report.Sources), report.Local)
+	assert.Zero(t, report.Remote)
+
+	var total int64
+	for _, s := range report.Sources {
+		total += s.Size
+	}
+	assert.Equal(t, total, report.TotalSize)
+}
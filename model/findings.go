@@ -0,0 +1,70 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	drBase "github.com/pb33f/doctor/model/high/base"
+)
+
+// AttachFindings attaches findings reported by other tools (for example vacuum rule results, see
+// drBase.NewRuleFinding) to the objects they were found on, so they can be read with GetFindings and rendered with
+// the model. A finding is attached to the object with its JSONPath, or when there is none, to the closest object
+// on the line of its node. The object, path and node of each attached finding are set. Findings that could not be
+// attached to any object are returned.
+func (w *DrDocument) AttachFindings(findings []*drBase.Finding) []*drBase.Finding {
+	if w == nil || w.V3Document == nil {
+		return findings
+	}
+	var byPath map[string]drBase.Foundational
+	var unattached []*drBase.Finding
+	for _, f := range findings {
+		if f == nil {
+			continue
+		}
+		var found drBase.Foundational
+		if f.Path != "" {
+			if byPath == nil {
+				byPath = w.objectPaths()
+			}
+			found = byPath[f.Path]
+		}
+		if found == nil && f.Node != nil && f.Node.Line > 0 {
+			if models, err := w.LocateModelByLine(f.Node.Line); err == nil && len(models) > 0 {
+				// models are ordered by line, the last is the closest to the node.
+				found = models[len(models)-1]
+			}
+		}
+		hf, ok := found.(drBase.HasFindings)
+		if !ok {
+			unattached = append(unattached, f)
+			continue
+		}
+		f.Object = found
+		f.Path = found.GenerateJSONPath()
+		if f.Node == nil {
+			f.Node = found.GetKeyNode()
+		}
+		hf.AddFinding(f)
+	}
+	return unattached
+}
+
+// objectPaths indexes every walked object by its JSONPath, the first object with a path wins.
+func (w *DrDocument) objectPaths() map[string]drBase.Foundational {
+	paths := make(map[string]drBase.Foundational)
+	var index func(f drBase.Foundational)
+	index = func(f drBase.Foundational) {
+		p := f.GenerateJSONPath()
+		if _, ok := paths[p]; ok {
+			return
+		}
+		paths[p] = f
+		for _, child := range drBase.ChildrenOf(f) {
+			index(child)
+		}
+	}
+	index(w.V3Document)
+	return paths
+}
@@ -0,0 +1,38 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	"os"
+	"testing"
+)
+
+func TestWalker_WalkV3_AttachFindings(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ := libopenapi.NewDocument(bytes)
+	v3Doc, _ := newDoc.BuildV3Model()
+	drDoc := NewDrDocument(v3Doc)
+
+	byPath := &base.Finding{Id: "burger-description", Severity: base.SeverityWarn, Message: "describe the burger",
+		Path: "$.components.schemas['Burger']"}
+	byLine := base.NewRuleFinding(&base.RuleFunctionResult{RuleId: "burger-line", RuleSeverity: base.SeverityInfo,
+		Message: "on a line", StartNode: &yaml.Node{Line: 442}})
+	missing := &base.Finding{Id: "missing", Path: "$.components.schemas['Pizza']"}
+	unattached := drDoc.AttachFindings([]*base.Finding{byPath, byLine, missing, nil})
+	assert.Equal(t, []*base.Finding{missing}, unattached)
+
+	burger := drDoc.V3Document.Components.Schemas.GetOrZero("Burger")
+	require.NotNil(t, byPath.Object)
+	assert.Equal(t, "$.components.schemas['Burger']", byPath.Object.GenerateJSONPath())
+	assert.NotNil(t, byPath.Node)
+	assert.Equal(t, "$.components.schemas['Burger']", byLine.Path)
+	assert.Contains(t, base.FindingsOf(byLine.Object), byLine)
+	assert.Contains(t, burger.GetFindings(), byPath)
+	assert.Nil(t, (*DrDocument)(nil).AttachFindings(nil))
+}
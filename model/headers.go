@@ -0,0 +1,207 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"fmt"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	drV3 "github.com/pb33f/doctor/model/high/v3"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"strings"
+)
+
+const (
+	FindingHeaderConflict = "header-conflict"
+	FindingHeaderMissing  = "header-missing"
+)
+
+const (
+	HeaderRequest  = "request"
+	HeaderResponse = "response"
+)
+
+// HeaderProfile is the set of standard headers operations are expected to declare.
+type HeaderProfile struct {
+	// RequestHeaders are header parameters every operation should accept.
+	RequestHeaders []string
+
+	// ResponseHeaders maps a response code (`201`), a response class (`2xx`) or `*` (every response) to the
+	// headers those responses should declare.
+	ResponseHeaders map[string][]string
+}
+
+// DefaultHeaderProfile expects `Location` on created and redirect responses, and `Retry-After` on rate
+// limited and unavailable responses.
+var DefaultHeaderProfile = &HeaderProfile{
+	ResponseHeaders: map[string][]string{
+		"201": {"Location"},
+		"3xx": {"Location"},
+		"429": {"Retry-After"},
+		"503": {"Retry-After"},
+	},
+}
+
+// HeaderDeclaration is a single header declared by an operation, either as a header parameter (request) or
+// as a response header. Code is the response code and is empty for request headers.
+type HeaderDeclaration struct {
+	Name      string
+	Operation *PathOperation
+	Code      string
+
+	// Parameter is set for request headers, Header is set for response headers.
+	Parameter *drV3.Parameter
+	Header    *drV3.Header
+	Schema    *base.Schema
+}
+
+// Object returns the doctor model for the declaration.
+func (d *HeaderDeclaration) Object() drBase.Foundational {
+	if d.Parameter != nil {
+		return d.Parameter
+	}
+	return d.Header
+}
+
+// HeaderGroup is every declaration of a header with the same (case-insensitive) name, in the same direction.
+type HeaderGroup struct {
+	Name         string
+	In           string
+	Declarations []*HeaderDeclaration
+
+	// Conflicting is true when declarations disagree on the schema of the header.
+	Conflicting bool
+}
+
+// HeaderInventory is every request and response header declared across a document.
+type HeaderInventory struct {
+	Headers  []*HeaderGroup
+	Findings []*drBase.Finding
+}
+
+// HeaderInventory lists every request header parameter and response header declared across all operations,
+// grouped by name. Groups where declarations have different schemas are flagged as conflicting, and operations
+// that do not declare the headers expected by the profile are reported as missing. A nil profile uses
+// DefaultHeaderProfile.
+func (w *DrDocument) HeaderInventory(profile *HeaderProfile) *HeaderInventory {
+	if profile == nil {
+		profile = DefaultHeaderProfile
+	}
+	inventory := &HeaderInventory{}
+	groups := make(map[string]*HeaderGroup)
+	add := func(in string, decl *HeaderDeclaration) {
+		key := in + ":" + strings.ToLower(decl.Name)
+		g, ok := groups[key]
+		if !ok {
+			g = &HeaderGroup{Name: decl.Name, In: in}
+			groups[key] = g
+			inventory.Headers = append(inventory.Headers, g)
+		}
+		g.Declarations = append(g.Declarations, decl)
+	}
+
+	for _, po := range w.Operations() {
+		declared := make(map[string]bool)
+		for _, p := range po.Parameters() {
+			if p.Value.In != "header" {
+				continue
+			}
+			add(HeaderRequest, &HeaderDeclaration{Name: p.Value.Name, Operation: po, Parameter: p,
+				Schema: schemaOf(p.Value.Schema)})
+			declared[strings.ToLower(p.Value.Name)] = true
+		}
+		for _, name := range profile.RequestHeaders {
+			if !declared[strings.ToLower(name)] {
+				inventory.Findings = append(inventory.Findings, drBase.NewFinding(FindingHeaderMissing, drBase.SeverityWarn,
+					fmt.Sprintf("`%s %s` does not accept the '%s' request header",
+						strings.ToUpper(po.Method), po.Path, name), po.Operation))
+			}
+		}
+
+		for _, cr := range operationResponses(po) {
+			code, r := cr.code, cr.response
+			responseDeclared := make(map[string]bool)
+			if r.Headers != nil {
+				for name, h := range r.Headers.FromOldest() {
+					add(HeaderResponse, &HeaderDeclaration{Name: name, Operation: po, Code: code, Header: h,
+						Schema: schemaOf(h.Value.Schema)})
+					responseDeclared[strings.ToLower(name)] = true
+				}
+			}
+			for _, name := range expectedResponseHeaders(profile, code) {
+				if !responseDeclared[strings.ToLower(name)] {
+					inventory.Findings = append(inventory.Findings, drBase.NewFinding(FindingHeaderMissing, drBase.SeverityWarn,
+						fmt.Sprintf("'%s' response for `%s %s` does not declare the '%s' header",
+							code, strings.ToUpper(po.Method), po.Path, name), r))
+				}
+			}
+		}
+	}
+
+	for _, g := range inventory.Headers {
+		var first *HeaderDeclaration
+		for _, d := range g.Declarations {
+			if first == nil {
+				first = d
+				continue
+			}
+			if schemaHash(d.Schema) == schemaHash(first.Schema) {
+				continue
+			}
+			g.Conflicting = true
+			inventory.Findings = append(inventory.Findings, drBase.NewFinding(FindingHeaderConflict, drBase.SeverityWarn,
+				fmt.Sprintf("%s header '%s' in `%s %s` has a different schema to `%s %s`", g.In, d.Name,
+					strings.ToUpper(d.Operation.Method), d.Operation.Path,
+					strings.ToUpper(first.Operation.Method), first.Operation.Path), d.Object()))
+		}
+	}
+	return inventory
+}
+
+type codedResponse struct {
+	code     string
+	response *drV3.Response
+}
+
+// operationResponses returns every response of an operation in the order they are declared, `default` last.
+func operationResponses(po *PathOperation) []*codedResponse {
+	var responses []*codedResponse
+	if po.Operation.Responses == nil {
+		return responses
+	}
+	if po.Operation.Responses.Codes != nil {
+		for code, r := range po.Operation.Responses.Codes.FromOldest() {
+			responses = append(responses, &codedResponse{code: code, response: r})
+		}
+	}
+	if po.Operation.Responses.Default != nil {
+		responses = append(responses, &codedResponse{code: "default", response: po.Operation.Responses.Default})
+	}
+	return responses
+}
+
+func expectedResponseHeaders(profile *HeaderProfile, code string) []string {
+	var names []string
+	names = append(names, profile.ResponseHeaders["*"]...)
+	names = append(names, profile.ResponseHeaders[code]...)
+	if len(code) == 3 {
+		names = append(names, profile.ResponseHeaders[code[:1]+"xx"]...)
+		names = append(names, profile.ResponseHeaders[code[:1]+"XX"]...)
+	}
+	return names
+}
+
+func schemaOf(proxy *base.SchemaProxy) *base.Schema {
+	if proxy == nil {
+		return nil
+	}
+	return proxy.Schema()
+}
+
+func schemaHash(schema *base.Schema) [32]byte {
+	if schema == nil || schema.GoLow() == nil {
+		return [32]byte{}
+	}
+	return schema.GoLow().Hash()
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWalker_WalkV3_HeaderInventory(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: headers
+  version: 1
+paths:
+  /burgers:
+    parameters:
+      - name: X-Request-Id
+        in: header
+        schema:
+          type: string
+    post:
+      responses:
+        "201":
+          description: created
+          headers:
+            X-Rate-Limit:
+              schema:
+                type: integer
+        "429":
+          description: slow down
+          headers:
+            Retry-After:
+              schema:
+                type: integer
+  /fries:
+    get:
+      parameters:
+        - name: x-request-id
+          in: header
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: ok
+          headers:
+            X-Rate-Limit:
+              schema:
+                type: integer`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocument(v3Doc)
+
+	inventory := walker.HeaderInventory(nil)
+	assert.Len(t, inventory.Headers, 3)
+
+	requestId := inventory.Headers[0]
+	assert.Equal(t, "X-Request-Id", requestId.Name)
+	assert.Equal(t, HeaderRequest, requestId.In)
+	assert.Len(t, requestId.Declarations, 2)
+	assert.True(t, requestId.Conflicting)
+	assert.NotNil(t, requestId.Declarations[0].Parameter)
+
+	rateLimit := inventory.Headers[1]
+	assert.Equal(t, "X-Rate-Limit", rateLimit.Name)
+	assert.Equal(t, HeaderResponse, rateLimit.In)
+	assert.Len(t, rateLimit.Declarations, 2)
+	assert.False(t, rateLimit.Conflicting)
+	assert.Equal(t, "201", rateLimit.Declarations[0].Code)
+	assert.NotNil(t, rateLimit.Declarations[0].Header)
+
+	assert.Len(t, inventory.Findings, 2)
+	assert.Equal(t, FindingHeaderMissing, inventory.Findings[0].Id)
+	assert.Equal(t, "'201' response for `POST /burgers` does not declare the 'Location' header",
+		inventory.Findings[0].Message)
+	assert.Equal(t, FindingHeaderConflict, inventory.Findings[1].Id)
+	assert.Equal(t, "request header 'x-request-id' in `GET /fries` has a different schema to `POST /burgers`",
+		inventory.Findings[1].Message)
+
+	inventory = walker.HeaderInventory(&HeaderProfile{
+		RequestHeaders:  []string{"X-Request-Id"},
+		ResponseHeaders: map[string][]string{"*": {"X-Rate-Limit"}},
+	})
+	var missing []string
+	for _, f := range inventory.Findings {
+		if f.Id == FindingHeaderMissing {
+			missing = append(missing, f.Message)
+		}
+	}
+	assert.Equal(t, []string{"'429' response for `POST /burgers` does not declare the 'X-Rate-Limit' header"}, missing)
+}
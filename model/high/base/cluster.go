@@ -0,0 +1,48 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+import "strings"
+
+// Clusters are the regions of a document graph nodes are grouped by, so front-ends can render swim-lanes or
+// collapse groups of nodes. Components are clustered by their kind, as `components.` followed by the kind (like
+// ClusterSchemas).
+const (
+	ClusterDocument     = "document"
+	ClusterInfo         = "info"
+	ClusterServers      = "servers"
+	ClusterPaths        = "paths"
+	ClusterWebhooks     = "webhooks"
+	ClusterComponents   = "components"
+	ClusterSchemas      = "components.schemas"
+	ClusterParameters   = "components.parameters"
+	ClusterSecurity     = "security"
+	ClusterTags         = "tags"
+	ClusterExternalDocs = "externalDocs"
+)
+
+// NodeCluster returns the cluster of the object at a JSONPath, the first segment of the path, or the kind of
+// component for an object beneath components. The document itself (`$`) is ClusterDocument.
+func NodeCluster(path string) string {
+	path, ok := strings.CutPrefix(path, "$")
+	if !ok {
+		return ""
+	}
+	if path == "" {
+		return ClusterDocument
+	}
+	segment := func(p string) (string, string) {
+		p = strings.TrimPrefix(p, ".")
+		if i := strings.IndexAny(p, ".["); i >= 0 {
+			return p[:i], p[i:]
+		}
+		return p, ""
+	}
+	first, rest := segment(path)
+	if first != ClusterComponents || !strings.HasPrefix(rest, ".") {
+		return first
+	}
+	kind, _ := segment(rest)
+	return ClusterComponents + "." + kind
+}
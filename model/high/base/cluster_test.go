@@ -0,0 +1,27 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNodeCluster(t *testing.T) {
+	for path, expected := range map[string]string{
+		"$":                                    ClusterDocument,
+		"$.info.contact":                       ClusterInfo,
+		"$.paths['/burgers'].get.responses":    ClusterPaths,
+		"$.webhooks['order']":                  ClusterWebhooks,
+		"$.components":                         ClusterComponents,
+		"$.components.schemas['Burger'].items": ClusterSchemas,
+		"$.components.parameters['Id']":        ClusterParameters,
+		"$.components.securitySchemes['key']":  "components.securitySchemes",
+		"$.servers[0]":                         ClusterServers,
+		"$.tags[1]":                            ClusterTags,
+		"not-a-path":                           "",
+	} {
+		assert.Equal(t, expected, NodeCluster(path), path)
+	}
+}
@@ -16,6 +16,10 @@ type Contact struct {
 }
 
 func (c *Contact) Walk(ctx context.Context, contact *base.Contact) {
+	if ctx.Err() != nil {
+		return
+	}
+
 	drCtx := GetDrContext(ctx)
 	c.Value = contact
 	c.PathSegment = "contact"
@@ -5,22 +5,85 @@ package base
 
 import (
 	"context"
+	"github.com/pb33f/doctor/telemetry"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 	"github.com/pb33f/libopenapi/index"
-	"github.com/sourcegraph/conc"
 	"gopkg.in/yaml.v3"
 	"log/slog"
-	"sync"
+	"strings"
+	"sync/atomic"
 )
 
 const HEIGHT = 25
 const WIDTH = 200
 
+// BuildErrorCategory describes what caused a schema to fail to build.
+type BuildErrorCategory string
+
+const (
+	// BuildErrorReference is a local reference (`#/...`) that could not be built.
+	BuildErrorReference BuildErrorCategory = "reference"
+
+	// BuildErrorExternalReference is a reference to another file or a remote document that could not be built,
+	// usually because file or remote references are not enabled, or the file could not be found.
+	BuildErrorExternalReference BuildErrorCategory = "external-reference"
+
+	// BuildErrorSchema is an inline schema that libopenapi could not build.
+	BuildErrorSchema BuildErrorCategory = "schema"
+)
+
 type BuildError struct {
 	Error         error
 	SchemaProxy   *base.SchemaProxy
 	DrSchemaProxy *SchemaProxy
+
+	// Category is the kind of build error, Reference is the reference that failed to build (if any).
+	Category  BuildErrorCategory
+	Reference string
+
+	// JSONPath is the path to the schema that failed to build, OwnerPath is the path to the operation
+	// or path item that owns it, it is empty when the schema is not owned by an operation (for example in components).
+	JSONPath  string
+	OwnerPath string
+
+	// File is the absolute path of the file containing the schema.
+	File   string
+	Line   int
+	Column int
+}
+
+// NewBuildError creates a new BuildError for a schema proxy that failed to build.
+func NewBuildError(schemaProxy *base.SchemaProxy, drSchemaProxy *SchemaProxy) *BuildError {
+	be := &BuildError{
+		Error:         schemaProxy.GetBuildError(),
+		SchemaProxy:   schemaProxy,
+		DrSchemaProxy: drSchemaProxy,
+		Category:      BuildErrorSchema,
+	}
+	if schemaProxy.IsReference() {
+		be.Reference = schemaProxy.GetReference()
+		be.Category = BuildErrorExternalReference
+		if strings.HasPrefix(be.Reference, "#") {
+			be.Category = BuildErrorReference
+		}
+	}
+	if drSchemaProxy != nil {
+		be.JSONPath = drSchemaProxy.GenerateJSONPath()
+	}
+	if l := schemaProxy.GoLow(); l != nil {
+		node := l.GetKeyNode()
+		if node == nil {
+			node = l.GetValueNode()
+		}
+		if node != nil {
+			be.Line, be.Column = node.Line, node.Column
+		}
+		if idx := l.GetIndex(); idx != nil {
+			be.File = idx.GetSpecAbsolutePath()
+		}
+	}
+	return be
 }
 
 type WalkedSchema struct {
@@ -56,13 +119,53 @@ type DrContext struct {
 	Index             *index.SpecIndex
 	Rolodex           *index.Rolodex
 	V3Document        *v3.Document
-	WaitGroup         *conc.WaitGroup
+	WaitGroup         WaitGroup
 	BuildGraph        bool
 	UseSchemaCache    bool
-	SchemaCache       *sync.Map
+	SchemaCache       SchemaCache
+	WalkId            string
 	StorageRoot       string
 	WorkingDirectory  string
 	Logger            *slog.Logger
+
+	// Sections are the top level sections of the document that are walked, every section is walked when empty.
+	Sections Sections
+
+	// Telemetry traces the walk, it records nothing when nil. Stats counts the work of the walk, it is shared by
+	// every clone of the context.
+	Telemetry *telemetry.Instruments
+	Stats     *WalkStats
+}
+
+// WalkStats counts the work of a walk, for its telemetry.
+type WalkStats struct {
+	Objects           atomic.Int64
+	SchemaCacheHits   atomic.Int64
+	SchemaCacheMisses atomic.Int64
+}
+
+// cacheLookup counts a lookup of the schema cache.
+func (ws *WalkStats) cacheLookup(hit bool) {
+	if ws == nil {
+		return
+	}
+	if hit {
+		ws.SchemaCacheHits.Add(1)
+	} else {
+		ws.SchemaCacheMisses.Add(1)
+	}
+}
+
+// DebugEnabled returns true when a logger is configured and debug logging is enabled.
+func (d *DrContext) DebugEnabled() bool {
+	return d != nil && d.Logger != nil && d.Logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+// Debug logs a structured debug event, when debug logging is enabled.
+func (d *DrContext) Debug(msg string, args ...any) {
+	if d.DebugEnabled() {
+		d.Logger.Debug(msg, args...)
+	}
 }
 
 func GetDrContext(ctx context.Context) *DrContext {
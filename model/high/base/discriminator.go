@@ -5,6 +5,8 @@ package base
 
 import (
 	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"gopkg.in/yaml.v3"
+	"strings"
 )
 
 type Discriminator struct {
@@ -12,6 +14,56 @@ type Discriminator struct {
 	Foundation
 }
 
+// DiscriminatorMapping is a value of the mapping of a discriminator, and the schema it maps to.
+type DiscriminatorMapping struct {
+	// Value is the value of the discriminator property, Target is the schema name or reference it maps to.
+	Value  string
+	Target string
+
+	// Reference is the reference the target resolves to, schema names are references to components.
+	Reference string
+
+	// Schema is the walked schema the mapping points to, nil when the reference does not point to a schema.
+	Schema *SchemaProxy
+
+	// Node is the node of the target in the mapping.
+	Node *yaml.Node
+}
+
 func (d *Discriminator) GetValue() any {
 	return d.Value
 }
+
+// ResolveMappings returns every value of the mapping of the discriminator in the order they are declared, with the
+// walked schemas they point to. Mappings can only be resolved once the discriminator has been walked by a
+// document, until then the schemas of every mapping are nil.
+func (d *Discriminator) ResolveMappings() []*DiscriminatorMapping {
+	if d == nil || d.Value == nil || d.Value.Mapping == nil {
+		return nil
+	}
+	nodes := make(map[string]*yaml.Node)
+	if low := d.Value.GoLow(); low != nil && low.Mapping.Value != nil {
+		for k, v := range low.Mapping.Value.FromOldest() {
+			nodes[k.Value] = v.ValueNode
+		}
+	}
+	var mappings []*DiscriminatorMapping
+	for value, target := range d.Value.Mapping.FromOldest() {
+		m := &DiscriminatorMapping{Value: value, Target: target, Reference: target, Node: nodes[value]}
+
+		// values that are not references are the names of schemas in components.
+		if !strings.Contains(target, "#") && !strings.Contains(target, "/") {
+			m.Reference = "#/components/schemas/" + target
+		}
+		if d.resolver != nil {
+			switch s := d.resolver.ResolveReference(m.Reference, d).(type) {
+			case *SchemaProxy:
+				m.Schema = s
+			case *Schema:
+				m.Schema, _ = s.Parent.(*SchemaProxy)
+			}
+		}
+		mappings = append(mappings, m)
+	}
+	return mappings
+}
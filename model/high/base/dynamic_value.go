@@ -12,6 +12,30 @@ type Walkable interface {
 	Walk(context.Context, *base.SchemaProxy)
 }
 
+// SchemaOrBool is the value of a keyword that is either a schema or a boolean, such as items,
+// additionalProperties and unevaluatedProperties (see DynamicValue.Resolve).
+type SchemaOrBool interface {
+	// Schema returns the schema, or nil when the value is a boolean.
+	Schema() *SchemaProxy
+
+	// Bool returns the boolean value, ok is false when the value is a schema.
+	Bool() (value, ok bool)
+}
+
+type schemaOrBool struct {
+	schema *SchemaProxy
+	value  bool
+	isBool bool
+}
+
+func (s *schemaOrBool) Schema() *SchemaProxy {
+	return s.schema
+}
+
+func (s *schemaOrBool) Bool() (value, ok bool) {
+	return s.value, s.isBool
+}
+
 type DynamicValue[A, R, S, E any] struct {
 	Value *base.DynamicValue[A, R]
 	A     S
@@ -19,20 +43,44 @@ type DynamicValue[A, R, S, E any] struct {
 	Foundation
 }
 
-func (d *DynamicValue[A, R, S, E]) Walk(ctx context.Context) {
+// Walk walks the schema of the value when it holds one, and adds the value to the document whether it holds a
+// schema or a boolean, so the value is always a child of the schema that owns it.
+func (d *DynamicValue[A, R, S, E]) Walk(ctx context.Context, depth int) {
+	if ctx.Err() != nil {
+		return
+	}
+
 	drCtx := GetDrContext(ctx)
 	wg := drCtx.WaitGroup
 
 	if d.Value.IsA() {
 		if v, ok := any(d.A).(*SchemaProxy); ok {
 			p := any(d.Value.A).(*base.SchemaProxy)
-			wg.Go(func() { v.Walk(ctx, p, 0) })
+			wg.Go(func() { v.Walk(ctx, p, depth) })
 		}
 	}
 	drCtx.ObjectChan <- d
 	// there are no models that use the B value for walkable structures.
 }
 
+// Resolve returns the schema or the boolean the value holds, so consumers don't need to check which it is. It
+// returns nil for a nil value, or a value that holds neither a schema nor a boolean.
+func (d *DynamicValue[A, R, S, E]) Resolve() SchemaOrBool {
+	if d == nil || d.Value == nil {
+		return nil
+	}
+	if d.Value.IsA() {
+		if v, ok := any(d.A).(*SchemaProxy); ok && v != nil {
+			return &schemaOrBool{schema: v}
+		}
+		return nil
+	}
+	if b, ok := any(d.B).(bool); ok {
+		return &schemaOrBool{value: b, isBool: true}
+	}
+	return nil
+}
+
 func (d *DynamicValue[A, R, S, E]) GetValue() any {
 	return d.Value
 }
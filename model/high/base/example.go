@@ -14,6 +14,10 @@ type Example struct {
 }
 
 func (e *Example) Walk(ctx context.Context, example *base.Example) {
+	if ctx.Err() != nil {
+		return
+	}
+
 	drCtx := GetDrContext(ctx)
 	e.BuildNodesAndEdges(ctx, e.Key, "example", example, e)
 	e.Value = example
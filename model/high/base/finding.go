@@ -0,0 +1,54 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+import "gopkg.in/yaml.v3"
+
+// Finding severities use the same values as rule severities.
+const (
+	SeverityError = "error"
+	SeverityWarn  = "warn"
+	SeverityInfo  = "info"
+)
+
+// Finding is a problem discovered by one of the doctor's built-in analyzers. Unlike a RuleFunctionResult,
+// a finding always points to the doctor model it was found on.
+type Finding struct {
+	Id       string       `json:"id" yaml:"id"`
+	Message  string       `json:"message" yaml:"message"`
+	Severity string       `json:"severity" yaml:"severity"`
+	Path     string       `json:"path" yaml:"path"`
+	Object   Foundational `json:"-" yaml:"-"`
+	Node     *yaml.Node   `json:"-" yaml:"-"`
+}
+
+// NewFinding creates a new Finding for a doctor model, the path and node are extracted from the model.
+func NewFinding(id, severity, message string, object Foundational) *Finding {
+	f := &Finding{
+		Id:       id,
+		Message:  message,
+		Severity: severity,
+		Object:   object,
+	}
+	if object != nil {
+		f.Path = object.GenerateJSONPath()
+		f.Node = object.GetKeyNode()
+	}
+	return f
+}
+
+// NewRuleFinding creates a Finding from the result of a lint rule (see ConvertRuleResult), so it can be attached to
+// the doctor model it was reported on (see DrDocument.AttachFindings). The finding is not attached to an object.
+func NewRuleFinding(result *RuleFunctionResult) *Finding {
+	if result == nil {
+		return nil
+	}
+	return &Finding{
+		Id:       result.RuleId,
+		Message:  result.Message,
+		Severity: result.RuleSeverity,
+		Path:     result.Path,
+		Node:     result.StartNode,
+	}
+}
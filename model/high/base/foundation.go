@@ -4,6 +4,7 @@
 package base
 
 import (
+	"cmp"
 	"context"
 	"crypto/md5"
 	"encoding/hex"
@@ -11,8 +12,11 @@ import (
 	"github.com/pb33f/libopenapi/datamodel/high"
 	"github.com/pb33f/libopenapi/datamodel/low"
 	"github.com/pb33f/libopenapi/index"
+	"github.com/pb33f/libopenapi/what-changed/model"
 	"gopkg.in/yaml.v3"
 	"reflect"
+	"slices"
+	"strings"
 	"sync"
 )
 
@@ -28,6 +32,14 @@ type AcceptsRuleResults interface {
 	GetRuleFunctionResults() []*RuleFunctionResult
 }
 
+// HasChanges is implemented by objects that changes between two versions of a specification can be attached to.
+// Changes are appended and read concurrently, by walkers and renderers.
+type HasChanges interface {
+	AppendChange(change *model.Change)
+	GetChanges() []*model.Change
+	GetAllNestedChanges() []*model.Change
+}
+
 // Foundational is the base interface for all models in the doctor. It provides a way to navigate the model
 type Foundational interface {
 	GetParent() Foundational
@@ -46,10 +58,69 @@ type Foundational interface {
 	GetInstanceType() string
 }
 
+// HasChildren is implemented by objects that know the model objects walked directly beneath them. Every
+// Foundation implements it, it is kept out of Foundational so existing implementations still satisfy it.
+type HasChildren interface {
+	GetChildren() []Foundational
+	AddChild(child Foundational) bool
+}
+
+// HasAlias is implemented by objects that can be included in a specification through a YAML alias.
+type HasAlias interface {
+	IsAliasUsage() bool
+	GetAliasNode() *yaml.Node
+}
+
+// HasResolved is implemented by objects that can resolve the references they were walked through.
+type HasResolved interface {
+	Resolved() *ResolvedObject
+	RefChain() []*RefHop
+}
+
+// HasFindings is implemented by objects that findings, such as the results of lint rules, can be attached to.
+type HasFindings interface {
+	AddFinding(finding *Finding)
+	GetFindings() []*Finding
+}
+
+// ChildrenOf returns the children of an object, or nil when it does not implement HasChildren.
+func ChildrenOf(f Foundational) []Foundational {
+	if hc, ok := f.(HasChildren); ok {
+		return hc.GetChildren()
+	}
+	return nil
+}
+
+// FindingsOf returns the findings attached to an object, or nil when it does not implement HasFindings.
+func FindingsOf(f Foundational) []*Finding {
+	if hf, ok := f.(HasFindings); ok {
+		return hf.GetFindings()
+	}
+	return nil
+}
+
 type HasSize interface {
 	GetSize() (height, width int)
 }
 
+// SizeCalculator measures the graph node of a walked object, so front ends can size nodes for their own layout
+// (font metrics, density). The node holds the size it was built with.
+type SizeCalculator interface {
+	Size(object Foundational, node *Node) (height, width int)
+}
+
+// DefaultSizeCalculator sizes nodes for the pb33f UI, objects that implement HasSize are sized by GetSize and
+// the other nodes keep the size they were built with.
+type DefaultSizeCalculator struct{}
+
+// Size returns the size of an object that implements HasSize, or the size of its node.
+func (DefaultSizeCalculator) Size(object Foundational, node *Node) (height, width int) {
+	if hs, ok := object.(HasSize); ok {
+		return hs.GetSize()
+	}
+	return node.Height, node.Width
+}
+
 type Foundation struct {
 	PathSegment   string
 	InstanceType  string
@@ -68,6 +139,14 @@ type Foundation struct {
 	KeyNode       *yaml.Node
 	ValueNode     *yaml.Node
 	CacheSplit    bool
+	Children      []Foundational
+	AliasNode     *yaml.Node // set when the object was included through a YAML alias (`*anchor`)
+	resolver      ReferenceResolver
+	self          Foundational
+	changes       []*model.Change
+	findings      []*Finding
+	childSet      map[Foundational]struct{}
+	sorted        bool
 }
 
 func (f *Foundation) GetInstanceType() string {
@@ -82,6 +161,28 @@ func (f *Foundation) GetValueNode() *yaml.Node {
 	return f.ValueNode
 }
 
+// GetArrayIndex returns the position of the object in its parent array, or nil if it is not in an array.
+func (f *Foundation) GetArrayIndex() *int {
+	return f.Index
+}
+
+// IsAliasUsage returns true if the object was included in the specification through a YAML alias, rather than
+// being defined where it appears. The key and value nodes of an alias usage point at the anchor definition, the
+// alias itself is available from GetAliasNode.
+func (f *Foundation) IsAliasUsage() bool {
+	return f.AliasNode != nil
+}
+
+// GetAliasNode returns the alias node (`*anchor`) the object was included through, or nil.
+func (f *Foundation) GetAliasNode() *yaml.Node {
+	return f.AliasNode
+}
+
+// SetAliasNode records the alias node (`*anchor`) the object was included through.
+func (f *Foundation) SetAliasNode(node *yaml.Node) {
+	f.AliasNode = node
+}
+
 func (f *Foundation) BuildReferenceEdge(ctx context.Context, source, destination, ref string, poly string) *Edge {
 	drCtx := GetDrContext(ctx)
 	if drCtx != nil && f != nil {
@@ -97,6 +198,7 @@ func (f *Foundation) BuildReferenceEdge(ctx context.Context, source, destination
 			e.Poly = poly
 		}
 		e.Ref = ref
+		e.Relationship = RelationshipRef
 		f.AddEdge(e)
 		drCtx.EdgeChan <- e
 		return e
@@ -165,7 +267,6 @@ func (f *Foundation) BuildNode(ctx context.Context, label, nodeType string, arra
 	} else {
 		panic("no parent node")
 	}
-	return nil
 }
 
 func (f *Foundation) BuildNodesAndEdgesWithArray(ctx context.Context, label, nodeType string, model high.GoesLowUntyped, drModel any, arrayType bool, arrayCount int, arrayIndex *int) {
@@ -224,6 +325,7 @@ func (f *Foundation) ProcessNodesAndEdges(ctx context.Context, label, nodeType s
 			if f.PolyType != "" {
 				e.Poly = f.PolyType
 			}
+			e.Relationship = EdgeRelationship(f, parent)
 
 			parent.AddEdge(e)
 			drCtx.EdgeChan <- e
@@ -406,3 +508,181 @@ func (f *Foundation) GetRefNode() *Node {
 func (f *Foundation) GetEdges() []*Edge {
 	return f.Edges
 }
+
+// AppendChange attaches a change to the object.
+func (f *Foundation) AppendChange(change *model.Change) {
+	if f == nil || change == nil {
+		return
+	}
+	f.Mutex.Lock()
+	f.changes = append(f.changes, change)
+	f.Mutex.Unlock()
+}
+
+// GetChanges returns the changes attached to the object, in the order they were attached.
+func (f *Foundation) GetChanges() []*model.Change {
+	if f == nil {
+		return nil
+	}
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
+	return slices.Clone(f.changes)
+}
+
+// GetAllNestedChanges returns the changes attached to the object and to every object beneath it (see
+// GetChildren), the changes of the object first, followed by the changes of its children in order.
+func (f *Foundation) GetAllNestedChanges() []*model.Change {
+	changes := f.GetChanges()
+	seen := map[Foundational]bool{}
+	var collect func(children []Foundational)
+	collect = func(children []Foundational) {
+		for _, child := range children {
+			if seen[child] {
+				continue
+			}
+			seen[child] = true
+			if hc, ok := child.(HasChanges); ok {
+				changes = append(changes, hc.GetChanges()...)
+			}
+			collect(ChildrenOf(child))
+		}
+	}
+	collect(f.GetChildren())
+	return changes
+}
+
+// AddFinding attaches a finding to the object, for example the result of a lint rule (see
+// DrDocument.AttachFindings).
+func (f *Foundation) AddFinding(finding *Finding) {
+	if f == nil || finding == nil {
+		return
+	}
+	f.Mutex.Lock()
+	f.findings = append(f.findings, finding)
+	f.Mutex.Unlock()
+}
+
+// GetFindings returns the findings attached to the object, in the order they were attached.
+func (f *Foundation) GetFindings() []*Finding {
+	if f == nil {
+		return nil
+	}
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
+	return slices.Clone(f.findings)
+}
+
+// GetChildren returns the model objects walked directly beneath this one, in the order they appear in the
+// specification, by file and then by line and column. Children are populated as the walk completes, and sorted
+// the first time they are read after being added.
+func (f *Foundation) GetChildren() []Foundational {
+	if f == nil {
+		return nil
+	}
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
+	if !f.sorted {
+		sortFoundations(f.Children)
+		f.sorted = true
+	}
+	return slices.Clone(f.Children)
+}
+
+// childSetSize is the number of children an object holds before AddChild tracks them in a set, rather than
+// searching them, so linking the children of large objects (like components) stays linear.
+const childSetSize = 16
+
+// AddChild adds a child to the object, children are appended and only sorted when they are read (see
+// GetChildren). Returns false if the child has already been added.
+func (f *Foundation) AddChild(child Foundational) bool {
+	if f == nil || child == nil {
+		return false
+	}
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
+	if f.childSet != nil {
+		if _, ok := f.childSet[child]; ok {
+			return false
+		}
+		f.childSet[child] = struct{}{}
+	} else {
+		if slices.Contains(f.Children, child) {
+			return false
+		}
+		if len(f.Children) >= childSetSize {
+			f.childSet = make(map[Foundational]struct{}, len(f.Children)*2)
+			for _, c := range f.Children {
+				f.childSet[c] = struct{}{}
+			}
+			f.childSet[child] = struct{}{}
+		}
+	}
+	f.Children = append(f.Children, child)
+	f.sorted = false
+	return true
+}
+
+// sortFoundations orders objects by their location in the specification, by the file they were built from and
+// then by line and column. Objects without a location are kept last, in the order they were added.
+func sortFoundations(objects []Foundational) {
+	type located struct {
+		object    Foundational
+		file      string
+		line, col int
+	}
+	sorted := make([]located, len(objects))
+	for i, o := range objects {
+		line, col := foundationLocation(o)
+		sorted[i] = located{object: o, file: foundationFile(o), line: line, col: col}
+	}
+	slices.SortStableFunc(sorted, func(a, b located) int {
+		if (a.line == 0) != (b.line == 0) {
+			if a.line == 0 {
+				return 1
+			}
+			return -1
+		}
+		if c := strings.Compare(a.file, b.file); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.line, b.line); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.col, b.col)
+	})
+	for i := range sorted {
+		objects[i] = sorted[i].object
+	}
+}
+
+// foundationFile returns the absolute path of the file an object was built from, or an empty string when it
+// is not known.
+func foundationFile(f Foundational) string {
+	hv, ok := f.(HasValue)
+	if !ok {
+		return ""
+	}
+	gl, ok := hv.GetValue().(high.GoesLowUntyped)
+	if !ok || reflect.ValueOf(gl).Kind() == reflect.Pointer && reflect.ValueOf(gl).IsNil() {
+		return ""
+	}
+	hi, ok := gl.GoLowUntyped().(HasIndex)
+	if !ok || reflect.ValueOf(hi).Kind() == reflect.Pointer && reflect.ValueOf(hi).IsNil() {
+		return ""
+	}
+	if idx := hi.GetIndex(); idx != nil {
+		return idx.GetSpecAbsolutePath()
+	}
+	return ""
+}
+
+// foundationLocation returns the line and column of an object, using the key node if it has one.
+func foundationLocation(f Foundational) (int, int) {
+	if n := f.GetKeyNode(); n != nil {
+		return n.Line, n.Column
+	}
+	if n := f.GetValueNode(); n != nil {
+		return n.Line, n.Column
+	}
+	return 0, 0
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+import (
+	"fmt"
+	"github.com/pb33f/libopenapi/index"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"testing"
+)
+
+// fileValue is a low level model built from a file, for objects that need to know where they came from.
+type fileValue struct {
+	idx *index.SpecIndex
+}
+
+func (v fileValue) GoLowUntyped() any {
+	return v
+}
+
+func (v fileValue) GetIndex() *index.SpecIndex {
+	return v.idx
+}
+
+type fileObject struct {
+	Foundation
+	value fileValue
+}
+
+func (o *fileObject) GetValue() any {
+	return o.value
+}
+
+func newFileObject(file string, line int) *fileObject {
+	idx := &index.SpecIndex{}
+	idx.SetAbsolutePath(file)
+	return &fileObject{
+		Foundation: Foundation{PathSegment: fmt.Sprintf("%s:%d", file, line), KeyNode: &yaml.Node{Line: line, Column: 1}},
+		value:      fileValue{idx: idx},
+	}
+}
+
+func TestFoundation_AddChild(t *testing.T) {
+	parent := &Foundation{}
+	external := newFileObject("/specs/schemas.yaml", 1)
+	late := newFileObject("/specs/openapi.yaml", 20)
+	early := newFileObject("/specs/openapi.yaml", 3)
+	unlocated := &Foundation{PathSegment: "unlocated"}
+
+	for _, child := range []Foundational{unlocated, external, late, early} {
+		assert.True(t, parent.AddChild(child))
+	}
+	assert.False(t, parent.AddChild(late))
+	assert.False(t, parent.AddChild(nil))
+
+	// children are sorted by file, then by line, those without a location come last.
+	assert.Equal(t, []Foundational{early, late, external, unlocated}, parent.GetChildren())
+
+	// children added after a read are sorted again.
+	first := newFileObject("/specs/openapi.yaml", 1)
+	assert.True(t, parent.AddChild(first))
+	assert.Equal(t, []Foundational{first, early, late, external, unlocated}, parent.GetChildren())
+}
+
+func TestFoundation_AddChild_Many(t *testing.T) {
+	parent := &Foundation{}
+	var children []Foundational
+	for i := childSetSize * 2; i > 0; i-- {
+		child := newFileObject("/specs/openapi.yaml", i)
+		children = append([]Foundational{child}, children...)
+		assert.True(t, parent.AddChild(child))
+	}
+	for _, child := range children {
+		assert.False(t, parent.AddChild(child))
+	}
+	assert.Equal(t, children, parent.GetChildren())
+}
+
+func TestFoundation_Nil(t *testing.T) {
+	var f *Foundation
+	assert.Nil(t, f.GetChildren())
+	assert.Nil(t, f.GetChanges())
+	assert.Nil(t, f.GetFindings())
+	assert.False(t, f.AddChild(&Foundation{}))
+	assert.Nil(t, ChildrenOf(f))
+	assert.Nil(t, FindingsOf(f))
+}
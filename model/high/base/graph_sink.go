@@ -0,0 +1,12 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+// GraphSink receives the nodes and edges of the graph as a document is walked, so they can be streamed into another
+// store (a database, or the state of a UI) instead of being collected by the document. Calls are never made
+// concurrently, implementations do not need to be safe for concurrent use.
+type GraphSink interface {
+	AddNode(node *Node)
+	AddEdge(edge *Edge)
+}
@@ -18,6 +18,9 @@ type Info struct {
 }
 
 func (i *Info) Walk(ctx context.Context, info *base.Info) {
+	if ctx.Err() != nil {
+		return
+	}
 
 	drCtx := GetDrContext(ctx)
 	wg := drCtx.WaitGroup
@@ -0,0 +1,304 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+import (
+	"slices"
+	"sort"
+)
+
+type LayoutDirection string
+
+const (
+	// LayoutRight places layers from left to right, the default.
+	LayoutRight LayoutDirection = "right"
+
+	// LayoutDown places layers from top to bottom.
+	LayoutDown LayoutDirection = "down"
+)
+
+// LayoutOptions controls the hierarchical layout of a graph.
+type LayoutOptions struct {
+	Direction LayoutDirection
+
+	// NodeSpacing is the gap between nodes in the same layer, LayerSpacing is the gap between layers.
+	NodeSpacing  int
+	LayerSpacing int
+
+	// Iterations is the number of crossing reduction sweeps made over the layers.
+	Iterations int
+}
+
+// NodeLayout is the position of a node, the top left corner of the node is at X and Y.
+type NodeLayout struct {
+	X     int `json:"x"`
+	Y     int `json:"y"`
+	Layer int `json:"layer"`
+}
+
+// Point is a point on the route of an edge.
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// layoutVertex is a node (or a dummy vertex on an edge that spans layers) being laid out.
+type layoutVertex struct {
+	node     *Node
+	layer    int
+	order    int
+	breadth  int // position across the layer
+	size     int // size across the layer
+	depth    int // size along the direction of the layers
+	in, out  []int
+	position int // position along the direction of the layers
+}
+
+// Layout computes a layered (Sugiyama style) layout for a graph, assigning a position to every node and a route
+// to every edge, so consumers can draw the graph without laying it out themselves.
+//
+// Cycles (circular references) are broken by reversing edges, layers are assigned by longest path, crossings are
+// reduced with barycenter sweeps and nodes are placed towards the centre of their predecessors. Edges that span
+// more than one layer are routed through the gaps between the nodes of the layers they cross. Only the first
+// source and target of an edge are used. Node sizes are taken from Width and Height, zero sizes use WIDTH and HEIGHT.
+func Layout(nodes []*Node, edges []*Edge, opts *LayoutOptions) {
+	if opts == nil {
+		opts = &LayoutOptions{}
+	}
+	direction := opts.Direction
+	if direction == "" {
+		direction = LayoutRight
+	}
+	nodeSpacing, layerSpacing, iterations := opts.NodeSpacing, opts.LayerSpacing, opts.Iterations
+	if nodeSpacing <= 0 {
+		nodeSpacing = 20
+	}
+	if layerSpacing <= 0 {
+		layerSpacing = 60
+	}
+	if iterations <= 0 {
+		iterations = 4
+	}
+
+	var vertices []*layoutVertex
+	ids := make(map[string]int)
+	for _, n := range nodes {
+		if n == nil {
+			continue
+		}
+		if _, ok := ids[n.Id]; ok {
+			continue
+		}
+		w, h := n.Width, n.Height
+		if w <= 0 {
+			w = WIDTH
+		}
+		if h <= 0 {
+			h = HEIGHT
+		}
+		v := &layoutVertex{node: n, size: h, depth: w}
+		if direction == LayoutDown {
+			v.size, v.depth = w, h
+		}
+		ids[n.Id] = len(vertices)
+		vertices = append(vertices, v)
+	}
+	if len(vertices) == 0 {
+		return
+	}
+
+	// collect the edges between known nodes, skipping loops and duplicates.
+	type link struct{ from, to int }
+	type route struct {
+		edge *Edge
+		link link
+	}
+	var routes []*route
+	out := make([][]int, len(vertices))
+	seen := make(map[link]bool)
+	for _, e := range edges {
+		if e == nil || len(e.Sources) == 0 || len(e.Targets) == 0 {
+			continue
+		}
+		from, ok := ids[e.Sources[0]]
+		to, ko := ids[e.Targets[0]]
+		if !ok || !ko || from == to {
+			continue
+		}
+		r := &route{edge: e, link: link{from, to}}
+		routes = append(routes, r)
+		if !seen[r.link] {
+			seen[r.link] = true
+			out[from] = append(out[from], to)
+		}
+	}
+
+	// break cycles, edges that point back up the depth first search are reversed.
+	state := make([]int, len(vertices))
+	var topological []int
+	reversed := make(map[link]bool)
+	var visit func(v int)
+	visit = func(v int) {
+		state[v] = 1
+		for _, t := range out[v] {
+			switch state[t] {
+			case 0:
+				visit(t)
+			case 1:
+				reversed[link{v, t}] = true
+			}
+		}
+		state[v] = 2
+		topological = append(topological, v)
+	}
+	for v := range vertices {
+		if state[v] == 0 {
+			visit(v)
+		}
+	}
+	slices.Reverse(topological)
+
+	dag := make([][]int, len(vertices))
+	for from, targets := range out {
+		for _, to := range targets {
+			if reversed[link{from, to}] {
+				if !slices.Contains(dag[to], from) {
+					dag[to] = append(dag[to], from)
+				}
+			} else if !slices.Contains(dag[from], to) {
+				dag[from] = append(dag[from], to)
+			}
+		}
+	}
+
+	// longest path layering.
+	for _, v := range topological {
+		for _, t := range dag[v] {
+			if vertices[t].layer < vertices[v].layer+1 {
+				vertices[t].layer = vertices[v].layer + 1
+			}
+		}
+	}
+
+	// split edges that span layers with dummy vertices, so every edge joins adjacent layers.
+	chains := make(map[link][]int)
+	for from := range dag {
+		for _, to := range dag[from] {
+			chain := []int{from}
+			prev := from
+			for l := vertices[from].layer + 1; l < vertices[to].layer; l++ {
+				vertices = append(vertices, &layoutVertex{layer: l})
+				d := len(vertices) - 1
+				vertices[prev].out = append(vertices[prev].out, d)
+				vertices[d].in = append(vertices[d].in, prev)
+				chain = append(chain, d)
+				prev = d
+			}
+			vertices[prev].out = append(vertices[prev].out, to)
+			vertices[to].in = append(vertices[to].in, prev)
+			chains[link{from, to}] = append(chain, to)
+		}
+	}
+
+	var layers [][]int
+	for i, v := range vertices {
+		for len(layers) <= v.layer {
+			layers = append(layers, nil)
+		}
+		v.order = len(layers[v.layer])
+		layers[v.layer] = append(layers[v.layer], i)
+	}
+
+	// reduce crossings by ordering each layer by the barycenter of its neighbours in the layer before (or after).
+	barycenter := func(layer []int, neighbours func(v *layoutVertex) []int) {
+		centres := make(map[int]float64, len(layer))
+		for _, i := range layer {
+			n := neighbours(vertices[i])
+			if len(n) == 0 {
+				centres[i] = float64(vertices[i].order)
+				continue
+			}
+			sum := 0
+			for _, j := range n {
+				sum += vertices[j].order
+			}
+			centres[i] = float64(sum) / float64(len(n))
+		}
+		sort.SliceStable(layer, func(a, b int) bool { return centres[layer[a]] < centres[layer[b]] })
+		for o, i := range layer {
+			vertices[i].order = o
+		}
+	}
+	for i := 0; i < iterations; i++ {
+		for l := 1; l < len(layers); l++ {
+			barycenter(layers[l], func(v *layoutVertex) []int { return v.in })
+		}
+		for l := len(layers) - 2; l >= 0; l-- {
+			barycenter(layers[l], func(v *layoutVertex) []int { return v.out })
+		}
+	}
+
+	// place layers one after another, and nodes in a layer as close to the centre of their predecessors as the
+	// nodes before them allow.
+	position := 0
+	for _, layer := range layers {
+		depth, next := 0, 0
+		for _, i := range layer {
+			v := vertices[i]
+			v.position = position
+			depth = max(depth, v.depth)
+			breadth := next
+			if len(v.in) > 0 {
+				sum := 0
+				for _, j := range v.in {
+					sum += vertices[j].breadth + vertices[j].size/2
+				}
+				breadth = max(next, sum/len(v.in)-v.size/2)
+			}
+			v.breadth = breadth
+			next = breadth + v.size + nodeSpacing
+		}
+		position += depth + layerSpacing
+	}
+
+	point := func(primary, breadth int) *Point {
+		if direction == LayoutDown {
+			return &Point{X: breadth, Y: primary}
+		}
+		return &Point{X: primary, Y: breadth}
+	}
+	for _, v := range vertices {
+		if v.node == nil {
+			continue
+		}
+		p := point(v.position, v.breadth)
+		v.node.Layout = &NodeLayout{X: p.X, Y: p.Y, Layer: v.layer}
+	}
+
+	// route each edge from the far side of its source, through any dummy vertices, to the near side of its target.
+	for _, r := range routes {
+		from, to := r.link.from, r.link.to
+		if reversed[r.link] {
+			from, to = to, from
+		}
+		chain := chains[link{from, to}]
+		if len(chain) < 2 {
+			continue
+		}
+		var points []*Point
+		for i, c := range chain {
+			v := vertices[c]
+			centre := v.breadth + v.size/2
+			if i == 0 {
+				points = append(points, point(v.position+v.depth, centre))
+				continue
+			}
+			points = append(points, point(v.position, centre))
+		}
+		if reversed[r.link] {
+			slices.Reverse(points)
+		}
+		r.edge.Points = points
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLayout(t *testing.T) {
+	a := &Node{Id: "a", Width: 100, Height: 20}
+	b := &Node{Id: "b", Width: 100, Height: 20}
+	c := &Node{Id: "c", Width: 100, Height: 20}
+	d := &Node{Id: "d"}
+	edges := []*Edge{
+		{Id: "ab", Sources: []string{"a"}, Targets: []string{"b"}},
+		{Id: "bc", Sources: []string{"b"}, Targets: []string{"c"}},
+		{Id: "ac", Sources: []string{"a"}, Targets: []string{"c"}},
+		{Id: "ca", Sources: []string{"c"}, Targets: []string{"a"}}, // circular
+		{Id: "ad", Sources: []string{"a"}, Targets: []string{"d"}},
+	}
+	Layout([]*Node{a, b, c, d}, edges, nil)
+
+	assert.Equal(t, 0, a.Layout.Layer)
+	assert.Equal(t, 1, b.Layout.Layer)
+	assert.Equal(t, 2, c.Layout.Layer)
+	assert.Equal(t, 1, d.Layout.Layer)
+	assert.Equal(t, 0, a.Layout.X)
+	assert.Equal(t, 160, b.Layout.X)
+	assert.Equal(t, 160, d.Layout.X)
+	assert.Equal(t, 420, c.Layout.X) // the widest node in a layer decides its width.
+
+	// nodes in the same layer do not overlap.
+	top, bottom := b, d
+	if d.Layout.Y < b.Layout.Y {
+		top, bottom = d, b
+	}
+	assert.GreaterOrEqual(t, bottom.Layout.Y, top.Layout.Y+20+20)
+
+	// edges leave the right of the source and arrive on the left of the target.
+	assert.Equal(t, &Point{X: 100, Y: a.Layout.Y + 10}, edges[0].Points[0])
+	assert.Equal(t, &Point{X: 160, Y: b.Layout.Y + 10}, edges[0].Points[1])
+
+	// the edge that skips a layer is routed through it.
+	assert.Len(t, edges[2].Points, 3)
+	assert.Equal(t, 160, edges[2].Points[1].X)
+
+	// the circular edge still starts at its source.
+	assert.Equal(t, &Point{X: 420, Y: c.Layout.Y + 10}, edges[3].Points[0])
+	assert.Equal(t, &Point{X: 100, Y: a.Layout.Y + 10}, edges[3].Points[len(edges[3].Points)-1])
+
+	Layout([]*Node{a, b}, edges[:1], &LayoutOptions{Direction: LayoutDown, LayerSpacing: 10})
+	assert.Equal(t, 0, a.Layout.Y)
+	assert.Equal(t, 30, b.Layout.Y)
+	assert.Equal(t, a.Layout.X, b.Layout.X)
+}
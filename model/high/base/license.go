@@ -16,6 +16,10 @@ type License struct {
 }
 
 func (l *License) Walk(ctx context.Context, license *base.License) {
+	if ctx.Err() != nil {
+		return
+	}
+
 	drCtx := GetDrContext(ctx)
 	l.PathSegment = "license"
 	l.Value = license
@@ -0,0 +1,78 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// HTTPMethods are the operation keys of a path item, in the order the specification defines them. QUERY is
+// defined by OpenAPI 3.2, methods are lower case.
+var HTTPMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace", "query"}
+
+// MethodOrder controls which operation methods are rendered, and the order the operations of a path are rendered
+// in. A nil MethodOrder renders every method, in the order the operations are declared.
+type MethodOrder struct {
+	// Methods is the order operations of the same path are rendered in, for example HTTPMethods. Methods that are
+	// not listed are rendered after those that are, in the order they are declared. Operations are rendered in
+	// the order they are declared when empty.
+	Methods []string
+
+	// Exclude are the methods that are not rendered, for example trace.
+	Exclude []string
+}
+
+// Includes returns true if operations with a method are rendered, methods are not case sensitive.
+func (m *MethodOrder) Includes(method string) bool {
+	if m == nil {
+		return true
+	}
+	return !slices.ContainsFunc(m.Exclude, func(e string) bool { return strings.EqualFold(e, method) })
+}
+
+// Compare orders two methods, methods that are not in the order are equal to each other and come last.
+func (m *MethodOrder) Compare(a, b string) int {
+	if m == nil {
+		return 0
+	}
+	rank := func(method string) int {
+		if i := slices.IndexFunc(m.Methods, func(o string) bool { return strings.EqualFold(o, method) }); i >= 0 {
+			return i
+		}
+		return len(m.Methods)
+	}
+	return rank(a) - rank(b)
+}
+
+// OrderMethods returns the items with a rendered method, the operations of each path ordered by method. Paths
+// keep the order they first appear in. The key of an item is its path (anything that groups operations, such as
+// the name of a webhook) and its method.
+func OrderMethods[T any](m *MethodOrder, items []T, key func(T) (path, method string)) []T {
+	ordered := make([]T, 0, len(items))
+	first := make(map[string]int)
+	for _, item := range items {
+		path, method := key(item)
+		if !m.Includes(method) {
+			continue
+		}
+		if _, ok := first[path]; !ok {
+			first[path] = len(first)
+		}
+		ordered = append(ordered, item)
+	}
+	if m == nil || len(m.Methods) == 0 {
+		return ordered
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		pi, mi := key(ordered[i])
+		pj, mj := key(ordered[j])
+		if pi != pj {
+			return first[pi] < first[pj]
+		}
+		return m.Compare(mi, mj) < 0
+	})
+	return ordered
+}
@@ -0,0 +1,29 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOrderMethods(t *testing.T) {
+	ops := [][2]string{{"/b", "trace"}, {"/b", "post"}, {"/a", "query"}, {"/a", "delete"}, {"/a", "get"}}
+	key := func(op [2]string) (string, string) { return op[0], op[1] }
+
+	assert.Equal(t, ops, OrderMethods(nil, ops, key))
+	assert.Equal(t, [][2]string{{"/b", "post"}, {"/a", "query"}, {"/a", "delete"}, {"/a", "get"}},
+		OrderMethods(&MethodOrder{Exclude: []string{"TRACE"}}, ops, key))
+	assert.Equal(t, [][2]string{{"/b", "post"}, {"/b", "trace"}, {"/a", "get"}, {"/a", "delete"}, {"/a", "query"}},
+		OrderMethods(&MethodOrder{Methods: HTTPMethods}, ops, key))
+
+	// unlisted methods come last, in the order they are declared.
+	order := &MethodOrder{Methods: []string{"DELETE"}, Exclude: []string{"post"}}
+	assert.Equal(t, [][2]string{{"/b", "trace"}, {"/a", "delete"}, {"/a", "query"}, {"/a", "get"}},
+		OrderMethods(order, ops, key))
+	assert.True(t, order.Includes("GET"))
+	assert.False(t, order.Includes("Post"))
+	assert.Less(t, order.Compare("delete", "get"), 0)
+	assert.Equal(t, 0, order.Compare("get", "query"))
+}
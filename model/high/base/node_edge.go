@@ -35,20 +35,54 @@ type Node struct {
 	Extensions    int               `json:"extensions,omitempty"`
 	Hash          string            `json:"hash,omitempty"`
 	Origin        *index.NodeOrigin `json:"origin,omitempty"`
+	Cluster       string            `json:"cluster,omitempty"` // the region of the document, see NodeCluster
 	drModel       any               `json:"-"`
 	KeyLine       int               `json:"-"`
 	ValueLine     int               `json:"valueLine"`
 	Instance      any               `json:"-"`
 	DrInstance    any               `json:"-"`
 	RenderProps   bool              `json:"-"`
+	Layout        *NodeLayout       `json:"-"` // set by Layout
+}
+
+// Relationship describes how the target of an edge relates to its source.
+type Relationship string
+
+const (
+	RelationshipPropertyOf  Relationship = "property-of"
+	RelationshipItemOf      Relationship = "item-of"
+	RelationshipAllOfMember Relationship = "allOf-member"
+	RelationshipOneOfMember Relationship = "oneOf-member"
+	RelationshipAnyOfMember Relationship = "anyOf-member"
+	RelationshipParameterOf Relationship = "parameter-of"
+	RelationshipResponseOf  Relationship = "response-of"
+	RelationshipRef         Relationship = "ref"
+	RelationshipChildOf     Relationship = "child-of"
+)
+
+// relationships maps the path segment an object is found under, to its relationship with its parent.
+var relationships = map[string]Relationship{
+	"properties":        RelationshipPropertyOf,
+	"patternProperties": RelationshipPropertyOf,
+	"items":             RelationshipItemOf,
+	"prefixItems":       RelationshipItemOf,
+	"contains":          RelationshipItemOf,
+	"unevaluatedItems":  RelationshipItemOf,
+	"allOf":             RelationshipAllOfMember,
+	"oneOf":             RelationshipOneOfMember,
+	"anyOf":             RelationshipAnyOfMember,
+	"parameters":        RelationshipParameterOf,
+	"responses":         RelationshipResponseOf,
 }
 
 type Edge struct {
-	Id      string   `json:"id"`
-	Sources []string `json:"sources"`
-	Targets []string `json:"targets"`
-	Poly    string   `json:"poly,omitempty"`
-	Ref     string   `json:"ref"`
+	Id           string       `json:"id"`
+	Sources      []string     `json:"sources"`
+	Targets      []string     `json:"targets"`
+	Poly         string       `json:"poly,omitempty"`
+	Ref          string       `json:"ref"`
+	Relationship Relationship `json:"relationship,omitempty"`
+	Points       []*Point     `json:"points,omitempty"` // the route of the edge, set by Layout
 }
 
 func (n *Node) MarshalJSON() ([]byte, error) {
@@ -70,6 +104,14 @@ func (n *Node) MarshalJSON() ([]byte, error) {
 	if n.Origin != nil {
 		propMap["origin"] = n.Origin.AbsoluteLocation
 	}
+	if n.Cluster != "" {
+		propMap["cluster"] = n.Cluster
+	}
+	if n.Layout != nil {
+		propMap["x"] = n.Layout.X
+		propMap["y"] = n.Layout.Y
+		propMap["layer"] = n.Layout.Layer
+	}
 
 	if n.IsPoly || n.PolyType != "" {
 		propMap["isPoly"] = n.IsPoly
@@ -145,12 +187,13 @@ func (n *Node) MarshalJSON() ([]byte, error) {
 
 func GenerateNode(parentId string, instance any, drModel any, ctx *DrContext) *Node {
 	// check if instance can go low
-	var uuidValue string
+	var uuidValue, cluster string
 	line := 1
 
 	var nodeOrigin *index.NodeOrigin
 	if instance != nil {
 		uuidValue = instance.(Foundational).GenerateJSONPath()
+		cluster = NodeCluster(uuidValue)
 
 		if hv, ok := drModel.(HasValue); ok {
 			if gl, kk := hv.GetValue().(high.GoesLowUntyped); kk {
@@ -211,7 +254,22 @@ func GenerateNode(parentId string, instance any, drModel any, ctx *DrContext) *N
 		ValueLine: line,
 		drModel:   drModel,
 		Origin:    nodeOrigin,
+		Cluster:   cluster,
+	}
+}
+
+// EdgeRelationship works out the relationship between an object and the object its graph node hangs from, using
+// the closest path segment between them (a schema in `properties` is a property of the schema that holds it).
+func EdgeRelationship(child, nodeParent Foundational) Relationship {
+	for f := child; f != nil && f != nodeParent; f = f.GetParent() {
+		if seg := f.GetPathSegment(); seg != "" {
+			if r, ok := relationships[seg]; ok {
+				return r
+			}
+			return RelationshipChildOf
+		}
 	}
+	return RelationshipChildOf
 }
 
 func GenerateEdge(sources []string, targets []string) *Edge {
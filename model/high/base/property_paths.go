@@ -0,0 +1,242 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+import (
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// PropertyPath is an addressable property in the effective shape of a schema.
+//
+// Path uses dots between property names (`customer.address.street`), `[]` for the items of an array
+// (`tags[].name`) and `[*]` for the values of additionalProperties (`metadata[*].id`). Property names that are
+// not simple identifiers are quoted in brackets (`headers['content-type']`).
+type PropertyPath struct {
+	Path     string
+	Type     []string
+	Leaf     bool
+	Required bool
+
+	// Circular is true when the property schema is already being visited higher up the path, its properties
+	// are not enumerated again.
+	Circular bool
+
+	// Constraints are the validation keywords set on the property schema, keyed by keyword name
+	// (for example `maxLength`, `pattern` or `enum`).
+	Constraints map[string]any
+
+	// Reference is the $ref followed to reach the property schema, if any.
+	Reference string
+	Schema    *base.Schema
+}
+
+var simplePropertyName = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// PropertyPaths walks the effective shape of the schema, following references and merging allOf, oneOf and anyOf
+// compositions, and returns a path for every addressable property, in the order they are declared.
+//
+// Properties are only required when they are required by the object that declares them (or an allOf member of it),
+// properties contributed by oneOf and anyOf members are never required.
+func (s *Schema) PropertyPaths() []*PropertyPath {
+	if s == nil || s.Value == nil {
+		return nil
+	}
+	pp := &propertyPaths{byPath: make(map[string]*PropertyPath)}
+	pp.collect(s.Value, "", []*base.Schema{s.Value})
+
+	// a leaf is a property with no properties of its own.
+	parents := make(map[string]bool)
+	for _, p := range pp.paths {
+		parents[parentPropertyPath(p.Path)] = true
+	}
+	for _, p := range pp.paths {
+		p.Leaf = !p.Circular && !parents[p.Path]
+	}
+	return pp.paths
+}
+
+type propertyPaths struct {
+	paths  []*PropertyPath
+	byPath map[string]*PropertyPath
+}
+
+type effectiveProperty struct {
+	name     string
+	proxy    *base.SchemaProxy
+	required bool
+}
+
+func (pp *propertyPaths) collect(schema *base.Schema, prefix string, stack []*base.Schema) {
+	var properties []*effectiveProperty
+	var items, additional []*base.SchemaProxy
+	flattenSchema(schema, true, make(map[*base.Schema]bool), func(s *base.Schema, required bool) {
+		if s.Properties != nil {
+			for name, proxy := range s.Properties.FromOldest() {
+				properties = append(properties, &effectiveProperty{
+					name: name, proxy: proxy, required: required && slices.Contains(s.Required, name),
+				})
+			}
+		}
+		if s.Items != nil && s.Items.IsA() && s.Items.A != nil {
+			items = append(items, s.Items.A)
+		}
+		if s.AdditionalProperties != nil && s.AdditionalProperties.IsA() && s.AdditionalProperties.A != nil {
+			additional = append(additional, s.AdditionalProperties.A)
+		}
+	})
+
+	for _, p := range properties {
+		pp.add(prefix+propertySegment(prefix, p.name), p.proxy, p.required, stack)
+	}
+	for _, proxy := range items {
+		pp.descend(prefix+"[]", proxy, stack)
+	}
+	for _, proxy := range additional {
+		pp.descend(prefix+"[*]", proxy, stack)
+	}
+}
+
+// add records a property path (merging it with an existing path from another composition member), then
+// enumerates the properties of the property schema.
+func (pp *propertyPaths) add(path string, proxy *base.SchemaProxy, required bool, stack []*base.Schema) {
+	schema := proxy.Schema()
+	if schema == nil {
+		return
+	}
+	p := pp.byPath[path]
+	if p == nil {
+		p = &PropertyPath{Path: path, Schema: schema, Constraints: make(map[string]any)}
+		pp.byPath[path] = p
+		pp.paths = append(pp.paths, p)
+	}
+	p.Required = p.Required || required
+	if proxy.IsReference() && p.Reference == "" {
+		p.Reference = proxy.GetReference()
+	}
+	flattenSchema(schema, true, make(map[*base.Schema]bool), func(s *base.Schema, _ bool) {
+		for _, t := range EffectiveTypes(s) {
+			if !slices.Contains(p.Type, t) {
+				p.Type = append(p.Type, t)
+			}
+		}
+		addConstraints(p.Constraints, s)
+	})
+
+	if slices.Contains(stack, schema) {
+		p.Circular = true
+		return
+	}
+	pp.collect(schema, path, append(stack, schema))
+}
+
+// descend enumerates the properties of array items and additionalProperties, which are not properties
+// themselves.
+func (pp *propertyPaths) descend(prefix string, proxy *base.SchemaProxy, stack []*base.Schema) {
+	schema := proxy.Schema()
+	if schema == nil || slices.Contains(stack, schema) {
+		return
+	}
+	pp.collect(schema, prefix, append(stack, schema))
+}
+
+// flattenSchema calls visit for a schema and every member of its compositions. Required is false for members
+// of oneOf and anyOf, and anything composed inside them.
+func flattenSchema(schema *base.Schema, required bool, seen map[*base.Schema]bool, visit func(s *base.Schema, required bool)) {
+	if schema == nil || seen[schema] {
+		return
+	}
+	seen[schema] = true
+	visit(schema, required)
+	for _, proxy := range schema.AllOf {
+		flattenSchema(proxy.Schema(), required, seen, visit)
+	}
+	for _, proxy := range schema.OneOf {
+		flattenSchema(proxy.Schema(), false, seen, visit)
+	}
+	for _, proxy := range schema.AnyOf {
+		flattenSchema(proxy.Schema(), false, seen, visit)
+	}
+}
+
+// parentPropertyPath returns the path of the property that contains a property path, skipping over array
+// items and additionalProperties.
+func parentPropertyPath(path string) string {
+	i := strings.LastIndex(path, ".")
+	if strings.HasSuffix(path, "']") {
+		i = strings.LastIndex(path, "['")
+	}
+	if i < 0 {
+		return ""
+	}
+	parent := path[:i]
+	for strings.HasSuffix(parent, "[]") || strings.HasSuffix(parent, "[*]") {
+		parent = strings.TrimSuffix(strings.TrimSuffix(parent, "[]"), "[*]")
+	}
+	return parent
+}
+
+func propertySegment(prefix, name string) string {
+	if !simplePropertyName.MatchString(name) {
+		return "['" + name + "']"
+	}
+	if prefix == "" {
+		return name
+	}
+	return "." + name
+}
+
+func addConstraints(constraints map[string]any, s *base.Schema) {
+	if s.Format != "" {
+		constraints["format"] = s.Format
+	}
+	if s.Pattern != "" {
+		constraints["pattern"] = s.Pattern
+	}
+	setConstraint(constraints, "minLength", s.MinLength)
+	setConstraint(constraints, "maxLength", s.MaxLength)
+	setConstraint(constraints, "minimum", s.Minimum)
+	setConstraint(constraints, "maximum", s.Maximum)
+	setConstraint(constraints, "multipleOf", s.MultipleOf)
+	setConstraint(constraints, "minItems", s.MinItems)
+	setConstraint(constraints, "maxItems", s.MaxItems)
+	setConstraint(constraints, "uniqueItems", s.UniqueItems)
+	setConstraint(constraints, "minProperties", s.MinProperties)
+	setConstraint(constraints, "maxProperties", s.MaxProperties)
+	if IsNullable(s) {
+		constraints["nullable"] = true
+	}
+	setConstraint(constraints, "readOnly", s.ReadOnly)
+	setConstraint(constraints, "writeOnly", s.WriteOnly)
+	for keyword, v := range map[string]*base.DynamicValue[bool, float64]{
+		"exclusiveMinimum": s.ExclusiveMinimum,
+		"exclusiveMaximum": s.ExclusiveMaximum,
+	} {
+		switch {
+		case v == nil:
+		case v.IsA():
+			constraints[keyword] = v.A
+		default:
+			constraints[keyword] = v.B
+		}
+	}
+	if len(s.Enum) > 0 {
+		var values []string
+		for _, n := range s.Enum {
+			values = append(values, n.Value)
+		}
+		constraints["enum"] = values
+	}
+	if s.Const != nil {
+		constraints["const"] = s.Const.Value
+	}
+}
+
+func setConstraint[T any](constraints map[string]any, keyword string, value *T) {
+	if value != nil {
+		constraints[keyword] = *value
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSchema_PropertyPaths(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: paths
+  version: 1
+components:
+  schemas:
+    Order:
+      type: object
+      required: [id]
+      allOf:
+        - type: object
+          required: [customer]
+          properties:
+            customer:
+              $ref: '#/components/schemas/Customer'
+      properties:
+        id:
+          type: string
+          format: uuid
+        tags:
+          type: array
+          items:
+            type: object
+            properties:
+              name:
+                type: string
+                maxLength: 10
+        metadata:
+          type: object
+          additionalProperties:
+            type: object
+            properties:
+              value:
+                type: integer
+                minimum: 1
+        content-type:
+          type: string
+          enum: [json, yaml]
+      oneOf:
+        - properties:
+            coupon:
+              type: string
+          required: [coupon]
+    Customer:
+      type: object
+      properties:
+        name:
+          type: string
+        referrer:
+          $ref: '#/components/schemas/Customer'`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := doc.BuildV3Model()
+	order := v3Doc.Model.Components.Schemas.GetOrZero("Order").Schema()
+
+	paths := (&Schema{Value: order}).PropertyPaths()
+	byPath := make(map[string]*PropertyPath)
+	var names []string
+	for _, p := range paths {
+		byPath[p.Path] = p
+		names = append(names, p.Path)
+	}
+	assert.Equal(t, []string{"id", "tags", "tags[].name", "metadata", "metadata[*].value", "['content-type']",
+		"customer", "customer.name", "customer.referrer", "coupon"}, names)
+
+	assert.True(t, byPath["id"].Required)
+	assert.True(t, byPath["id"].Leaf)
+	assert.Equal(t, []string{"string"}, byPath["id"].Type)
+	assert.Equal(t, "uuid", byPath["id"].Constraints["format"])
+
+	assert.False(t, byPath["tags"].Leaf)
+	assert.Equal(t, int64(10), byPath["tags[].name"].Constraints["maxLength"])
+	assert.Equal(t, float64(1), byPath["metadata[*].value"].Constraints["minimum"])
+	assert.Equal(t, []string{"json", "yaml"}, byPath["['content-type']"].Constraints["enum"])
+
+	assert.True(t, byPath["customer"].Required)
+	assert.Equal(t, "#/components/schemas/Customer", byPath["customer"].Reference)
+	assert.True(t, byPath["customer.referrer"].Circular)
+	assert.False(t, byPath["customer.referrer"].Leaf)
+	assert.False(t, byPath["coupon"].Required)
+
+	assert.Nil(t, (*Schema)(nil).PropertyPaths())
+}
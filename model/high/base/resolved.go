@@ -0,0 +1,77 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+// ResolvedObject is a dereferenced view of a walked object. Every reference in the subtree is replaced by the doctor
+// object it points to (the walked object itself, not a copy), so the view can be navigated as if the specification
+// had no references at all.
+type ResolvedObject struct {
+	// Object is the walked object, for a reference this is the object the reference points to.
+	Object Foundational
+
+	// Reference is the reference that was followed to reach Object, Source is the object that holds it.
+	Reference string
+	Source    Foundational
+
+	Children []*ResolvedObject
+
+	// Circular is true when following the reference would lead back to an object that is already being
+	// resolved, the children of a circular object are not resolved again.
+	Circular bool
+}
+
+// RefHop is a single step through a chain of references.
+type RefHop struct {
+	// Reference is the `$ref` value followed by the hop.
+	Reference string
+
+	// Source is the object holding the reference, for hops after the first it is the object the previous hop
+	// landed on. Target is the walked object the reference points to, nil when the target was not walked (for
+	// example, a schema in another file that is only reachable through a reference).
+	Source Foundational
+	Target Foundational
+
+	// Origin is the absolute location of the file containing the target, Line and Column are the position of the
+	// target inside that file.
+	Origin string
+	Line   int
+	Column int
+
+	// Circular is true when the hop points back to a target that has already been visited in the chain.
+	Circular bool
+}
+
+// ReferenceResolver resolves the references of walked objects, it is provided by the document that walked them.
+type ReferenceResolver interface {
+	Resolve(f Foundational) *ResolvedObject
+	RefChain(f Foundational) []*RefHop
+
+	// ResolveReference returns the walked object a reference made by an object points to, or nil when nothing
+	// was walked at the target.
+	ResolveReference(ref string, from Foundational) Foundational
+}
+
+// SetResolver sets the resolver used by Resolved and RefChain, with the object that embeds the foundation.
+func (f *Foundation) SetResolver(resolver ReferenceResolver, self Foundational) {
+	f.resolver = resolver
+	f.self = self
+}
+
+// Resolved returns a view of the object with every `$ref` in its subtree replaced by the object it points to.
+// Nil is returned if the object was not walked by a document.
+func (f *Foundation) Resolved() *ResolvedObject {
+	if f.resolver == nil {
+		return nil
+	}
+	return f.resolver.Resolve(f.self)
+}
+
+// RefChain returns every hop taken to resolve the object when it is a reference, in order, including hops through
+// components that are themselves references. Nil is returned if the object is not a reference.
+func (f *Foundation) RefChain() []*RefHop {
+	if f.resolver == nil {
+		return nil
+	}
+	return f.resolver.RefChain(f.self)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/pb33f/libopenapi/orderedmap"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
 	"slices"
 	"strings"
 )
@@ -39,10 +40,53 @@ type Schema struct {
 	AdditionalProperties  *DynamicValue[*base.SchemaProxy, bool, *SchemaProxy, bool]
 	XML                   *XML
 	ExternalDocs          *ExternalDoc
+
+	// Skip explains why the schema was not walked, it is only set for skipped schemas.
+	Skip *SchemaSkip
 	Foundation
 }
 
+// SkipReason is the reason a schema was not walked.
+type SkipReason string
+
+const (
+	// SkipSelfReference is a schema that references itself.
+	SkipSelfReference SkipReason = "self-reference"
+	// SkipCircular is a schema that is the loop point of a circular reference.
+	SkipCircular SkipReason = "circular"
+	// SkipDepth is a schema nested too deeply to walk.
+	SkipDepth SkipReason = "depth"
+	// SkipBuildError is a schema that libopenapi could not build.
+	SkipBuildError SkipReason = "build-error"
+)
+
+// SchemaSkip describes why a schema was skipped, and where the skip happened. File, Line and Column are the
+// location of the reference (or schema) that was skipped.
+type SchemaSkip struct {
+	Reason    SkipReason
+	Reference string
+	File      string
+	Line      int
+	Column    int
+	Error     error
+}
+
+// NewSchemaSkip creates a new SchemaSkip for a node, in the file of the supplied index.
+func NewSchemaSkip(reason SkipReason, node *yaml.Node, idx *index.SpecIndex) *SchemaSkip {
+	skip := &SchemaSkip{Reason: reason}
+	if node != nil {
+		skip.Line, skip.Column = node.Line, node.Column
+	}
+	if idx != nil {
+		skip.File = idx.GetSpecAbsolutePath()
+	}
+	return skip
+}
+
 func (s *Schema) Walk(ctx context.Context, schema *base.Schema, depth int) {
+	if ctx.Err() != nil {
+		return
+	}
 
 	drCtx := GetDrContext(ctx)
 	buf := strings.Builder{}
@@ -56,30 +100,39 @@ func (s *Schema) Walk(ctx context.Context, schema *base.Schema, depth int) {
 	if depth > 500 {
 		// this schema is insane and we're going to bail
 		if drCtx.Logger != nil {
-			drCtx.Logger.Warn("schema is too deep, over 500 levels! - exiting build, model will be incomplete", "schema", schema)
+			drCtx.Logger.Warn("schema is too deep, over 500 levels! - exiting build, model will be incomplete",
+				"schema", buf.String(), "depth", depth)
+		}
+		s.Value = schema
+		s.Skip = NewSchemaSkip(SkipDepth, l.RootNode, l.Index)
+		drCtx.SkippedSchemaChan <- &WalkedSchema{
+			Schema:     s,
+			SchemaNode: l.RootNode,
 		}
 		return
 	}
 	wg := drCtx.WaitGroup
 
 	sm := drCtx.SchemaCache
-	if drCtx.UseSchemaCache {
-		if h, ok := sm.Load(buf.String()); ok {
+	var cacheKey string
+	if drCtx.UseSchemaCache && sm != nil {
+		cacheKey = schemaCacheKey(drCtx.WalkId, buf.String())
+
+		// only a schema this walk has already walked at the same location (through another reference) is skipped.
+		// schemas with the same content elsewhere, or walked by another document, are walked again so their
+		// objects, parents and JSONPaths belong to this document.
+		if _, ok := sm.Load(cacheKey); ok {
 
 			// cached! we don't need to re-walk this.
 			s.Value = schema
-
-			rnHash := index.HashNode(schema.GoLow().RootNode)
-			hash := h.(string)
-			if rnHash == hash {
-				s.BuildNodesAndEdges(ctx, s.Name, "schema", schema, s)
-				drCtx.ObjectChan <- s
-				return
-
-			}
+			drCtx.Debug("schema cache hit", "schema", buf.String())
+			drCtx.Stats.cacheLookup(true)
+			s.BuildNodesAndEdges(ctx, s.Name, "schema", schema, s)
+			drCtx.ObjectChan <- s
+			return
 		}
-
-		sm.Store(buf.String(), index.HashNode(schema.GoLow().RootNode))
+		drCtx.Debug("schema cache miss", "schema", buf.String())
+		drCtx.Stats.cacheLookup(false)
 	}
 
 	s.Value = schema
@@ -371,16 +424,16 @@ func (s *Schema) Walk(ctx context.Context, schema *base.Schema, depth int) {
 		dynamicValue.KeyNode = schema.GoLow().UnevaluatedProperties.KeyNode
 		if schema.UnevaluatedProperties.IsA() {
 			sch := &SchemaProxy{}
-			sch.Parent = s
+			sch.Parent = dynamicValue
 			sch.Value = schema.UnevaluatedProperties.A
 			sch.NodeParent = s
 			sch.ValueNode = schema.GoLow().UnevaluatedProperties.ValueNode
 			sch.KeyNode = schema.GoLow().UnevaluatedProperties.KeyNode
-			v := schema.UnevaluatedProperties.A
-			wg.Go(func() { sch.Walk(ctx, v, depth) })
+			dynamicValue.A = sch
 		} else {
 			dynamicValue.B = schema.UnevaluatedProperties.B
 		}
+		wg.Go(func() { dynamicValue.Walk(ctx, depth) })
 		s.UnevaluatedProperties = dynamicValue
 	}
 
@@ -403,13 +456,10 @@ func (s *Schema) Walk(ctx context.Context, schema *base.Schema, depth int) {
 			sch.Node = s.Node
 			dynamicValue.A = sch
 			dynamicValue.Node = s.Node
-			v := schema.Items.A
-			wg.Go(func() {
-				sch.Walk(ctx, v, depth)
-			})
 		} else {
 			dynamicValue.B = schema.Items.B
 		}
+		wg.Go(func() { dynamicValue.Walk(ctx, depth) })
 		s.Items = dynamicValue
 	}
 
@@ -496,10 +546,10 @@ func (s *Schema) Walk(ctx context.Context, schema *base.Schema, depth int) {
 			sch.KeyNode = schema.AdditionalProperties.A.GoLow().GetKeyNode()
 			sch.ValueNode = schema.AdditionalProperties.A.GoLow().GetValueNode()
 			dynamicValue.A = sch
-			wg.Go(func() { dynamicValue.Walk(ctx) })
 		} else {
 			dynamicValue.B = schema.AdditionalProperties.B
 		}
+		wg.Go(func() { dynamicValue.Walk(ctx, depth) })
 		s.AdditionalProperties = dynamicValue
 	}
 
@@ -528,6 +578,12 @@ func (s *Schema) Walk(ctx context.Context, schema *base.Schema, depth int) {
 		drCtx.ObjectChan <- externalDocs
 	}
 
+	// stored once the schema is walked, so the cache never holds a schema that is still being built.
+	if cacheKey != "" {
+		sm.Store(cacheKey, &CachedSchema{Hash: index.HashNode(schema.GoLow().RootNode), Location: buf.String(),
+			WalkId: drCtx.WalkId})
+	}
+
 	drCtx.ObjectChan <- s
 
 }
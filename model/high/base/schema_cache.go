@@ -0,0 +1,108 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultSchemaCacheSize is the number of schemas held by the schema cache created for a walk, when no cache
+// is supplied.
+const DefaultSchemaCacheSize = 100000
+
+// CachedSchema records a schema that has been walked, in a SchemaCache. Hash is the content hash of the schema node,
+// Location the absolute path of the file, line and column it was walked at, and WalkId identifies the walk that
+// walked it. Schemas are only recorded once they have been walked.
+type CachedSchema struct {
+	Hash     string
+	Location string
+	WalkId   string
+}
+
+// SchemaCache records the schemas a walk has walked, keyed by the walk and the location of the schema, so a walk
+// skips schemas it has already walked at the same location, such as the target of many references. Walked
+// schemas are never reused by another walk, or at another location with the same content, as their objects,
+// parents and JSONPaths belong to the document and location that walked them. A cache can be shared by walks of
+// many documents to bound the memory they use, each walk adds its own entries. Implementations must be safe for
+// concurrent use, unless they are only used by sequential walks (see OrderedSchemaCache).
+type SchemaCache interface {
+	Load(key string) (*CachedSchema, bool)
+	Store(key string, schema *CachedSchema)
+}
+
+// LRUSchemaCache is a thread-safe SchemaCache that evicts the least recently used schema once it is full.
+type LRUSchemaCache struct {
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+	lock    sync.Mutex
+}
+
+type lruEntry struct {
+	key    string
+	schema *CachedSchema
+}
+
+// NewSchemaCache creates a new LRUSchemaCache holding up to size schemas, a size of zero or less uses
+// DefaultSchemaCacheSize.
+func NewSchemaCache(size int) *LRUSchemaCache {
+	if size <= 0 {
+		size = DefaultSchemaCacheSize
+	}
+	return &LRUSchemaCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Load returns the cached schema for a key, and marks it as recently used.
+func (c *LRUSchemaCache) Load(key string) (*CachedSchema, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.order.MoveToFront(e)
+		return e.Value.(*lruEntry).schema, true
+	}
+	return nil, false
+}
+
+// Store adds (or replaces) the cached schema for a key, evicting the least recently used schema when full.
+func (c *LRUSchemaCache) Store(key string, schema *CachedSchema) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*lruEntry).schema = schema
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, schema: schema})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns the number of schemas in the cache.
+func (c *LRUSchemaCache) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.order.Len()
+}
+
+// Clear removes every schema from the cache.
+func (c *LRUSchemaCache) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// schemaCacheKey keys a walked schema by the walk that walked it and its location, so walks sharing a cache never
+// collide.
+func schemaCacheKey(walkId, location string) string {
+	return walkId + "|" + location
+}
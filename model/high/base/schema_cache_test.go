@@ -0,0 +1,35 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLRUSchemaCache(t *testing.T) {
+	cache := NewSchemaCache(2)
+	cache.Store("a", &CachedSchema{Hash: "1"})
+	cache.Store("b", &CachedSchema{Hash: "2"})
+
+	// touch a, so b is the least recently used.
+	_, ok := cache.Load("a")
+	assert.True(t, ok)
+	cache.Store("c", &CachedSchema{Hash: "3"})
+
+	assert.Equal(t, 2, cache.Len())
+	_, ok = cache.Load("b")
+	assert.False(t, ok)
+	c, ok := cache.Load("c")
+	assert.True(t, ok)
+	assert.Equal(t, "3", c.Hash)
+
+	cache.Store("a", &CachedSchema{Hash: "4"})
+	a, _ := cache.Load("a")
+	assert.Equal(t, "4", a.Hash)
+	assert.Equal(t, 2, cache.Len())
+
+	cache.Clear()
+	assert.Equal(t, 0, cache.Len())
+}
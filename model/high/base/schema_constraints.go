@@ -0,0 +1,191 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+import (
+	"fmt"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"strconv"
+	"strings"
+)
+
+// SchemaConstraints is a normalized view of the type and validation keywords of a schema, the differences between
+// OpenAPI 3.0 and 3.1 are folded away: a `null` type and `nullable: true` both set Nullable, and a numeric
+// exclusiveMinimum (or exclusiveMaximum) becomes the Minimum (or Maximum) with ExclusiveMinimum set.
+type SchemaConstraints struct {
+	Types            []string `json:"types,omitempty"`
+	Format           string   `json:"format,omitempty"`
+	Nullable         bool     `json:"nullable,omitempty"`
+	Enum             []string `json:"enum,omitempty"`
+	Const            string   `json:"const,omitempty"`
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum bool     `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum bool     `json:"exclusiveMaximum,omitempty"`
+	MultipleOf       *float64 `json:"multipleOf,omitempty"`
+	MinLength        *int64   `json:"minLength,omitempty"`
+	MaxLength        *int64   `json:"maxLength,omitempty"`
+	Pattern          string   `json:"pattern,omitempty"`
+	MinItems         *int64   `json:"minItems,omitempty"`
+	MaxItems         *int64   `json:"maxItems,omitempty"`
+	UniqueItems      bool     `json:"uniqueItems,omitempty"`
+	MinProperties    *int64   `json:"minProperties,omitempty"`
+	MaxProperties    *int64   `json:"maxProperties,omitempty"`
+
+	// Required are the names of the required properties of the schema.
+	Required   []string `json:"required,omitempty"`
+	ReadOnly   bool     `json:"readOnly,omitempty"`
+	WriteOnly  bool     `json:"writeOnly,omitempty"`
+	Deprecated bool     `json:"deprecated,omitempty"`
+}
+
+// ConstraintSummary returns the normalized constraints of the schema, or nil when the schema has no value.
+func (s *Schema) ConstraintSummary() *SchemaConstraints {
+	if s == nil || s.Value == nil {
+		return nil
+	}
+	v := s.Value
+	c := &SchemaConstraints{
+		Format:        v.Format,
+		Minimum:       v.Minimum,
+		Maximum:       v.Maximum,
+		MultipleOf:    v.MultipleOf,
+		MinLength:     v.MinLength,
+		MaxLength:     v.MaxLength,
+		Pattern:       v.Pattern,
+		MinItems:      v.MinItems,
+		MaxItems:      v.MaxItems,
+		MinProperties: v.MinProperties,
+		MaxProperties: v.MaxProperties,
+		Required:      v.Required,
+		UniqueItems:   v.UniqueItems != nil && *v.UniqueItems,
+		Types:         EffectiveTypes(v),
+		Nullable:      IsNullable(v),
+		ReadOnly:      v.ReadOnly != nil && *v.ReadOnly,
+		WriteOnly:     v.WriteOnly != nil && *v.WriteOnly,
+		Deprecated:    v.Deprecated != nil && *v.Deprecated,
+	}
+	c.Minimum, c.ExclusiveMinimum = exclusiveBound(c.Minimum, v.ExclusiveMinimum)
+	c.Maximum, c.ExclusiveMaximum = exclusiveBound(c.Maximum, v.ExclusiveMaximum)
+	for _, n := range v.Enum {
+		if n.Tag == "!!null" {
+			c.Nullable = true
+			continue
+		}
+		c.Enum = append(c.Enum, n.Value)
+	}
+	if v.Const != nil {
+		c.Const = v.Const.Value
+	}
+	return c
+}
+
+// exclusiveBound applies an exclusiveMinimum (or exclusiveMaximum) to a bound. In 3.0 the keyword is a boolean
+// that makes the bound exclusive, in 3.1 it is the (exclusive) bound itself.
+func exclusiveBound(bound *float64, exclusive *base.DynamicValue[bool, float64]) (*float64, bool) {
+	switch {
+	case exclusive == nil:
+		return bound, false
+	case exclusive.IsA():
+		return bound, exclusive.A && bound != nil
+	default:
+		b := exclusive.B
+		return &b, true
+	}
+}
+
+// String renders the constraints as a single line, for example
+// `string (email), nullable, length 1..64, pattern ^\S+@\S+$, read-only`.
+func (c *SchemaConstraints) String() string {
+	if c == nil {
+		return ""
+	}
+	var parts []string
+	if len(c.Types) > 0 {
+		t := strings.Join(c.Types, " | ")
+		if c.Format != "" {
+			t += " (" + c.Format + ")"
+		}
+		parts = append(parts, t)
+	} else if c.Format != "" {
+		parts = append(parts, "("+c.Format+")")
+	}
+	if c.Nullable {
+		parts = append(parts, "nullable")
+	}
+	if len(c.Enum) > 0 {
+		parts = append(parts, "one of ["+strings.Join(c.Enum, ", ")+"]")
+	}
+	if c.Const != "" {
+		parts = append(parts, "const "+c.Const)
+	}
+	if r := numberRange(c.Minimum, c.Maximum, c.ExclusiveMinimum, c.ExclusiveMaximum); r != "" {
+		parts = append(parts, r)
+	}
+	if c.MultipleOf != nil {
+		parts = append(parts, "multiple of "+formatFloat(*c.MultipleOf))
+	}
+	if r := lengthRange(c.MinLength, c.MaxLength); r != "" {
+		parts = append(parts, "length "+r)
+	}
+	if c.Pattern != "" {
+		parts = append(parts, "pattern "+c.Pattern)
+	}
+	if r := lengthRange(c.MinItems, c.MaxItems); r != "" {
+		parts = append(parts, "items "+r)
+	}
+	if c.UniqueItems {
+		parts = append(parts, "unique items")
+	}
+	if r := lengthRange(c.MinProperties, c.MaxProperties); r != "" {
+		parts = append(parts, "properties "+r)
+	}
+	if len(c.Required) > 0 {
+		parts = append(parts, "requires "+strings.Join(c.Required, ", "))
+	}
+	if c.ReadOnly {
+		parts = append(parts, "read-only")
+	}
+	if c.WriteOnly {
+		parts = append(parts, "write-only")
+	}
+	if c.Deprecated {
+		parts = append(parts, "deprecated")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func numberRange(minimum, maximum *float64, exclusiveMin, exclusiveMax bool) string {
+	bound := func(op string, exclusive bool, v float64) string {
+		if !exclusive {
+			op += "="
+		}
+		return op + " " + formatFloat(v)
+	}
+	switch {
+	case minimum != nil && maximum != nil:
+		return bound(">", exclusiveMin, *minimum) + " and " + bound("<", exclusiveMax, *maximum)
+	case minimum != nil:
+		return bound(">", exclusiveMin, *minimum)
+	case maximum != nil:
+		return bound("<", exclusiveMax, *maximum)
+	}
+	return ""
+}
+
+func lengthRange(minimum, maximum *int64) string {
+	switch {
+	case minimum != nil && maximum != nil:
+		return fmt.Sprintf("%d..%d", *minimum, *maximum)
+	case minimum != nil:
+		return fmt.Sprintf(">= %d", *minimum)
+	case maximum != nil:
+		return fmt.Sprintf("<= %d", *maximum)
+	}
+	return ""
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
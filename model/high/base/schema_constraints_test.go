@@ -0,0 +1,74 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func constraintSchema(t *testing.T, version, name, spec string) *Schema {
+	doc, err := libopenapi.NewDocument([]byte("openapi: " + version + "\ninfo:\n  title: constraints\n  version: 1\n" +
+		"components:\n  schemas:\n" + spec))
+	require.NoError(t, err)
+	v3Doc, errs := doc.BuildV3Model()
+	require.Empty(t, errs)
+	return &Schema{Value: v3Doc.Model.Components.Schemas.GetOrZero(name).Schema()}
+}
+
+func TestSchema_ConstraintSummary(t *testing.T) {
+	s := constraintSchema(t, "3.1.0", "Email", `    Email:
+      type: [string, "null"]
+      format: email
+      minLength: 3
+      maxLength: 64
+      pattern: ^\S+@\S+$
+      readOnly: true`)
+	c := s.ConstraintSummary()
+	assert.Equal(t, []string{"string"}, c.Types)
+	assert.True(t, c.Nullable)
+	assert.True(t, c.ReadOnly)
+	assert.Equal(t, int64(64), *c.MaxLength)
+	assert.Equal(t, `string (email), nullable, length 3..64, pattern ^\S+@\S+$, read-only`, c.String())
+
+	// 3.1 exclusive bounds are numbers.
+	s = constraintSchema(t, "3.1.0", "Price", `    Price:
+      type: number
+      exclusiveMinimum: 0
+      maximum: 100
+      multipleOf: 0.5`)
+	c = s.ConstraintSummary()
+	assert.Equal(t, float64(0), *c.Minimum)
+	assert.True(t, c.ExclusiveMinimum)
+	assert.False(t, c.ExclusiveMaximum)
+	assert.Equal(t, "number, > 0 and <= 100, multiple of 0.5", c.String())
+
+	// 3.0 exclusive bounds are booleans, and nullable is a keyword.
+	s = constraintSchema(t, "3.0.3", "Size", `    Size:
+      type: integer
+      nullable: true
+      minimum: 1
+      exclusiveMinimum: true
+      enum: [1, 2, 3]`)
+	c = s.ConstraintSummary()
+	assert.True(t, c.Nullable)
+	assert.True(t, c.ExclusiveMinimum)
+	assert.Equal(t, []string{"1", "2", "3"}, c.Enum)
+	assert.Equal(t, "integer, nullable, one of [1, 2, 3], > 1", c.String())
+
+	s = constraintSchema(t, "3.1.0", "Order", `    Order:
+      type: object
+      required: [id]
+      minProperties: 1
+      deprecated: true
+      properties:
+        id:
+          type: string`)
+	assert.Equal(t, "object, properties >= 1, requires id, deprecated", s.ConstraintSummary().String())
+
+	assert.Nil(t, (*Schema)(nil).ConstraintSummary())
+	assert.Empty(t, (*SchemaConstraints)(nil).String())
+}
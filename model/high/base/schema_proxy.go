@@ -6,6 +6,7 @@ package base
 import (
 	"context"
 	"fmt"
+	"github.com/pb33f/doctor/telemetry"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 	"github.com/pb33f/libopenapi/datamodel/low"
 	"github.com/pb33f/libopenapi/index"
@@ -57,6 +58,11 @@ func (r *ObjectReference) GetReferenceNode() *yaml.Node {
 }
 
 func (sp *SchemaProxy) IsCircular(ctx context.Context) bool {
+	return sp.circularResult(ctx) != nil
+}
+
+// circularResult returns the circular reference result the proxy is the loop point of, if there is one.
+func (sp *SchemaProxy) circularResult(ctx context.Context) *index.CircularReferenceResult {
 
 	drCtx := ctx.Value("drCtx").(*DrContext)
 	idx := drCtx.Index
@@ -67,28 +73,55 @@ func (sp *SchemaProxy) IsCircular(ctx context.Context) bool {
 	circularRefs = append(circularRefs, arrayRefs...)
 	for _, ref := range circularRefs {
 		if ref.LoopPoint.Definition == sp.Value.GetReference() {
-			return true
+			return ref
 		}
 	}
 
-	return false
+	return nil
+}
+
+// resolve builds the schema of a proxy (resolving its reference, if it has one) in a span of the telemetry of the walk.
+func (sp *SchemaProxy) resolve(ctx context.Context, drCtx *DrContext, schemaProxy *base.SchemaProxy) *base.Schema {
+	if drCtx.Telemetry == nil {
+		return schemaProxy.Schema()
+	}
+	_, span := drCtx.Telemetry.Start(ctx, telemetry.SpanResolveSchema)
+	defer span.End()
+	if schemaProxy.IsReference() {
+		span.SetAttributes(telemetry.String("reference", schemaProxy.GetReference()))
+	}
+	sch := schemaProxy.Schema()
+	if err := schemaProxy.GetBuildError(); err != nil {
+		span.RecordError(err)
+	}
+	return sch
 }
 
 func (sp *SchemaProxy) Walk(ctx context.Context, schemaProxy *base.SchemaProxy, depth int) {
+	if ctx.Err() != nil {
+		return
+	}
+
 	sp.Value = schemaProxy
 	drCtx := ctx.Value("drCtx").(*DrContext)
-	sch := schemaProxy.Schema()
+	sch := sp.resolve(ctx, drCtx, schemaProxy)
 
 	if sch != nil {
 
 		if schemaProxy.IsReference() {
-			if sp.IsCircular(ctx) {
+			if circ := sp.circularResult(ctx); circ != nil {
 				newSchema := &Schema{}
 				newSchema.Parent = sp
 				newSchema.NodeParent = sp.NodeParent
 				sp.Schema = newSchema
 				newSchema.Value = sch
 				newSchema.Name = sp.Key
+				reason := SkipCircular
+				if len(circ.Journey) <= 2 && circ.Start != nil && circ.Start.Definition == circ.LoopPoint.Definition {
+					reason = SkipSelfReference
+				}
+				newSchema.Skip = NewSchemaSkip(reason, schemaProxy.GetReferenceNode(), schemaProxy.GoLow().GetIndex())
+				newSchema.Skip.Reference = schemaProxy.GetReference()
 				drCtx.SkippedSchemaChan <- &WalkedSchema{
 					Schema:     newSchema,
 					SchemaNode: schemaProxy.GetSchemaKeyNode(),
@@ -166,10 +199,22 @@ func (sp *SchemaProxy) Walk(ctx context.Context, schemaProxy *base.SchemaProxy,
 
 	} else {
 		if schemaProxy.GetBuildError() != nil {
-			drCtx.ErrorChan <- &BuildError{
-				SchemaProxy:   schemaProxy,
-				DrSchemaProxy: sp,
-				Error:         schemaProxy.GetBuildError(),
+			drCtx.ErrorChan <- NewBuildError(schemaProxy, sp)
+			if keyNode := schemaProxy.GetSchemaKeyNode(); keyNode != nil {
+				newSchema := &Schema{}
+				newSchema.Parent = sp
+				newSchema.NodeParent = sp.NodeParent
+				newSchema.Name = sp.Key
+				newSchema.KeyNode = sp.KeyNode
+				newSchema.ValueNode = sp.ValueNode
+				newSchema.Skip = NewSchemaSkip(SkipBuildError, keyNode, schemaProxy.GoLow().GetIndex())
+				newSchema.Skip.Reference = schemaProxy.GetReference()
+				newSchema.Skip.Error = schemaProxy.GetBuildError()
+				sp.Schema = newSchema
+				drCtx.SkippedSchemaChan <- &WalkedSchema{
+					Schema:     newSchema,
+					SchemaNode: keyNode,
+				}
 			}
 		}
 	}
@@ -0,0 +1,49 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+import (
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"slices"
+)
+
+// EffectiveTypes returns the types of the schema without null (see EffectiveTypes).
+func (s *Schema) EffectiveTypes() []string {
+	if s == nil {
+		return nil
+	}
+	return EffectiveTypes(s.Value)
+}
+
+// IsNullable returns true if the schema allows null (see IsNullable).
+func (s *Schema) IsNullable() bool {
+	if s == nil {
+		return false
+	}
+	return IsNullable(s.Value)
+}
+
+// EffectiveTypes returns the types a schema allows, without null, in the order they are declared. OpenAPI 3.0
+// schemas have a single type and allow null with `nullable: true`, 3.1 schemas add null to their types, both
+// have the same effective types, use IsNullable to know if null is allowed.
+func EffectiveTypes(schema *base.Schema) []string {
+	if schema == nil {
+		return nil
+	}
+	var types []string
+	for _, t := range schema.Type {
+		if t != "null" && !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// IsNullable returns true if a schema allows null, with `nullable: true` (OpenAPI 3.0) or a null type (3.1).
+func IsNullable(schema *base.Schema) bool {
+	if schema == nil {
+		return false
+	}
+	return schema.Nullable != nil && *schema.Nullable || slices.Contains(schema.Type, "null")
+}
@@ -0,0 +1,54 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSchema_EffectiveTypes(t *testing.T) {
+	schemas := func(spec string) (*Schema, *Schema) {
+		doc, _ := libopenapi.NewDocument([]byte(spec))
+		v3Doc, _ := doc.BuildV3Model()
+		return &Schema{Value: v3Doc.Model.Components.Schemas.GetOrZero("Name").Schema()},
+			&Schema{Value: v3Doc.Model.Components.Schemas.GetOrZero("Id").Schema()}
+	}
+
+	v30Name, v30Id := schemas(`openapi: 3.0.3
+info:
+  title: types
+  version: 1
+components:
+  schemas:
+    Name:
+      type: string
+      nullable: true
+    Id:
+      type: integer`)
+
+	v31Name, v31Id := schemas(`openapi: 3.1.0
+info:
+  title: types
+  version: 1
+components:
+  schemas:
+    Name:
+      type: [string, "null"]
+    Id:
+      type: integer`)
+
+	for _, name := range []*Schema{v30Name, v31Name} {
+		assert.Equal(t, []string{"string"}, name.EffectiveTypes())
+		assert.True(t, name.IsNullable())
+		assert.Equal(t, "string, nullable", name.ConstraintSummary().String())
+	}
+	for _, id := range []*Schema{v30Id, v31Id} {
+		assert.Equal(t, []string{"integer"}, id.EffectiveTypes())
+		assert.False(t, id.IsNullable())
+	}
+	assert.Nil(t, (*Schema)(nil).EffectiveTypes())
+	assert.False(t, (*Schema)(nil).IsNullable())
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+import (
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"slices"
+)
+
+// ViewDirection is the direction a schema is viewed in, the properties of a schema that are sent depend on it.
+type ViewDirection int
+
+const (
+	// ViewRequest is the schema of a request, readOnly properties are not sent in requests.
+	ViewRequest ViewDirection = iota
+
+	// ViewResponse is the schema of a response, writeOnly properties are not sent in responses.
+	ViewResponse
+)
+
+// View returns the schema as it is sent in a direction (see SchemaView), or nil when the schema has no value.
+func (s *Schema) View(direction ViewDirection) *base.Schema {
+	if s == nil {
+		return nil
+	}
+	return SchemaView(s.Value, direction)
+}
+
+// SchemaView returns a copy of a schema with every reference resolved, and the properties that are not sent in a
+// direction removed (from properties and required), at every level of the schema: properties, items,
+// additionalProperties, prefixItems and compositions. Circular schemas produce circular views. The schema is not
+// modified, the view shares everything it does not change with it.
+func SchemaView(schema *base.Schema, direction ViewDirection) *base.Schema {
+	if schema == nil {
+		return nil
+	}
+	return (&schemaViewer{direction: direction, views: make(map[any]*base.Schema)}).view(schema)
+}
+
+type schemaViewer struct {
+	direction ViewDirection
+
+	// views are keyed by the root node of the schema, references build a new schema every time they are resolved.
+	views map[any]*base.Schema
+}
+
+// excluded returns true if a schema is not sent in the direction of the view.
+func (v *schemaViewer) excluded(schema *base.Schema) bool {
+	if schema == nil {
+		return false
+	}
+	if v.direction == ViewRequest {
+		return schema.ReadOnly != nil && *schema.ReadOnly
+	}
+	return schema.WriteOnly != nil && *schema.WriteOnly
+}
+
+func (v *schemaViewer) view(schema *base.Schema) *base.Schema {
+	var key any = schema
+	if low := schema.GoLow(); low != nil && low.RootNode != nil {
+		key = low.RootNode
+	}
+	if view, ok := v.views[key]; ok {
+		return view
+	}
+	view := *schema
+	v.views[key] = &view
+
+	if schema.Properties != nil {
+		properties := orderedmap.New[string, *base.SchemaProxy]()
+		var removed []string
+		for name, proxy := range schema.Properties.FromOldest() {
+			property := proxy.Schema()
+			if v.excluded(property) {
+				removed = append(removed, name)
+				continue
+			}
+			properties.Set(name, v.proxy(proxy))
+		}
+		view.Properties = properties
+		if len(removed) > 0 {
+			view.Required = slices.DeleteFunc(slices.Clone(schema.Required), func(name string) bool {
+				return slices.Contains(removed, name)
+			})
+		}
+	}
+	view.Items = v.dynamicValue(schema.Items)
+	view.AdditionalProperties = v.dynamicValue(schema.AdditionalProperties)
+	view.PrefixItems = v.proxies(schema.PrefixItems)
+	view.AllOf = v.proxies(schema.AllOf)
+	view.OneOf = v.proxies(schema.OneOf)
+	view.AnyOf = v.proxies(schema.AnyOf)
+	return &view
+}
+
+// proxy returns a proxy for the view of the schema of a proxy, proxies that can't be resolved are kept.
+func (v *schemaViewer) proxy(proxy *base.SchemaProxy) *base.SchemaProxy {
+	if proxy == nil {
+		return nil
+	}
+	schema := proxy.Schema()
+	if schema == nil {
+		return proxy
+	}
+	return base.CreateSchemaProxy(v.view(schema))
+}
+
+func (v *schemaViewer) proxies(proxies []*base.SchemaProxy) []*base.SchemaProxy {
+	if proxies == nil {
+		return nil
+	}
+	viewed := make([]*base.SchemaProxy, len(proxies))
+	for i, proxy := range proxies {
+		viewed[i] = v.proxy(proxy)
+	}
+	return viewed
+}
+
+func (v *schemaViewer) dynamicValue(value *base.DynamicValue[*base.SchemaProxy, bool]) *base.DynamicValue[*base.SchemaProxy, bool] {
+	if value == nil || !value.IsA() {
+		return value
+	}
+	return &base.DynamicValue[*base.SchemaProxy, bool]{N: value.N, A: v.proxy(value.A), B: value.B}
+}
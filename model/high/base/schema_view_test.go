@@ -0,0 +1,81 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSchema_View(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: views
+  version: 1
+components:
+  schemas:
+    Burger:
+      type: object
+      required: [id, name, secret]
+      properties:
+        id:
+          type: string
+          readOnly: true
+        name:
+          type: string
+        secret:
+          type: string
+          writeOnly: true
+        toppings:
+          type: array
+          items:
+            $ref: '#/components/schemas/Topping'
+        parent:
+          $ref: '#/components/schemas/Burger'
+    Topping:
+      type: object
+      properties:
+        created:
+          type: string
+          readOnly: true
+        name:
+          type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := doc.BuildV3Model()
+	burger := v3Doc.Model.Components.Schemas.GetOrZero("Burger").Schema()
+
+	names := func(s *base.Schema) []string {
+		var n []string
+		for k := range s.Properties.KeysFromOldest() {
+			n = append(n, k)
+		}
+		return n
+	}
+
+	request := (&Schema{Value: burger}).View(ViewRequest)
+	require.NotNil(t, request)
+	assert.Equal(t, []string{"name", "secret", "toppings", "parent"}, names(request))
+	assert.Equal(t, []string{"name", "secret"}, request.Required)
+	topping := request.Properties.GetOrZero("toppings").Schema().Items.A.Schema()
+	assert.Equal(t, []string{"name"}, names(topping))
+
+	// circular schemas are viewed once.
+	parent := request.Properties.GetOrZero("parent").Schema()
+	assert.Same(t, request, parent)
+
+	response := SchemaView(burger, ViewResponse)
+	assert.Equal(t, []string{"id", "name", "toppings", "parent"}, names(response))
+	assert.Equal(t, []string{"id", "name"}, response.Required)
+	topping = response.Properties.GetOrZero("toppings").Schema().Items.A.Schema()
+	assert.Equal(t, []string{"created", "name"}, names(topping))
+
+	// the schema is not modified.
+	assert.Equal(t, []string{"id", "name", "secret", "toppings", "parent"}, names(burger))
+	assert.Equal(t, []string{"id", "name", "secret"}, burger.Required)
+	assert.Nil(t, (*Schema)(nil).View(ViewRequest))
+}
@@ -0,0 +1,27 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+// Sections is a set of top level sections of a document, used to walk only part of it. The zero value is every
+// section.
+type Sections uint
+
+const (
+	SectionInfo Sections = 1 << iota
+	SectionServers
+	SectionPaths
+	SectionComponents
+	SectionSecurity
+	SectionTags
+	SectionWebhooks
+
+	// AllSections is every section of a document.
+	AllSections = SectionInfo | SectionServers | SectionPaths | SectionComponents | SectionSecurity | SectionTags |
+		SectionWebhooks
+)
+
+// Has returns true if a section is in the set, every section is in an empty set.
+func (s Sections) Has(section Sections) bool {
+	return s == 0 || s&section == section
+}
@@ -14,6 +14,10 @@ type SecurityRequirement struct {
 }
 
 func (s *SecurityRequirement) Walk(ctx context.Context, securityRequirement *base.SecurityRequirement) {
+	if ctx.Err() != nil {
+		return
+	}
+
 	drCtx := GetDrContext(ctx)
 	s.Value = securityRequirement
 	s.PathSegment = "security"
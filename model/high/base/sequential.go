@@ -0,0 +1,69 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+import "slices"
+
+// WaitGroup runs the walks of child objects, and waits for them to complete. A walk fans out with a
+// conc.WaitGroup, a goroutine for each child, or walks sequentially with a SequentialWaitGroup.
+type WaitGroup interface {
+	Go(f func())
+	Wait()
+}
+
+// SequentialWaitGroup is a WaitGroup that runs every function on the calling goroutine, as soon as it is added,
+// for runtimes where goroutines are expensive, such as js/wasm.
+type SequentialWaitGroup struct{}
+
+// Go runs the function immediately.
+func (SequentialWaitGroup) Go(f func()) {
+	f()
+}
+
+// Wait returns immediately, every function has already run.
+func (SequentialWaitGroup) Wait() {}
+
+// OrderedSchemaCache is a SchemaCache that remembers the order schemas were first stored in, so the keys of a
+// sequential walk are deterministic. Schemas are kept in a map with no locks, it is not safe for concurrent use,
+// it is meant for sequential walks, and never evicts schemas.
+type OrderedSchemaCache struct {
+	entries map[string]*CachedSchema
+	order   []string
+}
+
+// NewOrderedSchemaCache creates an empty OrderedSchemaCache.
+func NewOrderedSchemaCache() *OrderedSchemaCache {
+	return &OrderedSchemaCache{entries: make(map[string]*CachedSchema)}
+}
+
+// Load returns the cached schema for a key.
+func (c *OrderedSchemaCache) Load(key string) (*CachedSchema, bool) {
+	s, ok := c.entries[key]
+	return s, ok
+}
+
+// Store adds (or replaces) the cached schema for a key. A replaced schema keeps the position of its key.
+func (c *OrderedSchemaCache) Store(key string, schema *CachedSchema) {
+	if _, ok := c.entries[key]; !ok {
+		c.order = append(c.order, key)
+	}
+	c.entries[key] = schema
+}
+
+// Keys returns the key of every schema in the cache, in the order they were first stored. A sequential walk
+// stores schemas in the same order every time, so the keys are deterministic.
+func (c *OrderedSchemaCache) Keys() []string {
+	return slices.Clone(c.order)
+}
+
+// Len returns the number of schemas in the cache.
+func (c *OrderedSchemaCache) Len() int {
+	return len(c.order)
+}
+
+// Clear removes every schema from the cache.
+func (c *OrderedSchemaCache) Clear() {
+	c.entries = make(map[string]*CachedSchema)
+	c.order = nil
+}
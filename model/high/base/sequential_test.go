@@ -0,0 +1,44 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package base
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOrderedSchemaCache(t *testing.T) {
+	cache := NewOrderedSchemaCache()
+	cache.Store("b", &CachedSchema{Hash: "2"})
+	cache.Store("c", &CachedSchema{Hash: "3"})
+	cache.Store("a", &CachedSchema{Hash: "1"})
+	assert.Equal(t, 3, cache.Len())
+	assert.Equal(t, []string{"b", "c", "a"}, cache.Keys())
+	for key, hash := range map[string]string{"a": "1", "b": "2", "c": "3"} {
+		s, ok := cache.Load(key)
+		assert.True(t, ok)
+		assert.Equal(t, hash, s.Hash)
+	}
+	_, ok := cache.Load("d")
+	assert.False(t, ok)
+
+	cache.Store("b", &CachedSchema{Hash: "4"})
+	b, _ := cache.Load("b")
+	assert.Equal(t, "4", b.Hash)
+	assert.Equal(t, 3, cache.Len())
+	assert.Equal(t, []string{"b", "c", "a"}, cache.Keys())
+
+	cache.Clear()
+	assert.Equal(t, 0, cache.Len())
+	assert.Empty(t, cache.Keys())
+}
+
+func TestSequentialWaitGroup(t *testing.T) {
+	var order []int
+	var wg WaitGroup = SequentialWaitGroup{}
+	wg.Go(func() { order = append(order, 1) })
+	wg.Go(func() { order = append(order, 2) })
+	wg.Wait()
+	assert.Equal(t, []int{1, 2}, order)
+}
@@ -15,6 +15,10 @@ type Tag struct {
 }
 
 func (t *Tag) Walk(ctx context.Context, tag *libopenapibase.Tag) {
+	if ctx.Err() != nil {
+		return
+	}
+
 	t.Value = tag
 	drCtx := GetDrContext(ctx)
 
@@ -17,6 +17,9 @@ type Callback struct {
 }
 
 func (c *Callback) Walk(ctx context.Context, callback *v3.Callback) {
+	if ctx.Err() != nil {
+		return
+	}
 
 	c.Value = callback
 
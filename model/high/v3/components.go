@@ -28,6 +28,9 @@ type Components struct {
 }
 
 func (c *Components) Walk(ctx context.Context, components *v3.Components) {
+	if ctx.Err() != nil {
+		return
+	}
 
 	drCtx := drBase.GetDrContext(ctx)
 	wg := drCtx.WaitGroup
@@ -0,0 +1,213 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package v3
+
+import (
+	"github.com/pb33f/libopenapi/orderedmap"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// ContentType is a media type declared in the content of a request body or response, parsed into its parts.
+type ContentType struct {
+	// Name is the media type as declared, for example `application/json; charset=utf-8` or `application/*`.
+	Name       string
+	Type       string
+	Subtype    string
+	Parameters map[string]string
+
+	// Wildcard is true when the type or subtype is `*`, the media type accepts a range of content types.
+	Wildcard  bool
+	MediaType *MediaType
+}
+
+// Matches checks if a concrete content type (such as a Content-Type header) falls within the media type.
+// Parameters are ignored.
+func (c *ContentType) Matches(contentType string) bool {
+	t, s := splitMediaType(contentType)
+	if t == "" {
+		return false
+	}
+	return (c.Type == "*" || c.Type == t) && (c.Subtype == "*" || c.Subtype == s)
+}
+
+// MediaTypes returns the media types the request body accepts, in the order they are declared.
+func (r *RequestBody) MediaTypes() []*ContentType {
+	if r == nil {
+		return nil
+	}
+	return contentTypes(r.Content)
+}
+
+// MatchContentType returns the media type that describes a Content-Type, an exact match is preferred over
+// a wildcard (`application/*`), which is preferred over `*/*`. Nil is returned when nothing matches.
+func (r *RequestBody) MatchContentType(contentType string) *MediaType {
+	if r == nil {
+		return nil
+	}
+	return matchContentType(r.Content, contentType)
+}
+
+// PickContentType negotiates an Accept header against the request body media types, see Response.PickContentType.
+func (r *RequestBody) PickContentType(accept string) (string, *MediaType) {
+	if r == nil {
+		return "", nil
+	}
+	return pickContentType(r.Content, accept)
+}
+
+// MediaTypes returns the media types the response returns, in the order they are declared.
+func (r *Response) MediaTypes() []*ContentType {
+	if r == nil {
+		return nil
+	}
+	return contentTypes(r.Content)
+}
+
+// MatchContentType returns the media type that describes a Content-Type, an exact match is preferred over
+// a wildcard (`application/*`), which is preferred over `*/*`. Nil is returned when nothing matches.
+func (r *Response) MatchContentType(contentType string) *MediaType {
+	if r == nil {
+		return nil
+	}
+	return matchContentType(r.Content, contentType)
+}
+
+// PickContentType negotiates an Accept header against the response media types (as described by RFC 7231),
+// returning the content type to respond with and the media type that describes it.
+//
+// Media types are ranked by the quality of the most specific Accept range that matches them, ties are broken by
+// the order the media types are declared. When the chosen media type is a wildcard, the matching Accept range is
+// returned as the content type if it is concrete. An empty Accept header accepts anything. When nothing is
+// acceptable, an empty content type and nil are returned.
+func (r *Response) PickContentType(accept string) (string, *MediaType) {
+	if r == nil {
+		return "", nil
+	}
+	return pickContentType(r.Content, accept)
+}
+
+func contentTypes(content *orderedmap.Map[string, *MediaType]) []*ContentType {
+	if content == nil {
+		return nil
+	}
+	var types []*ContentType
+	for name, mt := range content.FromOldest() {
+		ct := &ContentType{Name: name, MediaType: mt, Parameters: make(map[string]string)}
+		ct.Type, ct.Subtype = splitMediaType(name)
+		if _, params, err := mime.ParseMediaType(name); err == nil {
+			ct.Parameters = params
+		}
+		ct.Wildcard = ct.Type == "*" || ct.Subtype == "*"
+		types = append(types, ct)
+	}
+	return types
+}
+
+func matchContentType(content *orderedmap.Map[string, *MediaType], contentType string) *MediaType {
+	var best *ContentType
+	for _, ct := range contentTypes(content) {
+		if ct.Matches(contentType) && (best == nil || specificity(ct.Type, ct.Subtype) > specificity(best.Type, best.Subtype)) {
+			best = ct
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return best.MediaType
+}
+
+// acceptRange is a single media range from an Accept header.
+type acceptRange struct {
+	name    string
+	typ     string
+	subtype string
+	quality float64
+}
+
+func parseAccept(accept string) []*acceptRange {
+	var ranges []*acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.TrimSpace(name)
+		t, s := splitMediaType(name)
+		if t == "" {
+			continue
+		}
+		ar := &acceptRange{name: name, typ: t, subtype: s, quality: 1}
+		for _, p := range strings.Split(params, ";") {
+			k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+			if strings.EqualFold(k, "q") {
+				if q, err := strconv.ParseFloat(v, 64); err == nil {
+					ar.quality = q
+				}
+			}
+		}
+		ranges = append(ranges, ar)
+	}
+	return ranges
+}
+
+func pickContentType(content *orderedmap.Map[string, *MediaType], accept string) (string, *MediaType) {
+	types := contentTypes(content)
+	if len(types) == 0 {
+		return "", nil
+	}
+	ranges := parseAccept(accept)
+	if len(ranges) == 0 {
+		return types[0].Name, types[0].MediaType
+	}
+
+	var chosen *ContentType
+	var chosenRange *acceptRange
+	best := 0.0
+	for _, ct := range types {
+		// the most specific range that matches the media type decides its quality.
+		var match *acceptRange
+		for _, ar := range ranges {
+			if !rangesOverlap(ar.typ, ar.subtype, ct.Type, ct.Subtype) {
+				continue
+			}
+			if match == nil || specificity(ar.typ, ar.subtype) > specificity(match.typ, match.subtype) {
+				match = ar
+			}
+		}
+		if match != nil && match.quality > best {
+			chosen, chosenRange, best = ct, match, match.quality
+		}
+	}
+	if chosen == nil {
+		return "", nil
+	}
+	if chosen.Wildcard && specificity(chosenRange.typ, chosenRange.subtype) == 2 {
+		return chosenRange.name, chosen.MediaType
+	}
+	return chosen.Name, chosen.MediaType
+}
+
+// splitMediaType returns the lower case type and subtype of a media type, ignoring parameters.
+func splitMediaType(mediaType string) (string, string) {
+	name, _, _ := strings.Cut(mediaType, ";")
+	t, s, ok := strings.Cut(strings.ToLower(strings.TrimSpace(name)), "/")
+	if !ok || t == "" || s == "" {
+		return "", ""
+	}
+	return t, s
+}
+
+func rangesOverlap(t1, s1, t2, s2 string) bool {
+	return (t1 == "*" || t2 == "*" || t1 == t2) && (s1 == "*" || s2 == "*" || s1 == s2)
+}
+
+func specificity(t, s string) int {
+	switch {
+	case t == "*":
+		return 0
+	case s == "*":
+		return 1
+	default:
+		return 2
+	}
+}
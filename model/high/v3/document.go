@@ -39,12 +39,13 @@ func (d *Document) Walk(ctx context.Context, doc *v3.Document) {
 	n.Width = 50
 	n.Height = 50
 	n.Label = "Document"
+	n.Cluster = base.ClusterDocument
 	drCtx.NodeChan <- n
 
 	//d.BuildNodesAndEdges(ctx, "document")
 	negOne := -1
 
-	if doc.Info != nil {
+	if doc.Info != nil && drCtx.Sections.Has(base.SectionInfo) {
 		d.Info = &base.Info{}
 		d.Info.Parent = d
 		d.Info.NodeParent = d
@@ -53,7 +54,7 @@ func (d *Document) Walk(ctx context.Context, doc *v3.Document) {
 		wg.Go(func() { d.Info.Walk(ctx, doc.Info) })
 	}
 
-	if doc.Servers != nil && len(doc.Servers) > 0 {
+	if doc.Servers != nil && len(doc.Servers) > 0 && drCtx.Sections.Has(base.SectionServers) {
 		serversNode := &base.Foundation{
 			Parent:      d,
 			NodeParent:  d,
@@ -81,7 +82,28 @@ func (d *Document) Walk(ctx context.Context, doc *v3.Document) {
 		}
 	}
 
-	if doc.Paths != nil && doc.Paths.PathItems != nil && doc.Paths.PathItems.Len() > 0 {
+	walkComponents := func() {
+		if doc.Components != nil && drCtx.Sections.Has(base.SectionComponents) {
+			c := &Components{}
+			c.Parent = d
+			c.NodeParent = d
+			c.ValueNode = base.ExtractValueNodeForLowModel(doc.GoLow().Components)
+			c.KeyNode = base.ExtractKeyNodeForLowModel(doc.GoLow().Components)
+			wg.Go(func() { c.Walk(ctx, doc.Components) })
+			d.Components = c
+		}
+	}
+
+	// a sequential walk runs each section to completion before the next, and a schema is cached where it is first
+	// walked. components are walked before paths, so schemas are found at their definitions rather than their
+	// first use. sections of a concurrent walk run at once, so their order makes no difference.
+	_, sequential := wg.(base.SequentialWaitGroup)
+	if sequential {
+		walkComponents()
+	}
+
+	if doc.Paths != nil && doc.Paths.PathItems != nil && doc.Paths.PathItems.Len() > 0 &&
+		drCtx.Sections.Has(base.SectionPaths) {
 		p := &Paths{}
 		p.Parent = d
 		p.NodeParent = d
@@ -93,17 +115,11 @@ func (d *Document) Walk(ctx context.Context, doc *v3.Document) {
 		d.Paths = p
 	}
 
-	if doc.Components != nil {
-		c := &Components{}
-		c.Parent = d
-		c.NodeParent = d
-		c.ValueNode = base.ExtractValueNodeForLowModel(doc.GoLow().Components)
-		c.KeyNode = base.ExtractKeyNodeForLowModel(doc.GoLow().Components)
-		wg.Go(func() { c.Walk(ctx, doc.Components) })
-		d.Components = c
+	if !sequential {
+		walkComponents()
 	}
 
-	if doc.Security != nil {
+	if doc.Security != nil && drCtx.Sections.Has(base.SectionSecurity) {
 
 		secNode := &base.Foundation{
 			Parent:      d,
@@ -168,7 +184,7 @@ func (d *Document) Walk(ctx context.Context, doc *v3.Document) {
 		drCtx.ObjectChan <- ed
 	}
 
-	if doc.Tags != nil {
+	if doc.Tags != nil && drCtx.Sections.Has(base.SectionTags) {
 		tagsNode := &base.Foundation{
 			Parent:      d,
 			NodeParent:  d,
@@ -198,7 +214,7 @@ func (d *Document) Walk(ctx context.Context, doc *v3.Document) {
 
 	}
 
-	if doc.Webhooks != nil {
+	if doc.Webhooks != nil && drCtx.Sections.Has(base.SectionWebhooks) {
 
 		webhooks := orderedmap.New[string, *PathItem]()
 		webhookNode := &base.Foundation{
@@ -329,6 +345,17 @@ func (d *Document) buildRenderedNode() {
 		}
 	}
 
+	if d.Webhooks != nil && d.Webhooks.Len() > 0 {
+		m["webhooks"] = d.Webhooks.Len()
+		if first := d.Webhooks.First(); first != nil && first.Value().NodeParent != nil {
+			if x, ok := first.Value().NodeParent.(base.Foundational); ok {
+				if x.GetNode() != nil {
+					m["webhooks_idhash"] = x.GetNode().IdHash
+				}
+			}
+		}
+	}
+
 	if d.Document.GoLow().Extensions != nil {
 		m["extensions"] = d.Document.GoLow().Extensions.Len()
 	}
@@ -345,6 +372,7 @@ func (d *Document) GetSize() (height, width int) {
 		d.Components,
 		d.Security,
 		d.Tags,
+		d.Webhooks,
 	}
 	for _, item := range items {
 		height = base.AddChunkDefaultHeight(item, height)
@@ -17,6 +17,9 @@ type Encoding struct {
 }
 
 func (e *Encoding) Walk(ctx context.Context, encoding *v3.Encoding) {
+	if ctx.Err() != nil {
+		return
+	}
 
 	drCtx := base.GetDrContext(ctx)
 	wg := drCtx.WaitGroup
@@ -29,6 +32,7 @@ func (e *Encoding) Walk(ctx context.Context, encoding *v3.Encoding) {
 		for headerPairs := encoding.Headers.First(); headerPairs != nil; headerPairs = headerPairs.Next() {
 			h := &Header{}
 			h.Parent = e
+			h.PathSegment = "headers"
 			h.Key = headerPairs.Key()
 			for lowHeadPairs := encoding.GoLow().Headers.Value.First(); lowHeadPairs != nil; lowHeadPairs = lowHeadPairs.Next() {
 				if lowHeadPairs.Key().Value == h.Key {
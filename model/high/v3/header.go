@@ -21,6 +21,9 @@ type Header struct {
 }
 
 func (h *Header) Walk(ctx context.Context, header *v3.Header) {
+	if ctx.Err() != nil {
+		return
+	}
 
 	drCtx := base.GetDrContext(ctx)
 	wg := drCtx.WaitGroup
@@ -16,6 +16,9 @@ type Link struct {
 }
 
 func (l *Link) Walk(ctx context.Context, link *v3.Link) {
+	if ctx.Err() != nil {
+		return
+	}
 
 	l.Value = link
 	l.PathSegment = "links"
@@ -19,6 +19,9 @@ type MediaType struct {
 }
 
 func (m *MediaType) Walk(ctx context.Context, mediaType *v3.MediaType) {
+	if ctx.Err() != nil {
+		return
+	}
 
 	drCtx := drBase.GetDrContext(ctx)
 	wg := drCtx.WaitGroup
@@ -84,6 +87,7 @@ func (m *MediaType) Walk(ctx context.Context, mediaType *v3.MediaType) {
 			wg.Go(func() { e.Walk(ctx, v) })
 			encoding.Set(encodingPairs.Key(), e)
 		}
+		m.Encoding = encoding
 	}
 
 	if mediaType.GoLow().IsReference() {
@@ -0,0 +1,117 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package v3
+
+import (
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"strings"
+)
+
+// PropertyEncoding is how a property of a multipart or form media type is encoded.
+type PropertyEncoding struct {
+	// Property is the name of the property.
+	Property string
+
+	// Schema is the schema of the property, nil when the schema of the media type does not define the property.
+	// Schemas are shared by every place they are referenced from, so the schema is not a doctor model.
+	Schema *base.SchemaProxy
+
+	// Encoding is the encoding of the property, nil when the property is encoded with the defaults.
+	Encoding *Encoding
+
+	// ContentType is the content type of the encoding, or the default content type for the schema of the property
+	// when the encoding does not define one.
+	ContentType string
+
+	// Headers are the headers of the encoding, they are only sent by multipart media types.
+	Headers *orderedmap.Map[string, *Header]
+}
+
+// IsFormEncoded returns true if the media type is multipart or application/x-www-form-urlencoded, the only media
+// types encodings apply to.
+func (m *MediaType) IsFormEncoded() bool {
+	key := strings.ToLower(m.Key)
+	return strings.HasPrefix(key, "multipart/") || strings.HasPrefix(key, "application/x-www-form-urlencoded")
+}
+
+// PropertyEncodings returns the encoding of every property of a multipart or form media type: the properties of
+// its schema (including the properties of allOf schemas) in the order they are declared, then encodings for
+// properties the schema does not define. It returns nil for other media types.
+func (m *MediaType) PropertyEncodings() []*PropertyEncoding {
+	if m == nil || !m.IsFormEncoded() {
+		return nil
+	}
+	var encodings []*PropertyEncoding
+	seen := make(map[string]bool)
+	visited := make(map[*base.Schema]bool)
+	var properties func(proxy *base.SchemaProxy)
+	properties = func(proxy *base.SchemaProxy) {
+		schema := schemaOf(proxy)
+		if schema == nil || visited[schema] {
+			return
+		}
+		visited[schema] = true
+		if schema.Properties != nil {
+			for name, property := range schema.Properties.FromOldest() {
+				if !seen[name] {
+					seen[name] = true
+					encodings = append(encodings, m.propertyEncoding(name, property))
+				}
+			}
+		}
+		for _, all := range schema.AllOf {
+			properties(all)
+		}
+	}
+	if m.Value != nil {
+		properties(m.Value.Schema)
+	}
+
+	if m.Encoding != nil {
+		for name := range m.Encoding.KeysFromOldest() {
+			if !seen[name] {
+				encodings = append(encodings, m.propertyEncoding(name, nil))
+			}
+		}
+	}
+	return encodings
+}
+
+func (m *MediaType) propertyEncoding(name string, property *base.SchemaProxy) *PropertyEncoding {
+	pe := &PropertyEncoding{Property: name, Schema: property}
+	if m.Encoding != nil {
+		pe.Encoding = m.Encoding.GetOrZero(name)
+	}
+	if pe.Encoding != nil && pe.Encoding.Value != nil {
+		pe.ContentType = pe.Encoding.Value.ContentType
+		pe.Headers = pe.Encoding.Headers
+	}
+	if pe.ContentType == "" && property != nil {
+		pe.ContentType = defaultEncodingContentType(property.Schema())
+	}
+	return pe
+}
+
+// defaultEncodingContentType returns the content type a property is encoded with when its encoding does not
+// define one: binary strings are application/octet-stream, other primitives are text/plain, objects are
+// application/json and arrays are encoded as their items.
+func defaultEncodingContentType(schema *base.Schema) string {
+	switch schemaType(schema) {
+	case "string":
+		if schema.Format == "binary" || schema.Format == "base64" {
+			return "application/octet-stream"
+		}
+		return "text/plain"
+	case "integer", "number", "boolean":
+		return "text/plain"
+	case "object":
+		return "application/json"
+	case "array":
+		if schema.Items != nil && schema.Items.IsA() {
+			return defaultEncodingContentType(schemaOf(schema.Items.A))
+		}
+	}
+	return "application/octet-stream"
+}
@@ -20,6 +20,10 @@ type OAuthFlows struct {
 }
 
 func (o *OAuthFlows) Walk(ctx context.Context, flows *v3.OAuthFlows) {
+	if ctx.Err() != nil {
+		return
+	}
+
 	o.Value = flows
 	drCtx := drBase.GetDrContext(ctx)
 
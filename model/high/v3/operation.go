@@ -27,6 +27,9 @@ type Operation struct {
 }
 
 func (o *Operation) Walk(ctx context.Context, operation *v3.Operation) {
+	if ctx.Err() != nil {
+		return
+	}
 
 	drCtx := drBase.GetDrContext(ctx)
 	wg := drCtx.WaitGroup
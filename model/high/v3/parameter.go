@@ -20,6 +20,9 @@ type Parameter struct {
 }
 
 func (p *Parameter) Walk(ctx context.Context, param *v3.Parameter) {
+	if ctx.Err() != nil {
+		return
+	}
 
 	drCtx := drBase.GetDrContext(ctx)
 	wg := drCtx.WaitGroup
@@ -0,0 +1,630 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package v3
+
+import (
+	"encoding/json"
+	"fmt"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"net/url"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Parameter styles, as defined by the specification.
+const (
+	StyleMatrix         = "matrix"
+	StyleLabel          = "label"
+	StyleForm           = "form"
+	StyleSimple         = "simple"
+	StyleSpaceDelimited = "spaceDelimited"
+	StylePipeDelimited  = "pipeDelimited"
+	StyleDeepObject     = "deepObject"
+)
+
+// Shapes of parameter values, styles serialize each shape differently.
+const (
+	ShapePrimitive = "primitive"
+	ShapeArray     = "array"
+	ShapeObject    = "object"
+)
+
+// locationStyles are the styles each parameter location supports, the first is the default.
+var locationStyles = map[string][]string{
+	"query":  {StyleForm, StyleSpaceDelimited, StylePipeDelimited, StyleDeepObject},
+	"path":   {StyleSimple, StyleMatrix, StyleLabel},
+	"header": {StyleSimple},
+	"cookie": {StyleForm},
+}
+
+// SerializationPlan describes how the value of a parameter is serialized, with the defaults of the specification
+// resolved: the style defaults to the style of the location of the parameter, and explode defaults to true for
+// the form style only. Encode and Decode serialize values with the plan.
+type SerializationPlan struct {
+	// Name and In are the name and location of the parameter.
+	Name string
+	In   string
+
+	// Style and Explode are the effective style and explode of the parameter, the style is empty when the
+	// parameter is serialized with a media type.
+	Style   string
+	Explode bool
+
+	// AllowReserved is true when reserved characters of query values are not percent encoded.
+	AllowReserved bool
+
+	// ContentType is the media type a parameter with content is serialized with.
+	ContentType string
+
+	// Shape is the shape of the value: ShapePrimitive, ShapeArray or ShapeObject.
+	Shape string
+
+	// Type is the schema type of a primitive value, or of the items of an array value.
+	Type string
+
+	// Properties are the schema types of the properties of an object value, in the order they are declared.
+	Properties *orderedmap.Map[string, string]
+}
+
+// SerializationPlan returns the plan the value of the parameter is serialized with, or nil when there is no
+// parameter.
+func (p *Parameter) SerializationPlan() *SerializationPlan {
+	if p == nil || p.Value == nil {
+		return nil
+	}
+	param := p.Value
+	plan := &SerializationPlan{Name: param.Name, In: param.In, AllowReserved: param.AllowReserved && param.In == "query"}
+
+	if param.Content != nil && param.Content.Len() > 0 {
+		for contentType, mediaType := range param.Content.FromOldest() {
+			plan.ContentType = contentType
+			plan.Shape, plan.Type, plan.Properties = schemaShape(mediaType.Schema)
+			break
+		}
+		return plan
+	}
+
+	plan.Style = param.Style
+	if plan.Style == "" {
+		if styles, ok := locationStyles[param.In]; ok {
+			plan.Style = styles[0]
+		}
+	}
+	plan.Explode = plan.Style == StyleForm
+	if param.Explode != nil {
+		plan.Explode = *param.Explode
+	}
+	plan.Shape, plan.Type, plan.Properties = schemaShape(param.Schema)
+	return plan
+}
+
+// schemaShape returns the shape of the values of a schema, the type of primitives or of array items, and the
+// types of object properties.
+func schemaShape(proxy *base.SchemaProxy) (shape, typ string, properties *orderedmap.Map[string, string]) {
+	schema := schemaOf(proxy)
+	if schema == nil {
+		return ShapePrimitive, "", nil
+	}
+	switch schemaType(schema) {
+	case "array":
+		var items *base.Schema
+		if schema.Items != nil && schema.Items.IsA() {
+			items = schemaOf(schema.Items.A)
+		}
+		return ShapeArray, schemaType(items), nil
+	case "object":
+		properties = orderedmap.New[string, string]()
+		if schema.Properties != nil {
+			for name, property := range schema.Properties.FromOldest() {
+				properties.Set(name, schemaType(schemaOf(property)))
+			}
+		}
+		return ShapeObject, "", properties
+	}
+	return ShapePrimitive, schemaType(schema), nil
+}
+
+func schemaOf(proxy *base.SchemaProxy) *base.Schema {
+	if proxy == nil {
+		return nil
+	}
+	return proxy.Schema()
+}
+
+// schemaType returns the type of a schema that is not null, inferring arrays and objects from their keywords.
+func schemaType(schema *base.Schema) string {
+	if schema == nil {
+		return ""
+	}
+	if types := drBase.EffectiveTypes(schema); len(types) > 0 {
+		return types[0]
+	}
+	if schema.Items != nil {
+		return "array"
+	}
+	if schema.Properties != nil && schema.Properties.Len() > 0 {
+		return "object"
+	}
+	return ""
+}
+
+// validate returns an error when the style of the plan can't serialize values of its shape in its location.
+func (s *SerializationPlan) validate() error {
+	if s.ContentType != "" {
+		return nil
+	}
+	if !slices.Contains(locationStyles[s.In], s.Style) {
+		return fmt.Errorf("parameter '%s': style '%s' is not supported in '%s'", s.Name, s.Style, s.In)
+	}
+	switch s.Style {
+	case StyleDeepObject:
+		if s.Shape != ShapeObject {
+			return fmt.Errorf("parameter '%s': style '%s' only serializes objects", s.Name, s.Style)
+		}
+	case StyleSpaceDelimited, StylePipeDelimited:
+		if s.Shape == ShapePrimitive {
+			return fmt.Errorf("parameter '%s': style '%s' does not serialize primitives", s.Name, s.Style)
+		}
+	}
+	return nil
+}
+
+// Encode serializes a value of the parameter. Query and cookie values are encoded as the pairs of the query string
+// or cookie (name=value), path values as the text that replaces the template expression of the path (including the
+// prefix of matrix and label styles) and header values as the value of the header. Arrays are slices, objects are
+// maps keyed by strings; object properties are encoded in the order they are declared, then by name.
+func (s *SerializationPlan) Encode(value any) (string, error) {
+	if s == nil {
+		return "", fmt.Errorf("no serialization plan")
+	}
+	if err := s.validate(); err != nil {
+		return "", err
+	}
+	if s.ContentType != "" {
+		var encoded string
+		if strings.Contains(s.ContentType, "json") {
+			b, err := json.Marshal(value)
+			if err != nil {
+				return "", fmt.Errorf("parameter '%s': %w", s.Name, err)
+			}
+			encoded = string(b)
+		} else {
+			encoded = fmt.Sprint(value)
+		}
+		if s.In == "query" || s.In == "cookie" {
+			return s.escape(s.Name) + "=" + s.escape(encoded), nil
+		}
+		return s.escape(encoded), nil
+	}
+
+	items, keys, values := s.flatten(value)
+	name := s.escape(s.Name)
+
+	switch s.Style {
+	case StyleMatrix:
+		if !s.Explode || keys == nil && len(items) == 1 {
+			return ";" + name + "=" + strings.Join(s.pairs(items, keys, values, ","), ","), nil
+		}
+		if keys != nil {
+			return ";" + strings.Join(s.pairs(nil, keys, values, "="), ";"), nil
+		}
+		return ";" + name + "=" + strings.Join(items, ";"+name+"="), nil
+
+	case StyleLabel:
+		if s.Explode {
+			return "." + strings.Join(s.pairs(items, keys, values, "="), "."), nil
+		}
+		return "." + strings.Join(s.pairs(items, keys, values, ","), ","), nil
+
+	case StyleSimple:
+		if s.Explode {
+			return strings.Join(s.pairs(items, keys, values, "="), ","), nil
+		}
+		return strings.Join(s.pairs(items, keys, values, ","), ","), nil
+
+	case StyleForm:
+		if !s.Explode {
+			return name + "=" + strings.Join(s.pairs(items, keys, values, ","), ","), nil
+		}
+		if keys != nil {
+			return strings.Join(s.pairs(nil, keys, values, "="), "&"), nil
+		}
+		if len(items) == 0 {
+			return name + "=", nil
+		}
+		return name + "=" + strings.Join(items, "&"+name+"="), nil
+
+	case StyleSpaceDelimited, StylePipeDelimited:
+		delimiter := "%20"
+		if s.Style == StylePipeDelimited {
+			delimiter = "|"
+		}
+		if s.Explode && keys == nil {
+			return name + "=" + strings.Join(items, "&"+name+"="), nil
+		}
+		return name + "=" + strings.Join(s.pairs(items, keys, values, delimiter), delimiter), nil
+
+	case StyleDeepObject:
+		pairs := make([]string, 0, len(keys))
+		for i, k := range keys {
+			pairs = append(pairs, name+"["+k+"]="+values[i])
+		}
+		return strings.Join(pairs, "&"), nil
+	}
+	return "", fmt.Errorf("parameter '%s': style '%s' is not supported", s.Name, s.Style)
+}
+
+// flatten returns the escaped items of array or primitive values, or the escaped keys and values of objects (keys
+// is nil for values that are not objects).
+func (s *SerializationPlan) flatten(value any) (items, keys, values []string) {
+	if value == nil {
+		return nil, nil, nil
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			for i := 0; i < v.Len(); i++ {
+				items = append(items, s.escape(fmt.Sprint(v.Index(i).Interface())))
+			}
+			return items, nil, nil
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() == reflect.String {
+			names := make([]string, 0, v.Len())
+			for _, k := range v.MapKeys() {
+				names = append(names, k.String())
+			}
+			sort.SliceStable(names, func(i, j int) bool {
+				return s.propertyRank(names[i]) < s.propertyRank(names[j]) ||
+					s.propertyRank(names[i]) == s.propertyRank(names[j]) && names[i] < names[j]
+			})
+			keys = make([]string, 0, len(names))
+			for _, k := range names {
+				keys = append(keys, s.escape(k))
+				values = append(values, s.escape(fmt.Sprint(v.MapIndex(reflect.ValueOf(k).Convert(v.Type().Key())).Interface())))
+			}
+			return nil, keys, values
+		}
+	}
+	return []string{s.escape(fmt.Sprint(value))}, nil, nil
+}
+
+// propertyRank returns the position a property is declared at, undeclared properties come last.
+func (s *SerializationPlan) propertyRank(name string) int {
+	rank := 0
+	if s.Properties != nil {
+		for k := range s.Properties.KeysFromOldest() {
+			if k == name {
+				return rank
+			}
+			rank++
+		}
+	}
+	return rank
+}
+
+// pairs returns the items, or the keys and values of an object joined by the separator.
+func (s *SerializationPlan) pairs(items, keys, values []string, separator string) []string {
+	if keys == nil {
+		return items
+	}
+	pairs := make([]string, 0, len(keys))
+	for i, k := range keys {
+		pairs = append(pairs, k+separator+values[i])
+	}
+	return pairs
+}
+
+// escape percent encodes text for the location of the parameter. Header and cookie values are not encoded, query
+// values keep reserved characters when they are allowed.
+func (s *SerializationPlan) escape(text string) string {
+	switch s.In {
+	case "path":
+		return url.PathEscape(text)
+	case "query":
+		if s.AllowReserved {
+			var b strings.Builder
+			for _, c := range []byte(text) {
+				if c > ' ' && c < 0x7f && !strings.ContainsRune(`"%<>\^`+"`{|}", rune(c)) {
+					b.WriteByte(c)
+				} else {
+					fmt.Fprintf(&b, "%%%02X", c)
+				}
+			}
+			return b.String()
+		}
+		return strings.ReplaceAll(url.QueryEscape(text), "+", "%20")
+	}
+	return text
+}
+
+func (s *SerializationPlan) unescape(text string) (string, error) {
+	switch s.In {
+	case "path":
+		return url.PathUnescape(text)
+	case "query":
+		if s.AllowReserved {
+			return url.PathUnescape(text)
+		}
+		return url.QueryUnescape(text)
+	}
+	return text, nil
+}
+
+// Decode parses a serialized value of the parameter: the query string or cookie header for query and cookie
+// parameters, the text that replaced the template expression of the path for path parameters, or the value of the
+// header. Primitives are converted to the type of their schema (int64, float64, bool or string), arrays are
+// returned as []any and objects as map[string]any. Decode returns nil when a query or cookie parameter is missing.
+func (s *SerializationPlan) Decode(raw string) (any, error) {
+	if s == nil {
+		return nil, fmt.Errorf("no serialization plan")
+	}
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+
+	var pairs [][2]string
+	if s.In == "query" || s.In == "cookie" {
+		var err error
+		if pairs, err = s.parsePairs(raw); err != nil {
+			return nil, err
+		}
+	}
+	lookup := func(name string) ([]string, bool) {
+		var found []string
+		for _, p := range pairs {
+			if p[0] == name {
+				found = append(found, p[1])
+			}
+		}
+		return found, len(found) > 0
+	}
+
+	if s.ContentType != "" {
+		text := raw
+		if s.In == "query" || s.In == "cookie" {
+			found, ok := lookup(s.Name)
+			if !ok {
+				return nil, nil
+			}
+			text = found[0]
+		}
+		text, err := s.unescape(text)
+		if err != nil {
+			return nil, fmt.Errorf("parameter '%s': %w", s.Name, err)
+		}
+		if !strings.Contains(s.ContentType, "json") {
+			return text, nil
+		}
+		var value any
+		if err := json.Unmarshal([]byte(text), &value); err != nil {
+			return nil, fmt.Errorf("parameter '%s': %w", s.Name, err)
+		}
+		return value, nil
+	}
+
+	switch s.Style {
+	case StyleForm, StyleSpaceDelimited, StylePipeDelimited:
+		if s.Shape == ShapeObject && s.Explode {
+			var segments []string
+			for _, p := range pairs {
+				declared := s.Properties == nil || s.Properties.Len() == 0
+				if !declared {
+					_, declared = s.Properties.Get(p[0])
+				}
+				if declared {
+					segments = append(segments, url.QueryEscape(p[0])+"="+p[1])
+				}
+			}
+			if len(segments) == 0 {
+				return nil, nil
+			}
+			return s.keyValues(segments)
+		}
+		found, ok := lookup(s.Name)
+		if !ok {
+			return nil, nil
+		}
+		if s.Shape == ShapeArray && s.Explode {
+			return s.shapedEscaped(strings.Join(found, ","), ",")
+		}
+		switch s.Style {
+		case StyleSpaceDelimited:
+			if !s.AllowReserved {
+				found[0] = strings.ReplaceAll(found[0], "+", "%20")
+			}
+			return s.shapedEscaped(found[0], "%20")
+		case StylePipeDelimited:
+			return s.shapedEscaped(strings.ReplaceAll(found[0], "%7C", "|"), "|")
+		}
+		return s.shapedEscaped(found[0], ",")
+
+	case StyleDeepObject:
+		var segments []string
+		prefix := s.Name + "["
+		for _, p := range pairs {
+			if strings.HasPrefix(p[0], prefix) && strings.HasSuffix(p[0], "]") {
+				segments = append(segments, url.QueryEscape(p[0][len(prefix):len(p[0])-1])+"="+p[1])
+			}
+		}
+		if len(segments) == 0 {
+			return nil, nil
+		}
+		return s.keyValues(segments)
+
+	case StyleMatrix:
+		if !strings.HasPrefix(raw, ";") {
+			return nil, fmt.Errorf("parameter '%s': matrix value '%s' does not start with ';'", s.Name, raw)
+		}
+		segments := strings.Split(raw[1:], ";")
+		if s.Explode && s.Shape == ShapeObject {
+			return s.keyValues(segments)
+		}
+		var values []string
+		for _, segment := range segments {
+			k, v, _ := strings.Cut(segment, "=")
+			if k != url.PathEscape(s.Name) {
+				return nil, fmt.Errorf("parameter '%s': unexpected matrix parameter '%s'", s.Name, k)
+			}
+			values = append(values, v)
+		}
+		return s.shapedEscaped(strings.Join(values, ","), ",")
+
+	case StyleLabel:
+		if !strings.HasPrefix(raw, ".") {
+			return nil, fmt.Errorf("parameter '%s': label value '%s' does not start with '.'", s.Name, raw)
+		}
+		if !s.Explode {
+			return s.shapedEscaped(raw[1:], ",")
+		}
+		if s.Shape == ShapeObject {
+			return s.keyValues(strings.Split(raw[1:], "."))
+		}
+		return s.shapedEscaped(strings.ReplaceAll(raw[1:], ".", ","), ",")
+
+	case StyleSimple:
+		if s.Explode && s.Shape == ShapeObject {
+			return s.keyValues(strings.Split(raw, ","))
+		}
+		return s.shapedEscaped(raw, ",")
+	}
+	return nil, fmt.Errorf("parameter '%s': style '%s' is not supported", s.Name, s.Style)
+}
+
+// parsePairs splits a query string or a cookie header into pairs of unescaped names and escaped values, values
+// are unescaped once they are split.
+func (s *SerializationPlan) parsePairs(raw string) ([][2]string, error) {
+	separators := "&"
+	if s.In == "cookie" {
+		separators = ";&"
+	}
+	var pairs [][2]string
+	for _, pair := range strings.FieldsFunc(strings.TrimPrefix(raw, "?"), func(r rune) bool {
+		return strings.ContainsRune(separators, r)
+	}) {
+		k, v, _ := strings.Cut(strings.TrimSpace(pair), "=")
+		name, err := s.unescape(k)
+		if err != nil {
+			return nil, fmt.Errorf("parameter '%s': %w", s.Name, err)
+		}
+		pairs = append(pairs, [2]string{name, v})
+	}
+	return pairs, nil
+}
+
+// split splits an escaped value and unescapes each segment.
+func (s *SerializationPlan) split(raw, separator string) ([]string, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	segments := strings.Split(raw, separator)
+	for i, segment := range segments {
+		unescaped, err := s.unescape(segment)
+		if err != nil {
+			return nil, fmt.Errorf("parameter '%s': %w", s.Name, err)
+		}
+		segments[i] = unescaped
+	}
+	return segments, nil
+}
+
+// shapedEscaped splits an escaped value and shapes the unescaped segments.
+func (s *SerializationPlan) shapedEscaped(raw, separator string) (any, error) {
+	segments, err := s.split(raw, separator)
+	if err != nil {
+		return nil, err
+	}
+	return s.shaped(segments)
+}
+
+// shaped returns the segments of a value as the shape of the plan: objects alternate keys and values.
+func (s *SerializationPlan) shaped(segments []string) (any, error) {
+	switch s.Shape {
+	case ShapeArray:
+		return s.array(segments)
+	case ShapeObject:
+		if len(segments)%2 != 0 {
+			return nil, fmt.Errorf("parameter '%s': object value has a key without a value", s.Name)
+		}
+		object := make(map[string]string)
+		for i := 0; i < len(segments); i += 2 {
+			object[segments[i]] = segments[i+1]
+		}
+		return s.object(object)
+	}
+	return coerce(s.Name, strings.Join(segments, ","), s.Type)
+}
+
+// keyValues returns an object from escaped key=value segments.
+func (s *SerializationPlan) keyValues(segments []string) (any, error) {
+	object := make(map[string]string)
+	for _, segment := range segments {
+		k, v, ok := strings.Cut(segment, "=")
+		if !ok {
+			return nil, fmt.Errorf("parameter '%s': object property '%s' has no value", s.Name, segment)
+		}
+		key, err := s.unescape(k)
+		if err != nil {
+			return nil, fmt.Errorf("parameter '%s': %w", s.Name, err)
+		}
+		if object[key], err = s.unescape(v); err != nil {
+			return nil, fmt.Errorf("parameter '%s': %w", s.Name, err)
+		}
+	}
+	return s.object(object)
+}
+
+func (s *SerializationPlan) array(values []string) (any, error) {
+	array := make([]any, 0, len(values))
+	for _, v := range values {
+		item, err := coerce(s.Name, v, s.Type)
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, item)
+	}
+	return array, nil
+}
+
+func (s *SerializationPlan) object(values map[string]string) (any, error) {
+	object := make(map[string]any, len(values))
+	for k, v := range values {
+		var typ string
+		if s.Properties != nil {
+			typ, _ = s.Properties.Get(k)
+		}
+		property, err := coerce(s.Name, v, typ)
+		if err != nil {
+			return nil, err
+		}
+		object[k] = property
+	}
+	return object, nil
+}
+
+// coerce converts a value to the primitive type of its schema.
+func coerce(name, value, typ string) (any, error) {
+	var converted any
+	var err error
+	switch typ {
+	case "integer":
+		converted, err = strconv.ParseInt(value, 10, 64)
+	case "number":
+		converted, err = strconv.ParseFloat(value, 64)
+	case "boolean":
+		converted, err = strconv.ParseBool(value)
+	default:
+		return value, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parameter '%s': value '%s' is not of type '%s'", name, value, typ)
+	}
+	return converted, nil
+}
@@ -71,6 +71,9 @@ func (p *PathItem) buildOperation(method string) *Operation {
 }
 
 func (p *PathItem) Walk(ctx context.Context, pathItem *v3.PathItem) {
+	if ctx.Err() != nil {
+		return
+	}
 
 	drCtx := base.GetDrContext(ctx)
 	wg := drCtx.WaitGroup
@@ -19,6 +19,9 @@ type Paths struct {
 }
 
 func (p *Paths) Walk(ctx context.Context, paths *v3.Paths) {
+	if ctx.Err() != nil {
+		return
+	}
 
 	drCtx := base.GetDrContext(ctx)
 	//wg := drCtx.WaitGroup
@@ -17,6 +17,9 @@ type RequestBody struct {
 }
 
 func (r *RequestBody) Walk(ctx context.Context, requestBody *v3.RequestBody) {
+	if ctx.Err() != nil {
+		return
+	}
 
 	drCtx := base.GetDrContext(ctx)
 	wg := drCtx.WaitGroup
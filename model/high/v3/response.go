@@ -19,6 +19,9 @@ type Response struct {
 }
 
 func (r *Response) Walk(ctx context.Context, response *v3.Response) {
+	if ctx.Err() != nil {
+		return
+	}
 
 	drCtx := base.GetDrContext(ctx)
 	wg := drCtx.WaitGroup
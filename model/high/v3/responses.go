@@ -18,6 +18,9 @@ type Responses struct {
 }
 
 func (r *Responses) Walk(ctx context.Context, responses *v3.Responses) {
+	if ctx.Err() != nil {
+		return
+	}
 
 	drCtx := base.GetDrContext(ctx)
 	wg := drCtx.WaitGroup
@@ -0,0 +1,368 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package v3
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Runtime expression sources, as defined by the OpenAPI specification.
+const (
+	ExpressionURL        = "url"
+	ExpressionMethod     = "method"
+	ExpressionStatusCode = "statusCode"
+	ExpressionRequest    = "request"
+	ExpressionResponse   = "response"
+)
+
+// RuntimeExpression is a parsed runtime expression, for example `$request.query.queryUrl` or
+// `$response.body#/id`.
+type RuntimeExpression struct {
+	Raw string
+
+	// Source is one of url, method, statusCode, request or response.
+	Source string
+
+	// Location is one of header, query, path or body, and is only set for request and response expressions.
+	Location string
+
+	// Name is the header, query or path parameter name.
+	Name string
+
+	// Pointer is the JSON pointer into the body, it is empty when the whole body is referenced.
+	Pointer string
+
+	// Position is the offset of the expression in the string it was parsed from.
+	Position int
+}
+
+// ExpressionError is a syntax (or evaluation) error in a runtime expression. Position is the offset in the
+// parsed string where the problem was found.
+type ExpressionError struct {
+	Expression string
+	Position   int
+	Message    string
+}
+
+func (e *ExpressionError) Error() string {
+	return fmt.Sprintf("invalid runtime expression '%s' at position %d: %s", e.Expression, e.Position, e.Message)
+}
+
+// RuntimeContext is the request and response a runtime expression is evaluated against.
+// Path parameters are read with http.Request.PathValue.
+type RuntimeContext struct {
+	Request  *http.Request
+	Response *http.Response
+}
+
+// ParseRuntimeExpression parses a single runtime expression, such as `$request.header.X-Id`.
+func ParseRuntimeExpression(expression string) (*RuntimeExpression, error) {
+	return parseRuntimeExpression(expression, expression, 0)
+}
+
+// ParseExpressionTemplate parses every runtime expression embedded in a string using braces,
+// for example `https://{$request.query.host}/status?id={$request.body#/id}`. A string that is a
+// bare expression (starting with `$`) is parsed as a single expression.
+func ParseExpressionTemplate(template string) ([]*RuntimeExpression, error) {
+	if strings.HasPrefix(template, "$") {
+		e, err := ParseRuntimeExpression(template)
+		if err != nil {
+			return nil, err
+		}
+		return []*RuntimeExpression{e}, nil
+	}
+	var expressions []*RuntimeExpression
+	for i := 0; i < len(template); i++ {
+		switch template[i] {
+		case '}':
+			return nil, &ExpressionError{Expression: template, Position: i, Message: "unexpected '}'"}
+		case '{':
+			end := strings.IndexByte(template[i:], '}')
+			if end < 0 {
+				return nil, &ExpressionError{Expression: template, Position: i, Message: "unterminated '{'"}
+			}
+			e, err := parseRuntimeExpression(template, template[i+1:i+end], i+1)
+			if err != nil {
+				return nil, err
+			}
+			expressions = append(expressions, e)
+			i += end
+		}
+	}
+	return expressions, nil
+}
+
+func parseRuntimeExpression(full, expression string, offset int) (*RuntimeExpression, error) {
+	fail := func(pos int, msg string) error {
+		return &ExpressionError{Expression: full, Position: offset + pos, Message: msg}
+	}
+	if !strings.HasPrefix(expression, "$") {
+		return nil, fail(0, "expression must start with '$'")
+	}
+	e := &RuntimeExpression{Raw: expression, Position: offset}
+	source, rest, _ := strings.Cut(expression[1:], ".")
+	switch source {
+	case ExpressionURL, ExpressionMethod, ExpressionStatusCode:
+		if len(expression) != len(source)+1 {
+			return nil, fail(len(source)+1, fmt.Sprintf("unexpected characters after '$%s'", source))
+		}
+		e.Source = source
+		return e, nil
+	case ExpressionRequest, ExpressionResponse:
+		e.Source = source
+	default:
+		return nil, fail(1, fmt.Sprintf("unknown source '%s', expected url, method, statusCode, request or response", source))
+	}
+
+	pos := len(source) + 2
+	if len(expression) < pos {
+		return nil, fail(len(expression), "expected '.' followed by header, query, path or body")
+	}
+	location, name, hasName := strings.Cut(rest, ".")
+	if strings.HasPrefix(rest, "body") {
+		location, name, hasName = "body", strings.TrimPrefix(rest, "body"), false
+	}
+	switch location {
+	case "header":
+		if !hasName || name == "" {
+			return nil, fail(pos+len(location), "expected a header name")
+		}
+		for i, r := range name {
+			if !isTokenChar(r) {
+				return nil, fail(pos+len(location)+1+i, fmt.Sprintf("invalid character '%c' in header name", r))
+			}
+		}
+	case "query", "path":
+		if !hasName || name == "" {
+			return nil, fail(pos+len(location), fmt.Sprintf("expected a %s parameter name", location))
+		}
+	case "body":
+		if name != "" {
+			if name[0] != '#' {
+				return nil, fail(pos+len(location), "expected '#' followed by a JSON pointer")
+			}
+			pointer := name[1:]
+			if pointer != "" && pointer[0] != '/' {
+				return nil, fail(pos+len(location)+1, "JSON pointer must start with '/'")
+			}
+			for i := 0; i < len(pointer); i++ {
+				if pointer[i] == '~' && (i+1 >= len(pointer) || (pointer[i+1] != '0' && pointer[i+1] != '1')) {
+					return nil, fail(pos+len(location)+1+i, "'~' must be followed by '0' or '1' in a JSON pointer")
+				}
+			}
+			e.Pointer = pointer
+		}
+		e.Location = location
+		return e, nil
+	default:
+		return nil, fail(pos, fmt.Sprintf("unknown location '%s', expected header, query, path or body", location))
+	}
+	e.Location = location
+	e.Name = name
+	return e, nil
+}
+
+// isTokenChar checks a rune is a valid RFC7230 token character.
+func isTokenChar(r rune) bool {
+	if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+		return true
+	}
+	return strings.ContainsRune("!#$%&'*+-.^_`|~", r)
+}
+
+// Evaluate resolves the expression against a request and response. Body values that are not strings are
+// returned as decoded JSON values.
+func (e *RuntimeExpression) Evaluate(rc *RuntimeContext) (any, error) {
+	fail := func(msg string) error {
+		return &ExpressionError{Expression: e.Raw, Position: 0, Message: msg}
+	}
+	if rc == nil {
+		return nil, fail("no request or response to evaluate against")
+	}
+	switch e.Source {
+	case ExpressionURL:
+		if rc.Request == nil || rc.Request.URL == nil {
+			return nil, fail("no request to read the URL from")
+		}
+		u := *rc.Request.URL
+		if u.Host == "" {
+			u.Host = rc.Request.Host
+		}
+		if u.Scheme == "" && u.Host != "" {
+			u.Scheme = "http"
+			if rc.Request.TLS != nil {
+				u.Scheme = "https"
+			}
+		}
+		return u.String(), nil
+	case ExpressionMethod:
+		if rc.Request == nil {
+			return nil, fail("no request to read the method from")
+		}
+		return rc.Request.Method, nil
+	case ExpressionStatusCode:
+		if rc.Response == nil {
+			return nil, fail("no response to read the status code from")
+		}
+		return rc.Response.StatusCode, nil
+	}
+
+	var header http.Header
+	var body *io.ReadCloser
+	if e.Source == ExpressionRequest {
+		if rc.Request == nil {
+			return nil, fail("no request to evaluate against")
+		}
+		header, body = rc.Request.Header, &rc.Request.Body
+	} else {
+		if rc.Response == nil {
+			return nil, fail("no response to evaluate against")
+		}
+		header, body = rc.Response.Header, &rc.Response.Body
+	}
+
+	switch e.Location {
+	case "header":
+		if v := header.Get(e.Name); v != "" {
+			return v, nil
+		}
+		return nil, fail(fmt.Sprintf("header '%s' not found", e.Name))
+	case "query":
+		if rc.Request == nil || rc.Request.URL == nil || !rc.Request.URL.Query().Has(e.Name) {
+			return nil, fail(fmt.Sprintf("query parameter '%s' not found", e.Name))
+		}
+		return rc.Request.URL.Query().Get(e.Name), nil
+	case "path":
+		if rc.Request == nil {
+			return nil, fail(fmt.Sprintf("path parameter '%s' not found", e.Name))
+		}
+		if v := rc.Request.PathValue(e.Name); v != "" {
+			return v, nil
+		}
+		return nil, fail(fmt.Sprintf("path parameter '%s' not found", e.Name))
+	}
+
+	// body, read it and put it back so it can be read again.
+	if *body == nil {
+		return nil, fail("no body to evaluate against")
+	}
+	raw, err := io.ReadAll(*body)
+	_ = (*body).Close()
+	*body = io.NopCloser(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fail(fmt.Sprintf("unable to read body: %s", err.Error()))
+	}
+	if e.Pointer == "" {
+		return string(raw), nil
+	}
+	var decoded any
+	if err = json.Unmarshal(raw, &decoded); err != nil {
+		return nil, fail(fmt.Sprintf("body is not valid JSON: %s", err.Error()))
+	}
+	value, err := resolvePointer(decoded, e.Pointer)
+	if err != nil {
+		return nil, fail(err.Error())
+	}
+	return value, nil
+}
+
+// ResolveExpressionTemplate replaces every runtime expression in a template with its evaluated value.
+func ResolveExpressionTemplate(template string, rc *RuntimeContext) (string, error) {
+	expressions, err := ParseExpressionTemplate(template)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(template, "$") {
+		v, err := expressions[0].Evaluate(rc)
+		if err != nil {
+			return "", err
+		}
+		return stringify(v), nil
+	}
+	var b strings.Builder
+	last := 0
+	for _, e := range expressions {
+		v, err := e.Evaluate(rc)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(template[last : e.Position-1])
+		b.WriteString(stringify(v))
+		last = e.Position + len(e.Raw) + 1
+	}
+	b.WriteString(template[last:])
+	return b.String(), nil
+}
+
+// resolvePointer resolves a JSON pointer against a decoded JSON value.
+func resolvePointer(value any, pointer string) (any, error) {
+	current := value
+	for _, segment := range strings.Split(pointer, "/")[1:] {
+		segment = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+		switch v := current.(type) {
+		case map[string]any:
+			next, ok := v[segment]
+			if !ok {
+				return nil, fmt.Errorf("'%s' not found in body", pointer)
+			}
+			current = next
+		case []any:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("'%s' not found in body", pointer)
+			}
+			current = v[i]
+		default:
+			return nil, fmt.Errorf("'%s' not found in body", pointer)
+		}
+	}
+	return current, nil
+}
+
+func stringify(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]any, []any:
+		b, _ := json.Marshal(v)
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// CallbackURL is a callback expression, resolved to a concrete URL.
+type CallbackURL struct {
+	Expression string
+	URL        string
+	PathItem   *PathItem
+}
+
+// ResolveExpressions evaluates every callback expression against a sample request, returning the concrete
+// URLs the callbacks would be sent to. Expressions that cannot be resolved are skipped, and returned
+// together as an error.
+func (c *Callback) ResolveExpressions(request *http.Request) ([]*CallbackURL, error) {
+	if c == nil || c.Expression == nil {
+		return nil, nil
+	}
+	rc := &RuntimeContext{Request: request}
+	var urls []*CallbackURL
+	var errs []error
+	for expression, pi := range c.Expression.FromOldest() {
+		u, err := ResolveExpressionTemplate(expression, rc)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		urls = append(urls, &CallbackURL{Expression: expression, URL: u, PathItem: pi})
+	}
+	return urls, errors.Join(errs...)
+}
@@ -16,6 +16,9 @@ type SecurityScheme struct {
 }
 
 func (s *SecurityScheme) Walk(ctx context.Context, securityScheme *v3.SecurityScheme) {
+	if ctx.Err() != nil {
+		return
+	}
 
 	drCtx := base.GetDrContext(ctx)
 	wg := drCtx.WaitGroup
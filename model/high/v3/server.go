@@ -8,6 +8,7 @@ import (
 	"github.com/pb33f/doctor/model/high/base"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 	"github.com/pb33f/libopenapi/orderedmap"
+	"strings"
 )
 
 type Server struct {
@@ -17,6 +18,9 @@ type Server struct {
 }
 
 func (s *Server) Walk(ctx context.Context, server *v3.Server) {
+	if ctx.Err() != nil {
+		return
+	}
 
 	drCtx := base.GetDrContext(ctx)
 	wg := drCtx.WaitGroup
@@ -48,6 +52,45 @@ func (s *Server) GetValue() any {
 	return s.Value
 }
 
+// ExpandURLs returns every concrete URL this server can produce. Each declared variable is substituted with
+// every one of its enum values, or with its default value when no enum is declared. Variables that are
+// used in the URL but never declared are left in place.
+func (s *Server) ExpandURLs() []string {
+	if s.Value == nil {
+		return nil
+	}
+	urls := []string{s.Value.URL}
+	if s.Value.Variables == nil {
+		return urls
+	}
+	for pair := s.Value.Variables.First(); pair != nil; pair = pair.Next() {
+		name := "{" + pair.Key() + "}"
+		if !strings.Contains(s.Value.URL, name) {
+			continue
+		}
+		values := pair.Value().Enum
+		if len(values) == 0 {
+			values = []string{pair.Value().Default}
+		}
+		var expanded []string
+		for _, u := range urls {
+			for _, v := range values {
+				expanded = append(expanded, strings.ReplaceAll(u, name, v))
+			}
+		}
+		urls = expanded
+	}
+	seen := make(map[string]bool, len(urls))
+	unique := urls[:0]
+	for _, u := range urls {
+		if !seen[u] {
+			seen[u] = true
+			unique = append(unique, u)
+		}
+	}
+	return unique
+}
+
 func (s *Server) GetSize() (height, width int) {
 	width = base.WIDTH
 	height = base.HEIGHT
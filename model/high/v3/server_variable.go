@@ -15,6 +15,10 @@ type ServerVariable struct {
 }
 
 func (sv *ServerVariable) Walk(ctx context.Context, serverVariable *v3.ServerVariable, key string) {
+	if ctx.Err() != nil {
+		return
+	}
+
 	drCtx := base.GetDrContext(ctx)
 	sv.Value = serverVariable
 	sv.PathSegment = "variables"
@@ -0,0 +1,109 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package v3
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"slices"
+	"strings"
+)
+
+// SignatureHash returns a stable hash of the contract of the operation: its effective parameters (the parameters
+// of its path item, overridden by its own), its request body and its responses (codes, content types and
+// headers), with every schema resolved. Two operations with the same contract have the same hash, however their
+// schemas are referenced, and the order parameters, responses and content types are declared in does not matter.
+// The hash is hex encoded SHA-256.
+func (o *Operation) SignatureHash() string {
+	if o == nil || o.Value == nil {
+		return ""
+	}
+	var lines []string
+
+	params := make(map[string]*v3.Parameter)
+	if pi, ok := o.Parent.(*PathItem); ok && pi.Value != nil {
+		for _, p := range pi.Value.Parameters {
+			params[parameterKey(p)] = p
+		}
+	}
+	for _, p := range o.Value.Parameters {
+		params[parameterKey(p)] = p
+	}
+	for key, p := range params {
+		required := p.Required != nil && *p.Required
+		lines = append(lines, fmt.Sprintf("parameter|%s|%t|%s", key, required, schemaSignature(p.Schema)))
+		lines = append(lines, contentSignatures("parameter|"+key, p.Content)...)
+	}
+
+	if rb := o.Value.RequestBody; rb != nil {
+		lines = append(lines, fmt.Sprintf("requestBody|%t", rb.Required != nil && *rb.Required))
+		lines = append(lines, contentSignatures("requestBody", rb.Content)...)
+	}
+
+	if o.Value.Responses != nil {
+		responses := make(map[string]*v3.Response)
+		for code, r := range o.Value.Responses.Codes.FromOldest() {
+			responses[code] = r
+		}
+		if o.Value.Responses.Default != nil {
+			responses["default"] = o.Value.Responses.Default
+		}
+		for code, r := range responses {
+			lines = append(lines, "response|"+code)
+			lines = append(lines, contentSignatures("response|"+code, r.Content)...)
+			for name, h := range r.Headers.FromOldest() {
+				prefix := fmt.Sprintf("response|%s|header|%s", code, strings.ToLower(name))
+				lines = append(lines, fmt.Sprintf("%s|%t|%s", prefix, h.Required, schemaSignature(h.Schema)))
+				lines = append(lines, contentSignatures(prefix, h.Content)...)
+			}
+		}
+	}
+
+	slices.Sort(lines)
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// parameterKey identifies a parameter by its location and name, header names are not case-sensitive.
+func parameterKey(p *v3.Parameter) string {
+	if p.In == "header" {
+		return p.In + "|" + strings.ToLower(p.Name)
+	}
+	return p.In + "|" + p.Name
+}
+
+// contentSignatures returns a line for every content type, and the signature of its schema.
+func contentSignatures(prefix string, content *orderedmap.Map[string, *v3.MediaType]) []string {
+	var lines []string
+	for contentType, mt := range content.FromOldest() {
+		lines = append(lines, fmt.Sprintf("%s|content|%s|%s", prefix, strings.ToLower(contentType),
+			schemaSignature(mt.Schema)))
+	}
+	return lines
+}
+
+// schemaSignature hashes a schema with every reference resolved inline. Schemas that cannot be rendered inline
+// (circular schemas) are hashed as they are, references included.
+func schemaSignature(proxy *base.SchemaProxy) string {
+	if proxy == nil {
+		return ""
+	}
+	schema := proxy.Schema()
+	if schema == nil {
+		return ""
+	}
+	rendered, err := schema.RenderInline()
+	if err != nil {
+		if schema.GoLow() == nil {
+			return ""
+		}
+		return fmt.Sprintf("%x", schema.GoLow().Hash())
+	}
+	sum := sha256.Sum256(rendered)
+	return hex.EncodeToString(sum[:])
+}
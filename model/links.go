@@ -0,0 +1,183 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"fmt"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	drV3 "github.com/pb33f/doctor/model/high/v3"
+	"gopkg.in/yaml.v3"
+	"slices"
+	"strings"
+)
+
+const (
+	FindingLinkTarget     = "link-target"
+	FindingLinkParameter  = "link-parameter"
+	FindingLinkExpression = "link-expression"
+)
+
+// LinkFinding is a problem with a link. The embedded finding is attached to the link, Source is the operation
+// that returns the link (nil for links in components) and Target is the operation the link points to, when it
+// could be found.
+type LinkFinding struct {
+	*drBase.Finding
+	Link   *drV3.Link
+	Source *PathOperation
+	Target *PathOperation
+}
+
+// ValidateLinks checks every link in the document (in responses and components) points at a real operation
+// by operationId or operationRef, that every mapped parameter exists on the target operation, and that every
+// runtime expression is valid.
+//
+// Only local operationRefs (`#/paths/...`) are checked, references to other documents are not followed.
+func (w *DrDocument) ValidateLinks() []*LinkFinding {
+	ops := w.Operations()
+	byId := make(map[string]*PathOperation)
+	for _, po := range ops {
+		if po.Operation.Value.OperationId != "" {
+			byId[po.Operation.Value.OperationId] = po
+		}
+	}
+
+	var findings []*LinkFinding
+	seen := make(map[*yaml.Node]bool)
+	check := func(link *drV3.Link, source *PathOperation) {
+		if link == nil || link.Value == nil {
+			return
+		}
+		if l := link.Value.GoLow(); l != nil && l.RootNode != nil {
+			seen[l.RootNode] = true
+		}
+		findings = append(findings, w.validateLink(link, source, byId)...)
+	}
+
+	for _, po := range ops {
+		if po.Operation.Responses == nil {
+			continue
+		}
+		var responses []*drV3.Response
+		if po.Operation.Responses.Codes != nil {
+			for _, r := range po.Operation.Responses.Codes.FromOldest() {
+				responses = append(responses, r)
+			}
+		}
+		if po.Operation.Responses.Default != nil {
+			responses = append(responses, po.Operation.Responses.Default)
+		}
+		for _, r := range responses {
+			if r.Links != nil {
+				for _, link := range r.Links.FromOldest() {
+					check(link, po)
+				}
+			}
+		}
+	}
+
+	// component links that are not used by any response.
+	if w.V3Document != nil && w.V3Document.Components != nil && w.V3Document.Components.Links != nil {
+		for _, link := range w.V3Document.Components.Links.FromOldest() {
+			if l := link.Value.GoLow(); l != nil && seen[l.RootNode] {
+				continue
+			}
+			check(link, nil)
+		}
+	}
+	return findings
+}
+
+func (w *DrDocument) validateLink(link *drV3.Link, source *PathOperation, byId map[string]*PathOperation) []*LinkFinding {
+	var findings []*LinkFinding
+	add := func(id, message string, target *PathOperation) {
+		findings = append(findings, &LinkFinding{
+			Finding: drBase.NewFinding(id, drBase.SeverityError, fmt.Sprintf("link '%s' %s", link.Key, message), link),
+			Link:    link,
+			Source:  source,
+			Target:  target,
+		})
+	}
+
+	v := link.Value
+	var target *PathOperation
+	switch {
+	case v.OperationId != "" && v.OperationRef != "":
+		add(FindingLinkTarget, "defines both operationId and operationRef, only one is allowed", nil)
+		return findings
+	case v.OperationId != "":
+		target = byId[v.OperationId]
+		if target == nil {
+			add(FindingLinkTarget, fmt.Sprintf("points to operationId '%s', which does not exist", v.OperationId), nil)
+		}
+	case v.OperationRef != "":
+		if !strings.HasPrefix(v.OperationRef, "#") {
+			break
+		}
+		target = w.locateOperationRef(v.OperationRef)
+		if target == nil {
+			add(FindingLinkTarget, fmt.Sprintf("points to operationRef '%s', which does not exist", v.OperationRef), nil)
+		}
+	default:
+		add(FindingLinkTarget, "does not define an operationId or operationRef", nil)
+	}
+
+	if v.Parameters != nil {
+		for name, value := range v.Parameters.FromOldest() {
+			if target != nil {
+				in, param, qualified := strings.Cut(name, ".")
+				if !qualified || !slices.Contains([]string{"path", "query", "header", "cookie"}, in) {
+					in, param = "", name
+				}
+				found := false
+				for _, p := range target.Parameters() {
+					if p.Value.Name == param && (in == "" || p.Value.In == in) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					add(FindingLinkParameter, fmt.Sprintf("maps parameter '%s', which is not a parameter of `%s %s`",
+						name, strings.ToUpper(target.Method), target.Path), target)
+				}
+			}
+			if err := validateLinkExpression(value); err != nil {
+				add(FindingLinkExpression, fmt.Sprintf("parameter '%s' has an invalid expression: %s", name, err.Error()), target)
+			}
+		}
+	}
+	if err := validateLinkExpression(v.RequestBody); err != nil {
+		add(FindingLinkExpression, fmt.Sprintf("requestBody has an invalid expression: %s", err.Error()), target)
+	}
+	return findings
+}
+
+// locateOperationRef finds the operation a local operationRef points to, for example `#/paths/~1burgers/post`.
+func (w *DrDocument) locateOperationRef(ref string) *PathOperation {
+	_, pointer, _ := strings.Cut(ref, "#")
+	segments := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	if len(segments) != 3 || (segments[0] != "paths" && segments[0] != "webhooks") {
+		return nil
+	}
+	unescape := func(s string) string {
+		return strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+	}
+	path, method := unescape(segments[1]), strings.ToLower(unescape(segments[2]))
+	for _, po := range w.Operations() {
+		if po.Path == path && po.Method == method && po.Webhook == (segments[0] == "webhooks") {
+			return po
+		}
+	}
+	return nil
+}
+
+// validateLinkExpression checks runtime expressions in a link value, values that are not expressions
+// are constants and are always valid.
+func validateLinkExpression(value string) error {
+	if !strings.HasPrefix(value, "$") && !strings.Contains(value, "{$") {
+		return nil
+	}
+	_, err := drV3.ParseExpressionTemplate(value)
+	return err
+}
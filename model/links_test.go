@@ -0,0 +1,73 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+func TestWalker_WalkV3_ValidateLinks(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ := libopenapi.NewDocument(bytes)
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocument(v3Doc)
+
+	findings := walker.ValidateLinks()
+	require.Len(t, findings, 1)
+	assert.Equal(t, FindingLinkParameter, findings[0].Id)
+	assert.Equal(t, "ListBurgerDressings", findings[0].Link.Key)
+	assert.Equal(t, "/burgers/{burgerId}", findings[0].Source.Path)
+	assert.Equal(t, "listBurgerDressings", findings[0].Target.Operation.Value.OperationId)
+	assert.Contains(t, findings[0].Message, "dressingId")
+
+	spec := `openapi: 3.1.0
+info:
+  title: links
+  version: 1
+paths:
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      parameters:
+        - in: path
+          name: petId
+          required: true
+      responses:
+        "200":
+          description: ok
+          links:
+            ByRef:
+              operationRef: '#/paths/~1pets~1{petId}/get'
+              parameters:
+                path.petId: $response.body#/id
+            Missing:
+              operationId: nope
+            BadRef:
+              operationRef: '#/paths/~1cats/get'
+            BadExpression:
+              operationId: getPet
+              parameters:
+                petId: $request.cookie.id
+              requestBody: '{$response.body#id}'
+            Nothing:
+              description: points nowhere`
+
+	newDoc, _ = libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ = newDoc.BuildV3Model()
+	walker = NewDrDocument(v3Doc)
+
+	found := make(map[string][]string)
+	for _, f := range walker.ValidateLinks() {
+		found[f.Link.Key] = append(found[f.Link.Key], f.Id)
+	}
+	assert.NotContains(t, found, "ByRef")
+	assert.Equal(t, []string{FindingLinkTarget}, found["Missing"])
+	assert.Equal(t, []string{FindingLinkTarget}, found["BadRef"])
+	assert.Equal(t, []string{FindingLinkExpression, FindingLinkExpression}, found["BadExpression"])
+	assert.Equal(t, []string{FindingLinkTarget}, found["Nothing"])
+}
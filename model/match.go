@@ -0,0 +1,89 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"github.com/pb33f/doctor/internal/routing"
+	drV3 "github.com/pb33f/doctor/model/high/v3"
+	"net/url"
+	"strings"
+)
+
+var (
+	// ErrPathNotFound is returned by MatchOperation when no path template matches the request path.
+	ErrPathNotFound = errors.New("no path in the specification matches")
+
+	// ErrMethodNotAllowed is returned by MatchOperation when a path matches, but does not define the method.
+	ErrMethodNotAllowed = errors.New("method is not defined for path")
+)
+
+// OperationMatch is an operation matched to a concrete request, along with the path parameter values
+// extracted from the request path.
+type OperationMatch struct {
+	*PathOperation
+	PathParameters map[string]string
+}
+
+// MatchOperation matches a request method and a concrete URL (or path), for example `/burgers/1234`, against the
+// path templates of the document (`/burgers/{burgerId}`) and returns the operation and the extracted path
+// parameter values. Concrete paths win over templated paths, as required by the specification.
+//
+// When no path matches, ErrPathNotFound is returned. When a path matches but none of the matching paths define
+// the method, ErrMethodNotAllowed is returned with a match for the first matching path item (the operation is nil).
+func (w *DrDocument) MatchOperation(method, concreteURL string) (*OperationMatch, error) {
+	path, _, _ := strings.Cut(concreteURL, "?")
+	if strings.Contains(concreteURL, "://") {
+		if u, err := url.Parse(concreteURL); err == nil {
+			path = u.Path
+		}
+	}
+	if w == nil {
+		return nil, fmt.Errorf("%w '%s'", ErrPathNotFound, path)
+	}
+	if path == "" {
+		path = "/"
+	}
+	routes, params := w.pathRouter().Match(path)
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("%w '%s'", ErrPathNotFound, path)
+	}
+	method = strings.ToLower(method)
+	for i, route := range routes {
+		if op, ok := route.Value.GetOperations().Get(method); ok {
+			return &OperationMatch{
+				PathOperation: &PathOperation{
+					Path:      route.Template.Raw,
+					Method:    method,
+					PathItem:  route.Value,
+					Operation: op,
+				},
+				PathParameters: params[i],
+			}, nil
+		}
+	}
+	return &OperationMatch{
+		PathOperation: &PathOperation{
+			Path:     routes[0].Template.Raw,
+			Method:   method,
+			PathItem: routes[0].Value,
+		},
+		PathParameters: params[0],
+	}, fmt.Errorf("%w '%s': %s", ErrMethodNotAllowed, routes[0].Template.Raw, strings.ToUpper(method))
+}
+
+// pathRouter builds the router for the paths of the document the first time it is needed.
+func (w *DrDocument) pathRouter() *routing.Router[*drV3.PathItem] {
+	w.routerOnce.Do(func() {
+		w.router = routing.NewRouter[*drV3.PathItem]()
+		if w.V3Document != nil && w.V3Document.Paths != nil && w.V3Document.Paths.PathItems != nil {
+			for k, pi := range w.V3Document.Paths.PathItems.FromOldest() {
+				w.router.Add(k, pi)
+			}
+		}
+	})
+	return w.router
+}
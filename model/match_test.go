@@ -0,0 +1,38 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+func TestWalker_WalkV3_MatchOperation(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ := libopenapi.NewDocument(bytes)
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocument(v3Doc)
+
+	match, err := walker.MatchOperation("GET", "https://api.pb33f.io/burgers/1234/dressings?limit=2")
+	require.NoError(t, err)
+	assert.Equal(t, "/burgers/{burgerId}/dressings", match.Path)
+	assert.Equal(t, "get", match.Method)
+	assert.Equal(t, "listBurgerDressings", match.Operation.Value.OperationId)
+	assert.Equal(t, map[string]string{"burgerId": "1234"}, match.PathParameters)
+
+	match, err = walker.MatchOperation("post", "/burgers")
+	require.NoError(t, err)
+	assert.Equal(t, "createBurger", match.Operation.Value.OperationId)
+
+	match, err = walker.MatchOperation("patch", "/burgers/1234")
+	assert.ErrorIs(t, err, ErrMethodNotAllowed)
+	assert.Equal(t, "/burgers/{burgerId}", match.Path)
+	assert.Nil(t, match.Operation)
+
+	_, err = walker.MatchOperation("get", "/pizza")
+	assert.ErrorIs(t, err, ErrPathNotFound)
+}
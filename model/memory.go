@@ -0,0 +1,103 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"context"
+	"fmt"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"reflect"
+)
+
+// memorySampleInterval is how often the memory estimator sizes a walked object, every object is counted.
+const memorySampleInterval = 32
+
+// objectOverhead is the memory each walked object costs beyond its own struct: its entries in the line index
+// and the parent links, its cached JSONPath and the growth of the slices and maps that hold it.
+const objectOverhead = 192
+
+// MemoryBudgetError is returned when a walk is aborted because the projected size of the model exceeds
+// DrConfig.MaxMemoryBytes. The document that was walked is partial.
+type MemoryBudgetError struct {
+	// Budget is the budget of the walk, Projected is the size of the model projected when the walk was aborted.
+	Budget    int64
+	Projected int64
+
+	// Objects is the number of objects walked before the walk was aborted.
+	Objects int64
+}
+
+func (e *MemoryBudgetError) Error() string {
+	return fmt.Sprintf("walk aborted: the projected model size of %d bytes exceeds the memory budget of %d bytes, "+
+		"after walking %d objects", e.Projected, e.Budget, e.Objects)
+}
+
+// memoryEstimator projects the size of the model as it is walked, from the number of objects walked and the
+// average size of a sample of them, and aborts the walk when the projection exceeds the budget. It is only used
+// by the goroutine collecting walked objects.
+type memoryEstimator struct {
+	budget       int64
+	objects      int64
+	sampled      int64
+	sampledBytes int64
+	abort        context.CancelCauseFunc
+	err          *MemoryBudgetError
+}
+
+func newMemoryEstimator(budget int64, abort context.CancelCauseFunc) *memoryEstimator {
+	return &memoryEstimator{budget: budget, abort: abort}
+}
+
+// observe counts a walked object, sizing every memorySampleInterval objects and checking the projection.
+func (m *memoryEstimator) observe(obj any) {
+	if m == nil || m.err != nil {
+		return
+	}
+	m.objects++
+	if (m.objects-1)%memorySampleInterval != 0 {
+		return
+	}
+	m.sampled++
+	m.sampledBytes += estimateObjectSize(obj)
+	if projected := m.projected(); projected > m.budget {
+		m.err = &MemoryBudgetError{Budget: m.budget, Projected: projected, Objects: m.objects}
+		m.abort(m.err)
+	}
+}
+
+// projected returns the projected size of the model, the walked objects at the average size of the sample.
+func (m *memoryEstimator) projected() int64 {
+	if m.sampled == 0 {
+		return 0
+	}
+	return m.sampledBytes / m.sampled * m.objects
+}
+
+// estimateObjectSize estimates the memory of a walked object: its struct, the graph node it owns and the
+// overhead of indexing it.
+func estimateObjectSize(obj any) int64 {
+	size := int64(objectOverhead)
+	t := reflect.TypeOf(obj)
+	if t == nil {
+		return size
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	size += int64(t.Size())
+	if f, ok := obj.(drBase.Foundational); ok && f.GetNode() != nil {
+		size += int64(reflect.TypeOf(drBase.Node{}).Size())
+	}
+	return size
+}
+
+// WalkError returns the error that aborted the walk of the document, a *MemoryBudgetError when the model
+// exceeded DrConfig.MaxMemoryBytes, or nil when the walk was not aborted. An aborted document is partial.
+func (w *DrDocument) WalkError() error {
+	if w.memory != nil && w.memory.err != nil {
+		return w.memory.err
+	}
+	return nil
+}
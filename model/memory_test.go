@@ -0,0 +1,58 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"context"
+	"errors"
+	"github.com/pb33f/doctor/events"
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+func TestWalker_WalkV3_MaxMemoryBytes(t *testing.T) {
+	spec, _ := os.ReadFile("../test_specs/asana.yaml")
+	walk := func(budget int64) (*DrDocument, error) {
+		newDoc, _ := libopenapi.NewDocument(spec)
+		v3Doc, _ := newDoc.BuildV3Model()
+		return NewDrDocumentWithContext(context.Background(), v3Doc,
+			&DrConfig{UseSchemaCache: true, BuildLineIndex: true, MaxMemoryBytes: budget})
+	}
+
+	complete, err := walk(1 << 30)
+	require.NoError(t, err)
+	require.NoError(t, complete.WalkError())
+
+	bus := events.NewBus()
+	sub := bus.Subscribe(0, events.DropNewest, events.Warning)
+	newDoc, _ := libopenapi.NewDocument(spec)
+	v3Doc, _ := newDoc.BuildV3Model()
+	partial, err := NewDrDocumentWithContext(context.Background(), v3Doc,
+		&DrConfig{UseSchemaCache: true, BuildLineIndex: true, MaxMemoryBytes: 64 << 10, Events: bus})
+	bus.Close()
+
+	var budgetErr *MemoryBudgetError
+	require.ErrorAs(t, err, &budgetErr)
+	assert.Equal(t, int64(64<<10), budgetErr.Budget)
+	assert.Greater(t, budgetErr.Projected, budgetErr.Budget)
+	assert.Positive(t, budgetErr.Objects)
+	assert.Contains(t, err.Error(), "exceeds the memory budget of 65536 bytes")
+	require.NotNil(t, partial)
+	assert.Equal(t, err, partial.WalkError())
+	assert.Less(t, len(partial.Schemas), len(complete.Schemas))
+
+	var warned bool
+	for e := range sub.Events() {
+		if w, ok := events.Payload(e, events.Warning); ok && errors.Is(w.Err, err) {
+			warned = true
+		}
+	}
+	assert.True(t, warned)
+
+	// walks without a config return no error.
+	assert.NoError(t, NewDrDocument(v3Doc).WalkError())
+}
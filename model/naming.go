@@ -0,0 +1,344 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"fmt"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	drV3 "github.com/pb33f/doctor/model/high/v3"
+	"gopkg.in/yaml.v3"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Naming conventions.
+const (
+	CaseCamel  = "camelCase"
+	CasePascal = "PascalCase"
+	CaseSnake  = "snake_case"
+	CaseKebab  = "kebab-case"
+)
+
+// Kinds of named elements checked by CheckNaming.
+const (
+	NamingComponent   = "component"
+	NamingProperty    = "property"
+	NamingParameter   = "parameter"
+	NamingHeader      = "header"
+	NamingOperationId = "operationId"
+)
+
+const FindingNaming = "naming-convention"
+
+var caseExpressions = map[string]*regexp.Regexp{
+	CaseCamel:  regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`),
+	CasePascal: regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`),
+	CaseSnake:  regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`),
+	CaseKebab:  regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`),
+}
+
+// NamingConfig sets the convention for each kind of element, an empty convention is not checked.
+type NamingConfig struct {
+	// Components is the convention for the names of components (of every kind).
+	Components string
+
+	// Properties is the convention for the names of schema properties.
+	Properties string
+
+	// Parameters is the convention for path, query and cookie parameters, Headers is the convention for header
+	// parameters.
+	Parameters string
+	Headers    string
+
+	OperationIds string
+}
+
+// DefaultNamingConfig returns PascalCase components, and camelCase properties, parameters and operationIds.
+// Headers are not checked.
+func DefaultNamingConfig() *NamingConfig {
+	return &NamingConfig{
+		Components:   CasePascal,
+		Properties:   CaseCamel,
+		Parameters:   CaseCamel,
+		OperationIds: CaseCamel,
+	}
+}
+
+// NamingFinding is a name that does not follow its convention. Suggestion is the name converted to the
+// convention, and Edits are the changes needed to rename the element to the suggestion (for components, the
+// same edits returned by RenameComponent).
+type NamingFinding struct {
+	*drBase.Finding
+	Kind       string
+	Name       string
+	Convention string
+	Suggestion string
+	Edits      []*Edit
+}
+
+// CheckNaming checks the names of components, schema properties, parameters and operationIds against the
+// conventions in config (DefaultNamingConfig if nil). Every name that breaks its convention is reported with a
+// suggestion, and the edits that apply it. Renaming a path parameter also edits the template of its path, when
+// the parameter is defined with the path, renaming an operationId also edits links that use it.
+//
+// Findings are returned in the order they appear in the document.
+func (w *DrDocument) CheckNaming(config *NamingConfig) ([]*NamingFinding, error) {
+	if w == nil || w.index == nil {
+		return nil, fmt.Errorf("DrDocument is nil, cannot check naming")
+	}
+	if config == nil {
+		config = DefaultNamingConfig()
+	}
+	for _, c := range []string{config.Components, config.Properties, config.Parameters, config.Headers,
+		config.OperationIds} {
+		if _, ok := caseExpressions[c]; c != "" && !ok {
+			return nil, fmt.Errorf("unknown naming convention '%s'", c)
+		}
+	}
+	rootPath := w.index.GetSpecAbsolutePath()
+	var findings []*NamingFinding
+	add := func(kind, convention string, node *yaml.Node, object drBase.Foundational, edits func(string) []*Edit) {
+		if convention == "" || node == nil || caseExpressions[convention].MatchString(node.Value) {
+			return
+		}
+		suggestion := ConvertCase(node.Value, convention)
+		f := &NamingFinding{
+			Finding: drBase.NewFinding(FindingNaming, drBase.SeverityWarn,
+				fmt.Sprintf("%s '%s' is not %s, use '%s'", kind, node.Value, convention, suggestion), object),
+			Kind:       kind,
+			Name:       node.Value,
+			Convention: convention,
+			Suggestion: suggestion,
+		}
+		f.Node = node
+		if suggestion != "" && suggestion != node.Value {
+			f.Edits = edits(suggestion)
+		}
+		findings = append(findings, f)
+	}
+	objectAt := func(line int) drBase.Foundational {
+		if models, err := w.LocateModelByLine(line); err == nil && len(models) > 0 {
+			return models[len(models)-1]
+		}
+		return nil
+	}
+
+	// components.
+	components := findMapValue(documentRoot(w.index.GetRootNode()), "components")
+	for _, kind := range ComponentKinds {
+		section := findMapValue(components, kind)
+		if section == nil || section.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(section.Content); i += 2 {
+			key := section.Content[i]
+			add(NamingComponent, config.Components, key, objectAt(key.Line), func(suggestion string) []*Edit {
+				edits, _ := w.RenameComponent(kind, key.Value, suggestion)
+				return edits
+			})
+		}
+	}
+
+	// schema properties, and the required lists that name them.
+	seen := make(map[*yaml.Node]bool)
+	for _, s := range w.Schemas {
+		if s == nil || s.Value == nil || s.Value.GoLow() == nil || seen[s.Value.GoLow().RootNode] {
+			continue
+		}
+		root := s.Value.GoLow().RootNode
+		seen[root] = true
+		properties := findMapValue(root, "properties")
+		if properties == nil || properties.Kind != yaml.MappingNode {
+			continue
+		}
+		required := findMapValue(root, "required")
+		for i := 0; i+1 < len(properties.Content); i += 2 {
+			key := properties.Content[i]
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			var object drBase.Foundational = s
+			if s.Properties != nil {
+				if p := s.Properties.GetOrZero(key.Value); p != nil {
+					object = p
+				}
+			}
+			add(NamingProperty, config.Properties, key, object, func(suggestion string) []*Edit {
+				file := w.nodeFile(key)
+				edits := []*Edit{{File: file, Line: key.Line, Column: key.Column, Original: key.Value,
+					Replacement: suggestion}}
+				if required != nil && required.Kind == yaml.SequenceNode {
+					for _, r := range required.Content {
+						if r.Value == key.Value {
+							edits = append(edits, &Edit{File: file, Line: r.Line, Column: r.Column, Original: r.Value,
+								Replacement: suggestion})
+						}
+					}
+				}
+				return edits
+			})
+		}
+	}
+
+	// parameters.
+	for _, p := range w.Parameters {
+		l := p.Value.GoLow()
+		if l == nil || l.Name.ValueNode == nil || seen[l.Name.ValueNode] {
+			continue
+		}
+		node := l.Name.ValueNode
+		seen[node] = true
+		kind, convention := NamingParameter, config.Parameters
+		if strings.EqualFold(p.Value.In, "header") {
+			kind, convention = NamingHeader, config.Headers
+		}
+		add(kind, convention, node, p, func(suggestion string) []*Edit {
+			edits := []*Edit{{File: w.nodeFile(node), Line: node.Line, Column: node.Column, Original: node.Value,
+				Replacement: suggestion}}
+			if p.Value.In == "path" {
+				if key := w.parameterPathKey(p); key != nil {
+					edits = append(edits, &Edit{File: rootPath, Line: key.Line, Column: key.Column, Original: key.Value,
+						Replacement: strings.ReplaceAll(key.Value, "{"+node.Value+"}", "{"+suggestion+"}")})
+				}
+			}
+			return edits
+		})
+	}
+
+	// operationIds, and the links that use them.
+	for _, po := range w.Operations() {
+		l := po.Operation.Value.GoLow()
+		if l == nil || l.OperationId.ValueNode == nil {
+			continue
+		}
+		node := l.OperationId.ValueNode
+		add(NamingOperationId, config.OperationIds, node, po.Operation, func(suggestion string) []*Edit {
+			var edits []*Edit
+			for _, n := range findOperationIds(w.index.GetRootNode(), node.Value) {
+				edits = append(edits, &Edit{File: rootPath, Line: n.Line, Column: n.Column, Original: n.Value,
+					Replacement: suggestion})
+			}
+			return edits
+		})
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Node.Line != findings[j].Node.Line {
+			return findings[i].Node.Line < findings[j].Node.Line
+		}
+		return findings[i].Node.Column < findings[j].Node.Column
+	})
+	return findings, nil
+}
+
+// nodeFile returns the location of the file that contains a node, the root document if it cannot be found.
+func (w *DrDocument) nodeFile(node *yaml.Node) string {
+	if rolodex := w.index.GetRolodex(); rolodex != nil {
+		if origin := rolodex.FindNodeOrigin(node); origin != nil && origin.AbsoluteLocation != "" {
+			return origin.AbsoluteLocation
+		}
+	}
+	return w.index.GetSpecAbsolutePath()
+}
+
+// parameterPathKey returns the key of the path a parameter belongs to, when it is defined by the path item, or by
+// one of its operations.
+func (w *DrDocument) parameterPathKey(p drBase.Foundational) *yaml.Node {
+	for parent := p.GetParent(); parent != nil; parent = parent.GetParent() {
+		if pi, ok := parent.(*drV3.PathItem); ok {
+			if _, ko := pi.GetParent().(*drV3.Paths); ko {
+				return pi.GetKeyNode()
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// findOperationIds returns the value of every `operationId` in a node that matches an id, in operations and links.
+func findOperationIds(node *yaml.Node, id string) []*yaml.Node {
+	var found []*yaml.Node
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		if n == nil {
+			return
+		}
+		if n.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if n.Content[i].Value == "operationId" && n.Content[i+1].Value == id {
+					found = append(found, n.Content[i+1])
+				}
+			}
+		}
+		for _, c := range n.Content {
+			walk(c)
+		}
+	}
+	walk(node)
+	return found
+}
+
+// ConvertCase converts a name to a naming convention (one of CaseCamel, CasePascal, CaseSnake or CaseKebab).
+// Words are split on underscores, hyphens, spaces, dots and changes of case, so acronyms are kept together
+// (`HTTPServer` becomes `http_server` in snake case).
+func ConvertCase(name, convention string) string {
+	words := splitWords(name)
+	if len(words) == 0 {
+		return name
+	}
+	switch convention {
+	case CaseSnake, CaseKebab:
+		for i, w := range words {
+			words[i] = strings.ToLower(w)
+		}
+		if convention == CaseSnake {
+			return strings.Join(words, "_")
+		}
+		return strings.Join(words, "-")
+	case CaseCamel, CasePascal:
+		for i, w := range words {
+			w = strings.ToLower(w)
+			if i > 0 || convention == CasePascal {
+				r := []rune(w)
+				r[0] = unicode.ToUpper(r[0])
+				w = string(r)
+			}
+			words[i] = w
+		}
+		return strings.Join(words, "")
+	}
+	return name
+}
+
+func splitWords(name string) []string {
+	var words []string
+	var current []rune
+	runes := []rune(name)
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ' || r == '.':
+			flush()
+			continue
+		case unicode.IsUpper(r) && len(current) > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+	return words
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWalker_WalkV3_CheckNaming(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: naming
+  version: 1.0.0
+paths:
+  /burgers/{burger_id}:
+    get:
+      operationId: get_burger
+      parameters:
+        - name: burger_id
+          in: path
+          required: true
+          schema:
+            type: string
+        - name: X-Request-Id
+          in: header
+          schema:
+            type: string
+      responses:
+        "200":
+          description: a burger
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/burger_meal'
+          links:
+            again:
+              operationId: get_burger
+components:
+  schemas:
+    burger_meal:
+      type: object
+      required:
+        - patty_weight
+      properties:
+        patty_weight:
+          type: number
+        name:
+          type: string`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocument(v3Doc)
+
+	findings, err := walker.CheckNaming(nil)
+	require.NoError(t, err)
+	require.Len(t, findings, 4)
+
+	assert.Equal(t, NamingOperationId, findings[0].Kind)
+	assert.Equal(t, "getBurger", findings[0].Suggestion)
+	assert.Equal(t, "operationId 'get_burger' is not camelCase, use 'getBurger'", findings[0].Message)
+	require.Len(t, findings[0].Edits, 2)
+	assert.Equal(t, 28, findings[0].Edits[1].Line)
+
+	assert.Equal(t, NamingParameter, findings[1].Kind)
+	assert.Equal(t, "burgerId", findings[1].Suggestion)
+	require.Len(t, findings[1].Edits, 2)
+	assert.Equal(t, "/burgers/{burgerId}", findings[1].Edits[1].Replacement)
+
+	assert.Equal(t, NamingComponent, findings[2].Kind)
+	assert.Equal(t, "BurgerMeal", findings[2].Suggestion)
+	assert.Len(t, findings[2].Edits, 2) // the component, and the reference to it.
+
+	assert.Equal(t, NamingProperty, findings[3].Kind)
+	assert.Equal(t, "pattyWeight", findings[3].Suggestion)
+	assert.Len(t, findings[3].Edits, 2) // the property, and the required list.
+	assert.Equal(t, base.SeverityWarn, findings[3].Severity)
+
+	// headers are checked when configured.
+	findings, err = walker.CheckNaming(&NamingConfig{Headers: CaseKebab})
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "x-request-id", findings[0].Suggestion)
+
+	_, err = walker.CheckNaming(&NamingConfig{Properties: "shouting"})
+	assert.Error(t, err)
+}
+
+func TestConvertCase(t *testing.T) {
+	assert.Equal(t, "httpServer", ConvertCase("HTTPServer", CaseCamel))
+	assert.Equal(t, "HttpServer", ConvertCase("http_server", CasePascal))
+	assert.Equal(t, "http_server_v2", ConvertCase("httpServer-v2", CaseSnake))
+	assert.Equal(t, "burger-id", ConvertCase("burgerID", CaseKebab))
+}
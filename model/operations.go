@@ -0,0 +1,92 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	drV3 "github.com/pb33f/doctor/model/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// PathOperation is an operation, along with the path and method it is defined under. For webhooks, Path is
+// the name of the webhook and Webhook is true.
+type PathOperation struct {
+	Path      string
+	Method    string
+	Webhook   bool
+	PathItem  *drV3.PathItem
+	Operation *drV3.Operation
+}
+
+// Operations returns every operation defined in the document, paths first and then webhooks, in the order
+// they are declared.
+func (w *DrDocument) Operations() []*PathOperation {
+	if w == nil || w.V3Document == nil {
+		return nil
+	}
+	var ops []*PathOperation
+	if w.V3Document.Paths != nil && w.V3Document.Paths.PathItems != nil {
+		ops = appendOperations(ops, w.V3Document.Paths.PathItems, false)
+	}
+	if w.V3Document.Webhooks != nil {
+		ops = appendOperations(ops, w.V3Document.Webhooks, true)
+	}
+	return ops
+}
+
+// PathOperations returns only the operations defined under paths.
+func (w *DrDocument) PathOperations() []*PathOperation {
+	var ops []*PathOperation
+	for _, po := range w.Operations() {
+		if !po.Webhook {
+			ops = append(ops, po)
+		}
+	}
+	return ops
+}
+
+// WebhookOperations returns only the operations defined under webhooks.
+func (w *DrDocument) WebhookOperations() []*PathOperation {
+	var ops []*PathOperation
+	for _, po := range w.Operations() {
+		if po.Webhook {
+			ops = append(ops, po)
+		}
+	}
+	return ops
+}
+
+func appendOperations(ops []*PathOperation, items *orderedmap.Map[string, *drV3.PathItem], webhook bool) []*PathOperation {
+	for path, pi := range items.FromOldest() {
+		for method, op := range pi.GetOperations().FromOldest() {
+			ops = append(ops, &PathOperation{
+				Path:      path,
+				Method:    method,
+				Webhook:   webhook,
+				PathItem:  pi,
+				Operation: op,
+			})
+		}
+	}
+	return ops
+}
+
+// Parameters returns the effective parameters of the operation, the path item parameters merged with the
+// operation parameters. Operation parameters override path item parameters with the same name and location.
+func (po *PathOperation) Parameters() []*drV3.Parameter {
+	var params []*drV3.Parameter
+	seen := make(map[string]bool)
+	for _, p := range po.Operation.Parameters {
+		seen[p.Value.In+":"+p.Value.Name] = true
+		params = append(params, p)
+	}
+	if po.PathItem != nil {
+		for _, p := range po.PathItem.Parameters {
+			if !seen[p.Value.In+":"+p.Value.Name] {
+				params = append(params, p)
+			}
+		}
+	}
+	return params
+}
@@ -0,0 +1,54 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWalker_WalkV3_WebhookOperations(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: hooks
+  version: 1
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: ok
+webhooks:
+  newPet:
+    post:
+      operationId: newPet
+      responses:
+        "200":
+          description: ok`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocumentAndGraph(v3Doc)
+
+	ops := walker.Operations()
+	assert.Len(t, ops, 2)
+	assert.False(t, ops[0].Webhook)
+	assert.True(t, ops[1].Webhook)
+	assert.Equal(t, "newPet", ops[1].Path)
+	assert.Equal(t, "post", ops[1].Method)
+	assert.Equal(t, "$.webhooks['newPet'].post", ops[1].Operation.GenerateJSONPath())
+
+	assert.Len(t, walker.PathOperations(), 1)
+	assert.Len(t, walker.WebhookOperations(), 1)
+
+	stats := walker.V3Document.Node.Instance.(map[string]any)
+	assert.Equal(t, 1, stats["webhooks"])
+	assert.NotEmpty(t, stats["webhooks_idhash"])
+
+	matrix := walker.ResponseMatrix()
+	assert.Len(t, matrix.Rows, 2)
+	assert.True(t, matrix.Rows[1].Operation.Webhook)
+	assert.Equal(t, []string{"200"}, matrix.Rows[1].Codes)
+}
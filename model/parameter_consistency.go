@@ -0,0 +1,121 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"fmt"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	drV3 "github.com/pb33f/doctor/model/high/v3"
+	"sort"
+	"strings"
+)
+
+const (
+	FindingParameterCasing      = "parameter-casing"
+	FindingParameterType        = "parameter-type"
+	FindingParameterDescription = "parameter-description"
+)
+
+// ParameterUsage is a parameter, used by an operation.
+type ParameterUsage struct {
+	Operation *PathOperation
+	Parameter *drV3.Parameter
+}
+
+// ParameterGroup is every usage of a parameter with the same (case-insensitive) name and location.
+type ParameterGroup struct {
+	Name   string
+	In     string
+	Usages []*ParameterUsage
+}
+
+// ParameterConsistencyReport is the result of analyzing parameters across every operation.
+type ParameterConsistencyReport struct {
+	Groups   []*ParameterGroup
+	Findings []*drBase.Finding
+}
+
+// ParameterConsistency groups parameters by name and location across all operations, and flags usages that
+// are inconsistent with the rest of the group:
+//
+//   - the name is cased differently to the most common spelling.
+//   - the schema type is different to the most common type.
+//   - the description is missing, when other usages have one.
+func (w *DrDocument) ParameterConsistency() *ParameterConsistencyReport {
+	report := &ParameterConsistencyReport{}
+	groups := make(map[string]*ParameterGroup)
+	for _, po := range w.Operations() {
+		for _, p := range po.Parameters() {
+			key := p.Value.In + ":" + strings.ToLower(p.Value.Name)
+			g, ok := groups[key]
+			if !ok {
+				g = &ParameterGroup{Name: strings.ToLower(p.Value.Name), In: p.Value.In}
+				groups[key] = g
+				report.Groups = append(report.Groups, g)
+			}
+			g.Usages = append(g.Usages, &ParameterUsage{Operation: po, Parameter: p})
+		}
+	}
+
+	for _, g := range report.Groups {
+		if len(g.Usages) < 2 {
+			continue
+		}
+		name := mostCommon(g.Usages, func(u *ParameterUsage) string { return u.Parameter.Value.Name })
+		typ := mostCommon(g.Usages, func(u *ParameterUsage) string { return parameterType(u.Parameter) })
+		described := 0
+		for _, u := range g.Usages {
+			if u.Parameter.Value.Description != "" {
+				described++
+			}
+		}
+		for _, u := range g.Usages {
+			p := u.Parameter
+			at := fmt.Sprintf("%s %s", strings.ToUpper(u.Operation.Method), u.Operation.Path)
+			if p.Value.Name != name {
+				report.Findings = append(report.Findings, drBase.NewFinding(FindingParameterCasing, drBase.SeverityWarn,
+					fmt.Sprintf("%s parameter '%s' in `%s` is cased differently to '%s' used by other operations",
+						p.Value.In, p.Value.Name, at, name), p))
+			}
+			if t := parameterType(p); t != typ {
+				report.Findings = append(report.Findings, drBase.NewFinding(FindingParameterType, drBase.SeverityError,
+					fmt.Sprintf("%s parameter '%s' in `%s` has type '%s', other operations use '%s'",
+						p.Value.In, p.Value.Name, at, t, typ), p))
+			}
+			if p.Value.Description == "" && described > 0 {
+				report.Findings = append(report.Findings, drBase.NewFinding(FindingParameterDescription, drBase.SeverityInfo,
+					fmt.Sprintf("%s parameter '%s' in `%s` has no description, other operations describe it",
+						p.Value.In, p.Value.Name, at), p))
+			}
+		}
+	}
+	return report
+}
+
+func parameterType(p *drV3.Parameter) string {
+	if p.Value.Schema == nil {
+		return ""
+	}
+	s := p.Value.Schema.Schema()
+	if s == nil {
+		return ""
+	}
+	return strings.Join(s.Type, ",")
+}
+
+// mostCommon returns the most common value, ties are broken by choosing the value seen first.
+func mostCommon[T any](items []T, value func(T) string) string {
+	counts := make(map[string]int)
+	var order []string
+	for _, i := range items {
+		v := value(i)
+		if _, ok := counts[v]; !ok {
+			order = append(order, v)
+		}
+		counts[v]++
+	}
+	sort.SliceStable(order, func(i, j int) bool { return counts[order[i]] > counts[order[j]] })
+	return order[0]
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWalker_WalkV3_ParameterConsistency(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /pizza:
+    get:
+      parameters:
+        - in: query
+          name: limit
+          description: how many to return
+          schema:
+            type: integer
+  /burgers:
+    get:
+      parameters:
+        - in: query
+          name: limit
+          description: how many to return
+          schema:
+            type: integer
+  /fries:
+    get:
+      parameters:
+        - in: query
+          name: Limit
+          schema:
+            type: string`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+
+	walker := NewDrDocument(v3Doc)
+	report := walker.ParameterConsistency()
+
+	require.Len(t, report.Groups, 1)
+	assert.Len(t, report.Groups[0].Usages, 3)
+	require.Len(t, report.Findings, 3)
+	for _, f := range report.Findings {
+		assert.Equal(t, "$.paths['/fries'].get.parameters[0]", f.Path)
+	}
+	assert.Equal(t, FindingParameterCasing, report.Findings[0].Id)
+	assert.Equal(t, FindingParameterType, report.Findings[1].Id)
+	assert.Equal(t, FindingParameterDescription, report.Findings[2].Id)
+}
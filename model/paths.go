@@ -0,0 +1,112 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"fmt"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	drV3 "github.com/pb33f/doctor/model/high/v3"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	FindingPathConflict            = "path-conflict"
+	FindingPathParameterUndeclared = "path-parameter-undeclared"
+	FindingPathParameterUnused     = "path-parameter-unused"
+)
+
+// PathFinding is a problem with a path template. For a conflict, the embedded finding is attached to PathItem and
+// Conflict is the path item declared first with the same template. For a templated segment without a parameter,
+// the finding is attached to the operation, and for a path parameter that is not in the template it is attached
+// to Parameter.
+type PathFinding struct {
+	*drBase.Finding
+	Path      string
+	PathItem  *drV3.PathItem
+	Conflict  *drV3.PathItem
+	Operation *PathOperation
+	Parameter *drV3.Parameter
+}
+
+var pathTemplateParam = regexp.MustCompile(`\{([^}]*)}`)
+
+// AnalyzePaths checks the path templates of the document. Templates that only differ by the names of their
+// parameters (like `/pets/{id}` and `/pets/{petId}`) are ambiguous, every templated segment must be declared as a
+// path parameter of each operation, and every declared path parameter must be used in the template. Findings are
+// returned in the order the paths are declared, webhooks are not checked.
+func (w *DrDocument) AnalyzePaths() []*PathFinding {
+	if w == nil || w.V3Document == nil || w.V3Document.Paths == nil || w.V3Document.Paths.PathItems == nil {
+		return nil
+	}
+	ops := make(map[*drV3.PathItem][]*PathOperation)
+	for _, po := range w.PathOperations() {
+		ops[po.PathItem] = append(ops[po.PathItem], po)
+	}
+
+	var findings []*PathFinding
+	templates := make(map[string]string)
+	items := make(map[string]*drV3.PathItem)
+	for path, pi := range w.V3Document.Paths.PathItems.FromOldest() {
+		template := pathTemplateParam.ReplaceAllString(path, "{}")
+		if len(template) > 1 {
+			template = strings.TrimSuffix(template, "/")
+		}
+		if first, ok := templates[template]; ok {
+			findings = append(findings, &PathFinding{
+				Finding: drBase.NewFinding(FindingPathConflict, drBase.SeverityError,
+					fmt.Sprintf("path '%s' is ambiguous with '%s', the templates only differ by parameter names",
+						path, first), pi),
+				Path:     path,
+				PathItem: pi,
+				Conflict: items[template],
+			})
+		} else {
+			templates[template], items[template] = path, pi
+		}
+
+		var names []string
+		for _, m := range pathTemplateParam.FindAllStringSubmatch(path, -1) {
+			if !slices.Contains(names, m[1]) {
+				names = append(names, m[1])
+			}
+		}
+		unused := make(map[*drV3.Parameter]bool)
+		for _, po := range ops[pi] {
+			declared := make(map[string]bool)
+			for _, p := range po.Parameters() {
+				if p.Value == nil || p.Value.In != "path" {
+					continue
+				}
+				declared[p.Value.Name] = true
+				if !slices.Contains(names, p.Value.Name) && !unused[p] {
+					unused[p] = true
+					findings = append(findings, &PathFinding{
+						Finding: drBase.NewFinding(FindingPathParameterUnused, drBase.SeverityError,
+							fmt.Sprintf("path parameter '%s' is not used in the path '%s'", p.Value.Name, path), p),
+						Path:      path,
+						PathItem:  pi,
+						Operation: po,
+						Parameter: p,
+					})
+				}
+			}
+			for _, name := range names {
+				if !declared[name] {
+					findings = append(findings, &PathFinding{
+						Finding: drBase.NewFinding(FindingPathParameterUndeclared, drBase.SeverityError,
+							fmt.Sprintf("`%s %s` does not declare the path parameter '%s'",
+								strings.ToUpper(po.Method), path, name), po.Operation),
+						Path:      path,
+						PathItem:  pi,
+						Operation: po,
+					})
+				}
+			}
+		}
+	}
+	return findings
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWalker_WalkV3_AnalyzePaths(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: paths
+  version: 1
+paths:
+  /pets/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+    get:
+      responses:
+        "200":
+          description: ok
+  /pets/{petId}/:
+    get:
+      parameters:
+        - name: petId
+          in: path
+          required: true
+      responses:
+        "200":
+          description: ok
+  /owners/{ownerId}/pets/{name}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+    get:
+      parameters:
+        - name: ownerId
+          in: path
+          required: true
+      responses:
+        "200":
+          description: ok
+    put:
+      responses:
+        "200":
+          description: ok`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	v3Doc, _ := doc.BuildV3Model()
+	drDoc := NewDrDocument(v3Doc)
+
+	findings := drDoc.AnalyzePaths()
+	var ids []string
+	for _, f := range findings {
+		ids = append(ids, f.Id)
+	}
+	assert.Equal(t, []string{FindingPathConflict, FindingPathParameterUnused, FindingPathParameterUndeclared,
+		FindingPathParameterUndeclared, FindingPathParameterUndeclared}, ids)
+
+	conflict := findings[0]
+	assert.Equal(t, "/pets/{petId}/", conflict.Path)
+	assert.Equal(t, drDoc.V3Document.Paths.PathItems.GetOrZero("/pets/{id}"), conflict.Conflict)
+	assert.Equal(t, drDoc.V3Document.Paths.PathItems.GetOrZero("/pets/{petId}/"), conflict.PathItem)
+
+	// the path item parameter is reported once, not for every operation.
+	unused := findings[1]
+	assert.Equal(t, "id", unused.Parameter.Value.Name)
+	assert.Equal(t, "path parameter 'id' is not used in the path '/owners/{ownerId}/pets/{name}'", unused.Message)
+
+	assert.Equal(t, "`GET /owners/{ownerId}/pets/{name}` does not declare the path parameter 'name'", findings[2].Message)
+	assert.Equal(t, "put", findings[4].Operation.Method)
+	assert.Equal(t, findings[4].Operation.Operation, findings[4].Object)
+
+	assert.Nil(t, (*DrDocument)(nil).AnalyzePaths())
+}
@@ -0,0 +1,345 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"fmt"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	drV3 "github.com/pb33f/doctor/model/high/v3"
+	"math"
+	"strings"
+)
+
+// Checks of a description quality report.
+const (
+	QualityOperationSummary     = "operation-summary"
+	QualityOperationDescription = "operation-description"
+	QualityParameterDescription = "parameter-description"
+	QualityResponseDescription  = "response-description"
+	QualitySchemaTitle          = "schema-title"
+)
+
+// qualityChecks are the checks of a description quality report, in the order they are reported.
+var qualityChecks = []string{
+	QualityOperationSummary,
+	QualityOperationDescription,
+	QualityParameterDescription,
+	QualityResponseDescription,
+	QualitySchemaTitle,
+}
+
+// QualityGap is an element of a document that is missing its documentation.
+type QualityGap struct {
+	Check string `json:"check"`
+
+	// Name names the element: the method and path of an operation, the name and location of a parameter, the
+	// code of a response or the name of a schema.
+	Name     string `json:"name"`
+	JSONPath string `json:"jsonPath"`
+
+	// Line and Column locate the element in the specification.
+	Line   int `json:"line"`
+	Column int `json:"column"`
+
+	// Reference is the component the element was reached through, when the operation references it.
+	Reference string `json:"reference,omitempty"`
+
+	// Operation is the operation the element belongs to, it is nil for component schemas.
+	Operation *PathOperation `json:"-"`
+
+	// Object is the doctor model of the element.
+	Object drBase.Foundational `json:"-"`
+}
+
+// QualityTally counts the elements a check applies to, and how many of them are documented.
+type QualityTally struct {
+	Check      string `json:"check"`
+	Checked    int    `json:"checked"`
+	Documented int    `json:"documented"`
+
+	// Score is the percentage of the elements that are documented, 100 when the check applies to nothing.
+	Score float64 `json:"score"`
+}
+
+// TagQuality rolls up the documentation of the operations of a tag, with the component schemas they use.
+type TagQuality struct {
+	// Tag is the name of the tag, empty for the operations that have no tags.
+	Tag        string          `json:"tag"`
+	Operations int             `json:"operations"`
+	Score      float64         `json:"score"`
+	Checks     []*QualityTally `json:"checks"`
+	Gaps       []*QualityGap   `json:"gaps,omitempty"`
+}
+
+// QualityReport scores how completely a document is described.
+type QualityReport struct {
+	// Score is the percentage of every checked element that is documented, from 0 to 100.
+	Score  float64         `json:"score"`
+	Checks []*QualityTally `json:"checks"`
+
+	// Tags are the rollups of every tag, in the order operations use them, followed by the untagged operations.
+	Tags []*TagQuality `json:"tags,omitempty"`
+
+	// Gaps are the elements that are missing documentation, once each, in the order of the document.
+	Gaps []*QualityGap `json:"gaps,omitempty"`
+}
+
+// Check returns the tally of a check by name, or nil.
+func (r *QualityReport) Check(check string) *QualityTally {
+	for _, t := range r.Checks {
+		if t.Check == check {
+			return t
+		}
+	}
+	return nil
+}
+
+// qualityItem is an element a check applies to, keyed by the object that documents it so elements shared by
+// operations (like path item parameters) are counted once.
+type qualityItem struct {
+	key        any
+	check      string
+	documented bool
+	gap        *QualityGap
+}
+
+// qualityTotals accumulates the tallies and gaps of a set of items.
+type qualityTotals struct {
+	seen    map[any]bool
+	tallies map[string]*QualityTally
+	gaps    []*QualityGap
+}
+
+func newQualityTotals() *qualityTotals {
+	totals := &qualityTotals{seen: make(map[any]bool), tallies: make(map[string]*QualityTally)}
+	for _, check := range qualityChecks {
+		totals.tallies[check] = &QualityTally{Check: check}
+	}
+	return totals
+}
+
+func (q *qualityTotals) add(items []*qualityItem) {
+	for _, item := range items {
+		if q.seen[item.key] {
+			continue
+		}
+		q.seen[item.key] = true
+		tally := q.tallies[item.check]
+		tally.Checked++
+		if item.documented {
+			tally.Documented++
+			continue
+		}
+		q.gaps = append(q.gaps, item.gap)
+	}
+}
+
+// scores returns the tallies in the order of the checks, and the score of all of them.
+func (q *qualityTotals) scores() ([]*QualityTally, float64) {
+	var tallies []*QualityTally
+	var checked, documented int
+	for _, check := range qualityChecks {
+		tally := q.tallies[check]
+		tally.Score = qualityScore(tally.Documented, tally.Checked)
+		checked += tally.Checked
+		documented += tally.Documented
+		tallies = append(tallies, tally)
+	}
+	return tallies, qualityScore(documented, checked)
+}
+
+func qualityScore(documented, checked int) float64 {
+	if checked == 0 {
+		return 100
+	}
+	return math.Round(float64(documented)/float64(checked)*100*100) / 100
+}
+
+// QualityReport scores the documentation of the document: operations without a summary or a description,
+// parameters and responses without a description, and component schemas without a title. Parameters and
+// responses are checked where operations use them, every component schema is checked. Each tag is rolled up
+// with the component schemas its operations reference, so a schema can count towards more than one tag.
+func (w *DrDocument) QualityReport() *QualityReport {
+	report := &QualityReport{}
+	if w == nil || w.V3Document == nil {
+		report.Checks, report.Score = newQualityTotals().scores()
+		return report
+	}
+
+	schemas := make(map[string]*qualityItem)
+	var schemaItems []*qualityItem
+	if w.V3Document.Components != nil && w.V3Document.Components.Schemas != nil {
+		for name, sp := range w.V3Document.Components.Schemas.FromOldest() {
+			if sp == nil || sp.Value == nil {
+				continue
+			}
+			schema := sp.Value.Schema()
+			item := &qualityItem{
+				key:        sp,
+				check:      QualitySchemaTitle,
+				documented: schema == nil || schema.Title != "",
+				gap:        qualityGap(QualitySchemaTitle, name, nil, sp),
+			}
+			schemas["#/components/schemas/"+name] = item
+			schemaItems = append(schemaItems, item)
+		}
+	}
+
+	all := newQualityTotals()
+	tags := make(map[string]*qualityTotals)
+	var tagOrder []string
+	operations := make(map[string]int)
+	for _, po := range w.Operations() {
+		items := operationQualityItems(po)
+		all.add(items)
+		if low := po.Operation.Value.GoLow(); low != nil {
+			for _, def := range w.ReferencedComponents(low.RootNode) {
+				if item := schemas[def]; item != nil {
+					items = append(items, item)
+				}
+			}
+		}
+		opTags := po.Operation.Value.Tags
+		if len(opTags) == 0 {
+			opTags = []string{""}
+		}
+		for _, tag := range opTags {
+			totals := tags[tag]
+			if totals == nil {
+				totals = newQualityTotals()
+				tags[tag] = totals
+				if tag != "" {
+					tagOrder = append(tagOrder, tag)
+				}
+			}
+			operations[tag]++
+			totals.add(items)
+		}
+	}
+	all.add(schemaItems)
+	report.Checks, report.Score = all.scores()
+	report.Gaps = all.gaps
+
+	// untagged operations are rolled up last.
+	if _, ok := tags[""]; ok {
+		tagOrder = append(tagOrder, "")
+	}
+	for _, tag := range tagOrder {
+		tq := &TagQuality{Tag: tag, Operations: operations[tag], Gaps: tags[tag].gaps}
+		tq.Checks, tq.Score = tags[tag].scores()
+		report.Tags = append(report.Tags, tq)
+	}
+	return report
+}
+
+// operationQualityItems returns the summary and description of an operation, and its parameters and responses.
+func operationQualityItems(po *PathOperation) []*qualityItem {
+	name := strings.ToUpper(po.Method) + " " + po.Path
+	items := []*qualityItem{
+		{
+			key:        [2]any{po.Operation, QualityOperationSummary},
+			check:      QualityOperationSummary,
+			documented: po.Operation.Value.Summary != "",
+			gap:        qualityGap(QualityOperationSummary, name, po, po.Operation),
+		},
+		{
+			key:        [2]any{po.Operation, QualityOperationDescription},
+			check:      QualityOperationDescription,
+			documented: po.Operation.Value.Description != "",
+			gap:        qualityGap(QualityOperationDescription, name, po, po.Operation),
+		},
+	}
+	for _, p := range po.Parameters() {
+		gap := qualityGap(QualityParameterDescription, fmt.Sprintf("%s (%s)", p.Value.Name, p.Value.In), po, p)
+		if l := p.Value.GoLow(); l != nil && l.IsReference() {
+			gap.Reference = l.GetReference()
+		}
+		items = append(items, &qualityItem{
+			key:        p,
+			check:      QualityParameterDescription,
+			documented: p.Value.Description != "",
+			gap:        gap,
+		})
+	}
+	if responses := po.Operation.Responses; responses != nil {
+		add := func(code string, r *drV3.Response) {
+			gap := qualityGap(QualityResponseDescription, code, po, r)
+			if l := r.Value.GoLow(); l != nil && l.IsReference() {
+				gap.Reference = l.GetReference()
+			}
+			items = append(items, &qualityItem{
+				key:        r,
+				check:      QualityResponseDescription,
+				documented: r.Value.Description != "",
+				gap:        gap,
+			})
+		}
+		if responses.Codes != nil {
+			for code, r := range responses.Codes.FromOldest() {
+				add(code, r)
+			}
+		}
+		if responses.Default != nil {
+			add("default", responses.Default)
+		}
+	}
+	return items
+}
+
+func qualityGap(check, name string, po *PathOperation, object drBase.Foundational) *QualityGap {
+	gap := &QualityGap{Check: check, Name: name, JSONPath: object.GenerateJSONPath(), Operation: po, Object: object}
+	node := object.GetKeyNode()
+	if node == nil {
+		node = object.GetValueNode()
+	}
+	if node != nil {
+		gap.Line, gap.Column = node.Line, node.Column
+	}
+	return gap
+}
+
+// Markdown renders the report as markdown: a table of checks, a table of tags and a table of the gaps.
+func (r *QualityReport) Markdown() string {
+	buf := strings.Builder{}
+	buf.WriteString(fmt.Sprintf("**Score: %s%%**\n\n", formatQualityScore(r.Score)))
+	buf.WriteString("| Check | Documented | Score |\n")
+	buf.WriteString("|-------|------------|-------|\n")
+	for _, t := range r.Checks {
+		buf.WriteString(fmt.Sprintf("| %s | %d / %d | %s%% |\n",
+			t.Check, t.Documented, t.Checked, formatQualityScore(t.Score)))
+	}
+	if len(r.Tags) > 0 {
+		buf.WriteString("\n| Tag | Operations | Score | Gaps |\n")
+		buf.WriteString("|-----|------------|-------|------|\n")
+		for _, tq := range r.Tags {
+			tag := tq.Tag
+			if tag == "" {
+				tag = "_untagged_"
+			}
+			buf.WriteString(fmt.Sprintf("| %s | %d | %s%% | %d |\n",
+				tag, tq.Operations, formatQualityScore(tq.Score), len(tq.Gaps)))
+		}
+	}
+	if len(r.Gaps) > 0 {
+		buf.WriteString("\n| Check | Operation | Name | Location |\n")
+		buf.WriteString("|-------|-----------|------|----------|\n")
+		for _, g := range r.Gaps {
+			op := ""
+			if g.Operation != nil {
+				op = fmt.Sprintf("`%s %s`", strings.ToUpper(g.Operation.Method), g.Operation.Path)
+				if g.Operation.Webhook {
+					op += " (webhook)"
+				}
+			}
+			buf.WriteString(fmt.Sprintf("| %s | %s | `%s` | `%s` (%d:%d) |\n",
+				g.Check, op, strings.ReplaceAll(g.Name, "|", "\\|"), g.JSONPath, g.Line, g.Column))
+		}
+	}
+	return buf.String()
+}
+
+func formatQualityScore(score float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", score), "0"), ".")
+}
@@ -0,0 +1,133 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestWalker_WalkV3_QualityReport(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: burgers
+  version: 1
+paths:
+  /burgers/{burgerId}:
+    parameters:
+      - name: burgerId
+        in: path
+        required: true
+        description: the burger
+    get:
+      tags: [burgers]
+      summary: get a burger
+      description: returns a burger by id
+      parameters:
+        - $ref: '#/components/parameters/Fries'
+      responses:
+        "200":
+          description: a burger
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Burger'
+        "404":
+          $ref: '#/components/responses/NotFound'
+    delete:
+      tags: [burgers, admin]
+      responses:
+        "204":
+          description: removed
+  /menu:
+    get:
+      summary: the menu
+      responses:
+        default:
+          description: ""
+components:
+  parameters:
+    Fries:
+      name: fries
+      in: query
+  responses:
+    NotFound:
+      description: ""
+  schemas:
+    Burger:
+      title: A burger
+      type: object
+      properties:
+        bun:
+          $ref: '#/components/schemas/Bun'
+    Bun:
+      type: string
+    Drink:
+      type: string`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+	report := NewDrDocument(v3Doc).QualityReport()
+	require.Len(t, report.Checks, 5)
+
+	assert.Equal(t, &QualityTally{Check: QualityOperationSummary, Checked: 3, Documented: 2, Score: 66.67},
+		report.Check(QualityOperationSummary))
+	assert.Equal(t, 1, report.Check(QualityOperationDescription).Documented)
+
+	// the path item parameter is shared by both burger operations, and counted once.
+	params := report.Check(QualityParameterDescription)
+	assert.Equal(t, 2, params.Checked)
+	assert.Equal(t, 1, params.Documented)
+	assert.Equal(t, 4, report.Check(QualityResponseDescription).Checked)
+	assert.Equal(t, 2, report.Check(QualityResponseDescription).Documented)
+	assert.Equal(t, &QualityTally{Check: QualitySchemaTitle, Checked: 3, Documented: 1, Score: 33.33},
+		report.Check(QualitySchemaTitle))
+	assert.Equal(t, 46.67, report.Score)
+
+	var gaps []string
+	for _, g := range report.Gaps {
+		gaps = append(gaps, g.Check+" "+g.Name)
+	}
+	assert.Equal(t, []string{
+		"parameter-description fries (query)",
+		"response-description 404",
+		"operation-summary DELETE /burgers/{burgerId}",
+		"operation-description DELETE /burgers/{burgerId}",
+		"operation-description GET /menu",
+		"response-description default",
+		"schema-title Bun",
+		"schema-title Drink",
+	}, gaps)
+	fries := report.Gaps[0]
+	assert.Equal(t, "#/components/parameters/Fries", fries.Reference)
+	assert.Equal(t, "$.paths['/burgers/{burgerId}'].get.parameters[0]", fries.JSONPath)
+	assert.Equal(t, "GET", strings.ToUpper(fries.Operation.Method))
+	assert.NotNil(t, fries.Object)
+	assert.Positive(t, fries.Line)
+	assert.Nil(t, report.Gaps[6].Operation)
+	assert.Equal(t, "$.components.schemas['Bun']", report.Gaps[6].JSONPath)
+
+	// tags roll up their operations, with the schemas they reference, untagged operations last.
+	require.Len(t, report.Tags, 3)
+	burgers := report.Tags[0]
+	assert.Equal(t, "burgers", burgers.Tag)
+	assert.Equal(t, 2, burgers.Operations)
+	assert.Equal(t, 2, burgers.Checks[4].Checked)
+	assert.Len(t, burgers.Gaps, 5)
+	assert.Equal(t, "admin", report.Tags[1].Tag)
+	assert.Equal(t, "", report.Tags[2].Tag)
+	assert.Equal(t, 33.33, report.Tags[2].Score)
+
+	md := report.Markdown()
+	assert.Contains(t, md, "**Score: 46.67%**")
+	assert.Contains(t, md, "| operation-summary | 2 / 3 | 66.67% |")
+	assert.Contains(t, md, "| _untagged_ | 1 | 33.33% | 2 |")
+	assert.Contains(t, md, "| schema-title |  | `Drink` | `$.components.schemas['Drink']`")
+
+	// an empty document has nothing to document.
+	assert.Equal(t, 100.0, (*DrDocument)(nil).QualityReport().Score)
+}
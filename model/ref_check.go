@@ -0,0 +1,278 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"fmt"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/index"
+	"gopkg.in/yaml.v3"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	FindingRefMissingComponent = "ref-missing-component"
+	FindingRefMissingFile      = "ref-missing-file"
+	FindingRefPointer          = "ref-pointer"
+)
+
+// maxRefSuggestions is the number of close matches suggested for a reference that does not resolve.
+const maxRefSuggestions = 3
+
+// RefFinding is a reference that does not resolve. File, Line and Column are the location of the `$ref` value,
+// Suggestions are the closest names that do exist where the reference went wrong, closest first.
+type RefFinding struct {
+	*drBase.Finding
+	Reference   string
+	File        string
+	Line        int
+	Column      int
+	Suggestions []string
+}
+
+// CheckRefs verifies every `$ref` in the document, and every other file in the rolodex, resolves to something.
+// Failures are classified as a missing component (`#/components/schemas/Burgr`), a missing file, or a pointer
+// that leads nowhere inside a document that does exist. Component names (or keys, for other pointers) that are a
+// close match to the one that could not be found are suggested, using edit distance.
+//
+// Findings are returned in the order they are found, file by file, the root document first.
+func (w *DrDocument) CheckRefs() []*RefFinding {
+	if w == nil || w.index == nil {
+		return nil
+	}
+	indexes := w.allIndexes()
+	files := make(map[string]*yaml.Node)
+	for _, idx := range indexes {
+		if idx != nil && idx.GetSpecAbsolutePath() != "" {
+			files[idx.GetSpecAbsolutePath()] = idx.GetRootNode()
+		}
+	}
+
+	var findings []*RefFinding
+	seen := make(map[*yaml.Node]bool)
+	for _, idx := range indexes {
+		if idx == nil || idx.GetRootNode() == nil || seen[idx.GetRootNode()] {
+			continue
+		}
+		seen[idx.GetRootNode()] = true
+		file := idx.GetSpecAbsolutePath()
+		for _, refNode := range findRefs(idx.GetRootNode()) {
+			f := w.checkRef(idx, refNode, files)
+			if f == nil {
+				continue
+			}
+			f.File = file
+			if idx == w.index {
+				// models are ordered by line, the last is the closest to the reference.
+				if models, err := w.LocateModelByLine(refNode.Line); err == nil && len(models) > 0 {
+					f.Object = models[len(models)-1]
+					f.Path = f.Object.GenerateJSONPath()
+				}
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// checkRef resolves a single reference from the file of an index, returning a finding if it does not resolve.
+func (w *DrDocument) checkRef(idx *index.SpecIndex, refNode *yaml.Node, files map[string]*yaml.Node) *RefFinding {
+	ref := refNode.Value
+	location, pointer, _ := strings.Cut(ref, "#")
+	newFinding := func(id, message string, suggestions []string) *RefFinding {
+		if len(suggestions) > 0 {
+			message = fmt.Sprintf("%s, did you mean '%s'?", message, strings.Join(suggestions, "', '"))
+		}
+		f := &RefFinding{
+			Finding:     drBase.NewFinding(id, drBase.SeverityError, message, nil),
+			Reference:   ref,
+			Line:        refNode.Line,
+			Column:      refNode.Column,
+			Suggestions: suggestions,
+		}
+		f.Node = refNode
+		return f
+	}
+
+	root := idx.GetRootNode()
+	if location != "" {
+		var found bool
+		root, found = w.openReferencedFile(idx, location, files)
+		if !found {
+			return newFinding(FindingRefMissingFile,
+				fmt.Sprintf("reference '%s' points to file '%s', which does not exist", ref, location), nil)
+		}
+	}
+
+	segments := pointerSegments(pointer)
+	node := documentRoot(root)
+	for i, segment := range segments {
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			next = findMapValue(node, segment)
+		case yaml.SequenceNode:
+			if n, err := strconv.Atoi(segment); err == nil && n >= 0 && n < len(node.Content) {
+				next = node.Content[n]
+			}
+		case yaml.AliasNode:
+			node = node.Alias
+			next = findMapValue(node, segment)
+		}
+		if next != nil {
+			node = next
+			continue
+		}
+		var candidates []string
+		if node.Kind == yaml.MappingNode {
+			for k := 0; k+1 < len(node.Content); k += 2 {
+				candidates = append(candidates, node.Content[k].Value)
+			}
+		}
+		suggestions := closestNames(segment, candidates)
+		if i == 2 && segments[0] == "components" {
+			return newFinding(FindingRefMissingComponent,
+				fmt.Sprintf("reference '%s' points to component '%s' in '%s', which does not exist", ref, segment,
+					segments[1]), suggestions)
+		}
+		return newFinding(FindingRefPointer,
+			fmt.Sprintf("reference '%s' cannot be resolved, '%s' does not exist", ref, segment), suggestions)
+	}
+	return nil
+}
+
+// openReferencedFile finds the root node of a file referenced from the file of an index.
+func (w *DrDocument) openReferencedFile(idx *index.SpecIndex, location string, files map[string]*yaml.Node) (*yaml.Node, bool) {
+	abs := referenceLocation(idx, location)
+	if root, ok := files[abs]; ok {
+		return root, true
+	}
+	rolodex := idx.GetRolodex()
+	if rolodex == nil {
+		return nil, false
+	}
+	rf, err := rolodex.Open(abs)
+	if err != nil || rf == nil {
+		return nil, false
+	}
+	root, err := rf.GetContentAsYAMLNode()
+	if err != nil || root == nil {
+		return nil, false
+	}
+	files[abs] = root
+	return root, true
+}
+
+// referenceLocation resolves the file (or URL) part of a reference made from the file of an index, relative
+// locations are resolved against the location of the referencing file.
+func referenceLocation(idx *index.SpecIndex, location string) string {
+	base := idx.GetSpecAbsolutePath()
+	switch {
+	case strings.HasPrefix(location, "http") || filepath.IsAbs(location):
+	case strings.HasPrefix(base, "http"):
+		if u, err := url.Parse(base); err == nil {
+			if r, ko := u.Parse(location); ko == nil {
+				return r.String()
+			}
+		}
+	case base != "":
+		return filepath.Join(filepath.Dir(base), location)
+	case idx.GetRolodex() != nil && idx.GetRolodex().GetConfig() != nil:
+		abs, _ := filepath.Abs(filepath.Join(idx.GetRolodex().GetConfig().BasePath, location))
+		return abs
+	}
+	return location
+}
+
+// findRefs returns the value node of every `$ref` inside a node, in document order.
+func findRefs(node *yaml.Node) []*yaml.Node {
+	var refs []*yaml.Node
+	seen := make(map[*yaml.Node]bool)
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		if n == nil || seen[n] {
+			return
+		}
+		seen[n] = true
+		if n.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if n.Content[i].Value == "$ref" && n.Content[i+1].Kind == yaml.ScalarNode {
+					refs = append(refs, n.Content[i+1])
+				}
+			}
+		}
+		for _, c := range n.Content {
+			walk(c)
+		}
+	}
+	walk(node)
+	return refs
+}
+
+// pointerSegments splits a JSON pointer (the fragment of a reference) into unescaped segments.
+func pointerSegments(pointer string) []string {
+	pointer = strings.TrimPrefix(pointer, "/")
+	if pointer == "" {
+		return nil
+	}
+	segments := strings.Split(pointer, "/")
+	for i, s := range segments {
+		if u, err := url.PathUnescape(s); err == nil {
+			s = u
+		}
+		segments[i] = strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+	}
+	return segments
+}
+
+// closestNames returns the candidates that are a close match to a name, ignoring case, closest first.
+func closestNames(name string, candidates []string) []string {
+	type match struct {
+		name     string
+		distance int
+	}
+	limit := max(2, len(name)/3)
+	var matches []match
+	for _, c := range candidates {
+		if c == name {
+			continue
+		}
+		if d := editDistance(strings.ToLower(name), strings.ToLower(c)); d <= limit {
+			matches = append(matches, match{c, d})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].distance < matches[j].distance })
+	var names []string
+	for i := 0; i < len(matches) && i < maxRefSuggestions; i++ {
+		names = append(names, matches[i].name)
+	}
+	return names
+}
+
+// editDistance is the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr := make([]int, len(rb)+1)
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(rb)]
+}
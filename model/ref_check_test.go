@@ -0,0 +1,88 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+func TestWalker_WalkV3_CheckRefs(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: refs
+  version: 1.0.0
+paths:
+  /burgers:
+    get:
+      responses:
+        "200":
+          description: burgers
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Burgr'
+        "201":
+          description: sizes
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Burger/properties/size'
+        "404":
+          $ref: 'errors.yaml#/NotFound'
+components:
+  schemas:
+    Burger:
+      type: object
+      properties:
+        name:
+          $ref: '#/components/schemas/Name'
+    Name:
+      type: string`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocument(v3Doc)
+
+	findings := walker.CheckRefs()
+	require.Len(t, findings, 3)
+
+	assert.Equal(t, FindingRefMissingComponent, findings[0].Id)
+	assert.Equal(t, "#/components/schemas/Burgr", findings[0].Reference)
+	assert.Equal(t, []string{"Burger"}, findings[0].Suggestions)
+	assert.Equal(t, "reference '#/components/schemas/Burgr' points to component 'Burgr' in 'schemas', which does "+
+		"not exist, did you mean 'Burger'?", findings[0].Message)
+	assert.Equal(t, 14, findings[0].Line)
+	assert.Equal(t, 23, findings[0].Column)
+	assert.Equal(t, base.SeverityError, findings[0].Severity)
+
+	assert.Equal(t, FindingRefPointer, findings[1].Id)
+	assert.Equal(t, "reference '#/components/schemas/Burger/properties/size' cannot be resolved, 'size' does not exist",
+		findings[1].Message)
+	assert.Empty(t, findings[1].Suggestions)
+	assert.Equal(t, 20, findings[1].Line)
+
+	assert.Equal(t, FindingRefMissingFile, findings[2].Id)
+	assert.Equal(t, "errors.yaml#/NotFound", findings[2].Reference)
+	assert.Equal(t, 22, findings[2].Line)
+}
+
+func TestWalker_WalkV3_CheckRefs_Files(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/test-relative/spec.yaml")
+	newDoc, _ := libopenapi.NewDocumentWithConfiguration(bytes, &datamodel.DocumentConfiguration{
+		BasePath:            "../test_specs/test-relative",
+		SpecFilePath:        "test_specs/test-relative/spec.yaml",
+		AllowFileReferences: true,
+	})
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocument(v3Doc)
+
+	// every reference across every file resolves.
+	assert.Empty(t, walker.CheckRefs())
+}
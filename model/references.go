@@ -0,0 +1,79 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+// ReferencedComponents returns the definition of every local component (like `#/components/schemas/Burger`)
+// referenced by a node, following references transitively through the components they point to.
+// Definitions are returned in the order they are first found, references to other files are ignored.
+func (w *DrDocument) ReferencedComponents(node *yaml.Node) []string {
+	if w == nil || w.index == nil || node == nil {
+		return nil
+	}
+	root := documentRoot(w.index.GetRootNode())
+	seen := make(map[string]bool)
+	var found []string
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		if n == nil {
+			return
+		}
+		if n.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if n.Content[i].Value != "$ref" || n.Content[i+1].Kind != yaml.ScalarNode {
+					continue
+				}
+				def := ComponentDefinition(n.Content[i+1].Value)
+				if def == "" || seen[def] {
+					continue
+				}
+				seen[def] = true
+				found = append(found, def)
+				walk(LocateComponentNode(root, def))
+			}
+		}
+		for _, c := range n.Content {
+			walk(c)
+		}
+	}
+	walk(node)
+	return found
+}
+
+// ComponentDefinition truncates a local reference to the component it points into, for example
+// `#/components/schemas/Burger/properties/name` becomes `#/components/schemas/Burger`. An empty string is
+// returned for references that do not point to a local component.
+func ComponentDefinition(ref string) string {
+	if !strings.HasPrefix(ref, "#/components/") {
+		return ""
+	}
+	segments := strings.SplitN(strings.TrimPrefix(ref, "#/components/"), "/", 3)
+	if len(segments) < 2 || segments[0] == "" || segments[1] == "" {
+		return ""
+	}
+	return "#/components/" + segments[0] + "/" + segments[1]
+}
+
+// SplitComponentDefinition breaks a component definition into its kind and (unescaped) name.
+func SplitComponentDefinition(def string) (kind, name string) {
+	segments := strings.SplitN(strings.TrimPrefix(def, "#/components/"), "/", 3)
+	if len(segments) < 2 {
+		return "", ""
+	}
+	return segments[0], strings.ReplaceAll(strings.ReplaceAll(segments[1], "~1", "/"), "~0", "~")
+}
+
+// LocateComponentNode finds the value node of a component definition inside a document root node.
+func LocateComponentNode(root *yaml.Node, def string) *yaml.Node {
+	kind, name := SplitComponentDefinition(def)
+	if kind == "" {
+		return nil
+	}
+	return findMapValue(findMapValue(findMapValue(documentRoot(root), "components"), kind), name)
+}
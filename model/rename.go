@@ -0,0 +1,187 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"fmt"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/index"
+	"gopkg.in/yaml.v3"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ComponentKinds are the component sections that can be renamed with RenameComponent.
+var ComponentKinds = []string{
+	"schemas", "responses", "parameters", "examples", "requestBodies",
+	"headers", "securitySchemes", "links", "callbacks", "pathItems",
+}
+
+// Edit is a single text replacement required to perform a refactor. Line and Column point to the start
+// of the YAML scalar that needs to be changed (including the opening quote, if the scalar is quoted),
+// Original is the current value of the scalar and Replacement is the value it should be changed to.
+type Edit struct {
+	File        string
+	Line        int
+	Column      int
+	Original    string
+	Replacement string
+}
+
+// RenameComponent returns every edit required to rename a component and update everything that points to it.
+// kind is the component section (one of ComponentKinds), for example `schemas`. Every $ref in every file
+// indexed by the rolodex is checked, references that point inside the component (like
+// `#/components/schemas/Burger/properties/name`) are rewritten too. Security schemes are not referenced
+// using $ref, so security requirements using the scheme are renamed instead.
+//
+// The document is not modified, edits are returned sorted by file, line and column.
+func (w *DrDocument) RenameComponent(kind, oldName, newName string) ([]*Edit, error) {
+	if !slices.Contains(ComponentKinds, kind) {
+		return nil, fmt.Errorf("unknown component kind '%s'", kind)
+	}
+	if oldName == newName {
+		return nil, fmt.Errorf("component '%s' already has that name", oldName)
+	}
+	if newName == "" {
+		return nil, fmt.Errorf("new component name cannot be empty")
+	}
+	if w == nil || w.index == nil {
+		return nil, fmt.Errorf("DrDocument is nil, cannot rename component")
+	}
+
+	section := findMapValue(findMapValue(documentRoot(w.index.GetRootNode()), "components"), kind)
+	oldKey, _ := findMapPair(section, oldName)
+	if oldKey == nil {
+		return nil, fmt.Errorf("component '%s' not found in '#/components/%s'", oldName, kind)
+	}
+	if existing, _ := findMapPair(section, newName); existing != nil {
+		return nil, fmt.Errorf("component '%s' already exists in '#/components/%s'", newName, kind)
+	}
+
+	rootPath := w.index.GetSpecAbsolutePath()
+	edits := []*Edit{{
+		File:        rootPath,
+		Line:        oldKey.Line,
+		Column:      oldKey.Column,
+		Original:    oldKey.Value,
+		Replacement: newName,
+	}}
+
+	if kind == "securitySchemes" {
+		edits = append(edits, w.renameSecurityRequirements(rootPath, oldName, newName)...)
+	} else {
+		oldPointer := "#/components/" + kind + "/" + escapePointerSegment(oldName)
+		newPointer := "#/components/" + kind + "/" + escapePointerSegment(newName)
+		target := rootPath + oldPointer
+
+		// the same references are used to build the ref edges in the graph, they
+		// are read from the index so rewrites work regardless of the graph being built.
+		seen := make(map[*yaml.Node]bool)
+		for _, idx := range w.allIndexes() {
+			for _, ref := range idx.GetRawReferencesSequenced() {
+				if ref.KeyNode == nil || seen[ref.KeyNode] {
+					continue
+				}
+				if ref.FullDefinition != target && !strings.HasPrefix(ref.FullDefinition, target+"/") {
+					continue
+				}
+				pos := strings.Index(ref.KeyNode.Value, oldPointer)
+				if pos < 0 {
+					continue
+				}
+				seen[ref.KeyNode] = true
+				edits = append(edits, &Edit{
+					File:        idx.GetSpecAbsolutePath(),
+					Line:        ref.KeyNode.Line,
+					Column:      ref.KeyNode.Column,
+					Original:    ref.KeyNode.Value,
+					Replacement: ref.KeyNode.Value[:pos] + newPointer + ref.KeyNode.Value[pos+len(oldPointer):],
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(edits, func(i, j int) bool {
+		if edits[i].File != edits[j].File {
+			return edits[i].File < edits[j].File
+		}
+		if edits[i].Line != edits[j].Line {
+			return edits[i].Line < edits[j].Line
+		}
+		return edits[i].Column < edits[j].Column
+	})
+	return edits, nil
+}
+
+func (w *DrDocument) renameSecurityRequirements(file, oldName, newName string) []*Edit {
+	var requirements []*drBase.SecurityRequirement
+	if w.V3Document != nil {
+		requirements = append(requirements, w.V3Document.Security...)
+	}
+	for _, po := range w.Operations() {
+		requirements = append(requirements, po.Operation.Security...)
+	}
+	var edits []*Edit
+	for _, sr := range requirements {
+		l := sr.Value.GoLow()
+		if l == nil || l.Requirements.Value == nil {
+			continue
+		}
+		for k := range l.Requirements.Value.KeysFromOldest() {
+			if k.Value == oldName && k.KeyNode != nil {
+				edits = append(edits, &Edit{
+					File:        file,
+					Line:        k.KeyNode.Line,
+					Column:      k.KeyNode.Column,
+					Original:    k.Value,
+					Replacement: newName,
+				})
+			}
+		}
+	}
+	return edits
+}
+
+// allIndexes returns the root index and every other index known to the rolodex.
+func (w *DrDocument) allIndexes() []*index.SpecIndex {
+	indexes := []*index.SpecIndex{w.index}
+	if r := w.index.GetRolodex(); r != nil {
+		for _, idx := range r.GetIndexes() {
+			if idx != w.index {
+				indexes = append(indexes, idx)
+			}
+		}
+	}
+	return indexes
+}
+
+func escapePointerSegment(segment string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(segment, "~", "~0"), "/", "~1")
+}
+
+func documentRoot(node *yaml.Node) *yaml.Node {
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		return node.Content[0]
+	}
+	return node
+}
+
+func findMapPair(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+func findMapValue(node *yaml.Node, key string) *yaml.Node {
+	_, v := findMapPair(node, key)
+	return v
+}
@@ -0,0 +1,45 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+func TestWalker_WalkV3_RenameComponent(t *testing.T) {
+
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ := libopenapi.NewDocument(bytes)
+	v3Doc, _ := newDoc.BuildV3Model()
+
+	walker := NewDrDocument(v3Doc)
+
+	edits, err := walker.RenameComponent("schemas", "Burger", "Sandwich")
+	require.NoError(t, err)
+	require.Len(t, edits, 5)
+	assert.Equal(t, 81, edits[0].Line)
+	assert.Equal(t, "#/components/schemas/Sandwich", edits[0].Replacement)
+	assert.Equal(t, "Burger", edits[3].Original)
+	assert.Equal(t, "Sandwich", edits[3].Replacement)
+	assert.Equal(t, 442, edits[3].Line)
+	assert.Equal(t, 550, edits[4].Line)
+
+	edits, err = walker.RenameComponent("securitySchemes", "OAuthScheme", "OAuth")
+	require.NoError(t, err)
+	require.Len(t, edits, 3)
+	assert.Equal(t, 16, edits[0].Line)
+	assert.Equal(t, 118, edits[1].Line)
+	assert.Equal(t, 373, edits[2].Line)
+
+	_, err = walker.RenameComponent("schemas", "Burger", "Fries")
+	assert.Error(t, err)
+	_, err = walker.RenameComponent("schemas", "Pizza", "Calzone")
+	assert.Error(t, err)
+	_, err = walker.RenameComponent("pizzas", "Burger", "Calzone")
+	assert.Error(t, err)
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"gopkg.in/yaml.v3"
+	"reflect"
+	"sort"
+)
+
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+// Render serializes the document back to YAML or JSON, including any changes made to the high-level
+// values of the model since the walk. Rendering is delegated to libopenapi, which keeps the original
+// ordering of keys (and comments, where the original nodes carry them).
+func (w *DrDocument) Render(format string) ([]byte, error) {
+	if w == nil || w.document == nil {
+		return nil, errors.New("DrDocument is nil, cannot render")
+	}
+	switch format {
+	case "", FormatYAML:
+		return w.document.Render()
+	case FormatJSON:
+		return w.document.RenderJSON("  ")
+	default:
+		return nil, fmt.Errorf("unknown render format '%s'", format)
+	}
+}
+
+// Modified is a dry run of Render, it returns every walked object whose value renders differently to how it
+// rendered when it was walked, sorted by JSONPath. A change to an object also changes the rendering of the
+// objects that contain it, so they are returned too.
+//
+// Changes are only tracked when the document was walked with DrConfig.TrackChanges enabled.
+func (w *DrDocument) Modified() ([]drBase.Foundational, error) {
+	if w == nil || w.snapshots == nil {
+		return nil, errors.New("changes are not tracked, walk the document with TrackChanges enabled")
+	}
+	var modified []drBase.Foundational
+	for f, before := range w.snapshots {
+		if after, ok := renderHash(f); !ok || after != before {
+			modified = append(modified, f)
+		}
+	}
+	sort.Slice(modified, func(i, j int) bool {
+		return modified[i].GenerateJSONPath() < modified[j].GenerateJSONPath()
+	})
+	return modified, nil
+}
+
+// renderHash renders the high-level value of an object and hashes the result.
+func renderHash(f drBase.Foundational) ([32]byte, bool) {
+	hv, ok := f.(HasValue)
+	if !ok {
+		return [32]byte{}, false
+	}
+	value, ok := hv.GetValue().(yaml.Marshaler)
+	if !ok {
+		return [32]byte{}, false
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Pointer && v.IsNil() {
+		return [32]byte{}, false
+	}
+	b, err := yaml.Marshal(value)
+	if err != nil {
+		return [32]byte{}, false
+	}
+	return sha256.Sum256(b), true
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+func TestWalker_WalkV3_Render(t *testing.T) {
+	bs, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ := libopenapi.NewDocument(bs)
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocumentWithConfig(v3Doc, &DrConfig{UseSchemaCache: true, TrackChanges: true})
+
+	modified, err := walker.Modified()
+	require.NoError(t, err)
+	assert.Empty(t, modified)
+
+	op := walker.V3Document.Paths.PathItems.GetOrZero("/burgers").Post
+	op.Value.Summary = "Make a tasty burger"
+	modified, err = walker.Modified()
+	require.NoError(t, err)
+	var paths []string
+	for _, m := range modified {
+		paths = append(paths, m.GenerateJSONPath())
+	}
+	assert.Equal(t, []string{"$", "$.paths", "$.paths['/burgers']", "$.paths['/burgers'].post"}, paths)
+
+	rendered, err := walker.Render(FormatYAML)
+	require.NoError(t, err)
+	assert.Contains(t, string(rendered), "summary: Make a tasty burger")
+
+	rendered, err = walker.Render(FormatJSON)
+	require.NoError(t, err)
+	assert.Contains(t, string(rendered), `"summary": "Make a tasty burger"`)
+
+	_, err = walker.Render("toml")
+	assert.EqualError(t, err, "unknown render format 'toml'")
+
+	_, err = NewDrDocument(v3Doc).Modified()
+	assert.Error(t, err)
+}
@@ -0,0 +1,221 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/datamodel/high"
+	"github.com/pb33f/libopenapi/datamodel/low"
+	"github.com/pb33f/libopenapi/index"
+	"gopkg.in/yaml.v3"
+	"reflect"
+	"slices"
+)
+
+// definitions maps the value node of every walked object to the object, so references can be resolved to the
+// object that was walked at their target.
+type definitions struct {
+	objects map[*yaml.Node]drBase.Foundational
+
+	// references tracks the nodes that are mapped to an object that is itself a reference.
+	references map[*yaml.Node]bool
+}
+
+type resolvable interface {
+	SetResolver(resolver drBase.ReferenceResolver, self drBase.Foundational)
+}
+
+// Resolve returns a dereferenced view of a walked object (see drBase.Foundation.Resolved).
+func (w *DrDocument) Resolve(f drBase.Foundational) *drBase.ResolvedObject {
+	if w == nil || f == nil {
+		return nil
+	}
+	return w.resolve(f, nil)
+}
+
+func (w *DrDocument) resolve(f drBase.Foundational, stack []drBase.Foundational) *drBase.ResolvedObject {
+	resolved := &drBase.ResolvedObject{Object: f}
+
+	// follow the reference, and any references its target makes in turn.
+	chain := []drBase.Foundational{f}
+	for {
+		ref, refNode := objectReference(resolved.Object)
+		if ref == "" {
+			break
+		}
+		if resolved.Reference == "" {
+			resolved.Source = f
+			resolved.Reference = ref
+		}
+		target, _, _ := w.followReference(ref, refNode)
+		if target == nil {
+			break
+		}
+		t := w.definitionObject(target)
+		if t == nil || t == resolved.Object {
+			break
+		}
+		resolved.Object = t
+		if slices.Contains(stack, t) || slices.Contains(chain, t) {
+			resolved.Circular = true
+			return resolved
+		}
+		chain = append(chain, t)
+	}
+	stack = append(stack, resolved.Object)
+	for _, child := range drBase.ChildrenOf(resolved.Object) {
+		resolved.Children = append(resolved.Children, w.resolve(child, stack))
+	}
+	return resolved
+}
+
+// RefChain returns the hops taken to resolve a walked object, when it is a reference (see
+// drBase.Foundation.RefChain).
+func (w *DrDocument) RefChain(f drBase.Foundational) []*drBase.RefHop {
+	if w == nil || f == nil {
+		return nil
+	}
+	ref, refNode := objectReference(f)
+	var hops []*drBase.RefHop
+	visited := make(map[*yaml.Node]bool)
+	source := f
+	for ref != "" {
+		hop := &drBase.RefHop{Reference: ref, Source: source}
+		hops = append(hops, hop)
+		target, idx, origin := w.followReference(ref, refNode)
+		if target == nil {
+			break
+		}
+		hop.Target = w.definitionObject(target)
+		hop.Line, hop.Column = target.Line, target.Column
+		if origin != nil {
+			hop.Origin, hop.Line, hop.Column = origin.AbsoluteLocation, origin.Line, origin.Column
+		}
+		if hop.Origin == "" && idx != nil {
+			hop.Origin = idx.GetSpecAbsolutePath()
+		}
+		if visited[target] {
+			hop.Circular = true
+			break
+		}
+		visited[target] = true
+
+		// keep going when the target is a reference itself.
+		ref, refNode = "", nil
+		if target.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(target.Content); i += 2 {
+				if target.Content[i].Value == "$ref" && target.Content[i+1].Kind == yaml.ScalarNode {
+					ref, refNode = target.Content[i+1].Value, target
+					break
+				}
+			}
+		}
+		source = hop.Target
+	}
+	return hops
+}
+
+// ResolveReference returns the walked object a reference made by an object points to (see
+// drBase.ReferenceResolver). The reference is looked up in the file that contains the object.
+func (w *DrDocument) ResolveReference(ref string, from drBase.Foundational) drBase.Foundational {
+	if w == nil || ref == "" {
+		return nil
+	}
+	var node *yaml.Node
+	if from != nil {
+		node = from.GetValueNode()
+		if node == nil {
+			node = from.GetKeyNode()
+		}
+	}
+	target, _, _ := w.followReference(ref, node)
+	if target == nil {
+		return nil
+	}
+	return w.definitionObject(target)
+}
+
+// objectReference returns the reference held by a walked object, and the node of the reference.
+func objectReference(f drBase.Foundational) (string, *yaml.Node) {
+	hv, ok := f.(HasValue)
+	if !ok || hv.GetValue() == nil {
+		return "", nil
+	}
+	gl, ok := hv.GetValue().(high.GoesLowUntyped)
+	if v := reflect.ValueOf(gl); !ok || v.Kind() == reflect.Pointer && v.IsNil() {
+		return "", nil
+	}
+	r, ok := gl.GoLowUntyped().(low.IsReferenced)
+	if !ok || !r.IsReference() {
+		return "", nil
+	}
+	return r.GetReference(), r.GetReferenceNode()
+}
+
+// followReference finds the node a reference points to, searching the index of the file that contains the
+// reference. The index that contains the target, and the origin of the target (when known) are also returned.
+func (w *DrDocument) followReference(ref string, refNode *yaml.Node) (*yaml.Node, *index.SpecIndex, *index.NodeOrigin) {
+	if w.index == nil {
+		return nil, nil, nil
+	}
+	idx := w.index
+	rolodex := w.index.GetRolodex()
+	if rolodex != nil && refNode != nil {
+		if origin := rolodex.FindNodeOrigin(refNode); origin != nil && origin.AbsoluteLocation != "" {
+			for _, i := range rolodex.GetIndexes() {
+				if i != nil && i.GetSpecAbsolutePath() == origin.AbsoluteLocation {
+					idx = i
+					break
+				}
+			}
+		}
+	}
+	found, foundIdx := idx.SearchIndexForReference(ref)
+	if found == nil || found.Node == nil {
+		return nil, nil, nil
+	}
+	if foundIdx == nil {
+		foundIdx = idx
+	}
+	var origin *index.NodeOrigin
+	if rolodex != nil {
+		origin = rolodex.FindNodeOrigin(found.Node)
+	}
+	return found.Node, foundIdx, origin
+}
+
+// definitionObject returns the walked object for the node of a definition, or nil if nothing was walked there.
+func (w *DrDocument) definitionObject(node *yaml.Node) drBase.Foundational {
+	w.definitionsOnce.Do(func() {
+		w.definitions = &definitions{
+			objects:    make(map[*yaml.Node]drBase.Foundational),
+			references: make(map[*yaml.Node]bool),
+		}
+		if w.V3Document != nil {
+			w.definitions.add(w.V3Document)
+		}
+	})
+	return w.definitions.objects[node]
+}
+
+// add maps an object and its children, the children of references are walked copies of their targets and are not
+// mapped. References are only mapped when they are defined at their node (a component that is a reference to
+// another), the value node of any other reference can be the node of its target. The first object walked at a
+// node wins, unless it is a reference and a definition is found for the node.
+func (d *definitions) add(f drBase.Foundational) {
+	ref, refNode := objectReference(f)
+	if node := f.GetValueNode(); node != nil && (ref == "" || refNode == node) {
+		if _, ok := d.objects[node]; !ok || (d.references[node] && ref == "") {
+			d.objects[node] = f
+			d.references[node] = ref != ""
+		}
+	}
+	if ref != "" {
+		return
+	}
+	for _, child := range drBase.ChildrenOf(f) {
+		d.add(child)
+	}
+}
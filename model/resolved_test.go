@@ -0,0 +1,140 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWalker_WalkV3_Resolved(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: resolved
+  version: 1.0.0
+paths:
+  /burgers:
+    get:
+      parameters:
+        - $ref: '#/components/parameters/Limit'
+      responses:
+        "200":
+          description: burgers
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Meal'
+components:
+  parameters:
+    Limit:
+      name: limit
+      in: query
+      schema:
+        type: integer
+  schemas:
+    Meal:
+      $ref: '#/components/schemas/Burger'
+    Burger:
+      type: object
+      properties:
+        name:
+          type: string
+        next:
+          $ref: '#/components/schemas/Burger'`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocumentAndGraph(v3Doc)
+
+	op := walker.V3Document.Paths.PathItems.GetOrZero("/burgers").Get
+	components := walker.V3Document.Components
+
+	// references are replaced by the walked component objects, not copies.
+	resolved := op.Resolved()
+	require.NotNil(t, resolved)
+	assert.Same(t, op, resolved.Object)
+	param := resolved.Children[0]
+	assert.Equal(t, "#/components/parameters/Limit", param.Reference)
+	assert.Same(t, op.Parameters[0], param.Source)
+	assert.Equal(t, "$.components.parameters['Limit']", param.Object.GenerateJSONPath())
+
+	var schema *base.ResolvedObject
+	var find func(r *base.ResolvedObject)
+	find = func(r *base.ResolvedObject) {
+		if r.Reference == "#/components/schemas/Meal" {
+			schema = r
+		}
+		for _, c := range r.Children {
+			find(c)
+		}
+	}
+	find(resolved)
+	require.NotNil(t, schema)
+	burger := components.Schemas.GetOrZero("Burger")
+	assert.Equal(t, "$.components.schemas['Burger']", schema.Object.GenerateJSONPath())
+	assert.Same(t, burger, schema.Object)
+	assert.False(t, schema.Circular)
+
+	// the circular property stops at the schema already being resolved.
+	var circular []string
+	var walk func(r *base.ResolvedObject)
+	walk = func(r *base.ResolvedObject) {
+		if r.Circular {
+			circular = append(circular, r.Source.GenerateJSONPath())
+		}
+		for _, c := range r.Children {
+			walk(c)
+		}
+	}
+	walk(schema)
+	assert.Equal(t, []string{"$.components.schemas['Burger'].properties['next']"}, circular)
+
+	// the chain hops through Meal to Burger.
+	mediaType := op.Responses.Codes.GetOrZero("200").Content.GetOrZero("application/json")
+	chain := mediaType.SchemaProxy.RefChain()
+	require.Len(t, chain, 2)
+	assert.Equal(t, "#/components/schemas/Meal", chain[0].Reference)
+	assert.Same(t, mediaType.SchemaProxy, chain[0].Source)
+	assert.Same(t, components.Schemas.GetOrZero("Meal"), chain[0].Target)
+	assert.Equal(t, 26, chain[0].Line)
+	assert.Equal(t, "#/components/schemas/Burger", chain[1].Reference)
+	assert.Same(t, components.Schemas.GetOrZero("Meal"), chain[1].Source)
+	assert.Same(t, burger, chain[1].Target)
+	assert.Equal(t, 28, chain[1].Line)
+	assert.Nil(t, op.RefChain())
+}
+
+func TestWalker_WalkV3_RefChainOrigin(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/test-relative/spec.yaml")
+	newDoc, _ := libopenapi.NewDocumentWithConfiguration(bytes, &datamodel.DocumentConfiguration{
+		BasePath:            "../test_specs/test-relative",
+		SpecFilePath:        "test_specs/test-relative/spec.yaml",
+		AllowFileReferences: true,
+	})
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocumentWithConfig(v3Doc, &DrConfig{BuildGraph: false, UseSchemaCache: false})
+
+	// the lemon schema lives in another file, and is only walked through the reference.
+	op := walker.V3Document.Paths.PathItems.GetOrZero("/v3/test").Get
+	proxy := op.Responses.Codes.GetOrZero("200").Content.GetOrZero("application/json").SchemaProxy
+	chain := proxy.RefChain()
+	require.Len(t, chain, 1)
+	assert.Equal(t, "lemons/schemas.yaml#/LemonThing", chain[0].Reference)
+	assert.Nil(t, chain[0].Target)
+	assert.True(t, strings.HasSuffix(chain[0].Origin, "lemons/schemas.yaml"))
+	assert.Equal(t, 5, chain[0].Line)
+	assert.False(t, chain[0].Circular)
+
+	// without a walked target, the walked copy is resolved in place.
+	resolved := proxy.Resolved()
+	assert.Same(t, proxy, resolved.Object)
+	assert.Equal(t, "lemons/schemas.yaml#/LemonThing", resolved.Reference)
+	assert.NotEmpty(t, resolved.Children)
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"fmt"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	drV3 "github.com/pb33f/doctor/model/high/v3"
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+const (
+	FindingResponseHeaderIgnored   = "response-header-ignored"
+	FindingResponseHeaderCollision = "response-header-collision"
+)
+
+// EffectiveHeader is a header a response returns. Inherited headers are declared by the response component the
+// response references. Ignored headers are declared next to the `$ref` of a response, tooling ignores them and
+// only the headers of the referenced component are returned, Header is nil for an ignored header.
+type EffectiveHeader struct {
+	Name      string
+	Header    *drV3.Header
+	Node      *yaml.Node
+	Inherited bool
+	Ignored   bool
+}
+
+// ResponseHeaders are the effective headers of a response of an operation. Reference is the response component
+// the response references (if any) and Component is the walked component.
+type ResponseHeaders struct {
+	Operation *PathOperation
+	Code      string
+	Response  *drV3.Response
+	Reference string
+	Component *drV3.Response
+	Headers   []*EffectiveHeader
+}
+
+// ResponseHeaderReport is the effective headers of every response of every operation.
+type ResponseHeaderReport struct {
+	Responses []*ResponseHeaders
+	Findings  []*drBase.Finding
+}
+
+// ResponseHeaderPropagation lists the effective headers of every response of every operation, the headers the
+// response declares itself, or the headers of the response component it references (see Resolve). A response
+// that references a component cannot override or add to its headers, so headers declared next to the `$ref` are
+// reported as ignored, or as a collision when the component already declares a header with the same
+// (case-insensitive) name. Headers declared more than once with names that only differ by case are reported as
+// collisions.
+func (w *DrDocument) ResponseHeaderPropagation() *ResponseHeaderReport {
+	report := &ResponseHeaderReport{}
+	for _, po := range w.Operations() {
+		for _, cr := range operationResponses(po) {
+			rh := &ResponseHeaders{Operation: po, Code: cr.code, Response: cr.response}
+			report.Responses = append(report.Responses, rh)
+			where := fmt.Sprintf("'%s' response of `%s %s`", cr.code, strings.ToUpper(po.Method), po.Path)
+
+			declaring := cr.response
+			ref, refNode := objectReference(cr.response)
+			if ref != "" {
+				rh.Reference = ref
+				if resolved := w.Resolve(cr.response); resolved != nil {
+					if component, ok := resolved.Object.(*drV3.Response); ok && component != cr.response {
+						rh.Component, declaring = component, component
+					}
+				}
+			}
+
+			seen := make(map[string]*EffectiveHeader)
+			if declaring.Headers != nil {
+				for name, h := range declaring.Headers.FromOldest() {
+					eh := &EffectiveHeader{Name: name, Header: h, Node: h.GetKeyNode(), Inherited: rh.Component != nil}
+					if first := seen[strings.ToLower(name)]; first != nil {
+						report.Findings = append(report.Findings, drBase.NewFinding(FindingResponseHeaderCollision,
+							drBase.SeverityWarn, fmt.Sprintf("%s declares the '%s' header more than once, also as '%s'",
+								where, first.Name, name), h))
+					} else {
+						seen[strings.ToLower(name)] = eh
+					}
+					rh.Headers = append(rh.Headers, eh)
+				}
+			}
+
+			// headers next to the $ref of the response.
+			if ref == "" {
+				continue
+			}
+			for _, key := range siblingHeaders(refNode) {
+				rh.Headers = append(rh.Headers, &EffectiveHeader{Name: key.Value, Node: key, Ignored: true})
+				f := drBase.NewFinding(FindingResponseHeaderIgnored, drBase.SeverityWarn,
+					fmt.Sprintf("%s declares the '%s' header next to a reference to '%s', it is ignored",
+						where, key.Value, ref), cr.response)
+				if first := seen[strings.ToLower(key.Value)]; first != nil {
+					f = drBase.NewFinding(FindingResponseHeaderCollision, drBase.SeverityError,
+						fmt.Sprintf("%s cannot override the '%s' header declared by '%s'", where, first.Name, ref),
+						cr.response)
+				}
+				f.Node = key
+				report.Findings = append(report.Findings, f)
+			}
+		}
+	}
+	return report
+}
+
+// siblingHeaders returns the key nodes of the headers declared in the same mapping as a `$ref`.
+func siblingHeaders(refNode *yaml.Node) []*yaml.Node {
+	headers := findMapValue(refNode, "headers")
+	if headers == nil || headers.Kind != yaml.MappingNode {
+		return nil
+	}
+	var keys []*yaml.Node
+	for i := 0; i+1 < len(headers.Content); i += 2 {
+		keys = append(keys, headers.Content[i])
+	}
+	return keys
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWalker_WalkV3_ResponseHeaderPropagation(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: headers
+  version: 1
+paths:
+  /burgers:
+    get:
+      responses:
+        "200":
+          $ref: '#/components/responses/Limited'
+          headers:
+            x-rate-limit:
+              schema:
+                type: integer
+            X-Trace:
+              schema:
+                type: string
+        "201":
+          description: created
+          headers:
+            Location:
+              schema:
+                type: string
+            location:
+              schema:
+                type: string
+components:
+  responses:
+    Limited:
+      description: rate limited
+      headers:
+        X-Rate-Limit:
+          schema:
+            type: integer`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	v3Doc, _ := doc.BuildV3Model()
+	drDoc := NewDrDocument(v3Doc)
+
+	report := drDoc.ResponseHeaderPropagation()
+	require.Len(t, report.Responses, 2)
+
+	ok := report.Responses[0]
+	assert.Equal(t, "#/components/responses/Limited", ok.Reference)
+	require.NotNil(t, ok.Component)
+	assert.Equal(t, "Limited", ok.Component.Key)
+	require.Len(t, ok.Headers, 3)
+	assert.Equal(t, "X-Rate-Limit", ok.Headers[0].Name)
+	assert.True(t, ok.Headers[0].Inherited)
+	assert.NotNil(t, ok.Headers[0].Header)
+	assert.True(t, ok.Headers[1].Ignored)
+	assert.Nil(t, ok.Headers[1].Header)
+
+	created := report.Responses[1]
+	assert.Empty(t, created.Reference)
+	require.Len(t, created.Headers, 2)
+	assert.False(t, created.Headers[0].Inherited)
+
+	require.Len(t, report.Findings, 3)
+	assert.Equal(t, FindingResponseHeaderCollision, report.Findings[0].Id)
+	assert.Equal(t, "'200' response of `GET /burgers` cannot override the 'X-Rate-Limit' header declared by "+
+		"'#/components/responses/Limited'", report.Findings[0].Message)
+	assert.Equal(t, 12, report.Findings[0].Node.Line)
+	assert.Equal(t, FindingResponseHeaderIgnored, report.Findings[1].Id)
+	assert.Equal(t, FindingResponseHeaderCollision, report.Findings[2].Id)
+	assert.Contains(t, report.Findings[2].Message, "'Location' header more than once, also as 'location'")
+}
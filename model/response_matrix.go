@@ -0,0 +1,88 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	drV3 "github.com/pb33f/doctor/model/high/v3"
+	"strings"
+)
+
+// ResponseMatrix is the set of responses declared by every operation in a document.
+type ResponseMatrix struct {
+	Rows []*ResponseMatrixRow
+}
+
+// ResponseMatrixRow is the set of responses declared by a single operation.
+type ResponseMatrixRow struct {
+	Operation *PathOperation
+
+	// Codes are the declared response codes in the order they are declared, `default` is included when declared.
+	Codes []string
+
+	// ContentTypes maps each declared code to the content types it returns.
+	ContentTypes map[string][]string
+
+	HasDefault bool
+	Has2xx     bool
+	Has4xx     bool
+	Has5xx     bool
+}
+
+// Missing returns the common response classes that the operation does not declare,
+// a `default` response covers missing 4xx and 5xx responses.
+func (r *ResponseMatrixRow) Missing() []string {
+	var missing []string
+	if !r.Has2xx {
+		missing = append(missing, "2xx")
+	}
+	if !r.HasDefault {
+		missing = append(missing, "default")
+		if !r.Has4xx {
+			missing = append(missing, "4xx")
+		}
+		if !r.Has5xx {
+			missing = append(missing, "5xx")
+		}
+	}
+	return missing
+}
+
+// ResponseMatrix builds a ResponseMatrix covering every operation in the document.
+func (w *DrDocument) ResponseMatrix() *ResponseMatrix {
+	m := &ResponseMatrix{}
+	for _, po := range w.Operations() {
+		row := &ResponseMatrixRow{Operation: po, ContentTypes: make(map[string][]string)}
+		add := func(code string, r *drV3.Response) {
+			row.Codes = append(row.Codes, code)
+			if r.Content != nil {
+				for ct := range r.Content.KeysFromOldest() {
+					row.ContentTypes[code] = append(row.ContentTypes[code], ct)
+				}
+			}
+			switch {
+			case code == "default":
+				row.HasDefault = true
+			case strings.HasPrefix(code, "2"):
+				row.Has2xx = true
+			case strings.HasPrefix(code, "4"):
+				row.Has4xx = true
+			case strings.HasPrefix(code, "5"):
+				row.Has5xx = true
+			}
+		}
+		if po.Operation.Responses != nil {
+			if po.Operation.Responses.Codes != nil {
+				for code, r := range po.Operation.Responses.Codes.FromOldest() {
+					add(code, r)
+				}
+			}
+			if po.Operation.Responses.Default != nil {
+				add("default", po.Operation.Responses.Default)
+			}
+		}
+		m.Rows = append(m.Rows, row)
+	}
+	return m
+}
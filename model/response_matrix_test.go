@@ -0,0 +1,29 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+func TestWalker_WalkV3_ResponseMatrix(t *testing.T) {
+
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ := libopenapi.NewDocument(bytes)
+	v3Doc, _ := newDoc.BuildV3Model()
+
+	walker := NewDrDocument(v3Doc)
+	matrix := walker.ResponseMatrix()
+
+	require.NotEmpty(t, matrix.Rows)
+	row := matrix.Rows[0]
+	assert.Equal(t, "/burgers", row.Operation.Path)
+	assert.Equal(t, []string{"200", "500", "422"}, row.Codes)
+	assert.Equal(t, []string{"application/json"}, row.ContentTypes["200"])
+	assert.Equal(t, []string{"default"}, row.Missing())
+}
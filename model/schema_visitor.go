@@ -0,0 +1,106 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"fmt"
+	drV3 "github.com/pb33f/doctor/model/high/v3"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// visitedSchema is a schema reachable from an operation, with the JSONPath it is reachable through.
+type visitedSchema struct {
+	Schema    *base.Schema
+	Proxy     *base.SchemaProxy
+	JSONPath  string
+	Reference string // the innermost $ref followed to reach this schema, if any.
+	Property  string // the property name, if this schema is a property of an object.
+}
+
+// visitOperationSchemas calls visit for every schema reachable from an operation, following references
+// (parameters, request bodies, responses and response headers). Schemas are only visited once per
+// operation, which stops circular references from looping.
+func visitOperationSchemas(po *PathOperation, visit func(v *visitedSchema)) {
+	seen := make(map[*base.Schema]bool)
+	for _, p := range po.Parameters() {
+		if p.SchemaProxy != nil {
+			visitSchemaProxy(p.Value.Schema, p.SchemaProxy.GenerateJSONPath(), "", "", seen, visit)
+		}
+		visitContent(p.Content, seen, visit)
+	}
+	if po.Operation.RequestBody != nil {
+		visitContent(po.Operation.RequestBody.Content, seen, visit)
+	}
+	if po.Operation.Responses != nil {
+		var responses []*drV3.Response
+		if po.Operation.Responses.Codes != nil {
+			for _, r := range po.Operation.Responses.Codes.FromOldest() {
+				responses = append(responses, r)
+			}
+		}
+		if po.Operation.Responses.Default != nil {
+			responses = append(responses, po.Operation.Responses.Default)
+		}
+		for _, r := range responses {
+			if r.Headers != nil {
+				for _, h := range r.Headers.FromOldest() {
+					if h.Schema != nil {
+						visitSchemaProxy(h.Value.Schema, h.Schema.GenerateJSONPath(), "", "", seen, visit)
+					}
+				}
+			}
+			visitContent(r.Content, seen, visit)
+		}
+	}
+}
+
+func visitContent(content *orderedmap.Map[string, *drV3.MediaType], seen map[*base.Schema]bool, visit func(v *visitedSchema)) {
+	if content == nil {
+		return
+	}
+	for _, mt := range content.FromOldest() {
+		if mt.SchemaProxy != nil {
+			visitSchemaProxy(mt.Value.Schema, mt.SchemaProxy.GenerateJSONPath(), "", "", seen, visit)
+		}
+	}
+}
+
+func visitSchemaProxy(proxy *base.SchemaProxy, path, ref, property string, seen map[*base.Schema]bool,
+	visit func(v *visitedSchema)) {
+	if proxy == nil {
+		return
+	}
+	if proxy.IsReference() {
+		ref = proxy.GetReference()
+	}
+	schema := proxy.Schema()
+	if schema == nil || seen[schema] {
+		return
+	}
+	seen[schema] = true
+	visit(&visitedSchema{Schema: schema, Proxy: proxy, JSONPath: path, Reference: ref, Property: property})
+
+	if schema.Properties != nil {
+		for k, p := range schema.Properties.FromOldest() {
+			visitSchemaProxy(p, path+".properties['"+k+"']", ref, k, seen, visit)
+		}
+	}
+	if schema.Items != nil && schema.Items.IsA() {
+		visitSchemaProxy(schema.Items.A, path+".items", ref, "", seen, visit)
+	}
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.IsA() {
+		visitSchemaProxy(schema.AdditionalProperties.A, path+".additionalProperties", ref, "", seen, visit)
+	}
+	for i, s := range schema.AllOf {
+		visitSchemaProxy(s, fmt.Sprintf("%s.allOf[%d]", path, i), ref, "", seen, visit)
+	}
+	for i, s := range schema.OneOf {
+		visitSchemaProxy(s, fmt.Sprintf("%s.oneOf[%d]", path, i), ref, "", seen, visit)
+	}
+	for i, s := range schema.AnyOf {
+		visitSchemaProxy(s, fmt.Sprintf("%s.anyOf[%d]", path, i), ref, "", seen, visit)
+	}
+}
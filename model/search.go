@@ -0,0 +1,242 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"errors"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/datamodel/high"
+	"gopkg.in/yaml.v3"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	SearchSummary     = "summary"
+	SearchDescription = "description"
+	SearchExample     = "example"
+)
+
+// searchFields are the fields of high-level models that are indexed, by the field name used in results.
+var searchFields = []struct {
+	name, field string
+}{
+	{SearchSummary, "Summary"},
+	{SearchDescription, "Description"},
+	{SearchExample, "Example"},
+	{SearchExample, "Value"}, // the value of an Example object.
+}
+
+// snippetRadius is the number of characters either side of the first match included in a snippet.
+const snippetRadius = 40
+
+// SearchResult is a single field of a walked object that matches a search.
+type SearchResult struct {
+	Object   drBase.Foundational
+	JSONPath string
+	Field    string
+	Line     int
+	Text     string
+
+	// Snippet is the text around the first match, with every matching word wrapped in `**`.
+	Snippet string
+}
+
+type searchEntry struct {
+	object drBase.Foundational
+	field  string
+	text   string
+	line   int
+}
+
+// searchIndex is an inverted index of words to the fields that contain them.
+type searchIndex struct {
+	entries []*searchEntry
+	words   map[string][]int
+}
+
+// Search finds every summary, description and example value that contains all the words in text (case is ignored),
+// sorted by JSONPath. Searching requires the document to be walked with DrConfig.BuildSearchIndex enabled.
+func (w *DrDocument) Search(text string) ([]*SearchResult, error) {
+	if w == nil || w.search == nil {
+		return nil, errors.New("search index is not built, walk the document with BuildSearchIndex enabled")
+	}
+	words := searchWords(text)
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	// intersect the entries of every word, starting with the rarest.
+	sort.Slice(words, func(i, j int) bool {
+		return len(w.search.words[words[i]]) < len(w.search.words[words[j]])
+	})
+	matches := make(map[int]bool)
+	for _, e := range w.search.words[words[0]] {
+		matches[e] = true
+	}
+	for _, word := range words[1:] {
+		found := make(map[int]bool)
+		for _, e := range w.search.words[word] {
+			if matches[e] {
+				found[e] = true
+			}
+		}
+		matches = found
+	}
+
+	var results []*SearchResult
+	for e := range matches {
+		entry := w.search.entries[e]
+		results = append(results, &SearchResult{
+			Object:   entry.object,
+			JSONPath: entry.object.GenerateJSONPath(),
+			Field:    entry.field,
+			Line:     entry.line,
+			Text:     entry.text,
+			Snippet:  searchSnippet(entry.text, words),
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].JSONPath == results[j].JSONPath {
+			return results[i].Field < results[j].Field
+		}
+		return results[i].JSONPath < results[j].JSONPath
+	})
+	return results, nil
+}
+
+// add indexes the summary, description and example values of a walked object.
+func (s *searchIndex) add(f drBase.Foundational) {
+	hv, ok := f.(HasValue)
+	if !ok || hv.GetValue() == nil {
+		return
+	}
+	value := reflect.ValueOf(hv.GetValue())
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return
+	}
+	var lowValue reflect.Value
+	if gl, ko := hv.GetValue().(high.GoesLowUntyped); ko {
+		if l := reflect.ValueOf(gl.GoLowUntyped()); l.Kind() == reflect.Pointer && !l.IsNil() {
+			lowValue = l.Elem()
+		}
+	}
+	for _, sf := range searchFields {
+		field := value.Elem().FieldByName(sf.field)
+		if !field.IsValid() {
+			continue
+		}
+		var text string
+		switch v := field.Interface().(type) {
+		case string:
+			text = v
+		case *yaml.Node:
+			text = nodeText(v)
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		line := 0
+		if lowValue.IsValid() && lowValue.Kind() == reflect.Struct {
+			if lf := lowValue.FieldByName(sf.field); lf.IsValid() {
+				if vn, kk := lf.Interface().(interface{ GetValueNode() *yaml.Node }); kk && vn.GetValueNode() != nil {
+					line = vn.GetValueNode().Line
+				}
+			}
+		}
+		s.entries = append(s.entries, &searchEntry{object: f, field: sf.name, text: text, line: line})
+		e := len(s.entries) - 1
+		seen := make(map[string]bool)
+		for _, word := range searchWords(text) {
+			if !seen[word] {
+				seen[word] = true
+				s.words[word] = append(s.words[word], e)
+			}
+		}
+	}
+}
+
+// nodeText joins every scalar value in a node.
+func nodeText(node *yaml.Node) string {
+	if node == nil {
+		return ""
+	}
+	if node.Kind == yaml.ScalarNode {
+		return node.Value
+	}
+	if node.Kind == yaml.AliasNode {
+		return nodeText(node.Alias)
+	}
+	var parts []string
+	for _, n := range node.Content {
+		if t := nodeText(n); t != "" {
+			parts = append(parts, t)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// searchSnippet cuts the text around the first matching word, and highlights every matching word in the cut.
+func searchSnippet(text string, words []string) string {
+	lower := strings.ToLower(text)
+	type span struct{ start, end int }
+	var spans []span
+	isWord := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+	start := -1
+	for i, r := range lower + " " {
+		if isWord(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			for _, w := range words {
+				if lower[start:i] == w {
+					spans = append(spans, span{start, i})
+					break
+				}
+			}
+			start = -1
+		}
+	}
+	if len(spans) == 0 || len(lower) != len(text) {
+		return text
+	}
+	from, to := max(0, spans[0].start-snippetRadius), min(len(text), spans[0].end+snippetRadius)
+	for from > 0 && !utf8.RuneStart(text[from]) {
+		from--
+	}
+	for to < len(text) && !utf8.RuneStart(text[to]) {
+		to++
+	}
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("...")
+	}
+	pos := from
+	for _, sp := range spans {
+		if sp.start < from || sp.end > to {
+			continue
+		}
+		b.WriteString(text[pos:sp.start])
+		b.WriteString("**" + text[sp.start:sp.end] + "**")
+		pos = sp.end
+	}
+	b.WriteString(text[pos:to])
+	if to < len(text) {
+		b.WriteString("...")
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWalker_WalkV3_Search(t *testing.T) {
+	bs, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ := libopenapi.NewDocument(bs)
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocumentWithConfig(v3Doc, &DrConfig{UseSchemaCache: true, BuildSearchIndex: true})
+
+	results, err := walker.Search("Tasty BURGER")
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	for _, r := range results {
+		assert.Contains(t, strings.ToLower(r.Text), "burger")
+		assert.Contains(t, strings.ToLower(r.Text), "tasty")
+		assert.Contains(t, r.Snippet, "**")
+		assert.NotZero(t, r.Line)
+		assert.Equal(t, r.Object.GenerateJSONPath(), r.JSONPath)
+	}
+	var lookup *SearchResult
+	for _, r := range results {
+		if r.JSONPath == "$.paths['/burgers/{burgerId}'].get" && r.Field == SearchDescription {
+			lookup = r
+		}
+	}
+	require.NotNil(t, lookup)
+	assert.Equal(t, 133, lookup.Line)
+	assert.Equal(t, "Look up a **tasty** **burger** take it and enjoy it", lookup.Snippet)
+
+	results, err = walker.Search("no-such-words-anywhere")
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	_, err = NewDrDocument(v3Doc).Search("burger")
+	assert.Error(t, err)
+}
@@ -0,0 +1,199 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"fmt"
+	drV3 "github.com/pb33f/doctor/model/high/v3"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"slices"
+	"strings"
+)
+
+// SecurityAudit describes every security scheme in a document, which operations use them and which operations
+// are not secured at all.
+type SecurityAudit struct {
+	Schemes []*SecuritySchemeAudit
+
+	// Unsecured are operations that can be called without credentials, either because no security applies to
+	// them, or because one of their requirements is empty (`{}`), which makes security optional.
+	Unsecured []*PathOperation
+
+	// UndeclaredSchemes are scheme names used by security requirements that are not defined in the components.
+	UndeclaredSchemes []string
+}
+
+// SecuritySchemeAudit describes a single security scheme, and how it is used.
+type SecuritySchemeAudit struct {
+	Name   string
+	Type   string
+	Scheme *drV3.SecurityScheme
+
+	// Flows are the OAuth2 flows defined by the scheme, in the order implicit, password, clientCredentials
+	// and authorizationCode.
+	Flows []*SecurityFlow
+
+	// Operations are the operations that require the scheme, including operations that inherit the
+	// document level security.
+	Operations []*PathOperation
+
+	// ScopeOperations maps each required scope to the operations that require it.
+	ScopeOperations map[string][]*PathOperation
+
+	// UnusedScopes are scopes declared by the scheme flows, that are not required by any operation.
+	UnusedScopes []string
+}
+
+// SecurityFlow is an OAuth2 flow declared by a security scheme.
+type SecurityFlow struct {
+	Name             string
+	AuthorizationUrl string
+	TokenUrl         string
+	RefreshUrl       string
+	Scopes           []string
+}
+
+// SecurityAudit enumerates all security schemes, their flows and scopes, the operations that use each scheme
+// and scope, the operations that have no security and the scopes that are declared but never required.
+//
+// Operations that do not define security inherit the document level security requirements, operations that
+// define an empty security list opt out of it.
+func (w *DrDocument) SecurityAudit() *SecurityAudit {
+	audit := &SecurityAudit{}
+	if w == nil || w.V3Document == nil {
+		return audit
+	}
+	schemes := make(map[string]*SecuritySchemeAudit)
+	if w.V3Document.Components != nil && w.V3Document.Components.SecuritySchemes != nil {
+		for name, ss := range w.V3Document.Components.SecuritySchemes.FromOldest() {
+			sa := &SecuritySchemeAudit{
+				Name:            name,
+				Type:            ss.Value.Type,
+				Scheme:          ss,
+				Flows:           securityFlows(ss.Value.Flows),
+				ScopeOperations: make(map[string][]*PathOperation),
+			}
+			schemes[name] = sa
+			audit.Schemes = append(audit.Schemes, sa)
+		}
+	}
+
+	var global []*base.SecurityRequirement
+	if w.V3Document.Document != nil {
+		global = w.V3Document.Document.Security
+	}
+	for _, po := range w.Operations() {
+		requirements := po.Operation.Value.Security
+		if requirements == nil {
+			requirements = global
+		}
+		secured := len(requirements) > 0
+		for _, req := range requirements {
+			if req.ContainsEmptyRequirement || req.Requirements == nil || req.Requirements.Len() == 0 {
+				secured = false
+				continue
+			}
+			for name, scopes := range req.Requirements.FromOldest() {
+				sa := schemes[name]
+				if sa == nil {
+					if !slices.Contains(audit.UndeclaredSchemes, name) {
+						audit.UndeclaredSchemes = append(audit.UndeclaredSchemes, name)
+					}
+					continue
+				}
+				if !slices.Contains(sa.Operations, po) {
+					sa.Operations = append(sa.Operations, po)
+				}
+				for _, scope := range scopes {
+					if !slices.Contains(sa.ScopeOperations[scope], po) {
+						sa.ScopeOperations[scope] = append(sa.ScopeOperations[scope], po)
+					}
+				}
+			}
+		}
+		if !secured {
+			audit.Unsecured = append(audit.Unsecured, po)
+		}
+	}
+
+	for _, sa := range audit.Schemes {
+		for _, flow := range sa.Flows {
+			for _, scope := range flow.Scopes {
+				if _, ok := sa.ScopeOperations[scope]; !ok && !slices.Contains(sa.UnusedScopes, scope) {
+					sa.UnusedScopes = append(sa.UnusedScopes, scope)
+				}
+			}
+		}
+	}
+	return audit
+}
+
+func securityFlows(flows *v3.OAuthFlows) []*SecurityFlow {
+	if flows == nil {
+		return nil
+	}
+	var result []*SecurityFlow
+	add := func(name string, flow *v3.OAuthFlow) {
+		if flow == nil {
+			return
+		}
+		sf := &SecurityFlow{
+			Name:             name,
+			AuthorizationUrl: flow.AuthorizationUrl,
+			TokenUrl:         flow.TokenUrl,
+			RefreshUrl:       flow.RefreshUrl,
+		}
+		if flow.Scopes != nil {
+			for scope := range flow.Scopes.KeysFromOldest() {
+				sf.Scopes = append(sf.Scopes, scope)
+			}
+		}
+		result = append(result, sf)
+	}
+	add("implicit", flows.Implicit)
+	add("password", flows.Password)
+	add("clientCredentials", flows.ClientCredentials)
+	add("authorizationCode", flows.AuthorizationCode)
+	return result
+}
+
+// Markdown renders the audit as markdown, a table of schemes followed by the unsecured operations.
+func (a *SecurityAudit) Markdown() string {
+	operation := func(po *PathOperation) string {
+		op := fmt.Sprintf("`%s %s`", strings.ToUpper(po.Method), po.Path)
+		if po.Webhook {
+			op += " (webhook)"
+		}
+		return op
+	}
+	buf := strings.Builder{}
+	buf.WriteString("| Scheme | Type | Flows | Operations | Unused Scopes |\n")
+	buf.WriteString("|--------|------|-------|------------|---------------|\n")
+	for _, sa := range a.Schemes {
+		var flows, ops []string
+		for _, f := range sa.Flows {
+			flows = append(flows, f.Name)
+		}
+		for _, po := range sa.Operations {
+			ops = append(ops, operation(po))
+		}
+		buf.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+			sa.Name, sa.Type, strings.Join(flows, ", "), strings.Join(ops, "<br/>"), strings.Join(sa.UnusedScopes, ", ")))
+	}
+	if len(a.Unsecured) > 0 {
+		buf.WriteString("\n**Unsecured operations**\n\n")
+		for _, po := range a.Unsecured {
+			buf.WriteString(fmt.Sprintf("- %s\n", operation(po)))
+		}
+	}
+	if len(a.UndeclaredSchemes) > 0 {
+		buf.WriteString("\n**Undeclared schemes**\n\n")
+		for _, name := range a.UndeclaredSchemes {
+			buf.WriteString(fmt.Sprintf("- %s\n", name))
+		}
+	}
+	return buf.String()
+}
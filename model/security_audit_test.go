@@ -0,0 +1,99 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWalker_WalkV3_SecurityAudit(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: audit
+  version: 1
+security:
+  - oauth:
+      - read
+paths:
+  /burgers:
+    get:
+      responses:
+        "200":
+          description: ok
+    post:
+      security:
+        - oauth:
+            - read
+            - write
+      responses:
+        "200":
+          description: ok
+  /health:
+    get:
+      security: []
+      responses:
+        "200":
+          description: ok
+  /menu:
+    get:
+      security:
+        - apiKey: []
+        - {}
+        - missing: []
+      responses:
+        "200":
+          description: ok
+components:
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      name: key
+      in: header
+    oauth:
+      type: oauth2
+      flows:
+        clientCredentials:
+          tokenUrl: https://pb33f.io/token
+          scopes:
+            read: read burgers
+            write: write burgers
+            admin: manage burgers`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocument(v3Doc)
+
+	audit := walker.SecurityAudit()
+	assert.Len(t, audit.Schemes, 2)
+
+	apiKey := audit.Schemes[0]
+	assert.Equal(t, "apiKey", apiKey.Name)
+	assert.Equal(t, "apiKey", apiKey.Type)
+	assert.Len(t, apiKey.Operations, 1)
+	assert.Equal(t, "/menu", apiKey.Operations[0].Path)
+
+	oauth := audit.Schemes[1]
+	assert.Equal(t, "oauth2", oauth.Type)
+	assert.Len(t, oauth.Flows, 1)
+	assert.Equal(t, "clientCredentials", oauth.Flows[0].Name)
+	assert.Equal(t, "https://pb33f.io/token", oauth.Flows[0].TokenUrl)
+	assert.Equal(t, []string{"read", "write", "admin"}, oauth.Flows[0].Scopes)
+	assert.Len(t, oauth.Operations, 2)
+	assert.Len(t, oauth.ScopeOperations["read"], 2)
+	assert.Len(t, oauth.ScopeOperations["write"], 1)
+	assert.Equal(t, "post", oauth.ScopeOperations["write"][0].Method)
+	assert.Equal(t, []string{"admin"}, oauth.UnusedScopes)
+
+	assert.Len(t, audit.Unsecured, 2)
+	assert.Equal(t, "/health", audit.Unsecured[0].Path)
+	assert.Equal(t, "/menu", audit.Unsecured[1].Path)
+	assert.Equal(t, []string{"missing"}, audit.UndeclaredSchemes)
+
+	md := audit.Markdown()
+	assert.Contains(t, md, "| oauth | oauth2 | clientCredentials | `GET /burgers`<br/>`POST /burgers` | admin |")
+	assert.Contains(t, md, "- `GET /health`")
+	assert.Contains(t, md, "- missing")
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	drV3 "github.com/pb33f/doctor/model/high/v3"
+)
+
+// ServerURL is a concrete URL produced by expanding one or more servers, Servers holds every server
+// (document, path or operation level) that can produce it.
+type ServerURL struct {
+	URL     string
+	Servers []*drV3.Server
+}
+
+// AllServerURLs returns every concrete URL that can be produced by servers declared at the document, path item
+// and operation levels, with variables expanded using ExpandURLs. URLs are returned in the order they are
+// first seen.
+func (w *DrDocument) AllServerURLs() []*ServerURL {
+	if w == nil || w.V3Document == nil {
+		return nil
+	}
+	var results []*ServerURL
+	seen := make(map[string]*ServerURL)
+	add := func(servers []*drV3.Server) {
+		for _, s := range servers {
+			for _, u := range s.ExpandURLs() {
+				if su, ok := seen[u]; ok {
+					su.Servers = append(su.Servers, s)
+					continue
+				}
+				su := &ServerURL{URL: u, Servers: []*drV3.Server{s}}
+				seen[u] = su
+				results = append(results, su)
+			}
+		}
+	}
+	add(w.V3Document.Servers)
+	if w.V3Document.Paths != nil && w.V3Document.Paths.PathItems != nil {
+		for _, pi := range w.V3Document.Paths.PathItems.FromOldest() {
+			add(pi.Servers)
+			for _, op := range pi.GetOperations().FromOldest() {
+				add(op.Servers)
+			}
+		}
+	}
+	return results
+}
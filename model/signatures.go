@@ -0,0 +1,28 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"strings"
+)
+
+// SignatureManifest maps the operationId of every operation defined in the document to the hash of its contract
+// (see drV3.Operation.SignatureHash). Comparing the manifests of the same API deployed to different environments
+// tells which operations drifted, without a full comparison of the documents. Operations without an operationId
+// are keyed as `METHOD /path` (the name of the webhook for webhooks), when operationIds are duplicated the first
+// operation is used.
+func (w *DrDocument) SignatureManifest() map[string]string {
+	manifest := make(map[string]string)
+	for _, po := range w.Operations() {
+		key := po.Operation.Value.OperationId
+		if key == "" {
+			key = strings.ToUpper(po.Method) + " " + po.Path
+		}
+		if _, ok := manifest[key]; !ok {
+			manifest[key] = po.Operation.SignatureHash()
+		}
+	}
+	return manifest
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestWalker_WalkV3_SignatureManifest(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: burgers
+  version: 1.0.0
+paths:
+  /burgers/{burgerId}:
+    parameters:
+      - name: burgerId
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      operationId: getBurger
+      parameters:
+        - name: X-Trace
+          in: header
+          schema:
+            type: string
+        - name: fries
+          in: query
+          schema:
+            type: boolean
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+        "404":
+          description: not found
+    delete:
+      responses:
+        "204":
+          description: deleted`
+
+	// the same contract, with referenced components, reordered parameters and a different description.
+	same := `openapi: 3.1.0
+info:
+  title: burgers
+  version: 2.0.0
+paths:
+  /burgers/{burgerId}:
+    parameters:
+      - $ref: '#/components/parameters/BurgerId'
+    get:
+      operationId: getBurger
+      description: fetch a burger
+      parameters:
+        - name: fries
+          in: query
+          schema:
+            type: boolean
+        - name: x-trace
+          in: header
+          schema:
+            type: string
+        - name: burgerId
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "404":
+          description: missing
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Burger'
+    delete:
+      responses:
+        "204":
+          description: deleted
+components:
+  parameters:
+    BurgerId:
+      name: burgerId
+      in: path
+      required: true
+      schema:
+        type: string
+  schemas:
+    Burger:
+      type: object
+      properties:
+        name:
+          type: string`
+
+	manifest := func(spec string) map[string]string {
+		doc, err := libopenapi.NewDocument([]byte(spec))
+		require.NoError(t, err)
+		v3Doc, _ := doc.BuildV3Model()
+		return NewDrDocument(v3Doc).SignatureManifest()
+	}
+
+	m := manifest(spec)
+	require.Len(t, m, 2)
+	assert.Len(t, m["getBurger"], 64)
+	assert.NotEqual(t, m["getBurger"], m["DELETE /burgers/{burgerId}"])
+	assert.Equal(t, m, manifest(same))
+
+	// drift in a response schema, a parameter, or a response code.
+	drifted := manifest(strings.Replace(spec, "                    type: string", "                    type: integer", 1))
+	assert.NotEqual(t, m["getBurger"], drifted["getBurger"])
+	assert.Equal(t, m["DELETE /burgers/{burgerId}"], drifted["DELETE /burgers/{burgerId}"])
+	drifted = manifest(strings.Replace(spec, "            type: boolean", "            type: integer", 1))
+	assert.NotEqual(t, m["getBurger"], drifted["getBurger"])
+	drifted = manifest(strings.Replace(spec, `"404"`, `"410"`, 1))
+	assert.NotEqual(t, m["getBurger"], drifted["getBurger"])
+
+	assert.Empty(t, (*DrDocument)(nil).SignatureManifest())
+}
@@ -0,0 +1,242 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	drV3 "github.com/pb33f/doctor/model/high/v3"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"gopkg.in/yaml.v3"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// DefaultSimilarityThreshold is the similarity above which two schemas are reported as similar.
+const DefaultSimilarityThreshold = 0.8
+
+// minShapeFeatures is the smallest shape compared, smaller schemas (like a single string) are far too common
+// to be worth consolidating.
+const minShapeFeatures = 3
+
+// maxShapeDepth limits how deep into nested schemas a shape is taken.
+const maxShapeDepth = 10
+
+// SchemaShape is the structure of a schema, ignoring documentation (titles, descriptions, examples). Features are
+// every structural fact about the schema (such as `properties.name.type=string`), Hash is a hash of them all.
+type SchemaShape struct {
+	Schema   *drBase.Schema
+	Name     string
+	Hash     string
+	Features []string
+}
+
+// DuplicateSchemas is a set of schemas with identical structure.
+type DuplicateSchemas struct {
+	Hash    string
+	Schemas []*SchemaShape
+}
+
+// SimilarSchemas is a pair of schemas whose structure overlaps. Similarity is the proportion of structural
+// features the schemas share (from 0 to 1).
+type SimilarSchemas struct {
+	Left       *SchemaShape
+	Right      *SchemaShape
+	Similarity float64
+}
+
+// SchemaSimilarityReport contains the consolidation candidates found in a document.
+type SchemaSimilarityReport struct {
+	Duplicates []*DuplicateSchemas
+	Similar    []*SimilarSchemas
+}
+
+// SchemaSimilarity compares the structure of every component schema, and every inline object schema, in the
+// document. Schemas with identical structure (under different names) are reported as duplicates, and pairs of
+// schemas with a similarity at or above the threshold are reported as similar, most similar first. A threshold
+// of zero (or less) uses DefaultSimilarityThreshold.
+//
+// References are followed, so a schema that references a component has the same shape as a copy of it.
+func (w *DrDocument) SchemaSimilarity(threshold float64) *SchemaSimilarityReport {
+	report := &SchemaSimilarityReport{}
+	if w == nil {
+		return report
+	}
+	if threshold <= 0 {
+		threshold = DefaultSimilarityThreshold
+	}
+
+	var shapes []*SchemaShape
+	seen := make(map[*yaml.Node]bool)
+	for _, s := range w.Schemas {
+		if s == nil || s.Value == nil || s.Value.GoLow() == nil {
+			continue
+		}
+		proxy, _ := s.Parent.(*drBase.SchemaProxy)
+		if proxy != nil && proxy.Value != nil && proxy.Value.IsReference() {
+			continue
+		}
+		root := s.Value.GoLow().RootNode
+		if root == nil || seen[root] {
+			continue
+		}
+		component := proxy != nil && isComponent(proxy)
+		if !component && (s.Value.Properties == nil || s.Value.Properties.Len() == 0) {
+			continue
+		}
+		seen[root] = true
+		shape := NewSchemaShape(s)
+		if len(shape.Features) < minShapeFeatures {
+			continue
+		}
+		shapes = append(shapes, shape)
+	}
+
+	byHash := make(map[string]*DuplicateSchemas)
+	for _, shape := range shapes {
+		d, ok := byHash[shape.Hash]
+		if !ok {
+			d = &DuplicateSchemas{Hash: shape.Hash}
+			byHash[shape.Hash] = d
+		}
+		d.Schemas = append(d.Schemas, shape)
+		if len(d.Schemas) == 2 {
+			report.Duplicates = append(report.Duplicates, d)
+		}
+	}
+
+	for i := 0; i < len(shapes); i++ {
+		for j := i + 1; j < len(shapes); j++ {
+			a, b := shapes[i], shapes[j]
+			if a.Hash == b.Hash {
+				continue
+			}
+			// the similarity can never be more than the ratio of the sizes of the shapes.
+			small, large := min(len(a.Features), len(b.Features)), max(len(a.Features), len(b.Features))
+			if float64(small)/float64(large) < threshold {
+				continue
+			}
+			if sim := shapeSimilarity(a.Features, b.Features); sim >= threshold {
+				report.Similar = append(report.Similar, &SimilarSchemas{Left: a, Right: b, Similarity: sim})
+			}
+		}
+	}
+	sort.SliceStable(report.Similar, func(i, j int) bool {
+		return report.Similar[i].Similarity > report.Similar[j].Similarity
+	})
+	return report
+}
+
+// NewSchemaShape takes the shape of a walked schema. Component schemas are named after the component, any other
+// schema is named by its JSONPath.
+func NewSchemaShape(s *drBase.Schema) *SchemaShape {
+	shape := &SchemaShape{Schema: s, Name: s.GenerateJSONPath()}
+	if proxy, ok := s.Parent.(*drBase.SchemaProxy); ok && isComponent(proxy) {
+		shape.Name = proxy.Key
+	}
+	features := make(map[string]bool)
+	schemaFeatures(s.Value, "", features, nil)
+	for f := range features {
+		shape.Features = append(shape.Features, f)
+	}
+	sort.Strings(shape.Features)
+	h := sha256.Sum256([]byte(strings.Join(shape.Features, "\n")))
+	shape.Hash = hex.EncodeToString(h[:])
+	return shape
+}
+
+// isComponent returns true if a schema proxy is defined in the schemas of the components object.
+func isComponent(proxy *drBase.SchemaProxy) bool {
+	_, ok := proxy.Parent.(*drV3.Components)
+	return ok
+}
+
+// schemaFeatures collects the structural features of a schema beneath a prefix. The stack holds the root nodes of
+// the schemas being visited, so circular references end.
+func schemaFeatures(s *base.Schema, prefix string, features map[string]bool, stack []*yaml.Node) {
+	if s == nil || len(stack) >= maxShapeDepth {
+		return
+	}
+	if l := s.GoLow(); l != nil && l.RootNode != nil {
+		if slices.Contains(stack, l.RootNode) {
+			features[prefix+"circular"] = true
+			return
+		}
+		stack = append(stack, l.RootNode)
+	}
+	for _, t := range drBase.EffectiveTypes(s) {
+		features[prefix+"type="+t] = true
+	}
+	if s.Format != "" {
+		features[prefix+"format="+s.Format] = true
+	}
+	if s.Const != nil {
+		features[prefix+"const="+s.Const.Value] = true
+	}
+	if len(s.Enum) > 0 {
+		var values []string
+		for _, e := range s.Enum {
+			values = append(values, e.Value)
+		}
+		sort.Strings(values)
+		features[prefix+"enum="+strings.Join(values, ",")] = true
+	}
+	if drBase.IsNullable(s) {
+		features[prefix+"nullable"] = true
+	}
+	for _, r := range s.Required {
+		features[prefix+"required="+r] = true
+	}
+	if s.Properties != nil {
+		for name, p := range s.Properties.FromOldest() {
+			features[prefix+"properties."+name] = true
+			schemaFeatures(p.Schema(), prefix+"properties."+name+".", features, stack)
+		}
+	}
+	if s.Items != nil {
+		if s.Items.IsA() {
+			schemaFeatures(s.Items.A.Schema(), prefix+"items.", features, stack)
+		} else {
+			features[fmt.Sprintf("%sitems=%t", prefix, s.Items.B)] = true
+		}
+	}
+	if s.AdditionalProperties != nil {
+		if s.AdditionalProperties.IsA() {
+			schemaFeatures(s.AdditionalProperties.A.Schema(), prefix+"additionalProperties.", features, stack)
+		} else {
+			features[fmt.Sprintf("%sadditionalProperties=%t", prefix, s.AdditionalProperties.B)] = true
+		}
+	}
+	for kind, proxies := range map[string][]*base.SchemaProxy{"allOf": s.AllOf, "oneOf": s.OneOf, "anyOf": s.AnyOf} {
+		for _, p := range proxies {
+			schemaFeatures(p.Schema(), prefix+kind+".", features, stack)
+		}
+	}
+}
+
+// shapeSimilarity is the Jaccard similarity of two sorted sets of features.
+func shapeSimilarity(a, b []string) float64 {
+	shared, i, j := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			shared++
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	union := len(a) + len(b) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sort"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("burger", "burger"))
+	assert.Equal(t, 1, editDistance("burgr", "burger"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+	assert.Equal(t, 5, editDistance("", "fries"))
+	assert.Equal(t, []string{"Burger", "burgers"}, closestNames("Burgr", []string{"Fries", "burgers", "Burger"}))
+}
+
+func TestWalker_WalkV3_SchemaSimilarity(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: similarity
+  version: 1.0.0
+paths:
+  /burgers:
+    get:
+      responses:
+        "200":
+          description: burgers
+          content:
+            application/json:
+              schema:
+                type: object
+                description: a burger, inline
+                properties:
+                  name:
+                    type: string
+                  price:
+                    type: number
+                    format: float
+                  toppings:
+                    type: array
+                    items:
+                      type: string
+components:
+  schemas:
+    Burger:
+      type: object
+      description: a burger
+      properties:
+        name:
+          type: string
+          description: the name of the burger
+        price:
+          type: number
+          format: float
+        toppings:
+          type: array
+          items:
+            type: string
+    Sandwich:
+      type: object
+      properties:
+        name:
+          type: string
+        price:
+          type: number
+          format: float
+        toppings:
+          type: array
+          items:
+            type: string
+    Meal:
+      type: object
+      properties:
+        name:
+          type: string
+        price:
+          type: number
+          format: float
+        toppings:
+          type: array
+          items:
+            type: string
+        drink:
+          type: string
+    Menu:
+      $ref: '#/components/schemas/Burger'
+    Name:
+      type: string`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocument(v3Doc)
+
+	report := walker.SchemaSimilarity(0)
+	require.Len(t, report.Duplicates, 1)
+	var names []string
+	for _, s := range report.Duplicates[0].Schemas {
+		names = append(names, s.Name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"$.paths['/burgers'].get.responses['200'].content['application/json'].schema",
+		"Burger", "Sandwich"}, names)
+
+	// meal has one more property than the duplicates.
+	require.Len(t, report.Similar, 3)
+	for _, s := range report.Similar {
+		assert.True(t, s.Left.Name == "Meal" || s.Right.Name == "Meal")
+		assert.InDelta(t, 9.0/11, s.Similarity, 0.0001)
+	}
+	assert.Empty(t, walker.SchemaSimilarity(0.95).Similar)
+}
@@ -0,0 +1,244 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/utils"
+	"gopkg.in/yaml.v3"
+	"slices"
+	"strings"
+)
+
+// SubsetOptions controls how a subset of a document is extracted.
+type SubsetOptions struct {
+	// Format is FormatYAML (the default) or FormatJSON.
+	Format string
+
+	// KeepWebhooks keeps every webhook (and the components they reference), webhooks are removed by default.
+	KeepWebhooks bool
+
+	// KeepUnusedTags keeps tags that are not used by any remaining operation, they are removed by default.
+	KeepUnusedTags bool
+}
+
+// ExtractSubset produces a new document containing only the selected paths, and the components they reference
+// (transitively). Each entry is either a path (`/burgers`), which keeps every operation of the path, or a method
+// and a path (`post /burgers`), which keeps a single operation. Security schemes used by the remaining operations
+// (or the document security) are kept, along with tags used by the remaining operations.
+//
+// The DrDocument is not modified, the subset is built from a copy of the root document and is returned as YAML
+// or JSON. Components in other files are left as they are, references to them are not changed.
+func (w *DrDocument) ExtractSubset(paths []string, opts *SubsetOptions) ([]byte, error) {
+	if w == nil || w.index == nil {
+		return nil, fmt.Errorf("DrDocument is nil, cannot extract subset")
+	}
+	if opts == nil {
+		opts = &SubsetOptions{}
+	}
+	if opts.Format != "" && opts.Format != FormatYAML && opts.Format != FormatJSON {
+		return nil, fmt.Errorf("unknown subset format '%s'", opts.Format)
+	}
+
+	selected := make(map[string][]string)
+	for _, entry := range paths {
+		method, path, ok := strings.Cut(strings.TrimSpace(entry), " ")
+		if !ok {
+			method, path = "", method
+		}
+		method, path = strings.ToLower(method), strings.TrimSpace(path)
+		if method != "" && !slices.Contains(drBase.HTTPMethods, method) {
+			return nil, fmt.Errorf("unknown method '%s' in '%s'", method, entry)
+		}
+		selected[path] = append(selected[path], method)
+	}
+
+	root := cloneNode(w.index.GetRootNode())
+	doc := documentRoot(root)
+
+	// remove paths and operations that were not selected.
+	pathsNode := findMapValue(doc, "paths")
+	for path, methods := range selected {
+		_, item := findMapPair(pathsNode, path)
+		if item == nil {
+			return nil, fmt.Errorf("path '%s' not found", path)
+		}
+		for _, m := range methods {
+			if m != "" && findMapValue(item, m) == nil {
+				return nil, fmt.Errorf("operation '%s %s' not found", strings.ToUpper(m), path)
+			}
+		}
+	}
+	filterMap(pathsNode, func(path string, item *yaml.Node) bool {
+		if strings.HasPrefix(path, "x-") {
+			return true
+		}
+		methods, ok := selected[path]
+		if !ok {
+			return false
+		}
+		if !slices.Contains(methods, "") {
+			filterMap(item, func(key string, _ *yaml.Node) bool {
+				return !slices.Contains(drBase.HTTPMethods, key) || slices.Contains(methods, key)
+			})
+		}
+		return true
+	})
+	if !opts.KeepWebhooks {
+		removeMapKey(doc, "webhooks")
+	}
+
+	// work out which components, security schemes and tags are still used.
+	used := make(map[string]bool)
+	for _, def := range w.ReferencedComponents(pathsNode) {
+		used[def] = true
+	}
+	if opts.KeepWebhooks {
+		for _, def := range w.ReferencedComponents(findMapValue(doc, "webhooks")) {
+			used[def] = true
+		}
+	}
+	schemes := make(map[string]bool)
+	tags := make(map[string]bool)
+	collectSecurity(findMapValue(doc, "security"), schemes)
+	for _, section := range []string{"paths", "webhooks"} {
+		for _, item := range mapValues(findMapValue(doc, section)) {
+			for _, m := range drBase.HTTPMethods {
+				op := findMapValue(item, m)
+				if op == nil {
+					continue
+				}
+				collectSecurity(findMapValue(op, "security"), schemes)
+				for _, t := range sequenceValues(findMapValue(op, "tags")) {
+					tags[t.Value] = true
+				}
+			}
+		}
+	}
+	for name := range schemes {
+		for _, def := range w.ReferencedComponents(LocateComponentNode(w.index.GetRootNode(),
+			"#/components/securitySchemes/"+escapePointerSegment(name))) {
+			used[def] = true
+		}
+	}
+
+	components := findMapValue(doc, "components")
+	filterMap(components, func(kind string, section *yaml.Node) bool {
+		if strings.HasPrefix(kind, "x-") {
+			return true
+		}
+		filterMap(section, func(name string, _ *yaml.Node) bool {
+			if kind == "securitySchemes" && schemes[name] {
+				return true
+			}
+			return used["#/components/"+kind+"/"+escapePointerSegment(name)]
+		})
+		return section.Kind != yaml.MappingNode || len(section.Content) > 0
+	})
+	if components != nil && len(components.Content) == 0 {
+		removeMapKey(doc, "components")
+	}
+	if !opts.KeepUnusedTags {
+		if tagsNode := findMapValue(doc, "tags"); tagsNode != nil && tagsNode.Kind == yaml.SequenceNode {
+			tagsNode.Content = slices.DeleteFunc(tagsNode.Content, func(t *yaml.Node) bool {
+				name := findMapValue(t, "name")
+				return name == nil || !tags[name.Value]
+			})
+			if len(tagsNode.Content) == 0 {
+				removeMapKey(doc, "tags")
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return nil, fmt.Errorf("unable to render subset: %w", err)
+	}
+	_ = enc.Close()
+	if opts.Format != FormatJSON {
+		return buf.Bytes(), nil
+	}
+	j, err := utils.ConvertYAMLtoJSON(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("unable to render subset as JSON: %w", err)
+	}
+	var indented bytes.Buffer
+	if err = json.Indent(&indented, j, "", "  "); err != nil {
+		return nil, fmt.Errorf("unable to render subset as JSON: %w", err)
+	}
+	return indented.Bytes(), nil
+}
+
+// collectSecurity adds the scheme names used by a list of security requirements.
+func collectSecurity(security *yaml.Node, schemes map[string]bool) {
+	for _, req := range sequenceValues(security) {
+		if req.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(req.Content); i += 2 {
+			schemes[req.Content[i].Value] = true
+		}
+	}
+}
+
+// cloneNode creates a deep copy of a yaml node, so a document can be rearranged without touching the
+// nodes used by the model.
+func cloneNode(node *yaml.Node) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	c := *node
+	if node.Alias != nil {
+		c.Alias = cloneNode(node.Alias)
+	}
+	if len(node.Content) > 0 {
+		c.Content = make([]*yaml.Node, len(node.Content))
+		for i, n := range node.Content {
+			c.Content[i] = cloneNode(n)
+		}
+	}
+	return &c
+}
+
+// filterMap removes every key of a mapping node that keep returns false for.
+func filterMap(node *yaml.Node, keep func(key string, value *yaml.Node) bool) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+	content := node.Content[:0]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if keep(node.Content[i].Value, node.Content[i+1]) {
+			content = append(content, node.Content[i], node.Content[i+1])
+		}
+	}
+	node.Content = content
+}
+
+func removeMapKey(node *yaml.Node, key string) {
+	filterMap(node, func(k string, _ *yaml.Node) bool { return k != key })
+}
+
+func mapValues(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	var values []*yaml.Node
+	for i := 1; i < len(node.Content); i += 2 {
+		values = append(values, node.Content[i])
+	}
+	return values
+}
+
+func sequenceValues(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return node.Content
+}
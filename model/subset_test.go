@@ -0,0 +1,70 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWalker_WalkV3_ExtractSubset(t *testing.T) {
+	bs, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ := libopenapi.NewDocument(bs)
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocument(v3Doc)
+
+	subset, err := walker.ExtractSubset([]string{"GET /dressings", "/burgers/{burgerId}/dressings"}, nil)
+	require.NoError(t, err)
+
+	subsetDoc, err := libopenapi.NewDocument(subset)
+	require.NoError(t, err)
+	subsetModel, errs := subsetDoc.BuildV3Model()
+	require.Empty(t, errs)
+	m := subsetModel.Model
+
+	assert.Equal(t, 2, m.Paths.PathItems.Len())
+	assert.NotNil(t, m.Paths.PathItems.GetOrZero("/dressings").Get)
+	assert.Equal(t, "milky", m.Paths.Extensions.GetOrZero("x-milky-milk").Value)
+	assert.Nil(t, m.Webhooks)
+
+	var schemas, responses, schemes []string
+	for k := range m.Components.Schemas.KeysFromOldest() {
+		schemas = append(schemas, k)
+	}
+	for k := range m.Components.Responses.KeysFromOldest() {
+		responses = append(responses, k)
+	}
+	for k := range m.Components.SecuritySchemes.KeysFromOldest() {
+		schemes = append(schemes, k)
+	}
+	assert.Equal(t, []string{"Error", "Dressing"}, schemas)
+	assert.Equal(t, []string{"DressingResponse"}, responses)
+	assert.Equal(t, []string{"OAuthScheme"}, schemes)
+	assert.Zero(t, m.Components.RequestBodies.Len())
+	assert.Len(t, m.Tags, 1)
+	assert.Equal(t, "Dressing", m.Tags[0].Name)
+
+	// the subset is valid, so it can be walked too.
+	subsetWalker := NewDrDocument(subsetModel)
+	assert.Empty(t, subsetWalker.BuildErrors)
+	assert.Len(t, subsetWalker.Operations(), 2)
+
+	subset, err = walker.ExtractSubset([]string{"post /burgers"}, &SubsetOptions{Format: FormatJSON, KeepWebhooks: true})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(subset), "{"))
+	assert.Contains(t, string(subset), `"someHook"`)
+	assert.Contains(t, string(subset), `"BurgerRequest"`)
+	assert.NotContains(t, string(subset), `"/dressings"`)
+
+	_, err = walker.ExtractSubset([]string{"/nope"}, nil)
+	assert.EqualError(t, err, "path '/nope' not found")
+	_, err = walker.ExtractSubset([]string{"delete /burgers"}, nil)
+	assert.EqualError(t, err, "operation 'DELETE /burgers' not found")
+	_, err = walker.ExtractSubset([]string{"fetch /burgers"}, nil)
+	assert.EqualError(t, err, "unknown method 'fetch' in 'fetch /burgers'")
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// TagIndex is a taxonomy of the tags in a document and the operations that use them.
+type TagIndex struct {
+	// Declared contains every tag declared in the root `tags` array.
+	Declared []*drBase.Tag
+
+	// Operations groups operations by the tag names they use, in the order they are first seen. Operations
+	// with multiple tags appear under every tag.
+	Operations *orderedmap.Map[string, []*PathOperation]
+
+	// Undeclared groups operations by tags that they use, but are not declared in the root `tags` array.
+	Undeclared *orderedmap.Map[string, []*PathOperation]
+
+	// Untagged contains operations that have no tags at all.
+	Untagged []*PathOperation
+
+	// Unused contains declared tags that are not used by any operation.
+	Unused []*drBase.Tag
+}
+
+// TagIndex builds a TagIndex for the document.
+func (w *DrDocument) TagIndex() *TagIndex {
+	ti := &TagIndex{
+		Operations: orderedmap.New[string, []*PathOperation](),
+		Undeclared: orderedmap.New[string, []*PathOperation](),
+	}
+	if w == nil || w.V3Document == nil {
+		return ti
+	}
+
+	declared := make(map[string]bool)
+	for _, t := range w.V3Document.Tags {
+		ti.Declared = append(ti.Declared, t)
+		declared[t.Value.Name] = true
+	}
+
+	for _, po := range w.Operations() {
+		if len(po.Operation.Value.Tags) == 0 {
+			ti.Untagged = append(ti.Untagged, po)
+			continue
+		}
+		for _, tag := range po.Operation.Value.Tags {
+			ops, _ := ti.Operations.Get(tag)
+			ti.Operations.Set(tag, append(ops, po))
+			if !declared[tag] {
+				ops, _ = ti.Undeclared.Get(tag)
+				ti.Undeclared.Set(tag, append(ops, po))
+			}
+		}
+	}
+
+	for _, t := range ti.Declared {
+		if _, ok := ti.Operations.Get(t.Value.Name); !ok {
+			ti.Unused = append(ti.Unused, t)
+		}
+	}
+	return ti
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestWalker_WalkV3_TagIndex(t *testing.T) {
+
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ := libopenapi.NewDocument(bytes)
+	v3Doc, _ := newDoc.BuildV3Model()
+
+	walker := NewDrDocument(v3Doc)
+	ti := walker.TagIndex()
+
+	assert.Len(t, ti.Declared, 2)
+	assert.Len(t, ti.Operations.GetOrZero("Burgers"), 2)
+	assert.Equal(t, "$.paths['/burgers'].post", ti.Operations.GetOrZero("Burgers")[0].Operation.GenerateJSONPath())
+	assert.Equal(t, 0, ti.Undeclared.Len())
+	assert.Empty(t, ti.Unused)
+}
+
+func TestWalker_WalkV3_TagIndex_Undeclared(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+tags:
+  - name: pizza
+  - name: burgers
+paths:
+  /pizza:
+    get:
+      tags:
+        - pizza
+        - sides
+  /chips:
+    get:
+      description: no tags here`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+
+	walker := NewDrDocument(v3Doc)
+	ti := walker.TagIndex()
+
+	assert.Len(t, ti.Operations.GetOrZero("pizza"), 1)
+	assert.Len(t, ti.Undeclared.GetOrZero("sides"), 1)
+	assert.Equal(t, 7, ti.Undeclared.GetOrZero("sides")[0].Operation.KeyNode.Line)
+	assert.Len(t, ti.Untagged, 1)
+	assert.Equal(t, "/chips", ti.Untagged[0].Path)
+	assert.Len(t, ti.Unused, 1)
+	assert.Equal(t, "burgers", ti.Unused[0].Value.Name)
+}
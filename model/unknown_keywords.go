@@ -0,0 +1,130 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+package model
+
+import (
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/datamodel/high"
+	"github.com/pb33f/libopenapi/datamodel/low"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// UnknownKeyword is a keyword found in the document that the model of the object it belongs to does not define,
+// for example an operation type or a security field added by a newer version of the specification. Keywords
+// are kept as generic doctor objects, the parent is the object that owns the keyword.
+type UnknownKeyword struct {
+	Name  string
+	Value *yaml.Node
+	drBase.Foundation
+}
+
+// UnknownKeywords reports every keyword in the document that is not known to the model of the object it
+// was found on, rather than silently dropping it. Extensions (`x-`) and references (`$ref`) are never reported,
+// and objects with dynamic keys (paths, responses, callbacks and security requirements) are not checked.
+// Keywords are returned in the order they appear in the document.
+func (w *DrDocument) UnknownKeywords() []*UnknownKeyword {
+	if w == nil {
+		return nil
+	}
+	var unknown []*UnknownKeyword
+	seenObjects := make(map[any]bool)
+	seenNodes := make(map[*yaml.Node]bool)
+	for _, objects := range w.lineIndex() {
+		for _, obj := range objects {
+			if seenObjects[obj] {
+				continue
+			}
+			seenObjects[obj] = true
+			f, ok := obj.(drBase.Foundational)
+			if !ok {
+				continue
+			}
+			hv, ok := obj.(HasValue)
+			if !ok {
+				continue
+			}
+			known := knownKeywords(hv.GetValue())
+			if known == nil {
+				continue
+			}
+			node := objectRootNode(hv.GetValue())
+			if node == nil || node.Kind != yaml.MappingNode || seenNodes[node] {
+				continue
+			}
+			seenNodes[node] = true
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key := node.Content[i].Value
+				if known[key] || key == "$ref" || strings.HasPrefix(key, "x-") {
+					continue
+				}
+				k := &UnknownKeyword{Name: key, Value: node.Content[i+1]}
+				k.Parent = f
+				k.PathSegment = key
+				k.KeyNode = node.Content[i]
+				k.ValueNode = node.Content[i+1]
+				unknown = append(unknown, k)
+			}
+		}
+	}
+	sort.SliceStable(unknown, func(i, j int) bool {
+		if unknown[i].KeyNode.Line != unknown[j].KeyNode.Line {
+			return unknown[i].KeyNode.Line < unknown[j].KeyNode.Line
+		}
+		return unknown[i].KeyNode.Column < unknown[j].KeyNode.Column
+	})
+	return unknown
+}
+
+var knownKeywordCache sync.Map
+
+var orderedMapType = reflect.TypeOf((*orderedmap.Map[string, any])(nil)).Elem().PkgPath()
+
+// knownKeywords returns the keywords defined by a high-level libopenapi model (from the yaml tags of its fields),
+// or nil when the value is not a model, or is a model with dynamic keys.
+func knownKeywords(value any) map[string]bool {
+	t := reflect.TypeOf(value)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct ||
+		!strings.HasPrefix(t.Elem().PkgPath(), "github.com/pb33f/libopenapi/datamodel/high") {
+		return nil
+	}
+	if known, ok := knownKeywordCache.Load(t); ok {
+		return known.(map[string]bool)
+	}
+	known := make(map[string]bool)
+	for i := 0; i < t.Elem().NumField(); i++ {
+		field := t.Elem().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			// maps of dynamic keys are not serialized by their tag, the object has no fixed keywords.
+			if field.Name != "Extensions" && field.Type.Kind() == reflect.Ptr && field.Type.Elem().PkgPath() == orderedMapType {
+				known = nil
+				break
+			}
+			continue
+		}
+		if name != "" {
+			known[name] = true
+		}
+	}
+	knownKeywordCache.Store(t, known)
+	return known
+}
+
+// objectRootNode returns the root node of the low-level model behind a high-level model.
+func objectRootNode(value any) *yaml.Node {
+	gl, ok := value.(high.GoesLowUntyped)
+	if !ok {
+		return nil
+	}
+	if rn, ok := gl.GoLowUntyped().(low.HasRootNode); ok {
+		return rn.GetRootNode()
+	}
+	return nil
+}
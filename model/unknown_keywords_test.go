@@ -0,0 +1,60 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package model
+
+import (
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+func TestWalker_WalkV3_UnknownKeywords(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: future
+  version: 1
+  colour: blue
+paths:
+  /burgers:
+    query:
+      responses:
+        "200":
+          description: ok
+    get:
+      responses:
+        "200":
+          description: ok
+components:
+  securitySchemes:
+    oauth:
+      type: oauth2
+      deviceAuthorization: https://pb33f.io/device
+      flows: {}
+  schemas:
+    Burger:
+      x-tasty: true
+      type: object`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocument(v3Doc)
+
+	unknown := walker.UnknownKeywords()
+	require.Len(t, unknown, 3)
+	assert.Equal(t, "colour", unknown[0].Name)
+	assert.Equal(t, "blue", unknown[0].Value.Value)
+	assert.Equal(t, "$.info.colour", unknown[0].GenerateJSONPath())
+	assert.Equal(t, "query", unknown[1].Name)
+	assert.Equal(t, 8, unknown[1].KeyNode.Line)
+	assert.Equal(t, "$.paths['/burgers'].query", unknown[1].GenerateJSONPath())
+	assert.Equal(t, "$.components.securitySchemes['oauth'].deviceAuthorization", unknown[2].GenerateJSONPath())
+
+	// burgershop only uses keywords the model knows.
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ = libopenapi.NewDocument(bytes)
+	v3Doc, _ = newDoc.BuildV3Model()
+	assert.Empty(t, NewDrDocument(v3Doc).UnknownKeywords())
+}
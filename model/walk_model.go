@@ -10,8 +10,12 @@ package model
 import (
 	"context"
 	"fmt"
+	"github.com/google/uuid"
+	"github.com/pb33f/doctor/events"
+	"github.com/pb33f/doctor/internal/routing"
 	drBase "github.com/pb33f/doctor/model/high/base"
 	drV3 "github.com/pb33f/doctor/model/high/v3"
+	"github.com/pb33f/doctor/telemetry"
 	"github.com/pb33f/libopenapi"
 	"github.com/pb33f/libopenapi/datamodel/high"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
@@ -19,7 +23,9 @@ import (
 	"github.com/pb33f/libopenapi/index"
 	"github.com/sourcegraph/conc"
 	"gopkg.in/yaml.v3"
+	"log/slog"
 	"os"
+	"slices"
 	"sort"
 	"strconv"
 	"sync"
@@ -29,27 +35,105 @@ import (
 // provides a much more powerful way to navigate an OpenAPI document.
 //
 // DrDocument also absorbs results from vacuum rules, and allows them to be attached contextually
-// to the models they represent.
+// to the models they represent (see AttachFindings).
 //
 // The doctor is the library we wanted all along. The doctor is the library we deserve.
 type DrDocument struct {
-	BuildErrors    []*drBase.BuildError
-	Schemas        []*drBase.Schema
-	SkippedSchemas []*drBase.Schema
-	Parameters     []*drV3.Parameter
-	Headers        []*drV3.Header
-	MediaTypes     []*drV3.MediaType
-	V3Document     *drV3.Document
-	Nodes          []*drBase.Node
-	Edges          []*drBase.Edge
-	StorageRoot    string
-	index          *index.SpecIndex
-	lineObjects    map[int][]any
+	BuildErrors     []*drBase.BuildError
+	Schemas         []*drBase.Schema
+	SkippedSchemas  []*drBase.Schema
+	Parameters      []*drV3.Parameter
+	Headers         []*drV3.Header
+	MediaTypes      []*drV3.MediaType
+	V3Document      *drV3.Document
+	Nodes           []*drBase.Node
+	Edges           []*drBase.Edge
+	StorageRoot     string
+	index           *index.SpecIndex
+	lineObjects     map[int][]any
+	lineIndexOnce   sync.Once
+	pendingObjects  []any
+	document        *v3.Document
+	config          *DrConfig
+	router          *routing.Router[*drV3.PathItem]
+	routerOnce      sync.Once
+	snapshots       map[drBase.Foundational][32]byte
+	anchors         *anchorIndex
+	search          *searchIndex
+	definitions     *definitions
+	definitionsOnce sync.Once
+	memory          *memoryEstimator
 }
 
 type DrConfig struct {
 	BuildGraph     bool
 	UseSchemaCache bool
+
+	// BuildLineIndex builds the line index used by the LocateModel* methods during the walk. When false
+	// the index is built lazily, the first time it is needed, so consumers that never locate models
+	// by line don't pay for it.
+	BuildLineIndex bool
+
+	// Logger receives structured debug events from the walk (objects walked, schema cache hits and misses,
+	// depth limits and dropped edges). When nil, the logger from the document index is used.
+	Logger *slog.Logger
+
+	// SchemaCache records the schemas a walk has walked, so the targets of references are only walked once by a
+	// walk. It can be shared between walks to bound the memory they use, but a walk never reuses the schemas of
+	// another (see drBase.SchemaCache). When nil, an LRU cache is created for each walk.
+	SchemaCache drBase.SchemaCache
+
+	// CacheJSONPaths generates and caches the JSONPath of every object as it is walked, so later lookups
+	// (locating models, findings, reports) don't have to climb the tree each time.
+	CacheJSONPaths bool
+
+	// TrackChanges records a hash of every walked object's rendered value, so objects that are modified after
+	// the walk can be reported by Modified. Rendering every object makes the walk slower.
+	TrackChanges bool
+
+	// ComputeLayout lays out the graph once it has been built (see LayoutGraph), using LayoutOptions. It has
+	// no effect unless BuildGraph is enabled.
+	ComputeLayout bool
+	LayoutOptions *drBase.LayoutOptions
+
+	// BuildSearchIndex builds a full-text index of summaries, descriptions and examples during the walk, used by Search.
+	BuildSearchIndex bool
+
+	// GraphSink receives the graph instead of the document, Nodes and Edges are left empty and the graph is not laid
+	// out. Nodes are added as they are walked, edges are added once the walk is complete, after their references have
+	// been resolved and edges to nodes that do not exist have been dropped. It has no effect unless BuildGraph is
+	// enabled.
+	GraphSink drBase.GraphSink
+
+	// Sections limits the walk to some top level sections of the document (for example drBase.SectionComponents,
+	// when only schemas are needed), the other sections are left nil. Every section is walked when empty. The
+	// externalDocs of the document are always walked.
+	Sections drBase.Sections
+
+	// Sequential walks the document on the calling goroutine, rather than a goroutine for every object, and collects
+	// what was walked on a single other goroutine. When no SchemaCache is supplied, an OrderedSchemaCache is used,
+	// so the cache keys are stored in the order they were walked. It is slower on most platforms, and is always
+	// enabled for js/wasm, where goroutines are expensive.
+	Sequential bool
+
+	// SizeCalculator sizes the graph node of every walked object, drBase.DefaultSizeCalculator when nil. Nodes
+	// are not sized unless BuildGraph is enabled.
+	SizeCalculator drBase.SizeCalculator
+
+	// Events receives the progress of the walk, and a warning for every build error, on the events.Progress and
+	// events.Warning topics. Nothing is published when nil.
+	Events *events.Bus
+
+	// TracerProvider traces the walk (the walk itself, the resolution of every schema and the build of the graph),
+	// and records the objects walked and the hit rate of the schema cache when it is also a
+	// telemetry.MeterProvider. Nothing is traced when nil.
+	TracerProvider telemetry.TracerProvider
+
+	// MaxMemoryBytes is the memory budget of the model. The size of the model is projected as it is walked, and
+	// the walk is aborted when the projection exceeds the budget, leaving a partial document and a
+	// *MemoryBudgetError (returned by NewDrDocumentWithContext, and by WalkError). The projection is an estimate
+	// of the doctor's model, it does not include the libopenapi model it wraps. There is no budget when zero.
+	MaxMemoryBytes int64
 }
 
 type HasValue interface {
@@ -61,7 +145,7 @@ func NewDrDocument(document *libopenapi.DocumentModel[v3.Document]) *DrDocument
 	doc := &DrDocument{
 		index: document.Index,
 	}
-	doc.walkV3(&document.Model, false, true)
+	doc.walkV3(context.Background(), &document.Model, &DrConfig{UseSchemaCache: true, BuildLineIndex: true})
 	return doc
 }
 
@@ -70,23 +154,71 @@ func NewDrDocumentWithConfig(document *libopenapi.DocumentModel[v3.Document], co
 	doc := &DrDocument{
 		index: document.Index,
 	}
-	doc.walkV3(&document.Model, config.BuildGraph, config.UseSchemaCache)
+	doc.walkV3(context.Background(), &document.Model, config)
 	return doc
 }
 
+// NewDrDocumentWithContext Create a new DrDocument from an OpenAPI v3+ document and a configuration struct, the walk
+// stops as soon as the context is cancelled. When the walk is cancelled, the partially built DrDocument is returned
+// along with an error, the partial model is incomplete and should not be relied upon. The same goes for walks
+// aborted for exceeding config.MaxMemoryBytes, which return a *MemoryBudgetError. A nil configuration uses the
+// same defaults as NewDrDocument.
+func NewDrDocumentWithContext(ctx context.Context, document *libopenapi.DocumentModel[v3.Document], config *DrConfig) (*DrDocument, error) {
+	if config == nil {
+		config = &DrConfig{UseSchemaCache: true, BuildLineIndex: true}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("unable to walk document: %w", err)
+	}
+	doc := &DrDocument{
+		index: document.Index,
+	}
+	doc.walkV3(ctx, &document.Model, config)
+	if err := ctx.Err(); err != nil {
+		return doc, fmt.Errorf("walk cancelled, document model is incomplete: %w", err)
+	}
+	if err := doc.WalkError(); err != nil {
+		return doc, err
+	}
+	return doc, nil
+}
+
 // NewDrDocumentAndGraph Create a new DrDocument from an OpenAPI v3+ document, and create a graph of the model.
 func NewDrDocumentAndGraph(document *libopenapi.DocumentModel[v3.Document]) *DrDocument {
 	doc := &DrDocument{
 		index: document.Index,
 	}
-	doc.walkV3(&document.Model, true, true)
+	doc.walkV3(context.Background(), &document.Model, &DrConfig{BuildGraph: true, UseSchemaCache: true, BuildLineIndex: true})
 	return doc
 }
 
 // LocateModelsByKeyAndValue finds the model represented by the line number of the supplied node. This method will
 // locate every model that points to the supplied key and value node. There could be many models that point to the same
 // key and value node, so this method will return a slice of models.
+//
+// When the value is a YAML anchor, or an alias of one, the models at the anchor definition and at every alias site
+// are returned. Models at alias sites report true from IsAliasUsage.
 func (w *DrDocument) LocateModelsByKeyAndValue(key, value *yaml.Node) ([]drBase.Foundational, error) {
+	models, err := w.locateModelsByKeyAndValue(key, value)
+	if w == nil || key == nil {
+		return models, err
+	}
+	anchored := w.anchorModels(value)
+	if len(anchored) == 0 {
+		return models, err
+	}
+	for _, m := range models {
+		if !slices.Contains(anchored, m) {
+			anchored = append(anchored, m)
+		}
+	}
+	sort.Slice(anchored, func(i, j int) bool {
+		return anchored[i].GenerateJSONPath() < anchored[j].GenerateJSONPath()
+	})
+	return anchored, nil
+}
+
+func (w *DrDocument) locateModelsByKeyAndValue(key, value *yaml.Node) ([]drBase.Foundational, error) {
 	if key == nil {
 		return nil, fmt.Errorf("key is nil, cannot locate model")
 	}
@@ -98,7 +230,7 @@ func (w *DrDocument) LocateModelsByKeyAndValue(key, value *yaml.Node) ([]drBase.
 	}
 
 	// now we have an origin, we can locate that model.
-	if w.lineObjects[key.Line] == nil {
+	if w.lineIndex()[key.Line] == nil {
 		return nil, fmt.Errorf("model not found at line %d", key.Line)
 	}
 
@@ -110,7 +242,7 @@ func (w *DrDocument) LocateModelsByKeyAndValue(key, value *yaml.Node) ([]drBase.
 
 	if origin.AbsoluteLocationValue != "" { // if the key and value have different origins (external refs)
 		// extract objects from line
-		objects := w.lineObjects[origin.LineValue]
+		objects := w.lineIndex()[origin.LineValue]
 		if objects == nil {
 			return nil, fmt.Errorf("model not found at line %d", origin.LineValue)
 		}
@@ -166,7 +298,7 @@ func (w *DrDocument) LocateModelsByKeyAndValue(key, value *yaml.Node) ([]drBase.
 
 	} else { // key and value are in the same origin
 
-		objects := w.lineObjects[origin.Line]
+		objects := w.lineIndex()[origin.Line]
 		if objects == nil {
 			return nil, fmt.Errorf("model not found at line %d", origin.Line)
 		}
@@ -229,11 +361,11 @@ func (w *DrDocument) LocateModel(node *yaml.Node) ([]drBase.Foundational, error)
 	if w == nil {
 		return nil, fmt.Errorf("DrDocument is nil, cannot locate model")
 	}
-	if w.lineObjects[node.Line] == nil {
+	if w.lineIndex()[node.Line] == nil {
 		return nil, fmt.Errorf("model not found at line %d", node.Line)
 	}
-	if len(w.lineObjects[node.Line]) > 0 {
-		r := w.lineObjects[node.Line]
+	if len(w.lineIndex()[node.Line]) > 0 {
+		r := w.lineIndex()[node.Line]
 		result := make([]drBase.Foundational, 0, len(r))
 		for _, item := range r {
 			if f, ok := item.(drBase.Foundational); ok {
@@ -253,11 +385,11 @@ func (w *DrDocument) LocateModelByLine(line int) ([]drBase.Foundational, error)
 	if w == nil {
 		return nil, fmt.Errorf("DrDocument is nil, cannot locate model")
 	}
-	if w.lineObjects[line] == nil {
+	if w.lineIndex()[line] == nil {
 		return nil, fmt.Errorf("model not found at line %d", line)
 	}
-	if len(w.lineObjects[line]) > 0 {
-		r := w.lineObjects[line]
+	if len(w.lineIndex()[line]) > 0 {
+		r := w.lineIndex()[line]
 		result := make([]drBase.Foundational, 0, len(r))
 		for _, item := range r {
 			if f, ok := item.(drBase.Foundational); ok {
@@ -281,10 +413,20 @@ func (w *DrDocument) LocateModelByLine(line int) ([]drBase.Foundational, error)
 	return nil, fmt.Errorf("model not found at line %d", line)
 }
 
+// LayoutGraph computes a hierarchical layout for the graph, assigning coordinates to every node and a route to
+// every edge (see drBase.Layout). The layout is included when nodes and edges are serialized. A nil opts uses
+// the default layout, left to right.
+func (w *DrDocument) LayoutGraph(opts *drBase.LayoutOptions) {
+	if w == nil {
+		return
+	}
+	drBase.Layout(w.Nodes, w.Edges, opts)
+}
+
 // BuildObjectLocationMap builds a map of line numbers to models in the document.
 func (w *DrDocument) BuildObjectLocationMap() map[int]any {
 	objectMap := make(map[int]any)
-	for k, v := range w.lineObjects {
+	for k, v := range w.lineIndex() {
 		objectMap[k] = v
 	}
 	return objectMap
@@ -294,7 +436,20 @@ func (w *DrDocument) GetIndex() *index.SpecIndex {
 	return w.index
 }
 
-func (w *DrDocument) walkV3(doc *v3.Document, buildGraph bool, useCache bool) *drV3.Document {
+func (w *DrDocument) walkV3(ctx context.Context, doc *v3.Document, config *DrConfig) *drV3.Document {
+	buildGraph, useCache, sink := config.BuildGraph, config.UseSchemaCache, config.GraphSink
+	buildLineIndex := config.BuildLineIndex || buildGraph // the graph needs the line index to resolve references.
+	cacheJSONPaths := config.CacheJSONPaths
+	w.document, w.config = doc, config
+	w.snapshots = nil
+	w.anchors = w.buildAnchorIndex()
+	w.search = nil
+	if config.BuildSearchIndex {
+		w.search = &searchIndex{words: make(map[string][]int)}
+	}
+	if config.TrackChanges {
+		w.snapshots = make(map[drBase.Foundational][32]byte)
+	}
 
 	schemaChan := make(chan *drBase.WalkedSchema)
 	skippedSchemaChan := make(chan *drBase.WalkedSchema)
@@ -305,13 +460,42 @@ func (w *DrDocument) walkV3(doc *v3.Document, buildGraph bool, useCache bool) *d
 	objectChan := make(chan any)
 	nodeChan := make(chan *drBase.Node)
 	edgeChan := make(chan *drBase.Edge)
-	var schemaCache sync.Map
+	sequential := config.Sequential || sequentialWalk
+	var wg drBase.WaitGroup = &conc.WaitGroup{}
+	if sequential {
+		wg = drBase.SequentialWaitGroup{}
+	}
+	schemaCache := config.SchemaCache
+	if schemaCache == nil {
+		if sequential {
+			schemaCache = drBase.NewOrderedSchemaCache()
+		} else {
+			schemaCache = drBase.NewSchemaCache(0)
+		}
+	}
+
+	logger := config.Logger
+	if logger == nil {
+		logger = doc.Index.GetLogger()
+	}
 
 	wd, _ := os.Getwd()
 	if wd == "/" {
 		wd = ""
 	}
 
+	w.memory = nil
+	if config.MaxMemoryBytes > 0 {
+		var abort context.CancelCauseFunc
+		ctx, abort = context.WithCancelCause(ctx)
+		defer abort(nil)
+		w.memory = newMemoryEstimator(config.MaxMemoryBytes, abort)
+	}
+	instruments := telemetry.Instrument(config.TracerProvider, telemetryScope)
+	ctx, walkSpan := instruments.Start(ctx, telemetry.SpanWalk)
+	defer walkSpan.End()
+	stats := &drBase.WalkStats{}
+
 	dctx := &drBase.DrContext{
 		SchemaChan:        schemaChan,
 		SkippedSchemaChan: skippedSchemaChan,
@@ -319,22 +503,28 @@ func (w *DrDocument) walkV3(doc *v3.Document, buildGraph bool, useCache bool) *d
 		HeaderChan:        headerChan,
 		MediaTypeChan:     mediaTypeChan,
 		Index:             w.index,
-		WaitGroup:         &conc.WaitGroup{},
+		WaitGroup:         wg,
 		ErrorChan:         buildErrorChan,
 		NodeChan:          nodeChan,
 		EdgeChan:          edgeChan,
 		ObjectChan:        objectChan,
 		V3Document:        doc,
 		BuildGraph:        buildGraph,
-		SchemaCache:       &schemaCache,
+		SchemaCache:       schemaCache,
+		WalkId:            uuid.NewString(),
 		StorageRoot:       doc.GoLow().StorageRoot,
-		Logger:            doc.Index.GetLogger(),
+		Logger:            logger,
 		UseSchemaCache:    useCache,
 		WorkingDirectory:  wd,
+		Sections:          config.Sections,
+		Telemetry:         instruments,
+		Stats:             stats,
 	}
 	w.StorageRoot = doc.GoLow().StorageRoot
 
-	drCtx := context.WithValue(context.Background(), "drCtx", dctx)
+	drCtx := context.WithValue(ctx, "drCtx", dctx)
+	events.Publish(config.Events, events.Progress, events.SourceWalker,
+		&events.ProgressEvent{Stage: "walk", Message: "walking document", Total: 1})
 
 	var schemas []*drBase.Schema
 	var skippedSchemas []*drBase.Schema
@@ -390,6 +580,8 @@ func (w *DrDocument) walkV3(doc *v3.Document, buildGraph bool, useCache bool) *d
 						skippedSchemas = append(skippedSchemas, schema.Schema)
 						skippedSchemasState[key] = true
 					}
+					// skipped schemas walk nothing of their own, link them so their proxies can be resolved.
+					w.linkChild(schema.Schema)
 				}
 			case p := <-parameterChan:
 				if p != nil {
@@ -442,8 +634,15 @@ func (w *DrDocument) walkV3(doc *v3.Document, buildGraph bool, useCache bool) *d
 							nodeValueMap[fmt.Sprint(nt.ValueLine)] = nt
 						}
 					}
-					nodes = append(nodes, nt)
 					nodeIdMap[nt.Id] = nt
+					if sink != nil {
+						if nt.Origin == nil && doc.Index.GetRolodex() != nil {
+							nt.Origin = doc.Index.GetRolodex().FindNodeOrigin(nt.Value)
+						}
+						sink.AddNode(nt)
+					} else {
+						nodes = append(nodes, nt)
+					}
 				}
 
 			case nt := <-dctx.EdgeChan:
@@ -457,11 +656,12 @@ func (w *DrDocument) walkV3(doc *v3.Document, buildGraph bool, useCache bool) *d
 
 			case obj := <-objectChan:
 				if obj != nil {
-					w.processObject(obj, ln)
+					w.processObject(dctx, obj, buildLineIndex, cacheJSONPaths, ln)
 				}
 
 			case buildError := <-buildErrorChan:
 				if buildError != nil {
+					buildError.OwnerPath = buildErrorOwner(buildError)
 					buildErrors = append(buildErrors, buildError)
 				}
 			}
@@ -475,7 +675,14 @@ func (w *DrDocument) walkV3(doc *v3.Document, buildGraph bool, useCache bool) *d
 
 	// wait for any straggling objects
 	for val := range objectChan {
-		w.processObject(val, ln)
+		w.processObject(dctx, val, buildLineIndex, cacheJSONPaths, ln)
+	}
+
+	// component schemas that are references walk nothing of their own, link them so they can be resolved.
+	if drDoc.Components != nil && drDoc.Components.Schemas != nil {
+		for sp := range drDoc.Components.Schemas.ValuesFromOldest() {
+			w.linkChild(sp)
+		}
 	}
 
 	// sort schemas by line number
@@ -494,7 +701,19 @@ func (w *DrDocument) walkV3(doc *v3.Document, buildGraph bool, useCache bool) *d
 		sort.Slice(schemas, orderedFunc)
 	}
 	if len(skippedSchemas) > 0 {
-		sort.Slice(skippedSchemas, orderedFunc)
+		// skipped schemas may not have a key node, use the location of the skip.
+		skippedLine := func(s *drBase.Schema) int {
+			if s.Skip != nil {
+				return s.Skip.Line
+			}
+			if s.GetKeyNode() != nil {
+				return s.GetKeyNode().Line
+			}
+			return 0
+		}
+		sort.SliceStable(skippedSchemas, func(i, j int) bool {
+			return skippedLine(skippedSchemas[i]) < skippedLine(skippedSchemas[j])
+		})
 	}
 	if len(parameters) > 0 {
 		sort.Slice(parameters, orderedFuncParam)
@@ -516,6 +735,7 @@ func (w *DrDocument) walkV3(doc *v3.Document, buildGraph bool, useCache bool) *d
 	var cleanedEdges []*drBase.Edge
 
 	if buildGraph {
+		_, graphSpan := instruments.Start(ctx, telemetry.SpanBuildGraph)
 		for _, edge := range refEdges {
 			//extract node id from line map
 			// check if the target is an int.
@@ -567,10 +787,22 @@ func (w *DrDocument) walkV3(doc *v3.Document, buildGraph bool, useCache bool) *d
 			}
 			if !l && !r {
 				cleanedEdges = append(cleanedEdges, re)
+			} else {
+				dctx.Debug("edge dropped, source or target node does not exist",
+					"edge", re.Id, "sources", re.Sources, "targets", re.Targets)
 			}
 		}
 
-		w.Edges = cleanedEdges
+		if sink != nil {
+			for _, e := range cleanedEdges {
+				sink.AddEdge(e)
+			}
+		} else {
+			w.Edges = cleanedEdges
+		}
+		if config.ComputeLayout && sink == nil {
+			w.LayoutGraph(config.LayoutOptions)
+		}
 
 		// build node tree
 		for _, n := range w.Nodes {
@@ -584,6 +816,8 @@ func (w *DrDocument) walkV3(doc *v3.Document, buildGraph bool, useCache bool) *d
 				}
 			}
 		}
+		graphSpan.SetAttributes(telemetry.Int("nodes", len(nodeIdMap)), telemetry.Int("edges", len(cleanedEdges)))
+		graphSpan.End()
 	}
 
 	if len(w.BuildErrors) > 0 {
@@ -592,6 +826,19 @@ func (w *DrDocument) walkV3(doc *v3.Document, buildGraph bool, useCache bool) *d
 		}
 		sort.Slice(w.BuildErrors, orderedFunc)
 	}
+	for _, buildError := range w.BuildErrors {
+		events.Publish(config.Events, events.Warning, events.SourceWalker,
+			&events.WarningEvent{Message: "unable to build schema", Path: buildError.JSONPath, Err: buildError.Error})
+	}
+	w.recordWalk(ctx, instruments, walkSpan, stats, len(buildErrors))
+	if err := w.WalkError(); err != nil {
+		walkSpan.RecordError(err)
+		events.Publish(config.Events, events.Warning, events.SourceWalker,
+			&events.WarningEvent{Message: "walk aborted, the document model is incomplete", Err: err})
+	}
+	events.Publish(config.Events, events.Progress, events.SourceWalker, &events.ProgressEvent{Stage: "walk",
+		Message: fmt.Sprintf("walked %d schemas, %d parameters and %d headers", len(schemas), len(parameters), len(headers)),
+		Done:    1, Total: 1})
 
 	// clear schema cache
 	drDoc.Document.Rolodex.ClearIndexCaches()
@@ -599,16 +846,103 @@ func (w *DrDocument) walkV3(doc *v3.Document, buildGraph bool, useCache bool) *d
 	return drDoc
 }
 
-func (w *DrDocument) processObject(obj any, ln []any) {
-	if hs, ll := obj.(drBase.HasSize); ll {
-		if f, lt := obj.(drBase.Foundational); lt {
-			he, wi := hs.GetSize()
-			if f.GetNode() != nil {
-				f.GetNode().Width = wi
-				f.GetNode().Height = he
+// telemetryScope is the instrumentation scope of the walk.
+const telemetryScope = "github.com/pb33f/doctor/model"
+
+// recordWalk records what a walk did on its span, and as metrics.
+func (w *DrDocument) recordWalk(ctx context.Context, instruments *telemetry.Instruments, span telemetry.Span,
+	stats *drBase.WalkStats, buildErrors int) {
+	if instruments == nil {
+		return
+	}
+	objects, hits, misses := stats.Objects.Load(), stats.SchemaCacheHits.Load(), stats.SchemaCacheMisses.Load()
+	span.SetAttributes(telemetry.Int("objects", int(objects)), telemetry.Int("schemas", len(w.Schemas)),
+		telemetry.Int("schema_cache.hits", int(hits)), telemetry.Int("schema_cache.misses", int(misses)),
+		telemetry.Int("build_errors", buildErrors))
+	instruments.Add(ctx, telemetry.MetricObjectsWalked, objects)
+	instruments.Add(ctx, telemetry.MetricSchemaCacheHits, hits)
+	instruments.Add(ctx, telemetry.MetricSchemaCacheMisses, misses)
+	if hits+misses > 0 {
+		instruments.Record(ctx, telemetry.MetricSchemaCacheHitRate, float64(hits)/float64(hits+misses))
+	}
+}
+
+// lineIndex returns the line index, building it first if it was deferred during the walk.
+func (w *DrDocument) lineIndex() map[int][]any {
+	w.lineIndexOnce.Do(func() {
+		if w.lineObjects == nil {
+			w.lineObjects = make(map[int][]any)
+		}
+		for _, obj := range w.pendingObjects {
+			w.indexObject(obj, nil)
+		}
+		w.pendingObjects = nil
+	})
+	return w.lineObjects
+}
+
+func (w *DrDocument) processObject(drCtx *drBase.DrContext, obj any, buildLineIndex, cacheJSONPaths bool, ln []any) {
+	w.memory.observe(obj)
+	if f, ok := obj.(drBase.Foundational); ok {
+		if drCtx.Stats != nil {
+			drCtx.Stats.Objects.Add(1)
+		}
+		w.linkChild(f)
+		w.anchors.track(f)
+		if w.search != nil {
+			w.search.add(f)
+		}
+		if w.snapshots != nil {
+			if hash, ok := renderHash(f); ok {
+				w.snapshots[f] = hash
 			}
 		}
+		if cacheJSONPaths {
+			f.GenerateJSONPath()
+		}
+		if drCtx.DebugEnabled() {
+			line := 0
+			if kn := f.GetKeyNode(); kn != nil {
+				line = kn.Line
+			}
+			drCtx.Debug("object walked", "type", fmt.Sprintf("%T", obj), "line", line)
+		}
+	}
+	// nodes are only sized for the graph.
+	if f, ok := obj.(drBase.Foundational); ok && drCtx.BuildGraph && f.GetNode() != nil {
+		var calculator drBase.SizeCalculator = drBase.DefaultSizeCalculator{}
+		if w.config != nil && w.config.SizeCalculator != nil {
+			calculator = w.config.SizeCalculator
+		}
+		n := f.GetNode()
+		n.Height, n.Width = calculator.Size(f, n)
+	}
+	if !buildLineIndex {
+		w.pendingObjects = append(w.pendingObjects, obj)
+		return
+	}
+	w.indexObject(obj, ln)
+}
+
+// linkChild adds a walked object to the children of its parent, and links any parents that were not walked
+// as objects of their own (such as schema proxies) to theirs. Every linked object can resolve its references
+// through the document.
+func (w *DrDocument) linkChild(child drBase.Foundational) {
+	for parent := child.GetParent(); parent != nil; child, parent = parent, parent.GetParent() {
+		if r, ok := child.(resolvable); ok {
+			r.SetResolver(w, child)
+		}
+		if r, ok := parent.(resolvable); ok {
+			r.SetResolver(w, parent)
+		}
+		if hc, ok := parent.(drBase.HasChildren); !ok || !hc.AddChild(child) {
+			return
+		}
 	}
+}
+
+// indexObject adds an object to the line index, against every line it covers.
+func (w *DrDocument) indexObject(obj any, ln []any) {
 	if hv, ok := obj.(HasValue); ok {
 		if gl, ll := hv.GetValue().(high.GoesLowUntyped); ll {
 			if nm, ko := gl.GoLowUntyped().(low.HasNodes); ko {
@@ -632,17 +966,7 @@ func (w *DrDocument) processObject(obj any, ln []any) {
 						} else {
 
 							// check if the object is already in the line objects
-							var found bool
-							b := obj.(drBase.Foundational).GenerateJSONPath()
-							lo := w.lineObjects[k]
-							for _, o := range lo {
-								a := o.(drBase.Foundational).GenerateJSONPath()
-								if a == b {
-									found = true
-									break
-								}
-							}
-							if !found {
+							if !slices.Contains(w.lineObjects[k], obj) {
 								w.lineObjects[k] = append(w.lineObjects[k], obj)
 							}
 						}
@@ -663,17 +987,7 @@ func (w *DrDocument) processObject(obj any, ln []any) {
 						}
 					} else {
 						// check if the object is already in the line objects
-						var found bool
-						b := obj.(drBase.Foundational).GenerateJSONPath()
-						lo := w.lineObjects[kn.Line]
-						for _, o := range lo {
-							a := o.(drBase.Foundational).GenerateJSONPath()
-							if a == b {
-								found = true
-								break
-							}
-						}
-						if !found {
+						if !slices.Contains(w.lineObjects[kn.Line], obj) {
 							w.lineObjects[kn.Line] = append(w.lineObjects[kn.Line], obj)
 						}
 					}
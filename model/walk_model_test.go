@@ -4,17 +4,28 @@
 package model
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"github.com/pb33f/doctor/events"
+	drV3 "github.com/pb33f/doctor/model/high/v3"
+	"github.com/pb33f/doctor/telemetry"
+	highbase "github.com/pb33f/libopenapi/datamodel/high/base"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 	"github.com/pb33f/libopenapi/index"
 	"github.com/stretchr/testify/require"
+	"log/slog"
+	"net/http/httptest"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/pb33f/doctor/model/high/base"
 	"github.com/pb33f/libopenapi"
 	"github.com/pb33f/libopenapi/datamodel"
+	whatChangedModel "github.com/pb33f/libopenapi/what-changed/model"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -1404,3 +1415,982 @@ func TestMultiRefLookup_PetStore_ApiResponse(t *testing.T) {
 	assert.Equal(t, 1156, models[0].GetKeyNode().Line)
 
 }
+
+func TestWalker_WalkV3_AllServerURLs(t *testing.T) {
+
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ := libopenapi.NewDocument(bytes)
+	v3Doc, _ := newDoc.BuildV3Model()
+
+	walker := NewDrDocument(v3Doc)
+
+	assert.Equal(t, []string{"https://api.pb33f.io", "wss://api.pb33f.io"}, walker.V3Document.Servers[0].ExpandURLs())
+	assert.Equal(t, []string{"https://api.pb33f.io.com"}, walker.V3Document.Servers[1].ExpandURLs())
+
+	urls := walker.AllServerURLs()
+	assert.Len(t, urls, 4)
+	assert.Equal(t, "https://pb33f.io", urls[3].URL)
+	assert.Equal(t, "$.paths['/burgers'].post.servers[0]", urls[3].Servers[0].GenerateJSONPath())
+}
+
+func TestWalker_WalkV3_CallbackExpressions(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ := libopenapi.NewDocument(bytes)
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocument(v3Doc)
+
+	callback := walker.V3Document.Components.Callbacks.GetOrZero("BurgerCallback")
+	require.NotNil(t, callback)
+
+	req := httptest.NewRequest("GET", "/burgers/123?queryUrl=https://pb33f.io/hook", nil)
+	urls, err := callback.ResolveExpressions(req)
+	assert.NoError(t, err)
+	require.Len(t, urls, 1)
+	assert.Equal(t, "{$request.query.queryUrl}", urls[0].Expression)
+	assert.Equal(t, "https://pb33f.io/hook", urls[0].URL)
+	assert.NotNil(t, urls[0].PathItem.Post)
+
+	_, err = callback.ResolveExpressions(httptest.NewRequest("GET", "/burgers/123", nil))
+	assert.Error(t, err)
+
+	// templates with multiple expressions, resolved against a request body.
+	req = httptest.NewRequest("POST", "/burgers", strings.NewReader(`{"id": 42, "tags": ["a/b"]}`))
+	req.Header.Set("X-Burger", "cheese")
+	resolved, err := drV3.ResolveExpressionTemplate(
+		"https://pb33f.io/{$request.body#/id}?burger={$request.header.X-Burger}&tag={$request.body#/tags/0}&m={$method}",
+		&drV3.RuntimeContext{Request: req})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://pb33f.io/42?burger=cheese&tag=a/b&m=POST", resolved)
+
+	// syntax errors are reported with positions.
+	for expr, pos := range map[string]int{
+		"$request.query":            14,
+		"$requests.query.q":         1,
+		"$request.cookie.q":         9,
+		"$request.header.bad name":  19,
+		"$request.body#id":          14,
+		"$request.body#/a~2b":       16,
+		"$url.thing":                4,
+		"https://pb33f.io/{$method": 17,
+		"https://pb33f.io/{method}": 18,
+	} {
+		_, err = drV3.ParseExpressionTemplate(expr)
+		var exprErr *drV3.ExpressionError
+		require.True(t, errors.As(err, &exprErr), expr)
+		assert.Equal(t, pos, exprErr.Position, expr)
+	}
+
+	e, err := drV3.ParseRuntimeExpression("$response.body#/id")
+	assert.NoError(t, err)
+	assert.Equal(t, drV3.ExpressionResponse, e.Source)
+	assert.Equal(t, "body", e.Location)
+	assert.Equal(t, "/id", e.Pointer)
+}
+
+func TestWalker_WalkV3_LazyLineIndex(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ := libopenapi.NewDocument(bytes)
+	v3Doc, _ := newDoc.BuildV3Model()
+	eager := NewDrDocument(v3Doc)
+
+	newDoc, _ = libopenapi.NewDocument(bytes)
+	v3Doc, _ = newDoc.BuildV3Model()
+	lazy := NewDrDocumentWithConfig(v3Doc, &DrConfig{UseSchemaCache: true})
+
+	assert.Empty(t, lazy.lineObjects)
+	assert.NotEmpty(t, lazy.pendingObjects)
+
+	models, err := lazy.LocateModelByLine(442)
+	require.NoError(t, err)
+	assert.Equal(t, "$.components.schemas['Burger']", models[0].GenerateJSONPath())
+	assert.Nil(t, lazy.pendingObjects)
+	assert.Len(t, lazy.lineObjects, len(eager.lineObjects))
+}
+
+func TestWalker_WalkV3_CacheJSONPaths(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ := libopenapi.NewDocument(bytes)
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocumentWithConfig(v3Doc, &DrConfig{UseSchemaCache: true, CacheJSONPaths: true})
+
+	for _, p := range walker.Parameters {
+		assert.NotEmpty(t, p.JSONPath)
+	}
+	assert.Equal(t, "$.paths['/burgers']", walker.V3Document.Paths.PathItems.GetOrZero("/burgers").JSONPath)
+
+	// every object is only indexed once per line.
+	for _, objects := range walker.lineIndex() {
+		seen := make(map[any]bool)
+		for _, o := range objects {
+			assert.False(t, seen[o])
+			seen[o] = true
+		}
+	}
+}
+
+func TestWalker_WalkV3_Logger(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ := libopenapi.NewDocument(bytes)
+	v3Doc, _ := newDoc.BuildV3Model()
+
+	buf := strings.Builder{}
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	NewDrDocumentWithConfig(v3Doc, &DrConfig{UseSchemaCache: true, BuildGraph: true, Logger: logger})
+
+	out := buf.String()
+	assert.Contains(t, out, `"msg":"object walked"`)
+	assert.Contains(t, out, `"msg":"schema cache hit"`)
+	assert.Contains(t, out, `"msg":"schema cache miss"`)
+	assert.Contains(t, out, `"type":"*v3.PathItem"`)
+}
+
+func TestNewDrDocumentWithContext(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ := libopenapi.NewDocument(bytes)
+	v3Doc, _ := newDoc.BuildV3Model()
+
+	walker, err := NewDrDocumentWithContext(context.Background(), v3Doc, nil)
+	require.NoError(t, err)
+	assert.NotEmpty(t, walker.Schemas)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	walker, err = NewDrDocumentWithContext(ctx, v3Doc, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, walker)
+}
+
+func TestNewDrDocumentWithContext_Cancelled(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/stripe.yaml")
+	newDoc, _ := libopenapi.NewDocument(bytes)
+	v3Doc, _ := newDoc.BuildV3Model()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	walker, err := NewDrDocumentWithContext(ctx, v3Doc, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotNil(t, walker)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestWalker_WalkV3_SharedSchemaCache(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	cache := base.NewSchemaCache(0)
+
+	newDoc, _ := libopenapi.NewDocument(bytes)
+	v3Doc, _ := newDoc.BuildV3Model()
+	first := NewDrDocumentWithConfig(v3Doc, &DrConfig{UseSchemaCache: true, SchemaCache: cache})
+	assert.NotZero(t, cache.Len())
+
+	newDoc, _ = libopenapi.NewDocument(bytes)
+	v3Doc, _ = newDoc.BuildV3Model()
+	second := NewDrDocumentWithConfig(v3Doc, &DrConfig{UseSchemaCache: true, SchemaCache: cache,
+		BuildLineIndex: true})
+
+	// the second walk walks its own schemas, nothing is shared with the first.
+	burger := second.V3Document.Components.Schemas.GetOrZero("Burger").Schema
+	require.NotNil(t, burger.Properties)
+	assert.NotSame(t, first.V3Document.Components.Schemas.GetOrZero("Burger").Schema, burger)
+	assert.Len(t, second.Schemas, len(first.Schemas))
+	for name, prop := range burger.Properties.FromOldest() {
+		assert.Same(t, burger, prop.GetParent())
+		assert.Equal(t, "$.components.schemas['Burger'].properties['"+name+"']", prop.GenerateJSONPath())
+		assert.Same(t, &second.V3Document.Foundation, prop.GetRoot())
+	}
+
+	models, err := second.LocateModelByLine(442)
+	require.NoError(t, err)
+	var paths []string
+	for _, m := range models {
+		paths = append(paths, m.GenerateJSONPath())
+		assert.Same(t, &second.V3Document.Foundation, m.GetRoot())
+	}
+	assert.Contains(t, paths, "$.components.schemas['Burger']")
+}
+
+func TestWalker_WalkV3_SchemaCache_SameContent(t *testing.T) {
+	yml := `openapi: 3.1.0
+components:
+  schemas:
+    Burger:
+      type: object
+      properties:
+        name:
+          type: string
+    Fries:
+      type: object
+      properties:
+        name:
+          type: string`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(yml))
+	v3Doc, _ := newDoc.BuildV3Model()
+	cache := base.NewSchemaCache(0)
+	walker := NewDrDocumentWithConfig(v3Doc, &DrConfig{UseSchemaCache: true, SchemaCache: cache})
+
+	// both schemas have the same content, and both are walked, and recorded, where they are.
+	for _, name := range []string{"Burger", "Fries"} {
+		schema := walker.V3Document.Components.Schemas.GetOrZero(name).Schema
+		require.NotNil(t, schema.Properties, name)
+		prop := schema.Properties.GetOrZero("name")
+		require.NotNil(t, prop, name)
+		assert.Equal(t, "$.components.schemas['"+name+"'].properties['name']", prop.GenerateJSONPath())
+		assert.Same(t, schema, prop.GetParent())
+	}
+	assert.Len(t, walker.Schemas, 4)
+	assert.Equal(t, 4, cache.Len())
+}
+
+func TestWalker_WalkV3_SharedSchemaCache_Concurrent(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	walk := func(cache base.SchemaCache) *DrDocument {
+		newDoc, _ := libopenapi.NewDocument(bytes)
+		v3Doc, _ := newDoc.BuildV3Model()
+		return NewDrDocumentWithConfig(v3Doc, &DrConfig{UseSchemaCache: true, SchemaCache: cache})
+	}
+	single := base.NewSchemaCache(0)
+	expected := len(walk(single).Schemas)
+
+	cache := base.NewSchemaCache(0)
+	walkers := make([]*DrDocument, 8)
+	var wg sync.WaitGroup
+	for i := range walkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			walkers[i] = walk(cache)
+		}()
+	}
+	wg.Wait()
+
+	// every walk finds all of its schemas, and every schema belongs to the document that walked it.
+	for _, w := range walkers {
+		assert.Len(t, w.Schemas, expected)
+		for _, s := range w.Schemas {
+			assert.Same(t, &w.V3Document.Foundation, s.GetRoot(), s.GenerateJSONPath())
+		}
+	}
+
+	// walks never overwrite each other's entries.
+	assert.Equal(t, len(walkers)*single.Len(), cache.Len())
+}
+
+func TestWalker_WalkV3_SkippedSchemaReasons(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: skip
+  version: 1
+components:
+  schemas:
+    Node:
+      type: object
+      properties:
+        next:
+          $ref: '#/components/schemas/Node'
+    A:
+      type: object
+      properties:
+        b:
+          $ref: '#/components/schemas/B'
+    B:
+      type: object
+      properties:
+        a:
+          $ref: '#/components/schemas/A'
+    Broken:
+      type: object
+      additionalProperties: indeterminate`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocument(v3Doc)
+
+	require.Len(t, walker.SkippedSchemas, 3)
+
+	next := walker.SkippedSchemas[0]
+	assert.Equal(t, base.SkipSelfReference, next.Skip.Reason)
+	assert.Equal(t, "#/components/schemas/Node", next.Skip.Reference)
+	assert.Equal(t, 11, next.Skip.Line)
+	assert.Equal(t, "$.components.schemas['Node'].properties['next']", next.GenerateJSONPath())
+
+	b := walker.SkippedSchemas[1]
+	assert.Equal(t, base.SkipCircular, b.Skip.Reason)
+	assert.Equal(t, "#/components/schemas/B", b.Skip.Reference)
+	assert.Equal(t, 16, b.Skip.Line)
+
+	broken := walker.SkippedSchemas[2]
+	assert.Equal(t, base.SkipBuildError, broken.Skip.Reason)
+	assert.Error(t, broken.Skip.Error)
+	assert.Equal(t, 22, broken.Skip.Line)
+}
+
+func TestWalker_WalkV3_ContentTypes(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: content
+  version: 1
+paths:
+  /burgers:
+    post:
+      requestBody:
+        content:
+          application/json; charset=utf-8:
+            schema:
+              type: object
+          application/*:
+            schema:
+              type: string
+          '*/*':
+            schema:
+              type: string
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+            application/yaml:
+              schema:
+                type: object
+            text/*:
+              schema:
+                type: string`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocument(v3Doc)
+
+	op := walker.V3Document.Paths.PathItems.GetOrZero("/burgers").Post
+	rb := op.RequestBody
+	types := rb.MediaTypes()
+	assert.Len(t, types, 3)
+	assert.Equal(t, "application", types[0].Type)
+	assert.Equal(t, "json", types[0].Subtype)
+	assert.Equal(t, "utf-8", types[0].Parameters["charset"])
+	assert.False(t, types[0].Wildcard)
+	assert.True(t, types[1].Wildcard)
+	assert.True(t, types[1].Matches("application/xml"))
+	assert.False(t, types[1].Matches("text/plain"))
+
+	assert.Equal(t, types[0].MediaType, rb.MatchContentType("application/json"))
+	assert.Equal(t, types[1].MediaType, rb.MatchContentType("application/xml"))
+	assert.Equal(t, types[2].MediaType, rb.MatchContentType("text/plain"))
+
+	resp := op.Responses.Codes.GetOrZero("200")
+	assert.Nil(t, resp.MatchContentType("image/png"))
+
+	ct, mt := resp.PickContentType("")
+	assert.Equal(t, "application/json", ct)
+	assert.Equal(t, resp.Content.GetOrZero("application/json"), mt)
+
+	ct, _ = resp.PickContentType("application/json;q=0.5, application/yaml")
+	assert.Equal(t, "application/yaml", ct)
+
+	ct, _ = resp.PickContentType("application/*;q=0.2, application/json;q=0")
+	assert.Equal(t, "application/yaml", ct)
+
+	ct, mt = resp.PickContentType("text/csv")
+	assert.Equal(t, "text/csv", ct)
+	assert.Equal(t, resp.Content.GetOrZero("text/*"), mt)
+
+	ct, mt = resp.PickContentType("image/png")
+	assert.Empty(t, ct)
+	assert.Nil(t, mt)
+}
+
+func TestWalker_WalkV3_GetChildren(t *testing.T) {
+	bs, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ := libopenapi.NewDocument(bs)
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocument(v3Doc)
+
+	doc := walker.V3Document
+	var segments []string
+	for _, c := range doc.GetChildren() {
+		assert.Equal(t, base.Foundational(doc), c.GetParent())
+		segments = append(segments, c.GetPathSegment())
+	}
+	assert.Contains(t, segments, "info")
+	assert.Contains(t, segments, "paths")
+	assert.Contains(t, segments, "components")
+
+	op := doc.Paths.PathItems.GetOrZero("/burgers").Post
+	children := op.GetChildren()
+	assert.NotEmpty(t, children)
+	assert.Contains(t, children, base.Foundational(op.RequestBody))
+	assert.Contains(t, children, base.Foundational(op.Responses))
+	line := 0
+	for _, c := range children {
+		if c.GetKeyNode() != nil {
+			assert.LessOrEqual(t, line, c.GetKeyNode().Line)
+			line = c.GetKeyNode().Line
+		}
+	}
+
+	// every walked object is reachable from the document.
+	var count func(f base.Foundational) int
+	count = func(f base.Foundational) int {
+		n := 1
+		for _, c := range base.ChildrenOf(f) {
+			n += count(c)
+		}
+		return n
+	}
+	assert.Greater(t, count(doc), len(walker.Schemas))
+	for _, s := range walker.Schemas {
+		var root base.Foundational = s
+		for root.GetParent() != nil {
+			assert.Contains(t, base.ChildrenOf(root.GetParent()), root)
+			root = root.GetParent()
+		}
+		assert.Equal(t, base.Foundational(doc), root)
+	}
+}
+
+func TestWalker_WalkV3_LayoutGraph(t *testing.T) {
+	bs, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ := libopenapi.NewDocument(bs)
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocumentWithConfig(v3Doc, &DrConfig{UseSchemaCache: true, BuildGraph: true, ComputeLayout: true})
+
+	require.NotEmpty(t, walker.Nodes)
+	layers := make(map[int][]*base.Node)
+	for _, n := range walker.Nodes {
+		require.NotNil(t, n.Layout, n.Id)
+		layers[n.Layout.Layer] = append(layers[n.Layout.Layer], n)
+	}
+	assert.Equal(t, 0, walker.Nodes[0].Layout.Layer)
+	for _, nodes := range layers {
+		for i := 1; i < len(nodes); i++ {
+			assert.NotEqual(t, nodes[i-1].Layout.Y, nodes[i].Layout.Y)
+		}
+	}
+	for _, e := range walker.Edges {
+		assert.GreaterOrEqual(t, len(e.Points), 2, e.Id)
+	}
+
+	b, err := walker.Nodes[1].MarshalJSON()
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"layer":`)
+}
+
+func TestWalker_WalkV3_EdgeRelationships(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: relationships
+  version: 1.0.0
+paths:
+  /burgers:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: burgers
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Burger'
+components:
+  schemas:
+    Burger:
+      allOf:
+        - type: object
+          properties:
+            patty:
+              type: object
+              properties:
+                weight:
+                  type: number
+            toppings:
+              type: array
+              items:
+                type: object
+                properties:
+                  name:
+                    type: string`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+	walker := NewDrDocumentAndGraph(v3Doc)
+
+	relationships := make(map[string]base.Relationship)
+	for _, e := range walker.Edges {
+		relationships[e.Targets[0]] = e.Relationship
+		if e.Ref != "" {
+			assert.Equal(t, base.RelationshipRef, e.Relationship)
+		}
+	}
+	assert.Equal(t, base.RelationshipParameterOf, relationships["$.paths['/burgers'].get.parameters[0]"])
+	assert.Equal(t, base.RelationshipResponseOf, relationships["$.paths['/burgers'].get.responses['200']"])
+	assert.Equal(t, base.RelationshipChildOf, relationships["$.paths['/burgers'].get"])
+	assert.Equal(t, base.RelationshipAllOfMember, relationships["$.components.schemas['Burger'].allOf[0]"])
+	assert.Equal(t, base.RelationshipPropertyOf, relationships["$.components.schemas['Burger'].allOf[0].properties['patty']"])
+	assert.Equal(t, base.RelationshipItemOf,
+		relationships["$.components.schemas['Burger'].allOf[0].properties['toppings'].items"])
+	assert.Contains(t, relationships, "$.components.schemas['Burger']")
+	for target, r := range relationships {
+		assert.NotEmpty(t, r, target)
+	}
+}
+
+type collectingSink struct {
+	nodes []*base.Node
+	edges []*base.Edge
+}
+
+func (c *collectingSink) AddNode(node *base.Node) { c.nodes = append(c.nodes, node) }
+func (c *collectingSink) AddEdge(edge *base.Edge) { c.edges = append(c.edges, edge) }
+
+func TestWalker_WalkV3_GraphSink(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc := func() *libopenapi.DocumentModel[v3.Document] {
+		doc, err := libopenapi.NewDocument(bytes)
+		require.NoError(t, err)
+		v3Doc, _ := doc.BuildV3Model()
+		return v3Doc
+	}
+	// the schema cache is not used, so both walks build the same graph.
+	graph := NewDrDocumentWithConfig(newDoc(), &DrConfig{BuildGraph: true})
+
+	sink := &collectingSink{}
+	streamed := NewDrDocumentWithConfig(newDoc(), &DrConfig{BuildGraph: true, GraphSink: sink})
+	assert.Empty(t, streamed.Nodes)
+	assert.Empty(t, streamed.Edges)
+	require.NotEmpty(t, sink.nodes)
+	assert.Len(t, sink.nodes, len(graph.Nodes))
+	assert.Len(t, sink.edges, len(graph.Edges))
+
+	ids := make(map[string]bool)
+	clusters := make(map[string]int)
+	for _, n := range sink.nodes {
+		ids[n.Id] = true
+		assert.NotNil(t, n.Origin)
+		assert.NotEmpty(t, n.Cluster, n.Id)
+		clusters[n.Cluster]++
+	}
+	assert.Equal(t, 1, clusters[base.ClusterDocument])
+	assert.NotZero(t, clusters[base.ClusterPaths])
+	assert.NotZero(t, clusters[base.ClusterSchemas])
+	for _, e := range sink.edges {
+		assert.True(t, ids[e.Sources[0]])
+		assert.True(t, ids[e.Targets[0]])
+	}
+}
+
+func TestWalker_WalkV3_Sections(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	doc, err := libopenapi.NewDocument(bytes)
+	require.NoError(t, err)
+	v3Doc, _ := doc.BuildV3Model()
+
+	walked := NewDrDocumentWithConfig(v3Doc, &DrConfig{BuildGraph: true, Sections: base.SectionComponents})
+	assert.Nil(t, walked.V3Document.Paths)
+	assert.Nil(t, walked.V3Document.Info)
+	assert.Empty(t, walked.V3Document.Tags)
+	assert.Empty(t, walked.Operations())
+	require.NotNil(t, walked.V3Document.Components)
+	assert.NotEmpty(t, walked.Schemas)
+
+	all := NewDrDocumentWithConfig(v3Doc, &DrConfig{BuildGraph: true})
+	assert.NotNil(t, all.V3Document.Paths)
+	assert.Less(t, len(walked.Nodes), len(all.Nodes))
+
+	assert.True(t, base.Sections(0).Has(base.SectionPaths))
+	assert.True(t, base.AllSections.Has(base.SectionPaths|base.SectionTags))
+	assert.False(t, (base.SectionPaths | base.SectionInfo).Has(base.SectionTags))
+}
+
+func TestWalker_WalkV3_GetAllNestedChanges(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: changes
+  version: 1
+paths:
+  /burgers:
+    get:
+      parameters:
+        - name: size
+          in: query
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ok`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	v3Doc, _ := doc.BuildV3Model()
+	drDoc := NewDrDocument(v3Doc)
+
+	ops := drDoc.PathOperations()
+	require.Len(t, ops, 1)
+	op := ops[0].Operation
+	params := op.Parameters
+	require.Len(t, params, 1)
+	response := op.Responses.Codes.GetOrZero("200")
+	require.NotNil(t, response)
+
+	// changes are attached from many goroutines at once.
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			op.AppendChange(&whatChangedModel.Change{Property: "description"})
+		}()
+		go func() {
+			defer wg.Done()
+			params[0].AppendChange(&whatChangedModel.Change{Property: "required"})
+		}()
+		go func() {
+			defer wg.Done()
+			response.AppendChange(&whatChangedModel.Change{Property: "description"})
+			_ = op.GetAllNestedChanges()
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, op.GetChanges(), 50)
+	assert.Len(t, params[0].GetChanges(), 50)
+	nested := op.GetAllNestedChanges()
+	require.Len(t, nested, 150)
+	assert.Equal(t, "description", nested[0].Property)
+	assert.Empty(t, drDoc.V3Document.Info.GetAllNestedChanges())
+	assert.Len(t, drDoc.V3Document.GetAllNestedChanges(), 150)
+}
+
+func TestWalker_WalkV3_Sequential(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	walk := func(sequential bool) *DrDocument {
+		newDoc, _ := libopenapi.NewDocument(bytes)
+		v3Doc, _ := newDoc.BuildV3Model()
+		return NewDrDocumentWithConfig(v3Doc, &DrConfig{BuildGraph: true, UseSchemaCache: true, Sequential: sequential})
+	}
+	concurrent, sequential := walk(false), walk(true)
+	assert.Len(t, sequential.Schemas, len(concurrent.Schemas))
+	assert.Len(t, sequential.Parameters, len(concurrent.Parameters))
+	assert.Len(t, sequential.MediaTypes, len(concurrent.MediaTypes))
+	assert.NotEmpty(t, sequential.Nodes)
+	assert.NotEmpty(t, sequential.Edges)
+
+	// a sequential walk always finds the same objects, in the same order.
+	again := walk(true)
+	assert.Len(t, again.Nodes, len(sequential.Nodes))
+	assert.Len(t, again.Edges, len(sequential.Edges))
+	for i, s := range sequential.Schemas {
+		assert.Equal(t, s.GenerateJSONPath(), again.Schemas[i].GenerateJSONPath())
+	}
+
+	models, err := sequential.LocateModelByLine(442)
+	require.NoError(t, err)
+	assert.Equal(t, "$.components.schemas['Burger']", models[0].GenerateJSONPath())
+}
+
+type countingSizer struct {
+	calls int
+	lock  sync.Mutex
+}
+
+func (c *countingSizer) Size(object base.Foundational, node *base.Node) (height, width int) {
+	c.lock.Lock()
+	c.calls++
+	c.lock.Unlock()
+	return 10, len(node.Label) * 7
+}
+
+func TestWalker_WalkV3_SizeCalculator(t *testing.T) {
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	newDoc, _ := libopenapi.NewDocument(bytes)
+	v3Doc, _ := newDoc.BuildV3Model()
+	sizer := &countingSizer{}
+	drDoc := NewDrDocumentWithConfig(v3Doc, &DrConfig{BuildGraph: true, UseSchemaCache: true, SizeCalculator: sizer})
+	assert.NotZero(t, sizer.calls)
+	for _, s := range drDoc.Schemas {
+		if n := s.GetNode(); n != nil {
+			assert.Equal(t, 10, n.Height)
+			assert.Equal(t, len(n.Label)*7, n.Width)
+		}
+	}
+
+	// the default calculator uses the size of each object.
+	newDoc, _ = libopenapi.NewDocument(bytes)
+	v3Doc, _ = newDoc.BuildV3Model()
+	drDoc = NewDrDocumentAndGraph(v3Doc)
+	burger := drDoc.V3Document.Components.Schemas.GetOrZero("Burger").Schema
+	h, w := burger.GetSize()
+	assert.Equal(t, h, burger.GetNode().Height)
+	assert.Equal(t, w, burger.GetNode().Width)
+
+	// nodes are not sized without a graph.
+	newDoc, _ = libopenapi.NewDocument(bytes)
+	v3Doc, _ = newDoc.BuildV3Model()
+	sizer = &countingSizer{}
+	NewDrDocumentWithConfig(v3Doc, &DrConfig{UseSchemaCache: true, SizeCalculator: sizer})
+	assert.Zero(t, sizer.calls)
+}
+
+func TestWalker_WalkV3_DynamicValues(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: burgers
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Burger:
+      type: object
+      additionalProperties:
+        type: string
+      unevaluatedProperties: false
+      properties:
+        toppings:
+          type: array
+          items:
+            type: string`
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+	drDoc := NewDrDocument(v3Doc)
+	burger := drDoc.V3Document.Components.Schemas.GetOrZero("Burger").Schema
+
+	additional := burger.AdditionalProperties.Resolve()
+	require.NotNil(t, additional)
+	require.NotNil(t, additional.Schema())
+	_, isBool := additional.Bool()
+	assert.False(t, isBool)
+	assert.Equal(t, "$.components.schemas['Burger'].additionalProperties", additional.Schema().GenerateJSONPath())
+
+	unevaluated := burger.UnevaluatedProperties.Resolve()
+	require.NotNil(t, unevaluated)
+	assert.Nil(t, unevaluated.Schema())
+	value, isBool := unevaluated.Bool()
+	assert.True(t, isBool)
+	assert.False(t, value)
+
+	// dynamic values are children of their schema, and hold the schema they resolve to.
+	assert.Contains(t, burger.GetChildren(), base.Foundational(burger.AdditionalProperties))
+	assert.Contains(t, burger.GetChildren(), base.Foundational(burger.UnevaluatedProperties))
+	items := burger.Properties.GetOrZero("toppings").Schema.Items
+	assert.Equal(t, "$.components.schemas['Burger'].properties['toppings'].items",
+		items.Resolve().Schema().Schema.GenerateJSONPath())
+	assert.Contains(t, items.GetChildren(), base.Foundational(items.Resolve().Schema()))
+
+	var nilValue *base.DynamicValue[*highbase.SchemaProxy, bool, *base.SchemaProxy, bool]
+	assert.Nil(t, nilValue.Resolve())
+}
+
+func TestWalker_WalkV3_SerializationPlan(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: burgers
+  version: 1.0.0
+paths:
+  /burgers/{burgerId}:
+    get:
+      parameters:
+        - name: burgerId
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: toppings
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+        - name: sauces
+          in: query
+          explode: false
+          schema:
+            type: array
+            items:
+              type: string
+        - name: filter
+          in: query
+          style: deepObject
+          schema:
+            type: object
+            properties:
+              size:
+                type: integer
+              vegan:
+                type: boolean
+        - name: colour
+          in: path
+          style: matrix
+          explode: true
+          schema:
+            type: object
+            properties:
+              R:
+                type: integer
+              G:
+                type: integer
+        - name: X-Fries
+          in: header
+          schema:
+            type: array
+            items:
+              type: number
+        - name: where
+          in: query
+          content:
+            application/json:
+              schema:
+                type: object
+      responses:
+        "200":
+          description: ok`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(spec))
+	v3Doc, _ := newDoc.BuildV3Model()
+	drDoc := NewDrDocument(v3Doc)
+	params := make(map[string]*drV3.SerializationPlan)
+	for _, p := range drDoc.V3Document.Paths.PathItems.GetOrZero("/burgers/{burgerId}").Get.Parameters {
+		params[p.Value.Name] = p.SerializationPlan()
+	}
+
+	// defaults are resolved per location.
+	assert.Equal(t, drV3.StyleSimple, params["burgerId"].Style)
+	assert.False(t, params["burgerId"].Explode)
+	assert.Equal(t, drV3.StyleForm, params["toppings"].Style)
+	assert.True(t, params["toppings"].Explode)
+	assert.Equal(t, drV3.ShapeArray, params["toppings"].Shape)
+	assert.Equal(t, "string", params["toppings"].Type)
+	assert.Equal(t, "application/json", params["where"].ContentType)
+	assert.Empty(t, params["where"].Style)
+
+	roundTrip := func(name string, value any, encoded string) {
+		plan := params[name]
+		e, err := plan.Encode(value)
+		require.NoError(t, err)
+		assert.Equal(t, encoded, e)
+		d, err := plan.Decode(e)
+		require.NoError(t, err)
+		assert.Equal(t, value, d)
+	}
+	roundTrip("burgerId", int64(42), "42")
+	roundTrip("toppings", []any{"cheese", "pickle relish"}, "toppings=cheese&toppings=pickle%20relish")
+	roundTrip("sauces", []any{"ketchup", "a,b"}, "sauces=ketchup,a%2Cb")
+	roundTrip("filter", map[string]any{"vegan": true, "size": int64(2)}, "filter[size]=2&filter[vegan]=true")
+	roundTrip("colour", map[string]any{"G": int64(200), "R": int64(100)}, ";R=100;G=200")
+	roundTrip("X-Fries", []any{1.5, 2.0}, "1.5,2")
+	roundTrip("where", map[string]any{"open": true}, "where=%7B%22open%22%3Atrue%7D")
+
+	// other parameters in a query string are ignored, missing parameters decode to nil.
+	d, err := params["toppings"].Decode("sauces=ketchup&toppings=cheese")
+	require.NoError(t, err)
+	assert.Equal(t, []any{"cheese"}, d)
+	d, err = params["toppings"].Decode("sauces=ketchup")
+	require.NoError(t, err)
+	assert.Nil(t, d)
+
+	_, err = params["burgerId"].Decode("fries")
+	assert.Error(t, err)
+	params["burgerId"].Style = drV3.StyleDeepObject
+	_, err = params["burgerId"].Encode(1)
+	assert.Error(t, err)
+	assert.Nil(t, (*drV3.Parameter)(nil).SerializationPlan())
+}
+
+func TestWalker_WalkV3_Events(t *testing.T) {
+	yml := `openapi: "3.1"
+components:
+  schemas:
+    Foo:
+      type: object
+      additionalProperties: indeterminate
+    Bar:
+      type: string`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(yml))
+	v3Doc, _ := newDoc.BuildV3Model()
+
+	bus := events.NewBus()
+	sub := bus.Subscribe(0, events.DropNewest)
+	NewDrDocumentWithConfig(v3Doc, &DrConfig{UseSchemaCache: true, Events: bus})
+	bus.Close()
+
+	var progress []string
+	var warnings []*events.WarningEvent
+	for e := range sub.Events() {
+		assert.Equal(t, events.SourceWalker, e.Source)
+		if p, ok := events.Payload(e, events.Progress); ok {
+			progress = append(progress, fmt.Sprintf("%d/%d %s", p.Done, p.Total, p.Message))
+		}
+		if w, ok := events.Payload(e, events.Warning); ok {
+			warnings = append(warnings, w)
+		}
+	}
+	assert.Equal(t, []string{"0/1 walking document", "1/1 walked 1 schemas, 0 parameters and 0 headers"}, progress)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "$.components.schemas['Foo']", warnings[0].Path)
+	assert.ErrorContains(t, warnings[0].Err, "unexpected data type")
+}
+
+func TestWalker_WalkV3_Telemetry(t *testing.T) {
+	yml := `openapi: "3.1"
+info:
+  title: burgers
+  version: 1
+paths:
+  /burgers:
+    get:
+      responses:
+        "200":
+          description: burgers
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Burger'
+components:
+  schemas:
+    Burger:
+      type: object
+      properties:
+        name:
+          type: string`
+
+	newDoc, _ := libopenapi.NewDocument([]byte(yml))
+	v3Doc, _ := newDoc.BuildV3Model()
+
+	recorder := telemetry.NewRecorder()
+	NewDrDocumentWithConfig(v3Doc, &DrConfig{UseSchemaCache: true, BuildGraph: true, BuildLineIndex: true,
+		TracerProvider: recorder})
+
+	walks := recorder.Spans(telemetry.SpanWalk)
+	require.Len(t, walks, 1)
+	assert.False(t, walks[0].Ended.IsZero())
+	assert.Equal(t, 1, walks[0].Attributes["schema_cache.hits"])
+
+	graphs := recorder.Spans(telemetry.SpanBuildGraph)
+	require.Len(t, graphs, 1)
+	assert.Same(t, walks[0], graphs[0].Parent)
+
+	var references []any
+	for _, s := range recorder.Spans(telemetry.SpanResolveSchema) {
+		assert.Same(t, walks[0], s.Parent)
+		if r, ok := s.Attributes["reference"]; ok {
+			references = append(references, r)
+		}
+	}
+	assert.Equal(t, []any{"#/components/schemas/Burger"}, references)
+
+	objects := recorder.Metric(telemetry.MetricObjectsWalked)
+	require.NotNil(t, objects)
+	assert.Equal(t, int64(walks[0].Attributes["objects"].(int)), objects.Counter)
+	assert.Positive(t, objects.Counter)
+	rate := recorder.Metric(telemetry.MetricSchemaCacheHitRate)
+	require.NotNil(t, rate)
+	assert.InDelta(t, float64(recorder.Metric(telemetry.MetricSchemaCacheHits).Counter)/
+		float64(recorder.Metric(telemetry.MetricSchemaCacheHits).Counter+recorder.Metric(telemetry.MetricSchemaCacheMisses).Counter),
+		rate.Values[0], 0.0001)
+}
@@ -0,0 +1,10 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+// https://pb33f.io
+
+//go:build js && wasm
+
+package model
+
+// sequentialWalk walks every document sequentially (see DrConfig.Sequential), js/wasm runs on a single thread.
+const sequentialWalk = true
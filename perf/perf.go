@@ -0,0 +1,179 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package perf measures how long the doctor takes to walk a specification, and how much it allocates while doing it.
+// It is used by the benchmarks in this repository, and can be used by applications that embed the doctor to track
+// performance regressions against their own specifications.
+package perf
+
+import (
+	"errors"
+	"fmt"
+	"github.com/pb33f/doctor/model"
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Spec is a named specification file.
+type Spec struct {
+	Name string
+	File string
+}
+
+// BundledSpecs are the specifications in the repository test_specs directory, smallest first.
+var BundledSpecs = []Spec{
+	{Name: "burgershop", File: "burgershop.openapi.yaml"},
+	{Name: "petstore", File: "petstorev3.json"},
+	{Name: "asana", File: "asana.yaml"},
+	{Name: "square", File: "square.json"},
+	{Name: "stripe", File: "stripe.yaml"},
+}
+
+// Result is the measured cost of walking a single specification.
+type Result struct {
+	Name string
+
+	// ParseDuration is the time taken by libopenapi to parse the specification and build the model,
+	// WalkDuration is the time taken by the doctor to walk it.
+	ParseDuration time.Duration
+	WalkDuration  time.Duration
+
+	// Allocations and AllocatedBytes are measured over the walk only.
+	Allocations    uint64
+	AllocatedBytes uint64
+
+	// PeakGoroutines is the highest number of goroutines seen while walking.
+	PeakGoroutines int
+
+	Schemas int
+	Nodes   int
+}
+
+// Budget is the maximum cost a walk is allowed, zero values are not checked.
+type Budget struct {
+	MaxWalkDuration   time.Duration
+	MaxAllocations    uint64
+	MaxAllocatedBytes uint64
+	MaxGoroutines     int
+}
+
+// Check returns an error describing every part of the budget the result exceeds.
+func (r *Result) Check(budget *Budget) error {
+	if budget == nil {
+		return nil
+	}
+	var errs []error
+	if budget.MaxWalkDuration > 0 && r.WalkDuration > budget.MaxWalkDuration {
+		errs = append(errs, fmt.Errorf("%s: walk took %s, budget is %s", r.Name, r.WalkDuration, budget.MaxWalkDuration))
+	}
+	if budget.MaxAllocations > 0 && r.Allocations > budget.MaxAllocations {
+		errs = append(errs, fmt.Errorf("%s: walk made %d allocations, budget is %d", r.Name, r.Allocations, budget.MaxAllocations))
+	}
+	if budget.MaxAllocatedBytes > 0 && r.AllocatedBytes > budget.MaxAllocatedBytes {
+		errs = append(errs, fmt.Errorf("%s: walk allocated %d bytes, budget is %d", r.Name, r.AllocatedBytes, budget.MaxAllocatedBytes))
+	}
+	if budget.MaxGoroutines > 0 && r.PeakGoroutines > budget.MaxGoroutines {
+		errs = append(errs, fmt.Errorf("%s: walk peaked at %d goroutines, budget is %d", r.Name, r.PeakGoroutines, budget.MaxGoroutines))
+	}
+	return errors.Join(errs...)
+}
+
+// Run parses a specification and measures the walk, using the supplied configuration. A nil configuration uses the
+// same defaults as model.NewDrDocument. The document configuration is optional, and is passed to libopenapi.
+func Run(name string, spec []byte, docConfig *datamodel.DocumentConfiguration, config *model.DrConfig) (*Result, error) {
+	if config == nil {
+		config = &model.DrConfig{UseSchemaCache: true, BuildLineIndex: true}
+	}
+	result := &Result{Name: name}
+
+	start := time.Now()
+	var doc libopenapi.Document
+	var err error
+	if docConfig != nil {
+		doc, err = libopenapi.NewDocumentWithConfiguration(spec, docConfig)
+	} else {
+		doc, err = libopenapi.NewDocument(spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse '%s': %w", name, err)
+	}
+	v3Doc, errs := doc.BuildV3Model()
+	if v3Doc == nil {
+		return nil, fmt.Errorf("unable to build model for '%s': %w", name, errors.Join(errs...))
+	}
+	result.ParseDuration = time.Since(start)
+
+	// sample the goroutine count while the walk runs.
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for {
+			if n := runtime.NumGoroutine(); n > result.PeakGoroutines {
+				result.PeakGoroutines = n
+			}
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start = time.Now()
+	drDoc := model.NewDrDocumentWithConfig(v3Doc, config)
+	result.WalkDuration = time.Since(start)
+	runtime.ReadMemStats(&after)
+	close(done)
+	wg.Wait()
+
+	result.Allocations = after.Mallocs - before.Mallocs
+	result.AllocatedBytes = after.TotalAlloc - before.TotalAlloc
+	result.Schemas = len(drDoc.Schemas)
+	result.Nodes = len(drDoc.Nodes)
+	return result, nil
+}
+
+// RunFile reads a specification from disk and measures the walk, references to other files are resolved
+// relative to the specification.
+func RunFile(path string, config *model.DrConfig) (*Result, error) {
+	spec, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	docConfig := &datamodel.DocumentConfiguration{
+		BasePath:            filepath.Dir(path),
+		AllowFileReferences: true,
+	}
+	return Run(filepath.Base(path), spec, docConfig, config)
+}
+
+// RunBundled measures the walk of every bundled specification found in dir (the test_specs directory),
+// specifications that are missing are skipped.
+func RunBundled(dir string, config *model.DrConfig) ([]*Result, error) {
+	var results []*Result
+	for _, spec := range BundledSpecs {
+		path := filepath.Join(dir, spec.File)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		r, err := RunFile(path, config)
+		if err != nil {
+			return results, err
+		}
+		r.Name = spec.Name
+		results = append(results, r)
+	}
+	return results, nil
+}
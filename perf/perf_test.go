@@ -0,0 +1,70 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package perf
+
+import (
+	"github.com/pb33f/doctor/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRunFile_Budget(t *testing.T) {
+	result, err := RunFile("../test_specs/burgershop.openapi.yaml", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "burgershop.openapi.yaml", result.Name)
+	assert.Greater(t, result.WalkDuration, time.Duration(0))
+	assert.Greater(t, result.Allocations, uint64(0))
+	assert.Greater(t, result.PeakGoroutines, 0)
+	assert.NotZero(t, result.Schemas)
+
+	// generous budgets, these catch order of magnitude regressions, not noise.
+	assert.NoError(t, result.Check(&Budget{MaxWalkDuration: 5 * time.Second, MaxGoroutines: 10000}))
+	assert.Error(t, result.Check(&Budget{MaxAllocations: 1}))
+}
+
+func TestRunBundled(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping bundled specifications in short mode")
+	}
+	results, err := RunBundled("../test_specs", nil)
+	require.NoError(t, err)
+	assert.Len(t, results, len(BundledSpecs))
+	assert.Equal(t, "stripe", results[len(results)-1].Name)
+}
+
+func benchmarkWalk(b *testing.B, file string, config *model.DrConfig) {
+	path := filepath.Join("../test_specs", file)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r, err := RunFile(path, config)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ReportMetric(float64(r.WalkDuration.Milliseconds()), "walk-ms")
+		b.ReportMetric(float64(r.PeakGoroutines), "peak-goroutines")
+	}
+}
+
+func BenchmarkWalk_BurgerShop(b *testing.B) {
+	benchmarkWalk(b, "burgershop.openapi.yaml", nil)
+}
+
+func BenchmarkWalk_Asana(b *testing.B) {
+	benchmarkWalk(b, "asana.yaml", nil)
+}
+
+func BenchmarkWalk_Square(b *testing.B) {
+	benchmarkWalk(b, "square.json", nil)
+}
+
+func BenchmarkWalk_Stripe(b *testing.B) {
+	benchmarkWalk(b, "stripe.yaml", nil)
+}
+
+func BenchmarkWalk_StripeGraph(b *testing.B) {
+	benchmarkWalk(b, "stripe.yaml", &model.DrConfig{BuildGraph: true, UseSchemaCache: true})
+}
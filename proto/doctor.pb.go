@@ -0,0 +1,1091 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+// Messages for the output of the doctor: the graph of a walked document, the changes between two versions of a
+// document, and the statistics of a specification report. The Go types in this package are generated from this
+// file (see proto.go), other languages can generate their types from it too.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: doctor.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ChangeType int32
+
+const (
+	ChangeType_CHANGE_TYPE_UNKNOWN          ChangeType = 0
+	ChangeType_CHANGE_TYPE_MODIFIED         ChangeType = 1
+	ChangeType_CHANGE_TYPE_PROPERTY_ADDED   ChangeType = 2
+	ChangeType_CHANGE_TYPE_OBJECT_ADDED     ChangeType = 3
+	ChangeType_CHANGE_TYPE_OBJECT_REMOVED   ChangeType = 4
+	ChangeType_CHANGE_TYPE_PROPERTY_REMOVED ChangeType = 5
+)
+
+// Enum value maps for ChangeType.
+var (
+	ChangeType_name = map[int32]string{
+		0: "CHANGE_TYPE_UNKNOWN",
+		1: "CHANGE_TYPE_MODIFIED",
+		2: "CHANGE_TYPE_PROPERTY_ADDED",
+		3: "CHANGE_TYPE_OBJECT_ADDED",
+		4: "CHANGE_TYPE_OBJECT_REMOVED",
+		5: "CHANGE_TYPE_PROPERTY_REMOVED",
+	}
+	ChangeType_value = map[string]int32{
+		"CHANGE_TYPE_UNKNOWN":          0,
+		"CHANGE_TYPE_MODIFIED":         1,
+		"CHANGE_TYPE_PROPERTY_ADDED":   2,
+		"CHANGE_TYPE_OBJECT_ADDED":     3,
+		"CHANGE_TYPE_OBJECT_REMOVED":   4,
+		"CHANGE_TYPE_PROPERTY_REMOVED": 5,
+	}
+)
+
+func (x ChangeType) Enum() *ChangeType {
+	p := new(ChangeType)
+	*p = x
+	return p
+}
+
+func (x ChangeType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChangeType) Descriptor() protoreflect.EnumDescriptor {
+	return file_doctor_proto_enumTypes[0].Descriptor()
+}
+
+func (ChangeType) Type() protoreflect.EnumType {
+	return &file_doctor_proto_enumTypes[0]
+}
+
+func (x ChangeType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChangeType.Descriptor instead.
+func (ChangeType) EnumDescriptor() ([]byte, []int) {
+	return file_doctor_proto_rawDescGZIP(), []int{0}
+}
+
+// Node is a node of the graph of a walked document.
+type Node struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	IdHash        string                 `protobuf:"bytes,2,opt,name=id_hash,json=idHash,proto3" json:"id_hash,omitempty"`
+	ParentId      string                 `protobuf:"bytes,3,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	Type          string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	Label         string                 `protobuf:"bytes,5,opt,name=label,proto3" json:"label,omitempty"`
+	Width         int32                  `protobuf:"varint,6,opt,name=width,proto3" json:"width,omitempty"`
+	Height        int32                  `protobuf:"varint,7,opt,name=height,proto3" json:"height,omitempty"`
+	KeyLine       int32                  `protobuf:"varint,8,opt,name=key_line,json=keyLine,proto3" json:"key_line,omitempty"`
+	ValueLine     int32                  `protobuf:"varint,9,opt,name=value_line,json=valueLine,proto3" json:"value_line,omitempty"`
+	IsArray       bool                   `protobuf:"varint,10,opt,name=is_array,json=isArray,proto3" json:"is_array,omitempty"`
+	IsPoly        bool                   `protobuf:"varint,11,opt,name=is_poly,json=isPoly,proto3" json:"is_poly,omitempty"`
+	PolyType      string                 `protobuf:"bytes,12,opt,name=poly_type,json=polyType,proto3" json:"poly_type,omitempty"`
+	PropertyCount int32                  `protobuf:"varint,13,opt,name=property_count,json=propertyCount,proto3" json:"property_count,omitempty"`
+	ArrayIndex    int32                  `protobuf:"varint,14,opt,name=array_index,json=arrayIndex,proto3" json:"array_index,omitempty"`
+	ArrayValues   int32                  `protobuf:"varint,15,opt,name=array_values,json=arrayValues,proto3" json:"array_values,omitempty"`
+	Extensions    int32                  `protobuf:"varint,16,opt,name=extensions,proto3" json:"extensions,omitempty"`
+	Hash          string                 `protobuf:"bytes,17,opt,name=hash,proto3" json:"hash,omitempty"`
+	// origin is the absolute location of the file (or URL) the node was found in.
+	Origin string `protobuf:"bytes,18,opt,name=origin,proto3" json:"origin,omitempty"`
+	// cluster is the region of the document the node is found in, like `paths` or `components.schemas`.
+	Cluster       string `protobuf:"bytes,19,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Node) Reset() {
+	*x = Node{}
+	mi := &file_doctor_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Node) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Node) ProtoMessage() {}
+
+func (x *Node) ProtoReflect() protoreflect.Message {
+	mi := &file_doctor_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Node.ProtoReflect.Descriptor instead.
+func (*Node) Descriptor() ([]byte, []int) {
+	return file_doctor_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Node) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Node) GetIdHash() string {
+	if x != nil {
+		return x.IdHash
+	}
+	return ""
+}
+
+func (x *Node) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Node) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Node) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *Node) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *Node) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Node) GetKeyLine() int32 {
+	if x != nil {
+		return x.KeyLine
+	}
+	return 0
+}
+
+func (x *Node) GetValueLine() int32 {
+	if x != nil {
+		return x.ValueLine
+	}
+	return 0
+}
+
+func (x *Node) GetIsArray() bool {
+	if x != nil {
+		return x.IsArray
+	}
+	return false
+}
+
+func (x *Node) GetIsPoly() bool {
+	if x != nil {
+		return x.IsPoly
+	}
+	return false
+}
+
+func (x *Node) GetPolyType() string {
+	if x != nil {
+		return x.PolyType
+	}
+	return ""
+}
+
+func (x *Node) GetPropertyCount() int32 {
+	if x != nil {
+		return x.PropertyCount
+	}
+	return 0
+}
+
+func (x *Node) GetArrayIndex() int32 {
+	if x != nil {
+		return x.ArrayIndex
+	}
+	return 0
+}
+
+func (x *Node) GetArrayValues() int32 {
+	if x != nil {
+		return x.ArrayValues
+	}
+	return 0
+}
+
+func (x *Node) GetExtensions() int32 {
+	if x != nil {
+		return x.Extensions
+	}
+	return 0
+}
+
+func (x *Node) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *Node) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *Node) GetCluster() string {
+	if x != nil {
+		return x.Cluster
+	}
+	return ""
+}
+
+// Edge connects the nodes of a graph, sources and targets are node ids.
+type Edge struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Sources       []string               `protobuf:"bytes,2,rep,name=sources,proto3" json:"sources,omitempty"`
+	Targets       []string               `protobuf:"bytes,3,rep,name=targets,proto3" json:"targets,omitempty"`
+	Poly          string                 `protobuf:"bytes,4,opt,name=poly,proto3" json:"poly,omitempty"`
+	Ref           string                 `protobuf:"bytes,5,opt,name=ref,proto3" json:"ref,omitempty"`
+	Relationship  string                 `protobuf:"bytes,6,opt,name=relationship,proto3" json:"relationship,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Edge) Reset() {
+	*x = Edge{}
+	mi := &file_doctor_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Edge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Edge) ProtoMessage() {}
+
+func (x *Edge) ProtoReflect() protoreflect.Message {
+	mi := &file_doctor_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Edge.ProtoReflect.Descriptor instead.
+func (*Edge) Descriptor() ([]byte, []int) {
+	return file_doctor_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Edge) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Edge) GetSources() []string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *Edge) GetTargets() []string {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *Edge) GetPoly() string {
+	if x != nil {
+		return x.Poly
+	}
+	return ""
+}
+
+func (x *Edge) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+func (x *Edge) GetRelationship() string {
+	if x != nil {
+		return x.Relationship
+	}
+	return ""
+}
+
+// Graph is every node and edge of a walked document, nodes are linked to their parents by parent_id.
+type Graph struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nodes         []*Node                `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Edges         []*Edge                `protobuf:"bytes,2,rep,name=edges,proto3" json:"edges,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Graph) Reset() {
+	*x = Graph{}
+	mi := &file_doctor_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Graph) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Graph) ProtoMessage() {}
+
+func (x *Graph) ProtoReflect() protoreflect.Message {
+	mi := &file_doctor_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Graph.ProtoReflect.Descriptor instead.
+func (*Graph) Descriptor() ([]byte, []int) {
+	return file_doctor_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Graph) GetNodes() []*Node {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *Graph) GetEdges() []*Edge {
+	if x != nil {
+		return x.Edges
+	}
+	return nil
+}
+
+// Change is a single change between two versions of a document. path is the JSONPath of the property that
+// changed, lines and columns are zero when they are not known.
+type Change struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Path           string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Property       string                 `protobuf:"bytes,2,opt,name=property,proto3" json:"property,omitempty"`
+	Type           ChangeType             `protobuf:"varint,3,opt,name=type,proto3,enum=pb33f.doctor.v1.ChangeType" json:"type,omitempty"`
+	Original       string                 `protobuf:"bytes,4,opt,name=original,proto3" json:"original,omitempty"`
+	New            string                 `protobuf:"bytes,5,opt,name=new,proto3" json:"new,omitempty"`
+	Breaking       bool                   `protobuf:"varint,6,opt,name=breaking,proto3" json:"breaking,omitempty"`
+	OriginalLine   int32                  `protobuf:"varint,7,opt,name=original_line,json=originalLine,proto3" json:"original_line,omitempty"`
+	OriginalColumn int32                  `protobuf:"varint,8,opt,name=original_column,json=originalColumn,proto3" json:"original_column,omitempty"`
+	NewLine        int32                  `protobuf:"varint,9,opt,name=new_line,json=newLine,proto3" json:"new_line,omitempty"`
+	NewColumn      int32                  `protobuf:"varint,10,opt,name=new_column,json=newColumn,proto3" json:"new_column,omitempty"`
+	Anchor         string                 `protobuf:"bytes,11,opt,name=anchor,proto3" json:"anchor,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Change) Reset() {
+	*x = Change{}
+	mi := &file_doctor_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Change) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Change) ProtoMessage() {}
+
+func (x *Change) ProtoReflect() protoreflect.Message {
+	mi := &file_doctor_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Change.ProtoReflect.Descriptor instead.
+func (*Change) Descriptor() ([]byte, []int) {
+	return file_doctor_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Change) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Change) GetProperty() string {
+	if x != nil {
+		return x.Property
+	}
+	return ""
+}
+
+func (x *Change) GetType() ChangeType {
+	if x != nil {
+		return x.Type
+	}
+	return ChangeType_CHANGE_TYPE_UNKNOWN
+}
+
+func (x *Change) GetOriginal() string {
+	if x != nil {
+		return x.Original
+	}
+	return ""
+}
+
+func (x *Change) GetNew() string {
+	if x != nil {
+		return x.New
+	}
+	return ""
+}
+
+func (x *Change) GetBreaking() bool {
+	if x != nil {
+		return x.Breaking
+	}
+	return false
+}
+
+func (x *Change) GetOriginalLine() int32 {
+	if x != nil {
+		return x.OriginalLine
+	}
+	return 0
+}
+
+func (x *Change) GetOriginalColumn() int32 {
+	if x != nil {
+		return x.OriginalColumn
+	}
+	return 0
+}
+
+func (x *Change) GetNewLine() int32 {
+	if x != nil {
+		return x.NewLine
+	}
+	return 0
+}
+
+func (x *Change) GetNewColumn() int32 {
+	if x != nil {
+		return x.NewColumn
+	}
+	return 0
+}
+
+func (x *Change) GetAnchor() string {
+	if x != nil {
+		return x.Anchor
+	}
+	return ""
+}
+
+// ChangeReport is every change between two versions of a document.
+type ChangeReport struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TotalChanges    int32                  `protobuf:"varint,1,opt,name=total_changes,json=totalChanges,proto3" json:"total_changes,omitempty"`
+	BreakingChanges int32                  `protobuf:"varint,2,opt,name=breaking_changes,json=breakingChanges,proto3" json:"breaking_changes,omitempty"`
+	Changes         []*Change              `protobuf:"bytes,3,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChangeReport) Reset() {
+	*x = ChangeReport{}
+	mi := &file_doctor_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeReport) ProtoMessage() {}
+
+func (x *ChangeReport) ProtoReflect() protoreflect.Message {
+	mi := &file_doctor_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeReport.ProtoReflect.Descriptor instead.
+func (*ChangeReport) Descriptor() ([]byte, []int) {
+	return file_doctor_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ChangeReport) GetTotalChanges() int32 {
+	if x != nil {
+		return x.TotalChanges
+	}
+	return 0
+}
+
+func (x *ChangeReport) GetBreakingChanges() int32 {
+	if x != nil {
+		return x.BreakingChanges
+	}
+	return 0
+}
+
+func (x *ChangeReport) GetChanges() []*Change {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+type CategoryStatistic struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CategoryName  string                 `protobuf:"bytes,1,opt,name=category_name,json=categoryName,proto3" json:"category_name,omitempty"`
+	CategoryId    string                 `protobuf:"bytes,2,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	NumIssues     int32                  `protobuf:"varint,3,opt,name=num_issues,json=numIssues,proto3" json:"num_issues,omitempty"`
+	Score         int32                  `protobuf:"varint,4,opt,name=score,proto3" json:"score,omitempty"`
+	Warnings      int32                  `protobuf:"varint,5,opt,name=warnings,proto3" json:"warnings,omitempty"`
+	Errors        int32                  `protobuf:"varint,6,opt,name=errors,proto3" json:"errors,omitempty"`
+	Info          int32                  `protobuf:"varint,7,opt,name=info,proto3" json:"info,omitempty"`
+	Hints         int32                  `protobuf:"varint,8,opt,name=hints,proto3" json:"hints,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryStatistic) Reset() {
+	*x = CategoryStatistic{}
+	mi := &file_doctor_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryStatistic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryStatistic) ProtoMessage() {}
+
+func (x *CategoryStatistic) ProtoReflect() protoreflect.Message {
+	mi := &file_doctor_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryStatistic.ProtoReflect.Descriptor instead.
+func (*CategoryStatistic) Descriptor() ([]byte, []int) {
+	return file_doctor_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CategoryStatistic) GetCategoryName() string {
+	if x != nil {
+		return x.CategoryName
+	}
+	return ""
+}
+
+func (x *CategoryStatistic) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *CategoryStatistic) GetNumIssues() int32 {
+	if x != nil {
+		return x.NumIssues
+	}
+	return 0
+}
+
+func (x *CategoryStatistic) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *CategoryStatistic) GetWarnings() int32 {
+	if x != nil {
+		return x.Warnings
+	}
+	return 0
+}
+
+func (x *CategoryStatistic) GetErrors() int32 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *CategoryStatistic) GetInfo() int32 {
+	if x != nil {
+		return x.Info
+	}
+	return 0
+}
+
+func (x *CategoryStatistic) GetHints() int32 {
+	if x != nil {
+		return x.Hints
+	}
+	return 0
+}
+
+// Statistics are the statistics of a specification report.
+type Statistics struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	FilesizeKb         int32                  `protobuf:"varint,1,opt,name=filesize_kb,json=filesizeKb,proto3" json:"filesize_kb,omitempty"`
+	FilesizeBytes      int32                  `protobuf:"varint,2,opt,name=filesize_bytes,json=filesizeBytes,proto3" json:"filesize_bytes,omitempty"`
+	SpecType           string                 `protobuf:"bytes,3,opt,name=spec_type,json=specType,proto3" json:"spec_type,omitempty"`
+	SpecFormat         string                 `protobuf:"bytes,4,opt,name=spec_format,json=specFormat,proto3" json:"spec_format,omitempty"`
+	Version            string                 `protobuf:"bytes,5,opt,name=version,proto3" json:"version,omitempty"`
+	References         int32                  `protobuf:"varint,6,opt,name=references,proto3" json:"references,omitempty"`
+	ExternalDocs       int32                  `protobuf:"varint,7,opt,name=external_docs,json=externalDocs,proto3" json:"external_docs,omitempty"`
+	Schemas            int32                  `protobuf:"varint,8,opt,name=schemas,proto3" json:"schemas,omitempty"`
+	Parameters         int32                  `protobuf:"varint,9,opt,name=parameters,proto3" json:"parameters,omitempty"`
+	Links              int32                  `protobuf:"varint,10,opt,name=links,proto3" json:"links,omitempty"`
+	Paths              int32                  `protobuf:"varint,11,opt,name=paths,proto3" json:"paths,omitempty"`
+	Operations         int32                  `protobuf:"varint,12,opt,name=operations,proto3" json:"operations,omitempty"`
+	Tags               int32                  `protobuf:"varint,13,opt,name=tags,proto3" json:"tags,omitempty"`
+	Examples           int32                  `protobuf:"varint,14,opt,name=examples,proto3" json:"examples,omitempty"`
+	Enums              int32                  `protobuf:"varint,15,opt,name=enums,proto3" json:"enums,omitempty"`
+	Security           int32                  `protobuf:"varint,16,opt,name=security,proto3" json:"security,omitempty"`
+	OverallScore       int32                  `protobuf:"varint,17,opt,name=overall_score,json=overallScore,proto3" json:"overall_score,omitempty"`
+	TotalErrors        int32                  `protobuf:"varint,18,opt,name=total_errors,json=totalErrors,proto3" json:"total_errors,omitempty"`
+	TotalWarnings      int32                  `protobuf:"varint,19,opt,name=total_warnings,json=totalWarnings,proto3" json:"total_warnings,omitempty"`
+	TotalInfo          int32                  `protobuf:"varint,20,opt,name=total_info,json=totalInfo,proto3" json:"total_info,omitempty"`
+	TotalHints         int32                  `protobuf:"varint,21,opt,name=total_hints,json=totalHints,proto3" json:"total_hints,omitempty"`
+	CategoryStatistics []*CategoryStatistic   `protobuf:"bytes,22,rep,name=category_statistics,json=categoryStatistics,proto3" json:"category_statistics,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Statistics) Reset() {
+	*x = Statistics{}
+	mi := &file_doctor_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Statistics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Statistics) ProtoMessage() {}
+
+func (x *Statistics) ProtoReflect() protoreflect.Message {
+	mi := &file_doctor_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Statistics.ProtoReflect.Descriptor instead.
+func (*Statistics) Descriptor() ([]byte, []int) {
+	return file_doctor_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Statistics) GetFilesizeKb() int32 {
+	if x != nil {
+		return x.FilesizeKb
+	}
+	return 0
+}
+
+func (x *Statistics) GetFilesizeBytes() int32 {
+	if x != nil {
+		return x.FilesizeBytes
+	}
+	return 0
+}
+
+func (x *Statistics) GetSpecType() string {
+	if x != nil {
+		return x.SpecType
+	}
+	return ""
+}
+
+func (x *Statistics) GetSpecFormat() string {
+	if x != nil {
+		return x.SpecFormat
+	}
+	return ""
+}
+
+func (x *Statistics) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Statistics) GetReferences() int32 {
+	if x != nil {
+		return x.References
+	}
+	return 0
+}
+
+func (x *Statistics) GetExternalDocs() int32 {
+	if x != nil {
+		return x.ExternalDocs
+	}
+	return 0
+}
+
+func (x *Statistics) GetSchemas() int32 {
+	if x != nil {
+		return x.Schemas
+	}
+	return 0
+}
+
+func (x *Statistics) GetParameters() int32 {
+	if x != nil {
+		return x.Parameters
+	}
+	return 0
+}
+
+func (x *Statistics) GetLinks() int32 {
+	if x != nil {
+		return x.Links
+	}
+	return 0
+}
+
+func (x *Statistics) GetPaths() int32 {
+	if x != nil {
+		return x.Paths
+	}
+	return 0
+}
+
+func (x *Statistics) GetOperations() int32 {
+	if x != nil {
+		return x.Operations
+	}
+	return 0
+}
+
+func (x *Statistics) GetTags() int32 {
+	if x != nil {
+		return x.Tags
+	}
+	return 0
+}
+
+func (x *Statistics) GetExamples() int32 {
+	if x != nil {
+		return x.Examples
+	}
+	return 0
+}
+
+func (x *Statistics) GetEnums() int32 {
+	if x != nil {
+		return x.Enums
+	}
+	return 0
+}
+
+func (x *Statistics) GetSecurity() int32 {
+	if x != nil {
+		return x.Security
+	}
+	return 0
+}
+
+func (x *Statistics) GetOverallScore() int32 {
+	if x != nil {
+		return x.OverallScore
+	}
+	return 0
+}
+
+func (x *Statistics) GetTotalErrors() int32 {
+	if x != nil {
+		return x.TotalErrors
+	}
+	return 0
+}
+
+func (x *Statistics) GetTotalWarnings() int32 {
+	if x != nil {
+		return x.TotalWarnings
+	}
+	return 0
+}
+
+func (x *Statistics) GetTotalInfo() int32 {
+	if x != nil {
+		return x.TotalInfo
+	}
+	return 0
+}
+
+func (x *Statistics) GetTotalHints() int32 {
+	if x != nil {
+		return x.TotalHints
+	}
+	return 0
+}
+
+func (x *Statistics) GetCategoryStatistics() []*CategoryStatistic {
+	if x != nil {
+		return x.CategoryStatistics
+	}
+	return nil
+}
+
+var File_doctor_proto protoreflect.FileDescriptor
+
+const file_doctor_proto_rawDesc = "" +
+	"\n" +
+	"\fdoctor.proto\x12\x0fpb33f.doctor.v1\"\x80\x04\n" +
+	"\x04Node\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\aid_hash\x18\x02 \x01(\tR\x06idHash\x12\x1b\n" +
+	"\tparent_id\x18\x03 \x01(\tR\bparentId\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\x12\x14\n" +
+	"\x05label\x18\x05 \x01(\tR\x05label\x12\x14\n" +
+	"\x05width\x18\x06 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\a \x01(\x05R\x06height\x12\x19\n" +
+	"\bkey_line\x18\b \x01(\x05R\akeyLine\x12\x1d\n" +
+	"\n" +
+	"value_line\x18\t \x01(\x05R\tvalueLine\x12\x19\n" +
+	"\bis_array\x18\n" +
+	" \x01(\bR\aisArray\x12\x17\n" +
+	"\ais_poly\x18\v \x01(\bR\x06isPoly\x12\x1b\n" +
+	"\tpoly_type\x18\f \x01(\tR\bpolyType\x12%\n" +
+	"\x0eproperty_count\x18\r \x01(\x05R\rpropertyCount\x12\x1f\n" +
+	"\varray_index\x18\x0e \x01(\x05R\n" +
+	"arrayIndex\x12!\n" +
+	"\farray_values\x18\x0f \x01(\x05R\varrayValues\x12\x1e\n" +
+	"\n" +
+	"extensions\x18\x10 \x01(\x05R\n" +
+	"extensions\x12\x12\n" +
+	"\x04hash\x18\x11 \x01(\tR\x04hash\x12\x16\n" +
+	"\x06origin\x18\x12 \x01(\tR\x06origin\x12\x18\n" +
+	"\acluster\x18\x13 \x01(\tR\acluster\"\x94\x01\n" +
+	"\x04Edge\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\asources\x18\x02 \x03(\tR\asources\x12\x18\n" +
+	"\atargets\x18\x03 \x03(\tR\atargets\x12\x12\n" +
+	"\x04poly\x18\x04 \x01(\tR\x04poly\x12\x10\n" +
+	"\x03ref\x18\x05 \x01(\tR\x03ref\x12\"\n" +
+	"\frelationship\x18\x06 \x01(\tR\frelationship\"a\n" +
+	"\x05Graph\x12+\n" +
+	"\x05nodes\x18\x01 \x03(\v2\x15.pb33f.doctor.v1.NodeR\x05nodes\x12+\n" +
+	"\x05edges\x18\x02 \x03(\v2\x15.pb33f.doctor.v1.EdgeR\x05edges\"\xd3\x02\n" +
+	"\x06Change\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1a\n" +
+	"\bproperty\x18\x02 \x01(\tR\bproperty\x12/\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x1b.pb33f.doctor.v1.ChangeTypeR\x04type\x12\x1a\n" +
+	"\boriginal\x18\x04 \x01(\tR\boriginal\x12\x10\n" +
+	"\x03new\x18\x05 \x01(\tR\x03new\x12\x1a\n" +
+	"\bbreaking\x18\x06 \x01(\bR\bbreaking\x12#\n" +
+	"\roriginal_line\x18\a \x01(\x05R\foriginalLine\x12'\n" +
+	"\x0foriginal_column\x18\b \x01(\x05R\x0eoriginalColumn\x12\x19\n" +
+	"\bnew_line\x18\t \x01(\x05R\anewLine\x12\x1d\n" +
+	"\n" +
+	"new_column\x18\n" +
+	" \x01(\x05R\tnewColumn\x12\x16\n" +
+	"\x06anchor\x18\v \x01(\tR\x06anchor\"\x91\x01\n" +
+	"\fChangeReport\x12#\n" +
+	"\rtotal_changes\x18\x01 \x01(\x05R\ftotalChanges\x12)\n" +
+	"\x10breaking_changes\x18\x02 \x01(\x05R\x0fbreakingChanges\x121\n" +
+	"\achanges\x18\x03 \x03(\v2\x17.pb33f.doctor.v1.ChangeR\achanges\"\xec\x01\n" +
+	"\x11CategoryStatistic\x12#\n" +
+	"\rcategory_name\x18\x01 \x01(\tR\fcategoryName\x12\x1f\n" +
+	"\vcategory_id\x18\x02 \x01(\tR\n" +
+	"categoryId\x12\x1d\n" +
+	"\n" +
+	"num_issues\x18\x03 \x01(\x05R\tnumIssues\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x05R\x05score\x12\x1a\n" +
+	"\bwarnings\x18\x05 \x01(\x05R\bwarnings\x12\x16\n" +
+	"\x06errors\x18\x06 \x01(\x05R\x06errors\x12\x12\n" +
+	"\x04info\x18\a \x01(\x05R\x04info\x12\x14\n" +
+	"\x05hints\x18\b \x01(\x05R\x05hints\"\xdd\x05\n" +
+	"\n" +
+	"Statistics\x12\x1f\n" +
+	"\vfilesize_kb\x18\x01 \x01(\x05R\n" +
+	"filesizeKb\x12%\n" +
+	"\x0efilesize_bytes\x18\x02 \x01(\x05R\rfilesizeBytes\x12\x1b\n" +
+	"\tspec_type\x18\x03 \x01(\tR\bspecType\x12\x1f\n" +
+	"\vspec_format\x18\x04 \x01(\tR\n" +
+	"specFormat\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\tR\aversion\x12\x1e\n" +
+	"\n" +
+	"references\x18\x06 \x01(\x05R\n" +
+	"references\x12#\n" +
+	"\rexternal_docs\x18\a \x01(\x05R\fexternalDocs\x12\x18\n" +
+	"\aschemas\x18\b \x01(\x05R\aschemas\x12\x1e\n" +
+	"\n" +
+	"parameters\x18\t \x01(\x05R\n" +
+	"parameters\x12\x14\n" +
+	"\x05links\x18\n" +
+	" \x01(\x05R\x05links\x12\x14\n" +
+	"\x05paths\x18\v \x01(\x05R\x05paths\x12\x1e\n" +
+	"\n" +
+	"operations\x18\f \x01(\x05R\n" +
+	"operations\x12\x12\n" +
+	"\x04tags\x18\r \x01(\x05R\x04tags\x12\x1a\n" +
+	"\bexamples\x18\x0e \x01(\x05R\bexamples\x12\x14\n" +
+	"\x05enums\x18\x0f \x01(\x05R\x05enums\x12\x1a\n" +
+	"\bsecurity\x18\x10 \x01(\x05R\bsecurity\x12#\n" +
+	"\roverall_score\x18\x11 \x01(\x05R\foverallScore\x12!\n" +
+	"\ftotal_errors\x18\x12 \x01(\x05R\vtotalErrors\x12%\n" +
+	"\x0etotal_warnings\x18\x13 \x01(\x05R\rtotalWarnings\x12\x1d\n" +
+	"\n" +
+	"total_info\x18\x14 \x01(\x05R\ttotalInfo\x12\x1f\n" +
+	"\vtotal_hints\x18\x15 \x01(\x05R\n" +
+	"totalHints\x12S\n" +
+	"\x13category_statistics\x18\x16 \x03(\v2\".pb33f.doctor.v1.CategoryStatisticR\x12categoryStatistics*\xbf\x01\n" +
+	"\n" +
+	"ChangeType\x12\x17\n" +
+	"\x13CHANGE_TYPE_UNKNOWN\x10\x00\x12\x18\n" +
+	"\x14CHANGE_TYPE_MODIFIED\x10\x01\x12\x1e\n" +
+	"\x1aCHANGE_TYPE_PROPERTY_ADDED\x10\x02\x12\x1c\n" +
+	"\x18CHANGE_TYPE_OBJECT_ADDED\x10\x03\x12\x1e\n" +
+	"\x1aCHANGE_TYPE_OBJECT_REMOVED\x10\x04\x12 \n" +
+	"\x1cCHANGE_TYPE_PROPERTY_REMOVED\x10\x05B\x1fZ\x1dgithub.com/pb33f/doctor/protob\x06proto3"
+
+var (
+	file_doctor_proto_rawDescOnce sync.Once
+	file_doctor_proto_rawDescData []byte
+)
+
+func file_doctor_proto_rawDescGZIP() []byte {
+	file_doctor_proto_rawDescOnce.Do(func() {
+		file_doctor_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_doctor_proto_rawDesc), len(file_doctor_proto_rawDesc)))
+	})
+	return file_doctor_proto_rawDescData
+}
+
+var file_doctor_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_doctor_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_doctor_proto_goTypes = []any{
+	(ChangeType)(0),           // 0: pb33f.doctor.v1.ChangeType
+	(*Node)(nil),              // 1: pb33f.doctor.v1.Node
+	(*Edge)(nil),              // 2: pb33f.doctor.v1.Edge
+	(*Graph)(nil),             // 3: pb33f.doctor.v1.Graph
+	(*Change)(nil),            // 4: pb33f.doctor.v1.Change
+	(*ChangeReport)(nil),      // 5: pb33f.doctor.v1.ChangeReport
+	(*CategoryStatistic)(nil), // 6: pb33f.doctor.v1.CategoryStatistic
+	(*Statistics)(nil),        // 7: pb33f.doctor.v1.Statistics
+}
+var file_doctor_proto_depIdxs = []int32{
+	1, // 0: pb33f.doctor.v1.Graph.nodes:type_name -> pb33f.doctor.v1.Node
+	2, // 1: pb33f.doctor.v1.Graph.edges:type_name -> pb33f.doctor.v1.Edge
+	0, // 2: pb33f.doctor.v1.Change.type:type_name -> pb33f.doctor.v1.ChangeType
+	4, // 3: pb33f.doctor.v1.ChangeReport.changes:type_name -> pb33f.doctor.v1.Change
+	6, // 4: pb33f.doctor.v1.Statistics.category_statistics:type_name -> pb33f.doctor.v1.CategoryStatistic
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_doctor_proto_init() }
+func file_doctor_proto_init() {
+	if File_doctor_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_doctor_proto_rawDesc), len(file_doctor_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_doctor_proto_goTypes,
+		DependencyIndexes: file_doctor_proto_depIdxs,
+		EnumInfos:         file_doctor_proto_enumTypes,
+		MessageInfos:      file_doctor_proto_msgTypes,
+	}.Build()
+	File_doctor_proto = out.File
+	file_doctor_proto_goTypes = nil
+	file_doctor_proto_depIdxs = nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+// Command protoc generates the Go code of .proto files with protoc-gen-go, without protoc. Files are compiled with
+// protocompile and handed to protoc-gen-go (run with 'go run', at the version in go.mod) as protoc hands them to a
+// plugin, so generating the code only needs Go. Files are generated in the working directory, with the
+// paths=source_relative option.
+//
+//	go run ./internal/protoc doctor.proto
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/bufbuild/protocompile"
+	"github.com/bufbuild/protocompile/linker"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/pluginpb"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: protoc <file.proto>...")
+		os.Exit(2)
+	}
+	if err := generate(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "protoc: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func generate(files []string) error {
+	compiler := protocompile.Compiler{
+		Resolver:       protocompile.WithStandardImports(&protocompile.SourceResolver{}),
+		SourceInfoMode: protocompile.SourceInfoStandard,
+	}
+	compiled, err := compiler.Compile(context.Background(), files...)
+	if err != nil {
+		return err
+	}
+
+	// every file is preceded by its imports, as protoc orders them.
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: files,
+		Parameter:      proto.String("paths=source_relative"),
+	}
+	seen := make(map[string]bool)
+	var add func(f protoreflect.FileDescriptor)
+	add = func(f protoreflect.FileDescriptor) {
+		if seen[f.Path()] {
+			return
+		}
+		seen[f.Path()] = true
+		imports := f.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+		if r, ok := f.(linker.Result); ok {
+			req.ProtoFile = append(req.ProtoFile, r.FileDescriptorProto())
+		} else {
+			req.ProtoFile = append(req.ProtoFile, protodesc.ToFileDescriptorProto(f))
+		}
+	}
+	for _, f := range compiled {
+		add(f)
+	}
+
+	in, err := proto.Marshal(req)
+	if err != nil {
+		return err
+	}
+	var out, stderr bytes.Buffer
+	cmd := exec.Command("go", "run", "google.golang.org/protobuf/cmd/protoc-gen-go")
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = bytes.NewReader(in), &out, &stderr
+	if err = cmd.Run(); err != nil {
+		return fmt.Errorf("protoc-gen-go failed: %w: %s", err, stderr.String())
+	}
+	resp := &pluginpb.CodeGeneratorResponse{}
+	if err = proto.Unmarshal(out.Bytes(), resp); err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("protoc-gen-go failed: %s", resp.GetError())
+	}
+	for _, f := range resp.File {
+		if err = os.WriteFile(filepath.FromSlash(f.GetName()), []byte(f.GetContent()), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
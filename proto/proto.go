@@ -0,0 +1,171 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package proto holds the protobuf messages defined in doctor.proto, and converters from the Go types of the doctor,
+// so the graph of a walked document, change reports and statistics can be consumed by other languages (over gRPC,
+// or as serialized files) without modeling them again. The messages are generated by protoc-gen-go into
+// doctor.pb.go, and encoded with google.golang.org/protobuf/proto.
+//
+// Change the messages in doctor.proto, then run 'go generate' in this directory. protoc is not needed, see
+// internal/protoc.
+package proto
+
+//go:generate go run ./internal/protoc doctor.proto
+
+import (
+	"github.com/pb33f/doctor/changerator"
+	"github.com/pb33f/doctor/gopher"
+	"github.com/pb33f/doctor/model"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	wcModel "github.com/pb33f/libopenapi/what-changed/model"
+)
+
+// NewNode converts a graph node.
+func NewNode(n *drBase.Node) *Node {
+	if n == nil {
+		return nil
+	}
+	node := &Node{
+		Id:            n.Id,
+		IdHash:        n.IdHash,
+		ParentId:      n.ParentId,
+		Type:          n.Type,
+		Label:         n.Label,
+		Width:         int32(n.Width),
+		Height:        int32(n.Height),
+		KeyLine:       int32(n.KeyLine),
+		ValueLine:     int32(n.ValueLine),
+		IsArray:       n.IsArray,
+		IsPoly:        n.IsPoly,
+		PolyType:      n.PolyType,
+		PropertyCount: int32(n.PropertyCount),
+		ArrayIndex:    int32(n.ArrayIndex),
+		ArrayValues:   int32(n.ArrayValues),
+		Extensions:    int32(n.Extensions),
+		Hash:          n.Hash,
+		Cluster:       n.Cluster,
+	}
+	if n.Origin != nil {
+		node.Origin = n.Origin.AbsoluteLocation
+	}
+	return node
+}
+
+// NewEdge converts a graph edge.
+func NewEdge(e *drBase.Edge) *Edge {
+	if e == nil {
+		return nil
+	}
+	return &Edge{
+		Id:           e.Id,
+		Sources:      e.Sources,
+		Targets:      e.Targets,
+		Poly:         e.Poly,
+		Ref:          e.Ref,
+		Relationship: string(e.Relationship),
+	}
+}
+
+// NewGraph converts the graph of a document, which must be walked with BuildGraph enabled.
+func NewGraph(drDoc *model.DrDocument) *Graph {
+	graph := &Graph{}
+	if drDoc == nil {
+		return graph
+	}
+	for _, n := range drDoc.Nodes {
+		graph.Nodes = append(graph.Nodes, NewNode(n))
+	}
+	for _, e := range drDoc.Edges {
+		graph.Edges = append(graph.Edges, NewEdge(e))
+	}
+	return graph
+}
+
+// NewChange converts a located change, the path of the change is the path of the property that changed. The values
+// of ChangeType are the what-changed change types.
+func NewChange(c *changerator.LocatedChange) *Change {
+	if c == nil || c.Change == nil {
+		return nil
+	}
+	change := &Change{
+		Path:     c.PropertyPath(),
+		Property: c.Change.Property,
+		Type:     ChangeType(c.Change.ChangeType),
+		Original: c.Change.Original,
+		New:      c.Change.New,
+		Breaking: c.Change.Breaking,
+		Anchor:   c.Anchor(),
+	}
+	if ctx := c.Change.Context; ctx != nil {
+		value := func(v *int) int32 {
+			if v == nil {
+				return 0
+			}
+			return int32(*v)
+		}
+		change.OriginalLine, change.OriginalColumn = value(ctx.OriginalLine), value(ctx.OriginalColumn)
+		change.NewLine, change.NewColumn = value(ctx.NewLine), value(ctx.NewColumn)
+	}
+	return change
+}
+
+// NewChangeReport converts every change between two versions of a document, in the order they are located (see
+// changerator.LocateChanges).
+func NewChangeReport(docChanges *wcModel.DocumentChanges) *ChangeReport {
+	report := &ChangeReport{}
+	if docChanges == nil {
+		return report
+	}
+	report.TotalChanges = int32(docChanges.TotalChanges())
+	report.BreakingChanges = int32(docChanges.TotalBreakingChanges())
+	for _, c := range changerator.LocateChanges(docChanges) {
+		report.Changes = append(report.Changes, NewChange(c))
+	}
+	return report
+}
+
+// NewStatistics converts the statistics of a specification report.
+func NewStatistics(s *gopher.ReportStatistics) *Statistics {
+	if s == nil {
+		return nil
+	}
+	stats := &Statistics{
+		FilesizeKb:    int32(s.FilesizeKB),
+		FilesizeBytes: int32(s.FilesizeBytes),
+		SpecType:      s.SpecType,
+		SpecFormat:    s.SpecFormat,
+		Version:       s.Version,
+		References:    int32(s.References),
+		ExternalDocs:  int32(s.ExternalDocs),
+		Schemas:       int32(s.Schemas),
+		Parameters:    int32(s.Parameters),
+		Links:         int32(s.Links),
+		Paths:         int32(s.Paths),
+		Operations:    int32(s.Operations),
+		Tags:          int32(s.Tags),
+		Examples:      int32(s.Examples),
+		Enums:         int32(s.Enums),
+		Security:      int32(s.Security),
+		OverallScore:  int32(s.OverallScore),
+		TotalErrors:   int32(s.TotalErrors),
+		TotalWarnings: int32(s.TotalWarnings),
+		TotalInfo:     int32(s.TotalInfo),
+		TotalHints:    int32(s.TotalHints),
+	}
+	for _, c := range s.CategoryStatistics {
+		if c == nil {
+			continue
+		}
+		stats.CategoryStatistics = append(stats.CategoryStatistics, &CategoryStatistic{
+			CategoryName: c.CategoryName,
+			CategoryId:   c.CategoryId,
+			NumIssues:    int32(c.NumIssues),
+			Score:        int32(c.Score),
+			Warnings:     int32(c.Warnings),
+			Errors:       int32(c.Errors),
+			Info:         int32(c.Info),
+			Hints:        int32(c.Hints),
+		})
+	}
+	return stats
+}
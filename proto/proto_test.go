@@ -0,0 +1,110 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package proto
+
+import (
+	"github.com/pb33f/doctor/gopher"
+	"github.com/pb33f/doctor/model"
+	"github.com/pb33f/libopenapi"
+	whatChanged "github.com/pb33f/libopenapi/what-changed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	protobuf "google.golang.org/protobuf/proto"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestEdge_Marshal(t *testing.T) {
+	// field 1 "a", field 2 "b", field 2 "" (repeated values are kept in place).
+	b, err := protobuf.Marshal(&Edge{Id: "a", Sources: []string{"b", ""}})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x0a, 0x01, 'a', 0x12, 0x01, 'b', 0x12, 0x00}, b)
+
+	// negative int32 values are ten bytes.
+	b, _ = protobuf.Marshal(&Node{ArrayIndex: -1})
+	assert.Len(t, b, 11)
+	n := &Node{}
+	require.NoError(t, protobuf.Unmarshal(b, n))
+	assert.Equal(t, int32(-1), n.ArrayIndex)
+
+	assert.Error(t, protobuf.Unmarshal([]byte{0x0a, 0x05, 'a'}, n))
+}
+
+func TestNewGraph(t *testing.T) {
+	bytes, err := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	require.NoError(t, err)
+	doc, err := libopenapi.NewDocument(bytes)
+	require.NoError(t, err)
+	v3Doc, _ := doc.BuildV3Model()
+	drDoc := model.NewDrDocumentAndGraph(v3Doc)
+
+	graph := NewGraph(drDoc)
+	require.Len(t, graph.Nodes, len(drDoc.Nodes))
+	require.Len(t, graph.Edges, len(drDoc.Edges))
+	assert.Equal(t, drDoc.Nodes[1].ParentId, graph.Nodes[1].ParentId)
+
+	b, err := protobuf.Marshal(graph)
+	require.NoError(t, err)
+	decoded := &Graph{}
+	require.NoError(t, protobuf.Unmarshal(b, decoded))
+	assert.True(t, protobuf.Equal(graph, decoded))
+	assert.Empty(t, NewGraph(nil).Nodes)
+}
+
+func TestNewChangeReport(t *testing.T) {
+	left := "openapi: 3.1.0\ninfo:\n  title: burgers\n  version: 1.0.0\npaths: {}"
+	right := strings.Replace(left, "1.0.0", "1.1.0", 1)
+	l, _ := libopenapi.NewDocument([]byte(left))
+	r, _ := libopenapi.NewDocument([]byte(right))
+	lm, _ := l.BuildV3Model()
+	rm, _ := r.BuildV3Model()
+
+	report := NewChangeReport(whatChanged.CompareOpenAPIDocuments(lm.Model.GoLow(), rm.Model.GoLow()))
+	assert.Equal(t, int32(1), report.TotalChanges)
+	require.Len(t, report.Changes, 1)
+	c := report.Changes[0]
+	assert.Equal(t, "$.info.version", c.Path)
+	assert.Equal(t, ChangeType_CHANGE_TYPE_MODIFIED, c.Type)
+	assert.Equal(t, "1.1.0", c.New)
+	assert.Equal(t, int32(4), c.NewLine)
+	assert.NotEmpty(t, c.Anchor)
+
+	b, err := protobuf.Marshal(report)
+	require.NoError(t, err)
+	decoded := &ChangeReport{}
+	require.NoError(t, protobuf.Unmarshal(b, decoded))
+	assert.True(t, protobuf.Equal(report, decoded))
+	assert.Empty(t, NewChangeReport(nil).Changes)
+}
+
+func TestNewStatistics(t *testing.T) {
+	stats := NewStatistics(&gopher.ReportStatistics{
+		SpecType: "openapi", Version: "3.1.0", Schemas: 12, TotalHints: 3,
+		CategoryStatistics: []*gopher.CategoryStatistic{{CategoryId: "schemas", NumIssues: 2, Score: 90}},
+	})
+	b, err := protobuf.Marshal(stats)
+	require.NoError(t, err)
+	decoded := &Statistics{}
+	require.NoError(t, protobuf.Unmarshal(b, decoded))
+	assert.True(t, protobuf.Equal(stats, decoded))
+	assert.Equal(t, int32(12), decoded.Schemas)
+	assert.Equal(t, int32(3), decoded.TotalHints)
+	assert.Equal(t, int32(90), decoded.CategoryStatistics[0].Score)
+	assert.Nil(t, NewStatistics(nil))
+}
+
+func TestMessages(t *testing.T) {
+	for name, m := range map[string]protobuf.Message{
+		"Node":              &Node{},
+		"Edge":              &Edge{},
+		"Graph":             &Graph{},
+		"Change":            &Change{},
+		"ChangeReport":      &ChangeReport{},
+		"CategoryStatistic": &CategoryStatistic{},
+		"Statistics":        &Statistics{},
+	} {
+		assert.Equal(t, "pb33f.doctor.v1."+name, string(m.ProtoReflect().Descriptor().FullName()))
+	}
+}
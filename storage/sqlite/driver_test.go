@@ -0,0 +1,8 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package sqlite
+
+// the tests use the pure Go driver, which registers itself as 'sqlite', so they run without cgo. The package itself
+// does not import a driver.
+import _ "modernc.org/sqlite"
@@ -0,0 +1,495 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package sqlite persists walked documents into a SQLite database, so exploration tools can query the graph of a
+// large specification without holding its model in memory, and walks of different versions of a specification can
+// be compared. Every saved walk is a run, and every other table is keyed by the id of its run (see Schema).
+//
+// The package uses database/sql and does not import a driver, applications register the driver of their choice
+// (for example by importing `modernc.org/sqlite`, which registers itself as `sqlite`), and set DriverName if the
+// driver uses a different name.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pb33f/doctor/model"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/index"
+	"sort"
+	"time"
+)
+
+// DriverName is the name of the database/sql driver used by Open.
+var DriverName = "sqlite"
+
+// Schema creates the tables a run is stored in.
+//
+//   - runs: a saved walk, with the name it was saved under and when.
+//   - nodes: the nodes of the graph, parent_id is the id of the parent node and cluster the region of the
+//     document the node is in.
+//   - edges: the edges of the graph, sources and targets are JSON arrays of node ids.
+//   - paths: the JSONPath and type of every walked object, with its line and column.
+//   - lines: the JSONPaths of the objects found at each line of the document.
+//   - statistics: named counts of what was walked (schemas, parameters, operations and so on).
+const Schema = `
+CREATE TABLE IF NOT EXISTS runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    created TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS nodes (
+    run_id INTEGER NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+    id TEXT NOT NULL,
+    id_hash TEXT,
+    parent_id TEXT,
+    type TEXT,
+    label TEXT,
+    width INTEGER,
+    height INTEGER,
+    key_line INTEGER,
+    value_line INTEGER,
+    is_array INTEGER,
+    is_poly INTEGER,
+    poly_type TEXT,
+    property_count INTEGER,
+    array_index INTEGER,
+    array_values INTEGER,
+    hash TEXT,
+    origin TEXT,
+    cluster TEXT,
+    PRIMARY KEY (run_id, id)
+);
+CREATE TABLE IF NOT EXISTS edges (
+    run_id INTEGER NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+    id TEXT NOT NULL,
+    sources TEXT NOT NULL,
+    targets TEXT NOT NULL,
+    ref TEXT,
+    poly TEXT,
+    relationship TEXT
+);
+CREATE TABLE IF NOT EXISTS paths (
+    run_id INTEGER NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+    path TEXT NOT NULL,
+    type TEXT,
+    line INTEGER,
+    col INTEGER
+);
+CREATE TABLE IF NOT EXISTS lines (
+    run_id INTEGER NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+    line INTEGER NOT NULL,
+    path TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS statistics (
+    run_id INTEGER NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    value INTEGER NOT NULL,
+    PRIMARY KEY (run_id, name)
+);
+CREATE INDEX IF NOT EXISTS nodes_parent ON nodes (run_id, parent_id);
+CREATE INDEX IF NOT EXISTS paths_path ON paths (run_id, path);
+CREATE INDEX IF NOT EXISTS lines_line ON lines (run_id, line);
+`
+
+// Run is a saved walk of a document.
+type Run struct {
+	Id      int64
+	Name    string
+	Created time.Time
+}
+
+// Path is the JSONPath of a walked object, its type, and where it is in the document.
+type Path struct {
+	Path   string
+	Type   string
+	Line   int
+	Column int
+}
+
+// Snapshot is everything stored for a single walk. Nodes that are loaded from the database only carry the
+// fields in the nodes table, they have no model instances.
+type Snapshot struct {
+	Run   *Run
+	Nodes []*drBase.Node
+	Edges []*drBase.Edge
+	Paths []*Path
+
+	// Lines maps each line of the document to the JSONPaths of the objects found at that line.
+	Lines      map[int][]string
+	Statistics map[string]int
+}
+
+// Open opens (or creates) a SQLite database file using DriverName, and creates the schema.
+func Open(ctx context.Context, file string) (*sql.DB, error) {
+	db, err := sql.Open(DriverName, file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open '%s': %w", file, err)
+	}
+	if err = createSchema(ctx, db); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("unable to create schema in '%s': %w", file, err)
+	}
+	return db, nil
+}
+
+// createSchema creates the tables of the schema, and adds the columns that were added to the schema since a
+// database was created.
+func createSchema(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, Schema); err != nil {
+		return err
+	}
+	rows, err := db.QueryContext(ctx, "SELECT name FROM pragma_table_info('nodes')")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return err
+		}
+		columns[name] = true
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	if !columns["cluster"] {
+		_, err = db.ExecContext(ctx, "ALTER TABLE nodes ADD COLUMN cluster TEXT")
+	}
+	return err
+}
+
+// NewSnapshot collects everything that is stored for a walked document. Nodes and edges are only present when the
+// document was walked with BuildGraph enabled. Paths are in the order of their first line.
+func NewSnapshot(drDoc *model.DrDocument, name string) *Snapshot {
+	snapshot := &Snapshot{
+		Run:        &Run{Name: name, Created: time.Now().UTC()},
+		Lines:      make(map[int][]string),
+		Statistics: make(map[string]int),
+	}
+	if drDoc == nil {
+		return snapshot
+	}
+	snapshot.Nodes, snapshot.Edges = drDoc.Nodes, drDoc.Edges
+
+	// objects are found at every line they span, each is given the path of the first.
+	locations := drDoc.BuildObjectLocationMap()
+	lines := make([]int, 0, len(locations))
+	for line := range locations {
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+	seen := make(map[drBase.Foundational]bool)
+	for _, line := range lines {
+		models, _ := locations[line].([]any)
+		for _, o := range models {
+			f, ok := o.(drBase.Foundational)
+			if !ok {
+				continue
+			}
+			path := f.GenerateJSONPath()
+			snapshot.Lines[line] = append(snapshot.Lines[line], path)
+			if seen[f] {
+				continue
+			}
+			seen[f] = true
+			p := &Path{Path: path, Type: f.GetInstanceType(), Line: line}
+			if n := f.GetKeyNode(); n != nil && n.Line == line {
+				p.Column = n.Column
+			} else if n = f.GetValueNode(); n != nil && n.Line == line {
+				p.Column = n.Column
+			}
+			snapshot.Paths = append(snapshot.Paths, p)
+		}
+	}
+
+	stats := snapshot.Statistics
+	stats["schemas"] = len(drDoc.Schemas)
+	stats["skippedSchemas"] = len(drDoc.SkippedSchemas)
+	stats["parameters"] = len(drDoc.Parameters)
+	stats["headers"] = len(drDoc.Headers)
+	stats["mediaTypes"] = len(drDoc.MediaTypes)
+	stats["operations"] = len(drDoc.Operations())
+	stats["buildErrors"] = len(drDoc.BuildErrors)
+	stats["nodes"] = len(snapshot.Nodes)
+	stats["edges"] = len(snapshot.Edges)
+	stats["paths"] = len(snapshot.Paths)
+	stats["lines"] = len(snapshot.Lines)
+	return snapshot
+}
+
+// Save stores a walked document as a new run (see NewSnapshot), and returns the id of the run.
+func Save(ctx context.Context, db *sql.DB, drDoc *model.DrDocument, name string) (int64, error) {
+	return SaveSnapshot(ctx, db, NewSnapshot(drDoc, name))
+}
+
+// SaveSnapshot stores a snapshot as a new run in a single transaction, and returns the id of the run. The schema is
+// created if it does not exist.
+func SaveSnapshot(ctx context.Context, db *sql.DB, snapshot *Snapshot) (int64, error) {
+	if db == nil || snapshot == nil {
+		return 0, fmt.Errorf("a database and a snapshot are required")
+	}
+	if err := createSchema(ctx, db); err != nil {
+		return 0, fmt.Errorf("unable to create schema: %w", err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	id, err := saveSnapshot(ctx, tx, snapshot)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func saveSnapshot(ctx context.Context, tx *sql.Tx, snapshot *Snapshot) (int64, error) {
+	run := snapshot.Run
+	if run == nil {
+		run = &Run{Created: time.Now().UTC()}
+	}
+	res, err := tx.ExecContext(ctx, "INSERT INTO runs (name, created) VALUES (?, ?)", run.Name,
+		run.Created.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return 0, fmt.Errorf("unable to save run: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	insert := func(table, query string, rows int, args func(i int) []any) error {
+		if rows == 0 {
+			return nil
+		}
+		stmt, e := tx.PrepareContext(ctx, query)
+		if e != nil {
+			return fmt.Errorf("unable to save %s: %w", table, e)
+		}
+		defer stmt.Close()
+		for i := 0; i < rows; i++ {
+			if _, e = stmt.ExecContext(ctx, append([]any{id}, args(i)...)...); e != nil {
+				return fmt.Errorf("unable to save %s: %w", table, e)
+			}
+		}
+		return nil
+	}
+
+	nodes := snapshot.Nodes
+	if err = insert("nodes", "INSERT OR REPLACE INTO nodes (run_id, id, id_hash, parent_id, type, label, width, height, "+
+		"key_line, value_line, is_array, is_poly, poly_type, property_count, array_index, array_values, hash, origin, "+
+		"cluster) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", len(nodes), func(i int) []any {
+		n := nodes[i]
+		origin := ""
+		if n.Origin != nil {
+			origin = n.Origin.AbsoluteLocation
+		}
+		return []any{n.Id, n.IdHash, n.ParentId, n.Type, n.Label, n.Width, n.Height, n.KeyLine, n.ValueLine,
+			n.IsArray, n.IsPoly, n.PolyType, n.PropertyCount, n.ArrayIndex, n.ArrayValues, n.Hash, origin, n.Cluster}
+	}); err != nil {
+		return 0, err
+	}
+
+	edges := snapshot.Edges
+	if err = insert("edges", "INSERT INTO edges (run_id, id, sources, targets, ref, poly, relationship) "+
+		"VALUES (?, ?, ?, ?, ?, ?, ?)", len(edges), func(i int) []any {
+		e := edges[i]
+		sources, _ := json.Marshal(e.Sources)
+		targets, _ := json.Marshal(e.Targets)
+		return []any{e.Id, string(sources), string(targets), e.Ref, e.Poly, string(e.Relationship)}
+	}); err != nil {
+		return 0, err
+	}
+
+	paths := snapshot.Paths
+	if err = insert("paths", "INSERT INTO paths (run_id, path, type, line, col) VALUES (?, ?, ?, ?, ?)", len(paths),
+		func(i int) []any {
+			return []any{paths[i].Path, paths[i].Type, paths[i].Line, paths[i].Column}
+		}); err != nil {
+		return 0, err
+	}
+
+	type linePath struct {
+		line int
+		path string
+	}
+	var lines []linePath
+	for line, ps := range snapshot.Lines {
+		for _, p := range ps {
+			lines = append(lines, linePath{line, p})
+		}
+	}
+	if err = insert("lines", "INSERT INTO lines (run_id, line, path) VALUES (?, ?, ?)", len(lines), func(i int) []any {
+		return []any{lines[i].line, lines[i].path}
+	}); err != nil {
+		return 0, err
+	}
+
+	var names []string
+	for name := range snapshot.Statistics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if err = insert("statistics", "INSERT INTO statistics (run_id, name, value) VALUES (?, ?, ?)", len(names),
+		func(i int) []any {
+			return []any{names[i], snapshot.Statistics[names[i]]}
+		}); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// Runs returns every saved run, oldest first.
+func Runs(ctx context.Context, db *sql.DB) ([]*Run, error) {
+	rows, err := db.QueryContext(ctx, "SELECT id, name, created FROM runs ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("unable to read runs: %w", err)
+	}
+	defer rows.Close()
+	var runs []*Run
+	for rows.Next() {
+		run, e := scanRun(rows)
+		if e != nil {
+			return nil, e
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// LoadFromSQLite reads a saved run back into a snapshot. Nodes are returned in the order they were saved, and are
+// linked to their children.
+func LoadFromSQLite(ctx context.Context, db *sql.DB, runId int64) (*Snapshot, error) {
+	row := db.QueryRowContext(ctx, "SELECT id, name, created FROM runs WHERE id = ?", runId)
+	run, err := scanRun(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("run %d does not exist", runId)
+		}
+		return nil, err
+	}
+	snapshot := &Snapshot{Run: run, Lines: make(map[int][]string), Statistics: make(map[string]int)}
+
+	query := func(table, q string, scan func(rows *sql.Rows) error) error {
+		rows, e := db.QueryContext(ctx, q, runId)
+		if e != nil {
+			return fmt.Errorf("unable to read %s: %w", table, e)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			if e = scan(rows); e != nil {
+				return fmt.Errorf("unable to read %s: %w", table, e)
+			}
+		}
+		return rows.Err()
+	}
+
+	byId := make(map[string]*drBase.Node)
+	if err = query("nodes", "SELECT id, id_hash, parent_id, type, label, width, height, key_line, value_line, "+
+		"is_array, is_poly, poly_type, property_count, array_index, array_values, hash, origin, "+
+		"COALESCE(cluster, '') FROM nodes WHERE run_id = ? ORDER BY rowid", func(rows *sql.Rows) error {
+		n := &drBase.Node{}
+		var origin string
+		if e := rows.Scan(&n.Id, &n.IdHash, &n.ParentId, &n.Type, &n.Label, &n.Width, &n.Height, &n.KeyLine,
+			&n.ValueLine, &n.IsArray, &n.IsPoly, &n.PolyType, &n.PropertyCount, &n.ArrayIndex, &n.ArrayValues,
+			&n.Hash, &origin, &n.Cluster); e != nil {
+			return e
+		}
+		if origin != "" {
+			n.Origin = &index.NodeOrigin{AbsoluteLocation: origin}
+		}
+		snapshot.Nodes = append(snapshot.Nodes, n)
+		byId[n.Id] = n
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	for _, n := range snapshot.Nodes {
+		if p := byId[n.ParentId]; p != nil && p != n {
+			p.Children = append(p.Children, n)
+		}
+	}
+
+	if err = query("edges", "SELECT id, sources, targets, ref, poly, relationship FROM edges WHERE run_id = ? "+
+		"ORDER BY rowid", func(rows *sql.Rows) error {
+		e := &drBase.Edge{}
+		var sources, targets, relationship string
+		if err := rows.Scan(&e.Id, &sources, &targets, &e.Ref, &e.Poly, &relationship); err != nil {
+			return err
+		}
+		if err := json.Unmarshal([]byte(sources), &e.Sources); err != nil {
+			return err
+		}
+		if err := json.Unmarshal([]byte(targets), &e.Targets); err != nil {
+			return err
+		}
+		e.Relationship = drBase.Relationship(relationship)
+		snapshot.Edges = append(snapshot.Edges, e)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if err = query("paths", "SELECT path, type, line, col FROM paths WHERE run_id = ? ORDER BY rowid",
+		func(rows *sql.Rows) error {
+			p := &Path{}
+			if e := rows.Scan(&p.Path, &p.Type, &p.Line, &p.Column); e != nil {
+				return e
+			}
+			snapshot.Paths = append(snapshot.Paths, p)
+			return nil
+		}); err != nil {
+		return nil, err
+	}
+
+	if err = query("lines", "SELECT line, path FROM lines WHERE run_id = ? ORDER BY rowid",
+		func(rows *sql.Rows) error {
+			var line int
+			var path string
+			if e := rows.Scan(&line, &path); e != nil {
+				return e
+			}
+			snapshot.Lines[line] = append(snapshot.Lines[line], path)
+			return nil
+		}); err != nil {
+		return nil, err
+	}
+
+	if err = query("statistics", "SELECT name, value FROM statistics WHERE run_id = ?",
+		func(rows *sql.Rows) error {
+			var name string
+			var value int
+			if e := rows.Scan(&name, &value); e != nil {
+				return e
+			}
+			snapshot.Statistics[name] = value
+			return nil
+		}); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// scanner is a *sql.Row or *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRun(s scanner) (*Run, error) {
+	run := &Run{}
+	var created string
+	if err := s.Scan(&run.Id, &run.Name, &created); err != nil {
+		return nil, err
+	}
+	run.Created, _ = time.Parse(time.RFC3339Nano, created)
+	return run, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"github.com/pb33f/doctor/model"
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func burgerShop(t *testing.T) *model.DrDocument {
+	bytes, err := os.ReadFile("../../test_specs/burgershop.openapi.yaml")
+	require.NoError(t, err)
+	doc, err := libopenapi.NewDocument(bytes)
+	require.NoError(t, err)
+	v3Doc, _ := doc.BuildV3Model()
+	return model.NewDrDocumentAndGraph(v3Doc)
+}
+
+func TestNewSnapshot(t *testing.T) {
+	drDoc := burgerShop(t)
+	snapshot := NewSnapshot(drDoc, "burgershop")
+	assert.Equal(t, "burgershop", snapshot.Run.Name)
+	assert.Len(t, snapshot.Nodes, len(drDoc.Nodes))
+	assert.Len(t, snapshot.Edges, len(drDoc.Edges))
+	assert.Equal(t, len(drDoc.Schemas), snapshot.Statistics["schemas"])
+	assert.Equal(t, len(drDoc.Operations()), snapshot.Statistics["operations"])
+	require.NotEmpty(t, snapshot.Paths)
+	assert.Equal(t, "$", snapshot.Paths[0].Path)
+	assert.Equal(t, "document", snapshot.Paths[0].Type)
+	assert.True(t, slices.IsSortedFunc(snapshot.Paths, func(a, b *Path) int { return a.Line - b.Line }))
+	assert.Contains(t, snapshot.Lines[snapshot.Paths[0].Line], "$")
+
+	empty := NewSnapshot(nil, "empty")
+	assert.Empty(t, empty.Nodes)
+	assert.Empty(t, empty.Statistics)
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	ctx := context.Background()
+	db, err := Open(ctx, filepath.Join(t.TempDir(), "doctor.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	drDoc := burgerShop(t)
+	first, err := Save(ctx, db, drDoc, "first")
+	require.NoError(t, err)
+	second, err := Save(ctx, db, drDoc, "second")
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+
+	runs, err := Runs(ctx, db)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, "first", runs[0].Name)
+
+	saved := NewSnapshot(drDoc, "first")
+	loaded, err := LoadFromSQLite(ctx, db, first)
+	require.NoError(t, err)
+	assert.Equal(t, saved.Statistics, loaded.Statistics)
+	assert.Equal(t, saved.Paths, loaded.Paths)
+	assert.Equal(t, saved.Lines, loaded.Lines)
+	require.Len(t, loaded.Edges, len(saved.Edges))
+	assert.Equal(t, saved.Edges[0].Sources, loaded.Edges[0].Sources)
+	require.Len(t, loaded.Nodes, len(saved.Nodes))
+	assert.Equal(t, saved.Nodes[0].Id, loaded.Nodes[0].Id)
+	for i, n := range saved.Nodes {
+		assert.Equal(t, n.Cluster, loaded.Nodes[i].Cluster, n.Id)
+	}
+	assert.NotEmpty(t, loaded.Nodes[0].Cluster)
+
+	_, err = LoadFromSQLite(ctx, db, 100)
+	assert.EqualError(t, err, "run 100 does not exist")
+}
+
+func TestOpen_AddsCluster(t *testing.T) {
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "doctor.db")
+
+	// a database created before nodes had a cluster.
+	db, err := sql.Open(DriverName, file)
+	require.NoError(t, err)
+	schema := strings.Replace(Schema, "    cluster TEXT,\n", "", 1)
+	require.NotEqual(t, Schema, schema)
+	_, err = db.ExecContext(ctx, schema)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	db, err = Open(ctx, file)
+	require.NoError(t, err)
+	defer db.Close()
+	id, err := Save(ctx, db, burgerShop(t), "burgershop")
+	require.NoError(t, err)
+	loaded, err := LoadFromSQLite(ctx, db, id)
+	require.NoError(t, err)
+	require.NotEmpty(t, loaded.Nodes)
+	assert.NotEmpty(t, loaded.Nodes[0].Cluster)
+}
@@ -0,0 +1,147 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package otel adapts OpenTelemetry tracer and meter providers to the telemetry interfaces of the doctor, so the
+// walks and reports of the doctor are traced and measured by the OpenTelemetry SDK a service already runs. It is
+// a package of its own so the doctor only depends on OpenTelemetry when it is used.
+//
+//	provider := otel.NewProvider(global.GetTracerProvider(), global.GetMeterProvider())
+//	drDoc := model.NewDrDocumentWithConfig(v3Doc, &model.DrConfig{TracerProvider: provider})
+package otel
+
+import (
+	"context"
+	"fmt"
+	"github.com/pb33f/doctor/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"sync"
+)
+
+// Provider is a telemetry.TracerProvider and telemetry.MeterProvider that starts spans with an OpenTelemetry
+// tracer provider, and records metrics with an OpenTelemetry meter provider.
+type Provider struct {
+	tracers trace.TracerProvider
+	meters  metric.MeterProvider
+}
+
+// NewProvider creates a provider that traces with tracers, and measures with meters. Metrics are not recorded when
+// meters is nil.
+func NewProvider(tracers trace.TracerProvider, meters metric.MeterProvider) *Provider {
+	return &Provider{tracers: tracers, meters: meters}
+}
+
+// Tracer returns the tracer of an instrumentation scope.
+func (p *Provider) Tracer(scope string) telemetry.Tracer {
+	return &tracer{tracer: p.tracers.Tracer(scope)}
+}
+
+// Meter returns the meter of an instrumentation scope, or nil when the provider has no meter provider.
+func (p *Provider) Meter(scope string) telemetry.Meter {
+	if p.meters == nil {
+		return nil
+	}
+	return &meter{
+		meter:      p.meters.Meter(scope),
+		counters:   make(map[string]metric.Int64Counter),
+		histograms: make(map[string]metric.Float64Histogram),
+	}
+}
+
+type tracer struct {
+	tracer trace.Tracer
+}
+
+func (t *tracer) Start(ctx context.Context, name string, attributes ...telemetry.Attribute) (context.Context,
+	telemetry.Span) {
+	ctx, s := t.tracer.Start(ctx, name, trace.WithAttributes(convert(attributes)...))
+	return ctx, &span{span: s}
+}
+
+type span struct {
+	span trace.Span
+}
+
+func (s *span) SetAttributes(attributes ...telemetry.Attribute) {
+	s.span.SetAttributes(convert(attributes)...)
+}
+
+// RecordError records the error as an event of the span, and marks the span as failed.
+func (s *span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+func (s *span) End() {
+	s.span.End()
+}
+
+// meter creates an instrument for every metric the first time it is recorded, counters for Add and histograms
+// for Record.
+type meter struct {
+	meter      metric.Meter
+	lock       sync.Mutex
+	counters   map[string]metric.Int64Counter
+	histograms map[string]metric.Float64Histogram
+}
+
+func (m *meter) Add(ctx context.Context, name string, value int64, attributes ...telemetry.Attribute) {
+	m.lock.Lock()
+	counter, ok := m.counters[name]
+	if !ok {
+		var err error
+		if counter, err = m.meter.Int64Counter(name); err != nil {
+			m.lock.Unlock()
+			return
+		}
+		m.counters[name] = counter
+	}
+	m.lock.Unlock()
+	counter.Add(ctx, value, metric.WithAttributes(convert(attributes)...))
+}
+
+func (m *meter) Record(ctx context.Context, name string, value float64, attributes ...telemetry.Attribute) {
+	m.lock.Lock()
+	histogram, ok := m.histograms[name]
+	if !ok {
+		var err error
+		if histogram, err = m.meter.Float64Histogram(name); err != nil {
+			m.lock.Unlock()
+			return
+		}
+		m.histograms[name] = histogram
+	}
+	m.lock.Unlock()
+	histogram.Record(ctx, value, metric.WithAttributes(convert(attributes)...))
+}
+
+// convert converts attributes to OpenTelemetry attributes, values of other types than strings, numbers and
+// booleans are recorded as strings.
+func convert(attributes []telemetry.Attribute) []attribute.KeyValue {
+	if len(attributes) == 0 {
+		return nil
+	}
+	kvs := make([]attribute.KeyValue, 0, len(attributes))
+	for _, a := range attributes {
+		switch v := a.Value.(type) {
+		case string:
+			kvs = append(kvs, attribute.String(a.Key, v))
+		case int:
+			kvs = append(kvs, attribute.Int(a.Key, v))
+		case int64:
+			kvs = append(kvs, attribute.Int64(a.Key, v))
+		case float64:
+			kvs = append(kvs, attribute.Float64(a.Key, v))
+		case bool:
+			kvs = append(kvs, attribute.Bool(a.Key, v))
+		default:
+			kvs = append(kvs, attribute.String(a.Key, fmt.Sprint(v)))
+		}
+	}
+	return kvs
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package otel
+
+import (
+	"context"
+	"errors"
+	"github.com/pb33f/doctor/model"
+	"github.com/pb33f/doctor/telemetry"
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"os"
+	"testing"
+)
+
+func TestProvider(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	provider := NewProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)),
+		sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	instruments := telemetry.Instrument(provider, "burgers")
+	ctx, walk := instruments.Start(context.Background(), telemetry.SpanWalk, telemetry.Int("objects", 12))
+	_, resolve := instruments.Start(ctx, telemetry.SpanResolveSchema,
+		telemetry.String("reference", "#/components/schemas/Burger"))
+	resolve.RecordError(errors.New("no pickles"))
+	resolve.End()
+	walk.End()
+
+	instruments.Add(ctx, telemetry.MetricSchemaCacheHits, 2)
+	instruments.Add(ctx, telemetry.MetricSchemaCacheHits, 3)
+	instruments.Record(ctx, telemetry.MetricSchemaCacheHitRate, 0.5)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 2)
+	assert.Equal(t, telemetry.SpanResolveSchema, ended[0].Name())
+	assert.Equal(t, ended[1].SpanContext().SpanID(), ended[0].Parent().SpanID())
+	assert.Contains(t, ended[0].Attributes(), attribute.String("reference", "#/components/schemas/Burger"))
+	assert.Equal(t, codes.Error, ended[0].Status().Code)
+	assert.Equal(t, "no pickles", ended[0].Status().Description)
+	assert.Contains(t, ended[1].Attributes(), attribute.Int("objects", 12))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	assert.Equal(t, "burgers", rm.ScopeMetrics[0].Scope.Name)
+	metrics := make(map[string]metricdata.Metrics)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+	hits, ok := metrics[telemetry.MetricSchemaCacheHits].Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	assert.Equal(t, int64(5), hits.DataPoints[0].Value)
+	rate, ok := metrics[telemetry.MetricSchemaCacheHitRate].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	assert.Equal(t, uint64(1), rate.DataPoints[0].Count)
+}
+
+func TestProvider_NoMeters(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	provider := NewProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)), nil)
+
+	instruments := telemetry.Instrument(provider, "burgers")
+	_, span := instruments.Start(context.Background(), telemetry.SpanRender)
+	instruments.Add(context.Background(), telemetry.MetricObjectsWalked, 1)
+	span.End()
+	assert.Len(t, spans.Ended(), 1)
+}
+
+func TestProvider_Walk(t *testing.T) {
+	bytes, err := os.ReadFile("../../test_specs/burgershop.openapi.yaml")
+	require.NoError(t, err)
+	doc, err := libopenapi.NewDocument(bytes)
+	require.NoError(t, err)
+	v3Doc, _ := doc.BuildV3Model()
+
+	spans := tracetest.NewSpanRecorder()
+	provider := NewProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)), nil)
+	model.NewDrDocumentWithConfig(v3Doc, &model.DrConfig{UseSchemaCache: true, TracerProvider: provider})
+
+	var names []string
+	for _, s := range spans.Ended() {
+		names = append(names, s.Name())
+	}
+	assert.Contains(t, names, telemetry.SpanWalk)
+	assert.Contains(t, names, telemetry.SpanResolveSchema)
+}
@@ -0,0 +1,136 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package telemetry
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RecordedSpan is a span recorded by a Recorder.
+type RecordedSpan struct {
+	Name       string
+	Parent     *RecordedSpan
+	Attributes map[string]any
+	Errors     []error
+	Started    time.Time
+	Ended      time.Time
+
+	recorder *Recorder
+}
+
+// RecordedMetric is a metric recorded by a Recorder, counters are the sum of what was added.
+type RecordedMetric struct {
+	Name    string
+	Counter int64
+	Values  []float64
+}
+
+// Recorder is a TracerProvider and MeterProvider that keeps spans and metrics in memory, for tests and for
+// debugging the performance of a walk without a collector. It is safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	spans   []*RecordedSpan
+	metrics map[string]*RecordedMetric
+}
+
+type spanKey struct{}
+
+// NewRecorder creates an empty recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{metrics: make(map[string]*RecordedMetric)}
+}
+
+// Tracer returns the recorder, for every scope.
+func (r *Recorder) Tracer(string) Tracer {
+	return r
+}
+
+// Meter returns the recorder, for every scope.
+func (r *Recorder) Meter(string) Meter {
+	return r
+}
+
+// Start starts a span, the child of the span in the context if there is one.
+func (r *Recorder) Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span) {
+	span := &RecordedSpan{Name: name, Attributes: make(map[string]any), Started: time.Now(), recorder: r}
+	span.Parent, _ = ctx.Value(spanKey{}).(*RecordedSpan)
+	span.SetAttributes(attributes...)
+	r.mu.Lock()
+	r.spans = append(r.spans, span)
+	r.mu.Unlock()
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// SetAttributes sets attributes of the span.
+func (s *RecordedSpan) SetAttributes(attributes ...Attribute) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	for _, a := range attributes {
+		s.Attributes[a.Key] = a.Value
+	}
+}
+
+// RecordError records an error of the span.
+func (s *RecordedSpan) RecordError(err error) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.Errors = append(s.Errors, err)
+}
+
+// End ends the span.
+func (s *RecordedSpan) End() {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.Ended = time.Now()
+}
+
+// Add adds to a counter.
+func (r *Recorder) Add(_ context.Context, name string, value int64, _ ...Attribute) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metric(name).Counter += value
+}
+
+// Record records a value.
+func (r *Recorder) Record(_ context.Context, name string, value float64, _ ...Attribute) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m := r.metric(name)
+	m.Values = append(m.Values, value)
+}
+
+func (r *Recorder) metric(name string) *RecordedMetric {
+	m, ok := r.metrics[name]
+	if !ok {
+		m = &RecordedMetric{Name: name}
+		r.metrics[name] = m
+	}
+	return m
+}
+
+// Spans returns the spans with a name, in the order they were started, or every span when the name is empty.
+func (r *Recorder) Spans(name string) []*RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var spans []*RecordedSpan
+	for _, s := range r.spans {
+		if name == "" || s.Name == name {
+			spans = append(spans, s)
+		}
+	}
+	return spans
+}
+
+// Metric returns a copy of a recorded metric, or nil when nothing was recorded with its name.
+func (r *Recorder) Metric(name string) *RecordedMetric {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m, ok := r.metrics[name]
+	if !ok {
+		return nil
+	}
+	return &RecordedMetric{Name: m.Name, Counter: m.Counter, Values: append([]float64(nil), m.Values...)}
+}
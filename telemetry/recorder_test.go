@@ -0,0 +1,44 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder()
+	tracer := recorder.Tracer("burgers")
+
+	ctx, walk := tracer.Start(context.Background(), SpanWalk)
+	_, resolve := tracer.Start(ctx, SpanResolveSchema, String("reference", "#/components/schemas/Burger"))
+	resolve.RecordError(errors.New("no pickles"))
+	resolve.End()
+	walk.SetAttributes(Int("objects", 12))
+	walk.End()
+
+	assert.Len(t, recorder.Spans(""), 2)
+	walks := recorder.Spans(SpanWalk)
+	resolves := recorder.Spans(SpanResolveSchema)
+	require.Len(t, walks, 1)
+	require.Len(t, resolves, 1)
+	assert.Nil(t, walks[0].Parent)
+	assert.Equal(t, 12, walks[0].Attributes["objects"])
+	assert.False(t, walks[0].Ended.Before(walks[0].Started))
+	assert.Same(t, walks[0], resolves[0].Parent)
+	assert.Equal(t, "#/components/schemas/Burger", resolves[0].Attributes["reference"])
+	assert.EqualError(t, resolves[0].Errors[0], "no pickles")
+
+	meter := recorder.Meter("burgers")
+	meter.Add(context.Background(), MetricSchemaCacheHits, 2)
+	meter.Add(context.Background(), MetricSchemaCacheHits, 3)
+	meter.Record(context.Background(), MetricSchemaCacheHitRate, 0.5)
+	assert.Equal(t, int64(5), recorder.Metric(MetricSchemaCacheHits).Counter)
+	assert.Equal(t, []float64{0.5}, recorder.Metric(MetricSchemaCacheHitRate).Values)
+	assert.Nil(t, recorder.Metric(MetricRenderDuration))
+}
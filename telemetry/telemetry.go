@@ -0,0 +1,136 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package telemetry traces and measures the work of the doctor (walks, schema resolution, graph builds and
+// renders), so services embedding it can see where the time goes on big specifications.
+//
+// The doctor does not depend on OpenTelemetry, it records through the small TracerProvider and MeterProvider
+// interfaces of this package. OpenTelemetry tracer and meter providers are used through the adapter in the otel
+// package beneath this one.
+package telemetry
+
+import (
+	"context"
+	"time"
+)
+
+// Spans started by the doctor.
+const (
+	SpanWalk          = "doctor.walk"
+	SpanResolveSchema = "doctor.resolve_schema"
+	SpanBuildGraph    = "doctor.build_graph"
+	SpanRender        = "doctor.render"
+)
+
+// Metrics recorded by the doctor.
+const (
+	// MetricObjectsWalked counts the objects walked.
+	MetricObjectsWalked = "doctor.walk.objects"
+
+	// MetricSchemaCacheHits and MetricSchemaCacheMisses count the lookups of the schema cache, and
+	// MetricSchemaCacheHitRate records the share of lookups of a walk that were hits, from 0 to 1.
+	MetricSchemaCacheHits    = "doctor.walk.schema_cache.hits"
+	MetricSchemaCacheMisses  = "doctor.walk.schema_cache.misses"
+	MetricSchemaCacheHitRate = "doctor.walk.schema_cache.hit_rate"
+
+	// MetricRenderDuration records how long a render took, in seconds.
+	MetricRenderDuration = "doctor.render.duration"
+)
+
+// Attribute is a key and value recorded with a span or metric.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute.
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span is a span of work started by a Tracer.
+type Span interface {
+	SetAttributes(attributes ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts spans, the returned context carries the span so spans started with it are its children.
+type Tracer interface {
+	Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span)
+}
+
+// TracerProvider provides the tracer of an instrumentation scope.
+type TracerProvider interface {
+	Tracer(scope string) Tracer
+}
+
+// Meter records metrics, Add adds to a counter and Record records a value of a histogram.
+type Meter interface {
+	Add(ctx context.Context, name string, value int64, attributes ...Attribute)
+	Record(ctx context.Context, name string, value float64, attributes ...Attribute)
+}
+
+// MeterProvider provides the meter of an instrumentation scope. Metrics are recorded when the TracerProvider
+// given to the doctor is also a MeterProvider.
+type MeterProvider interface {
+	Meter(scope string) Meter
+}
+
+// Instruments are the tracer and meter of a scope. Nil instruments, and instruments without a meter, record
+// nothing, so instrumented code doesn't have to check whether telemetry is enabled.
+type Instruments struct {
+	tracer Tracer
+	meter  Meter
+}
+
+// Instrument returns the instruments of a scope from a provider, or nil when the provider is nil.
+func Instrument(provider TracerProvider, scope string) *Instruments {
+	if provider == nil {
+		return nil
+	}
+	i := &Instruments{tracer: provider.Tracer(scope)}
+	if mp, ok := provider.(MeterProvider); ok {
+		i.meter = mp.Meter(scope)
+	}
+	return i
+}
+
+// Start starts a span, it returns the context unchanged and a span that records nothing when the instruments
+// are nil.
+func (i *Instruments) Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span) {
+	if i == nil || i.tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return i.tracer.Start(ctx, name, attributes...)
+}
+
+// Add adds to a counter.
+func (i *Instruments) Add(ctx context.Context, name string, value int64, attributes ...Attribute) {
+	if i != nil && i.meter != nil {
+		i.meter.Add(ctx, name, value, attributes...)
+	}
+}
+
+// Record records a value.
+func (i *Instruments) Record(ctx context.Context, name string, value float64, attributes ...Attribute) {
+	if i != nil && i.meter != nil {
+		i.meter.Record(ctx, name, value, attributes...)
+	}
+}
+
+// Duration records the seconds since a start time.
+func (i *Instruments) Duration(ctx context.Context, name string, start time.Time, attributes ...Attribute) {
+	i.Record(ctx, name, time.Since(start).Seconds(), attributes...)
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
@@ -0,0 +1,58 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package telemetry
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// tracerOnly is a provider without a meter.
+type tracerOnly struct {
+	recorder *Recorder
+}
+
+func (t tracerOnly) Tracer(scope string) Tracer {
+	return t.recorder.Tracer(scope)
+}
+
+func TestInstrument(t *testing.T) {
+	// nil instruments record nothing.
+	var nothing *Instruments
+	assert.Nil(t, Instrument(nil, "burgers"))
+	ctx := context.Background()
+	spanCtx, span := nothing.Start(ctx, SpanWalk)
+	assert.Equal(t, ctx, spanCtx)
+	span.SetAttributes(Int("objects", 1))
+	span.RecordError(assert.AnError)
+	span.End()
+	nothing.Add(ctx, MetricObjectsWalked, 1)
+	nothing.Duration(ctx, MetricRenderDuration, time.Now())
+
+	recorder := NewRecorder()
+	instruments := Instrument(recorder, "burgers")
+	_, span = instruments.Start(ctx, SpanRender, String("format", "json"))
+	span.End()
+	instruments.Add(ctx, MetricObjectsWalked, 2)
+	instruments.Duration(ctx, MetricRenderDuration, time.Now().Add(-time.Second))
+
+	spans := recorder.Spans(SpanRender)
+	require.Len(t, spans, 1)
+	assert.Equal(t, "json", spans[0].Attributes["format"])
+	assert.Equal(t, int64(2), recorder.Metric(MetricObjectsWalked).Counter)
+	require.Len(t, recorder.Metric(MetricRenderDuration).Values, 1)
+	assert.GreaterOrEqual(t, recorder.Metric(MetricRenderDuration).Values[0], 1.0)
+
+	// providers that are not meter providers only trace.
+	tracing := NewRecorder()
+	instruments = Instrument(tracerOnly{tracing}, "burgers")
+	_, span = instruments.Start(ctx, SpanWalk)
+	span.End()
+	instruments.Add(ctx, MetricObjectsWalked, 1)
+	assert.Len(t, tracing.Spans(SpanWalk), 1)
+	assert.Nil(t, tracing.Metric(MetricObjectsWalked))
+}
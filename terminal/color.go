@@ -0,0 +1,232 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package terminal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Level is how many colors a terminal shows, levels are ordered from no color to truecolor.
+type Level int
+
+const (
+	// LevelAuto detects the level from the terminal and the environment (see DetectLevel), it is only used to
+	// configure a painter.
+	LevelAuto Level = iota
+
+	// LevelNone renders plain text.
+	LevelNone
+
+	// LevelBasic renders the 16 ANSI colors, which terminals map to their own palette.
+	LevelBasic
+
+	// Level256 renders the 256 color palette.
+	Level256
+
+	// LevelTrueColor renders 24 bit colors.
+	LevelTrueColor
+)
+
+type colorKind uint8
+
+const (
+	colorDefault colorKind = iota
+	colorANSI
+	color256
+	colorRGB
+)
+
+// Color is a foreground color: one of the 16 ANSI colors, an index of the 256 color palette, or a 24 bit truecolor.
+// The zero Color is the default color of the terminal. Colors are rendered at the level of the terminal, colors the
+// terminal can't show are rendered as the closest color it can.
+type Color struct {
+	kind    colorKind
+	index   uint8
+	r, g, b uint8
+}
+
+// ANSI returns one of the 16 ANSI colors, 0 to 7 are the normal colors (black, red, green, yellow, blue, magenta,
+// cyan and white) and 8 to 15 their bright versions.
+func ANSI(index uint8) Color {
+	return Color{kind: colorANSI, index: index % 16}
+}
+
+// Index returns a color of the 256 color palette.
+func Index(index uint8) Color {
+	return Color{kind: color256, index: index}
+}
+
+// RGB returns a 24 bit truecolor.
+func RGB(r, g, b uint8) Color {
+	return Color{kind: colorRGB, r: r, g: g, b: b}
+}
+
+// ansiNames are the names of the 16 ANSI colors, bright colors are prefixed with 'bright-'.
+var ansiNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// ParseColor parses a color written as a '#rrggbb' truecolor, an index of the 256 color palette ('208'), or the
+// name of an ANSI color ('red', 'bright-blue'). An empty string is the default color.
+func ParseColor(s string) (Color, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" || s == "default" {
+		return Color{}, nil
+	}
+	if hex, ok := strings.CutPrefix(s, "#"); ok {
+		v, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || len(hex) != 6 {
+			return Color{}, fmt.Errorf("'%s' is not a #rrggbb color", s)
+		}
+		return RGB(uint8(v>>16), uint8(v>>8), uint8(v)), nil
+	}
+	if v, err := strconv.ParseUint(s, 10, 8); err == nil {
+		return Index(uint8(v)), nil
+	}
+	name, bright := strings.CutPrefix(s, "bright-")
+	for i, n := range ansiNames {
+		if n == name {
+			if bright {
+				i += 8
+			}
+			return ANSI(uint8(i)), nil
+		}
+	}
+	return Color{}, fmt.Errorf("'%s' is not a color, colors are #rrggbb, 0 to 255 or the name of an ANSI color", s)
+}
+
+// ansiRGB are the colors xterm renders the 16 ANSI colors as, to find the closest ANSI color to another color.
+var ansiRGB = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0}, {0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0}, {92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the values of each channel of the 6×6×6 color cube of the 256 color palette.
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// rgb returns the 24 bit value of a color.
+func (c Color) rgb() (uint8, uint8, uint8) {
+	switch c.kind {
+	case colorRGB:
+		return c.r, c.g, c.b
+	case colorANSI:
+		return ansiRGB[c.index][0], ansiRGB[c.index][1], ansiRGB[c.index][2]
+	}
+	switch {
+	case c.index < 16:
+		return ansiRGB[c.index][0], ansiRGB[c.index][1], ansiRGB[c.index][2]
+	case c.index < 232:
+		i := c.index - 16
+		return cubeLevels[i/36], cubeLevels[i/6%6], cubeLevels[i%6]
+	}
+	gray := 8 + (c.index-232)*10
+	return gray, gray, gray
+}
+
+// to256 returns the closest index of the 256 color palette, from the color cube or the gray ramp.
+func (c Color) to256() uint8 {
+	if c.kind == color256 {
+		return c.index
+	}
+	if c.kind == colorANSI {
+		return c.index
+	}
+	r, g, b := c.rgb()
+	cube := func(v uint8) uint8 {
+		if v < 48 {
+			return 0
+		}
+		if v < 115 {
+			return 1
+		}
+		return (v - 35) / 40
+	}
+	ci := 16 + 36*cube(r) + 6*cube(g) + cube(b)
+	gi := uint8(232)
+	if avg := (int(r) + int(g) + int(b)) / 3; avg > 238 {
+		gi = 255
+	} else if avg > 8 {
+		gi = uint8(232 + (avg-8)/10)
+	}
+	if distance(Index(gi), r, g, b) < distance(Index(ci), r, g, b) {
+		return gi
+	}
+	return ci
+}
+
+// toANSI returns the closest of the 16 ANSI colors.
+func (c Color) toANSI() uint8 {
+	if c.kind == colorANSI {
+		return c.index
+	}
+	r, g, b := c.rgb()
+	closest, best := uint8(0), -1
+	for i := range ansiRGB {
+		if d := distance(ANSI(uint8(i)), r, g, b); best < 0 || d < best {
+			closest, best = uint8(i), d
+		}
+	}
+	return closest
+}
+
+func distance(c Color, r, g, b uint8) int {
+	cr, cg, cb := c.rgb()
+	dr, dg, db := int(cr)-int(r), int(cg)-int(g), int(cb)-int(b)
+	return dr*dr + dg*dg + db*db
+}
+
+// sgr returns the SGR parameters of the color as a foreground, at a level, empty for the default color.
+func (c Color) sgr(level Level) string {
+	if c.kind == colorDefault || level <= LevelNone {
+		return ""
+	}
+	switch {
+	case level >= LevelTrueColor && c.kind == colorRGB:
+		return fmt.Sprintf("38;2;%d;%d;%d", c.r, c.g, c.b)
+	case level >= Level256 && c.kind != colorANSI:
+		return fmt.Sprintf("38;5;%d", c.to256())
+	}
+	i := c.toANSI()
+	if i < 8 {
+		return strconv.Itoa(30 + int(i))
+	}
+	return strconv.Itoa(90 + int(i) - 8)
+}
+
+// Style is how a part of the output is painted: a foreground color and attributes.
+type Style struct {
+	Foreground Color
+	Bold       bool
+	Faint      bool
+	Italic     bool
+	Underline  bool
+}
+
+// sequence returns the escape sequence that starts the style at a level, empty when the style is plain or the
+// level has no colors.
+func (s Style) sequence(level Level) string {
+	if level <= LevelNone {
+		return ""
+	}
+	var params []string
+	if s.Bold {
+		params = append(params, "1")
+	}
+	if s.Faint {
+		params = append(params, "2")
+	}
+	if s.Italic {
+		params = append(params, "3")
+	}
+	if s.Underline {
+		params = append(params, "4")
+	}
+	if fg := s.Foreground.sgr(level); fg != "" {
+		params = append(params, fg)
+	}
+	if len(params) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package terminal
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	for s, expected := range map[string]Color{
+		"":            {},
+		"#268bd2":     RGB(0x26, 0x8b, 0xd2),
+		"208":         Index(208),
+		"red":         ANSI(1),
+		"Bright-Blue": ANSI(12),
+	} {
+		c, err := ParseColor(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, expected, c, s)
+	}
+	for _, s := range []string{"#26b", "256", "mauve"} {
+		_, err := ParseColor(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestColor_Levels(t *testing.T) {
+	orange := RGB(0xff, 0x87, 0x00)
+	assert.Equal(t, "38;2;255;135;0", orange.sgr(LevelTrueColor))
+	assert.Equal(t, "38;5;208", orange.sgr(Level256))
+	assert.Equal(t, "33", orange.sgr(LevelBasic))
+	assert.Equal(t, "", orange.sgr(LevelNone))
+
+	// grays are rendered from the gray ramp, ANSI colors are rendered as they are at every level.
+	assert.Equal(t, "38;5;244", RGB(0x80, 0x80, 0x80).sgr(Level256))
+	assert.Equal(t, "34", ANSI(4).sgr(LevelTrueColor))
+	assert.Equal(t, "31", Index(160).sgr(LevelBasic))
+	assert.Equal(t, "", Color{}.sgr(LevelTrueColor))
+}
+
+func TestStyle_Sequence(t *testing.T) {
+	style := Style{Foreground: ANSI(2), Bold: true, Underline: true}
+	assert.Equal(t, "\x1b[1;4;32m", style.sequence(LevelBasic))
+	assert.Equal(t, "", style.sequence(LevelNone))
+	assert.Equal(t, "\x1b[2m", Style{Faint: true}.sequence(LevelTrueColor))
+	assert.Equal(t, "", Style{}.sequence(LevelTrueColor))
+}
@@ -0,0 +1,154 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package terminal
+
+import (
+	"fmt"
+	"golang.org/x/term"
+	"os"
+	"strings"
+)
+
+// reset ends a style.
+const reset = "\x1b[0m"
+
+// ColorConfig configures how output to a terminal is colored.
+type ColorConfig struct {
+	// Theme is the name of a registered theme (see Themes), ThemeTerminal when empty.
+	Theme string
+
+	// Palette overrides the colors of the theme.
+	Palette *Palette
+
+	// Level is the level of color to render, detected from the terminal and the environment when LevelAuto (see
+	// DetectLevel).
+	Level Level
+}
+
+// Palette is a user defined set of colors, each overrides the foreground of a part of the theme. Colors are written
+// as '#rrggbb' truecolors, indexes of the 256 color palette ('208') or the names of ANSI colors ('bright-red'), see
+// ParseColor. Empty colors keep the color of the theme.
+type Palette struct {
+	Branch   string `json:"branch,omitempty" yaml:"branch,omitempty"`
+	Key      string `json:"key,omitempty" yaml:"key,omitempty"`
+	Value    string `json:"value,omitempty" yaml:"value,omitempty"`
+	Location string `json:"location,omitempty" yaml:"location,omitempty"`
+	Path     string `json:"path,omitempty" yaml:"path,omitempty"`
+	Error    string `json:"error,omitempty" yaml:"error,omitempty"`
+	Warning  string `json:"warning,omitempty" yaml:"warning,omitempty"`
+	Info     string `json:"info,omitempty" yaml:"info,omitempty"`
+}
+
+// Painter paints text with the styles of a scheme, at the level of color of a terminal.
+type Painter struct {
+	Scheme *Scheme
+	Level  Level
+}
+
+// NewPainter returns a painter for output to a file, with the theme, palette and level of the configuration. A nil
+// configuration paints with the terminal theme at the detected level.
+func NewPainter(f *os.File, config *ColorConfig) (*Painter, error) {
+	if config == nil {
+		config = &ColorConfig{}
+	}
+	name := config.Theme
+	if name == "" {
+		name = ThemeTerminal
+	}
+	theme := Theme(name)
+	if theme == nil {
+		return nil, fmt.Errorf("unknown theme '%s', themes are %s", name, strings.Join(Themes(), ", "))
+	}
+	scheme := *theme
+	if p := config.Palette; p != nil {
+		for _, o := range []struct {
+			name  string
+			color string
+			style *Style
+		}{
+			{"branch", p.Branch, &scheme.Branch},
+			{"key", p.Key, &scheme.Key},
+			{"value", p.Value, &scheme.Value},
+			{"location", p.Location, &scheme.Location},
+			{"path", p.Path, &scheme.Path},
+			{"error", p.Error, &scheme.Error},
+			{"warning", p.Warning, &scheme.Warning},
+			{"info", p.Info, &scheme.Info},
+		} {
+			if o.color == "" {
+				continue
+			}
+			c, err := ParseColor(o.color)
+			if err != nil {
+				return nil, fmt.Errorf("unable to use the %s color of the palette: %w", o.name, err)
+			}
+			o.style.Foreground = c
+		}
+	}
+	level := config.Level
+	if level == LevelAuto {
+		level = DetectLevel(f)
+	}
+	return &Painter{Scheme: &scheme, Level: level}, nil
+}
+
+// Paint paints text with a style, text is returned as it is when the painter has no color.
+func (p *Painter) Paint(style Style, text string) string {
+	if p == nil || text == "" {
+		return text
+	}
+	start := style.sequence(p.Level)
+	if start == "" {
+		return text
+	}
+	return start + text + reset
+}
+
+// DetectLevel decides the level of color of output to a file from the environment and the terminal, following the
+// FORCE_COLOR, NO_COLOR (https://no-color.org) and CLICOLOR conventions, in that order:
+//
+//   - FORCE_COLOR forces color even when the file is not a terminal: '1' (or any other value) forces the basic
+//     colors, '2' 256 colors and '3' truecolor. '0' and 'false' disable color.
+//   - NO_COLOR disables color when it is set and not empty.
+//   - CLICOLOR_FORCE forces color when it is set and not '0', CLICOLOR=0 disables color.
+//
+// Otherwise, files that are not terminals, and dumb terminals, have no color. The level of a terminal is read from
+// COLORTERM (truecolor or 24bit) and TERM (256color).
+func DetectLevel(f *os.File) Level {
+	return detectLevel(os.Getenv, f != nil && term.IsTerminal(int(f.Fd())))
+}
+
+func detectLevel(getenv func(string) string, terminal bool) Level {
+	supported := func() Level {
+		colorterm := strings.ToLower(getenv("COLORTERM"))
+		switch {
+		case colorterm == "truecolor" || colorterm == "24bit":
+			return LevelTrueColor
+		case strings.Contains(getenv("TERM"), "256color"):
+			return Level256
+		}
+		return LevelBasic
+	}
+	if force := strings.ToLower(getenv("FORCE_COLOR")); force != "" {
+		switch force {
+		case "0", "false":
+			return LevelNone
+		case "2":
+			return Level256
+		case "3":
+			return LevelTrueColor
+		}
+		return LevelBasic
+	}
+	if getenv("NO_COLOR") != "" {
+		return LevelNone
+	}
+	if force := getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return supported()
+	}
+	if !terminal || getenv("CLICOLOR") == "0" || getenv("TERM") == "dumb" {
+		return LevelNone
+	}
+	return supported()
+}
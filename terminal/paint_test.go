@@ -0,0 +1,63 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package terminal
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestNewPainter(t *testing.T) {
+	p, err := NewPainter(nil, &ColorConfig{Theme: ThemeSolarized, Level: LevelTrueColor,
+		Palette: &Palette{Key: "#ff8700", Error: "bright-red"}})
+	require.NoError(t, err)
+	assert.Equal(t, "\x1b[1;38;2;255;135;0mname\x1b[0m", p.Paint(p.Scheme.Key, "name"))
+	assert.Equal(t, "\x1b[1;91merror\x1b[0m", p.Paint(p.Scheme.Error, "error"))
+	assert.Equal(t, "\x1b[38;2;133;153;0mvalue\x1b[0m", p.Paint(p.Scheme.Value, "value"))
+	// the palette does not change the theme.
+	assert.Equal(t, RGB(0x26, 0x8b, 0xd2), Solarized.Key.Foreground)
+
+	_, err = NewPainter(nil, &ColorConfig{Theme: "neon-dreams"})
+	assert.ErrorContains(t, err, "unknown theme 'neon-dreams'")
+	_, err = NewPainter(nil, &ColorConfig{Palette: &Palette{Path: "mauve"}})
+	assert.ErrorContains(t, err, "unable to use the path color of the palette")
+
+	// output that is not a terminal has no color.
+	t.Setenv("FORCE_COLOR", "")
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+	p, err = NewPainter(nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, LevelNone, p.Level)
+	assert.Equal(t, "name", p.Paint(p.Scheme.Key, "name"))
+}
+
+func TestDetectLevel(t *testing.T) {
+	level := func(terminal bool, env ...string) Level {
+		vars := make(map[string]string)
+		for _, kv := range env {
+			k, v, _ := strings.Cut(kv, "=")
+			vars[k] = v
+		}
+		return detectLevel(func(k string) string { return vars[k] }, terminal)
+	}
+	assert.Equal(t, LevelBasic, level(true, "TERM=xterm"))
+	assert.Equal(t, Level256, level(true, "TERM=xterm-256color"))
+	assert.Equal(t, LevelTrueColor, level(true, "TERM=xterm-256color", "COLORTERM=truecolor"))
+	assert.Equal(t, LevelNone, level(false, "TERM=xterm-256color"))
+	assert.Equal(t, LevelNone, level(true, "TERM=dumb"))
+
+	assert.Equal(t, LevelNone, level(true, "NO_COLOR=1"))
+	assert.Equal(t, LevelNone, level(true, "CLICOLOR=0"))
+	assert.Equal(t, Level256, level(false, "CLICOLOR_FORCE=1", "TERM=xterm-256color"))
+	assert.Equal(t, LevelNone, level(false, "CLICOLOR_FORCE=0"))
+
+	assert.Equal(t, LevelBasic, level(false, "FORCE_COLOR=1"))
+	assert.Equal(t, LevelBasic, level(false, "FORCE_COLOR=true", "NO_COLOR=1"))
+	assert.Equal(t, Level256, level(false, "FORCE_COLOR=2"))
+	assert.Equal(t, LevelTrueColor, level(false, "FORCE_COLOR=3"))
+	assert.Equal(t, LevelNone, level(true, "FORCE_COLOR=0", "TERM=xterm"))
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package terminal
+
+import (
+	"errors"
+	"slices"
+	"sync"
+)
+
+// Names of the built in themes.
+const (
+	ThemeTerminal       = "terminal"
+	ThemeGrayscale      = "grayscale"
+	ThemeSolarized      = "solarized"
+	ThemeHighContrast   = "high-contrast"
+	ThemeColorblindSafe = "colorblind-safe"
+)
+
+// Scheme is the style of every part of the output of the doctor in a terminal.
+type Scheme struct {
+	Name string
+
+	// Branch is the lines of a tree, Key and Value the key and value of a node, Location its line:col and Path its
+	// JSONPath.
+	Branch   Style
+	Key      Style
+	Value    Style
+	Location Style
+	Path     Style
+
+	// Error, Warning and Info are the severities of findings.
+	Error   Style
+	Warning Style
+	Info    Style
+}
+
+// Terminal is painted with the 16 ANSI colors, so it follows the palette of the terminal.
+var Terminal = &Scheme{
+	Name:     ThemeTerminal,
+	Branch:   Style{Faint: true},
+	Key:      Style{Foreground: ANSI(4), Bold: true},
+	Value:    Style{Foreground: ANSI(2)},
+	Location: Style{Faint: true},
+	Path:     Style{Foreground: ANSI(6)},
+	Error:    Style{Foreground: ANSI(1), Bold: true},
+	Warning:  Style{Foreground: ANSI(3)},
+	Info:     Style{Foreground: ANSI(4)},
+}
+
+// Grayscale is painted with the gray ramp of the 256 color palette and attributes, for terminals (and readers)
+// without color.
+var Grayscale = &Scheme{
+	Name:     ThemeGrayscale,
+	Branch:   Style{Foreground: Index(240)},
+	Key:      Style{Foreground: Index(255), Bold: true},
+	Value:    Style{Foreground: Index(250)},
+	Location: Style{Foreground: Index(243)},
+	Path:     Style{Foreground: Index(245), Italic: true},
+	Error:    Style{Foreground: Index(255), Bold: true, Underline: true},
+	Warning:  Style{Foreground: Index(252), Bold: true},
+	Info:     Style{Foreground: Index(248)},
+}
+
+// Solarized is painted with the accents of the solarized palette.
+var Solarized = &Scheme{
+	Name:     ThemeSolarized,
+	Branch:   Style{Foreground: RGB(0x58, 0x6e, 0x75)},
+	Key:      Style{Foreground: RGB(0x26, 0x8b, 0xd2), Bold: true},
+	Value:    Style{Foreground: RGB(0x85, 0x99, 0x00)},
+	Location: Style{Foreground: RGB(0x65, 0x7b, 0x83)},
+	Path:     Style{Foreground: RGB(0x2a, 0xa1, 0x98)},
+	Error:    Style{Foreground: RGB(0xdc, 0x32, 0x2f), Bold: true},
+	Warning:  Style{Foreground: RGB(0xb5, 0x89, 0x00)},
+	Info:     Style{Foreground: RGB(0x6c, 0x71, 0xc4)},
+}
+
+// HighContrast is painted with the bright ANSI colors, and severities are bold.
+var HighContrast = &Scheme{
+	Name:     ThemeHighContrast,
+	Branch:   Style{Foreground: ANSI(15)},
+	Key:      Style{Foreground: ANSI(15), Bold: true},
+	Value:    Style{Foreground: ANSI(11)},
+	Location: Style{Foreground: ANSI(14)},
+	Path:     Style{Foreground: ANSI(13)},
+	Error:    Style{Foreground: ANSI(9), Bold: true, Underline: true},
+	Warning:  Style{Foreground: ANSI(11), Bold: true},
+	Info:     Style{Foreground: ANSI(14), Bold: true},
+}
+
+// ColorblindSafe is painted with the Okabe-Ito palette, which stays distinct with every common form of color
+// blindness. Errors and warnings differ by attribute as well as by color.
+var ColorblindSafe = &Scheme{
+	Name:     ThemeColorblindSafe,
+	Branch:   Style{Faint: true},
+	Key:      Style{Foreground: RGB(0x56, 0xb4, 0xe9), Bold: true},
+	Value:    Style{Foreground: RGB(0x00, 0x9e, 0x73)},
+	Location: Style{Foreground: RGB(0xf0, 0xe4, 0x42)},
+	Path:     Style{Foreground: RGB(0xcc, 0x79, 0xa7)},
+	Error:    Style{Foreground: RGB(0xd5, 0x5e, 0x00), Bold: true, Underline: true},
+	Warning:  Style{Foreground: RGB(0xe6, 0x9f, 0x00), Bold: true},
+	Info:     Style{Foreground: RGB(0x00, 0x72, 0xb2)},
+}
+
+var themes = struct {
+	sync.RWMutex
+	byName map[string]*Scheme
+}{byName: map[string]*Scheme{
+	ThemeTerminal:       Terminal,
+	ThemeGrayscale:      Grayscale,
+	ThemeSolarized:      Solarized,
+	ThemeHighContrast:   HighContrast,
+	ThemeColorblindSafe: ColorblindSafe,
+}}
+
+// RegisterTheme adds a scheme to the themes, by its name, replacing a theme with the same name.
+func RegisterTheme(scheme *Scheme) error {
+	if scheme == nil || scheme.Name == "" {
+		return errors.New("a theme must have a name")
+	}
+	themes.Lock()
+	defer themes.Unlock()
+	themes.byName[scheme.Name] = scheme
+	return nil
+}
+
+// Theme returns a theme by name, or nil when there is no theme with the name.
+func Theme(name string) *Scheme {
+	themes.RLock()
+	defer themes.RUnlock()
+	return themes.byName[name]
+}
+
+// Themes returns the names of every theme, sorted.
+func Themes() []string {
+	themes.RLock()
+	defer themes.RUnlock()
+	var names []string
+	for name := range themes.byName {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,23 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package terminal
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestThemes(t *testing.T) {
+	assert.Subset(t, Themes(), []string{ThemeColorblindSafe, ThemeGrayscale, ThemeHighContrast, ThemeSolarized,
+		ThemeTerminal})
+	assert.Same(t, Grayscale, Theme(ThemeGrayscale))
+	assert.Nil(t, Theme("neon"))
+
+	neon := &Scheme{Name: "neon", Key: Style{Foreground: RGB(0x39, 0xff, 0x14)}}
+	require.NoError(t, RegisterTheme(neon))
+	assert.Same(t, neon, Theme("neon"))
+	assert.Contains(t, Themes(), "neon")
+	assert.Error(t, RegisterTheme(&Scheme{}))
+}
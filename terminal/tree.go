@@ -0,0 +1,271 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package terminal renders the doctor model for a terminal: trees of walked objects and YAML nodes, fitted to the
+// width of the terminal and painted with a theme at the level of color the terminal (and its user) allows.
+package terminal
+
+import (
+	"fmt"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ellipsis marks where a value or a path was truncated.
+const ellipsis = "…"
+
+// minValueWidth is the fewest characters of a value kept when a line is truncated, narrower values are dropped
+// to the ellipsis.
+const minValueWidth = 4
+
+// minPathWidth is the fewest characters of a JSONPath kept when its middle is truncated, narrower paths are left
+// out of the line.
+const minPathWidth = 16
+
+// TreeConfig controls how a tree is rendered.
+type TreeConfig struct {
+	// MaxWidth is the widest a line is rendered. Values that overflow it are truncated, keeping the line:col
+	// suffix of the line. When zero, the width of the terminal the tree is rendered to is used (see Width), and
+	// lines are not truncated when it isn't a terminal. Lines are never truncated when negative.
+	MaxWidth int
+
+	// Paths renders the JSONPath of every node after its location.
+	Paths bool
+
+	// TruncatePaths shortens JSONPaths that overflow the line by removing their middle, so the root and the
+	// object the path ends at are both kept. Paths that overflow the line are otherwise left out of it, as are
+	// paths that would be too short to read.
+	TruncatePaths bool
+
+	// Colors paints the branches, keys, values, locations and paths of the tree, it is rendered as plain text when
+	// nil. Lines are fitted to the width before they are painted.
+	Colors *Painter
+}
+
+// TreeNode is a line of a tree: a key, an optional value, the location and JSONPath of the node, and the nodes
+// beneath it.
+type TreeNode struct {
+	Key      string
+	Value    string
+	Path     string
+	Line     int
+	Column   int
+	Children []*TreeNode
+}
+
+// ObjectTree builds the tree of a walked doctor object, and every object beneath it. Objects are keyed by the part
+// of their JSONPath beneath their parent, and valued by their type.
+func ObjectTree(object drBase.Foundational) *TreeNode {
+	if object == nil {
+		return nil
+	}
+	return objectTree(object, "")
+}
+
+func objectTree(object drBase.Foundational, parentPath string) *TreeNode {
+	path := object.GenerateJSONPath()
+	key := strings.TrimPrefix(strings.TrimPrefix(path, parentPath), ".")
+	switch {
+	case parentPath == "":
+		key = path
+	case strings.HasPrefix(key, "['") && strings.HasSuffix(key, "']") && strings.Count(key, "['") == 1:
+		key = key[2 : len(key)-2]
+	case strings.HasPrefix(key, "[") && strings.HasSuffix(key, "]") && strings.Count(key, "[") == 1:
+		key = key[1 : len(key)-1]
+	}
+	tn := &TreeNode{Key: key, Value: object.GetInstanceType(), Path: path}
+	node := object.GetKeyNode()
+	if node == nil {
+		node = object.GetValueNode()
+	}
+	if node != nil {
+		tn.Line, tn.Column = node.Line, node.Column
+	}
+	for _, child := range drBase.ChildrenOf(object) {
+		tn.Children = append(tn.Children, objectTree(child, path))
+	}
+	return tn
+}
+
+// NodeTree builds the tree of a YAML node, at a JSONPath: mappings are keyed by their keys, sequences by their
+// indexes, and scalars are valued by their values. Aliases are rendered as the anchor they refer to.
+func NodeTree(key string, node *yaml.Node, path string) *TreeNode {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		return NodeTree(key, node.Content[0], path)
+	}
+	tn := &TreeNode{Key: key, Path: path, Line: node.Line, Column: node.Column}
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k := node.Content[i]
+			child := NodeTree(k.Value, node.Content[i+1], childPath(path, k.Value))
+			child.Line, child.Column = k.Line, k.Column
+			tn.Children = append(tn.Children, child)
+		}
+	case yaml.SequenceNode:
+		for i, n := range node.Content {
+			tn.Children = append(tn.Children, NodeTree(strconv.Itoa(i), n, fmt.Sprintf("%s[%d]", path, i)))
+		}
+	case yaml.AliasNode:
+		tn.Value = "*" + node.Value
+	default:
+		tn.Value = node.Value
+	}
+	return tn
+}
+
+// childPath appends a key to a JSONPath, in the bracket notation when the key is not a plain name.
+func childPath(path, key string) string {
+	if key != "" && (key[0] < '0' || key[0] > '9') && strings.IndexFunc(key, func(r rune) bool {
+		return !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) < 0 {
+		return path + "." + key
+	}
+	return path + "['" + key + "']"
+}
+
+// Render renders the tree, one line for every node. The width of the lines is decided by the configuration, and
+// the terminal of w when it is one.
+func (t *TreeNode) Render(w io.Writer, config *TreeConfig) error {
+	if config == nil {
+		config = &TreeConfig{}
+	}
+	width := config.MaxWidth
+	if width == 0 {
+		if f, ok := w.(*os.File); ok {
+			width = Width(f)
+		}
+	}
+	_, err := io.WriteString(w, t.render(width, config))
+	return err
+}
+
+// String renders the tree without truncating it.
+func (t *TreeNode) String() string {
+	return t.render(-1, &TreeConfig{})
+}
+
+func (t *TreeNode) render(width int, config *TreeConfig) string {
+	if t == nil {
+		return ""
+	}
+	buf := strings.Builder{}
+	var walk func(n *TreeNode, prefix, branch string)
+	walk = func(n *TreeNode, prefix, branch string) {
+		buf.WriteString(fitLine(prefix+branch, n, width, config) + "\n")
+		switch branch {
+		case "├── ":
+			prefix += "│   "
+		case "└── ":
+			prefix += "    "
+		}
+		for i, c := range n.Children {
+			if i == len(n.Children)-1 {
+				walk(c, prefix, "└── ")
+			} else {
+				walk(c, prefix, "├── ")
+			}
+		}
+	}
+	walk(t, "", "")
+	return buf.String()
+}
+
+// treeLine is the parts of the line of a node.
+type treeLine struct {
+	prefix, key, value, location, path string
+}
+
+// render renders the line, painting every part when there is a painter.
+func (l *treeLine) render(p *Painter) string {
+	if p == nil {
+		p = &Painter{Scheme: Terminal, Level: LevelNone}
+	}
+	line := p.Paint(p.Scheme.Branch, l.prefix) + p.Paint(p.Scheme.Key, l.key)
+	if l.value != "" {
+		line += ": " + p.Paint(p.Scheme.Value, l.value)
+	}
+	if l.location != "" {
+		line += " " + p.Paint(p.Scheme.Location, l.location)
+	}
+	if l.path != "" {
+		line += " " + p.Paint(p.Scheme.Path, l.path)
+	}
+	return line
+}
+
+// width is the number of characters of the line, without color.
+func (l *treeLine) width() int {
+	return runes(l.render(nil))
+}
+
+// fitLine renders the line of a node, truncating what overflows the width: the JSONPath first (from the middle
+// when the configuration truncates paths), then the value, then the key. The location is always kept.
+func fitLine(prefix string, n *TreeNode, width int, config *TreeConfig) string {
+	l := &treeLine{prefix: prefix, key: n.Key, value: n.Value}
+	if config.Paths {
+		l.path = n.Path
+	}
+	if n.Line > 0 {
+		l.location = fmt.Sprintf("(%d:%d)", n.Line, n.Column)
+	}
+	fits := func() bool { return width <= 0 || l.width() <= width }
+	over := func() int { return l.width() - width }
+	if !fits() && l.path != "" {
+		if keep := runes(l.path) - over(); config.TruncatePaths && keep >= minPathWidth {
+			l.path = truncateMiddle(l.path, keep)
+		} else {
+			l.path = ""
+		}
+	}
+	if !fits() && l.value != "" {
+		keep := runes(l.value) - over()
+		if keep < minValueWidth {
+			keep = 1
+		}
+		l.value = truncateEnd(l.value, keep)
+	}
+	if !fits() {
+		l.key = truncateEnd(l.key, max(1, runes(l.key)-over()))
+	}
+	return l.render(config.Colors)
+}
+
+// truncateEnd keeps the first characters of s, ending it with an ellipsis, so it is no wider than width.
+func truncateEnd(s string, width int) string {
+	if runes(s) <= width {
+		return s
+	}
+	if width <= 1 {
+		return ellipsis
+	}
+	r := []rune(s)
+	return string(r[:width-1]) + ellipsis
+}
+
+// truncateMiddle keeps the start and the end of s, replacing its middle with an ellipsis, so it is no wider than
+// width. The end is favoured, it names the object the path ends at.
+func truncateMiddle(s string, width int) string {
+	if runes(s) <= width {
+		return s
+	}
+	if width <= 1 {
+		return ellipsis
+	}
+	r := []rune(s)
+	head := (width - 1) / 3
+	tail := width - 1 - head
+	return string(r[:head]) + ellipsis + string(r[len(r)-tail:])
+}
+
+func runes(s string) int {
+	return utf8.RuneCountInString(s)
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package terminal
+
+import (
+	drModel "github.com/pb33f/doctor/model"
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	"strings"
+	"testing"
+)
+
+const treeSpec = `openapi: 3.1.0
+info:
+  title: burgers
+  description: a very long description of the burger shop that will not fit on a narrow terminal
+paths:
+  /burgers/{burgerId}/condiments:
+    get:
+      responses:
+        '200':
+          description: ok`
+
+func treeNode(t *testing.T) *TreeNode {
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(treeSpec), &node))
+	return NodeTree("$", &node, "$")
+}
+
+func TestNodeTree(t *testing.T) {
+	assert.Equal(t, `$ (1:1)
+├── openapi: 3.1.0 (1:1)
+├── info (2:1)
+│   ├── title: burgers (3:3)
+│   └── description: a very long description of the burger shop that will not fit on a narrow terminal (4:3)
+└── paths (5:1)
+    └── /burgers/{burgerId}/condiments (6:3)
+        └── get (7:5)
+            └── responses (8:7)
+                └── 200 (9:9)
+                    └── description: ok (10:11)
+`, treeNode(t).String())
+}
+
+func TestTreeNode_Render_MaxWidth(t *testing.T) {
+	buf := strings.Builder{}
+	require.NoError(t, treeNode(t).Render(&buf, &TreeConfig{MaxWidth: 40}))
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for _, l := range lines {
+		assert.LessOrEqual(t, runes(l), 40, l)
+	}
+	assert.Equal(t, "│   └── description: a very long … (4:3)", lines[4])
+	assert.Equal(t, "    └── /burgers/{burgerId}/condi… (6:3)", lines[6])
+
+	// a value with no room left is dropped to the ellipsis, the key is truncated after it.
+	buf.Reset()
+	require.NoError(t, treeNode(t).Render(&buf, &TreeConfig{MaxWidth: 26}))
+	assert.Contains(t, buf.String(), "│   └── descript…: … (4:3)\n")
+}
+
+func TestTreeNode_Render_Paths(t *testing.T) {
+	buf := strings.Builder{}
+	require.NoError(t, treeNode(t).Render(&buf, &TreeConfig{MaxWidth: 60, Paths: true, TruncatePaths: true}))
+	assert.Contains(t, buf.String(), "        └── get (7:5) $.paths['/bu…urgerId}/condiments'].get\n")
+	assert.Contains(t, buf.String(), "│   ├── title: burgers (3:3) $.info.title\n")
+	// paths that would be too short to read are left out.
+	assert.Contains(t, buf.String(), "                    └── description: ok (10:11)\n")
+
+	// without truncating paths, paths that do not fit are left out.
+	buf.Reset()
+	require.NoError(t, treeNode(t).Render(&buf, &TreeConfig{MaxWidth: 60, Paths: true}))
+	assert.Contains(t, buf.String(), "        └── get (7:5)\n")
+
+	// lines are never truncated with a negative width.
+	buf.Reset()
+	require.NoError(t, treeNode(t).Render(&buf, &TreeConfig{MaxWidth: -1, Paths: true}))
+	assert.Contains(t, buf.String(), "└── description: ok (10:11) $.paths['/burgers/{burgerId}/condiments'].get.responses['200'].description\n")
+}
+
+func TestObjectTree(t *testing.T) {
+	doc, err := libopenapi.NewDocument([]byte(treeSpec))
+	require.NoError(t, err)
+	v3Doc, _ := doc.BuildV3Model()
+	drDoc := drModel.NewDrDocument(v3Doc)
+
+	assert.Equal(t, `$: document
+├── info (2:1)
+└── paths (5:1)
+    └── /burgers/{burgerId}/condiments (6:3)
+        └── get (7:5)
+            └── responses (8:7)
+                └── 200 (9:9)
+`, ObjectTree(drDoc.V3Document).String())
+	assert.Nil(t, ObjectTree(nil))
+}
+
+func TestTreeNode_Render_Colors(t *testing.T) {
+	tree := &TreeNode{Key: "$", Line: 1, Column: 1, Children: []*TreeNode{
+		{Key: "description", Value: "a very long description of burgers", Line: 2, Column: 1, Path: "$.description"},
+	}}
+	buf := strings.Builder{}
+	require.NoError(t, tree.Render(&buf, &TreeConfig{MaxWidth: 30, Colors: &Painter{Scheme: Terminal, Level: LevelBasic}}))
+	assert.Equal(t, "\x1b[1;34m$\x1b[0m \x1b[2m(1:1)\x1b[0m\n"+
+		"\x1b[2m└── \x1b[0m\x1b[1;34mdescription\x1b[0m: \x1b[32ma very…\x1b[0m \x1b[2m(2:1)\x1b[0m\n", buf.String())
+}
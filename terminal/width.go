@@ -0,0 +1,27 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package terminal
+
+import (
+	"golang.org/x/term"
+	"os"
+	"strconv"
+)
+
+// Width returns the width of the terminal a file writes to, from the COLUMNS environment variable when it is set
+// (shells set it for the programs they run) and the size of the terminal otherwise. It returns zero when COLUMNS
+// is not set and the file is not a terminal, for example when output is piped to a file.
+func Width(f *os.File) int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	if f == nil || !term.IsTerminal(int(f.Fd())) {
+		return 0
+	}
+	width, _, err := term.GetSize(int(f.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
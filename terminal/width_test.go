@@ -0,0 +1,32 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package terminal
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWidth(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "tree.txt"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	t.Setenv("COLUMNS", "")
+	assert.Equal(t, 0, Width(f))
+	assert.Equal(t, 0, Width(nil))
+
+	// a tree rendered to a file that is not a terminal is not truncated.
+	require.NoError(t, (&TreeNode{Key: "description", Value: strings.Repeat("burgers ", 40)}).Render(f, nil))
+	b, err := os.ReadFile(f.Name())
+	require.NoError(t, err)
+	assert.Equal(t, "description: "+strings.Repeat("burgers ", 40)+"\n", string(b))
+
+	t.Setenv("COLUMNS", "120")
+	assert.Equal(t, 120, Width(f))
+}
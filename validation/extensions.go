@@ -0,0 +1,121 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"github.com/pb33f/doctor/model"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"gopkg.in/yaml.v3"
+	"strings"
+	"sync"
+)
+
+// FindingExtensionSchema is the finding id used for extension payloads that do not match a registered schema.
+const FindingExtensionSchema = "extension-schema"
+
+// ExtensionRegistry holds JSON Schemas for specific extension names (for example `x-api-id` or `x-audience`),
+// and validates the payloads of matching extensions in a document against them.
+type ExtensionRegistry struct {
+	schemas map[string]*base.Schema
+	lock    sync.RWMutex
+}
+
+// NewExtensionRegistry creates an empty ExtensionRegistry.
+func NewExtensionRegistry() *ExtensionRegistry {
+	return &ExtensionRegistry{schemas: make(map[string]*base.Schema)}
+}
+
+// Register adds a JSON Schema (as JSON or YAML) for an extension name, replacing any schema already
+// registered for that name.
+func (r *ExtensionRegistry) Register(name string, schema []byte) error {
+	if !strings.HasPrefix(name, "x-") {
+		return fmt.Errorf("extension '%s' must start with 'x-'", name)
+	}
+	s, err := buildSchema(schema)
+	if err != nil {
+		return fmt.Errorf("unable to build schema for extension '%s': %w", name, err)
+	}
+	r.RegisterSchema(name, s)
+	return nil
+}
+
+// RegisterSchema adds an already built schema for an extension name.
+func (r *ExtensionRegistry) RegisterSchema(name string, schema *base.Schema) {
+	r.lock.Lock()
+	r.schemas[name] = schema
+	r.lock.Unlock()
+}
+
+// Validate checks every extension in the document that has a registered schema, returning a finding for each
+// violation. Findings are attached to the object that owns the extension.
+func (r *ExtensionRegistry) Validate(drDoc *model.DrDocument) []*drBase.Finding {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	var findings []*drBase.Finding
+	for _, ext := range drDoc.Extensions() {
+		schema, ok := r.schemas[ext.Name]
+		if !ok || ext.Value == nil {
+			continue
+		}
+		var value any
+		if err := ext.Value.Decode(&value); err != nil {
+			findings = append(findings, drBase.NewFinding(FindingExtensionSchema, drBase.SeverityError,
+				fmt.Sprintf("extension '%s' at `%s` cannot be decoded: %s", ext.Name, ext.JSONPath, err.Error()), ext.Owner))
+			continue
+		}
+		path := fmt.Sprintf("%s['%s']", ext.JSONPath, ext.Name)
+		for _, v := range ValidateSchema(schema, value, path, ext.Name) {
+			f := drBase.NewFinding(FindingExtensionSchema, drBase.SeverityError,
+				fmt.Sprintf("extension '%s' at `%s` is invalid: %s", ext.Name, ext.JSONPath, v.Message), ext.Owner)
+			f.Path = v.Path
+			f.Node = ext.Value
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// buildSchema builds a high-level schema from JSON or YAML bytes, by wrapping it in a minimal document
+// so references and the index work as they would in a real specification.
+func buildSchema(schema []byte) (*base.Schema, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(schema, &node); err != nil {
+		return nil, err
+	}
+	if len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("schema must be an object")
+	}
+	str := func(v string) *yaml.Node { return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v} }
+	mapping := func(n ...*yaml.Node) *yaml.Node { return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: n} }
+	root := mapping(
+		str("openapi"), str("3.1.0"),
+		str("info"), mapping(str("title"), str("extension"), str("version"), str("1")),
+		str("components"), mapping(str("schemas"), mapping(str("extension"), node.Content[0])),
+	)
+	spec, err := yaml.Marshal(root)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := libopenapi.NewDocument(spec)
+	if err != nil {
+		return nil, err
+	}
+	v3, errs := doc.BuildV3Model()
+	if v3 == nil {
+		return nil, errors.Join(errs...)
+	}
+	sp := v3.Model.Components.Schemas.GetOrZero("extension")
+	if sp == nil {
+		return nil, errors.New("schema could not be built")
+	}
+	s, err := sp.BuildSchema()
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
@@ -0,0 +1,27 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestExtensionRegistry_Validate(t *testing.T) {
+	drDoc := loadBurgerShop(t)
+
+	registry := NewExtensionRegistry()
+	assert.Error(t, registry.Register("api-id", []byte(`type: string`)))
+	assert.NoError(t, registry.Register("x-internal-tong", []byte(`{"type": "integer", "minimum": 0}`)))
+	assert.NoError(t, registry.Register("x-milky-milk", []byte("type: string\nenum: [creamy, frothy]")))
+
+	findings := registry.Validate(drDoc)
+	assert.Len(t, findings, 1)
+	assert.Equal(t, FindingExtensionSchema, findings[0].Id)
+	assert.Equal(t, drBase.SeverityError, findings[0].Severity)
+	assert.Equal(t, "$.paths['x-milky-milk']", findings[0].Path)
+	assert.Contains(t, findings[0].Message, "x-milky-milk")
+	assert.Equal(t, 61, findings[0].Node.Line)
+}
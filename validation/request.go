@@ -0,0 +1,296 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pb33f/doctor/model"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	drV3 "github.com/pb33f/doctor/model/high/v3"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrorResponse is rendered by the request validator when a request is rejected.
+type ErrorResponse struct {
+	Error      string       `json:"error"`
+	Violations []*Violation `json:"violations,omitempty"`
+}
+
+// DefaultMaxBodySize is the largest request body (10MB) a RequestValidator will read, unless configured otherwise.
+const DefaultMaxBodySize int64 = 10 << 20
+
+// RequestValidator validates inbound requests against the operations of a DrDocument.
+type RequestValidator struct {
+	// MaxBodySize is the largest request body, in bytes, that will be read for validation. Larger bodies are
+	// rejected with a 413, zero uses DefaultMaxBodySize.
+	MaxBodySize int64
+
+	drDocument *model.DrDocument
+}
+
+// NewRequestValidator creates http middleware that validates every inbound request (path parameters, query,
+// headers, cookies and body) against the operation it is routed to. Invalid requests are rejected
+// with a 400 response listing each violation and the JSONPath of the object in the specification that was
+// violated. Requests that match no path are rejected with a 404, undefined methods with a 405, and bodies
+// larger than DefaultMaxBodySize with a 413.
+func NewRequestValidator(drDoc *model.DrDocument) func(next http.Handler) http.Handler {
+	return NewValidator(drDoc).Middleware
+}
+
+// NewValidator creates a RequestValidator for the supplied DrDocument.
+func NewValidator(drDoc *model.DrDocument) *RequestValidator {
+	return &RequestValidator{drDocument: drDoc}
+}
+
+// Middleware wraps the next handler, rejecting requests that do not pass ValidateRequest with an ErrorResponse.
+func (v *RequestValidator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		code, violations := v.ValidateRequest(r)
+		if code != http.StatusOK {
+			msg := "request is not valid for this operation"
+			if len(violations) == 1 && code != http.StatusBadRequest {
+				msg = violations[0].Message
+			}
+			WriteError(w, code, msg, violations)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ValidateRequest routes the request to an operation and validates it, returning the HTTP status code that
+// best describes the outcome (200 when the request is valid) and any violations.
+func (v *RequestValidator) ValidateRequest(r *http.Request) (int, []*Violation) {
+	match, err := v.drDocument.MatchOperation(r.Method, r.URL.Path)
+	switch {
+	case errors.Is(err, model.ErrPathNotFound):
+		return http.StatusNotFound, []*Violation{{
+			Message:  fmt.Sprintf("no path in the specification matches '%s'", r.URL.Path),
+			Location: "path",
+		}}
+	case errors.Is(err, model.ErrMethodNotAllowed):
+		return http.StatusMethodNotAllowed, []*Violation{{
+			Message:  fmt.Sprintf("method '%s' is not defined for path '%s'", r.Method, match.Path),
+			Path:     match.PathItem.GenerateJSONPath(),
+			Location: "method",
+		}}
+	}
+	return v.ValidateOperation(r, match.PathItem, match.Operation, match.PathParameters)
+}
+
+// ValidateOperation validates a request against an operation that has already been located, pathParams
+// are the values extracted from the path template. It returns 200 when the request is valid, 400 when it has
+// violations, and 413 when the body is larger than MaxBodySize. The request body is read and replaced, so it can
+// still be consumed by the next handler.
+func (v *RequestValidator) ValidateOperation(r *http.Request, pathItem *drV3.PathItem, op *drV3.Operation,
+	pathParams map[string]string) (int, []*Violation) {
+	var violations []*Violation
+	for _, p := range EffectiveParameters(pathItem, op) {
+		violations = append(violations, validateParameter(r, p, pathParams)...)
+	}
+	if op.RequestBody != nil {
+		limit := v.MaxBodySize
+		if limit <= 0 {
+			limit = DefaultMaxBodySize
+		}
+		body, err := readBody(r, limit)
+		if err != nil {
+			return http.StatusRequestEntityTooLarge, []*Violation{{
+				Message:  fmt.Sprintf("request body is larger than the limit of %d bytes", limit),
+				Path:     op.RequestBody.GenerateJSONPath(),
+				Location: "body",
+			}}
+		}
+		violations = append(violations, validateBody(r, body, op.RequestBody)...)
+	}
+	if len(violations) > 0 {
+		return http.StatusBadRequest, violations
+	}
+	return http.StatusOK, nil
+}
+
+// ValidateOperationRequest validates a request against an operation that has already been located, using
+// DefaultMaxBodySize, and returns the violations. An oversized body is reported as a single violation, use
+// RequestValidator.ValidateOperation to tell it apart with a 413.
+func ValidateOperationRequest(r *http.Request, pathItem *drV3.PathItem, op *drV3.Operation, pathParams map[string]string) []*Violation {
+	_, violations := (&RequestValidator{}).ValidateOperation(r, pathItem, op, pathParams)
+	return violations
+}
+
+// EffectiveParameters merges the path item parameters with the operation parameters,
+// operation parameters override path item parameters with the same name and location.
+func EffectiveParameters(pathItem *drV3.PathItem, op *drV3.Operation) []*drV3.Parameter {
+	var params []*drV3.Parameter
+	seen := make(map[string]bool)
+	for _, p := range op.Parameters {
+		seen[p.Value.In+":"+p.Value.Name] = true
+		params = append(params, p)
+	}
+	if pathItem != nil {
+		for _, p := range pathItem.Parameters {
+			if !seen[p.Value.In+":"+p.Value.Name] {
+				params = append(params, p)
+			}
+		}
+	}
+	return params
+}
+
+// WriteError renders an ErrorResponse as JSON.
+func WriteError(w http.ResponseWriter, code int, message string, violations []*Violation) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(&ErrorResponse{Error: message, Violations: violations})
+}
+
+func validateParameter(r *http.Request, p *drV3.Parameter, pathParams map[string]string) []*Violation {
+	var values []string
+	switch p.Value.In {
+	case "path":
+		if val, ok := pathParams[p.Value.Name]; ok {
+			values = []string{val}
+		}
+	case "query":
+		values = r.URL.Query()[p.Value.Name]
+	case "header":
+		values = r.Header.Values(p.Value.Name)
+	case "cookie":
+		if c, err := r.Cookie(p.Value.Name); err == nil {
+			values = []string{c.Value}
+		}
+	}
+	location := p.Value.In + "." + p.Value.Name
+
+	if len(values) == 0 {
+		if p.Value.Required != nil && *p.Value.Required {
+			return []*Violation{{
+				Message:  fmt.Sprintf("required %s parameter '%s' is missing", p.Value.In, p.Value.Name),
+				Path:     p.GenerateJSONPath(),
+				Location: location,
+			}}
+		}
+		return nil
+	}
+
+	if p.Value.Schema == nil {
+		return nil
+	}
+	schema := p.Value.Schema.Schema()
+	if schema == nil {
+		return nil
+	}
+	specPath := p.GenerateJSONPath() + ".schema"
+	if p.SchemaProxy != nil {
+		specPath = p.SchemaProxy.GenerateJSONPath()
+	}
+	return ValidateSchema(schema, coerceParameter(schema, values), specPath, location)
+}
+
+// readBody reads up to limit bytes of the request body and replaces it, so it can be read again. An error is
+// returned when the body is larger than the limit.
+func readBody(r *http.Request, limit int64) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, limit))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return nil, err
+	}
+	_ = r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func validateBody(r *http.Request, body []byte, rb *drV3.RequestBody) []*Violation {
+	if len(body) == 0 {
+		if rb.Value.Required != nil && *rb.Value.Required {
+			return []*Violation{{
+				Message:  "request body is required",
+				Path:     rb.GenerateJSONPath(),
+				Location: "body",
+			}}
+		}
+		return nil
+	}
+
+	contentType := strings.TrimSpace(strings.SplitN(r.Header.Get("Content-Type"), ";", 2)[0])
+	if rb.Content == nil || rb.Content.Len() == 0 {
+		return nil
+	}
+	mt := rb.MatchContentType(contentType)
+	if mt == nil {
+		return []*Violation{{
+			Message:  fmt.Sprintf("content type '%s' is not accepted by this operation", contentType),
+			Path:     rb.GenerateJSONPath() + ".content",
+			Location: "header.Content-Type",
+		}}
+	}
+	if !strings.Contains(contentType, "json") {
+		return nil
+	}
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return []*Violation{{
+			Message:  fmt.Sprintf("request body is not valid JSON: %s", err.Error()),
+			Path:     mt.GenerateJSONPath(),
+			Location: "body",
+		}}
+	}
+	if mt.Value.Schema == nil {
+		return nil
+	}
+	specPath := mt.GenerateJSONPath() + ".schema"
+	if mt.SchemaProxy != nil {
+		specPath = mt.SchemaProxy.GenerateJSONPath()
+	}
+	// readOnly properties are not sent in requests, body schemas are validated as requests see them.
+	return ValidateSchema(drBase.SchemaView(mt.Value.Schema.Schema(), drBase.ViewRequest), decoded, specPath, "body")
+}
+
+// coerceParameter converts raw parameter strings into the shapes expected by the schema.
+func coerceParameter(schema *base.Schema, values []string) any {
+	if slices.Contains(schema.Type, "array") {
+		if len(values) == 1 {
+			values = strings.Split(values[0], ",")
+		}
+		var items *base.Schema
+		if schema.Items != nil && schema.Items.IsA() && schema.Items.A != nil {
+			items = schema.Items.A.Schema()
+		}
+		arr := make([]any, len(values))
+		for i, val := range values {
+			arr[i] = coerceScalar(items, val)
+		}
+		return arr
+	}
+	return coerceScalar(schema, values[0])
+}
+
+func coerceScalar(schema *base.Schema, value string) any {
+	if schema == nil {
+		return value
+	}
+	for _, t := range schema.Type {
+		switch t {
+		case "integer", "number":
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				return f
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(value); err == nil {
+				return b
+			}
+		}
+	}
+	return value
+}
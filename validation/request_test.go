@@ -0,0 +1,121 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"encoding/json"
+	"github.com/pb33f/doctor/model"
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func loadBurgerShop(t *testing.T) *model.DrDocument {
+	bytes, _ := os.ReadFile("../test_specs/burgershop.openapi.yaml")
+	doc, err := libopenapi.NewDocument(bytes)
+	require.NoError(t, err)
+	v3Doc, errs := doc.BuildV3Model()
+	require.Empty(t, errs)
+	return model.NewDrDocument(v3Doc)
+}
+
+func TestNewRequestValidator(t *testing.T) {
+	var received string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		received = string(b)
+		w.WriteHeader(http.StatusTeapot)
+	})
+	handler := NewRequestValidator(loadBurgerShop(t))(next)
+
+	// valid request passes through, with the body intact.
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/burgers", strings.NewReader(`{"name":"big mac","numPatties":2}`))
+	req.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+	assert.Equal(t, `{"name":"big mac","numPatties":2}`, received)
+
+	// invalid body is rejected with paths into the spec.
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/burgers", strings.NewReader(`{"numPatties":"two"}`))
+	req.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var errResp ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errResp))
+	require.Len(t, errResp.Violations, 2)
+
+	paths := make(map[string]string)
+	for _, v := range errResp.Violations {
+		paths[v.Location] = v.Path
+	}
+	assert.Equal(t, "$.paths['/burgers'].post.requestBody.content['application/json'].schema", paths["body"])
+	assert.Equal(t, "$.paths['/burgers'].post.requestBody.content['application/json'].schema.properties['numPatties']",
+		paths["body/numPatties"])
+
+	// wrong content type
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/burgers", strings.NewReader(`<burger/>`))
+	req.Header.Set("Content-Type", "application/xml")
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// unknown path and method
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/pizza", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, "/burgers", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestRequestValidator_ValidateRequest_Parameters(t *testing.T) {
+	v := NewValidator(loadBurgerShop(t))
+
+	req := httptest.NewRequest(http.MethodGet, "/burgers/123", nil)
+	code, violations := v.ValidateRequest(req)
+	assert.Equal(t, http.StatusBadRequest, code)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "header.burgerHeader", violations[0].Location)
+
+	req.Header.Set("burgerHeader", "big-mac")
+	code, violations = v.ValidateRequest(req)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, violations)
+}
+
+func TestRequestValidator_MaxBodySize(t *testing.T) {
+	v := NewValidator(loadBurgerShop(t))
+	v.MaxBodySize = 16
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	handler := v.Middleware(next)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/burgers", strings.NewReader(`{"name":"big mac","numPatties":2}`))
+	req.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	var errResp ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errResp))
+	assert.Equal(t, "request body is larger than the limit of 16 bytes", errResp.Error)
+
+	// the default limit is used when none is configured.
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/burgers", strings.NewReader(`{"name":"big mac","numPatties":2}`))
+	req.Header.Set("Content-Type", "application/json")
+	NewRequestValidator(loadBurgerShop(t))(next).ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+}
@@ -0,0 +1,317 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"fmt"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"math"
+	"reflect"
+	"regexp"
+	"sync"
+	"unicode/utf8"
+)
+
+var patternCache sync.Map
+
+// Violation describes something in a request (or value) that does not satisfy the specification.
+// Path is the JSONPath of the object in the specification that was violated, Location describes where in the
+// request the problem was found, for example `query.limit` or `body/burger/name`.
+type Violation struct {
+	Message  string `json:"message"`
+	Path     string `json:"path,omitempty"`
+	Location string `json:"location,omitempty"`
+}
+
+// ValidateSchema checks a decoded JSON value (the result of json.Unmarshal into an `any`) against a schema.
+// specPath is the JSONPath of the schema in the specification and is used as the root for all reported
+// violations, location is the root of the value in the request.
+//
+// This is a lightweight validator, it covers the keywords that matter for request and response payloads
+// (types, enums, const, required, properties, items, bounds, lengths, patterns and composition), it is not
+// a full JSON Schema implementation.
+func ValidateSchema(schema *base.Schema, value any, specPath, location string) []*Violation {
+	return validateSchema(schema, normalize(value), specPath, location, 0)
+}
+
+func validateSchema(schema *base.Schema, value any, specPath, location string, depth int) []*Violation {
+	if schema == nil || depth > 100 {
+		return nil
+	}
+	depth++
+	var violations []*Violation
+	fail := func(msg string, args ...any) {
+		violations = append(violations, &Violation{
+			Message:  fmt.Sprintf(msg, args...),
+			Path:     specPath,
+			Location: location,
+		})
+	}
+
+	if value == nil {
+		if drBase.IsNullable(schema) || len(schema.Type) == 0 {
+			return nil
+		}
+		fail("value is null, expected '%v'", schema.Type)
+		return violations
+	}
+
+	if len(schema.Type) > 0 {
+		matched := false
+		for _, t := range schema.Type {
+			if matchesType(t, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("value is '%s', expected '%v'", typeOf(value), schema.Type)
+			return violations
+		}
+	}
+
+	if len(schema.Enum) > 0 {
+		found := false
+		for _, e := range schema.Enum {
+			var ev any
+			if e.Decode(&ev) == nil && reflect.DeepEqual(normalize(ev), value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("value '%v' is not one of the allowed enum values", value)
+		}
+	}
+
+	if schema.Const != nil {
+		var cv any
+		if schema.Const.Decode(&cv) == nil && !reflect.DeepEqual(normalize(cv), value) {
+			fail("value '%v' does not match const value '%v'", value, cv)
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := int64(utf8.RuneCountInString(v))
+		if schema.MinLength != nil && length < *schema.MinLength {
+			fail("string length %d is shorter than the minimum length of %d", length, *schema.MinLength)
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			fail("string length %d is longer than the maximum length of %d", length, *schema.MaxLength)
+		}
+		if schema.Pattern != "" {
+			if rx := compilePattern(schema.Pattern); rx != nil && !rx.MatchString(v) {
+				fail("string '%s' does not match pattern '%s'", v, schema.Pattern)
+			}
+		}
+	case float64:
+		if schema.Minimum != nil {
+			exclusive := schema.ExclusiveMinimum != nil && schema.ExclusiveMinimum.IsA() && schema.ExclusiveMinimum.A
+			if v < *schema.Minimum || (exclusive && v == *schema.Minimum) {
+				fail("number %v is less than the minimum of %v", v, *schema.Minimum)
+			}
+		}
+		if schema.ExclusiveMinimum != nil && schema.ExclusiveMinimum.IsB() && v <= schema.ExclusiveMinimum.B {
+			fail("number %v must be greater than %v", v, schema.ExclusiveMinimum.B)
+		}
+		if schema.Maximum != nil {
+			exclusive := schema.ExclusiveMaximum != nil && schema.ExclusiveMaximum.IsA() && schema.ExclusiveMaximum.A
+			if v > *schema.Maximum || (exclusive && v == *schema.Maximum) {
+				fail("number %v is greater than the maximum of %v", v, *schema.Maximum)
+			}
+		}
+		if schema.ExclusiveMaximum != nil && schema.ExclusiveMaximum.IsB() && v >= schema.ExclusiveMaximum.B {
+			fail("number %v must be less than %v", v, schema.ExclusiveMaximum.B)
+		}
+		if schema.MultipleOf != nil && *schema.MultipleOf != 0 {
+			if q := v / *schema.MultipleOf; q != math.Trunc(q) {
+				fail("number %v is not a multiple of %v", v, *schema.MultipleOf)
+			}
+		}
+	case []any:
+		count := int64(len(v))
+		if schema.MinItems != nil && count < *schema.MinItems {
+			fail("array has %d items, the minimum is %d", count, *schema.MinItems)
+		}
+		if schema.MaxItems != nil && count > *schema.MaxItems {
+			fail("array has %d items, the maximum is %d", count, *schema.MaxItems)
+		}
+		if schema.UniqueItems != nil && *schema.UniqueItems {
+			for i := range v {
+				for j := i + 1; j < len(v); j++ {
+					if reflect.DeepEqual(v[i], v[j]) {
+						fail("array items %d and %d are not unique", i, j)
+					}
+				}
+			}
+		}
+		if schema.Items != nil && schema.Items.IsA() && schema.Items.A != nil {
+			items := schema.Items.A.Schema()
+			for i, item := range v {
+				violations = append(violations, validateSchema(items, item,
+					specPath+".items", fmt.Sprintf("%s/%d", location, i), depth)...)
+			}
+		}
+	case map[string]any:
+		count := int64(len(v))
+		if schema.MinProperties != nil && count < *schema.MinProperties {
+			fail("object has %d properties, the minimum is %d", count, *schema.MinProperties)
+		}
+		if schema.MaxProperties != nil && count > *schema.MaxProperties {
+			fail("object has %d properties, the maximum is %d", count, *schema.MaxProperties)
+		}
+		for _, r := range schema.Required {
+			if _, ok := v[r]; !ok {
+				fail("required property '%s' is missing", r)
+			}
+		}
+		for k, pv := range v {
+			if schema.Properties != nil {
+				if prop, ok := schema.Properties.Get(k); ok {
+					violations = append(violations, validateSchema(prop.Schema(), pv,
+						specPath+".properties['"+k+"']", location+"/"+k, depth)...)
+					continue
+				}
+			}
+			if schema.AdditionalProperties != nil {
+				if schema.AdditionalProperties.IsB() && !schema.AdditionalProperties.B {
+					fail("property '%s' is not allowed", k)
+				}
+				if schema.AdditionalProperties.IsA() && schema.AdditionalProperties.A != nil {
+					violations = append(violations, validateSchema(schema.AdditionalProperties.A.Schema(), pv,
+						specPath+".additionalProperties", location+"/"+k, depth)...)
+				}
+			}
+		}
+	}
+
+	for i, sp := range schema.AllOf {
+		violations = append(violations, validateSchema(sp.Schema(), value,
+			fmt.Sprintf("%s.allOf[%d]", specPath, i), location, depth)...)
+	}
+	if len(schema.AnyOf) > 0 {
+		matched := false
+		for i, sp := range schema.AnyOf {
+			if len(validateSchema(sp.Schema(), value, fmt.Sprintf("%s.anyOf[%d]", specPath, i), location, depth)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("value does not match any schema in anyOf")
+		}
+	}
+	if len(schema.OneOf) > 0 {
+		matched := 0
+		for i, sp := range schema.OneOf {
+			if len(validateSchema(sp.Schema(), value, fmt.Sprintf("%s.oneOf[%d]", specPath, i), location, depth)) == 0 {
+				matched++
+			}
+		}
+		if matched != 1 {
+			fail("value matches %d schemas in oneOf, expected exactly one", matched)
+		}
+	}
+	if schema.Not != nil {
+		if len(validateSchema(schema.Not.Schema(), value, specPath+".not", location, depth)) == 0 {
+			fail("value must not match the schema defined by not")
+		}
+	}
+	return violations
+}
+
+func matchesType(t string, value any) bool {
+	switch t {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "null":
+		return value == nil
+	}
+	return true
+}
+
+func typeOf(value any) string {
+	switch v := value.(type) {
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return "null"
+}
+
+// normalize converts values decoded from YAML into the same shapes produced by encoding/json, so they can
+// be compared.
+func normalize(value any) any {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case []any:
+		n := make([]any, len(v))
+		for i := range v {
+			n[i] = normalize(v[i])
+		}
+		return n
+	case map[string]any:
+		n := make(map[string]any, len(v))
+		for k := range v {
+			n[k] = normalize(v[k])
+		}
+		return n
+	case map[any]any:
+		n := make(map[string]any, len(v))
+		for k := range v {
+			n[fmt.Sprint(k)] = normalize(v[k])
+		}
+		return n
+	}
+	return value
+}
+
+// compilePattern compiles a schema pattern once and caches it, nil is returned (and cached) for patterns that
+// are not valid regular expressions, which are not enforced.
+func compilePattern(pattern string) *regexp.Regexp {
+	if rx, ok := patternCache.Load(pattern); ok {
+		return rx.(*regexp.Regexp)
+	}
+	rx, err := regexp.Compile(pattern)
+	if err != nil {
+		rx = nil
+	}
+	patternCache.Store(pattern, rx)
+	return rx
+}
@@ -0,0 +1,25 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestValidateSchema_Pattern(t *testing.T) {
+	schema := &base.Schema{Type: []string{"string"}, Pattern: "^[a-z]+$"}
+	assert.Empty(t, ValidateSchema(schema, "burger", "$.schema", "body"))
+
+	violations := ValidateSchema(schema, "Burger", "$.schema", "body")
+	require.Len(t, violations, 1)
+	assert.Equal(t, "string 'Burger' does not match pattern '^[a-z]+$'", violations[0].Message)
+
+	// patterns are compiled once, invalid patterns are cached and not enforced.
+	assert.Same(t, compilePattern("^[a-z]+$"), compilePattern("^[a-z]+$"))
+	assert.Nil(t, compilePattern("[a-z"))
+	assert.Empty(t, ValidateSchema(&base.Schema{Type: []string{"string"}, Pattern: "[a-z"}, "Burger", "$.schema", "body"))
+}
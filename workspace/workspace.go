@@ -0,0 +1,252 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package workspace holds the DrDocuments of many services (for example every API on a platform), and answers
+// questions that cross document boundaries, such as operationIds used by more than one service, schemas that
+// are copied between specifications and path templates that collide.
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"github.com/pb33f/doctor/model"
+	drBase "github.com/pb33f/doctor/model/high/base"
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Service is a named document in a workspace.
+type Service struct {
+	Name     string
+	Document *model.DrDocument
+}
+
+// Workspace is a set of services. It is safe for concurrent use.
+type Workspace struct {
+	services []*Service
+	lock     sync.RWMutex
+}
+
+// ServiceOperation is an operation, and the service that defines it.
+type ServiceOperation struct {
+	Service *Service
+	*model.PathOperation
+}
+
+// ServiceSchema is a component schema, and the service that defines it.
+type ServiceSchema struct {
+	Service *Service
+	Name    string
+	Schema  *drBase.Schema
+}
+
+// DuplicateOperationId is an operationId used by operations in more than one service.
+type DuplicateOperationId struct {
+	OperationId string
+	Operations  []*ServiceOperation
+}
+
+// SharedSchema is a component schema defined identically (by hash) in more than one service.
+type SharedSchema struct {
+	Hash    string
+	Schemas []*ServiceSchema
+}
+
+// PathConflict is a method and path template declared by more than one service. Templates that only differ
+// by the names of their parameters (`/users/{id}` and `/users/{userId}`) conflict, as they match the same requests.
+type PathConflict struct {
+	Method     string
+	Template   string
+	Operations []*ServiceOperation
+}
+
+// NewWorkspace creates an empty Workspace.
+func NewWorkspace() *Workspace {
+	return &Workspace{}
+}
+
+// Add adds a walked document to the workspace as a service. Service names must be unique.
+func (w *Workspace) Add(name string, drDoc *model.DrDocument) (*Service, error) {
+	if drDoc == nil {
+		return nil, fmt.Errorf("DrDocument for service '%s' is nil", name)
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for _, s := range w.services {
+		if s.Name == name {
+			return nil, fmt.Errorf("service '%s' already exists in the workspace", name)
+		}
+	}
+	s := &Service{Name: name, Document: drDoc}
+	w.services = append(w.services, s)
+	return s, nil
+}
+
+// Load parses, builds and walks a specification, and adds it to the workspace as a service.
+func (w *Workspace) Load(name string, spec []byte, docConfig *datamodel.DocumentConfiguration) (*Service, error) {
+	var doc libopenapi.Document
+	var err error
+	if docConfig != nil {
+		doc, err = libopenapi.NewDocumentWithConfiguration(spec, docConfig)
+	} else {
+		doc, err = libopenapi.NewDocument(spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse service '%s': %w", name, err)
+	}
+	v3Doc, errs := doc.BuildV3Model()
+	if v3Doc == nil {
+		return nil, fmt.Errorf("unable to build model for service '%s': %w", name, errors.Join(errs...))
+	}
+	return w.Add(name, model.NewDrDocument(v3Doc))
+}
+
+// LoadFile reads a specification from disk and adds it to the workspace, the service is named after the file.
+// References to other files are resolved relative to the specification.
+func (w *Workspace) LoadFile(path string) (*Service, error) {
+	spec, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	docConfig := &datamodel.DocumentConfiguration{
+		BasePath:            filepath.Dir(path),
+		AllowFileReferences: true,
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return w.Load(name, spec, docConfig)
+}
+
+// Services returns every service in the workspace, in the order they were added.
+func (w *Workspace) Services() []*Service {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return append([]*Service(nil), w.services...)
+}
+
+// Service returns a service by name, or nil if it is not in the workspace.
+func (w *Workspace) Service(name string) *Service {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	for _, s := range w.services {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// DuplicateOperationIds returns every operationId that is used by more than one service. OperationIds that
+// are duplicated inside a single service are not reported.
+func (w *Workspace) DuplicateOperationIds() []*DuplicateOperationId {
+	var duplicates []*DuplicateOperationId
+	byId := make(map[string]*DuplicateOperationId)
+	for _, s := range w.Services() {
+		for _, po := range s.Document.Operations() {
+			id := po.Operation.Value.OperationId
+			if id == "" {
+				continue
+			}
+			d, ok := byId[id]
+			if !ok {
+				d = &DuplicateOperationId{OperationId: id}
+				byId[id] = d
+				duplicates = append(duplicates, d)
+			}
+			d.Operations = append(d.Operations, &ServiceOperation{Service: s, PathOperation: po})
+		}
+	}
+	return crossService(duplicates, func(d *DuplicateOperationId) []*Service {
+		return operationServices(d.Operations)
+	})
+}
+
+// SharedSchemas returns every component schema that is defined identically in more than one service.
+func (w *Workspace) SharedSchemas() []*SharedSchema {
+	var shared []*SharedSchema
+	byHash := make(map[string]*SharedSchema)
+	for _, s := range w.Services() {
+		doc := s.Document.V3Document
+		if doc == nil || doc.Components == nil || doc.Components.Schemas == nil {
+			continue
+		}
+		for name, sp := range doc.Components.Schemas.FromOldest() {
+			if sp.Schema == nil || sp.Schema.Value == nil || sp.Schema.Value.GoLow() == nil {
+				continue
+			}
+			hash := fmt.Sprintf("%x", sp.Schema.Value.GoLow().Hash())
+			ss, ok := byHash[hash]
+			if !ok {
+				ss = &SharedSchema{Hash: hash}
+				byHash[hash] = ss
+				shared = append(shared, ss)
+			}
+			ss.Schemas = append(ss.Schemas, &ServiceSchema{Service: s, Name: name, Schema: sp.Schema})
+		}
+	}
+	return crossService(shared, func(ss *SharedSchema) []*Service {
+		var services []*Service
+		for _, schema := range ss.Schemas {
+			services = append(services, schema.Service)
+		}
+		return services
+	})
+}
+
+var templateParam = regexp.MustCompile(`\{[^}]*}`)
+
+// ConflictingPaths returns every method and path template that is declared by more than one service.
+// Webhooks are not included.
+func (w *Workspace) ConflictingPaths() []*PathConflict {
+	var conflicts []*PathConflict
+	byRoute := make(map[string]*PathConflict)
+	for _, s := range w.Services() {
+		for _, po := range s.Document.Operations() {
+			if po.Webhook {
+				continue
+			}
+			template := templateParam.ReplaceAllString(po.Path, "{}")
+			if len(template) > 1 {
+				template = strings.TrimSuffix(template, "/")
+			}
+			key := po.Method + " " + template
+			c, ok := byRoute[key]
+			if !ok {
+				c = &PathConflict{Method: po.Method, Template: template}
+				byRoute[key] = c
+				conflicts = append(conflicts, c)
+			}
+			c.Operations = append(c.Operations, &ServiceOperation{Service: s, PathOperation: po})
+		}
+	}
+	return crossService(conflicts, func(c *PathConflict) []*Service {
+		return operationServices(c.Operations)
+	})
+}
+
+func operationServices(operations []*ServiceOperation) []*Service {
+	var services []*Service
+	for _, o := range operations {
+		services = append(services, o.Service)
+	}
+	return services
+}
+
+// crossService keeps the items that span more than one service.
+func crossService[T any](items []T, services func(T) []*Service) []T {
+	var found []T
+	for _, item := range items {
+		seen := make(map[*Service]bool)
+		for _, s := range services(item) {
+			seen[s] = true
+		}
+		if len(seen) > 1 {
+			found = append(found, item)
+		}
+	}
+	return found
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: BUSL-1.1
+
+package workspace
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const ordersSpec = `openapi: 3.1.0
+info:
+  title: orders
+  version: 1
+paths:
+  /orders/{orderId}:
+    get:
+      operationId: getOrder
+      responses:
+        "200":
+          description: ok
+  /health:
+    get:
+      operationId: health
+      responses:
+        "200":
+          description: ok
+components:
+  schemas:
+    Error:
+      type: object
+      properties:
+        message:
+          type: string
+    Order:
+      type: object`
+
+const paymentsSpec = `openapi: 3.1.0
+info:
+  title: payments
+  version: 1
+paths:
+  /orders/{id}/:
+    get:
+      operationId: getPayment
+      responses:
+        "200":
+          description: ok
+  /health:
+    get:
+      operationId: health
+      responses:
+        "200":
+          description: ok
+    post:
+      operationId: ping
+      responses:
+        "200":
+          description: ok
+components:
+  schemas:
+    Problem:
+      type: object
+      properties:
+        message:
+          type: string
+    Payment:
+      type: string`
+
+func TestWorkspace(t *testing.T) {
+	ws := NewWorkspace()
+	_, err := ws.Load("orders", []byte(ordersSpec), nil)
+	require.NoError(t, err)
+	_, err = ws.Load("payments", []byte(paymentsSpec), nil)
+	require.NoError(t, err)
+
+	assert.Len(t, ws.Services(), 2)
+	assert.Equal(t, "payments", ws.Service("payments").Name)
+	assert.Nil(t, ws.Service("shipping"))
+
+	_, err = ws.Add("orders", ws.Service("orders").Document)
+	assert.EqualError(t, err, "service 'orders' already exists in the workspace")
+
+	ids := ws.DuplicateOperationIds()
+	require.Len(t, ids, 1)
+	assert.Equal(t, "health", ids[0].OperationId)
+	assert.Len(t, ids[0].Operations, 2)
+	assert.Equal(t, "orders", ids[0].Operations[0].Service.Name)
+	assert.Equal(t, "payments", ids[0].Operations[1].Service.Name)
+
+	shared := ws.SharedSchemas()
+	require.Len(t, shared, 1)
+	assert.Len(t, shared[0].Schemas, 2)
+	assert.Equal(t, "Error", shared[0].Schemas[0].Name)
+	assert.Equal(t, "Problem", shared[0].Schemas[1].Name)
+
+	conflicts := ws.ConflictingPaths()
+	require.Len(t, conflicts, 2)
+	assert.Equal(t, "get", conflicts[0].Method)
+	assert.Equal(t, "/orders/{}", conflicts[0].Template)
+	assert.Equal(t, "/orders/{orderId}", conflicts[0].Operations[0].Path)
+	assert.Equal(t, "/orders/{id}/", conflicts[0].Operations[1].Path)
+	assert.Equal(t, "/health", conflicts[1].Template)
+}
+
+func TestWorkspace_LoadFile(t *testing.T) {
+	ws := NewWorkspace()
+	s, err := ws.LoadFile("../test_specs/burgershop.openapi.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "burgershop.openapi", s.Name)
+	assert.NotEmpty(t, s.Document.Operations())
+
+	_, err = ws.LoadFile("../test_specs/nope.yaml")
+	assert.Error(t, err)
+}